@@ -0,0 +1,76 @@
+// litetable-replay sends the requests of a query recording to a LiteTable server, creating the
+// tables and families they use, and reports their latencies. Point it at a test server: it
+// writes to it.
+//
+//	litetable-replay --file queries.jsonl --target 127.0.0.1:9443 --speed 2
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/workload"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run replays a recording and returns the process exit code.
+func run(args []string) int {
+	flags := flag.NewFlagSet("litetable-replay", flag.ContinueOnError)
+	file := flags.String("file", "", "query recording to replay")
+	target := flags.String("target", "127.0.0.1:9443", "gRPC address of the server to replay to")
+	speed := flags.Float64("speed", 1,
+		"multiple of the recorded request rate, 0 sends requests as fast as possible")
+	concurrency := flags.Int("concurrency", 16, "requests in flight at once")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "--file is required")
+		return 2
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open recording: %v\n", err)
+		return 1
+	}
+	shapes, err := workload.ReadShapes(f)
+	_ = f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	conn, err := grpc.NewClient(*target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to %s: %v\n", *target, err)
+		return 1
+	}
+	defer conn.Close()
+
+	// an interrupted replay still reports the requests it sent
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := workload.Replay(ctx, proto.NewLitetableServiceClient(conn), shapes,
+		workload.ReplayOptions{Speed: *speed, Concurrency: *concurrency})
+	if report != nil {
+		report.Write(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+// litetable-router is a gRPC endpoint in front of a cluster of LiteTable servers. Clients send
+// it the requests they would send a single server:
+//
+//	litetable-router --nodes 10.0.0.1:9443,10.0.0.2:9443,10.0.0.3:9443
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"github.com/litetable/litetable-db/internal/app"
+	"github.com/litetable/litetable-db/internal/logging"
+	"github.com/litetable/litetable-db/internal/router"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	application, err := initialize(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	if err = application.Run(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+func initialize(args []string) (*app.App, error) {
+	flags := flag.NewFlagSet("litetable-router", flag.ContinueOnError)
+	address := flags.String("address", "127.0.0.1", "address the router listens on")
+	port := flags.Int("port", 9444, "port the router listens on")
+	nodes := flags.String("nodes", "", "comma separated gRPC addresses of the LiteTable servers")
+	debug := flags.Bool("debug", false, "enable debug logging")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	logging.Init(&logging.Config{Debug: *debug})
+
+	var addresses []string
+	for _, node := range strings.Split(*nodes, ",") {
+		if node = strings.TrimSpace(node); node != "" {
+			addresses = append(addresses, node)
+		}
+	}
+
+	r, err := router.New(&router.Config{
+		Address: *address,
+		Port:    *port,
+		Nodes:   addresses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return app.CreateApp(&app.Config{
+		ServiceName: "LiteTable Router",
+		StopTimeout: 30 * time.Second,
+	}, r)
+}
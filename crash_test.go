@@ -0,0 +1,265 @@
+//go:build crashtest
+
+// The crash recovery tests run the server built with the crashtest tag, kill it at the fault
+// points of the storage, restart it and check what it recovered:
+//
+//	go test -tags crashtest -run TestCrashRecovery .
+//
+// They hold the storage to its durability contract. A write acknowledged with sync=BACKUP, or
+// followed by a successful Flush, survives any crash. A write saved in a snapshot survives a
+// crash whether or not the snapshot was merged into a backup. A crash never leaves a partial
+// backup or snapshot behind, and every file left verifies. Writes acknowledged in memory but in
+// no snapshot yet may be lost.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCrashRecovery(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "litetable-db")
+	build := exec.Command("go", "build", "-tags", "crashtest", "-o", bin, ".")
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	tests := map[string]struct {
+		// crashAt is the fault point the server is killed at. Every point is reached once by
+		// the first flush, so the server is killed in the second.
+		crashAt string
+		// saved is whether the changes of the second flush are in a snapshot at the crash
+		saved bool
+	}{
+		"before a snapshot is written": {crashAt: "snapshot_before_write:2"},
+		"after a snapshot is written":  {crashAt: "snapshot_after_write:2", saved: true},
+		"in the middle of a backup":    {crashAt: "backup_mid_write:2", saved: true},
+		"before merged snapshots are removed": {
+			crashAt: "backup_before_cleanup:2",
+			saved:   true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			home := t.TempDir()
+			cfg, rpcPort := writeCrashConfig(t, home)
+
+			srv := startServer(t, bin, home, cfg, rpcPort, tc.crashAt)
+			_, err := srv.client.CreateFamily(srv.ctx(), &proto.CreateFamilyRequest{
+				Family: []string{"main"},
+			})
+			req.NoError(err)
+
+			// the last durable write flushes the others with it
+			for i := range 5 {
+				sync := proto.WriteSync_MEMORY
+				if i == 4 {
+					sync = proto.WriteSync_BACKUP
+				}
+				req.NoError(srv.write(fmt.Sprintf("durable:%d", i), sync))
+			}
+
+			for i := range 5 {
+				req.NoError(srv.write(fmt.Sprintf("pending:%d", i), proto.WriteSync_MEMORY))
+			}
+			_, err = srv.client.Delete(srv.ctx(), &proto.DeleteRequest{
+				RowKey: "durable:0",
+				Family: "main",
+				Ttl:    3600,
+			})
+			req.NoError(err)
+			_, err = srv.client.Flush(srv.ctx(), &proto.Empty{})
+			req.Error(err, "the server was not killed at %s", tc.crashAt)
+			srv.waitKilled()
+
+			// a restart recovers every durable write, and the pending ones if they were saved
+			srv = startServer(t, bin, home, cfg, rpcPort, "")
+			for i := 1; i < 5; i++ {
+				key := fmt.Sprintf("durable:%d", i)
+				req.Equal([][]byte{[]byte(key)}, srv.read(key), key)
+			}
+			if tc.saved {
+				req.Empty(srv.read("durable:0"))
+				for i := range 5 {
+					key := fmt.Sprintf("pending:%d", i)
+					req.Equal([][]byte{[]byte(key)}, srv.read(key), key)
+				}
+			} else {
+				req.Equal([][]byte{[]byte("durable:0")}, srv.read("durable:0"))
+			}
+
+			// what the crash left behind merges and verifies
+			_, err = srv.client.Flush(srv.ctx(), &proto.Empty{})
+			req.NoError(err)
+			srv.stop()
+
+			dataDir := filepath.Join(home, defaultDir)
+			temp, err := filepath.Glob(filepath.Join(dataDir, "*", "*.tmp"))
+			req.NoError(err)
+			req.Empty(temp)
+			report, err := shard_storage.Verify(dataDir)
+			req.NoError(err)
+			req.True(report.OK())
+		})
+	}
+}
+
+// writeCrashConfig writes a config on free ports and returns its path and gRPC port. The timers
+// are long so that only the flushes of the test save snapshots and backups.
+func writeCrashConfig(t *testing.T, home string) (string, int) {
+	path := filepath.Join(home, configFile)
+	rpcPort := freePort(t)
+	contents := fmt.Sprintf(`server:
+  address: 127.0.0.1
+  port: %d
+  rpc_port: %d
+storage:
+  snapshot_timer: 3600
+  backup_timer: 3600
+cdc:
+  port: %d
+`, freePort(t), rpcPort, freePort(t))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path, rpcPort
+}
+
+const configFile = "litetable.yaml"
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+type testServer struct {
+	t      *testing.T
+	cmd    *exec.Cmd
+	exited chan error
+	// done is set once the exit was received from exited
+	done   bool
+	logs   *bytes.Buffer
+	client proto.LitetableServiceClient
+}
+
+// startServer starts the server in home and waits until it serves requests. crashAt names the
+// fault point to kill it at, if any.
+func startServer(t *testing.T, bin, home, cfg string, rpcPort int,
+	crashAt string) *testServer {
+	s := &testServer{t: t, logs: &bytes.Buffer{}, exited: make(chan error, 1)}
+	s.cmd = exec.Command(bin, "--config", cfg)
+	s.cmd.Env = append(os.Environ(), "HOME="+home, "LITETABLE_CRASH_AT="+crashAt)
+	s.cmd.Stdout, s.cmd.Stderr = s.logs, s.logs
+	require.NoError(t, s.cmd.Start())
+	go func() { s.exited <- s.cmd.Wait() }()
+	t.Cleanup(func() {
+		if !s.done {
+			_ = s.cmd.Process.Kill()
+			<-s.exited
+		}
+		if t.Failed() {
+			t.Logf("server logs:\n%s", s.logs.String())
+		}
+	})
+
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", rpcPort),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	s.client = proto.NewLitetableServiceClient(conn)
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		if _, err = s.client.ListTables(s.ctx(), &proto.Empty{}); err == nil {
+			return s
+		}
+		select {
+		case err = <-s.exited:
+			s.done = true
+			t.Fatalf("server exited on start: %v\n%s", err, s.logs.String())
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start: %v\n%s", err, s.logs.String())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (s *testServer) ctx() context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	s.t.Cleanup(cancel)
+	return ctx
+}
+
+// write writes the row key as the name of a row.
+func (s *testServer) write(rowKey string, sync proto.WriteSync) error {
+	_, err := s.client.Write(s.ctx(), &proto.WriteRequest{
+		RowKey:     rowKey,
+		Family:     "main",
+		Qualifiers: []*proto.ColumnQualifier{{Name: "name", Value: []byte(rowKey)}},
+		Sync:       sync,
+	})
+	return err
+}
+
+// read returns the versions of the name of a row, newest first.
+func (s *testServer) read(rowKey string) [][]byte {
+	data, err := s.client.Read(s.ctx(), &proto.ReadRequest{
+		RowKey:    rowKey,
+		QueryType: proto.QueryType_EXACT,
+		Family:    "main",
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	require.NoError(s.t, err)
+
+	var values [][]byte
+	row, ok := data.GetRows()[rowKey]
+	if !ok {
+		return nil
+	}
+	for _, v := range row.GetCols()["main"].GetQualifiers()["name"].GetValues() {
+		values = append(values, v.GetValue())
+	}
+	return values
+}
+
+// waitKilled waits for the server to be killed at its fault point.
+func (s *testServer) waitKilled() {
+	select {
+	case err := <-s.exited:
+		s.done = true
+		require.Error(s.t, err, "the server exited cleanly")
+	case <-time.After(30 * time.Second):
+		s.t.Fatal("the server was not killed")
+	}
+}
+
+// stop shuts the server down gracefully.
+func (s *testServer) stop() {
+	require.NoError(s.t, s.cmd.Process.Signal(os.Interrupt))
+	select {
+	case err := <-s.exited:
+		s.done = true
+		require.NoError(s.t, err)
+	case <-time.After(60 * time.Second):
+		s.t.Fatal("the server did not stop")
+	}
+}
@@ -4,12 +4,16 @@ go 1.24.2
 
 require (
 	github.com/google/uuid v1.6.0
+	github.com/klauspost/compress v1.19.2
 	github.com/litetable/litetable-cdc/go v0.0.0-20250513134217-86c8304ea9c1
 	github.com/litetable/litetable-db/pkg v0.0.0-20250512131000-8654642e1b45
 	github.com/rs/zerolog v1.34.0
 	github.com/stretchr/testify v1.10.0
 	go.uber.org/mock v0.5.2
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250512202823-5a2f75b736a9
 	google.golang.org/grpc v1.72.0
+	google.golang.org/protobuf v1.36.6
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -20,7 +24,8 @@ require (
 	golang.org/x/net v0.40.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.25.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20250512202823-5a2f75b736a9 // indirect
-	google.golang.org/protobuf v1.36.6 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// The server is built against the protos and client in this tree rather than the last
+// published pkg module, so RPCs added to pkg/proto can be served before pkg is tagged.
+replace github.com/litetable/litetable-db/pkg => ./pkg
@@ -7,11 +7,16 @@ import (
 	"github.com/rs/zerolog/log"
 	"os"
 	"os/signal"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 )
 
+// minStopTimeout guards against a StopTimeout given as a bare number of seconds.
+const minStopTimeout = 100 * time.Millisecond
+
 //go:generate mockgen -destination=./app_mock.go -package=app -source=app.go
 
 // Dependency is the interface that wraps the basic methods of a dependency required for the application.
@@ -24,12 +29,60 @@ type Dependency interface {
 	Name() string
 }
 
+// configuredDependency carries the startup phase and stop timeout of a dependency.
+type configuredDependency struct {
+	Dependency
+	phase       int
+	stopTimeout time.Duration
+	restart     *RestartPolicy
+}
+
+func configure(dep Dependency) *configuredDependency {
+	if c, ok := dep.(*configuredDependency); ok {
+		return c
+	}
+	return &configuredDependency{Dependency: dep}
+}
+
+// InPhase assigns a dependency to a startup phase. Phases start in ascending order and a phase
+// only starts once every dependency in the previous phases has returned from Start, so servers
+// can be held back until storage is loaded. Dependencies not wrapped with InPhase are in
+// phase 0. Dependencies are stopped in the reverse order.
+func InPhase(phase int, dep Dependency) Dependency {
+	c := configure(dep)
+	c.phase = phase
+	return c
+}
+
+// WithStopTimeout bounds how long the app waits for a dependency to stop. Without it, a
+// dependency may use whatever is left of the app's StopTimeout.
+func WithStopTimeout(timeout time.Duration, dep Dependency) Dependency {
+	c := configure(dep)
+	c.stopTimeout = timeout
+	return c
+}
+
+func phaseOf(dep Dependency) int {
+	if c, ok := dep.(*configuredDependency); ok {
+		return c.phase
+	}
+	return 0
+}
+
+func stopTimeoutOf(dep Dependency) time.Duration {
+	if c, ok := dep.(*configuredDependency); ok {
+		return c.stopTimeout
+	}
+	return 0
+}
+
 type App struct {
 	serviceName string
 	// Deps is a list of dependencies that the application will start.
 	deps []Dependency
-	// depFailChan is a channel that will be used to signal when a dependency has failed to start.
-	depFailChan chan error
+	// depFailChan is a channel that will be used to signal when a dependency has failed to start
+	// or, for a Failer, failed while running.
+	depFailChan chan *dependencyFailure
 	// osSignalChan is a channel that will be used to signal when the OS has sent a signal to the application.
 	osSignalChan chan os.Signal
 	// stopCalled is an atomic bool. It allows stop to be called once
@@ -38,11 +91,25 @@ type App struct {
 	runCalled *atomic.Bool
 	// stopTimeout is the amount of time the application will wait for dependencies to stop before exiting.
 	stopTimeout time.Duration
+	// reload is called on SIGHUP to apply configuration changes without a restart.
+	reload func() error
+
+	// lifecycleMutex orders the restart of a dependency with stop, so a dependency is never
+	// started again while the app is being stopped
+	lifecycleMutex sync.Mutex
+	// restarts tracks the dependencies being restarted under a RestartPolicy
+	restartMutex sync.Mutex
+	restarts     map[Dependency]*restartState
+	// watching holds the dependencies whose runtime failures are being watched
+	watching sync.Map
 }
 
 type Config struct {
 	ServiceName string
+	// StopTimeout is the total time allowed for every dependency to stop, e.g. 30 * time.Second.
 	StopTimeout time.Duration
+	// Reload is optional. When set, it is called every time the process receives SIGHUP.
+	Reload func() error
 }
 
 func (c *Config) validate() error {
@@ -52,6 +119,10 @@ func (c *Config) validate() error {
 	}
 	if c.StopTimeout == 0 {
 		errs = append(errs, errors.New("stop timeout is required"))
+	} else if c.StopTimeout < minStopTimeout {
+		// a bare number such as 30 is 30ns, not 30s
+		errs = append(errs, fmt.Errorf("stop timeout must be at least %s, got %s",
+			minStopTimeout, c.StopTimeout))
 	}
 	return errors.Join(errs...)
 }
@@ -65,10 +136,12 @@ func CreateApp(cfg *Config, deps ...Dependency) (*App, error) {
 		serviceName:  cfg.ServiceName,
 		deps:         deps,
 		stopTimeout:  cfg.StopTimeout,
+		reload:       cfg.Reload,
 		stopCalled:   &atomic.Bool{},
 		runCalled:    &atomic.Bool{},
-		depFailChan:  make(chan error, len(deps)), // only 1 channel for each dependency
-		osSignalChan: make(chan os.Signal, 1),     // first signal we get shuts down the app
+		depFailChan:  make(chan *dependencyFailure, len(deps)), // 1 slot for each dependency
+		osSignalChan: make(chan os.Signal, 1),                  // first signal we get shuts down the app
+		restarts:     make(map[Dependency]*restartState),
 	}, nil
 }
 
@@ -82,46 +155,21 @@ func (a *App) Run(ctx context.Context) error {
 
 	// defer funcs are always LIFO - don't forget!
 	ctxCancel, cancel := context.WithCancel(ctx) // we are cancelling the consumer context
+	// depFailChan is left open: a dependency in an unfinished phase may still report a failure
+	// after Run returns
 	defer func() {
-		close(a.depFailChan)
 		close(a.osSignalChan)
 		cancel() // cancel would be called first, then close the channels
 	}()
 
 	// TODO: probably want to handle a panic
 
-	// Start all dependencies
-	for _, dep := range a.deps {
-		// Each dependency exists in its own goroutine. Some deps like a grpc server will run
-		// inside the goroutine until the SIGTERM is received. We should never block, but listen for
-		// failures
-		go func(dep Dependency) {
-			defer func() {
-				if err := recover(); err != nil {
-					// if error, throw error into depFailChan
-					a.depFailChan <- fmt.Errorf("panic in Start() for dependency %s: %v", dep.Name(), err)
-				}
-			}()
-
-			log.Info().Msg("Starting dependency: " + dep.Name())
-			err := dep.Start()
-			if err != nil {
-				a.depFailChan <- fmt.Errorf("failure in Start() for dependency %s: %v", dep.Name(),
-					err)
-			}
-		}(dep)
-	}
-
 	// here we are waiting for a signal from the OS or a failure from a dependency,
 	// or the ctx to just cancel
 	signal.Notify(a.osSignalChan, os.Interrupt, syscall.SIGTERM)
-	select {
-	case <-ctxCancel.Done():
-		log.Info().Msg("App Context cancelled: shutting down")
-	case depErr := <-a.depFailChan:
-		log.Error().Msg("Dependency failed to start: " + depErr.Error())
-	case sig := <-a.osSignalChan:
-		log.Info().Msg("OS Signal received: " + sig.String() + " shutdown beginning...")
+
+	if a.startPhases(ctxCancel) {
+		a.wait(ctxCancel)
 	}
 
 	// Stop all dependencies
@@ -134,37 +182,167 @@ func (a *App) Run(ctx context.Context) error {
 	return nil
 }
 
-// stop attempts a graceful shutdown of each dependency.
+// startPhases starts the dependencies one phase at a time. It returns false if the app should
+// shut down before every phase has started.
+func (a *App) startPhases(ctx context.Context) bool {
+	phases := make(map[int][]Dependency)
+	var order []int
+	for _, dep := range a.deps {
+		p := phaseOf(dep)
+		if _, ok := phases[p]; !ok {
+			order = append(order, p)
+		}
+		phases[p] = append(phases[p], dep)
+	}
+	sort.Ints(order)
+
+	for _, p := range order {
+		started := make(chan struct{})
+		var wg sync.WaitGroup
+		for _, dep := range phases[p] {
+			wg.Add(1)
+			// Each dependency starts in its own goroutine so a slow Start does not hold up the
+			// rest of its phase. We should never block, but listen for failures
+			a.startDependency(ctx, dep, wg.Done)
+		}
+		go func() {
+			wg.Wait()
+			close(started)
+		}()
+
+	phase:
+		for {
+			select {
+			case <-started:
+				log.Info().Int("phase", p).Msg("Startup phase complete")
+				break phase
+			case <-ctx.Done():
+				log.Info().Msg("App Context cancelled during startup: shutting down")
+				return false
+			case failure := <-a.depFailChan:
+				if a.restart(ctx, failure) {
+					continue
+				}
+				log.Error().Msg("Dependency failed to start: " + failure.err.Error())
+				return false
+			case sig := <-a.osSignalChan:
+				log.Info().Msg("OS Signal received during startup: " + sig.String() +
+					" shutdown beginning...")
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// wait blocks until the app should shut down, reloading the configuration on SIGHUP.
+func (a *App) wait(ctx context.Context) {
+	reloadChan := make(chan os.Signal, 1)
+	if a.reload != nil {
+		signal.Notify(reloadChan, syscall.SIGHUP)
+		defer signal.Stop(reloadChan)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("App Context cancelled: shutting down")
+			return
+		case failure := <-a.depFailChan:
+			if a.restart(ctx, failure) {
+				continue
+			}
+			log.Error().Msg("Dependency failed: " + failure.err.Error())
+			return
+		case sig := <-a.osSignalChan:
+			log.Info().Msg("OS Signal received: " + sig.String() + " shutdown beginning...")
+			return
+		case <-reloadChan:
+			log.Info().Msg("SIGHUP received: reloading configuration")
+			if err := a.reload(); err != nil {
+				log.Error().Err(err).Msg("configuration reload failed, keeping current settings")
+				continue
+			}
+			log.Info().Msg("configuration reloaded")
+		}
+	}
+}
+
+// stop attempts a graceful shutdown of each dependency, in the reverse of the startup order.
+// Each dependency gets its own stop timeout, capped by what is left of the app's StopTimeout; a
+// dependency that does not stop in time is reported by name and the rest are still stopped.
 func (a *App) stop() error {
+	a.lifecycleMutex.Lock()
 	if a.stopCalled.Load() {
+		a.lifecycleMutex.Unlock()
 		return errors.New("stop has already been called")
 	}
 
-	// set stopCalled to true
+	// set stopCalled to true, after any restart in progress has finished
 	a.stopCalled.Store(true)
+	a.lifecycleMutex.Unlock()
 
-	ctxTo, cancel := context.WithTimeout(context.Background(), a.stopTimeout+60*time.Second)
+	deadline := time.Now().Add(a.stopTimeout)
 
 	var errs []error
+	for _, dep := range a.stopOrder() {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			errs = append(errs, fmt.Errorf("dependency %s not stopped: stop timeout of %s exceeded",
+				dep.Name(), a.stopTimeout))
+			continue
+		}
 
-	go func(ctx context.Context) {
-		defer cancel()
+		timeout := remaining
+		if t := stopTimeoutOf(dep); t > 0 && t < timeout {
+			timeout = t
+		}
 
-		for _, dep := range a.deps {
-			log.Info().Msg("Stopping dependency: " + dep.Name())
-			if err := dep.Stop(); err != nil {
-				errs = append(errs, fmt.Errorf("failure in Stop() for dependency %s: %v", dep.Name(), err))
-			}
+		log.Info().Msg("Stopping dependency: " + dep.Name())
+		if err := stopWithTimeout(dep, timeout); err != nil {
+			errs = append(errs, err)
 		}
-	}(ctxTo)
+	}
 
-	// we need all dependencies to stop before we can return: block until ctxTo is done
-	<-ctxTo.Done()
+	return errors.Join(errs...)
+}
 
-	// check the ctxTo for errors. This could have been a timeout
-	if err := ctxTo.Err(); errors.Is(err, context.DeadlineExceeded) {
-		errs = append(errs, err)
+// stopOrder returns the dependencies in the reverse of the order they were started.
+func (a *App) stopOrder() []Dependency {
+	order := make([]Dependency, len(a.deps))
+	for i, dep := range a.deps {
+		order[len(a.deps)-1-i] = dep
 	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return phaseOf(order[i]) > phaseOf(order[j])
+	})
+	return order
+}
 
-	return errors.Join(errs...)
+// stopWithTimeout stops a dependency, giving up after the timeout. A dependency that does not
+// return in time is left running in the background while shutdown continues.
+func stopWithTimeout(dep Dependency, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic in Stop() for dependency %s: %v", dep.Name(), r)
+			}
+		}()
+		done <- dep.Stop()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failure in Stop() for dependency %s: %v", dep.Name(), err)
+		}
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("dependency %s did not stop within %s", dep.Name(), timeout)
+	}
 }
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the order dependencies were started and stopped in.
+type recorder struct {
+	mutex  sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) list() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+type fakeDependency struct {
+	name      string
+	rec       *recorder
+	delay     time.Duration
+	startErr  error
+	stopDelay time.Duration
+}
+
+func (f *fakeDependency) Start() error {
+	time.Sleep(f.delay)
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.rec.add("start " + f.name)
+	return nil
+}
+
+func (f *fakeDependency) Stop() error {
+	time.Sleep(f.stopDelay)
+	f.rec.add("stop " + f.name)
+	return nil
+}
+
+func (f *fakeDependency) Name() string { return f.name }
+
+func TestCreateApp(t *testing.T) {
+	_, err := CreateApp(&Config{})
+	require.EqualError(t, err, "service name is required\nstop timeout is required")
+
+	_, err = CreateApp(&Config{ServiceName: "test", StopTimeout: 30})
+	require.EqualError(t, err, "stop timeout must be at least 100ms, got 30ns")
+}
+
+func TestApp_Run_phases(t *testing.T) {
+	req := require.New(t)
+	rec := &recorder{}
+
+	a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+		InPhase(2, &fakeDependency{name: "server", rec: rec}),
+		InPhase(1, &fakeDependency{name: "storage", rec: rec, delay: 50 * time.Millisecond}),
+	)
+	req.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- a.Run(ctx) }()
+
+	req.Eventually(func() bool { return len(rec.list()) == 2 }, time.Second, 10*time.Millisecond)
+	cancel()
+	req.NoError(<-done)
+
+	req.Equal([]string{"start storage", "start server", "stop server", "stop storage"},
+		rec.list())
+}
+
+func TestApp_Run_failedPhaseStopsStartup(t *testing.T) {
+	req := require.New(t)
+	rec := &recorder{}
+
+	a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+		InPhase(0, &fakeDependency{name: "storage", rec: rec, startErr: errors.New("boom")}),
+		InPhase(1, &fakeDependency{name: "server", rec: rec}),
+	)
+	req.NoError(err)
+
+	req.NoError(a.Run(context.Background()))
+	req.NotContains(rec.list(), "start server")
+}
+
+func TestApp_stop(t *testing.T) {
+	req := require.New(t)
+	rec := &recorder{}
+
+	a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+		InPhase(0, &fakeDependency{name: "cdc", rec: rec}),
+		InPhase(0, &fakeDependency{name: "storage", rec: rec}),
+		WithStopTimeout(20*time.Millisecond,
+			InPhase(1, &fakeDependency{name: "server", rec: rec, stopDelay: time.Second})),
+	)
+	req.NoError(err)
+
+	start := time.Now()
+	err = a.stop()
+	req.EqualError(err, "dependency server did not stop within 20ms")
+	req.Less(time.Since(start), 500*time.Millisecond)
+
+	// the slow server is skipped over and the rest still stop in reverse order
+	req.Equal([]string{"stop storage", "stop cdc"}, rec.list())
+
+	req.EqualError(a.stop(), "stop has already been called")
+}
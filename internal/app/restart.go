@@ -0,0 +1,268 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const (
+	defaultRestartBackoff    = time.Second
+	defaultRestartMaxBackoff = time.Minute
+)
+
+// RestartPolicy lets the app restart a failed dependency instead of shutting down. Only use it
+// for dependencies whose Start can be called again after a failure. A failed dependency is
+// stopped before it is restarted, unless it is a Resetter.
+type RestartPolicy struct {
+	// MaxRetries is the number of restarts attempted after consecutive failures. A successful
+	// Start resets the count.
+	MaxRetries int
+	// Backoff is the delay before the first restart. It doubles on every retry up to MaxBackoff.
+	// Defaults to 1s and 1m.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// CrashLoopFailures failures within CrashLoopWindow are treated as a crash loop and shut the
+	// app down even if retries remain. Disabled when either is zero.
+	CrashLoopFailures int
+	CrashLoopWindow   time.Duration
+}
+
+// Failer is implemented by dependencies that can fail after Start has returned, such as a
+// server whose listener dies. A value on the channel is handled like a failed Start.
+type Failer interface {
+	Failed() <-chan error
+}
+
+// Resetter is implemented by restartable dependencies whose Stop is final. Reset releases what a
+// failed dependency holds, such as a listener, so Start can be called again; Stop is then only
+// called when the app shuts down.
+type Resetter interface {
+	Reset() error
+}
+
+// WithRestart restarts a dependency according to the policy when it fails.
+func WithRestart(policy RestartPolicy, dep Dependency) Dependency {
+	c := configure(dep)
+	c.restart = &policy
+	return c
+}
+
+func restartPolicyOf(dep Dependency) *RestartPolicy {
+	if c, ok := dep.(*configuredDependency); ok {
+		return c.restart
+	}
+	return nil
+}
+
+func unwrap(dep Dependency) Dependency {
+	if c, ok := dep.(*configuredDependency); ok {
+		return c.Dependency
+	}
+	return dep
+}
+
+// dependencyFailure reports a dependency that failed to start or failed while running.
+type dependencyFailure struct {
+	dep Dependency
+	err error
+	// onStarted is carried over to the restart, so a restarted dependency still completes its
+	// startup phase.
+	onStarted func()
+}
+
+// restartState tracks the failures of one dependency.
+type restartState struct {
+	retries  int
+	failures []time.Time
+}
+
+// startDependency starts a dependency in its own goroutine. onStarted is called once Start
+// succeeds; failures are reported on depFailChan.
+func (a *App) startDependency(ctx context.Context, dep Dependency, onStarted func()) {
+	go func() {
+		if failure := a.start(ctx, dep, onStarted); failure != nil {
+			a.reportFailure(ctx, failure)
+		}
+	}()
+}
+
+// start starts a dependency and watches it for runtime failures, or returns why it failed to
+// start.
+func (a *App) start(ctx context.Context, dep Dependency, onStarted func()) (
+	failure *dependencyFailure) {
+	defer func() {
+		if err := recover(); err != nil {
+			failure = &dependencyFailure{
+				dep:       dep,
+				err:       fmt.Errorf("panic in Start() for dependency %s: %v", dep.Name(), err),
+				onStarted: onStarted,
+			}
+		}
+	}()
+
+	log.Info().Int("phase", phaseOf(dep)).Msg("Starting dependency: " + dep.Name())
+	if err := dep.Start(); err != nil {
+		return &dependencyFailure{
+			dep:       dep,
+			err:       fmt.Errorf("failure in Start() for dependency %s: %v", dep.Name(), err),
+			onStarted: onStarted,
+		}
+	}
+
+	a.restartMutex.Lock()
+	if state, ok := a.restarts[dep]; ok {
+		state.retries = 0
+	}
+	a.restartMutex.Unlock()
+
+	if onStarted != nil {
+		onStarted()
+	}
+	a.watch(ctx, dep)
+	return nil
+}
+
+// restartDependency stops or resets a failed dependency and starts it again, unless the app is
+// stopping.
+// It holds the lifecycle mutex throughout, so stop waits for the restart to finish and then
+// stops the restarted dependency rather than racing with it.
+func (a *App) restartDependency(ctx context.Context, f *dependencyFailure) *dependencyFailure {
+	a.lifecycleMutex.Lock()
+	defer a.lifecycleMutex.Unlock()
+	if a.stopCalled.Load() {
+		return nil
+	}
+
+	// a dependency that failed may still hold what it started with, such as a listener
+	if r, ok := unwrap(f.dep).(Resetter); ok {
+		if err := r.Reset(); err != nil {
+			log.Warn().Err(err).Msg("Failed to reset dependency before restarting: " + f.dep.Name())
+		}
+		return a.start(ctx, f.dep, f.onStarted)
+	}
+	timeout := a.stopTimeout
+	if t := stopTimeoutOf(f.dep); t > 0 && t < timeout {
+		timeout = t
+	}
+	if err := stopWithTimeout(f.dep, timeout); err != nil {
+		log.Warn().Err(err).Msg("Failed to stop dependency before restarting: " + f.dep.Name())
+	}
+	return a.start(ctx, f.dep, f.onStarted)
+}
+
+// watch forwards runtime failures of a Failer to depFailChan. It is started once per dependency
+// and keeps running across restarts.
+func (a *App) watch(ctx context.Context, dep Dependency) {
+	failer, ok := unwrap(dep).(Failer)
+	if !ok {
+		return
+	}
+	if _, loaded := a.watching.LoadOrStore(dep, struct{}{}); loaded {
+		return
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-failer.Failed():
+				a.reportFailure(ctx, &dependencyFailure{
+					dep: dep,
+					err: fmt.Errorf("dependency %s failed: %v", dep.Name(), err),
+				})
+			}
+		}
+	}()
+}
+
+func (a *App) reportFailure(ctx context.Context, f *dependencyFailure) {
+	select {
+	case a.depFailChan <- f:
+	case <-ctx.Done():
+	}
+}
+
+// restart schedules a restart of a failed dependency. It returns false when the dependency has
+// no restart policy, has used up its retries, or is crash looping, in which case the app should
+// shut down.
+func (a *App) restart(ctx context.Context, f *dependencyFailure) bool {
+	policy := restartPolicyOf(f.dep)
+	if policy == nil {
+		return false
+	}
+
+	a.restartMutex.Lock()
+	state, ok := a.restarts[f.dep]
+	if !ok {
+		state = &restartState{}
+		a.restarts[f.dep] = state
+	}
+
+	now := time.Now()
+	state.failures = append(state.failures, now)
+	if policy.CrashLoopFailures > 0 && policy.CrashLoopWindow > 0 {
+		recent := state.failures[:0]
+		for _, t := range state.failures {
+			if now.Sub(t) <= policy.CrashLoopWindow {
+				recent = append(recent, t)
+			}
+		}
+		state.failures = recent
+		if len(recent) >= policy.CrashLoopFailures {
+			a.restartMutex.Unlock()
+			log.Error().Int("failures", len(recent)).Dur("window", policy.CrashLoopWindow).
+				Msg("Dependency is crash looping: " + f.dep.Name())
+			return false
+		}
+	}
+
+	if state.retries >= policy.MaxRetries {
+		a.restartMutex.Unlock()
+		log.Error().Int("retries", state.retries).
+			Msg("Dependency exhausted its restarts: " + f.dep.Name())
+		return false
+	}
+	backoff := policy.backoff(state.retries)
+	state.retries++
+	attempt := state.retries
+	a.restartMutex.Unlock()
+
+	log.Warn().Err(f.err).Int("attempt", attempt).Dur("backoff", backoff).
+		Msg("Restarting dependency: " + f.dep.Name())
+
+	go func() {
+		timer := time.NewTimer(backoff)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		// the failure is reported once the lifecycle mutex is released, since stop may be
+		// waiting for it while nothing reads depFailChan
+		if failure := a.restartDependency(ctx, f); failure != nil {
+			a.reportFailure(ctx, failure)
+		}
+	}()
+	return true
+}
+
+// backoff returns the delay before the given retry.
+func (p *RestartPolicy) backoff(retry int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultRestartBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRestartMaxBackoff
+	}
+
+	for i := 0; i < retry && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
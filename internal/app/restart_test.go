@@ -0,0 +1,209 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyDependency fails its first failures calls to Start.
+type flakyDependency struct {
+	fakeDependency
+	failures int32
+	calls    atomic.Int32
+	failed   chan error
+}
+
+func (f *flakyDependency) Start() error {
+	if f.calls.Add(1) <= f.failures {
+		return errors.New("not yet")
+	}
+	return f.fakeDependency.Start()
+}
+
+func (f *flakyDependency) Failed() <-chan error { return f.failed }
+
+// resettableDependency is a flakyDependency whose Stop is final, so a restart resets it.
+type resettableDependency struct {
+	flakyDependency
+}
+
+func (r *resettableDependency) Reset() error {
+	r.rec.add("reset " + r.name)
+	return nil
+}
+
+func TestRestartPolicy_backoff(t *testing.T) {
+	p := &RestartPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	require.Equal(t, time.Second, p.backoff(0))
+	require.Equal(t, 2*time.Second, p.backoff(1))
+	require.Equal(t, 4*time.Second, p.backoff(2))
+	require.Equal(t, 5*time.Second, p.backoff(3))
+
+	require.Equal(t, defaultRestartBackoff, (&RestartPolicy{}).backoff(0))
+}
+
+func TestApp_restart(t *testing.T) {
+	policy := RestartPolicy{MaxRetries: 3, Backoff: time.Millisecond}
+
+	t.Run("restarts until start succeeds", func(t *testing.T) {
+		req := require.New(t)
+		rec := &recorder{}
+		cdc := &flakyDependency{fakeDependency: fakeDependency{name: "cdc", rec: rec}, failures: 2}
+
+		a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+			WithRestart(policy, InPhase(0, cdc)),
+			InPhase(1, &fakeDependency{name: "server", rec: rec}),
+		)
+		req.NoError(err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- a.Run(ctx) }()
+
+		req.Eventually(func() bool { return len(rec.list()) == 4 }, time.Second,
+			5*time.Millisecond)
+		cancel()
+		req.NoError(<-done)
+
+		// every failed start is stopped before it is retried
+		req.Equal(int32(3), cdc.calls.Load())
+		req.Equal([]string{"stop cdc", "stop cdc", "start cdc", "start server"}, rec.list()[:4])
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		req := require.New(t)
+		rec := &recorder{}
+		cdc := &flakyDependency{fakeDependency: fakeDependency{name: "cdc", rec: rec}, failures: 10}
+
+		a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+			WithRestart(policy, cdc))
+		req.NoError(err)
+
+		req.NoError(a.Run(context.Background()))
+		req.Equal(int32(4), cdc.calls.Load())
+	})
+
+	t.Run("detects a crash loop", func(t *testing.T) {
+		req := require.New(t)
+		rec := &recorder{}
+		cdc := &flakyDependency{fakeDependency: fakeDependency{name: "cdc", rec: rec}, failures: 10}
+
+		a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+			WithRestart(RestartPolicy{
+				MaxRetries:        10,
+				Backoff:           time.Millisecond,
+				CrashLoopFailures: 2,
+				CrashLoopWindow:   time.Minute,
+			}, cdc))
+		req.NoError(err)
+
+		req.NoError(a.Run(context.Background()))
+		req.Equal(int32(2), cdc.calls.Load())
+	})
+
+	t.Run("restarts after a runtime failure", func(t *testing.T) {
+		req := require.New(t)
+		rec := &recorder{}
+		cdc := &flakyDependency{
+			fakeDependency: fakeDependency{name: "cdc", rec: rec},
+			failed:         make(chan error, 1),
+		}
+
+		a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+			WithRestart(policy, cdc))
+		req.NoError(err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- a.Run(ctx) }()
+
+		req.Eventually(func() bool { return cdc.calls.Load() == 1 }, time.Second,
+			5*time.Millisecond)
+		cdc.failed <- errors.New("listener closed")
+		req.Eventually(func() bool { return cdc.calls.Load() == 2 }, time.Second,
+			5*time.Millisecond)
+
+		cancel()
+		req.NoError(<-done)
+		req.Equal([]string{"start cdc", "stop cdc", "start cdc", "stop cdc"}, rec.list())
+	})
+
+	t.Run("resets a Resetter instead of stopping it", func(t *testing.T) {
+		req := require.New(t)
+		rec := &recorder{}
+		cdc := &resettableDependency{flakyDependency{
+			fakeDependency: fakeDependency{name: "cdc", rec: rec},
+			failed:         make(chan error, 1),
+		}}
+
+		a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+			WithRestart(policy, cdc))
+		req.NoError(err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- a.Run(ctx) }()
+
+		req.Eventually(func() bool { return cdc.calls.Load() == 1 }, time.Second,
+			5*time.Millisecond)
+		cdc.failed <- errors.New("listener closed")
+		req.Eventually(func() bool { return cdc.calls.Load() == 2 }, time.Second,
+			5*time.Millisecond)
+
+		cancel()
+		req.NoError(<-done)
+		req.Equal([]string{"start cdc", "reset cdc", "start cdc", "stop cdc"}, rec.list())
+	})
+
+	t.Run("stopping waits for a restart and cancels the next", func(t *testing.T) {
+		req := require.New(t)
+		rec := &recorder{}
+		cdc := &flakyDependency{
+			fakeDependency: fakeDependency{name: "cdc", rec: rec, delay: 50 * time.Millisecond},
+			failures:       1,
+		}
+
+		a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second},
+			WithRestart(policy, cdc))
+		req.NoError(err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- a.Run(ctx) }()
+
+		// cancel while the restart is in its Start, which stop must wait for
+		req.Eventually(func() bool { return cdc.calls.Load() == 2 }, time.Second,
+			time.Millisecond)
+		cancel()
+		req.NoError(<-done)
+		req.Equal([]string{"stop cdc", "start cdc", "stop cdc"}, rec.list())
+
+		// a restart scheduled after stop never starts the dependency
+		req.Nil(a.restartDependency(ctx, &dependencyFailure{dep: cdc}))
+		req.Equal(int32(2), cdc.calls.Load())
+	})
+
+	t.Run("failures without a policy shut down", func(t *testing.T) {
+		req := require.New(t)
+		rec := &recorder{}
+		cdc := &flakyDependency{
+			fakeDependency: fakeDependency{name: "cdc", rec: rec},
+			failed:         make(chan error, 1),
+		}
+
+		a, err := CreateApp(&Config{ServiceName: "test", StopTimeout: time.Second}, cdc)
+		req.NoError(err)
+
+		done := make(chan error, 1)
+		go func() { done <- a.Run(context.Background()) }()
+
+		req.Eventually(func() bool { return cdc.calls.Load() == 1 }, time.Second,
+			5*time.Millisecond)
+		cdc.failed <- errors.New("listener closed")
+		req.NoError(<-done)
+	})
+}
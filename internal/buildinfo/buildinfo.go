@@ -0,0 +1,105 @@
+// Package buildinfo describes the running server: its build, the versions of the API and the
+// storage formats it speaks, and how its storage is set up, so operators and clients can check
+// compatibility during a rollout.
+package buildinfo
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Version and Commit are stamped into release builds:
+//
+//	go build -ldflags "-X github.com/litetable/litetable-db/internal/buildinfo.Version=v1.2.0 \
+//	  -X github.com/litetable/litetable-db/internal/buildinfo.Commit=$(git rev-parse HEAD)"
+//
+// Without a commit, the VCS revision the go tool records in the binary is reported.
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// Info describes the running server.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"goVersion"`
+	// APIVersion is the protobuf package of the gRPC API. Its major version only changes with
+	// a breaking change.
+	APIVersion string `json:"apiVersion"`
+	// ProtobufVersion and GRPCVersion are the versions of the libraries serving the API
+	ProtobufVersion string `json:"protobufVersion"`
+	GRPCVersion     string `json:"grpcVersion"`
+	// the versions of the backups, delta backups and snapshots the server writes
+	BackupFormatVersion   int    `json:"backupFormatVersion"`
+	DeltaFormatVersion    int    `json:"deltaFormatVersion"`
+	SnapshotFormatVersion int    `json:"snapshotFormatVersion"`
+	StorageEngine         string `json:"storageEngine"`
+	ShardCount            int    `json:"shardCount"`
+	// StartedAt is when the server started, in Unix nanoseconds
+	StartedAt int64 `json:"startedAtUnix"`
+	// UptimeSeconds is how long the server had been running when the info was read
+	UptimeSeconds int64 `json:"uptimeSeconds"`
+	// Startup is what the server loaded when it started. It is set before the servers accept
+	// traffic, and is nil until then.
+	Startup *Startup `json:"startup,omitempty"`
+}
+
+// Startup is what the server loaded when it started and where it listens, so operators can
+// check a recovery at a glance.
+type Startup struct {
+	// Tables are what each table loaded, by name
+	Tables []litetable.LoadReport `json:"tables"`
+	// Addresses are the addresses each server listens on, by server
+	Addresses map[string]string `json:"addresses"`
+}
+
+// New describes the running build, with the storage engine and shard count it was configured
+// with, as started now.
+func New(storageEngine string, shardCount int) *Info {
+	info := &Info{
+		Version:               Version,
+		Commit:                Commit,
+		GoVersion:             runtime.Version(),
+		APIVersion:            string(proto.File_proto_litetable_operation_proto.Package()),
+		BackupFormatVersion:   shard_storage.BackupFormatVersion,
+		DeltaFormatVersion:    shard_storage.DeltaFormatVersion,
+		SnapshotFormatVersion: shard_storage.SnapshotFormatVersion,
+		StorageEngine:         storageEngine,
+		ShardCount:            shardCount,
+		StartedAt:             time.Now().UnixNano(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, dep := range build.Deps {
+		switch dep.Path {
+		case "google.golang.org/protobuf":
+			info.ProtobufVersion = dep.Version
+		case "google.golang.org/grpc":
+			info.GRPCVersion = dep.Version
+		}
+	}
+	if info.Commit != "" {
+		return info
+	}
+	for _, setting := range build.Settings {
+		if setting.Key == "vcs.revision" {
+			info.Commit = setting.Value
+		}
+	}
+	return info
+}
+
+// At returns the info with the uptime at now.
+func (i *Info) At(now time.Time) Info {
+	at := *i
+	at.UptimeSeconds = int64(now.Sub(time.Unix(0, i.StartedAt)).Seconds())
+	return at
+}
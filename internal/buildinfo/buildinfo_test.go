@@ -0,0 +1,43 @@
+package buildinfo
+
+import (
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	req := require.New(t)
+	info := New("memory", 8)
+
+	req.Equal("dev", info.Version)
+	req.Equal(runtime.Version(), info.GoVersion)
+	req.Equal("litetable.server.v1", info.APIVersion)
+	req.NotEmpty(info.ProtobufVersion)
+	req.NotEmpty(info.GRPCVersion)
+	req.Equal(1, info.BackupFormatVersion)
+	req.Equal(1, info.DeltaFormatVersion)
+	req.Equal(1, info.SnapshotFormatVersion)
+	req.Equal("memory", info.StorageEngine)
+	req.Equal(8, info.ShardCount)
+	req.Zero(info.UptimeSeconds)
+}
+
+func TestNew_commit(t *testing.T) {
+	// a stamped commit wins over the VCS revision
+	Commit = "abc123"
+	t.Cleanup(func() { Commit = "" })
+	require.Equal(t, "abc123", New("memory", 8).Commit)
+}
+
+func TestInfo_At(t *testing.T) {
+	req := require.New(t)
+	info := New("memory", 8)
+	started := time.Unix(0, info.StartedAt)
+
+	at := info.At(started.Add(90 * time.Second))
+	req.Equal(int64(90), at.UptimeSeconds)
+	// the uptime is only set on the copy
+	req.Zero(info.UptimeSeconds)
+}
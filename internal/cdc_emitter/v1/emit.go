@@ -1,5 +1,123 @@
 package v1
 
+import (
+	"github.com/litetable/litetable-db/internal/metrics"
+)
+
+// Overflow policies decide what Emit does with an event when the event queue is full.
+const (
+	// OverflowBlock waits for room in the queue, delaying the mutation that emitted the event
+	OverflowBlock = "block"
+	// OverflowDropOldest drops the oldest queued event to make room for the new one
+	OverflowDropOldest = "drop_oldest"
+	// OverflowSpill writes the event to the spill file for replay instead of queueing it
+	OverflowSpill = "spill"
+)
+
+// OverflowPolicies are the policies that can be selected with cdc.overflow_policy.
+var OverflowPolicies = []string{OverflowBlock, OverflowDropOldest, OverflowSpill}
+
+// defaultBufferSize is the number of events queued for dispatch when the config does not say
+const defaultBufferSize = 1000
+
+var (
+	eventsDropped = metrics.NewCounter("litetable_cdc_events_dropped_total",
+		"CDC events dropped because the event queue was full.")
+	eventsOverflowSpilled = metrics.NewCounter("litetable_cdc_events_overflow_spilled_total",
+		"CDC events written to the spill file because the event queue was full.")
+	emitsBlocked = metrics.NewCounter("litetable_cdc_emits_blocked_total",
+		"Mutations that waited for room in the full CDC event queue.")
+	queueHighWater = metrics.NewGauge("litetable_cdc_queue_high_water",
+		"Most CDC events queued for dispatch at once since the server started.")
+	queueDepth = metrics.NewGauge("litetable_cdc_queue_depth",
+		"CDC events queued for dispatch.")
+)
+
+// Emit queues an event for the subscribers, unless CDC is disabled or the family of the event
+// does not emit events. Events are dropped before they are queued, so they take no buffer space.
+// When the queue is full, the overflow policy decides what happens to the event.
 func (s *Server) Emit(evt *CDCEvent) {
-	s.events <- evt
+	if !s.emits(evt.Family) {
+		return
+	}
+	select {
+	case s.events <- evt:
+	default:
+		s.overflow(evt)
+	}
+	s.recordDepth(len(s.events))
+}
+
+// emits reports whether the changes to a family are sent to subscribers.
+func (s *Server) emits(family string) bool {
+	if s.disabled {
+		return false
+	}
+	if s.families == nil {
+		return true
+	}
+	_, ok := s.families[family]
+	return ok
+}
+
+// overflow handles an event that did not fit in the full queue.
+func (s *Server) overflow(evt *CDCEvent) {
+	switch s.overflowPolicy {
+	case OverflowDropOldest:
+		for {
+			// the dispatcher may empty the queue in between, so nothing is dropped then
+			select {
+			case <-s.events:
+				eventsDropped.Inc()
+			default:
+			}
+			select {
+			case s.events <- evt:
+				return
+			default:
+			}
+		}
+	case OverflowSpill:
+		if s.Spill(evt) {
+			eventsOverflowSpilled.Inc()
+		}
+	default:
+		emitsBlocked.Inc()
+		s.events <- evt
+	}
+}
+
+// Spill keeps an event for replay without dispatching it, as the spill overflow policy does, and
+// reports whether it was kept. The event is never dispatched, so it has no sequence and watches
+// miss it. Without a spill file it is dropped.
+func (s *Server) Spill(evt *CDCEvent) bool {
+	if !s.emits(evt.Family) {
+		return false
+	}
+	evt.NodeID = s.nodeID
+	if !s.spillEvent(evt) {
+		eventsDropped.Inc()
+		return false
+	}
+	return true
+}
+
+// QueueDepth returns the number of events queued for dispatch.
+func (s *Server) QueueDepth() int {
+	return len(s.events)
+}
+
+// recordDepth reports the depth of the queue, raising its high-water mark to depth.
+func (s *Server) recordDepth(depth int) {
+	queueDepth.Set(float64(depth))
+	for {
+		highWater := s.highWater.Load()
+		if int64(depth) <= highWater {
+			return
+		}
+		if s.highWater.CompareAndSwap(highWater, int64(depth)) {
+			queueHighWater.Set(float64(depth))
+			return
+		}
+	}
 }
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestServer_Emit(t *testing.T) {
+	tests := map[string]struct {
+		cfg      *Config
+		expected []string
+	}{
+		"all families": {
+			cfg:      &Config{},
+			expected: []string{"main", "stats"},
+		},
+		"some families": {
+			cfg:      &Config{Families: []string{"stats"}},
+			expected: []string{"stats"},
+		},
+		"disabled": {
+			cfg: &Config{Disabled: true, Families: []string{"stats"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			s := New(tc.cfg)
+			s.Emit(&CDCEvent{Operation: litetable.OperationWrite, Family: "main"})
+			s.Emit(&CDCEvent{Operation: litetable.OperationWrite, Family: "stats"})
+
+			// the dispatcher is not running, so the queued events are still buffered
+			req.Len(s.events, len(tc.expected))
+			for _, family := range tc.expected {
+				req.Equal(family, (<-s.events).Family)
+			}
+		})
+	}
+}
+
+func TestServer_Emit_overflow(t *testing.T) {
+	emit := func(s *Server, rowKeys ...string) {
+		for _, rowKey := range rowKeys {
+			s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: rowKey})
+		}
+	}
+	queued := func(s *Server) []string {
+		var rowKeys []string
+		for len(s.events) > 0 {
+			rowKeys = append(rowKeys, (<-s.events).RowKey)
+		}
+		return rowKeys
+	}
+
+	t.Run("drop oldest", func(t *testing.T) {
+		req := require.New(t)
+		s := New(&Config{BufferSize: 2, OverflowPolicy: OverflowDropOldest})
+		dropped := eventsDropped.Value()
+
+		emit(s, "champ:1", "champ:2", "champ:3")
+		req.Equal(dropped+1, eventsDropped.Value())
+		req.Equal(2, s.QueueDepth())
+		req.Equal(2.0, queueDepth.Value())
+		req.Equal([]string{"champ:2", "champ:3"}, queued(s))
+		req.Equal(int64(2), s.highWater.Load())
+	})
+
+	t.Run("spill", func(t *testing.T) {
+		req := require.New(t)
+		s := New(&Config{BufferSize: 1, OverflowPolicy: OverflowSpill, NodeID: "node-a"})
+		spill, err := openSpill(t.TempDir(), 0)
+		req.NoError(err)
+		s.spill = spill
+		t.Cleanup(func() { _ = spill.close() })
+		spilled := eventsOverflowSpilled.Value()
+
+		emit(s, "champ:1", "champ:2")
+		req.Equal(spilled+1, eventsOverflowSpilled.Value())
+		req.Equal([]string{"champ:1"}, queued(s))
+		events, err := spill.read(0)
+		req.NoError(err)
+		req.Len(events, 1)
+		req.Equal("champ:2", events[0].RowKey)
+		req.Equal("node-a", events[0].NodeID)
+	})
+
+	t.Run("spill without a spill file", func(t *testing.T) {
+		req := require.New(t)
+		s := New(&Config{BufferSize: 1, OverflowPolicy: OverflowSpill})
+		dropped := eventsDropped.Value()
+
+		emit(s, "champ:1", "champ:2")
+		req.Equal(dropped+1, eventsDropped.Value())
+		req.Equal([]string{"champ:1"}, queued(s))
+	})
+
+	t.Run("block", func(t *testing.T) {
+		req := require.New(t)
+		s := New(&Config{BufferSize: 1})
+		blocked := emitsBlocked.Value()
+
+		emit(s, "champ:1")
+		done := make(chan struct{})
+		go func() {
+			emit(s, "champ:2")
+			close(done)
+		}()
+		req.Eventually(func() bool { return emitsBlocked.Value() == blocked+1 }, time.Second,
+			time.Millisecond)
+		req.Equal("champ:1", (<-s.events).RowKey)
+		<-done
+		req.Equal([]string{"champ:2"}, queued(s))
+	})
+}
@@ -1,13 +1,20 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	v1 "github.com/litetable/litetable-cdc/go/v1"
 	"github.com/litetable/litetable-db/internal/litetable"
-	"github.com/rs/zerolog/log"
+	"github.com/litetable/litetable-db/internal/logging"
+	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"net"
+	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -19,128 +26,324 @@ type Server struct {
 	v1.UnimplementedCDCServiceServer
 	address     string
 	port        int
-	grpcStreams map[string]v1.CDCService_CDCStreamServer
+	grpcStreams map[string]*grpcSubscriber
 	grpcMux     sync.Mutex
+	// sequence numbers the events in the order they are dispatched
+	sequence atomic.Uint64
+
+	// server is replaced by Reset, as a stopped gRPC server cannot serve again; serverMux guards
+	// it
+	server    *grpc.Server
+	serverMux sync.Mutex
+	events    chan *CDCEvent
+	// overflowPolicy is what Emit does when events is full
+	overflowPolicy string
+	// highWater is the most events that were queued at once
+	highWater atomic.Int64
+	// joins hands the dispatcher the subscribers that asked for a replay, and caughtUp hands it
+	// back the ones that finished it
+	joins    chan *grpcSubscriber
+	caughtUp chan *grpcSubscriber
+	// spill keeps the events a stream missed; it is nil without a spill directory, and spillMux
+	// guards it, as Emit writes overflowed events to it
+	spill         *spill
+	spillMux      sync.Mutex
+	spillDir      string
+	spillMaxBytes int64
+	// nodeID is stamped on every event as its source
+	nodeID string
+	// disabled drops every event, and families, unless nil, are the only families that emit
+	// events
+	disabled bool
+	families map[string]struct{}
+
+	// watches are the filtered subscriptions of the Watch RPC
+	watches       map[*Watch]struct{}
+	watchMux      sync.Mutex
+	watchesClosed bool
+	// watchIDs is the number of watches started, which names the next one
+	watchIDs uint64
+
+	eventWg      sync.WaitGroup
+	stopOnce     sync.Once
+	dispatchOnce sync.Once
+	// failed reports a Serve failure so the app can restart the stream
+	failed chan error
+
+	logger zerolog.Logger
+}
 
-	server *grpc.Server
-	events chan *CDCEvent
-
-	eventWg  sync.WaitGroup
-	stopOnce sync.Once
+// Config sets where the CDC stream is served. Zero values use the defaults.
+type Config struct {
+	Address string
+	Port    int
+	// SpillDir is where the events a stream subscriber missed are kept for replay. Without
+	// one, they are dropped.
+	SpillDir string
+	// SpillMaxBytes bounds the spill file, dropping the oldest events first
+	SpillMaxBytes int
+	// NodeID names this server as the source of its events. Defaults to the hostname.
+	NodeID string
+	// Disabled drops every change instead of sending it to subscribers and watches
+	Disabled bool
+	// Families restricts the events to the changes of these column families. Empty emits the
+	// changes of every family.
+	Families []string
+	// BufferSize is the number of events queued for dispatch. Defaults to 1000.
+	BufferSize int
+	// OverflowPolicy is what happens to an event when the queue is full: one of
+	// OverflowPolicies. Defaults to OverflowBlock.
+	OverflowPolicy string
 }
 
-func New() *Server {
-	cdcServer := &Server{
-		address:     cdcAddress,
-		port:        cdcPort,
-		grpcStreams: make(map[string]v1.CDCService_CDCStreamServer),
-		events:      make(chan *CDCEvent, 1000),
+func New(cfg *Config) *Server {
+	address := cfg.Address
+	if address == "" {
+		address = cdcAddress
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = cdcPort
+	}
+	nodeID := cfg.NodeID
+	if nodeID == "" {
+		nodeID, _ = os.Hostname()
+	}
+	bufferSize := cfg.BufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultBufferSize
 	}
 
-	// Create a new gRPC server
-	srv := grpc.NewServer()
-
-	// Register the CDC service
-	v1.RegisterCDCServiceServer(srv, cdcServer)
+	cdcServer := &Server{
+		address:        address,
+		port:           port,
+		grpcStreams:    make(map[string]*grpcSubscriber),
+		events:         make(chan *CDCEvent, bufferSize),
+		joins:          make(chan *grpcSubscriber),
+		caughtUp:       make(chan *grpcSubscriber),
+		spillDir:       cfg.SpillDir,
+		spillMaxBytes:  int64(cfg.SpillMaxBytes),
+		nodeID:         nodeID,
+		watches:        make(map[*Watch]struct{}),
+		failed:         make(chan error, 1),
+		logger:         logging.For("cdc"),
+		disabled:       cfg.Disabled,
+		overflowPolicy: cfg.OverflowPolicy,
+	}
+	if len(cfg.Families) > 0 {
+		cdcServer.families = make(map[string]struct{}, len(cfg.Families))
+		for _, family := range cfg.Families {
+			cdcServer.families[family] = struct{}{}
+		}
+	}
 
-	cdcServer.server = srv
+	cdcServer.server = cdcServer.newGRPCServer()
 	return cdcServer
 }
 
+// newGRPCServer creates a gRPC server with the CDC service registered.
+func (s *Server) newGRPCServer() *grpc.Server {
+	srv := grpc.NewServer()
+	v1.RegisterCDCServiceServer(srv, s)
+	return srv
+}
+
 type grpcSubscriber struct {
-	id     string
-	stream v1.CDCService_CDCStreamServer
-	done   chan struct{}
+	id          string
+	stream      v1.CDCService_CDCStreamServer
+	done        chan struct{}
+	stopOnce    sync.Once
+	connectedAt time.Time
+	// replay is whether the subscriber asked for the spilled events from replayFrom on
+	replay     bool
+	replayFrom int64
+	// joined is closed once the dispatcher registered a subscriber that asked for a replay.
+	// joinedAt is the sequence of the last event dispatched before, and pending buffers the
+	// events dispatched after it until the replay is done. Only the dispatcher changes pending.
+	joined   chan struct{}
+	joinedAt uint64
+	pending  chan *CDCEvent
+	// replayFailed is set when a send of the replay failed, before the subscriber is handed back
+	// to the dispatcher
+	replayFailed bool
+	// left is set once the subscriber's stream ended, so it is not registered again
+	left bool
+	// lastSequence is the sequence of the last event sent to the subscriber
+	lastSequence atomic.Uint64
+	// kicked is set when an operator disconnected the subscriber
+	kicked atomic.Bool
+}
+
+// stop ends the subscriber's stream. It is safe to call more than once.
+func (sub *grpcSubscriber) stop() {
+	sub.stopOnce.Do(func() { close(sub.done) })
 }
 
 var grpcSubscribers sync.Map // map[string]*grpcSubscriber
 
 func (s *Server) CDCStream(req *v1.CDCSubscriptionRequest, stream v1.CDCService_CDCStreamServer) error {
 	sub := &grpcSubscriber{
-		id:     req.GetClientId(),
-		stream: stream,
-		done:   make(chan struct{}),
+		id:          req.GetClientId(),
+		stream:      stream,
+		done:        make(chan struct{}),
+		connectedAt: time.Now(),
+		replay:      req.GetReplay(),
+		replayFrom:  req.GetResumeFromUnix(),
+		joined:      make(chan struct{}),
 	}
 
 	grpcSubscribers.Store(sub.id, sub)
-	s.registerGRPCStream(sub.id, sub.stream)
 
 	// Monitor for cancellation
 	ctx := stream.Context()
 
+	if sub.replay {
+		// the dispatcher registers the subscriber between two events and buffers the events
+		// that follow while the spilled ones are replayed here, so the spill is read without
+		// holding up the other subscribers, and the subscriber misses none and receives them in
+		// order
+		select {
+		case s.joins <- sub:
+			s.replay(sub)
+		case <-ctx.Done():
+		case <-sub.done:
+		}
+	} else {
+		s.registerGRPCStream(sub)
+	}
+
 	select {
 	case <-ctx.Done(): // client closed the stream
 	case <-sub.done: // server signaled shutdown
 	}
 
-	grpcSubscribers.Delete(sub.id)
-	s.unregisterGRPCStream(sub.id)
+	grpcSubscribers.CompareAndDelete(sub.id, sub)
+	s.unregisterGRPCStream(sub)
+	if sub.kicked.Load() {
+		return status.Errorf(codes.Aborted, "disconnected by an operator")
+	}
 	return nil
 }
 
-func (s *Server) registerGRPCStream(clientID string, stream v1.CDCService_CDCStreamServer) {
+func (s *Server) registerGRPCStream(sub *grpcSubscriber) {
 	s.grpcMux.Lock()
 	defer s.grpcMux.Unlock()
+	if sub.left {
+		return
+	}
 	if s.grpcStreams == nil {
-		s.grpcStreams = make(map[string]v1.CDCService_CDCStreamServer)
+		s.grpcStreams = make(map[string]*grpcSubscriber)
 	}
-	s.grpcStreams[clientID] = stream
-	log.Debug().Str("client-id", clientID).Msg("registered gRPC stream")
+	s.grpcStreams[sub.id] = sub
+	s.logger.Debug().Str("client-id", sub.id).Msg("registered gRPC stream")
 }
 
-func (s *Server) unregisterGRPCStream(clientID string) {
+// unregisterGRPCStream removes a subscriber, unless a newer one with the same client id has
+// taken its place.
+func (s *Server) unregisterGRPCStream(sub *grpcSubscriber) {
 	s.grpcMux.Lock()
 	defer s.grpcMux.Unlock()
-	delete(s.grpcStreams, clientID)
-	log.Debug().Str("client-id", clientID).Msg("unregistered gRPC stream")
+	sub.left = true
+	if s.grpcStreams[sub.id] == sub {
+		delete(s.grpcStreams, sub.id)
+	}
+	s.logger.Debug().Str("client-id", sub.id).Msg("unregistered gRPC stream")
 }
 
+// Start listens for CDC subscribers. It may be called again after Reset, following a failure
+// reported on Failed; the dispatch loop is only started once.
 func (s *Server) Start() error {
+	s.spillMux.Lock()
+	if s.spill == nil && s.spillDir != "" {
+		spill, err := openSpill(s.spillDir, s.spillMaxBytes)
+		if err != nil {
+			s.spillMux.Unlock()
+			return err
+		}
+		s.spill = spill
+	}
+	s.spillMux.Unlock()
+
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.address, s.port))
 	if err != nil {
 		return fmt.Errorf("failed to listen on port %d: %w", s.port, err)
 	}
 
-	log.Info().Msgf("CDC gRPC server listening at %s:%d", s.address, s.port)
+	s.logger.Info().Msgf("CDC gRPC server listening at %s:%d", s.address, s.port)
 
 	// Start fan-out dispatcher
-	s.eventWg.Add(1)
-	go s.dispatchLoop()
+	s.dispatchOnce.Do(func() {
+		s.eventWg.Add(1)
+		go s.dispatchLoop()
+	})
 
 	// Start gRPC server
+	s.serverMux.Lock()
+	srv := s.server
+	s.serverMux.Unlock()
 	go func() {
-		if err := s.server.Serve(lis); err != nil {
-			log.Error().Err(err).Msg("CDC gRPC server failed")
+		if err := srv.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.logger.Error().Err(err).Msg("CDC gRPC server failed")
+			select {
+			case s.failed <- err:
+			default: // a failure is already pending
+			}
 		}
 	}()
 
 	return nil
 }
+
+// Failed reports errors that stop the server from accepting subscribers after Start.
+func (s *Server) Failed() <-chan error {
+	return s.failed
+}
+
+// Reset stops serving the subscribers of a failed Start so it can be called again. The
+// subscribers are disconnected to reconnect once it has, while the events, the watches and the
+// spill are kept; only Stop ends those.
+func (s *Server) Reset() error {
+	s.serverMux.Lock()
+	defer s.serverMux.Unlock()
+	s.server.Stop()
+	s.server = s.newGRPCServer()
+	return nil
+}
+
+// Stop ends the CDC stream for good: it disconnects the subscribers, dispatches the queued
+// events and ends the watches. The server cannot be started again.
 func (s *Server) Stop() error {
 	s.stopOnce.Do(func() {
 		// Step 1: Notify all subscriber goroutines to exit
 		grpcSubscribers.Range(func(key, value any) bool {
 			if sub, ok := value.(*grpcSubscriber); ok {
-				select {
-				case <-sub.done:
-					// already closed
-				default:
-					close(sub.done)
-				}
+				sub.stop()
 			}
 			return true
 		})
 
 		// Gracefully stop gRPC server (blocks until in-flight RPCs complete)
-		if s.server != nil {
-			s.server.GracefulStop()
-		}
+		s.serverMux.Lock()
+		s.server.GracefulStop()
+		s.serverMux.Unlock()
 
 		// Close the event channel so dispatchLoop exits
 		close(s.events)
 
 		// Wait for dispatchLoop to finish processing
 		s.eventWg.Wait()
+
+		// no more events will be dispatched, so end the watches
+		s.closeWatches()
+
+		s.spillMux.Lock()
+		if s.spill != nil {
+			if err := s.spill.close(); err != nil {
+				s.logger.Warn().Err(err).Msg("failed to close CDC spill file")
+			}
+			s.spill = nil
+		}
+		s.spillMux.Unlock()
 	})
 	return nil
 }
@@ -149,42 +352,224 @@ func (s *Server) Name() string {
 	return "CDC Stream"
 }
 
+// Addr returns the address the CDC stream is served on.
+func (s *Server) Addr() string {
+	return fmt.Sprintf("%s:%d", s.address, s.port)
+}
+
 func (s *Server) dispatchLoop() {
 	defer s.eventWg.Done()
-	for evt := range s.events {
-		// TODO: verify litetable configuration before sending events
-		// if disabled, just discard the event
-
-		// TODO: support backing up events to a file
-		s.grpcMux.Lock()
-		for id, stream := range s.grpcStreams {
-			event := &v1.CDCEvent{
-				RowKey:        evt.RowKey,
-				Family:        evt.Family,
-				Qualifier:     evt.Qualifier,
-				Value:         evt.Value,
-				TimestampUnix: evt.Timestamp,
-				Tombstone:     evt.IsTombstone,
-				ExpiresAtUnix: evt.ExpiresAt,
+	for {
+		select {
+		case evt, ok := <-s.events:
+			if !ok {
+				s.logger.Debug().Msg("event dispatch loop exited")
+				return
 			}
+			queueDepth.Set(float64(len(s.events)))
+			s.dispatch(evt)
+		case sub := <-s.joins:
+			s.join(sub)
+		case sub := <-s.caughtUp:
+			s.goLive(sub)
+		}
+	}
+}
 
-			switch evt.Operation {
-			case litetable.OperationRead:
-				event.Operation = v1.LitetableOperation_READ
-			case litetable.OperationWrite:
-				event.Operation = v1.LitetableOperation_WRITE
-			case litetable.OperationDelete:
-				event.Operation = v1.LitetableOperation_DELETE
+// dispatch sends an event to every stream and watch. An event a stream missed, because none was
+// connected or a send failed, is spilled for replay.
+func (s *Server) dispatch(evt *CDCEvent) {
+	evt.Sequence = s.sequence.Add(1)
+	evt.NodeID = s.nodeID
+	event := toProto(evt)
+
+	// the lock is not held while sending, so a subscriber stuck in Send can still be kicked,
+	// which ends its stream and fails the Send
+	sent, failed := 0, false
+	for _, sub := range s.streamSubscribers() {
+		select {
+		case <-sub.done:
+			continue
+		default:
+		}
+		if sub.pending != nil {
+			// the subscriber is replaying the spill, so the event waits for it
+			select {
+			case sub.pending <- evt:
+				sent++
+			default:
+				s.logger.Warn().Str("client", sub.id).
+					Msg("removing gRPC stream that fell behind while replaying")
+				s.abandon(sub)
+				failed = true
 			}
+			continue
+		}
+		if err := sub.stream.Send(event); err != nil {
+			s.logger.Warn().Err(err).Str("client", sub.id).
+				Msg("removing gRPC stream due to send error")
+			s.unregisterGRPCStream(sub)
+			failed = true
+			continue
+		}
+		sub.lastSequence.Store(evt.Sequence)
+		sent++
+	}
+	if sent == 0 || failed {
+		s.spillEvent(evt)
+	}
 
-			err := stream.Send(event)
-			if err != nil {
-				log.Warn().Err(err).Str("client", id).Msg("removing gRPC stream due to send error")
-				delete(s.grpcStreams, id)
+	s.notifyWatches(evt)
+}
+
+// join registers a subscriber that asked for a replay between two events. The events
+// dispatched from then on are buffered for it, as many as the event queue holds, while it replays
+// the spilled events on its own goroutine.
+func (s *Server) join(sub *grpcSubscriber) {
+	sub.joinedAt = s.sequence.Load()
+	sub.pending = make(chan *CDCEvent, cap(s.events))
+	s.registerGRPCStream(sub)
+	close(sub.joined)
+}
+
+// replay sends a subscriber the spilled events it asked for that were dispatched before it
+// joined, then the events buffered for it since, and hands it back to the dispatcher, which
+// abandons it if a send failed.
+func (s *Server) replay(sub *grpcSubscriber) {
+	<-sub.joined
+	pending := sub.pending
+
+	s.spillMux.Lock()
+	var events []*CDCEvent
+	var err error
+	if s.spill != nil {
+		events, err = s.spill.read(sub.replayFrom)
+	}
+	s.spillMux.Unlock()
+	if err != nil {
+		s.logger.Warn().Err(err).Str("client", sub.id).Msg("failed to read CDC spill file")
+	}
+
+	replayed := 0
+	for _, evt := range events {
+		// an event spilled after the subscriber joined is buffered for it as well
+		if evt.Sequence > sub.joinedAt {
+			continue
+		}
+		select {
+		case <-sub.done:
+			return
+		default:
+		}
+		if err = sub.stream.Send(toProto(evt)); err != nil {
+			s.logger.Warn().Err(err).Str("client", sub.id).Msg("failed to replay CDC events")
+			sub.replayFailed = true
+			break
+		}
+		replayed++
+	}
+	s.logger.Debug().Str("client", sub.id).Int("events", replayed).
+		Msg("replayed spilled CDC events")
+
+drain:
+	for !sub.replayFailed {
+		select {
+		case evt := <-pending:
+			if err = sub.stream.Send(toProto(evt)); err != nil {
+				s.logger.Warn().Err(err).Str("client", sub.id).
+					Msg("failed to send the CDC events buffered during a replay")
+				s.spillEvent(evt)
+				sub.replayFailed = true
+				continue
 			}
+			sub.lastSequence.Store(evt.Sequence)
+		case <-sub.done:
+			return
+		default:
+			break drain
 		}
-		s.grpcMux.Unlock()
 	}
 
-	log.Debug().Msg("event dispatch loop exited")
+	select {
+	case s.caughtUp <- sub:
+	case <-sub.done:
+	}
+}
+
+// goLive sends a subscriber that replayed the spill the events buffered for it since it drained
+// them, then sends it events as they are dispatched.
+func (s *Server) goLive(sub *grpcSubscriber) {
+	if sub.replayFailed {
+		s.abandon(sub)
+		return
+	}
+	for {
+		select {
+		case evt := <-sub.pending:
+			if err := sub.stream.Send(toProto(evt)); err != nil {
+				s.logger.Warn().Err(err).Str("client", sub.id).
+					Msg("removing gRPC stream due to send error")
+				s.spillEvent(evt)
+				s.abandon(sub)
+				return
+			}
+			sub.lastSequence.Store(evt.Sequence)
+		default:
+			sub.pending = nil
+			return
+		}
+	}
+}
+
+// abandon stops a replaying subscriber whose send failed or that fell behind, spilling the
+// events still buffered for it so it can replay them when it returns. It runs on the dispatcher,
+// so no event is buffered for the subscriber after.
+func (s *Server) abandon(sub *grpcSubscriber) {
+	s.unregisterGRPCStream(sub)
+	sub.stop()
+	for {
+		select {
+		case evt := <-sub.pending:
+			s.spillEvent(evt)
+		default:
+			return
+		}
+	}
+}
+
+// spillEvent keeps an event for replay, reporting whether it was kept.
+func (s *Server) spillEvent(evt *CDCEvent) bool {
+	s.spillMux.Lock()
+	defer s.spillMux.Unlock()
+	if s.spill == nil {
+		return false
+	}
+	if err := s.spill.write(evt); err != nil {
+		s.logger.Warn().Err(err).Msg("failed to spill CDC event")
+		return false
+	}
+	return true
+}
+
+// toProto converts an event to the message sent to stream subscribers.
+func toProto(evt *CDCEvent) *v1.CDCEvent {
+	event := &v1.CDCEvent{
+		RowKey:        evt.RowKey,
+		Family:        evt.Family,
+		Qualifier:     evt.Qualifier,
+		Value:         evt.Value,
+		TimestampUnix: evt.Timestamp,
+		Tombstone:     evt.IsTombstone,
+		ExpiresAtUnix: evt.ExpiresAt,
+	}
+
+	switch evt.Operation {
+	case litetable.OperationRead:
+		event.Operation = v1.LitetableOperation_READ
+	case litetable.OperationWrite:
+		event.Operation = v1.LitetableOperation_WRITE
+	case litetable.OperationDelete:
+		event.Operation = v1.LitetableOperation_DELETE
+	}
+	return event
 }
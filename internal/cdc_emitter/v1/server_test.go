@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"context"
+	v1 "github.com/litetable/litetable-cdc/go/v1"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"net"
+	"testing"
+	"time"
+)
+
+// connect subscribes to the CDC stream over gRPC and waits until the server registered it.
+func connect(t *testing.T, s *Server, id string) v1.CDCService_CDCStreamClient {
+	conn, err := grpc.NewClient(s.Addr(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	stream, err := v1.NewCDCServiceClient(conn).CDCStream(context.Background(),
+		&v1.CDCSubscriptionRequest{ClientId: id})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		s.grpcMux.Lock()
+		defer s.grpcMux.Unlock()
+		return s.grpcStreams[id] != nil
+	}, time.Second, time.Millisecond)
+	return stream
+}
+
+func TestServer_Reset(t *testing.T) {
+	req := require.New(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	req.NoError(err)
+	port := lis.Addr().(*net.TCPAddr).Port
+	req.NoError(lis.Close())
+
+	s := New(&Config{Port: port})
+	req.NoError(s.Start())
+	before := connect(t, s, "indexer")
+
+	// a reset ends the stream, and the restarted server serves and dispatches again
+	req.NoError(s.Reset())
+	_, err = before.Recv()
+	req.Error(err)
+	req.NoError(s.Start())
+	after := connect(t, s, "indexer")
+
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:1"})
+	evt, err := after.Recv()
+	req.NoError(err)
+	req.Equal("champ:1", evt.GetRowKey())
+
+	req.NoError(s.Stop())
+}
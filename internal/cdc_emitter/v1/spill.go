@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// defaultSpillMaxBytes bounds the spill file when the config does not
+	defaultSpillMaxBytes = 64 << 20
+	spillFile            = "cdc_spill.jsonl"
+)
+
+// spill is a bounded on-disk buffer of the events a stream subscriber missed, because none was
+// connected or a send failed, for subscribers to replay when they return. Events are kept as
+// JSON lines in two segments: once the current one reaches half of maxBytes it replaces the
+// previous one, so the oldest events are dropped first. Only the dispatcher writes it, and
+// writes are not synced, so a crash may lose the latest events.
+type spill struct {
+	path     string
+	maxBytes int64
+	file     *os.File
+	size     int64
+}
+
+// openSpill opens the spill file in dir, keeping the events spilled before a restart.
+func openSpill(dir string, maxBytes int64) (*spill, error) {
+	if maxBytes == 0 {
+		maxBytes = defaultSpillMaxBytes
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create CDC spill directory: %w", err)
+	}
+
+	s := &spill{path: filepath.Join(dir, spillFile), maxBytes: maxBytes}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CDC spill file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to stat CDC spill file: %w", err)
+	}
+	s.file, s.size = file, info.Size()
+	return s, nil
+}
+
+// write appends an event, rotating the segments once the current one is full.
+func (s *spill) write(evt *CDCEvent) error {
+	line, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to encode CDC event: %w", err)
+	}
+	line = append(line, '\n')
+
+	if s.size > 0 && s.size+int64(len(line)) > s.maxBytes/2 {
+		if err = s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write CDC spill file: %w", err)
+	}
+	return nil
+}
+
+// rotate replaces the previous segment with the current one and starts a new one.
+func (s *spill) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close CDC spill file: %w", err)
+	}
+	if err := os.Rename(s.path, s.path+".old"); err != nil {
+		return fmt.Errorf("failed to rotate CDC spill file: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open CDC spill file: %w", err)
+	}
+	s.file, s.size = file, 0
+	return nil
+}
+
+// read returns the spilled events with a timestamp at or after from, oldest first.
+func (s *spill) read(from int64) ([]*CDCEvent, error) {
+	var events []*CDCEvent
+	for _, path := range []string{s.path + ".old", s.path} {
+		file, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open CDC spill file: %w", err)
+		}
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(nil, int(s.maxBytes))
+		for scanner.Scan() {
+			evt := &CDCEvent{}
+			if err = json.Unmarshal(scanner.Bytes(), evt); err != nil {
+				// a line cut short by a crash is skipped
+				continue
+			}
+			if evt.Timestamp >= from {
+				events = append(events, evt)
+			}
+		}
+		err = scanner.Err()
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CDC spill file: %w", err)
+		}
+	}
+	return events, nil
+}
+
+func (s *spill) close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	v1 "github.com/litetable/litetable-cdc/go/v1"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpill(t *testing.T) {
+	req := require.New(t)
+	dir := t.TempDir()
+	s, err := openSpill(dir, 0)
+	req.NoError(err)
+	req.Equal(int64(defaultSpillMaxBytes), s.maxBytes)
+
+	for i := range 3 {
+		req.NoError(s.write(&CDCEvent{
+			Operation: litetable.OperationWrite,
+			RowKey:    fmt.Sprintf("champ:%d", i),
+			Timestamp: int64(i),
+		}))
+	}
+	events, err := s.read(1)
+	req.NoError(err)
+	req.Len(events, 2)
+	req.Equal("champ:1", events[0].RowKey)
+	req.Equal(litetable.OperationWrite, events[0].Operation)
+	req.NoError(s.close())
+
+	// the events outlive a restart, and a line cut short by a crash is skipped
+	f, err := os.OpenFile(filepath.Join(dir, spillFile), os.O_WRONLY|os.O_APPEND, 0644)
+	req.NoError(err)
+	_, err = f.WriteString(`{"key":"champ:`)
+	req.NoError(err)
+	req.NoError(f.Close())
+
+	s, err = openSpill(dir, 0)
+	req.NoError(err)
+	events, err = s.read(0)
+	req.NoError(err)
+	req.Len(events, 3)
+	req.NoError(s.close())
+}
+
+func TestSpill_bounded(t *testing.T) {
+	req := require.New(t)
+	s, err := openSpill(t.TempDir(), 1024)
+	req.NoError(err)
+	t.Cleanup(func() { _ = s.close() })
+
+	for i := range 100 {
+		req.NoError(s.write(&CDCEvent{RowKey: fmt.Sprintf("champ:%d", i), Timestamp: int64(i)}))
+	}
+
+	// only the newest events are kept, within the bound
+	events, err := s.read(0)
+	req.NoError(err)
+	req.NotEmpty(events)
+	req.Less(len(events), 100)
+	req.Equal("champ:99", events[len(events)-1].RowKey)
+	for i := 1; i < len(events); i++ {
+		req.Equal(events[i-1].Timestamp+1, events[i].Timestamp)
+	}
+
+	var size int64
+	for _, path := range []string{s.path, s.path + ".old"} {
+		info, err := os.Stat(path)
+		req.NoError(err)
+		size += info.Size()
+	}
+	req.LessOrEqual(size, int64(1024))
+}
+
+// failingStream fails every send, as a stream whose client went away does.
+type failingStream struct {
+	*testStream
+}
+
+func (failingStream) Send(*v1.CDCEvent) error {
+	return errors.New("connection reset")
+}
+
+func TestServer_spill(t *testing.T) {
+	req := require.New(t)
+	s := New(&Config{SpillDir: t.TempDir()})
+	spill, err := openSpill(s.spillDir, s.spillMaxBytes)
+	req.NoError(err)
+	s.spill = spill
+	startDispatch(t, s)
+
+	// events no stream receives are spilled
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:1", Timestamp: 1})
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:2", Timestamp: 2})
+	req.Eventually(func() bool { return s.Sequence() == 2 }, time.Second, time.Millisecond)
+
+	// so are the events a send fails for, even when another stream receives them
+	healthy := newTestStream(false)
+	subscribe(t, s, "healthy", healthy)
+	failing := failingStream{newTestStream(false)}
+	ended := make(chan error, 1)
+	go func() {
+		ended <- s.CDCStream(&v1.CDCSubscriptionRequest{ClientId: "failing"}, failing)
+	}()
+	req.Eventually(func() bool { return len(s.Subscribers()) == 2 }, time.Second, time.Millisecond)
+	s.Emit(&CDCEvent{Operation: litetable.OperationDelete, RowKey: "champ:3", Timestamp: 3})
+	req.Equal("champ:3", (<-healthy.events).GetRowKey())
+	failing.cancel()
+	req.NoError(<-ended)
+
+	// events every stream receives are not
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:4", Timestamp: 4})
+	req.Equal("champ:4", (<-healthy.events).GetRowKey())
+
+	// a replay sends the spilled events from resume_from_unix on, then the live ones
+	replayed := newTestStream(false)
+	go func() {
+		_ = s.CDCStream(&v1.CDCSubscriptionRequest{
+			ClientId: "replayed", Replay: true, ResumeFromUnix: 2,
+		}, replayed)
+	}()
+	req.Equal("champ:2", (<-replayed.events).GetRowKey())
+	evt := <-replayed.events
+	req.Equal("champ:3", evt.GetRowKey())
+	req.Equal(v1.LitetableOperation_DELETE, evt.GetOperation())
+
+	req.Eventually(func() bool { return len(s.Subscribers()) == 2 }, time.Second, time.Millisecond)
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:5", Timestamp: 5})
+	req.Equal("champ:5", (<-replayed.events).GetRowKey())
+	req.Empty(replayed.events)
+	replayed.cancel()
+}
+
+func TestServer_replay_withoutSpill(t *testing.T) {
+	req := require.New(t)
+	s := New(&Config{})
+	startDispatch(t, s)
+
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:1"})
+	req.Eventually(func() bool { return s.Sequence() == 1 }, time.Second, time.Millisecond)
+	stream := newTestStream(false)
+	go func() {
+		_ = s.CDCStream(&v1.CDCSubscriptionRequest{ClientId: "late", Replay: true}, stream)
+	}()
+	req.Eventually(func() bool { return len(s.Subscribers()) == 1 }, time.Second, time.Millisecond)
+
+	// without a spill directory, nothing is kept to replay
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:2"})
+	req.Equal("champ:2", (<-stream.events).GetRowKey())
+	stream.cancel()
+}
+
+func TestServer_replay_doesNotHoldUpDispatch(t *testing.T) {
+	req := require.New(t)
+	s := New(&Config{SpillDir: t.TempDir()})
+	spill, err := openSpill(s.spillDir, s.spillMaxBytes)
+	req.NoError(err)
+	s.spill = spill
+	startDispatch(t, s)
+
+	for i := range 20 {
+		s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: fmt.Sprintf("champ:%d", i)})
+	}
+	req.Eventually(func() bool { return s.Sequence() == 20 }, time.Second, time.Millisecond)
+
+	// the replay fills the stream and waits for its client to read, while the events that
+	// follow still reach the other streams
+	replayed := newTestStream(false)
+	go func() {
+		_ = s.CDCStream(&v1.CDCSubscriptionRequest{ClientId: "replayed", Replay: true}, replayed)
+	}()
+	req.Eventually(func() bool { return len(replayed.events) == cap(replayed.events) },
+		time.Second, time.Millisecond)
+	healthy := newTestStream(false)
+	subscribe(t, s, "healthy", healthy)
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:20"})
+	req.Equal("champ:20", (<-healthy.events).GetRowKey())
+
+	// the replayed stream is sent every event once, in order
+	for i := range 21 {
+		req.Equal(fmt.Sprintf("champ:%d", i), (<-replayed.events).GetRowKey())
+	}
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:21"})
+	req.Equal("champ:21", (<-replayed.events).GetRowKey())
+	req.Empty(replayed.events)
+	replayed.cancel()
+	healthy.cancel()
+}
@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The kinds of subscriber.
+const (
+	// SubscriberStream is a CDCStream client, which is sent every change
+	SubscriberStream = "stream"
+	// SubscriberWatch is a Watch, which is sent the changes to a row or key prefix
+	SubscriberWatch = "watch"
+)
+
+// Subscriber describes a client of the change stream, so operators can find slow or stuck ones.
+type Subscriber struct {
+	// ID is the client id of a stream, or the id the server gave a watch
+	ID   string
+	Kind string
+	// RowKey and Prefix are the rows a watch is filtered to. Both are empty for a stream.
+	RowKey string
+	Prefix string
+	// LastSequence is the sequence of the last event sent to the subscriber, 0 before the first
+	LastSequence uint64
+	// QueueDepth is the number of events waiting to be sent to the subscriber. Streams are sent
+	// each event in turn, so theirs is the queue of events not yet dispatched, which they share.
+	QueueDepth  int
+	ConnectedAt time.Time
+}
+
+// Subscribers lists the connected stream clients, then the watches, each by id.
+func (s *Server) Subscribers() []Subscriber {
+	var subscribers []Subscriber
+	pending := len(s.events)
+	for _, sub := range s.streamSubscribers() {
+		subscribers = append(subscribers, Subscriber{
+			ID:           sub.id,
+			Kind:         SubscriberStream,
+			LastSequence: sub.lastSequence.Load(),
+			QueueDepth:   pending,
+			ConnectedAt:  sub.connectedAt,
+		})
+	}
+
+	s.watchMux.Lock()
+	var watches []Subscriber
+	for w := range s.watches {
+		watches = append(watches, Subscriber{
+			ID:           w.id,
+			Kind:         SubscriberWatch,
+			RowKey:       w.rowKey,
+			Prefix:       w.prefix,
+			LastSequence: w.lastSequence.Load(),
+			QueueDepth:   len(w.events),
+			ConnectedAt:  w.connectedAt,
+		})
+	}
+	s.watchMux.Unlock()
+	slices.SortFunc(watches, func(a, b Subscriber) int {
+		// watch ids are numbered, so shorter ones come first
+		if len(a.ID) != len(b.ID) {
+			return len(a.ID) - len(b.ID)
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return append(subscribers, watches...)
+}
+
+// Sequence returns the sequence of the last event dispatched to the subscribers. A stream whose
+// last sequence is behind it has not been sent every change yet.
+func (s *Server) Sequence() uint64 {
+	return s.sequence.Load()
+}
+
+// Kick disconnects a subscriber. A stream ends with ABORTED, which also fails a send it is
+// stuck in, and a watch reports Kicked. Either may reconnect.
+func (s *Server) Kick(id string) error {
+	s.grpcMux.Lock()
+	sub, ok := s.grpcStreams[id]
+	s.grpcMux.Unlock()
+	if ok {
+		sub.kicked.Store(true)
+		sub.stop()
+		s.logger.Info().Str("client-id", id).Msg("kicked CDC subscriber")
+		return nil
+	}
+
+	s.watchMux.Lock()
+	defer s.watchMux.Unlock()
+	for w := range s.watches {
+		if w.id == id {
+			w.kicked = true
+			delete(s.watches, w)
+			close(w.events)
+			s.logger.Info().Str("watch-id", id).Msg("kicked watch")
+			return nil
+		}
+	}
+	return litetable.NewError(litetable.ErrorCodeNotFound, "no CDC subscriber %s", id)
+}
+
+// streamSubscribers returns the connected stream clients by client id.
+func (s *Server) streamSubscribers() []*grpcSubscriber {
+	s.grpcMux.Lock()
+	defer s.grpcMux.Unlock()
+	subs := make([]*grpcSubscriber, 0, len(s.grpcStreams))
+	for _, sub := range s.grpcStreams {
+		subs = append(subs, sub)
+	}
+	slices.SortFunc(subs, func(a, b *grpcSubscriber) int { return strings.Compare(a.id, b.id) })
+	return subs
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"context"
+	v1 "github.com/litetable/litetable-cdc/go/v1"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+// testStream is a CDC stream client. A stuck stream blocks in Send until its context is done,
+// as a client that stopped reading does.
+type testStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	stuck  bool
+	events chan *v1.CDCEvent
+}
+
+func newTestStream(stuck bool) *testStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &testStream{ctx: ctx, cancel: cancel, stuck: stuck, events: make(chan *v1.CDCEvent, 10)}
+}
+
+func (t *testStream) Context() context.Context {
+	return t.ctx
+}
+
+func (t *testStream) Send(evt *v1.CDCEvent) error {
+	if t.stuck {
+		<-t.ctx.Done()
+		return t.ctx.Err()
+	}
+	t.events <- evt
+	return nil
+}
+
+// subscribe connects a stream client and returns the error its stream ends with. Like gRPC,
+// the stream's context is cancelled once the handler returns.
+func subscribe(t *testing.T, s *Server, id string, stream *testStream) <-chan error {
+	ended := make(chan error, 1)
+	go func() {
+		ended <- s.CDCStream(&v1.CDCSubscriptionRequest{ClientId: id}, stream)
+		stream.cancel()
+	}()
+	require.Eventually(t, func() bool {
+		s.grpcMux.Lock()
+		defer s.grpcMux.Unlock()
+		return s.grpcStreams[id] != nil
+	}, time.Second, time.Millisecond)
+	return ended
+}
+
+// startDispatch dispatches events until the server stops.
+func startDispatch(t *testing.T, s *Server) {
+	s.eventWg.Add(1)
+	go s.dispatchLoop()
+	t.Cleanup(func() { _ = s.Stop() })
+}
+
+func TestServer_Subscribers(t *testing.T) {
+	req := require.New(t)
+	s := New(&Config{})
+	startDispatch(t, s)
+
+	stream := newTestStream(false)
+	subscribe(t, s, "indexer", stream)
+	w, err := s.Watch("", "champ:")
+	req.NoError(err)
+
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:1"})
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:2"})
+	<-stream.events
+	<-stream.events
+	w.Sent(<-w.Events())
+
+	req.Eventually(func() bool { return s.Sequence() == 2 }, time.Second, time.Millisecond)
+	subscribers := s.Subscribers()
+	req.Len(subscribers, 2)
+	req.Equal("indexer", subscribers[0].ID)
+	req.Equal(SubscriberStream, subscribers[0].Kind)
+	req.Equal(uint64(2), subscribers[0].LastSequence)
+	req.Empty(subscribers[0].Prefix)
+	req.Equal("watch-1", subscribers[1].ID)
+	req.Equal(SubscriberWatch, subscribers[1].Kind)
+	req.Equal("champ:", subscribers[1].Prefix)
+	req.Equal(uint64(1), subscribers[1].LastSequence)
+	req.Equal(1, subscribers[1].QueueDepth)
+	req.False(subscribers[1].ConnectedAt.IsZero())
+}
+
+func TestServer_Kick(t *testing.T) {
+	req := require.New(t)
+	s := New(&Config{})
+	startDispatch(t, s)
+
+	stuck := newTestStream(true)
+	stuckEnded := subscribe(t, s, "stuck", stuck)
+	healthy := newTestStream(false)
+	subscribe(t, s, "healthy", healthy)
+
+	// the healthy stream waits on the stuck one until it is kicked
+	s.Emit(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:1"})
+	req.NoError(s.Kick("stuck"))
+	req.Equal(codes.Aborted, status.Code(<-stuckEnded))
+	<-healthy.events
+	req.Eventually(func() bool { return len(s.Subscribers()) == 1 }, time.Second, time.Millisecond)
+	req.Equal("healthy", s.Subscribers()[0].ID)
+
+	w, err := s.Watch("champ:1", "")
+	req.NoError(err)
+	req.NoError(s.Kick("watch-1"))
+	_, open := <-w.Events()
+	req.False(open)
+	req.True(w.Kicked())
+	req.False(w.Overflowed())
+
+	req.ErrorIs(s.Kick("watch-1"), litetable.ErrNotFound)
+}
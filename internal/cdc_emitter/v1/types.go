@@ -13,4 +13,17 @@ type CDCEvent struct {
 	Timestamp   int64               `json:"timestamp"`
 	IsTombstone bool                `json:"isTombstone"`
 	ExpiresAt   int64               `json:"expiresAt"`
+	// Sequence numbers the event in the order the CDC server dispatches events
+	Sequence uint64 `json:"sequence"`
+	// Table is the table the change was made to
+	Table string `json:"table"`
+	// TableSequence is the sequence number of the mutation in its table, which reads take as
+	// read_at. Unlike Sequence it survives restarts, and the cells of one mutation share it.
+	TableSequence uint64 `json:"tableSequence"`
+	// NodeID names the server the change was made on
+	NodeID string `json:"nodeId"`
+	// OldValue is the latest value of the cell before the change, when the storage is set up to
+	// report it and the cell had one that was not a tombstone
+	OldValue    []byte `json:"oldValue,omitempty"`
+	HasOldValue bool   `json:"hasOldValue,omitempty"`
 }
@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// watchBuffer is the number of events a watch holds for a slow client before it is closed.
+const watchBuffer = 256
+
+// Watch receives the changes to one row or to every row with a key prefix.
+type Watch struct {
+	id          string
+	rowKey      string
+	prefix      string
+	events      chan *CDCEvent
+	connectedAt time.Time
+	// lastSequence is the sequence of the last event sent to the client
+	lastSequence atomic.Uint64
+	// overflowed is set when the watch was closed because the client fell behind
+	overflowed bool
+	// kicked is set when an operator ended the watch
+	kicked bool
+}
+
+// Events delivers the changes to the watched rows. It is closed when the watch is cancelled,
+// the server stops, or the client falls behind.
+func (w *Watch) Events() <-chan *CDCEvent {
+	return w.events
+}
+
+// Overflowed reports whether the watch was closed because the client fell behind, in which case
+// changes were missed. Only read it once Events is closed.
+func (w *Watch) Overflowed() bool {
+	return w.overflowed
+}
+
+// Kicked reports whether the watch was ended by an operator. Only read it once Events is closed.
+func (w *Watch) Kicked() bool {
+	return w.kicked
+}
+
+// Sent records that an event was sent to the client, for the subscriber list.
+func (w *Watch) Sent(evt *CDCEvent) {
+	w.lastSequence.Store(evt.Sequence)
+}
+
+func (w *Watch) matches(evt *CDCEvent) bool {
+	if w.rowKey != "" {
+		return evt.RowKey == w.rowKey
+	}
+	return strings.HasPrefix(evt.RowKey, w.prefix)
+}
+
+// Watch subscribes to the writes and deletes of a row, or of every row starting with prefix when
+// rowKey is empty. Call Unwatch once done.
+func (s *Server) Watch(rowKey, prefix string) (*Watch, error) {
+	if (rowKey == "") == (prefix == "") {
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"watch needs either a row key or a prefix")
+	}
+
+	w := &Watch{
+		rowKey:      rowKey,
+		prefix:      prefix,
+		events:      make(chan *CDCEvent, watchBuffer),
+		connectedAt: time.Now(),
+	}
+
+	s.watchMux.Lock()
+	defer s.watchMux.Unlock()
+	s.watchIDs++
+	w.id = fmt.Sprintf("watch-%d", s.watchIDs)
+	if s.watchesClosed {
+		// the dispatcher has stopped, so the watch ends right away
+		close(w.events)
+		return w, nil
+	}
+	s.watches[w] = struct{}{}
+	return w, nil
+}
+
+// Unwatch cancels a watch. It is safe to call more than once.
+func (s *Server) Unwatch(w *Watch) {
+	s.watchMux.Lock()
+	defer s.watchMux.Unlock()
+	if _, ok := s.watches[w]; ok {
+		delete(s.watches, w)
+		close(w.events)
+	}
+}
+
+// notifyWatches hands an event to every watch on its row. A watch that is full is closed rather
+// than blocking the dispatcher, and its client must re-read the rows it watches.
+func (s *Server) notifyWatches(evt *CDCEvent) {
+	s.watchMux.Lock()
+	defer s.watchMux.Unlock()
+	for w := range s.watches {
+		if !w.matches(evt) {
+			continue
+		}
+		select {
+		case w.events <- evt:
+		default:
+			s.logger.Warn().Str("row-key", w.rowKey).Str("prefix", w.prefix).
+				Msg("closing watch that fell behind")
+			w.overflowed = true
+			delete(s.watches, w)
+			close(w.events)
+		}
+	}
+}
+
+// closeWatches ends every watch once the dispatcher stops.
+func (s *Server) closeWatches() {
+	s.watchMux.Lock()
+	defer s.watchMux.Unlock()
+	for w := range s.watches {
+		close(w.events)
+	}
+	s.watches = make(map[*Watch]struct{})
+	s.watchesClosed = true
+}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestServer_Watch(t *testing.T) {
+	tests := map[string]struct {
+		rowKey, prefix string
+		expected       []string
+		expectErr      error
+	}{
+		"row key": {
+			rowKey:   "champ:1",
+			expected: []string{"champ:1"},
+		},
+		"prefix": {
+			prefix:   "champ:",
+			expected: []string{"champ:1", "champ:10", "champ:2"},
+		},
+		"neither": {
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"both": {
+			rowKey:    "champ:1",
+			prefix:    "champ:",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			s := New(&Config{})
+
+			w, err := s.Watch(tc.rowKey, tc.prefix)
+			if tc.expectErr != nil {
+				req.ErrorIs(err, tc.expectErr)
+				return
+			}
+			req.NoError(err)
+
+			for _, key := range []string{"champ:1", "champ:10", "title:1", "champ:2"} {
+				s.notifyWatches(&CDCEvent{Operation: litetable.OperationWrite, RowKey: key})
+			}
+			s.Unwatch(w)
+			s.Unwatch(w)
+
+			var keys []string
+			for evt := range w.Events() {
+				keys = append(keys, evt.RowKey)
+			}
+			req.Equal(tc.expected, keys)
+			req.False(w.Overflowed())
+		})
+	}
+}
+
+func TestServer_Watch_overflow(t *testing.T) {
+	req := require.New(t)
+	s := New(&Config{})
+	slow, err := s.Watch("champ:1", "")
+	req.NoError(err)
+	other, err := s.Watch("champ:2", "")
+	req.NoError(err)
+
+	for range watchBuffer + 1 {
+		s.notifyWatches(&CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:1"})
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	req.Equal(watchBuffer, received)
+	req.True(slow.Overflowed())
+
+	// watches on other rows are unaffected
+	req.Len(s.watches, 1)
+	s.Unwatch(other)
+	_, open := <-other.Events()
+	req.False(open)
+}
+
+func TestServer_Stop_watches(t *testing.T) {
+	req := require.New(t)
+	s := New(&Config{})
+	w, err := s.Watch("", "champ:")
+	req.NoError(err)
+
+	req.NoError(s.Stop())
+	_, open := <-w.Events()
+	req.False(open)
+	req.False(w.Overflowed())
+
+	// watches made after a stop end right away
+	w, err = s.Watch("champ:1", "")
+	req.NoError(err)
+	_, open = <-w.Events()
+	req.False(open)
+}
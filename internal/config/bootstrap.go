@@ -0,0 +1,249 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// ServerCertFile and ServerKeyFile are the TLS certificate and key in the LiteTable directory.
+	ServerCertFile = "server.crt"
+	ServerKeyFile  = "server.key"
+
+	certValidity = 365 * 24 * time.Hour
+)
+
+// defaultConfig is written to litetable.yaml on first run.
+const defaultConfig = `# LiteTable configuration. Every setting can be overridden with a LITETABLE_<KEY> environment
+# variable or a --<key> flag, e.g. LITETABLE_SERVER_PORT or --server-port.
+
+server:
+  # address the HTTP and gRPC servers listen on
+  address: 127.0.0.1
+  # HTTP port for /health and /metrics
+  port: 8080
+  # gRPC port for reads and writes
+  rpc_port: 9443
+
+# gRPC tuning, e.g. for large values; requests are limited to 4MB by default
+# grpc:
+#   # prefix and regex reads matching more rows than this are truncated with a page token
+#   max_response_size: 4194304
+#   # read cost quotas of the clients naming themselves in the x-litetable-client header
+#   cost_window: 1m
+#   clients:
+#     reports:
+#       max_cost: 1000000
+#       alert_cost: 500000
+#   max_recv_msg_size: 16777216
+#   max_send_msg_size: 16777216
+#   max_concurrent_streams: 100
+#   # clients that send keepalive pings more often than this are disconnected
+#   keepalive_min_time: 5m
+#   keepalive_permit_without_stream: false
+#   # gzip level of responses to clients that compress, 1 (fastest) to 9 (smallest)
+#   compression_level: 6
+#   # request, byte and connection counts on /metrics
+#   stats: true
+#   # record the anonymized shape of a sample of the reads, writes and deletes, for replay
+#   # against a test server with litetable-replay
+#   record_queries_file: /var/lib/litetable/queries.jsonl
+#   record_queries_rate: 0.01
+#   # debugging services for grpcurl and grpcdebug; keep them off in production
+#   reflection: true
+#   channelz: true
+
+storage:
+  # engine that holds table data: memory
+  engine: memory
+  # also apply every write to this engine and log the reads it answers differently, to verify
+  # an engine before moving to it; it doubles the memory and disk tables use
+  # shadow_engine: memory
+  # group up to this many concurrent writes into one WAL record, waiting at most the delay
+  # write_batch_size: 64
+  # write_batch_delay: 200us
+  # seconds between incremental snapshots of changed rows
+  snapshot_timer: 5
+  # seconds between merges of snapshots into a backup
+  backup_timer: 60
+  # number of backups to keep
+  max_snapshot_limit: 10
+  # older backups are also kept by age, e.g. 7 dailies and 4 weeklies
+  # backup_retention_days: 0
+  # backup_keep_daily: 7
+  # backup_keep_weekly: 4
+  # rewrite the full backup on every nth merge and write deltas in between
+  # full_backup_interval: 1
+  # seconds between garbage collection runs
+  garbage_collection_timer: 10
+  # time between runs of the job that computes row, cell and version stats of each family
+  # stats_interval: 10m
+  # row key regexes compiling to more instructions are refused, and a regex scan is abandoned
+  # once it has read one shard for longer than the budget
+  # max_regex_program: 2000
+  # regex_scan_budget: 250ms
+  # cache the rows of prefix reads for read-mostly dashboards, until a row under the prefix
+  # changes or the ttl passes
+  # scan_cache_ttl: 5s
+  # keep a copy of each shard, refreshed this often, for reads with consistency=eventual to read
+  # without waiting on writes; it takes as much memory as the rows
+  # read_view_interval: 100ms
+  # most bytes per second written to snapshot, backup and delta files, e.g. 50 MB/s
+  # background_write_rate: 52428800
+  # snapshots and backups are refused below this much free disk space, e.g. 1 GB, and writes
+  # too with reject_writes_on_low_disk
+  # min_free_disk_bytes: 1073741824
+  # reject_writes_on_low_disk: false
+  # writes, deletes and transactions are refused with RESOURCE_EXHAUSTED while a backlog is
+  # over its limit, instead of queueing in memory under sustained overload
+  # max_wal_backlog: 10000
+  # max_snapshot_backlog: 1000000
+  # max_cdc_queue: 5000
+  # max_reaper_queue: 50000
+  # full backup rewrites and the family policy scan run at full speed only in these windows,
+  # in the server's local time; without windows they are never held back
+  # maintenance_windows:
+  #   - "sat,sun 01:00-05:00"
+  #   - "mon-fri 02:00-03:00"
+  # per-family retention and storage, e.g.
+  # families:
+  #   wrestlers:
+  #     max_age: 720h
+  #     max_versions: 5
+  #     default_ttl: 24h
+  #     max_bytes: 1073741824
+  #     max_rows: 100000
+  #     # store values compressed with zstd
+  #     compress: true
+  #     # check the values of these qualifiers and store them in canonical form
+  #     types:
+  #       wins: int64
+  #       ratio: float64
+  #       active: bool
+  # per-table quotas, e.g.
+  # tables:
+  #   default:
+  #     max_bytes: 4294967296
+
+# change data capture stream
+# cdc:
+#   port: 32473
+#   # most bytes kept of the events no subscriber received, for replay
+#   spill_max_bytes: 67108864
+#   # name of this server on its events; the hostname by default
+#   node_id: litetable-1
+#   # report the value each change replaces on its events
+#   old_values: true
+#   # emit each change only once a snapshot holds it, delaying events by up to snapshot_timer,
+#   # and hold at most outbox_size events, applying overflow_policy to the one held longest
+#   durable: false
+#   outbox_size: 100000
+#   # only these column families emit events; every family does by default
+#   families: [wrestlers]
+#   # events queued for dispatch, and what happens to an event when the queue is full: block
+#   # the write, drop_oldest or spill it for replay
+#   buffer_size: 1000
+#   overflow_policy: block
+#   # turn off the stream and the Watch RPC
+#   disabled: false
+
+logging:
+  debug: false
+`
+
+// Bootstrap creates the config file's directory, a commented default config at configPath, and
+// a self-signed TLS certificate next to it, skipping anything that already exists. It returns
+// the paths it created.
+func Bootstrap(configPath string) ([]string, error) {
+	var created []string
+	dir := filepath.Dir(configPath)
+
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create LiteTable directory: %w", err)
+		}
+		created = append(created, dir)
+	}
+
+	if !exists(configPath) {
+		if err := os.WriteFile(configPath, []byte(defaultConfig), 0644); err != nil {
+			return created, fmt.Errorf("failed to write default config: %w", err)
+		}
+		created = append(created, configPath)
+	}
+
+	certPath := filepath.Join(dir, ServerCertFile)
+	keyPath := filepath.Join(dir, ServerKeyFile)
+	if !exists(certPath) || !exists(keyPath) {
+		if err := writeSelfSignedCert(certPath, keyPath); err != nil {
+			return created, err
+		}
+		created = append(created, certPath, keyPath)
+	}
+
+	return created, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// writeSelfSignedCert generates a certificate for localhost that is good enough for local
+// development. Production deployments should replace it.
+func writeSelfSignedCert(certPath, keyPath string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate TLS key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return fmt.Errorf("failed to generate certificate serial: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"LiteTable"}, CommonName: "localhost"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("failed to create TLS certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode TLS key: %w", err)
+	}
+
+	if err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY",
+		Bytes: keyDER}), 0600); err != nil {
+		return fmt.Errorf("failed to write TLS key: %w", err)
+	}
+	if err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE",
+		Bytes: der}), 0644); err != nil {
+		return fmt.Errorf("failed to write TLS certificate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"crypto/tls"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBootstrap(t *testing.T) {
+	req := require.New(t)
+	dir := filepath.Join(t.TempDir(), ".litetable")
+	configPath := filepath.Join(dir, configFileName)
+
+	created, err := Bootstrap(configPath)
+	req.NoError(err)
+	req.Equal([]string{
+		dir,
+		configPath,
+		filepath.Join(dir, ServerCertFile),
+		filepath.Join(dir, ServerKeyFile),
+	}, created)
+
+	// the default config is valid as written
+	cfg, err := NewConfig([]string{"--config", configPath})
+	req.NoError(err)
+	req.NoError(cfg.Validate())
+
+	_, err = tls.LoadX509KeyPair(filepath.Join(dir, ServerCertFile),
+		filepath.Join(dir, ServerKeyFile))
+	req.NoError(err)
+
+	info, err := os.Stat(filepath.Join(dir, ServerKeyFile))
+	req.NoError(err)
+	req.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// a second run leaves everything in place
+	created, err = Bootstrap(configPath)
+	req.NoError(err)
+	req.Empty(created)
+}
+
+func TestNewConfig_Init(t *testing.T) {
+	req := require.New(t)
+	configPath := filepath.Join(t.TempDir(), "custom.yaml")
+
+	_, err := NewConfig([]string{"--config", configPath})
+	req.ErrorContains(err, "configuration file not found")
+
+	cfg, err := NewConfig([]string{"--config", configPath, "--init"})
+	req.NoError(err)
+	req.Equal(8080, cfg.Server.Port)
+}
@@ -2,18 +2,25 @@ package config
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
 	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/logging"
 	"github.com/litetable/litetable-db/internal/server"
 	"github.com/litetable/litetable-db/internal/server/grpc"
+	"github.com/litetable/litetable-db/internal/shard_storage"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
-	configFileName = "litetable.conf"
+	configFileName = "litetable.yaml"
+	// legacyConfigFileName is the key=value format written by older installs
+	legacyConfigFileName = "litetable.conf"
 )
 
 type Config struct {
@@ -25,26 +32,271 @@ type Config struct {
 	Debug                  bool
 	CloudEnvironment       string
 	GRPCServer             grpc.Config
+	CDC                    v1.Config
+	// FamilyPolicies are the per-family retention rules enforced by the reaper and the
+	// per-family quotas
+	FamilyPolicies map[string]shard_storage.FamilyPolicy
+	// TableQuotas are the quotas of each table, keyed by table name
+	TableQuotas map[string]shard_storage.Quota
+	// BackupRetention keeps backups by age in addition to the newest MaxSnapshotLimit
+	BackupRetention shard_storage.BackupRetention
+	// FullBackupInterval is the number of merges per full backup
+	FullBackupInterval int
+	// StorageEngine is the name of the engine that holds table data
+	StorageEngine string
+	// ShadowEngine is the name of an engine every table also applies its writes to, to compare
+	// its reads with StorageEngine. Empty runs no shadow.
+	ShadowEngine string
+	// WriteBatchSize is the most concurrent writes grouped into one WAL record. Writes are not
+	// grouped below 2.
+	WriteBatchSize int
+	// WriteBatchDelay is how long a write waits for others to group with
+	WriteBatchDelay time.Duration
+	// StatsInterval is the time between runs of the job that computes the stats of each family
+	StatsInterval time.Duration
+	// MaxRegexProgram is the most instructions the row key regex of a scan may compile to
+	MaxRegexProgram int
+	// RegexScanBudget is how long a regex scan may read one shard before it is abandoned
+	RegexScanBudget time.Duration
+	// ScanCacheTTL is how long the rows of a prefix read are cached. 0 does not cache.
+	ScanCacheTTL time.Duration
+	// ReadViewInterval is the time between refreshes of the copies of the shards eventually
+	// consistent reads use. 0 keeps no copies.
+	ReadViewInterval time.Duration
+	// MaintenanceWindows are the windows in which full backups are rewritten and the family
+	// policies scanned at full speed
+	MaintenanceWindows shard_storage.MaintenanceWindows
+	// BackgroundWriteRate is the most bytes per second written to snapshot, backup and delta
+	// files by every table together. 0 is unlimited.
+	BackgroundWriteRate int
+	// MinFreeDiskBytes is the free space below which snapshots and backups are refused. 0 only
+	// reports the free space.
+	MinFreeDiskBytes int
+	// RejectWritesOnLowDisk refuses writes too while the disk is below MinFreeDiskBytes
+	RejectWritesOnLowDisk bool
+	// MaxWALBacklog, MaxSnapshotBacklog, MaxCDCQueue and MaxReaperQueue refuse writes, deletes
+	// and transactions while the entries waiting for the WAL, the changes waiting for a
+	// snapshot, the queued CDC events or the queued reap entries are over them. 0 is no limit.
+	MaxWALBacklog      int
+	MaxSnapshotBacklog int
+	MaxCDCQueue        int
+	MaxReaperQueue     int
+	// CDCOldValues reports the value each change replaces on its CDC events
+	CDCOldValues bool
+	// CDCDurable holds each CDC event until a snapshot holds its change
+	CDCDurable bool
+	// CDCOutboxSize bounds the events CDCDurable holds, after which cdc.overflow_policy applies
+	// to the event held longest. 0 uses the storage default.
+	CDCOutboxSize int
+	// RecordQueriesFile is the file the anonymized shapes of a sample of the reads, writes and
+	// deletes are recorded to for replay. Empty records nothing.
+	RecordQueriesFile string
+	// RecordQueriesRate is the fraction of requests recorded
+	RecordQueriesRate float64
 }
 
-func NewConfig() (*Config, error) {
+// setting is a configuration key that can be set in the config file, as an environment
+// variable, or as a command line flag.
+type setting struct {
+	key   string
+	usage string
+}
+
+// settings are the keys that can be overridden. The environment variable is the key upper
+// cased with the LITETABLE_ prefix and the flag is the key with dashes, so server_port can be
+// set with LITETABLE_SERVER_PORT or --server-port.
+var settings = []setting{
+	{key: "server_address", usage: "address the HTTP and gRPC servers listen on"},
+	{key: "server_port", usage: "HTTP server port"},
+	{key: "server_rpc_port", usage: "gRPC server port"},
+	{key: "storage_engine", usage: "storage engine that holds table data"},
+	{key: "shadow_engine", usage: "storage engine to verify against the storage engine"},
+	{key: "write_batch_size", usage: "most concurrent writes grouped into one WAL record"},
+	{key: "write_batch_delay", usage: "how long a write waits for others to group with"},
+	{key: "backup_timer", usage: "seconds between snapshot merges into a backup"},
+	{key: "garbage_collection_timer", usage: "seconds between garbage collection runs"},
+	{key: "debug", usage: "enable debug logging"},
+	{key: "cloud_environment", usage: "cloud environment the logs are formatted for"},
+	{key: "snapshot_timer", usage: "seconds between incremental snapshots"},
+	{key: "max_snapshot_limit", usage: "number of backups to keep"},
+	{key: "backup_retention_days", usage: "keep every backup created within this many days"},
+	{key: "backup_keep_daily", usage: "keep the newest backup of this many days"},
+	{key: "backup_keep_weekly", usage: "keep the newest backup of this many weeks"},
+	{key: "full_backup_interval", usage: "merges per full backup, with deltas in between"},
+	{key: "stats_interval", usage: "time between runs of the family stats job"},
+	{key: "max_regex_program", usage: "most instructions a row key regex may compile to"},
+	{key: "regex_scan_budget", usage: "time a regex scan may read one shard"},
+	{key: "scan_cache_ttl", usage: "time the rows of a prefix read are cached, 0 does not cache"},
+	{key: "read_view_interval",
+		usage: "time between refreshes of the shard copies for eventual reads, 0 keeps none"},
+	{key: "background_write_rate",
+		usage: "most bytes per second written to snapshot and backup files, 0 is unlimited"},
+	{key: "min_free_disk_bytes",
+		usage: "free space below which snapshots and backups are refused, 0 is no minimum"},
+	{key: "reject_writes_on_low_disk",
+		usage: "refuse writes too while the disk is below min_free_disk_bytes"},
+	{key: "max_wal_backlog",
+		usage: "entries waiting for the WAL above which writes are refused, 0 is no limit"},
+	{key: "max_snapshot_backlog",
+		usage: "changes waiting for a snapshot above which writes are refused, 0 is no limit"},
+	{key: "max_cdc_queue",
+		usage: "queued CDC events above which writes are refused, 0 is no limit"},
+	{key: "max_reaper_queue",
+		usage: "queued reap entries above which writes are refused, 0 is no limit"},
+	{key: "maintenance_windows",
+		usage: "semicolon separated windows for heavy maintenance, e.g. \"sat,sun 01:00-05:00\""},
+	{key: "max_row_key_length", usage: "maximum row key length in bytes"},
+	{key: "max_qualifiers", usage: "maximum qualifiers per request"},
+	{key: "max_value_size", usage: "maximum value size in bytes"},
+	{key: "family_name_pattern", usage: "regular expression family names must match"},
+	{key: "max_response_size",
+		usage: "largest size in bytes of the rows of a scan before it is truncated"},
+	{key: "cost_window", usage: "time over which the read cost of a client adds up"},
+	{key: "max_recv_msg_size", usage: "largest gRPC request in bytes"},
+	{key: "max_send_msg_size", usage: "largest gRPC response in bytes"},
+	{key: "max_concurrent_streams", usage: "concurrent gRPC requests per client connection"},
+	{key: "keepalive_min_time", usage: "shortest interval between client keepalive pings"},
+	{key: "keepalive_permit_without_stream",
+		usage: "allow client keepalive pings with no request in flight"},
+	{key: "compression_level", usage: "gzip level of compressed gRPC responses, from 1 to 9"},
+	{key: "grpc_reflection", usage: "register gRPC reflection for tools like grpcurl"},
+	{key: "grpc_channelz", usage: "register the gRPC channelz service for grpcdebug"},
+	{key: "grpc_stats", usage: "export gRPC request, byte and connection counts on /metrics"},
+	{key: "record_queries_file",
+		usage: "file the anonymized shapes of sampled requests are recorded to for replay"},
+	{key: "record_queries_rate", usage: "fraction of requests recorded, 0.01 by default"},
+	{key: "cdc_address", usage: "address the CDC stream listens on"},
+	{key: "cdc_port", usage: "CDC stream port"},
+	{key: "cdc_spill_max_bytes",
+		usage: "largest size in bytes of the file keeping CDC events no subscriber received"},
+	{key: "cdc_node_id", usage: "name of this server on its CDC events, the hostname by default"},
+	{key: "cdc_old_values", usage: "report the value each change replaces on its CDC events"},
+	{key: "cdc_durable", usage: "emit CDC events only once a snapshot holds their changes"},
+	{key: "cdc_outbox_size", usage: "number of CDC events held until a snapshot with cdc_durable"},
+	{key: "cdc_disabled", usage: "turn off the CDC stream and watches"},
+	{key: "cdc_families", usage: "comma separated column families whose changes emit CDC events"},
+	{key: "cdc_buffer_size", usage: "number of CDC events queued for dispatch"},
+	{key: "cdc_overflow_policy",
+		usage: "what happens to a CDC event when the queue is full: block, drop_oldest or spill"},
+}
+
+// NewConfig builds the configuration from, in increasing order of precedence, the config file,
+// LITETABLE_* environment variables, and command line flags. The config file defaults to
+// ~/.litetable/litetable.yaml, falling back to the legacy litetable.conf, and can be moved with
+// --config or LITETABLE_CONFIG. On first run, or with --init, the default layout is created
+// with Bootstrap. Unset values are defaulted and the result is validated.
+func NewConfig(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("litetable", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to the configuration file")
+	initialize := fs.Bool("init", false,
+		"create the config directory, a default config and TLS certificates if missing")
+	for _, s := range settings {
+		fs.String(flagName(s.key), "", s.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	path, isDefault, err := resolveConfigPath(*configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// first run: create the default layout instead of failing on a missing install
+	if *initialize || (isDefault && !exists(path)) {
+		created, err := Bootstrap(path)
+		logger := logging.For("config")
+		for _, p := range created {
+			logger.Info().Str("path", p).Msg("created")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := &Config{}
+	if err = config.loadFile(path); err != nil {
+		return nil, err
+	}
+
+	for _, s := range settings {
+		value, ok := os.LookupEnv(envName(s.key))
+		if !ok {
+			continue
+		}
+		if err = config.set(s.key, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", envName(s.key), err)
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil || f.Name == "config" || f.Name == "init" {
+			return
+		}
+		key := strings.ReplaceAll(f.Name, "-", "_")
+		if setErr := config.set(key, f.Value.String()); setErr != nil {
+			err = fmt.Errorf("--%s: %w", f.Name, setErr)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	config.applyDefaults()
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// resolveConfigPath returns the config file to load, preferring the flag, then the
+// environment, then the default location, and whether the default location was used.
+func resolveConfigPath(flagPath string) (string, bool, error) {
+	if flagPath != "" {
+		return flagPath, false, nil
+	}
+	if envPath := os.Getenv(envName("config")); envPath != "" {
+		return envPath, false, nil
+	}
+
 	liteTableDir, err := litetable.GetLitetableDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get LiteTable directory: %w", err)
+		return "", false, fmt.Errorf("failed to get LiteTable directory: %w", err)
+	}
+	path := filepath.Join(liteTableDir, configFileName)
+	if exists(path) {
+		return path, true, nil
+	}
+	legacyPath := filepath.Join(liteTableDir, legacyConfigFileName)
+	if exists(legacyPath) {
+		return legacyPath, true, nil
+	}
+	return path, true, nil
+}
+
+// loadFile reads the config file, choosing the format by its extension.
+func (c *Config) loadFile(configPath string) error {
+	switch filepath.Ext(configPath) {
+	case ".yaml", ".yml":
+		return c.loadYAML(configPath)
+	default:
+		return c.loadLegacy(configPath)
 	}
+}
 
-	configPath := filepath.Join(liteTableDir, configFileName)
+// loadLegacy reads key=value pairs from a litetable.conf file. Unknown keys are ignored.
+func (c *Config) loadLegacy(configPath string) error {
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("LiteTable is not installed or configuration file not found")
+		return fmt.Errorf("LiteTable is not installed or configuration file not found")
 	}
 
 	file, err := os.Open(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open config file: %w", err)
+		return fmt.Errorf("failed to open config file: %w", err)
 	}
 	defer file.Close()
 
-	config := &Config{}
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
@@ -63,50 +315,471 @@ func NewConfig() (*Config, error) {
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
 
-		switch key {
-		case "server_address":
-			config.Server.Address = value
-			config.GRPCServer.Address = value
-		case "server_port":
-			config.Server.Port, err = strconv.Atoi(value)
-			if err != nil {
-				return nil, fmt.Errorf("invalid server port value: %w", err)
-			}
-		case "server_rpc_port":
-			config.GRPCServer.Port, err = strconv.Atoi(value)
-			if err != nil {
-				return nil, fmt.Errorf("invalid server RPC port value: %w", err)
-			}
-		case "backup_timer":
-			config.BackupTimer, err = strconv.Atoi(value)
-			if err != nil {
-				return nil, fmt.Errorf("invalid backup timer value: %w", err)
-			}
-		case "garbage_collection_timer":
-			config.GarbageCollectionTimer, err = strconv.Atoi(value)
-			if err != nil {
-				return nil, fmt.Errorf("invalid garbage collection timer value: %w", err)
-			}
-		case "debug":
-			config.Debug = value == "true"
-		case "cloud_environment":
-			config.CloudEnvironment = value
-		case "snapshot_timer":
-			config.SnapshotTimer, err = strconv.Atoi(value)
-			if err != nil {
-				return nil, fmt.Errorf("invalid snapshot timer value: %w", err)
-			}
-		case "max_snapshot_limit":
-			config.MaxSnapshotLimit, err = strconv.Atoi(value)
-			if err != nil {
-				return nil, fmt.Errorf("invalid snapshot limit value: %w", err)
-			}
+		if err = c.set(key, value); err != nil {
+			return err
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
+		return fmt.Errorf("error reading config file: %w", err)
 	}
 
-	return config, nil
+	return nil
+}
+
+// set applies a single configuration value. Unknown keys are ignored.
+func (c *Config) set(key, value string) error {
+	var err error
+
+	switch key {
+	case "server_address":
+		c.Server.Address = value
+		c.GRPCServer.Address = value
+	case "server_port":
+		c.Server.Port, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid server port value: %w", err)
+		}
+	case "server_rpc_port":
+		c.GRPCServer.Port, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid server RPC port value: %w", err)
+		}
+	case "backup_timer":
+		c.BackupTimer, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid backup timer value: %w", err)
+		}
+	case "garbage_collection_timer":
+		c.GarbageCollectionTimer, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid garbage collection timer value: %w", err)
+		}
+	case "debug":
+		c.Debug, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid debug value: %w", err)
+		}
+	case "cloud_environment":
+		c.CloudEnvironment = value
+	case "snapshot_timer":
+		c.SnapshotTimer, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid snapshot timer value: %w", err)
+		}
+	case "storage_engine":
+		c.StorageEngine = value
+	case "shadow_engine":
+		c.ShadowEngine = value
+	case "write_batch_size":
+		c.WriteBatchSize, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid write batch size value: %w", err)
+		}
+	case "write_batch_delay":
+		c.WriteBatchDelay, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid write batch delay value: %w", err)
+		}
+	case "max_snapshot_limit":
+		c.MaxSnapshotLimit, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid snapshot limit value: %w", err)
+		}
+	case "backup_retention_days":
+		c.BackupRetention.Days, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid backup retention days value: %w", err)
+		}
+	case "backup_keep_daily":
+		c.BackupRetention.KeepDaily, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid backup keep daily value: %w", err)
+		}
+	case "backup_keep_weekly":
+		c.BackupRetention.KeepWeekly, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid backup keep weekly value: %w", err)
+		}
+	case "full_backup_interval":
+		c.FullBackupInterval, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid full backup interval value: %w", err)
+		}
+	case "stats_interval":
+		c.StatsInterval, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid stats interval value: %w", err)
+		}
+	case "max_regex_program":
+		c.MaxRegexProgram, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max regex program value: %w", err)
+		}
+	case "regex_scan_budget":
+		c.RegexScanBudget, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid regex scan budget value: %w", err)
+		}
+	case "scan_cache_ttl":
+		c.ScanCacheTTL, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid scan cache ttl value: %w", err)
+		}
+	case "read_view_interval":
+		c.ReadViewInterval, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid read view interval value: %w", err)
+		}
+	case "background_write_rate":
+		c.BackgroundWriteRate, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid background write rate value: %w", err)
+		}
+	case "min_free_disk_bytes":
+		c.MinFreeDiskBytes, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid min free disk bytes value: %w", err)
+		}
+	case "reject_writes_on_low_disk":
+		c.RejectWritesOnLowDisk, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid reject writes on low disk value: %w", err)
+		}
+	case "max_wal_backlog":
+		c.MaxWALBacklog, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max WAL backlog value: %w", err)
+		}
+	case "max_snapshot_backlog":
+		c.MaxSnapshotBacklog, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max snapshot backlog value: %w", err)
+		}
+	case "max_cdc_queue":
+		c.MaxCDCQueue, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max CDC queue value: %w", err)
+		}
+	case "max_reaper_queue":
+		c.MaxReaperQueue, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max reaper queue value: %w", err)
+		}
+	case "maintenance_windows":
+		c.MaintenanceWindows, err = parseMaintenanceWindows(strings.Split(value, ";"))
+		if err != nil {
+			return err
+		}
+	case "max_row_key_length":
+		c.GRPCServer.Limits.MaxRowKeyLength, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max row key length value: %w", err)
+		}
+	case "max_qualifiers":
+		c.GRPCServer.Limits.MaxQualifiers, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max qualifiers value: %w", err)
+		}
+	case "max_value_size":
+		c.GRPCServer.Limits.MaxValueSize, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max value size value: %w", err)
+		}
+	case "family_name_pattern":
+		c.GRPCServer.Limits.FamilyNamePattern = value
+	case "max_response_size":
+		c.GRPCServer.Limits.MaxResponseSize, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max response size value: %w", err)
+		}
+	case "cost_window":
+		c.GRPCServer.Limits.CostWindow, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid cost window value: %w", err)
+		}
+	case "max_recv_msg_size":
+		c.GRPCServer.Transport.MaxRecvMsgSize, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max receive message size value: %w", err)
+		}
+	case "max_send_msg_size":
+		c.GRPCServer.Transport.MaxSendMsgSize, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max send message size value: %w", err)
+		}
+	case "max_concurrent_streams":
+		c.GRPCServer.Transport.MaxConcurrentStreams, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max concurrent streams value: %w", err)
+		}
+	case "keepalive_min_time":
+		c.GRPCServer.Transport.KeepaliveMinTime, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid keepalive min time value: %w", err)
+		}
+	case "keepalive_permit_without_stream":
+		c.GRPCServer.Transport.KeepalivePermitWithoutStream, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid keepalive permit without stream value: %w", err)
+		}
+	case "compression_level":
+		c.GRPCServer.Transport.CompressionLevel, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid compression level value: %w", err)
+		}
+	case "grpc_reflection":
+		c.GRPCServer.Reflection, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid gRPC reflection value: %w", err)
+		}
+	case "grpc_channelz":
+		c.GRPCServer.Channelz, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid gRPC channelz value: %w", err)
+		}
+	case "grpc_stats":
+		c.GRPCServer.Stats, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid gRPC stats value: %w", err)
+		}
+	case "record_queries_file":
+		c.RecordQueriesFile = value
+	case "record_queries_rate":
+		c.RecordQueriesRate, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid record queries rate value: %w", err)
+		}
+	case "cdc_address":
+		c.CDC.Address = value
+	case "cdc_port":
+		c.CDC.Port, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid CDC port value: %w", err)
+		}
+	case "cdc_spill_max_bytes":
+		c.CDC.SpillMaxBytes, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid CDC spill max bytes value: %w", err)
+		}
+	case "cdc_node_id":
+		c.CDC.NodeID = value
+	case "cdc_old_values":
+		c.CDCOldValues, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid CDC old values value: %w", err)
+		}
+	case "cdc_durable":
+		c.CDCDurable, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid CDC durable value: %w", err)
+		}
+	case "cdc_disabled":
+		c.CDC.Disabled, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid CDC disabled value: %w", err)
+		}
+	case "cdc_outbox_size":
+		c.CDCOutboxSize, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid CDC outbox size value: %w", err)
+		}
+	case "cdc_buffer_size":
+		c.CDC.BufferSize, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid CDC buffer size value: %w", err)
+		}
+	case "cdc_overflow_policy":
+		c.CDC.OverflowPolicy = value
+	case "cdc_families":
+		c.CDC.Families = nil
+		for _, family := range strings.Split(value, ",") {
+			if family = strings.TrimSpace(family); family != "" {
+				c.CDC.Families = append(c.CDC.Families, family)
+			}
+		}
+	default:
+		if strings.HasPrefix(key, "table_") {
+			return c.parseTableQuota(key, value)
+		}
+		if strings.HasPrefix(key, "client_") {
+			return c.parseClientQuota(key, value)
+		}
+		return c.parseFamilyPolicy(key, value)
+	}
+
+	return nil
+}
+
+func envName(key string) string {
+	return "LITETABLE_" + strings.ToUpper(key)
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// parseFamilyPolicy handles the per-family retention and storage keys. Any other key is
+// ignored.
+//
+//	gc_max_age.<family> = 720h
+//	gc_max_versions.<family> = 5
+//	default_ttl.<family> = 24h
+//	max_bytes.<family> = 1073741824
+//	max_rows.<family> = 100000
+//	compress.<family> = true
+//	type.<family>.<qualifier> = int64
+func (c *Config) parseFamilyPolicy(key, value string) error {
+	setting, family, found := strings.Cut(key, ".")
+	if !found || family == "" {
+		return nil
+	}
+
+	switch setting {
+	case "gc_max_age", "gc_max_versions", "default_ttl", "max_bytes", "max_rows", "compress",
+		"type":
+	default:
+		return nil
+	}
+
+	// types are declared per qualifier of the family
+	var qualifier string
+	if setting == "type" {
+		family, qualifier, found = strings.Cut(family, ".")
+		if !found || family == "" || qualifier == "" {
+			return fmt.Errorf("invalid type key %s, expected type.<family>.<qualifier>", key)
+		}
+	}
+
+	if c.FamilyPolicies == nil {
+		c.FamilyPolicies = make(map[string]shard_storage.FamilyPolicy)
+	}
+	policy := c.FamilyPolicies[family]
+
+	switch setting {
+	case "gc_max_age":
+		maxAge, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid max age value for family %s: %w", family, err)
+		}
+		policy.MaxAge = maxAge
+	case "gc_max_versions":
+		maxVersions, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max versions value for family %s: %w", family, err)
+		}
+		policy.MaxVersions = maxVersions
+	case "default_ttl":
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid default ttl value for family %s: %w", family, err)
+		}
+		policy.DefaultTTL = ttl
+	case "max_bytes":
+		maxBytes, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid max bytes value for family %s: %w", family, err)
+		}
+		policy.Quota.MaxBytes = maxBytes
+	case "max_rows":
+		maxRows, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid max rows value for family %s: %w", family, err)
+		}
+		policy.Quota.MaxRows = maxRows
+	case "compress":
+		compress, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid compress value for family %s: %w", family, err)
+		}
+		policy.Compress = compress
+	case "type":
+		if policy.Types == nil {
+			policy.Types = make(map[string]shard_storage.ValueType)
+		}
+		policy.Types[qualifier] = shard_storage.ValueType(value)
+	}
+
+	c.FamilyPolicies[family] = policy
+	return nil
+}
+
+// parseTableQuota handles the per-table quota keys. Any other key is ignored.
+//
+//	table_max_bytes.<table> = 1073741824
+//	table_max_rows.<table> = 100000
+func (c *Config) parseTableQuota(key, value string) error {
+	setting, table, found := strings.Cut(key, ".")
+	if !found || table == "" {
+		return nil
+	}
+	if setting != "table_max_bytes" && setting != "table_max_rows" {
+		return nil
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s value for table %s: %w",
+			strings.ReplaceAll(strings.TrimPrefix(setting, "table_"), "_", " "), table, err)
+	}
+
+	if c.TableQuotas == nil {
+		c.TableQuotas = make(map[string]shard_storage.Quota)
+	}
+	quota := c.TableQuotas[table]
+	if setting == "table_max_bytes" {
+		quota.MaxBytes = limit
+	} else {
+		quota.MaxRows = limit
+	}
+	c.TableQuotas[table] = quota
+	return nil
+}
+
+// parseClientQuota handles the per-client read cost keys. Any other key is ignored.
+//
+//	client_max_cost.<client> = 1000000
+//	client_alert_cost.<client> = 500000
+func (c *Config) parseClientQuota(key, value string) error {
+	setting, client, found := strings.Cut(key, ".")
+	if !found || client == "" {
+		return nil
+	}
+	if setting != "client_max_cost" && setting != "client_alert_cost" {
+		return nil
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s value for client %s: %w",
+			strings.ReplaceAll(strings.TrimPrefix(setting, "client_"), "_", " "), client, err)
+	}
+
+	if c.GRPCServer.Limits.ClientQuotas == nil {
+		c.GRPCServer.Limits.ClientQuotas = make(map[string]grpc.ClientQuota)
+	}
+	quota := c.GRPCServer.Limits.ClientQuotas[client]
+	if setting == "client_max_cost" {
+		quota.MaxCost = limit
+	} else {
+		quota.AlertCost = limit
+	}
+	c.GRPCServer.Limits.ClientQuotas[client] = quota
+	return nil
+}
+
+// parseMaintenanceWindows parses a list of maintenance windows, skipping blank entries.
+func parseMaintenanceWindows(values []string) (shard_storage.MaintenanceWindows, error) {
+	var windows shard_storage.MaintenanceWindows
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		w, err := shard_storage.ParseMaintenanceWindow(value)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
 }
@@ -0,0 +1,383 @@
+package config
+
+import (
+	"github.com/litetable/litetable-db/internal/server/grpc"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestNewConfig(t *testing.T) {
+	path := writeConfig(t, legacyConfigFileName, `
+# comment
+server_address = 127.0.0.1
+server_port = 9000
+server_rpc_port = 9090
+snapshot_timer = 5
+backup_keep_daily = 7
+backup_keep_weekly = 4
+gc_max_age.main = 720h
+default_ttl.main = 24h
+max_bytes.main = 1024
+compress.main = true
+type.main.wins = int64
+table_max_rows.wwe = 100
+max_concurrent_streams = 100
+keepalive_min_time = 30s
+grpc_channelz = true
+cost_window = 30s
+client_max_cost.reports = 1000
+client_alert_cost.reports = 800
+regex_scan_budget = 100ms
+`)
+
+	tests := map[string]struct {
+		args    []string
+		env     map[string]string
+		check   func(r *require.Assertions, cfg *Config)
+		wantErr string
+	}{
+		"file only": {
+			args: []string{"--config", path},
+			check: func(r *require.Assertions, cfg *Config) {
+				r.Equal("127.0.0.1", cfg.Server.Address)
+				r.Equal("127.0.0.1", cfg.GRPCServer.Address)
+				r.Equal(9000, cfg.Server.Port)
+				r.Equal(9090, cfg.GRPCServer.Port)
+				r.Equal(5, cfg.SnapshotTimer)
+				r.Equal(shard_storage.BackupRetention{KeepDaily: 7, KeepWeekly: 4},
+					cfg.BackupRetention)
+				r.Equal(720*time.Hour, cfg.FamilyPolicies["main"].MaxAge)
+				r.Equal(24*time.Hour, cfg.FamilyPolicies["main"].DefaultTTL)
+				r.Equal(shard_storage.Quota{MaxBytes: 1024}, cfg.FamilyPolicies["main"].Quota)
+				r.True(cfg.FamilyPolicies["main"].Compress)
+				r.Equal(map[string]shard_storage.ValueType{"wins": shard_storage.ValueInt64},
+					cfg.FamilyPolicies["main"].Types)
+				r.Equal(map[string]shard_storage.Quota{"wwe": {MaxRows: 100}}, cfg.TableQuotas)
+				r.Equal(100, cfg.GRPCServer.Transport.MaxConcurrentStreams)
+				r.Equal(30*time.Second, cfg.GRPCServer.Transport.KeepaliveMinTime)
+				r.True(cfg.GRPCServer.Channelz)
+				r.False(cfg.GRPCServer.Reflection)
+				r.Equal(30*time.Second, cfg.GRPCServer.Limits.CostWindow)
+				r.Equal(map[string]grpc.ClientQuota{"reports": {MaxCost: 1000, AlertCost: 800}},
+					cfg.GRPCServer.Limits.ClientQuotas)
+				r.Equal(100*time.Millisecond, cfg.RegexScanBudget)
+			},
+		},
+		"env overrides file": {
+			args: []string{"--config", path},
+			env:  map[string]string{"LITETABLE_SERVER_PORT": "9100"},
+			check: func(r *require.Assertions, cfg *Config) {
+				r.Equal(9100, cfg.Server.Port)
+				r.Equal(9090, cfg.GRPCServer.Port)
+			},
+		},
+		"flag overrides env": {
+			args: []string{"--config", path, "--server-port", "9200", "--debug=true"},
+			env:  map[string]string{"LITETABLE_SERVER_PORT": "9100"},
+			check: func(r *require.Assertions, cfg *Config) {
+				r.Equal(9200, cfg.Server.Port)
+				r.True(cfg.Debug)
+			},
+		},
+		"config path from env": {
+			env: map[string]string{"LITETABLE_CONFIG": path},
+			check: func(r *require.Assertions, cfg *Config) {
+				r.Equal(9000, cfg.Server.Port)
+			},
+		},
+		"invalid env value": {
+			args:    []string{"--config", path},
+			env:     map[string]string{"LITETABLE_SNAPSHOT_TIMER": "soon"},
+			wantErr: "LITETABLE_SNAPSHOT_TIMER: invalid snapshot timer value",
+		},
+		"maintenance windows from env": {
+			args: []string{"--config", path},
+			env: map[string]string{
+				"LITETABLE_MAINTENANCE_WINDOWS": "02:00-04:00; fri-mon 23:00-01:00",
+			},
+			check: func(r *require.Assertions, cfg *Config) {
+				r.Equal(shard_storage.MaintenanceWindows{
+					{Start: 2 * time.Hour, Length: 2 * time.Hour},
+					{
+						Days: []time.Weekday{time.Friday, time.Saturday, time.Sunday,
+							time.Monday},
+						Start:  23 * time.Hour,
+						Length: 2 * time.Hour,
+					},
+				}, cfg.MaintenanceWindows)
+			},
+		},
+		"cdc families from flag": {
+			args: []string{"--config", path, "--cdc-families", "main, stats,", "--cdc-disabled",
+				"true"},
+			check: func(r *require.Assertions, cfg *Config) {
+				r.Equal([]string{"main", "stats"}, cfg.CDC.Families)
+				r.True(cfg.CDC.Disabled)
+			},
+		},
+		"booleans accept any strconv form": {
+			args: []string{"--config", path, "--cdc-durable", "1", "--grpc-reflection", "T"},
+			check: func(r *require.Assertions, cfg *Config) {
+				r.True(cfg.CDCDurable)
+				r.True(cfg.GRPCServer.Reflection)
+			},
+		},
+		"invalid boolean": {
+			args:    []string{"--config", path, "--cdc-disabled", "yes"},
+			wantErr: "--cdc-disabled: invalid CDC disabled value",
+		},
+		"invalid flag value": {
+			args:    []string{"--config", path, "--server-rpc-port", "abc"},
+			wantErr: "--server-rpc-port: invalid server RPC port value",
+		},
+		"unknown flag": {
+			args:    []string{"--config", path, "--nope", "1"},
+			wantErr: "flag provided but not defined: -nope",
+		},
+		"missing file": {
+			args:    []string{"--config", filepath.Join(t.TempDir(), "missing.conf")},
+			wantErr: "configuration file not found",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := NewConfig(tc.args)
+			r := require.New(t)
+			if tc.wantErr != "" {
+				r.ErrorContains(err, tc.wantErr)
+				return
+			}
+			r.NoError(err)
+			tc.check(r, cfg)
+		})
+	}
+}
+
+func TestNewConfig_compress(t *testing.T) {
+	r := require.New(t)
+	path := writeConfig(t, legacyConfigFileName, "compress.main = 1\ncompress.logs = FALSE\n")
+	cfg, err := NewConfig([]string{"--config", path})
+	r.NoError(err)
+	r.True(cfg.FamilyPolicies["main"].Compress)
+	r.False(cfg.FamilyPolicies["logs"].Compress)
+
+	path = writeConfig(t, legacyConfigFileName, "compress.main = yes\n")
+	_, err = NewConfig([]string{"--config", path})
+	r.ErrorContains(err, "invalid compress value for family main")
+}
+
+func TestNewConfig_YAML(t *testing.T) {
+	tests := map[string]struct {
+		contents string
+		check    func(r *require.Assertions, cfg *Config)
+		wantErr  string
+	}{
+		"nested sections": {
+			contents: `
+server:
+  address: 127.0.0.1
+  port: 9000
+  rpc_port: 9090
+grpc:
+  max_qualifiers: 10
+  max_response_size: 1048576
+  cost_window: 2m
+  clients:
+    reports:
+      max_cost: 5000
+  max_recv_msg_size: 16777216
+  max_send_msg_size: 8388608
+  keepalive_permit_without_stream: true
+  compression_level: 9
+  reflection: true
+  stats: true
+  record_queries_file: /tmp/queries.jsonl
+storage:
+  snapshot_timer: 5
+  backup_keep_daily: 7
+  backup_keep_weekly: 4
+  full_backup_interval: 6
+  engine: memory
+  shadow_engine: memory
+  write_batch_size: 64
+  write_batch_delay: 500us
+  stats_interval: 30m
+  max_regex_program: 500
+  regex_scan_budget: 50ms
+  scan_cache_ttl: 2s
+  read_view_interval: 250ms
+  background_write_rate: 1048576
+  min_free_disk_bytes: 2048
+  reject_writes_on_low_disk: true
+  max_wal_backlog: 1000
+  max_cdc_queue: 500
+  maintenance_windows:
+    - "sat,sun 01:00-05:00"
+  families:
+    main:
+      max_age: 720h
+      max_versions: 3
+      default_ttl: 1h
+      max_rows: 50
+      compress: true
+      types:
+        wins: int64
+        active: bool
+  tables:
+    default:
+      max_bytes: 4096
+cdc:
+  port: 4000
+  spill_max_bytes: 1048576
+  node_id: node-a
+  old_values: true
+  durable: true
+  outbox_size: 500
+  families: [main]
+  buffer_size: 100
+  overflow_policy: drop_oldest
+logging:
+  debug: true
+`,
+			check: func(r *require.Assertions, cfg *Config) {
+				r.Equal("127.0.0.1", cfg.GRPCServer.Address)
+				r.Equal(9000, cfg.Server.Port)
+				r.Equal(9090, cfg.GRPCServer.Port)
+				r.Equal(10, cfg.GRPCServer.Limits.MaxQualifiers)
+				r.Equal(1<<20, cfg.GRPCServer.Limits.MaxResponseSize)
+				r.Equal(2*time.Minute, cfg.GRPCServer.Limits.CostWindow)
+				r.Equal(map[string]grpc.ClientQuota{"reports": {MaxCost: 5000}},
+					cfg.GRPCServer.Limits.ClientQuotas)
+				r.Equal(16<<20, cfg.GRPCServer.Transport.MaxRecvMsgSize)
+				r.Equal(8<<20, cfg.GRPCServer.Transport.MaxSendMsgSize)
+				r.True(cfg.GRPCServer.Transport.KeepalivePermitWithoutStream)
+				r.Equal(9, cfg.GRPCServer.Transport.CompressionLevel)
+				r.True(cfg.GRPCServer.Reflection)
+				r.False(cfg.GRPCServer.Channelz)
+				r.True(cfg.GRPCServer.Stats)
+				r.Equal("/tmp/queries.jsonl", cfg.RecordQueriesFile)
+				r.Equal(0.01, cfg.RecordQueriesRate)
+				r.Equal(5, cfg.SnapshotTimer)
+				r.Equal(shard_storage.BackupRetention{KeepDaily: 7, KeepWeekly: 4},
+					cfg.BackupRetention)
+				r.Equal(6, cfg.FullBackupInterval)
+				r.Equal("memory", cfg.StorageEngine)
+				r.Equal("memory", cfg.ShadowEngine)
+				r.Equal(64, cfg.WriteBatchSize)
+				r.Equal(500*time.Microsecond, cfg.WriteBatchDelay)
+				r.Equal(30*time.Minute, cfg.StatsInterval)
+				r.Equal(500, cfg.MaxRegexProgram)
+				r.Equal(50*time.Millisecond, cfg.RegexScanBudget)
+				r.Equal(2*time.Second, cfg.ScanCacheTTL)
+				r.Equal(250*time.Millisecond, cfg.ReadViewInterval)
+				r.Equal(1<<20, cfg.BackgroundWriteRate)
+				r.Equal(2048, cfg.MinFreeDiskBytes)
+				r.True(cfg.RejectWritesOnLowDisk)
+				r.Equal(1000, cfg.MaxWALBacklog)
+				r.Zero(cfg.MaxSnapshotBacklog)
+				r.Equal(500, cfg.MaxCDCQueue)
+				r.Zero(cfg.MaxReaperQueue)
+				r.Equal(shard_storage.MaintenanceWindows{{
+					Days:   []time.Weekday{time.Saturday, time.Sunday},
+					Start:  time.Hour,
+					Length: 4 * time.Hour,
+				}}, cfg.MaintenanceWindows)
+				r.Equal(720*time.Hour, cfg.FamilyPolicies["main"].MaxAge)
+				r.Equal(3, cfg.FamilyPolicies["main"].MaxVersions)
+				r.Equal(time.Hour, cfg.FamilyPolicies["main"].DefaultTTL)
+				r.Equal(shard_storage.Quota{MaxRows: 50}, cfg.FamilyPolicies["main"].Quota)
+				r.True(cfg.FamilyPolicies["main"].Compress)
+				r.Equal(map[string]shard_storage.ValueType{
+					"wins":   shard_storage.ValueInt64,
+					"active": shard_storage.ValueBool,
+				}, cfg.FamilyPolicies["main"].Types)
+				r.Equal(map[string]shard_storage.Quota{"default": {MaxBytes: 4096}},
+					cfg.TableQuotas)
+				r.Equal(4000, cfg.CDC.Port)
+				r.Equal(1<<20, cfg.CDC.SpillMaxBytes)
+				r.Equal("node-a", cfg.CDC.NodeID)
+				r.True(cfg.CDCOldValues)
+				r.True(cfg.CDCDurable)
+				r.Equal(500, cfg.CDCOutboxSize)
+				r.Equal([]string{"main"}, cfg.CDC.Families)
+				r.False(cfg.CDC.Disabled)
+				r.Equal(100, cfg.CDC.BufferSize)
+				r.Equal("drop_oldest", cfg.CDC.OverflowPolicy)
+				r.True(cfg.Debug)
+			},
+		},
+		"empty file uses defaults": {
+			contents: "",
+			check: func(r *require.Assertions, cfg *Config) {
+				r.Equal(defaultServerAddress, cfg.GRPCServer.Address)
+				r.Equal(defaultServerPort, cfg.Server.Port)
+				r.Equal(defaultSnapshotTimer, cfg.SnapshotTimer)
+				r.Equal(defaultStorageEngine, cfg.StorageEngine)
+			},
+		},
+		"out of range": {
+			contents: "storage:\n  snapshot_timer: -1\n",
+			wantErr:  "storage.snapshot_timer must be between 1 and 3600, got -1",
+		},
+		"unknown storage engine": {
+			contents: "storage:\n  engine: tape\n",
+			wantErr:  `storage.engine must be one of memory, got "tape"`,
+		},
+		"invalid keepalive min time": {
+			contents: "grpc:\n  keepalive_min_time: often\n",
+			wantErr:  "invalid grpc.keepalive_min_time",
+		},
+		"invalid write batch delay": {
+			contents: "storage:\n  write_batch_delay: soon\n",
+			wantErr:  "invalid storage.write_batch_delay",
+		},
+		"invalid maintenance window": {
+			contents: "storage:\n  maintenance_windows: [\"someday 01:00-02:00\"]\n",
+			wantErr:  "invalid storage.maintenance_windows",
+		},
+		"unknown key": {
+			contents: "server:\n  prot: 9000\n",
+			wantErr:  "field prot not found",
+		},
+		"invalid max age": {
+			contents: "storage:\n  families:\n    main:\n      max_age: forever\n",
+			wantErr:  "invalid max age value for family main",
+		},
+		"unknown value type": {
+			contents: "storage:\n  families:\n    main:\n      types:\n        wins: integer\n",
+			wantErr: `storage.families.main.types.wins must be one of [int64 float64 bool], ` +
+				`got "integer"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(t, configFileName, tc.contents)
+
+			cfg, err := NewConfig([]string{"--config", path})
+			r := require.New(t)
+			if tc.wantErr != "" {
+				r.ErrorContains(err, tc.wantErr)
+				return
+			}
+			r.NoError(err)
+			tc.check(r, cfg)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/engine"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/server/grpc"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Defaults for settings left unset. They match the template written by Bootstrap.
+const (
+	defaultServerAddress          = "127.0.0.1"
+	defaultServerPort             = 8080
+	defaultRPCPort                = 9443
+	defaultSnapshotTimer          = 5
+	defaultBackupTimer            = 60
+	defaultMaxSnapshotLimit       = 10
+	defaultGarbageCollectionTimer = 10
+	defaultStorageEngine          = engine.Memory
+	defaultRecordQueriesRate      = 0.01
+)
+
+// bound is the accepted range of an integer setting.
+type bound struct {
+	key      string
+	value    int
+	min, max int
+}
+
+func (b bound) check() error {
+	if b.value < b.min || b.value > b.max {
+		return fmt.Errorf("%s must be between %d and %d, got %d", b.key, b.min, b.max, b.value)
+	}
+	return nil
+}
+
+// applyDefaults fills in every setting that was left at its zero value.
+func (c *Config) applyDefaults() {
+	if c.Server.Address == "" {
+		c.Server.Address = defaultServerAddress
+	}
+	if c.GRPCServer.Address == "" {
+		c.GRPCServer.Address = c.Server.Address
+	}
+	if c.Server.Port == 0 {
+		c.Server.Port = defaultServerPort
+	}
+	if c.GRPCServer.Port == 0 {
+		c.GRPCServer.Port = defaultRPCPort
+	}
+	if c.StorageEngine == "" {
+		c.StorageEngine = defaultStorageEngine
+	}
+	if c.SnapshotTimer == 0 {
+		c.SnapshotTimer = defaultSnapshotTimer
+	}
+	if c.BackupTimer == 0 {
+		c.BackupTimer = defaultBackupTimer
+	}
+	if c.MaxSnapshotLimit == 0 {
+		c.MaxSnapshotLimit = defaultMaxSnapshotLimit
+	}
+	if c.GarbageCollectionTimer == 0 {
+		c.GarbageCollectionTimer = defaultGarbageCollectionTimer
+	}
+	if c.RecordQueriesFile != "" && c.RecordQueriesRate == 0 {
+		c.RecordQueriesRate = defaultRecordQueriesRate
+	}
+}
+
+// Validate reports every setting the server cannot start with, naming the config key and the
+// accepted range. Request limits and the CDC port, spill size, buffer size and outbox size may be
+// zero to use their component defaults.
+func (c *Config) Validate() error {
+	bounds := []bound{
+		{key: "server.port", value: c.Server.Port, min: 1, max: 65535},
+		{key: "server.rpc_port", value: c.GRPCServer.Port, min: 1, max: 65535},
+		{key: "cdc.port", value: c.CDC.Port, min: 0, max: 65535},
+		{key: "cdc.spill_max_bytes", value: c.CDC.SpillMaxBytes, min: 0, max: 1 << 40},
+		{key: "cdc.buffer_size", value: c.CDC.BufferSize, min: 0, max: 1 << 20},
+		{key: "cdc.outbox_size", value: c.CDCOutboxSize, min: 0, max: 1 << 24},
+		{key: "storage.snapshot_timer", value: c.SnapshotTimer, min: 1, max: 3600},
+		{key: "storage.backup_timer", value: c.BackupTimer, min: 1, max: 86400},
+		{key: "storage.max_snapshot_limit", value: c.MaxSnapshotLimit, min: 1, max: 50},
+		{key: "storage.backup_retention_days", value: c.BackupRetention.Days, min: 0, max: 3650},
+		{key: "storage.backup_keep_daily", value: c.BackupRetention.KeepDaily, min: 0, max: 3650},
+		{key: "storage.backup_keep_weekly", value: c.BackupRetention.KeepWeekly, min: 0,
+			max: 520},
+		{key: "storage.write_batch_size", value: c.WriteBatchSize, min: 0, max: 4096},
+		{key: "storage.full_backup_interval", value: c.FullBackupInterval, min: 0, max: 1000},
+		{key: "storage.garbage_collection_timer", value: c.GarbageCollectionTimer, min: 1,
+			max: 86400},
+		{key: "storage.max_regex_program", value: c.MaxRegexProgram, min: 0, max: 1 << 20},
+		{key: "storage.background_write_rate", value: c.BackgroundWriteRate, min: 0,
+			max: 1 << 40},
+		{key: "storage.min_free_disk_bytes", value: c.MinFreeDiskBytes, min: 0, max: 1 << 50},
+		{key: "storage.max_wal_backlog", value: c.MaxWALBacklog, min: 0, max: 1 << 30},
+		{key: "storage.max_snapshot_backlog", value: c.MaxSnapshotBacklog, min: 0, max: 1 << 30},
+		{key: "storage.max_cdc_queue", value: c.MaxCDCQueue, min: 0, max: 1 << 30},
+		{key: "storage.max_reaper_queue", value: c.MaxReaperQueue, min: 0, max: 1 << 30},
+		{key: "grpc.max_row_key_length", value: c.GRPCServer.Limits.MaxRowKeyLength, min: 0,
+			max: 1 << 16},
+		{key: "grpc.max_qualifiers", value: c.GRPCServer.Limits.MaxQualifiers, min: 0,
+			max: 1 << 16},
+		{key: "grpc.max_value_size", value: c.GRPCServer.Limits.MaxValueSize, min: 0,
+			max: 64 << 20},
+		{key: "grpc.max_response_size", value: c.GRPCServer.Limits.MaxResponseSize, min: 0,
+			max: 1 << 30},
+		{key: "grpc.max_recv_msg_size", value: c.GRPCServer.Transport.MaxRecvMsgSize, min: 0,
+			max: 1 << 30},
+		{key: "grpc.max_send_msg_size", value: c.GRPCServer.Transport.MaxSendMsgSize, min: 0,
+			max: 1 << 30},
+		{key: "grpc.max_concurrent_streams", value: c.GRPCServer.Transport.MaxConcurrentStreams,
+			min: 0, max: 1 << 20},
+		{key: "grpc.compression_level", value: c.GRPCServer.Transport.CompressionLevel, min: 0,
+			max: 9},
+	}
+
+	var errGrp []error
+	if c.Server.Address == "" {
+		errGrp = append(errGrp, fmt.Errorf("server.address is required"))
+	}
+	for _, b := range bounds {
+		errGrp = append(errGrp, b.check())
+	}
+
+	if !slices.Contains(engine.Names, c.StorageEngine) {
+		errGrp = append(errGrp, fmt.Errorf("storage.engine must be one of %s, got %q",
+			strings.Join(engine.Names, ", "), c.StorageEngine))
+	}
+	if c.ShadowEngine != "" && !slices.Contains(engine.Names, c.ShadowEngine) {
+		errGrp = append(errGrp, fmt.Errorf("storage.shadow_engine must be one of %s, got %q",
+			strings.Join(engine.Names, ", "), c.ShadowEngine))
+	}
+
+	if policy := c.CDC.OverflowPolicy; policy != "" && !slices.Contains(v1.OverflowPolicies,
+		policy) {
+		errGrp = append(errGrp, fmt.Errorf("cdc.overflow_policy must be one of %s, got %q",
+			strings.Join(v1.OverflowPolicies, ", "), policy))
+	}
+
+	if c.SnapshotTimer > 0 && c.BackupTimer > 0 && c.BackupTimer < c.SnapshotTimer {
+		errGrp = append(errGrp, fmt.Errorf(
+			"storage.backup_timer (%d) must not be shorter than storage.snapshot_timer (%d)",
+			c.BackupTimer, c.SnapshotTimer))
+	}
+
+	// a value larger than the largest request could never be written
+	maxRecv := c.GRPCServer.Transport.MaxRecvMsgSize
+	if maxRecv == 0 {
+		maxRecv = grpc.DefaultMaxRecvMsgSize
+	}
+	if c.GRPCServer.Limits.MaxValueSize > maxRecv {
+		errGrp = append(errGrp, fmt.Errorf(
+			"grpc.max_value_size (%d) must not be larger than grpc.max_recv_msg_size (%d)",
+			c.GRPCServer.Limits.MaxValueSize, maxRecv))
+	}
+
+	if c.WriteBatchDelay < 0 || c.WriteBatchDelay > time.Second {
+		errGrp = append(errGrp, fmt.Errorf(
+			"storage.write_batch_delay must be between 0s and 1s, got %s", c.WriteBatchDelay))
+	}
+
+	if c.StatsInterval != 0 && c.StatsInterval < time.Minute {
+		errGrp = append(errGrp, fmt.Errorf("storage.stats_interval must be at least 1m, got %s",
+			c.StatsInterval))
+	}
+
+	if c.RegexScanBudget < 0 {
+		errGrp = append(errGrp, fmt.Errorf("storage.regex_scan_budget cannot be negative, got %s",
+			c.RegexScanBudget))
+	}
+
+	if c.ReadViewInterval != 0 && c.ReadViewInterval < 10*time.Millisecond {
+		errGrp = append(errGrp, fmt.Errorf(
+			"storage.read_view_interval must be at least 10ms, got %s", c.ReadViewInterval))
+	}
+
+	if c.ScanCacheTTL < 0 {
+		errGrp = append(errGrp, fmt.Errorf("storage.scan_cache_ttl cannot be negative, got %s",
+			c.ScanCacheTTL))
+	}
+
+	if c.RecordQueriesRate < 0 || c.RecordQueriesRate > 1 {
+		errGrp = append(errGrp, fmt.Errorf(
+			"grpc.record_queries_rate must be between 0 and 1, got %g", c.RecordQueriesRate))
+	}
+
+	if c.GRPCServer.Limits.CostWindow < 0 {
+		errGrp = append(errGrp, fmt.Errorf("grpc.cost_window cannot be negative, got %s",
+			c.GRPCServer.Limits.CostWindow))
+	}
+	for client, quota := range c.GRPCServer.Limits.ClientQuotas {
+		if quota.MaxCost < 0 {
+			errGrp = append(errGrp, fmt.Errorf(
+				"grpc.clients.%s.max_cost cannot be negative, got %d", client, quota.MaxCost))
+		}
+		if quota.AlertCost < 0 {
+			errGrp = append(errGrp, fmt.Errorf(
+				"grpc.clients.%s.alert_cost cannot be negative, got %d", client, quota.AlertCost))
+		}
+	}
+	if c.GRPCServer.Transport.KeepaliveMinTime < 0 {
+		errGrp = append(errGrp, fmt.Errorf("grpc.keepalive_min_time cannot be negative, got %s",
+			c.GRPCServer.Transport.KeepaliveMinTime))
+	}
+
+	if pattern := c.GRPCServer.Limits.FamilyNamePattern; pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errGrp = append(errGrp, fmt.Errorf("grpc.family_name_pattern is not a valid "+
+				"regular expression: %w", err))
+		}
+	}
+
+	for family, policy := range c.FamilyPolicies {
+		if policy.MaxAge < 0 {
+			errGrp = append(errGrp, fmt.Errorf(
+				"storage.families.%s.max_age cannot be negative, got %s", family, policy.MaxAge))
+		}
+		if policy.MaxVersions < 0 {
+			errGrp = append(errGrp, fmt.Errorf(
+				"storage.families.%s.max_versions cannot be negative, got %d", family,
+				policy.MaxVersions))
+		}
+		if policy.DefaultTTL < 0 || policy.DefaultTTL > litetable.MaxTTL {
+			errGrp = append(errGrp, fmt.Errorf(
+				"storage.families.%s.default_ttl must be between 0 and %s, got %s", family,
+				litetable.MaxTTL, policy.DefaultTTL))
+		}
+		errGrp = append(errGrp, validateQuota("storage.families."+family, policy.Quota)...)
+		for _, qualifier := range slices.Sorted(maps.Keys(policy.Types)) {
+			if t := policy.Types[qualifier]; !slices.Contains(shard_storage.ValueTypes, t) {
+				errGrp = append(errGrp, fmt.Errorf(
+					"storage.families.%s.types.%s must be one of %v, got %q", family, qualifier,
+					shard_storage.ValueTypes, string(t)))
+			}
+		}
+	}
+
+	for table, quota := range c.TableQuotas {
+		if !litetable.ValidTableName(table) {
+			errGrp = append(errGrp, fmt.Errorf("storage.tables.%s must match %s", table,
+				litetable.TableNamePattern()))
+		}
+		errGrp = append(errGrp, validateQuota("storage.tables."+table, quota)...)
+	}
+
+	return errors.Join(errGrp...)
+}
+
+func validateQuota(prefix string, quota shard_storage.Quota) []error {
+	var errGrp []error
+	if quota.MaxBytes < 0 {
+		errGrp = append(errGrp, fmt.Errorf("%s.max_bytes cannot be negative, got %d", prefix,
+			quota.MaxBytes))
+	}
+	if quota.MaxRows < 0 {
+		errGrp = append(errGrp, fmt.Errorf("%s.max_rows cannot be negative, got %d", prefix,
+			quota.MaxRows))
+	}
+	return errGrp
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"github.com/litetable/litetable-db/internal/server/grpc"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		modify  func(c *Config)
+		wantErr string
+	}{
+		"defaults are valid": {
+			modify: func(c *Config) {},
+		},
+		"ports out of range": {
+			modify: func(c *Config) {
+				c.Server.Port = -1
+				c.GRPCServer.Port = 70000
+			},
+			wantErr: "server.port must be between 1 and 65535, got -1\n" +
+				"server.rpc_port must be between 1 and 65535, got 70000",
+		},
+		"backup shorter than snapshot": {
+			modify: func(c *Config) {
+				c.SnapshotTimer = 30
+				c.BackupTimer = 10
+			},
+			wantErr: "storage.backup_timer (10) must not be shorter than " +
+				"storage.snapshot_timer (30)",
+		},
+		"snapshot limit": {
+			modify:  func(c *Config) { c.MaxSnapshotLimit = 51 },
+			wantErr: "storage.max_snapshot_limit must be between 1 and 50, got 51",
+		},
+		"negative backup retention": {
+			modify: func(c *Config) {
+				c.BackupRetention = shard_storage.BackupRetention{Days: -1, KeepWeekly: 600}
+			},
+			wantErr: "storage.backup_retention_days must be between 0 and 3650, got -1\n" +
+				"storage.backup_keep_weekly must be between 0 and 520, got 600",
+		},
+		"unknown storage engine": {
+			modify:  func(c *Config) { c.StorageEngine = "tape" },
+			wantErr: `storage.engine must be one of memory, got "tape"`,
+		},
+		"unknown shadow engine": {
+			modify:  func(c *Config) { c.ShadowEngine = "tape" },
+			wantErr: `storage.shadow_engine must be one of memory, got "tape"`,
+		},
+		"cdc queue": {
+			modify: func(c *Config) {
+				c.CDC.BufferSize = -1
+				c.CDC.OverflowPolicy = "discard"
+			},
+			wantErr: "cdc.buffer_size must be between 0 and 1048576, got -1\n" +
+				`cdc.overflow_policy must be one of block, drop_oldest, spill, got "discard"`,
+		},
+		"full backup interval": {
+			modify:  func(c *Config) { c.FullBackupInterval = -1 },
+			wantErr: "storage.full_backup_interval must be between 0 and 1000, got -1",
+		},
+		"write batching": {
+			modify: func(c *Config) {
+				c.WriteBatchSize = 5000
+				c.WriteBatchDelay = 2 * time.Second
+			},
+			wantErr: "storage.write_batch_size must be between 0 and 4096, got 5000\n" +
+				"storage.write_batch_delay must be between 0s and 1s, got 2s",
+		},
+		"backlog limits": {
+			modify: func(c *Config) {
+				c.MaxWALBacklog = -1
+				c.MaxReaperQueue = 1 << 31
+			},
+			wantErr: "storage.max_wal_backlog must be between 0 and 1073741824, got -1\n" +
+				"storage.max_reaper_queue must be between 0 and 1073741824, got 2147483648",
+		},
+		"record queries rate": {
+			modify: func(c *Config) {
+				c.RecordQueriesFile = "queries.jsonl"
+				c.RecordQueriesRate = 2
+			},
+			wantErr: "grpc.record_queries_rate must be between 0 and 1, got 2",
+		},
+		"stats interval": {
+			modify:  func(c *Config) { c.StatsInterval = time.Second },
+			wantErr: "storage.stats_interval must be at least 1m, got 1s",
+		},
+		"regex scans": {
+			modify: func(c *Config) {
+				c.MaxRegexProgram = -1
+				c.RegexScanBudget = -time.Second
+			},
+			wantErr: "storage.max_regex_program must be between 0 and 1048576, got -1\n" +
+				"storage.regex_scan_budget cannot be negative, got -1s",
+		},
+		"read view interval": {
+			modify:  func(c *Config) { c.ReadViewInterval = time.Millisecond },
+			wantErr: "storage.read_view_interval must be at least 10ms, got 1ms",
+		},
+		"negative scan cache ttl": {
+			modify:  func(c *Config) { c.ScanCacheTTL = -time.Second },
+			wantErr: "storage.scan_cache_ttl cannot be negative, got -1s",
+		},
+		"negative background write rate": {
+			modify:  func(c *Config) { c.BackgroundWriteRate = -1 },
+			wantErr: "storage.background_write_rate must be between 0 and 1099511627776, got -1",
+		},
+		"value larger than a request": {
+			modify: func(c *Config) { c.GRPCServer.Limits.MaxValueSize = 8 << 20 },
+			wantErr: "grpc.max_value_size (8388608) must not be larger than " +
+				"grpc.max_recv_msg_size (4194304)",
+		},
+		"larger requests for larger values": {
+			modify: func(c *Config) {
+				c.GRPCServer.Limits.MaxValueSize = 8 << 20
+				c.GRPCServer.Transport.MaxRecvMsgSize = 16 << 20
+			},
+		},
+		"grpc transport": {
+			modify: func(c *Config) {
+				c.GRPCServer.Transport.MaxSendMsgSize = -1
+				c.GRPCServer.Transport.MaxConcurrentStreams = 1 << 21
+				c.GRPCServer.Transport.KeepaliveMinTime = -time.Second
+			},
+			wantErr: "grpc.max_send_msg_size must be between 0 and 1073741824, got -1\n" +
+				"grpc.max_concurrent_streams must be between 0 and 1048576, got 2097152\n" +
+				"grpc.keepalive_min_time cannot be negative, got -1s",
+		},
+		"client quotas": {
+			modify: func(c *Config) {
+				c.GRPCServer.Limits.CostWindow = -time.Minute
+				c.GRPCServer.Limits.ClientQuotas = map[string]grpc.ClientQuota{
+					"reports": {MaxCost: -1},
+				}
+			},
+			wantErr: "grpc.cost_window cannot be negative, got -1m0s\n" +
+				"grpc.clients.reports.max_cost cannot be negative, got -1",
+		},
+		"family name pattern": {
+			modify:  func(c *Config) { c.GRPCServer.Limits.FamilyNamePattern = "[" },
+			wantErr: "grpc.family_name_pattern is not a valid regular expression",
+		},
+		"negative family policy": {
+			modify: func(c *Config) {
+				c.FamilyPolicies = map[string]shard_storage.FamilyPolicy{
+					"main": {MaxVersions: -1, DefaultTTL: -time.Second},
+				}
+			},
+			wantErr: "storage.families.main.max_versions cannot be negative, got -1\n" +
+				"storage.families.main.default_ttl must be between 0 and 876000h0m0s, got -1s",
+		},
+		"unknown value types": {
+			modify: func(c *Config) {
+				c.FamilyPolicies = map[string]shard_storage.FamilyPolicy{
+					"main": {Types: map[string]shard_storage.ValueType{
+						"wins": shard_storage.ValueInt64, "ratio": "double", "name": "string",
+					}},
+				}
+			},
+			wantErr: "storage.families.main.types.name must be one of [int64 float64 bool], " +
+				"got \"string\"\n" +
+				"storage.families.main.types.ratio must be one of [int64 float64 bool], " +
+				"got \"double\"",
+		},
+		"negative family quota": {
+			modify: func(c *Config) {
+				c.FamilyPolicies = map[string]shard_storage.FamilyPolicy{
+					"main": {Quota: shard_storage.Quota{MaxBytes: -1}},
+				}
+			},
+			wantErr: "storage.families.main.max_bytes cannot be negative, got -1",
+		},
+		"table quota": {
+			modify: func(c *Config) {
+				c.TableQuotas = map[string]shard_storage.Quota{
+					"../wwe": {MaxRows: -1},
+				}
+			},
+			wantErr: "storage.tables.../wwe must match ^[A-Za-z0-9_-]{1,64}$\n" +
+				"storage.tables.../wwe.max_rows cannot be negative, got -1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.applyDefaults()
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/server/grpc"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"time"
+)
+
+// fileConfig is the schema of litetable.yaml. Unknown keys are rejected so a typo cannot
+// silently fall back to a default.
+//
+//	server:
+//	  address: 127.0.0.1
+//	  port: 8080
+//	  rpc_port: 9443
+//	grpc:
+//	  max_row_key_length: 4096
+//	  max_recv_msg_size: 16777216
+//	storage:
+//	  snapshot_timer: 5
+//	  write_batch_size: 64
+//	  write_batch_delay: 200us
+//	  families:
+//	    wrestlers:
+//	      max_age: 720h
+//	      max_versions: 5
+//	      default_ttl: 24h
+//	      max_bytes: 1073741824
+//	      compress: true
+//	      types:
+//	        wins: int64
+//	  tables:
+//	    default:
+//	      max_rows: 100000
+//	cdc:
+//	  port: 32473
+//	  spill_max_bytes: 67108864
+//	  node_id: litetable-1
+//	  old_values: true
+//	  durable: true
+//	  outbox_size: 100000
+//	  families: [wrestlers]
+//	  buffer_size: 10000
+//	  overflow_policy: spill
+//	logging:
+//	  debug: true
+type fileConfig struct {
+	Server struct {
+		Address string `yaml:"address"`
+		Port    int    `yaml:"port"`
+		RPCPort int    `yaml:"rpc_port"`
+	} `yaml:"server"`
+	GRPC struct {
+		MaxRowKeyLength   int    `yaml:"max_row_key_length"`
+		MaxQualifiers     int    `yaml:"max_qualifiers"`
+		MaxValueSize      int    `yaml:"max_value_size"`
+		FamilyNamePattern string `yaml:"family_name_pattern"`
+		MaxResponseSize   int    `yaml:"max_response_size"`
+		CostWindow        string `yaml:"cost_window"`
+		Clients           map[string]struct {
+			MaxCost   int64 `yaml:"max_cost"`
+			AlertCost int64 `yaml:"alert_cost"`
+		} `yaml:"clients"`
+
+		MaxRecvMsgSize               int    `yaml:"max_recv_msg_size"`
+		MaxSendMsgSize               int    `yaml:"max_send_msg_size"`
+		MaxConcurrentStreams         int    `yaml:"max_concurrent_streams"`
+		KeepaliveMinTime             string `yaml:"keepalive_min_time"`
+		KeepalivePermitWithoutStream bool   `yaml:"keepalive_permit_without_stream"`
+		CompressionLevel             int    `yaml:"compression_level"`
+
+		Reflection bool `yaml:"reflection"`
+		Channelz   bool `yaml:"channelz"`
+		Stats      bool `yaml:"stats"`
+
+		RecordQueriesFile string  `yaml:"record_queries_file"`
+		RecordQueriesRate float64 `yaml:"record_queries_rate"`
+	} `yaml:"grpc"`
+	Storage struct {
+		Engine                 string   `yaml:"engine"`
+		ShadowEngine           string   `yaml:"shadow_engine"`
+		WriteBatchSize         int      `yaml:"write_batch_size"`
+		WriteBatchDelay        string   `yaml:"write_batch_delay"`
+		BackupTimer            int      `yaml:"backup_timer"`
+		SnapshotTimer          int      `yaml:"snapshot_timer"`
+		MaxSnapshotLimit       int      `yaml:"max_snapshot_limit"`
+		BackupRetentionDays    int      `yaml:"backup_retention_days"`
+		BackupKeepDaily        int      `yaml:"backup_keep_daily"`
+		BackupKeepWeekly       int      `yaml:"backup_keep_weekly"`
+		FullBackupInterval     int      `yaml:"full_backup_interval"`
+		GarbageCollectionTimer int      `yaml:"garbage_collection_timer"`
+		StatsInterval          string   `yaml:"stats_interval"`
+		MaxRegexProgram        int      `yaml:"max_regex_program"`
+		RegexScanBudget        string   `yaml:"regex_scan_budget"`
+		ScanCacheTTL           string   `yaml:"scan_cache_ttl"`
+		ReadViewInterval       string   `yaml:"read_view_interval"`
+		MaintenanceWindows     []string `yaml:"maintenance_windows"`
+		BackgroundWriteRate    int      `yaml:"background_write_rate"`
+		MinFreeDiskBytes       int      `yaml:"min_free_disk_bytes"`
+		RejectWritesOnLowDisk  bool     `yaml:"reject_writes_on_low_disk"`
+		MaxWALBacklog          int      `yaml:"max_wal_backlog"`
+		MaxSnapshotBacklog     int      `yaml:"max_snapshot_backlog"`
+		MaxCDCQueue            int      `yaml:"max_cdc_queue"`
+		MaxReaperQueue         int      `yaml:"max_reaper_queue"`
+		Families               map[string]struct {
+			MaxAge      string `yaml:"max_age"`
+			MaxVersions int    `yaml:"max_versions"`
+			DefaultTTL  string `yaml:"default_ttl"`
+			MaxBytes    int64  `yaml:"max_bytes"`
+			MaxRows     int64  `yaml:"max_rows"`
+			Compress    bool   `yaml:"compress"`
+			// Types declares the type of the values of qualifiers, by qualifier
+			Types map[string]string `yaml:"types"`
+		} `yaml:"families"`
+		Tables map[string]struct {
+			MaxBytes int64 `yaml:"max_bytes"`
+			MaxRows  int64 `yaml:"max_rows"`
+		} `yaml:"tables"`
+	} `yaml:"storage"`
+	CDC struct {
+		Address        string   `yaml:"address"`
+		Port           int      `yaml:"port"`
+		SpillMaxBytes  int      `yaml:"spill_max_bytes"`
+		NodeID         string   `yaml:"node_id"`
+		OldValues      bool     `yaml:"old_values"`
+		Durable        bool     `yaml:"durable"`
+		OutboxSize     int      `yaml:"outbox_size"`
+		Disabled       bool     `yaml:"disabled"`
+		Families       []string `yaml:"families"`
+		BufferSize     int      `yaml:"buffer_size"`
+		OverflowPolicy string   `yaml:"overflow_policy"`
+	} `yaml:"cdc"`
+	Logging struct {
+		Debug            bool   `yaml:"debug"`
+		CloudEnvironment string `yaml:"cloud_environment"`
+	} `yaml:"logging"`
+}
+
+// loadYAML reads a YAML config file.
+func (c *Config) loadYAML(configPath string) error {
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("LiteTable is not installed or configuration file not found")
+		}
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+
+	var fc fileConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&fc); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", configPath, err)
+	}
+
+	c.Server.Address = fc.Server.Address
+	c.GRPCServer.Address = fc.Server.Address
+	c.Server.Port = fc.Server.Port
+	c.GRPCServer.Port = fc.Server.RPCPort
+
+	c.GRPCServer.Limits.MaxRowKeyLength = fc.GRPC.MaxRowKeyLength
+	c.GRPCServer.Limits.MaxQualifiers = fc.GRPC.MaxQualifiers
+	c.GRPCServer.Limits.MaxValueSize = fc.GRPC.MaxValueSize
+	c.GRPCServer.Limits.FamilyNamePattern = fc.GRPC.FamilyNamePattern
+	c.GRPCServer.Limits.MaxResponseSize = fc.GRPC.MaxResponseSize
+
+	c.GRPCServer.Transport.MaxRecvMsgSize = fc.GRPC.MaxRecvMsgSize
+	c.GRPCServer.Transport.MaxSendMsgSize = fc.GRPC.MaxSendMsgSize
+	c.GRPCServer.Transport.MaxConcurrentStreams = fc.GRPC.MaxConcurrentStreams
+	c.GRPCServer.Transport.KeepalivePermitWithoutStream = fc.GRPC.KeepalivePermitWithoutStream
+	c.GRPCServer.Transport.CompressionLevel = fc.GRPC.CompressionLevel
+	c.GRPCServer.Reflection = fc.GRPC.Reflection
+	c.GRPCServer.Channelz = fc.GRPC.Channelz
+	c.GRPCServer.Stats = fc.GRPC.Stats
+	c.RecordQueriesFile = fc.GRPC.RecordQueriesFile
+	c.RecordQueriesRate = fc.GRPC.RecordQueriesRate
+	if fc.GRPC.KeepaliveMinTime != "" {
+		minTime, err := time.ParseDuration(fc.GRPC.KeepaliveMinTime)
+		if err != nil {
+			return fmt.Errorf("invalid grpc.keepalive_min_time: %w", err)
+		}
+		c.GRPCServer.Transport.KeepaliveMinTime = minTime
+	}
+	if fc.GRPC.CostWindow != "" {
+		window, err := time.ParseDuration(fc.GRPC.CostWindow)
+		if err != nil {
+			return fmt.Errorf("invalid grpc.cost_window: %w", err)
+		}
+		c.GRPCServer.Limits.CostWindow = window
+	}
+	for client, quota := range fc.GRPC.Clients {
+		if c.GRPCServer.Limits.ClientQuotas == nil {
+			c.GRPCServer.Limits.ClientQuotas = make(map[string]grpc.ClientQuota)
+		}
+		c.GRPCServer.Limits.ClientQuotas[client] = grpc.ClientQuota{
+			MaxCost:   quota.MaxCost,
+			AlertCost: quota.AlertCost,
+		}
+	}
+
+	c.StorageEngine = fc.Storage.Engine
+	c.ShadowEngine = fc.Storage.ShadowEngine
+	c.WriteBatchSize = fc.Storage.WriteBatchSize
+	if fc.Storage.WriteBatchDelay != "" {
+		delay, err := time.ParseDuration(fc.Storage.WriteBatchDelay)
+		if err != nil {
+			return fmt.Errorf("invalid storage.write_batch_delay: %w", err)
+		}
+		c.WriteBatchDelay = delay
+	}
+	c.BackupTimer = fc.Storage.BackupTimer
+	c.SnapshotTimer = fc.Storage.SnapshotTimer
+	c.MaxSnapshotLimit = fc.Storage.MaxSnapshotLimit
+	c.BackupRetention = shard_storage.BackupRetention{
+		Days:       fc.Storage.BackupRetentionDays,
+		KeepDaily:  fc.Storage.BackupKeepDaily,
+		KeepWeekly: fc.Storage.BackupKeepWeekly,
+	}
+	c.FullBackupInterval = fc.Storage.FullBackupInterval
+	c.GarbageCollectionTimer = fc.Storage.GarbageCollectionTimer
+	if fc.Storage.StatsInterval != "" {
+		interval, err := time.ParseDuration(fc.Storage.StatsInterval)
+		if err != nil {
+			return fmt.Errorf("invalid storage.stats_interval: %w", err)
+		}
+		c.StatsInterval = interval
+	}
+	c.MaxRegexProgram = fc.Storage.MaxRegexProgram
+	if fc.Storage.RegexScanBudget != "" {
+		budget, err := time.ParseDuration(fc.Storage.RegexScanBudget)
+		if err != nil {
+			return fmt.Errorf("invalid storage.regex_scan_budget: %w", err)
+		}
+		c.RegexScanBudget = budget
+	}
+	if fc.Storage.ScanCacheTTL != "" {
+		ttl, err := time.ParseDuration(fc.Storage.ScanCacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid storage.scan_cache_ttl: %w", err)
+		}
+		c.ScanCacheTTL = ttl
+	}
+	if fc.Storage.ReadViewInterval != "" {
+		interval, err := time.ParseDuration(fc.Storage.ReadViewInterval)
+		if err != nil {
+			return fmt.Errorf("invalid storage.read_view_interval: %w", err)
+		}
+		c.ReadViewInterval = interval
+	}
+	c.BackgroundWriteRate = fc.Storage.BackgroundWriteRate
+	c.MinFreeDiskBytes = fc.Storage.MinFreeDiskBytes
+	c.RejectWritesOnLowDisk = fc.Storage.RejectWritesOnLowDisk
+	c.MaxWALBacklog = fc.Storage.MaxWALBacklog
+	c.MaxSnapshotBacklog = fc.Storage.MaxSnapshotBacklog
+	c.MaxCDCQueue = fc.Storage.MaxCDCQueue
+	c.MaxReaperQueue = fc.Storage.MaxReaperQueue
+	c.MaintenanceWindows, err = parseMaintenanceWindows(fc.Storage.MaintenanceWindows)
+	if err != nil {
+		return fmt.Errorf("invalid storage.maintenance_windows: %w", err)
+	}
+	for family, rule := range fc.Storage.Families {
+		policy := shard_storage.FamilyPolicy{
+			MaxVersions: rule.MaxVersions,
+			Quota:       shard_storage.Quota{MaxBytes: rule.MaxBytes, MaxRows: rule.MaxRows},
+			Compress:    rule.Compress,
+		}
+		for qualifier, t := range rule.Types {
+			if policy.Types == nil {
+				policy.Types = make(map[string]shard_storage.ValueType)
+			}
+			policy.Types[qualifier] = shard_storage.ValueType(t)
+		}
+		if rule.MaxAge != "" {
+			policy.MaxAge, err = time.ParseDuration(rule.MaxAge)
+			if err != nil {
+				return fmt.Errorf("invalid max age value for family %s: %w", family, err)
+			}
+		}
+		if rule.DefaultTTL != "" {
+			policy.DefaultTTL, err = time.ParseDuration(rule.DefaultTTL)
+			if err != nil {
+				return fmt.Errorf("invalid default ttl value for family %s: %w", family, err)
+			}
+		}
+		if c.FamilyPolicies == nil {
+			c.FamilyPolicies = make(map[string]shard_storage.FamilyPolicy)
+		}
+		c.FamilyPolicies[family] = policy
+	}
+	for table, quota := range fc.Storage.Tables {
+		if c.TableQuotas == nil {
+			c.TableQuotas = make(map[string]shard_storage.Quota)
+		}
+		c.TableQuotas[table] = shard_storage.Quota{
+			MaxBytes: quota.MaxBytes,
+			MaxRows:  quota.MaxRows,
+		}
+	}
+
+	c.CDC.Address = fc.CDC.Address
+	c.CDC.Port = fc.CDC.Port
+	c.CDC.SpillMaxBytes = fc.CDC.SpillMaxBytes
+	c.CDC.NodeID = fc.CDC.NodeID
+	c.CDCOldValues = fc.CDC.OldValues
+	c.CDCDurable = fc.CDC.Durable
+	c.CDCOutboxSize = fc.CDC.OutboxSize
+	c.CDC.Disabled = fc.CDC.Disabled
+	c.CDC.Families = fc.CDC.Families
+	c.CDC.BufferSize = fc.CDC.BufferSize
+	c.CDC.OverflowPolicy = fc.CDC.OverflowPolicy
+
+	c.Debug = fc.Logging.Debug
+	c.CloudEnvironment = fc.Logging.CloudEnvironment
+
+	return nil
+}
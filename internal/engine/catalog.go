@@ -0,0 +1,350 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/app"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+const (
+	// tablesDir holds a directory for every table in the catalog.
+	tablesDir = "tables"
+	// tablesFile lists the tables in the catalog.
+	tablesFile = "tables.json"
+)
+
+var tableCount = metrics.NewGauge("litetable_tables",
+	"Tables in the catalog, not counting the default table.")
+
+// Opener opens the storage engine of a table that keeps its data in dir, along with the
+// background dependencies that maintain it.
+type Opener func(table, dir string) (StorageEngine, []app.Dependency, error)
+
+// CatalogConfig configures the table catalog.
+type CatalogConfig struct {
+	// Dir is the data directory. Every table keeps its data in Dir/tables/<name>.
+	Dir string
+	// Open opens the storage engine of a table.
+	Open Opener
+}
+
+func (c *CatalogConfig) validate() error {
+	var errGrp []error
+	if c.Dir == "" {
+		errGrp = append(errGrp, errors.New("catalog directory cannot be empty"))
+	}
+	if c.Open == nil {
+		errGrp = append(errGrp, errors.New("catalog opener cannot be nil"))
+	}
+	return errors.Join(errGrp...)
+}
+
+// Catalog holds the tables created with CreateTable. Every table is an isolated keyspace with
+// its own storage engine, column families and backups, so dropping a table removes its data
+// without touching any other table. The default table is not in the catalog.
+type Catalog struct {
+	dir  string
+	open Opener
+
+	mutex  sync.RWMutex
+	tables map[string]*table
+}
+
+// table is an open table and the dependencies that maintain it.
+type table struct {
+	storage StorageEngine
+	deps    []app.Dependency
+}
+
+// NewCatalog creates a catalog. Tables are opened when the catalog starts.
+func NewCatalog(cfg *CatalogConfig) (*Catalog, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Catalog{
+		dir:    cfg.Dir,
+		open:   cfg.Open,
+		tables: make(map[string]*table),
+	}, nil
+}
+
+// Start opens and starts every table in the catalog.
+func (c *Catalog) Start() error {
+	names, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, name := range names {
+		t, err := c.openTable(name)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to open table %s: %w", name, err),
+				c.stopTables())
+		}
+		c.tables[name] = t
+	}
+	tableCount.Set(float64(len(c.tables)))
+	return nil
+}
+
+// Stop stops every table, flushing its data like any other storage engine.
+func (c *Catalog) Stop() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stopTables()
+}
+
+func (c *Catalog) Name() string {
+	return "Table Catalog"
+}
+
+// Table returns the storage engine of a table.
+func (c *Catalog) Table(name string) (StorageEngine, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	t, ok := c.tables[name]
+	if !ok {
+		return nil, litetable.NewError(litetable.ErrorCodeNotFound, "table %s does not exist",
+			name)
+	}
+	return t.storage, nil
+}
+
+// ListTables returns the names of the tables in the catalog, sorted.
+func (c *Catalog) ListTables() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	names := make([]string, 0, len(c.tables))
+	for name := range c.tables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// CreateTable opens a new table with the given column families.
+func (c *Catalog) CreateTable(name string, families []string) error {
+	if name == litetable.DefaultTable {
+		return litetable.NewError(litetable.ErrorCodeConflict, "table %s already exists", name)
+	}
+	if !litetable.ValidTableName(name) {
+		return litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"table name must match %s, got %q", litetable.TableNamePattern(), name)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, exists := c.tables[name]; exists {
+		return litetable.NewError(litetable.ErrorCodeConflict, "table %s already exists", name)
+	}
+
+	t, err := c.openTable(name)
+	if err != nil {
+		return litetable.WrapError(litetable.ErrorCodeInternal, err,
+			"failed to open table %s", name)
+	}
+	if len(families) > 0 {
+		if err = t.storage.UpdateFamilies(families); err != nil {
+			return errors.Join(litetable.WrapError(litetable.ErrorCodeInternal, err,
+				"failed to create families of table %s", name), c.removeTable(name, t))
+		}
+	}
+
+	c.tables[name] = t
+	if err = c.save(); err != nil {
+		delete(c.tables, name)
+		return errors.Join(litetable.WrapError(litetable.ErrorCodeInternal, err,
+			"failed to save table catalog"), c.removeTable(name, t))
+	}
+	tableCount.Set(float64(len(c.tables)))
+	return nil
+}
+
+// DropTable stops a table and deletes its data. The data cannot be recovered.
+func (c *Catalog) DropTable(name string) error {
+	if name == litetable.DefaultTable {
+		return litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"the %s table cannot be dropped", name)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	t, ok := c.tables[name]
+	if !ok {
+		return litetable.NewError(litetable.ErrorCodeNotFound, "table %s does not exist",
+			name)
+	}
+
+	// the table leaves the catalog first, so a crash never brings back a half-deleted table
+	delete(c.tables, name)
+	if err := c.save(); err != nil {
+		c.tables[name] = t
+		return litetable.WrapError(litetable.ErrorCodeInternal, err,
+			"failed to save table catalog")
+	}
+	tableCount.Set(float64(len(c.tables)))
+
+	if err := c.removeTable(name, t); err != nil {
+		return litetable.WrapError(litetable.ErrorCodeInternal, err,
+			"failed to remove table %s", name)
+	}
+	return nil
+}
+
+// Flush flushes every table.
+func (c *Catalog) Flush() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var errGrp []error
+	for name, t := range c.tables {
+		if err := t.storage.Flush(); err != nil {
+			errGrp = append(errGrp, fmt.Errorf("failed to flush table %s: %w", name, err))
+		}
+	}
+	return errors.Join(errGrp...)
+}
+
+// SetTimers changes the snapshot, backup and garbage collection intervals of every table whose
+// engine has them.
+func (c *Catalog) SetTimers(snapshot, backup, gc int) error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var errGrp []error
+	for name, t := range c.tables {
+		timers, ok := t.storage.(interface {
+			SetTimers(snapshot, backup, gc int) error
+		})
+		if !ok {
+			continue
+		}
+		if err := timers.SetTimers(snapshot, backup, gc); err != nil {
+			errGrp = append(errGrp, fmt.Errorf("table %s: %w", name, err))
+		}
+	}
+	return errors.Join(errGrp...)
+}
+
+func (c *Catalog) tableDir(name string) string {
+	return filepath.Join(c.dir, tablesDir, name)
+}
+
+// openTable opens and starts a table. Nothing is left running if it fails.
+func (c *Catalog) openTable(name string) (*table, error) {
+	storage, deps, err := c.open(name, c.tableDir(name))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &table{storage: storage}
+	if err = storage.Start(); err != nil {
+		return nil, err
+	}
+	for _, dep := range deps {
+		if err = dep.Start(); err != nil {
+			return nil, errors.Join(err, t.stop())
+		}
+		t.deps = append(t.deps, dep)
+	}
+	return t, nil
+}
+
+// stop stops the dependencies of a table, then its storage engine.
+func (t *table) stop() error {
+	var errGrp []error
+	for i := len(t.deps) - 1; i >= 0; i-- {
+		if err := t.deps[i].Stop(); err != nil {
+			errGrp = append(errGrp, err)
+		}
+	}
+	if err := t.storage.Stop(); err != nil {
+		errGrp = append(errGrp, err)
+	}
+	return errors.Join(errGrp...)
+}
+
+// removeTable stops a table and deletes its directory.
+func (c *Catalog) removeTable(name string, t *table) error {
+	return errors.Join(t.stop(), os.RemoveAll(c.tableDir(name)))
+}
+
+func (c *Catalog) stopTables() error {
+	var errGrp []error
+	for name, t := range c.tables {
+		if err := t.stop(); err != nil {
+			errGrp = append(errGrp, fmt.Errorf("failed to stop table %s: %w", name, err))
+		}
+	}
+	clear(c.tables)
+	tableCount.Set(0)
+	return errors.Join(errGrp...)
+}
+
+// TableDirs returns the data directory of every table in the catalog under dir by name, without
+// opening any of them.
+func TableDirs(dir string) (map[string]string, error) {
+	c := &Catalog{dir: dir}
+	names, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	dirs := make(map[string]string, len(names))
+	for _, name := range names {
+		dirs[name] = c.tableDir(name)
+	}
+	return dirs, nil
+}
+
+// load reads the table names from the catalog file. A missing file is an empty catalog.
+func (c *Catalog) load() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, tablesDir, tablesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table catalog: %w", err)
+	}
+
+	var names []string
+	if err = json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to decode table catalog: %w", err)
+	}
+	return names, nil
+}
+
+// save writes the table names to the catalog file, replacing it atomically.
+func (c *Catalog) save() error {
+	names := make([]string, 0, len(c.tables))
+	for name := range c.tables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	data, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(c.dir, tablesDir)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, tablesFile+".tmp")
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, tablesFile))
+}
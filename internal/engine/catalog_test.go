@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"github.com/litetable/litetable-db/internal/app"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCatalog creates a catalog of memory tables in dir.
+func newTestCatalog(t *testing.T, dir string) *Catalog {
+	c, err := NewCatalog(&CatalogConfig{
+		Dir: dir,
+		Open: func(_, dir string) (StorageEngine, []app.Dependency, error) {
+			return Open(&Config{Memory: &shard_storage.Config{
+				RootDir:        dir,
+				FlushThreshold: 60,
+				SnapshotTimer:  5,
+				CDCEmitter:     fakeCDC{},
+			}})
+		},
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewCatalog(t *testing.T) {
+	_, err := NewCatalog(&CatalogConfig{})
+	require.EqualError(t, err,
+		"catalog directory cannot be empty\ncatalog opener cannot be nil")
+}
+
+func TestCatalog(t *testing.T) {
+	req := require.New(t)
+	dir := t.TempDir()
+	c := newTestCatalog(t, dir)
+	req.NoError(c.Start())
+
+	req.NoError(c.CreateTable("wwe", []string{"wrestlers"}))
+	req.NoError(c.CreateTable("aew", nil))
+	req.Equal([]string{"aew", "wwe"}, c.ListTables())
+
+	wwe, err := c.Table("wwe")
+	req.NoError(err)
+	req.True(wwe.IsFamilyAllowed("wrestlers"))
+	req.NoError(wwe.Apply("champ:1", "wrestlers", []string{"name"}, [][]byte{[]byte("John")},
+		time.Now().UnixNano(), 0))
+
+	// tables are isolated keyspaces with their own families
+	aew, err := c.Table("aew")
+	req.NoError(err)
+	req.False(aew.IsFamilyAllowed("wrestlers"))
+	_, found := aew.GetRowByFamily("champ:1", "wrestlers")
+	req.False(found)
+
+	// tables and their data survive a restart
+	req.NoError(c.Stop())
+	c = newTestCatalog(t, dir)
+	req.NoError(c.Start())
+	req.Equal([]string{"aew", "wwe"}, c.ListTables())
+	wwe, err = c.Table("wwe")
+	req.NoError(err)
+	data, found := wwe.GetRowByFamily("champ:1", "wrestlers")
+	req.True(found)
+	req.Equal([]byte("John"), (*data)["champ:1"]["wrestlers"]["name"][0].Value)
+
+	req.NoError(c.DropTable("wwe"))
+	req.Equal([]string{"aew"}, c.ListTables())
+	_, err = c.Table("wwe")
+	req.ErrorIs(err, litetable.ErrNotFound)
+	_, err = os.Stat(filepath.Join(dir, tablesDir, "wwe"))
+	req.ErrorIs(err, os.ErrNotExist)
+
+	req.NoError(c.Stop())
+	c = newTestCatalog(t, dir)
+	req.NoError(c.Start())
+	req.Equal([]string{"aew"}, c.ListTables())
+	req.NoError(c.Stop())
+
+	// the directories of the tables are found without opening them
+	dirs, err := TableDirs(dir)
+	req.NoError(err)
+	req.Equal(map[string]string{"aew": filepath.Join(dir, tablesDir, "aew")}, dirs)
+}
+
+func TestCatalog_errors(t *testing.T) {
+	c := newTestCatalog(t, t.TempDir())
+	require.NoError(t, c.Start())
+	t.Cleanup(func() { require.NoError(t, c.Stop()) })
+	require.NoError(t, c.CreateTable("wwe", nil))
+
+	tests := map[string]struct {
+		run     func() error
+		wantErr error
+	}{
+		"create the default table": {
+			run:     func() error { return c.CreateTable(litetable.DefaultTable, nil) },
+			wantErr: litetable.ErrConflict,
+		},
+		"create an existing table": {
+			run:     func() error { return c.CreateTable("wwe", nil) },
+			wantErr: litetable.ErrConflict,
+		},
+		"invalid name": {
+			run:     func() error { return c.CreateTable("../wwe", nil) },
+			wantErr: litetable.ErrInvalidArgument,
+		},
+		"drop the default table": {
+			run:     func() error { return c.DropTable(litetable.DefaultTable) },
+			wantErr: litetable.ErrInvalidArgument,
+		},
+		"drop a missing table": {
+			run:     func() error { return c.DropTable("aew") },
+			wantErr: litetable.ErrNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, tc.run(), tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package engine selects the storage engine that holds table data. The operations manager reads
+// and writes through the StorageEngine interface, so an engine can keep rows in memory with
+// periodic backups or, in a durability-first deployment, in an embedded store on disk.
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/app"
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Memory is the in-memory sharded engine, persisted through snapshots and backups. It is the
+// default.
+const Memory = "memory"
+
+// Names are the engines that can be selected with storage.engine.
+var Names = []string{Memory}
+
+// StorageEngine is the storage behind reads and writes. It is started and stopped with the
+// application like any other dependency.
+type StorageEngine interface {
+	app.Dependency
+
+	// Apply writes the values of the qualifiers at timestamp. A non-zero expiresAt is a TTL.
+	Apply(rowKey, family string, qualifiers []string, values [][]byte, timestamp int64,
+		expiresAt int64) error
+	// Delete places tombstones on the qualifiers, or the whole family without qualifiers.
+	Delete(key, family string, qualifiers []string, timestamp int64, expiresAt int64) error
+
+	// GetRowByFamily reads a single row.
+	GetRowByFamily(key, family string) (*litetable.Data, bool)
+	// FilterRowsByPrefix and FilterRowsByRegex scan for the page of rows with a matching key,
+	// and return the number of row keys examined. A regex scan fails when the pattern is too
+	// complex or the scan runs over its time budget.
+	FilterRowsByPrefix(prefix string, page litetable.ScanPage) (*litetable.Data,
+		litetable.ScanStats, bool)
+	FilterRowsByRegex(regex string, page litetable.ScanPage) (*litetable.Data,
+		litetable.ScanStats, bool, error)
+	// ScanCached returns the cached rows of a prefix read, or runs scan and may cache its rows
+	// when scan reports it read every row of the prefix. Engines without a scan cache always run
+	// scan.
+	ScanCached(prefix, family string, latest int,
+		scan func() (map[string]*litetable.Row, bool, error)) (map[string]*litetable.Row, error)
+	// CachesScans reports whether ScanCached keeps the rows of prefix reads.
+	CachesScans() bool
+	// ReadView returns a reader that never waits on writes, at the cost of missing the latest
+	// of them, or false when the engine has none.
+	ReadView() (litetable.RowReader, bool)
+
+	IsFamilyAllowed(family string) bool
+	UpdateFamilies(families []string) error
+	// GetFamilies returns the column families of the table.
+	GetFamilies() []string
+	// CloneFamily creates the family target holding a copy of the rows of the family source,
+	// and returns the number of rows and cells copied.
+	CloneFamily(source, target string) (int, int, error)
+	// ExpiresAt returns when a value of the family written at timestamp with expiresAt expires,
+	// which for a value without a TTL of its own is the default TTL of the family, if any.
+	ExpiresAt(family string, timestamp, expiresAt int64) int64
+
+	// Flush snapshots every acknowledged write and blocks until it is durable.
+	Flush() error
+	// ListBackups returns the backup catalog, oldest first. Engines without backups return an
+	// empty catalog.
+	ListBackups() ([]*litetable.BackupManifest, error)
+	// Usage returns the space the table and each of its families take, with their quotas.
+	Usage() litetable.TableUsage
+	// Sequence returns the sequence number of the latest mutation.
+	Sequence() uint64
+	// Commit applies the mutations of a transaction at once, unless a row it read or changes
+	// was changed after readAt, and returns the sequence number of the transaction. accepted,
+	// when set, is called once the transaction has passed its checks and before it is applied.
+	Commit(readAt uint64, reads []string, mutations []litetable.Mutation,
+		accepted func() error) (uint64, error)
+	// ApplyBatch applies a group of independent writes and returns the error of each.
+	ApplyBatch(mutations []litetable.Mutation) []error
+	// Tombstones returns up to limit tombstones that have not expired on rows starting with
+	// prefix, and whether there were more.
+	Tombstones(prefix string, limit int) ([]litetable.Tombstone, bool)
+	// LoadReport returns what the table loaded from disk when it started.
+	LoadReport() litetable.LoadReport
+}
+
+// Config selects and configures the storage engine.
+type Config struct {
+	// Engine is the name of the engine to open. Defaults to Memory.
+	Engine string
+	// Memory configures the in-memory engine.
+	Memory *shard_storage.Config
+	// Shadow is the name of an engine to run alongside Engine to verify it. It is sent every
+	// write and, while it keeps up, every read, but answers none of them. Empty runs no shadow.
+	Shadow string
+}
+
+func (c *Config) validate() error {
+	var errGrp []error
+	if (c.Engine == Memory || c.Shadow == Memory) && c.Memory == nil {
+		errGrp = append(errGrp, errors.New("memory engine config cannot be nil"))
+	}
+	if c.Shadow != "" && !slices.Contains(Names, c.Shadow) {
+		errGrp = append(errGrp, fmt.Errorf("unknown shadow engine %q, expected one of %s",
+			c.Shadow, strings.Join(Names, ", ")))
+	}
+	return errors.Join(errGrp...)
+}
+
+// Open creates the configured engine along with the background dependencies that maintain it,
+// such as a garbage collector, which start after the engine.
+//
+// With a shadow, the shadow keeps its data in the shadow directory of the table. It is emptied
+// on every open and seeded from the primary when they start, so it never serves data of its
+// own and emits no CDC events.
+func Open(cfg *Config) (StorageEngine, []app.Dependency, error) {
+	if cfg.Engine == "" {
+		cfg.Engine = Memory
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	primary, maintenance, err := open(cfg.Engine, cfg.Memory)
+	if err != nil || cfg.Shadow == "" {
+		return primary, maintenance, err
+	}
+
+	var memory *shard_storage.Config
+	if cfg.Memory != nil {
+		shadowMemory := *cfg.Memory
+		shadowMemory.RootDir = filepath.Join(cfg.Memory.RootDir, shadowDir)
+		shadowMemory.CDCEmitter = noCDC{}
+		shadowMemory.CDCDurable = false
+		shadowMemory.CDCOldValues = false
+		if err = os.RemoveAll(shadowMemory.RootDir); err != nil {
+			return nil, nil, fmt.Errorf("failed to empty shadow engine directory: %w", err)
+		}
+		memory = &shadowMemory
+	}
+	secondary, shadowMaintenance, err := open(cfg.Shadow, memory)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open shadow engine: %w", err)
+	}
+	var table string
+	if cfg.Memory != nil {
+		table = cfg.Memory.Table
+	}
+	return newShadow(primary, secondary, table),
+		append(maintenance, shadowMaintenance...), nil
+}
+
+// open creates an engine by name.
+func open(name string, memory *shard_storage.Config) (StorageEngine, []app.Dependency, error) {
+	switch name {
+	case Memory:
+		m, gc, err := shard_storage.New(memory)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, []app.Dependency{gc}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown storage engine %q, expected one of %s", name,
+			strings.Join(Names, ", "))
+	}
+}
+
+// noCDC drops the CDC events of a shadow engine, which the primary already emits.
+type noCDC struct{}
+
+func (noCDC) Emit(*v1.CDCEvent) {}
+
+func (noCDC) Spill(*v1.CDCEvent) bool { return false }
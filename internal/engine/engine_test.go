@@ -0,0 +1,83 @@
+package engine
+
+import (
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type fakeCDC struct{}
+
+func (fakeCDC) Emit(*v1.CDCEvent) {}
+
+func (fakeCDC) Spill(*v1.CDCEvent) bool { return false }
+
+func TestOpen(t *testing.T) {
+	memory := func(t *testing.T) *shard_storage.Config {
+		return &shard_storage.Config{
+			RootDir:        t.TempDir(),
+			FlushThreshold: 60,
+			SnapshotTimer:  5,
+			CDCEmitter:     fakeCDC{},
+		}
+	}
+
+	tests := map[string]struct {
+		cfg             func(t *testing.T) *Config
+		wantMaintenance int
+		wantShadow      bool
+		wantErr         string
+	}{
+		"defaults to memory": {
+			cfg:             func(t *testing.T) *Config { return &Config{Memory: memory(t)} },
+			wantMaintenance: 1,
+		},
+		"memory": {
+			cfg: func(t *testing.T) *Config {
+				return &Config{Engine: Memory, Memory: memory(t)}
+			},
+			wantMaintenance: 1,
+		},
+		"memory without config": {
+			cfg:     func(t *testing.T) *Config { return &Config{Engine: Memory} },
+			wantErr: "memory engine config cannot be nil",
+		},
+		"memory with memory shadow": {
+			cfg: func(t *testing.T) *Config {
+				return &Config{Engine: Memory, Memory: memory(t), Shadow: Memory}
+			},
+			wantMaintenance: 2,
+			wantShadow:      true,
+		},
+		"unknown shadow engine": {
+			cfg: func(t *testing.T) *Config {
+				return &Config{Memory: memory(t), Shadow: "tape"}
+			},
+			wantErr: `unknown shadow engine "tape", expected one of memory`,
+		},
+		"unknown engine": {
+			cfg:     func(t *testing.T) *Config { return &Config{Engine: "tape"} },
+			wantErr: `unknown storage engine "tape", expected one of memory`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			storage, maintenance, err := Open(tc.cfg(t))
+			if tc.wantErr != "" {
+				req.EqualError(err, tc.wantErr)
+				return
+			}
+
+			req.NoError(err)
+			if tc.wantShadow {
+				req.IsType(&shadow{}, storage)
+			} else {
+				req.IsType(&shard_storage.Manager{}, storage)
+			}
+			req.Len(maintenance, tc.wantMaintenance)
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package engine
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/logging"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"github.com/rs/zerolog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// shadowDir holds the data of the shadow engine, inside the data directory of its table.
+const shadowDir = "shadow"
+
+const (
+	// shadowQueueSize is the most reads waiting to be compared. Reads beyond it are not compared.
+	shadowQueueSize = 1024
+	// shadowRecheckDelay is how long a mismatch is given to settle before both engines are read
+	// again, since a write may have reached one of them and not yet the other.
+	shadowRecheckDelay = 100 * time.Millisecond
+)
+
+var (
+	shadowComparisons = metrics.NewCounterVec("litetable_shadow_reads_compared_total",
+		"Reads compared between the primary and the shadow storage engine.", "table")
+	shadowMismatches = metrics.NewCounterVec("litetable_shadow_mismatches_total",
+		"Reads the shadow storage engine answered differently from the primary.", "table")
+	shadowDropped = metrics.NewCounterVec("litetable_shadow_comparisons_dropped_total",
+		"Reads not compared because the comparison queue was full.", "table")
+	shadowWriteErrors = metrics.NewCounterVec("litetable_shadow_write_errors_total",
+		"Writes the primary storage engine applied and the shadow did not.", "table")
+)
+
+// comparison is a read of the primary engine to repeat on the shadow.
+type comparison struct {
+	// read reads the rows from an engine
+	read func(e StorageEngine) (*litetable.Data, bool, error)
+	// operation and key describe the read in logs
+	operation string
+	key       string
+	primary   litetable.Data
+}
+
+// shadow serves every request from the primary engine and applies the writes the primary
+// accepts to a second engine as well. Reads are repeated on the second engine in the
+// background and mismatches are logged, so a new engine can be verified against production
+// traffic before it is trusted with it. Reads from the shadow are never returned and its
+// failures never fail a request.
+type shadow struct {
+	StorageEngine
+	shadow StorageEngine
+	table  string
+	logger zerolog.Logger
+
+	queue chan comparison
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newShadow(primary, secondary StorageEngine, table string) *shadow {
+	if table == "" {
+		table = litetable.DefaultTable
+	}
+	logger := logging.For("shadow")
+	return &shadow{
+		StorageEngine: primary,
+		shadow:        secondary,
+		table:         table,
+		logger:        logger.With().Str("table", table).Logger(),
+		queue:         make(chan comparison, shadowQueueSize),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start starts the primary, then the shadow, which is seeded with the rows of the primary.
+func (s *shadow) Start() error {
+	if err := s.StorageEngine.Start(); err != nil {
+		return err
+	}
+	if err := s.shadow.Start(); err != nil {
+		return errors.Join(err, s.StorageEngine.Stop())
+	}
+	if err := s.seed(); err != nil {
+		return errors.Join(err, s.shadow.Stop(), s.StorageEngine.Stop())
+	}
+
+	s.wg.Add(1)
+	go s.compareLoop()
+	return nil
+}
+
+// Stop stops comparing and stops both engines.
+func (s *shadow) Stop() error {
+	close(s.done)
+	s.wg.Wait()
+	return errors.Join(s.StorageEngine.Stop(), s.shadow.Stop())
+}
+
+// seed copies every family, value and tombstone of the primary to the empty shadow.
+func (s *shadow) seed() error {
+	start := time.Now()
+	if families := s.StorageEngine.GetFamilies(); len(families) > 0 {
+		if err := s.shadow.UpdateFamilies(families); err != nil {
+			return err
+		}
+	}
+
+	data, _, found := s.StorageEngine.FilterRowsByPrefix("", litetable.ScanPage{})
+	if !found {
+		return nil
+	}
+	cells := 0
+	for rowKey, families := range *data {
+		for family, qualifiers := range families {
+			for qualifier, values := range qualifiers {
+				for _, v := range values {
+					var err error
+					if v.IsTombstone {
+						err = s.shadow.Delete(rowKey, family, []string{qualifier}, v.Timestamp,
+							v.ExpiresAt)
+					} else {
+						err = s.shadow.Apply(rowKey, family, []string{qualifier},
+							[][]byte{v.Value}, v.Timestamp, v.ExpiresAt)
+					}
+					if err != nil {
+						return err
+					}
+					cells++
+				}
+			}
+		}
+	}
+	s.logger.Info().Int("rows", len(*data)).Int("cells", cells).
+		Dur("duration", time.Since(start)).Msg("Shadow engine seeded")
+	return nil
+}
+
+func (s *shadow) Apply(rowKey, family string, qualifiers []string, values [][]byte,
+	timestamp int64, expiresAt int64) error {
+	if err := s.StorageEngine.Apply(rowKey, family, qualifiers, values, timestamp,
+		expiresAt); err != nil {
+		return err
+	}
+	s.shadowWrite(s.shadow.Apply(rowKey, family, qualifiers, values, timestamp, expiresAt))
+	return nil
+}
+
+func (s *shadow) Delete(key, family string, qualifiers []string, timestamp int64,
+	expiresAt int64) error {
+	if err := s.StorageEngine.Delete(key, family, qualifiers, timestamp, expiresAt); err != nil {
+		return err
+	}
+	s.shadowWrite(s.shadow.Delete(key, family, qualifiers, timestamp, expiresAt))
+	return nil
+}
+
+func (s *shadow) ApplyBatch(mutations []litetable.Mutation) []error {
+	errs := s.StorageEngine.ApplyBatch(mutations)
+	var applied []litetable.Mutation
+	for i, m := range mutations {
+		if errs[i] == nil {
+			applied = append(applied, m)
+		}
+	}
+	if len(applied) > 0 {
+		s.shadowWrite(errors.Join(s.shadow.ApplyBatch(applied)...))
+	}
+	return errs
+}
+
+// Commit commits the transaction on the primary. The shadow applies its mutations without the
+// conflict check, since the primary already decided the transaction.
+func (s *shadow) Commit(readAt uint64, reads []string, mutations []litetable.Mutation,
+	accepted func() error) (uint64, error) {
+	seq, err := s.StorageEngine.Commit(readAt, reads, mutations, accepted)
+	if err != nil {
+		return 0, err
+	}
+	s.shadowWrite(errors.Join(s.shadow.ApplyBatch(mutations)...))
+	return seq, nil
+}
+
+func (s *shadow) UpdateFamilies(families []string) error {
+	if err := s.StorageEngine.UpdateFamilies(families); err != nil {
+		return err
+	}
+	s.shadowWrite(s.shadow.UpdateFamilies(families))
+	return nil
+}
+
+func (s *shadow) CloneFamily(source, target string) (int, int, error) {
+	rows, cells, err := s.StorageEngine.CloneFamily(source, target)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, _, err = s.shadow.CloneFamily(source, target)
+	s.shadowWrite(err)
+	return rows, cells, nil
+}
+
+func (s *shadow) Flush() error {
+	if err := s.StorageEngine.Flush(); err != nil {
+		return err
+	}
+	s.shadowWrite(s.shadow.Flush())
+	return nil
+}
+
+// SetTimers changes the timers of both engines, when they have them.
+func (s *shadow) SetTimers(snapshot, backup, gc int) error {
+	var errGrp []error
+	for _, e := range []StorageEngine{s.StorageEngine, s.shadow} {
+		if timers, ok := e.(interface {
+			SetTimers(snapshot, backup, gc int) error
+		}); ok {
+			errGrp = append(errGrp, timers.SetTimers(snapshot, backup, gc))
+		}
+	}
+	return errors.Join(errGrp...)
+}
+
+func (s *shadow) shadowWrite(err error) {
+	if err == nil {
+		return
+	}
+	shadowWriteErrors.With(s.table).Inc()
+	s.logger.Warn().Err(err).Msg("shadow engine failed a write the primary applied")
+}
+
+func (s *shadow) GetRowByFamily(key, family string) (*litetable.Data, bool) {
+	data, found := s.StorageEngine.GetRowByFamily(key, family)
+	s.compare(comparison{
+		read: func(e StorageEngine) (*litetable.Data, bool, error) {
+			data, found := e.GetRowByFamily(key, family)
+			return data, found, nil
+		},
+		operation: "row",
+		key:       key,
+	}, data, found)
+	return data, found
+}
+
+func (s *shadow) FilterRowsByPrefix(prefix string, page litetable.ScanPage) (*litetable.Data,
+	litetable.ScanStats, bool) {
+	data, stats, found := s.StorageEngine.FilterRowsByPrefix(prefix, page)
+	s.compare(comparison{
+		read: func(e StorageEngine) (*litetable.Data, bool, error) {
+			data, _, found := e.FilterRowsByPrefix(prefix, page)
+			return data, found, nil
+		},
+		operation: "prefix",
+		key:       prefix,
+	}, data, found)
+	return data, stats, found
+}
+
+func (s *shadow) FilterRowsByRegex(regex string, page litetable.ScanPage) (*litetable.Data,
+	litetable.ScanStats, bool, error) {
+	data, stats, found, err := s.StorageEngine.FilterRowsByRegex(regex, page)
+	if err == nil {
+		s.compare(comparison{
+			read: func(e StorageEngine) (*litetable.Data, bool, error) {
+				data, _, found, err := e.FilterRowsByRegex(regex, page)
+				return data, found, err
+			},
+			operation: "regex",
+			key:       regex,
+		}, data, found)
+	}
+	return data, stats, found, err
+}
+
+// compare queues a read for comparison with a copy of what the primary returned, since the
+// caller may change the rows. The read is not compared when the queue is full.
+func (s *shadow) compare(c comparison, data *litetable.Data, found bool) {
+	if found && data != nil {
+		c.primary = cloneData(*data)
+	}
+	select {
+	case s.queue <- c:
+	default:
+		shadowDropped.With(s.table).Inc()
+	}
+}
+
+func (s *shadow) compareLoop() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.done:
+			return
+		case c := <-s.queue:
+			s.check(c)
+		}
+	}
+}
+
+// check reads the shadow and compares it with the primary. A mismatch is read again from both
+// engines after a delay and only reported if it remains.
+func (s *shadow) check(c comparison) {
+	shadowComparisons.With(s.table).Inc()
+	shadowData, err := readData(s.shadow, c.read)
+	if err == nil && equalData(c.primary, shadowData) {
+		return
+	}
+
+	select {
+	case <-s.done:
+		return
+	case <-time.After(shadowRecheckDelay):
+	}
+	primaryData, primaryErr := readData(s.StorageEngine, c.read)
+	shadowData, err = readData(s.shadow, c.read)
+	if primaryErr == nil && err == nil && equalData(primaryData, shadowData) {
+		return
+	}
+
+	shadowMismatches.With(s.table).Inc()
+	event := s.logger.Warn().Str("operation", c.operation).Str("key", c.key).
+		Int("primaryRows", len(primaryData)).Int("shadowRows", len(shadowData))
+	if err != nil {
+		event = event.Err(err)
+	} else if rowKey, ok := firstDifference(primaryData, shadowData); ok {
+		event = event.Str("rowKey", rowKey)
+	}
+	event.Msg("shadow engine read does not match the primary")
+}
+
+func readData(e StorageEngine, read func(e StorageEngine) (*litetable.Data, bool,
+	error)) (litetable.Data, error) {
+	data, found, err := read(e)
+	if err != nil || !found || data == nil {
+		return nil, err
+	}
+	return *data, nil
+}
+
+// equalData reports whether two reads returned the same rows, values, timestamps, tombstones
+// and expiries. Sequence numbers are ignored, since each engine numbers its own mutations.
+func equalData(a, b litetable.Data) bool {
+	_, differ := firstDifference(a, b)
+	return !differ
+}
+
+// firstDifference returns the first row key, in order, that two reads disagree on.
+func firstDifference(a, b litetable.Data) (string, bool) {
+	keys := slices.Sorted(maps.Keys(a))
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if !equalRow(a[key], b[key]) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func equalRow(a, b map[string]litetable.VersionedQualifier) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for family, qa := range a {
+		qb, ok := b[family]
+		if !ok || len(qa) != len(qb) {
+			return false
+		}
+		for qualifier, va := range qa {
+			vb, ok := qb[qualifier]
+			if !ok || !slices.EqualFunc(va, vb, equalValue) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func equalValue(a, b litetable.TimestampedValue) bool {
+	return string(a.Value) == string(b.Value) && a.Timestamp == b.Timestamp &&
+		a.IsTombstone == b.IsTombstone && a.ExpiresAt == b.ExpiresAt
+}
+
+// cloneData copies the rows, families and version slices of a read. Values are shared, since
+// they are never changed in place.
+func cloneData(data litetable.Data) litetable.Data {
+	out := make(litetable.Data, len(data))
+	for rowKey, families := range data {
+		row := make(map[string]litetable.VersionedQualifier, len(families))
+		for family, qualifiers := range families {
+			vq := make(litetable.VersionedQualifier, len(qualifiers))
+			for qualifier, values := range qualifiers {
+				vq[qualifier] = slices.Clone(values)
+			}
+			row[family] = vq
+		}
+		out[rowKey] = row
+	}
+	return out
+}
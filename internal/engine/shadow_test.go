@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newMemory(t *testing.T, dir, table string) *shard_storage.Manager {
+	m, _, err := shard_storage.New(&shard_storage.Config{
+		RootDir:        dir,
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+		Table:          table,
+	})
+	require.NoError(t, err)
+	return m
+}
+
+func TestShadow(t *testing.T) {
+	req := require.New(t)
+	table := "shadow_test"
+	dir := t.TempDir()
+	now := time.Now().UnixNano()
+
+	// the primary already holds a row, which the shadow is seeded with
+	existing := newMemory(t, dir, table)
+	req.NoError(existing.Start())
+	req.NoError(existing.UpdateFamilies([]string{"main"}))
+	req.NoError(existing.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("ahri")},
+		now, 0))
+	req.NoError(existing.Stop())
+
+	primary := newMemory(t, dir, table)
+	secondary := newMemory(t, t.TempDir(), table)
+	s := newShadow(primary, secondary, table)
+	req.NoError(s.Start())
+	t.Cleanup(func() { _ = s.Stop() })
+
+	data, found := secondary.GetRowByFamily("champ:1", "main")
+	req.True(found)
+	req.Equal("ahri", string((*data)["champ:1"]["main"]["name"][0].Value))
+	req.True(secondary.IsFamilyAllowed("main"))
+
+	// writes reach both engines
+	req.NoError(s.UpdateFamilies([]string{"stats"}))
+	req.True(secondary.IsFamilyAllowed("stats"))
+	req.NoError(s.Apply("champ:2", "stats", []string{"wins"}, [][]byte{[]byte("10")}, now, 0))
+	_, found = secondary.GetRowByFamily("champ:2", "stats")
+	req.True(found)
+	req.NoError(s.Delete("champ:1", "main", []string{"name"}, now+1, 0))
+	req.Equal(primary.GetFamilies(), secondary.GetFamilies())
+	primaryData, _ := primary.GetRowByFamily("champ:1", "main")
+	shadowData, _ := secondary.GetRowByFamily("champ:1", "main")
+	req.True(equalData(*primaryData, *shadowData))
+
+	// reads both engines answer alike are compared without a mismatch
+	compared := shadowComparisons.With(table).Value()
+	mismatches := shadowMismatches.With(table).Value()
+	_, found = s.GetRowByFamily("champ:2", "stats")
+	req.True(found)
+	req.Eventually(func() bool {
+		return shadowComparisons.With(table).Value() == compared+1
+	}, time.Second, 10*time.Millisecond)
+	req.Equal(mismatches, shadowMismatches.With(table).Value())
+
+	// a write only the shadow received is a mismatch, and the read still comes from the primary
+	req.NoError(secondary.Apply("champ:2", "stats", []string{"wins"}, [][]byte{[]byte("11")},
+		now+1, 0))
+	data, _, found = s.FilterRowsByPrefix("champ:", litetable.ScanPage{})
+	req.True(found)
+	req.Equal("10", string((*data)["champ:2"]["stats"]["wins"][0].Value))
+	req.Eventually(func() bool {
+		return shadowMismatches.With(table).Value() == mismatches+1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestEqualData(t *testing.T) {
+	value := func(v string, seq uint64) litetable.TimestampedValue {
+		return litetable.TimestampedValue{Value: []byte(v), Timestamp: 1, Seq: seq}
+	}
+	row := func(values ...litetable.TimestampedValue) litetable.Data {
+		return litetable.Data{"r": {"f": {"q": values}}}
+	}
+
+	tests := map[string]struct {
+		a, b    litetable.Data
+		want    bool
+		wantKey string
+	}{
+		"both empty": {
+			want: true,
+		},
+		"same values with other sequence numbers": {
+			a:    row(value("a", 1)),
+			b:    row(value("a", 7)),
+			want: true,
+		},
+		"other value": {
+			a:       row(value("a", 1)),
+			b:       row(value("b", 1)),
+			wantKey: "r",
+		},
+		"missing version": {
+			a:       row(value("a", 1), value("b", 2)),
+			b:       row(value("a", 1)),
+			wantKey: "r",
+		},
+		"missing row": {
+			a:       litetable.Data{},
+			b:       row(value("a", 1)),
+			wantKey: "r",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			req.Equal(tc.want, equalData(tc.a, tc.b))
+			key, differ := firstDifference(tc.a, tc.b)
+			req.Equal(!tc.want, differ)
+			req.Equal(tc.wantKey, key)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package litetable
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode classifies an Error so that transports and clients can react to a failure without
+// matching on error strings. The gRPC server maps each code to a status code.
+type ErrorCode string
+
+const (
+	// ErrorCodeInternal is an unexpected failure inside LiteTable
+	ErrorCodeInternal ErrorCode = "INTERNAL"
+	// ErrorCodeNotFound means the requested row, family or qualifier does not exist
+	ErrorCodeNotFound ErrorCode = "NOT_FOUND"
+	// ErrorCodeInvalidArgument means the request was malformed
+	ErrorCodeInvalidArgument ErrorCode = "INVALID_ARGUMENT"
+	// ErrorCodeFamilyMissing means the column family has not been created
+	ErrorCodeFamilyMissing ErrorCode = "FAMILY_MISSING"
+	// ErrorCodeConflict means the request conflicts with the current state
+	ErrorCodeConflict ErrorCode = "CONFLICT"
+	// ErrorCodeExhausted means a limit or quota has been reached
+	ErrorCodeExhausted ErrorCode = "EXHAUSTED"
+)
+
+// Sentinel errors for use with errors.Is. Any Error matches the sentinel with the same code.
+var (
+	ErrNotFound        = &Error{Code: ErrorCodeNotFound}
+	ErrInvalidArgument = &Error{Code: ErrorCodeInvalidArgument}
+	ErrFamilyMissing   = &Error{Code: ErrorCodeFamilyMissing}
+	ErrConflict        = &Error{Code: ErrorCodeConflict}
+	ErrExhausted       = &Error{Code: ErrorCodeExhausted}
+)
+
+// Error is the error type shared by every layer of LiteTable.
+type Error struct {
+	Code    ErrorCode
+	err     error  // The underlying error, if any
+	context string // Additional error context
+}
+
+// Error satisfies the error interface
+func (e *Error) Error() string {
+	switch {
+	case e.err != nil && e.context != "":
+		return fmt.Sprintf("%s: %s", e.err.Error(), e.context)
+	case e.err != nil:
+		return e.err.Error()
+	case e.context != "":
+		return e.context
+	default:
+		return string(e.Code)
+	}
+}
+
+// Unwrap implements the errors.Unwrap interface for compatibility with errors.Is/As
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is the sentinel error for this error's code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.err == nil && t.context == "" && t.Code == e.Code
+}
+
+// NewError creates an error with the given code and message.
+func NewError(code ErrorCode, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:    code,
+		context: fmt.Sprintf(format, args...),
+	}
+}
+
+// WrapError creates an error with the given code that wraps err with additional context.
+func WrapError(code ErrorCode, err error, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:    code,
+		err:     err,
+		context: fmt.Sprintf(format, args...),
+	}
+}
+
+// CodeOf returns the code of the first Error in err's chain, or ErrorCodeInternal when there is
+// none.
+func CodeOf(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ErrorCodeInternal
+}
@@ -0,0 +1,52 @@
+package litetable
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestError_Error(t *testing.T) {
+	cause := errors.New("invalid format")
+
+	tests := map[string]struct {
+		err      *Error
+		expected string
+	}{
+		"code only": {
+			err:      &Error{Code: ErrorCodeNotFound},
+			expected: "NOT_FOUND",
+		},
+		"message": {
+			err:      NewError(ErrorCodeNotFound, "row not found: %s", "champ:1"),
+			expected: "row not found: champ:1",
+		},
+		"wrapped with context": {
+			err:      WrapError(ErrorCodeInvalidArgument, cause, "missing family"),
+			expected: "invalid format: missing family",
+		},
+		"wrapped without context": {
+			err:      WrapError(ErrorCodeInvalidArgument, cause, ""),
+			expected: "invalid format",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.err.Error())
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	req := require.New(t)
+	cause := errors.New("invalid format")
+	err := fmt.Errorf("outer: %w", WrapError(ErrorCodeInvalidArgument, cause, "missing key"))
+
+	req.True(errors.Is(err, ErrInvalidArgument))
+	req.True(errors.Is(err, cause))
+	req.False(errors.Is(err, ErrNotFound))
+	req.Equal(ErrorCodeInvalidArgument, CodeOf(err))
+	req.Equal(ErrorCodeInternal, CodeOf(errors.New("boom")))
+}
@@ -0,0 +1,93 @@
+package litetable
+
+import (
+	"fmt"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxRowKeyLength is the longest row key, in bytes, unless configured otherwise.
+	DefaultMaxRowKeyLength = 4096
+	// DefaultFamilyNamePattern is the pattern family names must match unless configured
+	// otherwise. It keeps them safe to use in queries and file names.
+	DefaultFamilyNamePattern = `^[A-Za-z0-9_.:-]{1,128}$`
+)
+
+var defaultFamilyPattern = regexp.MustCompile(DefaultFamilyNamePattern)
+
+// NameRules are the rules row keys and family names are held to when they are written or
+// created, so they cannot break the text query protocol later. The zero value uses the
+// defaults.
+type NameRules struct {
+	maxRowKeyLength int
+	familyPattern   *regexp.Regexp
+}
+
+// NewNameRules returns the rules for a maximum row key length and a family name pattern. Zero
+// and empty values use the defaults.
+func NewNameRules(maxRowKeyLength int, familyNamePattern string) (NameRules, error) {
+	rules := NameRules{maxRowKeyLength: maxRowKeyLength}
+	if familyNamePattern != "" {
+		pattern, err := regexp.Compile(familyNamePattern)
+		if err != nil {
+			return NameRules{}, fmt.Errorf("invalid family name pattern: %w", err)
+		}
+		rules.familyPattern = pattern
+	}
+	return rules, nil
+}
+
+// MaxRowKeyLength is the longest row key, in bytes.
+func (r NameRules) MaxRowKeyLength() int {
+	if r.maxRowKeyLength <= 0 {
+		return DefaultMaxRowKeyLength
+	}
+	return r.maxRowKeyLength
+}
+
+// FamilyPattern is the pattern family names must match.
+func (r NameRules) FamilyPattern() *regexp.Regexp {
+	if r.familyPattern == nil {
+		return defaultFamilyPattern
+	}
+	return r.familyPattern
+}
+
+// CheckRowKey returns an InvalidArgument error for a row key that is too long or that
+// NameViolation rejects.
+func (r NameRules) CheckRowKey(key string) error {
+	if len(key) > r.MaxRowKeyLength() {
+		return NewError(ErrorCodeInvalidArgument, "row key must be at most %d bytes",
+			r.MaxRowKeyLength())
+	}
+	if problem := NameViolation(key); problem != "" {
+		return NewError(ErrorCodeInvalidArgument, "row key %q %s", key, problem)
+	}
+	return nil
+}
+
+// CheckFamily returns an InvalidArgument error for a family name that does not match the
+// family pattern.
+func (r NameRules) CheckFamily(family string) error {
+	if !r.FamilyPattern().MatchString(family) {
+		return NewError(ErrorCodeInvalidArgument, "family name %q must match %s", family,
+			r.FamilyPattern())
+	}
+	return nil
+}
+
+// NameViolation describes why a row key or qualifier would break the text query protocol: it
+// must be valid UTF-8 without whitespace or control characters. It is empty for a valid name.
+func NameViolation(name string) string {
+	if !utf8.ValidString(name) {
+		return "must be valid UTF-8"
+	}
+	for _, r := range name {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return "cannot contain whitespace or control characters"
+		}
+	}
+	return ""
+}
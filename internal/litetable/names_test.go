@@ -0,0 +1,87 @@
+package litetable
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestNameRules_CheckRowKey(t *testing.T) {
+	custom, err := NewNameRules(8, "")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		rules     NameRules
+		key       string
+		expectErr string
+	}{
+		"valid key": {
+			key: "champ:1",
+		},
+		"default length": {
+			key:       strings.Repeat("k", DefaultMaxRowKeyLength+1),
+			expectErr: "row key must be at most 4096 bytes",
+		},
+		"configured length": {
+			rules:     custom,
+			key:       "champ:123",
+			expectErr: "row key must be at most 8 bytes",
+		},
+		"whitespace": {
+			key:       "champ 1",
+			expectErr: `row key "champ 1" cannot contain whitespace or control characters`,
+		},
+		"control character": {
+			key:       "champ\x00",
+			expectErr: "cannot contain whitespace or control characters",
+		},
+		"invalid UTF-8": {
+			key:       "champ\xff",
+			expectErr: "must be valid UTF-8",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.rules.CheckRowKey(tc.key)
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidArgument)
+			require.ErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestNameRules_CheckFamily(t *testing.T) {
+	lower, err := NewNameRules(0, "^[a-z]+$")
+	require.NoError(t, err)
+	_, err = NewNameRules(0, "[")
+	require.Error(t, err)
+
+	tests := map[string]struct {
+		rules     NameRules
+		family    string
+		expectErr bool
+	}{
+		"default pattern":            {family: "wrestlers:v2"},
+		"slash breaks file names":    {family: "a/b", expectErr: true},
+		"empty":                      {family: "", expectErr: true},
+		"too long":                   {family: strings.Repeat("f", 129), expectErr: true},
+		"configured pattern":         {rules: lower, family: "wrestlers"},
+		"configured pattern rejects": {rules: lower, family: "Wrestlers", expectErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.rules.CheckFamily(tc.family)
+			if !tc.expectErr {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidArgument)
+			require.ErrorContains(t, err, "must match")
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package litetable
+
+// slabChunk is the smallest number of values a ValueSlab allocates at once.
+const slabChunk = 256
+
+// ValueSlab carves copies of value slices out of larger allocations, so copying the many small
+// slices of a read costs one allocation per chunk rather than one per slice. A copy keeps its
+// whole chunk alive, so a slab suits short-lived copies. The zero value is ready to use.
+type ValueSlab struct {
+	buf []TimestampedValue
+}
+
+// Grow makes room for at least n more values, so the next copies of up to n values in total do
+// not allocate.
+func (s *ValueSlab) Grow(n int) {
+	if cap(s.buf)-len(s.buf) < n {
+		s.buf = make([]TimestampedValue, 0, n)
+	}
+}
+
+// Copy returns a copy of values. The copy is capped at its length, so appending to it never
+// overwrites another copy.
+func (s *ValueSlab) Copy(values []TimestampedValue) []TimestampedValue {
+	if len(values) == 0 {
+		return nil
+	}
+	if cap(s.buf)-len(s.buf) < len(values) {
+		s.buf = make([]TimestampedValue, 0, max(slabChunk, len(values)))
+	}
+
+	start := len(s.buf)
+	s.buf = append(s.buf, values...)
+	return s.buf[start:len(s.buf):len(s.buf)]
+}
@@ -0,0 +1,41 @@
+package litetable
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValueSlab_Copy(t *testing.T) {
+	req := require.New(t)
+	var s ValueSlab
+
+	req.Nil(s.Copy(nil))
+
+	values := []TimestampedValue{{Value: []byte("a"), Timestamp: 1}, {Timestamp: 2}}
+	a := s.Copy(values)
+	b := s.Copy(values[:1])
+	req.Equal(values, a)
+	req.Equal(values[:1], b)
+
+	// the copies are independent of the source and of each other
+	values[0].Timestamp = 9
+	a = append(a, TimestampedValue{Timestamp: 3})
+	req.Equal(int64(1), a[0].Timestamp)
+	req.Equal([]TimestampedValue{{Value: []byte("a"), Timestamp: 1}}, b)
+
+	// a copy larger than a chunk gets an allocation of its own
+	big := make([]TimestampedValue, slabChunk+1)
+	req.Len(s.Copy(big), slabChunk+1)
+}
+
+func TestValueSlab_Grow(t *testing.T) {
+	values := []TimestampedValue{{Timestamp: 1}, {Timestamp: 2}}
+	allocs := testing.AllocsPerRun(10, func() {
+		var s ValueSlab
+		s.Grow(len(values) * 3)
+		for i := 0; i < 3; i++ {
+			s.Copy(values)
+		}
+	})
+	require.Equal(t, float64(1), allocs)
+}
@@ -0,0 +1,104 @@
+package litetable
+
+import (
+	"regexp"
+	"time"
+)
+
+// DefaultTable is the table of requests that do not name one. It holds the keyspace that
+// predates tables and cannot be created or dropped.
+const DefaultTable = "default"
+
+// tableNamePattern keeps table names safe to use as directory names.
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// TableNamePattern is the pattern every table name must match.
+func TableNamePattern() string {
+	return tableNamePattern.String()
+}
+
+// ValidTableName reports whether name can be used for a table.
+func ValidTableName(name string) bool {
+	return tableNamePattern.MatchString(name)
+}
+
+// Usage is the space a table or column family takes and the quota it is held to. A zero
+// quota is unlimited.
+type Usage struct {
+	// Bytes is the size of every stored value, old versions included.
+	Bytes    int64
+	Rows     int64
+	MaxBytes int64
+	MaxRows  int64
+}
+
+// TableUsage is the usage of a table and of each of its column families, with the stats of its
+// families.
+type TableUsage struct {
+	Usage
+	Families map[string]Usage
+	// Stats are the stats of each family as of StatsAt, when the stats job last ran. They are
+	// empty until it first runs.
+	Stats   map[string]FamilyStats
+	StatsAt time.Time
+}
+
+// VersionDepthBounds are the upper bounds of the buckets of FamilyStats.VersionDepth. The last
+// bucket holds the qualifiers with more versions than the last bound.
+var VersionDepthBounds = []int64{1, 2, 4, 8, 16, 32, 64}
+
+// FamilyStats describe the shape of a column family for capacity planning.
+type FamilyStats struct {
+	Rows int64
+	// Qualifiers counts the qualifiers of every row, so a qualifier in two rows counts twice.
+	Qualifiers int64
+	// Cells counts every version of every qualifier, tombstones included.
+	Cells int64
+	// Bytes estimates the memory the family takes: its values, and the row key, qualifier and
+	// timestamps stored with each cell.
+	Bytes int64
+	// MaxVersions is the most versions of any qualifier.
+	MaxVersions int64
+	// VersionDepth counts qualifiers by their number of versions, one bucket per bound of
+	// VersionDepthBounds and one for deeper qualifiers.
+	VersionDepth []int64
+	// CompressedCells counts the values stored compressed. CompressedBytes is their stored size,
+	// included in Bytes, and UncompressedBytes their size before compression.
+	CompressedCells   int64
+	CompressedBytes   int64
+	UncompressedBytes int64
+}
+
+// LoadReport is what a table loaded from disk when it started, so operators can check a recovery
+// at a glance.
+type LoadReport struct {
+	Table string `json:"table"`
+	// Backup is the file of the full backup loaded, with its deltas. Empty without one.
+	Backup string `json:"backup,omitempty"`
+	// Snapshots is the number of snapshots applied that were not merged into the backup yet
+	Snapshots int `json:"snapshots"`
+	Rows      int `json:"rows"`
+	// ShardRows is the number of rows loaded into each shard, and RowSkew the largest over the
+	// mean, 1 being perfectly even
+	ShardRows []int   `json:"shardRows"`
+	RowSkew   float64 `json:"rowSkew"`
+	// Families are the column families of the rows loaded, sorted
+	Families []string      `json:"families"`
+	Duration time.Duration `json:"durationNanos"`
+}
+
+// SchemaVersion is the version of the schemas this server exports, and the newest it imports.
+const SchemaVersion = 1
+
+// Schema is a portable description of the tables of a server and their column families, for
+// promoting them from one environment to another. Later versions may describe more of a table.
+type Schema struct {
+	Version int
+	Tables  []TableSchema
+}
+
+// TableSchema is a table of a Schema with its column families, sorted.
+type TableSchema struct {
+	Name     string
+	Families []string
+}
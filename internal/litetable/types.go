@@ -1,5 +1,11 @@
 package litetable
 
+import "time"
+
+// MaxTTL is the longest a value stays readable for after it is written, by its own TTL or the
+// default TTL of its family, so its expiry always fits in Unix nanoseconds.
+const MaxTTL = 100 * 365 * 24 * time.Hour
+
 type Operation string
 
 const (
@@ -22,7 +28,27 @@ type TimestampedValue struct {
 	Value       []byte `json:"value"`
 	Timestamp   int64  `json:"timestamp"`
 	IsTombstone bool   `json:"tombstone,omitempty"` // if the value is slated for deletion
-	ExpiresAt   int64  `json:"expiresAt,omitempty"` // the time in which the value will expire
+	// ExpiresAt is when a tombstone is collected, or when a value written with a TTL expires.
+	// It is in Unix nanoseconds, like Timestamp.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+	// Seq is the sequence number of the mutation that stored the value. Values stored before
+	// mutations were numbered have none.
+	Seq uint64 `json:"seq,omitempty"`
+	// Compressed is set on a value stored compressed with zstd. Reads return values
+	// decompressed.
+	Compressed bool `json:"compressed,omitempty"`
+}
+
+// IsExpired reports whether a value written with a TTL has expired at now. Tombstones never
+// expire this way; their ExpiresAt is when the reaper collects them.
+func (tv TimestampedValue) IsExpired(now int64) bool {
+	return !tv.IsTombstone && tv.ExpiresAt > 0 && tv.ExpiresAt <= now
+}
+
+// Size is an estimate of the memory held by a value: the stored bytes, compressed or not, plus
+// the fixed width timestamp, expiry, sequence and tombstone fields.
+func (tv TimestampedValue) Size() int64 {
+	return int64(len(tv.Value)) + 25
 }
 
 // VersionedQualifier maps qualifiers to their timestamped values
@@ -55,3 +81,133 @@ type Row struct {
 }
 
 type Data map[string]map[string]VersionedQualifier
+
+// RowReader reads the rows of a table by key, key prefix or key regex. found is false when
+// nothing matched. Prefix and regex scans read the page of matching rows page asks for, and also
+// return what they examined, which a scan matching nothing still pays for.
+type RowReader interface {
+	GetRowByFamily(key, family string) (*Data, bool)
+	FilterRowsByPrefix(prefix string, page ScanPage) (*Data, ScanStats, bool)
+	FilterRowsByRegex(regex string, page ScanPage) (*Data, ScanStats, bool, error)
+}
+
+// ScanPage bounds a prefix or regex scan to a page of its matching rows in row key order. The
+// zero ScanPage reads every matching row.
+type ScanPage struct {
+	// After skips the row keys up to and including it.
+	After string
+	// MaxBytes stops the scan once the rows it read pass this many bytes of keys, names and
+	// values. The first row is always read, so every page makes progress. Zero is no limit.
+	MaxBytes int
+}
+
+// Skips reports whether a row key comes before the page.
+func (p ScanPage) Skips(rowKey string) bool {
+	return p.After != "" && rowKey <= p.After
+}
+
+// ScanStats is the work a prefix or regex scan did.
+type ScanStats struct {
+	// Examined is the number of row keys the scan examined, whether or not they matched.
+	Examined int
+	// More is set when the scan stopped at ScanPage.MaxBytes before its last matching row.
+	More bool
+}
+
+// Aggregate holds the aggregations of one qualifier over the cells of a read. Aggregations that
+// were not requested are left zero.
+type Aggregate struct {
+	Family       string
+	Qualifier    string
+	Count        uint64
+	MinTimestamp int64
+	MaxTimestamp int64
+	Sum          float64
+	// NonNumeric counts the cells left out of Sum because they are not decimal numbers
+	NonNumeric uint64
+}
+
+// Cost is the work a read did, for accounting the load each client puts on the server.
+type Cost struct {
+	// RowsScanned are the rows read from storage, before their families and qualifiers are
+	// filtered
+	RowsScanned int
+	// CellsCopied are the values copied into the result, or aggregated
+	CellsCopied int
+}
+
+// QualifierVersions names a qualifier of a row and counts its versions.
+type QualifierVersions struct {
+	Name     string
+	Versions uint64
+}
+
+// Tombstone is a deleted cell that has not expired, so the reaper has yet to collect it.
+type Tombstone struct {
+	RowKey    string
+	Family    string
+	Qualifier string
+	// Timestamp and ExpiresAt are in Unix nanoseconds. The tombstone hides the versions written
+	// at or before Timestamp, and is collected with them once ExpiresAt passes.
+	Timestamp int64
+	ExpiresAt int64
+	// Scheduled reports whether the reaper holds an entry, in its GC log, that collects the
+	// tombstone. One without an entry is only collected along with a later delete of the cell.
+	Scheduled bool
+}
+
+// BackupManifest describes a full backup in the backup catalog.
+type BackupManifest struct {
+	// File is the name of the backup file in the backup directory
+	File string `json:"file"`
+	// FormatVersion is the version of the backup format. Zero is a backup written before
+	// manifests, whose other fields are inferred from the file.
+	FormatVersion int    `json:"formatVersion"`
+	CreatedAt     int64  `json:"createdAt"`
+	Rows          int    `json:"rows"`
+	Bytes         int64  `json:"bytes"`
+	Checksum      string `json:"checksum,omitempty"`
+	// FirstSnapshot and LastSnapshot are the timestamps of the incremental snapshots merged into
+	// the backup. Every write acknowledged before LastSnapshot is in the backup.
+	FirstSnapshot int64 `json:"firstSnapshot,omitempty"`
+	LastSnapshot  int64 `json:"lastSnapshot,omitempty"`
+	// Sequence is the sequence number of the latest mutation when the last snapshot was taken.
+	// A restart never numbers mutations below it, even when those mutations left no data.
+	Sequence uint64 `json:"sequence,omitempty"`
+}
+
+// RowLease is an advisory lock on a row, held until it expires or is released.
+type RowLease struct {
+	// Token is the fencing token of the lease. Every lease granted by the server has a larger
+	// token than the leases before it.
+	Token uint64
+	// ExpiresAt is when the lease ends, in Unix nanoseconds.
+	ExpiresAt int64
+}
+
+// Mutation is a write or delete in a transaction.
+type Mutation struct {
+	Operation  Operation
+	RowKey     string
+	Family     string
+	Qualifiers []string
+	// Values are the values of the qualifiers of a write
+	Values [][]byte
+	// Timestamp and ExpiresAt are in Unix nanoseconds. ExpiresAt is when the values of a write
+	// expire, or when the tombstones of a delete are collected.
+	Timestamp int64
+	ExpiresAt int64
+}
+
+// CommitResult is the sequence number and timestamp shared by the mutations of a transaction.
+type CommitResult struct {
+	Sequence  uint64
+	Timestamp int64
+}
+
+// MutationQuery is a write or delete in a transaction, in the query form of a write or delete.
+type MutationQuery struct {
+	// Operation is OperationWrite or OperationDelete
+	Operation Operation
+	Query     string
+}
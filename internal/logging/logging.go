@@ -0,0 +1,105 @@
+// Package logging is the logging facade for LiteTable. The process configures it once at
+// startup and every module asks it for a component logger instead of writing to the global
+// zerolog logger or stdout.
+package logging
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// ComponentField is the log field naming the module that wrote an entry.
+	ComponentField = "component"
+
+	// googleSeverityKey is the key used for severity in Google Cloud Logging to conform to their
+	// Stackdriver logging format
+	googleSeverityKey = "severity"
+)
+
+var (
+	baseMutex sync.RWMutex
+	base      = log.Logger
+)
+
+type Config struct {
+	Debug            bool
+	CloudEnvironment string
+}
+
+// Init configures the base logger every component logger is derived from. The global zerolog
+// logger is kept in sync for third-party code that writes to it.
+func Init(cfg *Config) {
+	// if deployed to google, change the severity key
+	if cfg.CloudEnvironment == "google" {
+		zerolog.LevelFieldName = googleSeverityKey
+	}
+
+	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.SetGlobalLevel(zerolog.InfoLevel) // set to info for production
+
+	// for sanity's sake - make the dev logs easier to read and parse
+	if cfg.Debug {
+		output := zerolog.ConsoleWriter{
+			Out:         os.Stderr,
+			TimeFormat:  time.RFC3339,
+			NoColor:     false,
+			FormatLevel: formatLevel,
+		}
+		zerolog.SetGlobalLevel(zerolog.DebugLevel) // always start with debug for base logging
+		logger = zerolog.New(output).With().Timestamp().Logger()
+	}
+
+	Set(logger)
+}
+
+// Set replaces the base logger.
+func Set(logger zerolog.Logger) {
+	baseMutex.Lock()
+	defer baseMutex.Unlock()
+	base = logger
+	log.Logger = logger
+}
+
+// SetDebug switches between debug and info level logging at runtime. The output format chosen
+// by Init is kept.
+func SetDebug(debug bool) {
+	if debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+		return
+	}
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+}
+
+// For returns a logger for the named component. Components should call it once when they are
+// constructed and keep the result.
+func For(component string) zerolog.Logger {
+	baseMutex.RLock()
+	defer baseMutex.RUnlock()
+	return base.With().Str(ComponentField, component).Logger()
+}
+
+func formatLevel(i interface{}) string {
+	level, ok := i.(string)
+	if !ok {
+		return "???"
+	}
+
+	switch level {
+	case "debug":
+		return "\x1b[35m" + "DEBUG" + "\x1b[0m" // Purple for debug
+	case "info":
+		return "\x1b[32m" + "INFO " + "\x1b[0m" // Green for info
+	case "warn":
+		return "\x1b[33m" + "WARN " + "\x1b[0m" // Yellow for warn
+	case "error":
+		return "\x1b[31m" + "ERROR" + "\x1b[0m" // Red for error
+	case "fatal", "panic":
+		return "\x1b[41m" + level + "\x1b[0m" // White on red background
+	default:
+		return level
+	}
+}
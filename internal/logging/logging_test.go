@@ -0,0 +1,25 @@
+package logging
+
+import (
+	"bytes"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	req := require.New(t)
+
+	original := log.Logger
+	defer Set(original)
+
+	var buf bytes.Buffer
+	Set(zerolog.New(&buf))
+
+	logger := For("reaper")
+	logger.Info().Msg("collected")
+
+	req.Contains(buf.String(), `"component":"reaper"`)
+	req.Contains(buf.String(), `"message":"collected"`)
+}
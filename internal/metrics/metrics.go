@@ -0,0 +1,308 @@
+// Package metrics is a small, dependency free metrics registry for LiteTable.
+//
+// Metrics are registered once (usually as package level variables) and exported in the
+// Prometheus text exposition format by Handler, which the HTTP server mounts at /metrics.
+//
+// Only counters and gauges with fixed label names are needed, which the text format covers in a
+// few hundred lines, so the Prometheus client library and its dependencies are not pulled into
+// the server for them.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type metricType string
+
+const (
+	typeCounter metricType = "counter"
+	typeGauge   metricType = "gauge"
+)
+
+// value is a float64 that can be updated atomically.
+type value struct {
+	bits atomic.Uint64
+}
+
+func (v *value) add(delta float64) {
+	for {
+		old := v.bits.Load()
+		next := math.Float64bits(math.Float64frombits(old) + delta)
+		if v.bits.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+func (v *value) set(f float64) {
+	v.bits.Store(math.Float64bits(f))
+}
+
+func (v *value) get() float64 {
+	return math.Float64frombits(v.bits.Load())
+}
+
+// Counter is a value that only ever goes up.
+type Counter struct {
+	v value
+}
+
+// Inc increments the counter by 1.
+func (c *Counter) Inc() {
+	c.v.add(1)
+}
+
+// Add increments the counter by delta. Negative values are ignored.
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.v.add(delta)
+}
+
+// Value returns the current counter value.
+func (c *Counter) Value() float64 {
+	return c.v.get()
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	v value
+}
+
+// Set replaces the gauge value.
+func (g *Gauge) Set(f float64) {
+	g.v.set(f)
+}
+
+// Add adds delta (which may be negative) to the gauge.
+func (g *Gauge) Add(delta float64) {
+	g.v.add(delta)
+}
+
+// Value returns the current gauge value.
+func (g *Gauge) Value() float64 {
+	return g.v.get()
+}
+
+// family is a named metric with zero or more labelled series.
+type family struct {
+	name       string
+	help       string
+	kind       metricType
+	labelNames []string
+
+	mu     sync.RWMutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	counter     *Counter
+	gauge       *Gauge
+}
+
+func (f *family) with(labelValues ...string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", f.name,
+			len(f.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	f.mu.RLock()
+	s, ok := f.series[key]
+	f.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok = f.series[key]; ok {
+		return s
+	}
+	s = &series{labelValues: labelValues}
+	switch f.kind {
+	case typeCounter:
+		s.counter = &Counter{}
+	case typeGauge:
+		s.gauge = &Gauge{}
+	}
+	f.series[key] = s
+	return s
+}
+
+// CounterVec is a counter partitioned by a fixed set of labels.
+type CounterVec struct {
+	f *family
+}
+
+// With returns the counter for the given label values, in the order the labels were declared.
+func (c *CounterVec) With(labelValues ...string) *Counter {
+	return c.f.with(labelValues...).counter
+}
+
+// GaugeVec is a gauge partitioned by a fixed set of labels.
+type GaugeVec struct {
+	f *family
+}
+
+// With returns the gauge for the given label values, in the order the labels were declared.
+func (g *GaugeVec) With(labelValues ...string) *Gauge {
+	return g.f.with(labelValues...).gauge
+}
+
+// Registry holds a set of metric families.
+type Registry struct {
+	mu       sync.RWMutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// defaultRegistry is used by the package level constructors.
+var defaultRegistry = NewRegistry()
+
+func (r *Registry) register(name, help string, kind metricType, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.families[name]; ok {
+		if existing.kind != kind {
+			panic(fmt.Sprintf("metric %s already registered as a %s", name, existing.kind))
+		}
+		return existing
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		series:     make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// NewCounter registers a counter without labels on the registry.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	return r.register(name, help, typeCounter, nil).with().counter
+}
+
+// NewCounterVec registers a labelled counter on the registry.
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{f: r.register(name, help, typeCounter, labelNames)}
+}
+
+// NewGauge registers a gauge without labels on the registry.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	return r.register(name, help, typeGauge, nil).with().gauge
+}
+
+// NewGaugeVec registers a labelled gauge on the registry.
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{f: r.register(name, help, typeGauge, labelNames)}
+}
+
+// WriteText writes every metric in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.mu.RLock()
+		f := r.families[name]
+		r.mu.RUnlock()
+
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name,
+			f.kind); err != nil {
+			return err
+		}
+
+		f.mu.RLock()
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			var v float64
+			if s.counter != nil {
+				v = s.counter.Value()
+			} else {
+				v = s.gauge.Value()
+			}
+			if _, err := fmt.Fprintf(w, "%s%s %v\n", f.name, formatLabels(f.labelNames,
+				s.labelValues), v); err != nil {
+				f.mu.RUnlock()
+				return err
+			}
+		}
+		f.mu.RUnlock()
+	}
+	return nil
+}
+
+// Handler serves the registry over HTTP.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_ = r.WriteText(w)
+	})
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i := range names {
+		pairs[i] = fmt.Sprintf("%s=%q", names[i], values[i])
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// NewCounter registers a counter on the default registry.
+func NewCounter(name, help string) *Counter {
+	return defaultRegistry.NewCounter(name, help)
+}
+
+// NewCounterVec registers a labelled counter on the default registry.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return defaultRegistry.NewCounterVec(name, help, labelNames...)
+}
+
+// NewGauge registers a gauge on the default registry.
+func NewGauge(name, help string) *Gauge {
+	return defaultRegistry.NewGauge(name, help)
+}
+
+// NewGaugeVec registers a labelled gauge on the default registry.
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return defaultRegistry.NewGaugeVec(name, help, labelNames...)
+}
+
+// Handler serves the default registry over HTTP.
+func Handler() http.Handler {
+	return defaultRegistry.Handler()
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	req := require.New(t)
+	r := NewRegistry()
+
+	c := r.NewCounter("test_total", "A test counter")
+	c.Inc()
+	c.Add(2)
+	c.Add(-5) // ignored
+
+	g := r.NewGauge("test_gauge", "A test gauge")
+	g.Set(10)
+	g.Add(-2.5)
+
+	vec := r.NewCounterVec("test_family_total", "A labelled counter", "family")
+	vec.With("b").Inc()
+	vec.With("a").Add(4)
+
+	var buf bytes.Buffer
+	req.NoError(r.WriteText(&buf))
+
+	req.Equal(`# HELP test_family_total A labelled counter
+# TYPE test_family_total counter
+test_family_total{family="a"} 4
+test_family_total{family="b"} 1
+# HELP test_gauge A test gauge
+# TYPE test_gauge gauge
+test_gauge 7.5
+# HELP test_total A test counter
+# TYPE test_total counter
+test_total 3
+`, buf.String())
+}
+
+func TestRegistry_registerTwice(t *testing.T) {
+	r := NewRegistry()
+	first := r.NewCounter("dup_total", "dup")
+	second := r.NewCounter("dup_total", "dup")
+	first.Inc()
+	require.Equal(t, float64(1), second.Value())
+
+	require.Panics(t, func() {
+		r.NewGauge("dup_total", "dup")
+	})
+}
+
+func TestFamily_withWrongLabels(t *testing.T) {
+	r := NewRegistry()
+	vec := r.NewGaugeVec("labels", "labels", "a", "b")
+	require.Panics(t, func() {
+		vec.With("only-one")
+	})
+}
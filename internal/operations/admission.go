@@ -0,0 +1,37 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+)
+
+var admissionRejections = metrics.NewCounterVec("litetable_admission_rejections_total",
+	"Writes, deletes and transactions refused because a backlog was over its limit.", "backlog")
+
+// Backlog is a queue of work left behind by the mutations the server accepts, such as the
+// entries waiting for the WAL or the changes waiting for a snapshot.
+type Backlog struct {
+	// Name names the backlog in errors and the rejection metric
+	Name string
+	// Depth returns the current size of the backlog
+	Depth func() int
+	// Limit is the size above which mutations are refused. 0 never refuses any.
+	Limit int
+}
+
+// admit refuses a mutation with an Exhausted error while a backlog is over its limit, so a
+// server under sustained overload sheds writes instead of queueing them in memory.
+func (m *Manager) admit() error {
+	for _, b := range m.backlogs {
+		if b.Limit <= 0 {
+			continue
+		}
+		if depth := b.Depth(); depth > b.Limit {
+			admissionRejections.With(b.Name).Inc()
+			return litetable.NewError(litetable.ErrorCodeExhausted,
+				"server is overloaded: %s backlog of %d is over its limit of %d", b.Name, depth,
+				b.Limit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_admit(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	depth := 10
+	backlog := func() int { return depth }
+	// nothing reaches the WAL or the storage while a backlog is over its limit
+	m := &Manager{
+		writeAhead:   NewMockwriteAhead(ctrl),
+		shardStorage: NewMockshardManager(ctrl),
+		backlogs: []Backlog{
+			{Name: "cdc", Depth: backlog},
+			{Name: "wal", Depth: backlog, Limit: 5},
+		},
+	}
+	rejected := admissionRejections.With("wal").Value()
+
+	_, err := m.Write("key=champ:1 family=wrestlers qualifier=name value=John")
+	req.ErrorIs(err, litetable.ErrExhausted)
+	req.ErrorContains(err, "wal backlog of 10 is over its limit of 5")
+
+	req.ErrorIs(m.Delete("key=champ:1 family=wrestlers"), litetable.ErrExhausted)
+
+	_, err = m.Commit("", 0, nil, []litetable.MutationQuery{{
+		Operation: litetable.OperationWrite,
+		Query:     "key=champ:1 family=wrestlers qualifier=name value=John",
+	}})
+	req.ErrorIs(err, litetable.ErrExhausted)
+
+	_, _, err = m.CloneFamily("", "wrestlers", "copy")
+	req.ErrorIs(err, litetable.ErrExhausted)
+	req.Equal(rejected+4, admissionRejections.With("wal").Value())
+
+	// a backlog without a limit never refuses, and one at its limit is admitted
+	depth = 5
+	req.NoError(m.admit())
+}
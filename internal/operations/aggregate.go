@@ -0,0 +1,158 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// aggregations are the aggregations requested by a query.
+type aggregations struct {
+	count        bool
+	minTimestamp bool
+	maxTimestamp bool
+	sum          bool
+}
+
+// add requests an aggregation by its query name.
+func (a *aggregations) add(name string) error {
+	switch name {
+	case "count":
+		a.count = true
+	case "minTimestamp":
+		a.minTimestamp = true
+	case "maxTimestamp":
+		a.maxTimestamp = true
+	case "sum":
+		a.sum = true
+	default:
+		return newError(errInvalidFormat,
+			"aggregate must be one of count, minTimestamp, maxTimestamp or sum. received %s",
+			name)
+	}
+	return nil
+}
+
+func (a aggregations) any() bool {
+	return a.count || a.minTimestamp || a.maxTimestamp || a.sum
+}
+
+// Aggregate runs a read query with at least one aggregate parameter and returns the requested
+// aggregations of every qualifier, sorted by qualifier, instead of the values. The values are
+// filtered as a read filters them, so tombstones, expiry, latest, readAt and asOf all apply, and
+// they are aggregated while the shards are scanned without being copied. The cost of the read is
+// returned with the aggregations.
+func (m *Manager) Aggregate(query string) ([]*litetable.Aggregate, litetable.Cost, error) {
+	parsed, err := parseRead(query)
+	if err != nil {
+		return nil, litetable.Cost{}, err
+	}
+	if !parsed.aggregations.any() {
+		return nil, litetable.Cost{}, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"missing aggregate: provide at least one of count, minTimestamp, maxTimestamp "+
+				"or sum")
+	}
+	if len(parsed.jsonPaths) > 0 {
+		return nil, litetable.Cost{}, newError(errInvalidFormat,
+			"jsonPath is not supported by aggregate queries")
+	}
+	if parsed.pageBytes > 0 {
+		return nil, litetable.Cost{}, newError(errInvalidFormat,
+			"pageBytes is not supported by aggregate queries")
+	}
+
+	data, found, err := m.scan(parsed, "aggregate")
+	if err != nil {
+		return nil, litetable.Cost{}, err
+	}
+	if !found {
+		return []*litetable.Aggregate{}, parsed.cost, nil
+	}
+	aggregates := parsed.aggregate(*data)
+	return aggregates, parsed.cost, nil
+}
+
+// aggregate computes the requested aggregations of the query's family over the matched rows.
+func (r *readQuery) aggregate(data litetable.Data) []*litetable.Aggregate {
+	byQualifier := make(map[string]*litetable.Aggregate)
+
+	scratch := filterPool.Get().(*[]litetable.TimestampedValue)
+	defer releaseScratch(scratch)
+
+	for rowKey, rowData := range data {
+		// an exact read only aggregates its own row
+		if r.rowKey != "" && rowKey != r.rowKey {
+			continue
+		}
+		family, exists := rowData[r.family]
+		if !exists {
+			continue
+		}
+
+		qualifiers := r.qualifiers
+		if len(qualifiers) == 0 {
+			qualifiers = make([]string, 0, len(family))
+			for qualifier := range family {
+				qualifiers = append(qualifiers, qualifier)
+			}
+		}
+
+		for _, qualifier := range qualifiers {
+			values := r.latestN(family[qualifier], r.latest, scratch)
+			if len(values) == 0 {
+				continue
+			}
+			agg, ok := byQualifier[qualifier]
+			if !ok {
+				agg = &litetable.Aggregate{Family: r.family, Qualifier: qualifier}
+				byQualifier[qualifier] = agg
+			}
+			r.aggregations.apply(agg, values, !ok)
+			r.cost.CellsCopied += len(values)
+		}
+	}
+
+	result := make([]*litetable.Aggregate, 0, len(byQualifier))
+	for _, agg := range byQualifier {
+		result = append(result, agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Qualifier < result[j].Qualifier
+	})
+	return result
+}
+
+// apply adds values, sorted by timestamp, to the requested aggregations of agg. first is set for
+// the first values of a qualifier, which set its timestamp bounds.
+func (a aggregations) apply(agg *litetable.Aggregate, values []litetable.TimestampedValue,
+	first bool) {
+	if a.count {
+		agg.Count += uint64(len(values))
+	}
+	newest, oldest := values[0].Timestamp, values[len(values)-1].Timestamp
+	if newest < oldest {
+		newest, oldest = oldest, newest
+	}
+	if a.maxTimestamp && (first || newest > agg.MaxTimestamp) {
+		agg.MaxTimestamp = newest
+	}
+	if a.minTimestamp && (first || oldest < agg.MinTimestamp) {
+		agg.MinTimestamp = oldest
+	}
+	if !a.sum {
+		return
+	}
+	for _, v := range values {
+		if v.IsTombstone {
+			continue
+		}
+		// words such as inf and nan parse as floats but are not numbers to sum
+		n, err := strconv.ParseFloat(string(v.Value), 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			agg.NonNumeric++
+			continue
+		}
+		agg.Sum += n
+	}
+}
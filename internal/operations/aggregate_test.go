@@ -0,0 +1,130 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_Aggregate(t *testing.T) {
+	rows := &litetable.Data{
+		"match:1": {"stats": {
+			"score": {
+				{Value: []byte("3"), Timestamp: 10, Seq: 1},
+				{Value: []byte("4.5"), Timestamp: 20, Seq: 2},
+			},
+			"note": {{Value: []byte("upset"), Timestamp: 15, Seq: 1}},
+		}},
+		"match:2": {"stats": {
+			"score": {
+				{Value: []byte("nan"), Timestamp: 5, Seq: 1},
+				{Value: []byte("10"), Timestamp: 30, Seq: 3},
+			},
+			"note": {
+				{Value: []byte("draw"), Timestamp: 12, Seq: 1},
+				{Timestamp: 13, IsTombstone: true, Seq: 2},
+			},
+		}},
+	}
+
+	tests := map[string]struct {
+		query     string
+		mockSetup func(m *MockshardManager)
+		expected  []*litetable.Aggregate
+		expectErr error
+	}{
+		"every aggregation over a prefix": {
+			query: "prefix=match: family=stats aggregate=count aggregate=minTimestamp " +
+				"aggregate=maxTimestamp aggregate=sum",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("stats").Return(true)
+				m.EXPECT().Sequence().Return(uint64(3))
+				m.EXPECT().FilterRowsByPrefix("match:", litetable.ScanPage{}).
+					Return(rows, litetable.ScanStats{Examined: 2}, true)
+			},
+			expected: []*litetable.Aggregate{
+				{Family: "stats", Qualifier: "note", Count: 1, MinTimestamp: 15,
+					MaxTimestamp: 15, NonNumeric: 1},
+				{Family: "stats", Qualifier: "score", Count: 4, MinTimestamp: 5,
+					MaxTimestamp: 30, Sum: 17.5, NonNumeric: 1},
+			},
+		},
+		"latest values at a sequence number": {
+			query: "prefix=match: family=stats qualifier=score latest=1 readAt=2 " +
+				"aggregate=count aggregate=sum",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("stats").Return(true)
+				m.EXPECT().FilterRowsByPrefix("match:", litetable.ScanPage{}).
+					Return(rows, litetable.ScanStats{Examined: 2}, true)
+			},
+			expected: []*litetable.Aggregate{
+				{Family: "stats", Qualifier: "score", Count: 2, Sum: 4.5, NonNumeric: 1},
+			},
+		},
+		"single row": {
+			query: "key=match:1 family=stats qualifier=score aggregate=maxTimestamp",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("stats").Return(true)
+				m.EXPECT().GetRowByFamily("match:1", "stats").Return(rows, true)
+			},
+			expected: []*litetable.Aggregate{
+				{Family: "stats", Qualifier: "score", MaxTimestamp: 20},
+			},
+		},
+		"no matches": {
+			query: "regex=^none family=stats aggregate=count",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("stats").Return(true)
+				m.EXPECT().Sequence().Return(uint64(3))
+				m.EXPECT().FilterRowsByRegex("^none", litetable.ScanPage{}).
+					Return(&litetable.Data{}, litetable.ScanStats{Examined: 2}, false, nil)
+			},
+			expected: []*litetable.Aggregate{},
+		},
+		"missing aggregate": {
+			query:     "prefix=match: family=stats",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"unknown aggregate": {
+			query:     "prefix=match: family=stats aggregate=avg",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"jsonPath": {
+			query:     "prefix=match: family=stats aggregate=count jsonPath=score",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"family does not exist": {
+			query: "prefix=match: family=nope aggregate=count",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("nope").Return(false)
+			},
+			expectErr: litetable.ErrFamilyMissing,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			storage := NewMockshardManager(gomock.NewController(t))
+			if tc.mockSetup != nil {
+				tc.mockSetup(storage)
+			}
+
+			m := &Manager{shardStorage: storage}
+			got, _, err := m.Aggregate(tc.query)
+			if tc.expectErr != nil {
+				req.ErrorIs(err, tc.expectErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.expected, got)
+		})
+	}
+}
+
+func TestManager_Read_RejectsAggregate(t *testing.T) {
+	m := &Manager{}
+	_, _, err := m.Read("key=match:1 family=stats aggregate=count")
+	require.ErrorIs(t, err, litetable.ErrInvalidArgument)
+}
@@ -0,0 +1,14 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+)
+
+// ListBackups returns the backup catalog, oldest first.
+func (m *Manager) ListBackups() ([]*litetable.BackupManifest, error) {
+	backups, err := m.shardStorage.ListBackups()
+	if err != nil {
+		return nil, litetable.WrapError(litetable.ErrorCodeInternal, err, "failed to list backups")
+	}
+	return backups, nil
+}
@@ -0,0 +1,26 @@
+package operations
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_ListBackups(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	storage := NewMockshardManager(ctrl)
+	m := &Manager{shardStorage: storage}
+
+	want := []*litetable.BackupManifest{{File: "backup-1.db", FormatVersion: 1}}
+	storage.EXPECT().ListBackups().Return(want, nil)
+	got, err := m.ListBackups()
+	req.NoError(err)
+	req.Equal(want, got)
+
+	storage.EXPECT().ListBackups().Return(nil, errors.New("permission denied"))
+	_, err = m.ListBackups()
+	req.True(errors.Is(err, &litetable.Error{Code: litetable.ErrorCodeInternal}))
+}
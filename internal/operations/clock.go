@@ -0,0 +1,59 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// maxTimestampSkew is how far in the future a write or a delete may set its own timestamp.
+const maxTimestampSkew = time.Minute
+
+// maxClockStep is how far the wall clock can fall behind the clock before it counts as a step
+// back rather than writes in the same instant.
+const maxClockStep = time.Millisecond
+
+var clockStepsBack = metrics.NewCounterVec("litetable_clock_steps_back_total",
+	"Times the wall clock fell behind the timestamps already given to writes.", "clock")
+
+// hybridClock gives writes their timestamps. It follows the wall clock, but every timestamp is
+// after the ones before it: writes in the same nanosecond, or after the wall clock steps back,
+// get the last timestamp plus one, a logical tick, until the wall clock catches up. Its zero
+// value reads time.Now.
+type hybridClock struct {
+	last atomic.Int64
+	wall func() time.Time
+}
+
+// now returns a timestamp in Unix nanoseconds after every timestamp it returned before.
+func (c *hybridClock) now() int64 {
+	wall := time.Now
+	if c.wall != nil {
+		wall = c.wall
+	}
+
+	physical := wall().UnixNano()
+	for {
+		last := c.last.Load()
+		next := physical
+		if next <= last {
+			if last-next > int64(maxClockStep) {
+				clockStepsBack.With("wall").Inc()
+			}
+			next = last + 1
+		}
+		if c.last.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
+
+// observe moves the clock up to a timestamp a write set itself, so later writes are after it.
+func (c *hybridClock) observe(timestamp int64) {
+	for {
+		last := c.last.Load()
+		if timestamp <= last || c.last.CompareAndSwap(last, timestamp) {
+			return
+		}
+	}
+}
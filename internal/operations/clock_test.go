@@ -0,0 +1,97 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHybridClock_now(t *testing.T) {
+	req := require.New(t)
+	wall := time.Unix(0, 1_000)
+	c := &hybridClock{wall: func() time.Time { return wall }}
+
+	req.Equal(int64(1_000), c.now())
+	// the same instant gets a logical tick
+	req.Equal(int64(1_001), c.now())
+
+	// a step back does not take timestamps back
+	wall = time.Unix(0, 500)
+	req.Equal(int64(1_002), c.now())
+
+	// the clock follows the wall clock again once it catches up
+	wall = time.Unix(0, 5_000)
+	req.Equal(int64(5_000), c.now())
+}
+
+func TestHybridClock_observe(t *testing.T) {
+	req := require.New(t)
+	wall := time.Unix(0, 1_000)
+	c := &hybridClock{wall: func() time.Time { return wall }}
+
+	// older timestamps leave the clock alone
+	c.observe(10)
+	req.Equal(int64(1_000), c.now())
+
+	// later writes are after a timestamp set ahead of the wall clock
+	c.observe(2_000)
+	req.Equal(int64(2_001), c.now())
+}
+
+func TestHybridClock_now_Concurrent(t *testing.T) {
+	var c hybridClock
+	var wg sync.WaitGroup
+	timestamps := make([][]int64, 8)
+	for i := range timestamps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				timestamps[i] = append(timestamps[i], c.now())
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int64]struct{})
+	for _, ts := range timestamps {
+		for i, v := range ts {
+			if i > 0 {
+				require.Greater(t, v, ts[i-1])
+			}
+			seen[v] = struct{}{}
+		}
+	}
+	require.Len(t, seen, 8*1000)
+}
+
+func TestManager_Write_MonotonicTimestamps(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	w := NewMockwriteAhead(ctrl)
+	w.EXPECT().Apply(gomock.Any()).Return(nil).Times(2)
+	s := NewMockshardManager(ctrl)
+	expectExpiresAt(s)
+	s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(), gomock.Any(),
+		int64(0)).Return(nil).Times(2)
+
+	m := &Manager{writeAhead: w, shardStorage: s}
+	wall := time.Unix(0, 1_000)
+	m.clock.wall = func() time.Time { return wall }
+
+	first, err := m.Write("key=champ:1 family=wrestlers qualifier=name value=John")
+	req.NoError(err)
+	// the wall clock steps back between the writes
+	wall = time.Unix(0, 10)
+	second, err := m.Write("key=champ:1 family=wrestlers qualifier=name value=Randy")
+	req.NoError(err)
+
+	timestamp := func(rows map[string]*litetable.Row) int64 {
+		return rows["champ:1"].Columns["wrestlers"]["name"][0].Timestamp
+	}
+	req.Equal(int64(1_000), timestamp(first))
+	req.Equal(int64(1_001), timestamp(second))
+}
@@ -0,0 +1,127 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"github.com/litetable/litetable-db/internal/shard_storage/wal"
+	"sync"
+	"time"
+)
+
+// defaultCoalesceDelay is how long the first write of a group waits for others by default.
+const defaultCoalesceDelay = 200 * time.Microsecond
+
+var (
+	writeGroups = metrics.NewCounter("litetable_write_groups_total",
+		"Groups of coalesced writes applied together.")
+	coalescedWrites = metrics.NewCounter("litetable_coalesced_writes_total",
+		"Writes applied as part of a group.")
+)
+
+// CoalesceConfig turns on group commit: concurrent single-family writes to the same table are
+// applied together, with one WAL record and one lock of each shard they touch, so many small
+// writers share those costs. Each write still gets its own result.
+type CoalesceConfig struct {
+	// MaxBatch is the most writes in a group. Writes are not coalesced below 2.
+	MaxBatch int
+	// MaxDelay is how long the first write of a group waits for others before the group is
+	// applied, which adds up to that much latency to every write. Defaults to 200µs.
+	MaxDelay time.Duration
+}
+
+// coalescer groups concurrent writes by the storage they write to. The first write of a group
+// leads it: it waits until the group is full or MaxDelay passes, then applies the group while
+// the others wait for their results.
+type coalescer struct {
+	maxBatch int
+	maxDelay time.Duration
+
+	mutex   sync.Mutex
+	pending map[shardManager]*writeGroup
+}
+
+// writeGroup is a group of writes to one storage.
+type writeGroup struct {
+	entries   []*wal.Entry
+	mutations []litetable.Mutation
+	errs      []error
+	// full is closed once the group has maxBatch writes
+	full chan struct{}
+	// done is closed once the group is applied and errs are set
+	done chan struct{}
+}
+
+// newCoalescer returns a coalescer for cfg, or nil when coalescing is off.
+func newCoalescer(cfg *CoalesceConfig) *coalescer {
+	if cfg == nil || cfg.MaxBatch < 2 {
+		return nil
+	}
+	delay := cfg.MaxDelay
+	if delay <= 0 {
+		delay = defaultCoalesceDelay
+	}
+	return &coalescer{
+		maxBatch: cfg.MaxBatch,
+		maxDelay: delay,
+		pending:  make(map[shardManager]*writeGroup),
+	}
+}
+
+// apply logs and applies a write as part of a group and returns its result.
+func (c *coalescer) apply(writeAhead writeAhead, storage shardManager, entry *wal.Entry,
+	mutation litetable.Mutation) error {
+	c.mutex.Lock()
+	group, joined := c.pending[storage]
+	if !joined {
+		group = &writeGroup{full: make(chan struct{}), done: make(chan struct{})}
+		c.pending[storage] = group
+	}
+	i := len(group.mutations)
+	group.entries = append(group.entries, entry)
+	group.mutations = append(group.mutations, mutation)
+	if len(group.mutations) == c.maxBatch {
+		// later writes start a new group
+		delete(c.pending, storage)
+		close(group.full)
+	}
+	c.mutex.Unlock()
+
+	if joined {
+		<-group.done
+		return group.errs[i]
+	}
+
+	timer := time.NewTimer(c.maxDelay)
+	select {
+	case <-group.full:
+	case <-timer.C:
+	}
+	timer.Stop()
+
+	c.mutex.Lock()
+	if c.pending[storage] == group {
+		delete(c.pending, storage)
+	}
+	c.mutex.Unlock()
+
+	group.apply(writeAhead, storage)
+	close(group.done)
+	return group.errs[i]
+}
+
+// apply writes the group to the WAL in one record and then to the storage. No write of the
+// group is applied if the WAL cannot be written.
+func (g *writeGroup) apply(writeAhead writeAhead, storage shardManager) {
+	writeGroups.Inc()
+	coalescedWrites.Add(float64(len(g.mutations)))
+
+	g.errs = make([]error, len(g.mutations))
+	if err := writeAhead.ApplyBatch(g.entries); err != nil {
+		err = litetable.WrapError(litetable.ErrorCodeInternal, err, "failed to write to WAL")
+		for i := range g.errs {
+			g.errs[i] = err
+		}
+		return
+	}
+	copy(g.errs, storage.ApplyBatch(g.mutations))
+}
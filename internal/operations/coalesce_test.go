@@ -0,0 +1,136 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/shard_storage/wal"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewCoalescer(t *testing.T) {
+	tests := map[string]struct {
+		cfg         *CoalesceConfig
+		expectOff   bool
+		expectDelay time.Duration
+	}{
+		"nil config":    {expectOff: true},
+		"batch of one":  {cfg: &CoalesceConfig{MaxBatch: 1}, expectOff: true},
+		"default delay": {cfg: &CoalesceConfig{MaxBatch: 8}, expectDelay: defaultCoalesceDelay},
+		"configured delay": {
+			cfg:         &CoalesceConfig{MaxBatch: 8, MaxDelay: time.Millisecond},
+			expectDelay: time.Millisecond,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newCoalescer(tc.cfg)
+			if tc.expectOff {
+				require.Nil(t, c)
+				return
+			}
+			require.Equal(t, tc.expectDelay, c.maxDelay)
+		})
+	}
+}
+
+func TestManager_Write_coalesced(t *testing.T) {
+	const writers = 4
+
+	tests := map[string]struct {
+		mockSetup func(w *MockwriteAhead, s *MockshardManager)
+		// expectErr is the error of every write, except for champ:2 with expectErr2
+		expectErr  error
+		expectErr2 error
+	}{
+		"full group is applied together": {
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				gomock.InOrder(
+					w.EXPECT().ApplyBatch(gomock.Len(writers)).Return(nil),
+					s.EXPECT().ApplyBatch(gomock.Len(writers)).DoAndReturn(
+						func(mutations []litetable.Mutation) []error {
+							errs := make([]error, len(mutations))
+							for i, mutation := range mutations {
+								if mutation.RowKey == "champ:2" {
+									errs[i] = litetable.NewError(litetable.ErrorCodeExhausted,
+										"quota exceeded")
+								}
+							}
+							return errs
+						}),
+				)
+			},
+			expectErr2: litetable.ErrExhausted,
+		},
+		"failed WAL write fails the group": {
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().ApplyBatch(gomock.Len(writers)).Return(errors.New("disk full"))
+			},
+			expectErr:  &litetable.Error{Code: litetable.ErrorCodeInternal},
+			expectErr2: &litetable.Error{Code: litetable.ErrorCodeInternal},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			w := NewMockwriteAhead(ctrl)
+			s := NewMockshardManager(ctrl)
+			expectExpiresAt(s)
+			tc.mockSetup(w, s)
+
+			// the delay is long enough that the group is only applied once it is full
+			m := &Manager{
+				writeAhead:   w,
+				shardStorage: s,
+				coalescer: newCoalescer(&CoalesceConfig{
+					MaxBatch: writers,
+					MaxDelay: time.Minute,
+				}),
+			}
+
+			var wg sync.WaitGroup
+			errs := make([]error, writers)
+			for i := range writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = m.Write(fmt.Sprintf(
+						"key=champ:%d family=wrestlers qualifier=name value=John", i))
+				}()
+			}
+			wg.Wait()
+
+			for i, err := range errs {
+				expectErr := tc.expectErr
+				if i == 2 {
+					expectErr = tc.expectErr2
+				}
+				if expectErr != nil {
+					require.ErrorIs(t, err, expectErr)
+					continue
+				}
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCoalescer_apply_delay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w := NewMockwriteAhead(ctrl)
+	s := NewMockshardManager(ctrl)
+	expectExpiresAt(s)
+	w.EXPECT().ApplyBatch(gomock.Len(1)).Return(nil)
+	s.EXPECT().ApplyBatch(gomock.Len(1)).Return([]error{nil})
+
+	// a lone write is applied once the delay passes
+	c := newCoalescer(&CoalesceConfig{MaxBatch: 8, MaxDelay: time.Millisecond})
+	require.NoError(t, c.apply(w, s, &wal.Entry{}, litetable.Mutation{RowKey: "champ:1"}))
+	require.Empty(t, c.pending)
+}
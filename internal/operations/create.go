@@ -1,21 +1,67 @@
 package operations
 
-func (m *Manager) CreateFamilies(families []string) error {
+import "github.com/litetable/litetable-db/internal/litetable"
+
+// CreateFamilies creates column families in a table. An empty table is the default table.
+func (m *Manager) CreateFamilies(table string, families []string) error {
 	if len(families) == 0 {
 		return newError(errInvalidFormat, "creating a family requires at least one family name")
 	}
 
+	storage, err := m.storage(table, "create_family")
+	if err != nil {
+		return err
+	}
+
+	if err = m.checkFamilies(families); err != nil {
+		return err
+	}
+
 	// make sure the families are not allowed currently if they are it exists
 	for _, family := range families {
-		if m.shardStorage.IsFamilyAllowed(family) {
-			return newError(errInvalidFormat, "family %s already exists", family)
+		if storage.IsFamilyAllowed(family) {
+			return litetable.NewError(litetable.ErrorCodeConflict, "family %s already exists", family)
 		}
 	}
 
 	// Update the shard storage with the new families
-	err := m.shardStorage.UpdateFamilies(families)
+	err = storage.UpdateFamilies(families)
 	if err != nil {
-		return newError(err, "failed to update families")
+		return litetable.WrapError(litetable.ErrorCodeInternal, err, "failed to update families")
 	}
 	return nil
 }
+
+// CloneFamily creates the column family target in a table holding a copy of the rows of the
+// family source, for experiments and backfills that must not touch the source. It returns the
+// number of rows and cells copied. An empty table is the default table.
+func (m *Manager) CloneFamily(table, source, target string) (int, int, error) {
+	// a clone is as large a write as the source family, so it is refused like any other write
+	// while the disk is low or the server is overloaded
+	if err := m.checkDisk(); err != nil {
+		return 0, 0, err
+	}
+	if err := m.admit(); err != nil {
+		return 0, 0, err
+	}
+
+	storage, err := m.storage(table, "clone_family")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err = m.checkFamilies([]string{target}); err != nil {
+		return 0, 0, err
+	}
+	if !storage.IsFamilyAllowed(source) {
+		return 0, 0, litetable.NewError(litetable.ErrorCodeFamilyMissing,
+			"column family does not exist: %s", source)
+	}
+	if storage.IsFamilyAllowed(target) {
+		return 0, 0, litetable.NewError(litetable.ErrorCodeConflict, "family %s already exists",
+			target)
+	}
+
+	// quota errors keep their code
+	return storage.CloneFamily(source, target)
+}
@@ -0,0 +1,75 @@
+package operations
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_CloneFamily(t *testing.T) {
+	tests := map[string]struct {
+		target    string
+		mockSetup func(m *MockshardManager)
+		wantRows  int
+		wantErr   error
+	}{
+		"clone": {
+			target: "wrestlers_copy",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().IsFamilyAllowed("wrestlers_copy").Return(false)
+				m.EXPECT().CloneFamily("wrestlers", "wrestlers_copy").Return(2, 5, nil)
+			},
+			wantRows: 2,
+		},
+		"missing source": {
+			target: "wrestlers_copy",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(false)
+			},
+			wantErr: litetable.ErrFamilyMissing,
+		},
+		"existing target": {
+			target: "wrestlers_copy",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().IsFamilyAllowed("wrestlers_copy").Return(true)
+			},
+			wantErr: litetable.ErrConflict,
+		},
+		"target the pattern rejects": {
+			target:    "bad/family",
+			mockSetup: func(m *MockshardManager) {},
+			wantErr:   litetable.ErrInvalidArgument,
+		},
+		"quota error keeps its code": {
+			target: "wrestlers_copy",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().IsFamilyAllowed("wrestlers_copy").Return(false)
+				m.EXPECT().CloneFamily("wrestlers", "wrestlers_copy").
+					Return(0, 0, litetable.NewError(litetable.ErrorCodeExhausted, "full"))
+			},
+			wantErr: litetable.ErrExhausted,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			s := NewMockshardManager(gomock.NewController(t))
+			tc.mockSetup(s)
+
+			m := &Manager{shardStorage: s}
+			rows, _, err := m.CloneFamily("", "wrestlers", tc.target)
+			if tc.wantErr != nil {
+				req.True(errors.Is(err, tc.wantErr), err)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.wantRows, rows)
+		})
+	}
+}
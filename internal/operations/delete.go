@@ -1,7 +1,6 @@
 package operations
 
 import (
-	"fmt"
 	"github.com/litetable/litetable-db/internal/litetable"
 	wal2 "github.com/litetable/litetable-db/internal/shard_storage/wal"
 	"strconv"
@@ -10,57 +9,86 @@ import (
 )
 
 func (m *Manager) Delete(query string) error {
+	if err := m.admit(); err != nil {
+		return err
+	}
 	if err := m.writeAhead.Apply(&wal2.Entry{
 		Operation: litetable.OperationDelete,
 		Query:     []byte(query),
 		Timestamp: time.Now(),
 	}); err != nil {
-		return err
+		return litetable.WrapError(litetable.ErrorCodeInternal, err, "failed to write to WAL")
 	}
 
 	// Parse the query
-	parsed, err := parseDeleteQuery(query)
+	parsed, err := parseDeleteQuery(query, m.clock.now())
 	if err != nil {
 		return err
 	}
+	m.clock.observe(parsed.timestamp)
+	if err = m.checkNames(parsed.rowKey); err != nil {
+		return err
+	}
 
-	err = m.shardStorage.Delete(parsed.rowKey, parsed.family, parsed.qualifiers, parsed.timestamp, parsed.expiresAt)
+	storage, err := m.storage(parsed.table, "delete")
 	if err != nil {
 		return err
 	}
+	if err = m.checkFence(parsed.table, parsed.rowKey, parsed.fence); err != nil {
+		return err
+	}
+
+	err = storage.Delete(parsed.rowKey, parsed.family, parsed.qualifiers, parsed.timestamp, parsed.expiresAt)
+	if err != nil {
+		return err
+	}
+	countMutations(litetable.Mutation{Operation: litetable.OperationDelete, Family: parsed.family})
 	return nil
 }
 
 type deleteQuery struct {
+	table      string
 	rowKey     string
 	family     string
 	qualifiers []string
 	timestamp  int64 // this is either the current time or the provided timestamp
 	ttl        int64
 	expiresAt  int64
+	fence      uint64
 }
 
-func parseDeleteQuery(input string) (*deleteQuery, error) {
-	parts := strings.Fields(input)
-	now := time.Now()
-	defaultExpiresAt := now.Add(time.Hour).UnixNano()
+// parseDeleteQuery parses a delete query string in the grammar version it names, with the
+// tombstones written at now unless the query sets a timestamp.
+func parseDeleteQuery(input string, now int64) (*deleteQuery, error) {
+	version, query, err := queryVersion(input)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenizeQuery(version, query)
+	if err != nil {
+		return nil, err
+	}
+	return parseDeleteTokens(tokens, now)
+}
+
+// parseDeleteTokens parses the parameters of a delete query.
+func parseDeleteTokens(tokens []queryToken, now int64) (*deleteQuery, error) {
 	parsed := &deleteQuery{
 		qualifiers: []string{},
 		ttl:        3600,
-		timestamp:  now.UnixNano(),
-		expiresAt:  defaultExpiresAt,
+		timestamp:  now,
 	}
 
-	for _, part := range parts {
-		kv := strings.SplitN(part, "=", 2)
-		if len(kv) != 2 {
-			return nil, fmt.Errorf("invalid format: %s", part)
+	for _, token := range tokens {
+		if !token.ok {
+			return nil, newError(errInvalidFormat, "%s", token.raw)
 		}
 
-		key, value := kv[0], kv[1]
-		key = strings.TrimLeft(key, "-")
+		key, value := strings.TrimLeft(token.name, "-"), token.value
 
 		switch key {
+		case "table":
+			parsed.table = value
 		case "key":
 			parsed.rowKey = value
 		case "family":
@@ -69,29 +97,45 @@ func parseDeleteQuery(input string) (*deleteQuery, error) {
 			parsed.qualifiers = append(parsed.qualifiers, value)
 		case "timestamp":
 			timestamp, err := strconv.ParseInt(value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid timestamp value: %s", value)
+			if err != nil || timestamp <= 0 {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"invalid timestamp value: %s", value)
+			}
+			// a tombstone far in the future would hide every later write to its cells
+			if timestamp > now+int64(maxTimestampSkew) {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"timestamp %d is more than %s in the future", timestamp, maxTimestampSkew)
 			}
 			parsed.timestamp = timestamp
 		case "ttl":
 			ttlSec, err := strconv.ParseInt(value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid ttl value: %s", value)
+			if err != nil || ttlSec < 0 {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"invalid ttl value: %s", value)
+			}
+			if ttlSec > int64(litetable.MaxTTL/time.Second) {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"ttl %d is longer than %s", ttlSec, litetable.MaxTTL)
 			}
 			parsed.ttl = ttlSec
-
-			ttlNanos := parsed.ttl * 1_000_000_000
-			ttlTime := parsed.timestamp + ttlNanos
-			parsed.expiresAt = ttlTime
+		case "fence":
+			fence, err := parseFence(value)
+			if err != nil {
+				return nil, err
+			}
+			parsed.fence = fence
 
 		default:
-			return nil, fmt.Errorf("unknown parameter: %s", key)
+			return nil, newError(errUnknownParameter, "%s", key)
 		}
 	}
 
+	// the tombstone expires ttl after it is written, whichever order the parameters are in
+	parsed.expiresAt = parsed.timestamp + parsed.ttl*int64(time.Second)
+
 	// Validate required fields
 	if parsed.rowKey == "" {
-		return nil, fmt.Errorf("missing key")
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument, "missing key")
 	}
 
 	return parsed, nil
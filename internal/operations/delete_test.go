@@ -0,0 +1,111 @@
+package operations
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+// FuzzParseDeleteQuery locks in the delete grammar, which like reads does not decode its values.
+func FuzzParseDeleteQuery(f *testing.F) {
+	f.Add("champ:1", "main", "name")
+	f.Fuzz(func(t *testing.T, key, family, qualifier string) {
+		req := require.New(t)
+		now := time.Now().UnixNano()
+		parsed, err := parseDeleteQuery(
+			"key="+key+" family="+family+" qualifier="+qualifier, now)
+		if err == nil {
+			req.NotEmpty(parsed.rowKey)
+		}
+		// a space splits a value into more pairs, so only values without one are read back
+		if strings.ContainsFunc(key+family+qualifier, unicode.IsSpace) {
+			return
+		}
+
+		if key == "" {
+			req.Error(err)
+			return
+		}
+		req.NoError(err)
+		req.Equal(key, parsed.rowKey)
+		req.Equal(family, parsed.family)
+		req.Equal([]string{qualifier}, parsed.qualifiers)
+		req.Equal(now, parsed.timestamp)
+		req.Equal(now+int64(time.Hour), parsed.expiresAt)
+	})
+}
+
+func TestParseDeleteQuery_timestamp(t *testing.T) {
+	now := time.Now().UnixNano()
+	tests := map[string]struct {
+		timestamp int64
+		expectErr bool
+	}{
+		"backfill":              {timestamp: 1000},
+		"in the near future":    {timestamp: now + int64(30*time.Second)},
+		"far in the future":     {timestamp: now + int64(time.Hour), expectErr: true},
+		"zero":                  {timestamp: 0, expectErr: true},
+		"before the Unix epoch": {timestamp: -1, expectErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			parsed, err := parseDeleteQuery(
+				fmt.Sprintf("key=champ:1 family=main timestamp=%d", tc.timestamp), now)
+			if tc.expectErr {
+				req.ErrorIs(err, litetable.ErrInvalidArgument)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.timestamp, parsed.timestamp)
+		})
+	}
+}
+
+func TestParseDeleteQuery_ttl(t *testing.T) {
+	now := time.Now().UnixNano()
+	tests := map[string]struct {
+		query     string
+		expiresAt int64
+		expectErr bool
+	}{
+		"default": {
+			query:     "key=champ:1 family=main",
+			expiresAt: now + int64(time.Hour),
+		},
+		"ttl before the timestamp": {
+			query:     "key=champ:1 family=main ttl=60 timestamp=1000",
+			expiresAt: 1000 + int64(time.Minute),
+		},
+		"ttl after the timestamp": {
+			query:     "key=champ:1 family=main timestamp=1000 ttl=60",
+			expiresAt: 1000 + int64(time.Minute),
+		},
+		"negative": {
+			query:     "key=champ:1 family=main ttl=-1",
+			expectErr: true,
+		},
+		"longer than the longest ttl": {
+			query:     "key=champ:1 family=main ttl=9223372036854775807",
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			parsed, err := parseDeleteQuery(tc.query, now)
+			if tc.expectErr {
+				req.ErrorIs(err, litetable.ErrInvalidArgument)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.expiresAt, parsed.expiresAt)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package operations
 
 import (
 	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -14,8 +15,9 @@ func Test_newError(t *testing.T) {
 		req.NotNil(err)
 		req.Implements((*error)(nil), err)
 
-		req.Equal(errInvalidFormat, err.err)
+		req.Equal(litetable.ErrorCodeInvalidArgument, err.Code)
 		req.True(errors.Is(err, errInvalidFormat))
+		req.True(errors.Is(err, litetable.ErrInvalidArgument))
 	})
 
 	t.Run("test error wrapping with context", func(t *testing.T) {
@@ -23,7 +25,7 @@ func Test_newError(t *testing.T) {
 		req.NotNil(err)
 		req.Implements((*error)(nil), err)
 
-		req.Equal(errInvalidFormat, err.err)
+		req.Equal(litetable.ErrorCodeInvalidArgument, err.Code)
 		req.True(errors.Is(err, errInvalidFormat))
 		req.Equal("invalid format: test error: context", err.Error())
 	})
@@ -0,0 +1,40 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+)
+
+// Operations are only counted once they succeed, which they cannot for a family the table does
+// not allow, so the family label is bounded by the allowed families.
+var (
+	familyOperations = metrics.NewCounterVec("litetable_family_operations_total",
+		"Reads, scans, writes and deletes of each column family.", "family", "operation")
+	familyWrittenBytes = metrics.NewCounterVec("litetable_family_written_bytes_total",
+		"Value bytes written to each column family.", "family")
+)
+
+// countRead counts a read of a family, as a scan when it matches rows by prefix or regex.
+func countRead(parsed *readQuery) {
+	operation := "read"
+	if parsed.rowKeyPrefix != "" || parsed.rowKeyRegex != "" {
+		operation = "scan"
+	}
+	familyOperations.With(parsed.family, operation).Inc()
+}
+
+// countMutations counts the writes and deletes of applied mutations, with the bytes written.
+func countMutations(mutations ...litetable.Mutation) {
+	for _, mutation := range mutations {
+		if mutation.Operation == litetable.OperationDelete {
+			familyOperations.With(mutation.Family, "delete").Inc()
+			continue
+		}
+		familyOperations.With(mutation.Family, "write").Inc()
+		var bytes int
+		for _, value := range mutation.Values {
+			bytes += len(value)
+		}
+		familyWrittenBytes.With(mutation.Family).Add(float64(bytes))
+	}
+}
@@ -0,0 +1,111 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_familyMetrics(t *testing.T) {
+	tests := map[string]struct {
+		// each case uses its own family, so the counters of other tests do not interfere
+		family    string
+		run       func(m *Manager, family string) error
+		mockSetup func(w *MockwriteAhead, s *MockshardManager, family string)
+		operation string
+		bytes     float64
+	}{
+		"read": {
+			family: "metered_read",
+			run: func(m *Manager, family string) error {
+				_, _, err := m.Read("key=champ:1 family=" + family)
+				return err
+			},
+			mockSetup: func(_ *MockwriteAhead, s *MockshardManager, family string) {
+				s.EXPECT().IsFamilyAllowed(family).Return(true)
+				s.EXPECT().GetRowByFamily("champ:1", family).Return(nil, false)
+			},
+			operation: "read",
+		},
+		"scan": {
+			family: "metered_scan",
+			run: func(m *Manager, family string) error {
+				_, _, err := m.Read("prefix=champ family=" + family)
+				return err
+			},
+			mockSetup: func(_ *MockwriteAhead, s *MockshardManager, family string) {
+				expectScanCache(s, "champ", family, 0)
+				s.EXPECT().IsFamilyAllowed(family).Return(true)
+				s.EXPECT().Sequence().Return(uint64(1))
+				s.EXPECT().FilterRowsByPrefix("champ", litetable.ScanPage{}).
+					Return(nil, litetable.ScanStats{Examined: 2}, false)
+			},
+			operation: "scan",
+		},
+		"write": {
+			family: "metered_write",
+			run: func(m *Manager, family string) error {
+				_, err := m.Write("key=champ:1 family=" + family +
+					" qualifier=name value=John qualifier=title value=WWE")
+				return err
+			},
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager, family string) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Apply("champ:1", family, gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any()).Return(nil)
+			},
+			operation: "write",
+			bytes:     7,
+		},
+		"delete": {
+			family: "metered_delete",
+			run: func(m *Manager, family string) error {
+				return m.Delete("key=champ:1 family=" + family)
+			},
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager, family string) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Delete("champ:1", family, gomock.Any(), gomock.Any(),
+					gomock.Any()).Return(nil)
+			},
+			operation: "delete",
+		},
+		"failed writes are not counted": {
+			family: "metered_missing",
+			run: func(m *Manager, family string) error {
+				_, err := m.Write("key=champ:1 family=" + family + " qualifier=name value=John")
+				require.ErrorIs(t, err, litetable.ErrFamilyMissing)
+				return nil
+			},
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager, family string) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Apply("champ:1", family, gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any()).Return(litetable.NewError(litetable.ErrorCodeFamilyMissing,
+					"column family not allowed: %s", family))
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			w := NewMockwriteAhead(ctrl)
+			s := NewMockshardManager(ctrl)
+			expectExpiresAt(s)
+			tc.mockSetup(w, s, tc.family)
+			m := &Manager{writeAhead: w, shardStorage: s}
+
+			require.NoError(t, tc.run(m, tc.family))
+
+			for _, operation := range []string{"read", "scan", "write", "delete"} {
+				expect := 0.0
+				if operation == tc.operation {
+					expect = 1
+				}
+				require.Equal(t, expect,
+					familyOperations.With(tc.family, operation).Value(), operation)
+			}
+			require.Equal(t, tc.bytes, familyWrittenBytes.With(tc.family).Value())
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package operations
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// latestQueryVersion is the newest version of the text query grammar. A query names the version
+// it is written in with a prefix, as in "v1:key=champ:1 family=main", and a query without one is
+// version 1, the grammar clients spoke before queries were versioned. A grammar change, such as
+// quoted values or new operators, adds a version, so clients written against an older one keep
+// working.
+const latestQueryVersion = 2
+
+// queryGrammar is how a version of the grammar splits a query into its parameters.
+type queryGrammar struct {
+	tokenize func(input string) ([]queryToken, error)
+	// decode returns the value written by a value parameter of a write
+	decode func(value string) (string, error)
+}
+
+// grammars are the versions of the text query grammar, by version.
+//
+// Version 1 splits a query on whitespace and each parameter on its first =, so keys and values
+// cannot contain either, and values written are URL-encoded. Version 2 adds double quotes, which
+// keep whitespace and = in a value, and backslash escapes, so values are written as they are.
+var grammars = map[int]queryGrammar{
+	1: {tokenize: tokenizeV1, decode: url.QueryUnescape},
+	2: {tokenize: tokenizeV2, decode: func(value string) (string, error) { return value, nil }},
+}
+
+// queryToken is a name=value parameter of a query.
+type queryToken struct {
+	// raw is the parameter as it is written in the query
+	raw         string
+	name, value string
+	// ok is whether the parameter has an =
+	ok bool
+}
+
+// tokenizeQuery splits a query without its version prefix into its parameters.
+func tokenizeQuery(version int, input string) ([]queryToken, error) {
+	return grammars[version].tokenize(input)
+}
+
+// tokenizeV1 splits a query in version 1 of the grammar into its parameters.
+func tokenizeV1(input string) ([]queryToken, error) {
+	parts := strings.Fields(input)
+	tokens := make([]queryToken, 0, len(parts))
+	for _, part := range parts {
+		name, value, ok := strings.Cut(part, "=")
+		tokens = append(tokens, queryToken{raw: part, name: name, value: value, ok: ok})
+	}
+	return tokens, nil
+}
+
+// tokenizeV2 splits a query in version 2 of the grammar into its parameters. Parameters are
+// separated by whitespace outside of double quotes, a backslash makes the character after it
+// literal, and the first = that is neither quoted nor escaped separates a name from its value,
+// as in value="Jinx \"the loose cannon\"" or value=a\ b\=c.
+func tokenizeV2(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	var text strings.Builder
+	var token queryToken
+	// start is where the current parameter began, or -1 between parameters
+	start := -1
+	quoted, escaped := false, false
+
+	end := func(at int) {
+		if token.ok {
+			token.value = text.String()
+		} else {
+			token.name = text.String()
+		}
+		token.raw = input[start:at]
+		tokens = append(tokens, token)
+		token, start = queryToken{}, -1
+		text.Reset()
+	}
+
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		char := input[i : i+size]
+		switch {
+		case start == -1 && unicode.IsSpace(r):
+		case escaped:
+			text.WriteString(char)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(r):
+			end(i)
+			i += size
+			continue
+		case !quoted && r == '=' && !token.ok:
+			token.name, token.ok = text.String(), true
+			text.Reset()
+		default:
+			text.WriteString(char)
+		}
+		if start == -1 && !unicode.IsSpace(r) {
+			start = i
+		}
+		i += size
+	}
+
+	switch {
+	case quoted:
+		return nil, newError(errInvalidFormat, "unterminated quote in %s", input[start:])
+	case escaped:
+		return nil, newError(errInvalidFormat, "trailing backslash in %s", input[start:])
+	case start != -1:
+		end(len(input))
+	}
+	return tokens, nil
+}
+
+// queryVersion returns the grammar version of a query and the query without its version
+// prefix.
+func queryVersion(input string) (int, string, error) {
+	prefix, query := cutQueryVersion(input)
+	if prefix == "" {
+		return 1, query, nil
+	}
+
+	name := strings.TrimSuffix(prefix, ":")
+	version, err := strconv.Atoi(name[1:])
+	if err != nil || version < 1 || version > latestQueryVersion {
+		return 0, "", newError(errUnsupportedVersion,
+			"query version %s is not supported, the latest is v%d", name, latestQueryVersion)
+	}
+	return version, query, nil
+}
+
+// cutQueryVersion takes the version prefix, such as v1:, off the front of a query and returns
+// it along with the rest of the query. The prefix of a query without one is empty.
+func cutQueryVersion(input string) (string, string) {
+	trimmed := strings.TrimLeftFunc(input, unicode.IsSpace)
+	if !strings.HasPrefix(trimmed, "v") {
+		return "", input
+	}
+	digits := len(trimmed[1:]) - len(strings.TrimLeft(trimmed[1:], "0123456789"))
+	if digits == 0 || !strings.HasPrefix(trimmed[1+digits:], ":") {
+		return "", input
+	}
+	return trimmed[:digits+2], trimmed[digits+2:]
+}
@@ -0,0 +1,222 @@
+package operations
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryVersion(t *testing.T) {
+	tests := map[string]struct {
+		input       string
+		wantVersion int
+		wantQuery   string
+		wantErr     bool
+	}{
+		"unversioned": {
+			input:       "key=champ:1 family=main",
+			wantVersion: 1,
+			wantQuery:   "key=champ:1 family=main",
+		},
+		"version 1": {
+			input:       "v1:key=champ:1 family=main",
+			wantVersion: 1,
+			wantQuery:   "key=champ:1 family=main",
+		},
+		"version 2": {
+			input:       "v2:key=champ:1",
+			wantVersion: 2,
+			wantQuery:   "key=champ:1",
+		},
+		"version 1 with spaces": {
+			input:       "  v1: key=champ:1",
+			wantVersion: 1,
+			wantQuery:   " key=champ:1",
+		},
+		// a value that looks like a prefix is not one
+		"prefix in a value": {
+			input:       "key=v2:champ family=main",
+			wantVersion: 1,
+			wantQuery:   "key=v2:champ family=main",
+		},
+		"not a version": {
+			input:       "vx:key=champ:1",
+			wantVersion: 1,
+			wantQuery:   "vx:key=champ:1",
+		},
+		"newer version":   {input: "v3:key=champ:1", wantErr: true},
+		"version 0":       {input: "v0:key=champ:1", wantErr: true},
+		"huge version":    {input: "v99999999999999999999:key=champ:1", wantErr: true},
+		"only the prefix": {input: "v1:", wantVersion: 1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			version, query, err := queryVersion(tc.input)
+			if tc.wantErr {
+				req.ErrorIs(err, errUnsupportedVersion)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.wantVersion, version)
+			req.Equal(tc.wantQuery, query)
+		})
+	}
+}
+
+func TestParsers_versioned(t *testing.T) {
+	req := require.New(t)
+	now := time.Now().UnixNano()
+
+	read, err := parseRead("v1:key=champ:1 family=main")
+	req.NoError(err)
+	req.Equal("champ:1", read.rowKey)
+	_, err = parseRead("v3:key=champ:1 family=main")
+	req.ErrorIs(err, errUnsupportedVersion)
+
+	write, err := parseWriteQuery("v1:key=champ:1 family=main qualifier=name value=Ahri", now)
+	req.NoError(err)
+	req.Equal("champ:1", write.rowKey)
+	_, err = parseWriteQuery("v3:key=champ:1 family=main qualifier=name value=Ahri", now)
+	req.ErrorIs(err, errUnsupportedVersion)
+
+	del, err := parseDeleteQuery("v1:key=champ:1 family=main", now)
+	req.NoError(err)
+	req.Equal("champ:1", del.rowKey)
+	_, err = parseDeleteQuery("v3:key=champ:1", now)
+	req.ErrorIs(err, errUnsupportedVersion)
+}
+
+func TestIdempotencyKey_versioned(t *testing.T) {
+	key, request := idempotencyKey("v1:idempotencyKey=abc key=champ:1 family=main")
+	require.Equal(t, "abc", key)
+	require.Equal(t, "v1:key=champ:1 family=main", request)
+}
+
+func TestTokenizeV2(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    []queryToken
+		wantErr bool
+	}{
+		"plain": {
+			input: " key=champ:1  family=main ",
+			want: []queryToken{
+				{raw: "key=champ:1", name: "key", value: "champ:1", ok: true},
+				{raw: "family=main", name: "family", value: "main", ok: true},
+			},
+		},
+		"quoted value": {
+			input: `value="Jinx = the loose  cannon"`,
+			want: []queryToken{{raw: `value="Jinx = the loose  cannon"`, name: "value",
+				value: "Jinx = the loose  cannon", ok: true}},
+		},
+		"escaped value": {
+			input: `value=a\ b\=c\\ key=x`,
+			want: []queryToken{
+				{raw: `value=a\ b\=c\\`, name: "value", value: `a b=c\`, ok: true},
+				{raw: "key=x", name: "key", value: "x", ok: true},
+			},
+		},
+		"escaped quote": {
+			input: `value="say \"hi\""`,
+			want: []queryToken{
+				{raw: `value="say \"hi\""`, name: "value", value: `say "hi"`, ok: true},
+			},
+		},
+		"quoted name": {
+			input: `"qualifier=name"`,
+			want:  []queryToken{{raw: `"qualifier=name"`, name: "qualifier=name"}},
+		},
+		"empty quotes": {
+			input: `value=""`,
+			want:  []queryToken{{raw: `value=""`, name: "value", ok: true}},
+		},
+		"unicode": {
+			input: `value="日本 語"`,
+			want:  []queryToken{{raw: `value="日本 語"`, name: "value", value: "日本 語", ok: true}},
+		},
+		"unterminated quote": {input: `key=x value="Jinx`, wantErr: true},
+		"trailing backslash": {input: `value=Jinx\`, wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			tokens, err := tokenizeV2(tc.input)
+			if tc.wantErr {
+				req.ErrorIs(err, errInvalidFormat)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.want, tokens)
+		})
+	}
+}
+
+func TestParsers_v2(t *testing.T) {
+	req := require.New(t)
+	now := time.Now().UnixNano()
+
+	read, err := parseRead(`v2:key="champ 1" family=main qualifier="first=name"`)
+	req.NoError(err)
+	req.Equal("champ 1", read.rowKey)
+	req.Equal([]string{"first=name"}, read.qualifiers)
+
+	// values are written as they are, without URL decoding
+	write, err := parseWriteQuery(
+		`v2:key=champ\ 1 family=main qualifier=name value="100% = Ahri+Jinx"`, now)
+	req.NoError(err)
+	req.Equal("champ 1", write.rowKey)
+	req.Equal([][]byte{[]byte("100% = Ahri+Jinx")}, write.cells[0].values)
+
+	del, err := parseDeleteQuery(`v2:key="champ 1" family=main qualifier="a b"`, now)
+	req.NoError(err)
+	req.Equal("champ 1", del.rowKey)
+	req.Equal([]string{"a b"}, del.qualifiers)
+
+	_, err = parseWriteQuery(`v2:key=champ:1 family=main qualifier=name value="Ahri`, now)
+	req.ErrorIs(err, errInvalidFormat)
+	// version 1 still splits on whitespace
+	_, err = parseRead(`key="champ 1" family=main`)
+	req.ErrorIs(err, errInvalidFormat)
+}
+
+func TestIdempotencyKey_v2(t *testing.T) {
+	req := require.New(t)
+	key, request := idempotencyKey(`v2:idempotencyKey="a b" key=champ:1 value="x  y"`)
+	req.Equal("a b", key)
+	req.Equal(`v2:key=champ:1 value="x  y"`, request)
+
+	// a query that fails to parse has no key
+	key, request = idempotencyKey(`v2:idempotencyKey=abc value="x`)
+	req.Empty(key)
+	req.Equal(`v2:idempotencyKey=abc value="x`, request)
+}
+
+// quoteV2 quotes a value for version 2 of the grammar.
+func quoteV2(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func FuzzTokenizeV2(f *testing.F) {
+	f.Add("qualifier", "name")
+	f.Add("value", "Jinx = the loose  cannon")
+	f.Add("value", `say "hi" \o/`)
+	f.Fuzz(func(t *testing.T, name, value string) {
+		req := require.New(t)
+		// any query either splits or fails without panicking
+		_, _ = tokenizeV2(name + "=" + value)
+
+		tokens, err := tokenizeV2(quoteV2(name) + "=" + quoteV2(value))
+		req.NoError(err)
+		req.Equal([]queryToken{{
+			raw:   quoteV2(name) + "=" + quoteV2(value),
+			name:  name,
+			value: value,
+			ok:    true,
+		}}, tokens)
+	})
+}
@@ -0,0 +1,118 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"strings"
+	"sync"
+	"time"
+)
+
+// idempotencyTTL is how long the result of a request with an idempotency key is kept for retries.
+const idempotencyTTL = 10 * time.Minute
+
+var idempotentReplays = metrics.NewCounterVec("litetable_idempotent_replays_total",
+	"Requests answered from the idempotency cache instead of being applied again.", "operation")
+
+// idempotencyCache remembers the results of requests made with an idempotency key, so a client
+// that retries after a timeout gets the result of its first attempt instead of applying the
+// request twice. Like leases, the cache is kept in memory only and a restart forgets it.
+type idempotencyCache struct {
+	mutex   sync.Mutex
+	entries map[string]*idempotentCall
+	ttl     time.Duration
+	// prune is the number of entries at which expired entries are removed
+	prune int
+	now   func() time.Time
+}
+
+// idempotentCall is a request made with an idempotency key. done is closed once it has run.
+type idempotentCall struct {
+	request   string
+	done      chan struct{}
+	result    any
+	err       error
+	expiresAt int64
+}
+
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{
+		entries: make(map[string]*idempotentCall),
+		ttl:     idempotencyTTL,
+		prune:   1024,
+		now:     time.Now,
+	}
+}
+
+// do runs fn once for a key and request, and returns its result to every retry until the result
+// expires. A retry that arrives while fn is running waits for it. Failed requests are not kept,
+// so they can be retried, and reusing a key for a different request is a conflict.
+func (c *idempotencyCache) do(key, request string, fn func() (any, error)) (any, bool, error) {
+	c.mutex.Lock()
+	now := c.now().UnixNano()
+	if call, ok := c.entries[key]; ok && (call.expiresAt == 0 || call.expiresAt > now) {
+		c.mutex.Unlock()
+		if call.request != request {
+			return nil, false, litetable.NewError(litetable.ErrorCodeConflict,
+				"idempotency key %s was used for a different request", key)
+		}
+		<-call.done
+		return call.result, true, call.err
+	}
+
+	call := &idempotentCall{request: request, done: make(chan struct{})}
+	c.entries[key] = call
+	if len(c.entries) >= c.prune {
+		c.pruneExpired(now)
+	}
+	c.mutex.Unlock()
+
+	call.result, call.err = fn()
+
+	c.mutex.Lock()
+	if call.err != nil {
+		delete(c.entries, key)
+	} else {
+		call.expiresAt = c.now().Add(c.ttl).UnixNano()
+	}
+	c.mutex.Unlock()
+	close(call.done)
+	return call.result, false, call.err
+}
+
+// pruneExpired removes expired entries and sets the next prune to twice the entries left, so
+// pruning costs O(1) per request on average. Requests still running are kept.
+func (c *idempotencyCache) pruneExpired(now int64) {
+	for key, call := range c.entries {
+		if call.expiresAt != 0 && call.expiresAt <= now {
+			delete(c.entries, key)
+		}
+	}
+	c.prune = max(1024, 2*len(c.entries))
+}
+
+// idempotencyKey returns the idempotency key of a query and the query without it, which
+// identifies the request the key was used for.
+func idempotencyKey(query string) (string, string) {
+	var key string
+	// the key may follow the version prefix, which is kept on the request
+	prefix, rest := cutQueryVersion(query)
+	version, _, err := queryVersion(query)
+	if err != nil {
+		return "", query
+	}
+	tokens, err := tokenizeQuery(version, rest)
+	if err != nil {
+		// the query fails to parse, so there is no result to keep
+		return "", query
+	}
+	request := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token.ok && token.name == "idempotencyKey" {
+			key = token.value
+			continue
+		}
+		request = append(request, token.raw)
+	}
+	return key, prefix + strings.Join(request, " ")
+}
@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyCache_do(t *testing.T) {
+	req := require.New(t)
+	now := time.Unix(0, 0)
+	c := newIdempotencyCache()
+	c.now = func() time.Time { return now }
+
+	var calls int
+	fn := func() (any, error) {
+		calls++
+		return calls, nil
+	}
+
+	result, replayed, err := c.do("k1", "write a", fn)
+	req.NoError(err)
+	req.False(replayed)
+	req.Equal(1, result)
+
+	// a retry gets the first result back
+	result, replayed, err = c.do("k1", "write a", fn)
+	req.NoError(err)
+	req.True(replayed)
+	req.Equal(1, result)
+	req.Equal(1, calls)
+
+	// the key cannot be reused for another request
+	_, _, err = c.do("k1", "write b", fn)
+	req.ErrorIs(err, litetable.ErrConflict)
+
+	// once expired, the key runs the request again
+	now = now.Add(idempotencyTTL)
+	result, replayed, err = c.do("k1", "write b", fn)
+	req.NoError(err)
+	req.False(replayed)
+	req.Equal(2, result)
+
+	// failures are not kept, so the request can be retried
+	_, _, err = c.do("k2", "write c", func() (any, error) { return nil, errors.New("boom") })
+	req.Error(err)
+	result, replayed, err = c.do("k2", "write c", fn)
+	req.NoError(err)
+	req.False(replayed)
+	req.Equal(3, result)
+}
+
+func TestIdempotencyCache_do_ConcurrentRetries(t *testing.T) {
+	c := newIdempotencyCache()
+	started, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		_, _, _ = c.do("k", "write", func() (any, error) {
+			close(started)
+			<-release
+			return "first", nil
+		})
+	}()
+	<-started
+
+	var wg sync.WaitGroup
+	results := make([]any, 4)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _, _ = c.do("k", "write", func() (any, error) {
+				return "retry", nil
+			})
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	for _, result := range results {
+		require.Equal(t, "first", result)
+	}
+}
+
+func TestIdempotencyCache_pruneExpired(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newIdempotencyCache()
+	c.now = func() time.Time { return now }
+	c.prune = 2
+
+	_, _, _ = c.do("k1", "write", func() (any, error) { return nil, nil })
+	now = now.Add(idempotencyTTL)
+	_, _, _ = c.do("k2", "write", func() (any, error) { return nil, nil })
+
+	require.Len(t, c.entries, 1)
+	require.Contains(t, c.entries, "k2")
+}
+
+func TestManager_Write_IdempotencyKey(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	w := NewMockwriteAhead(ctrl)
+	s := NewMockshardManager(ctrl)
+	expectExpiresAt(s)
+
+	// the write reaches the WAL and the shards once, without its key
+	w.EXPECT().Apply(gomock.Any()).Return(nil)
+	s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(), gomock.Any(),
+		int64(0)).Return(nil)
+
+	m := &Manager{writeAhead: w, shardStorage: s, idempotency: newIdempotencyCache()}
+	query := "key=champ:1 family=wrestlers qualifier=name value=John idempotencyKey=abc"
+	first, err := m.Write(query)
+	req.NoError(err)
+
+	retry, err := m.Write(query)
+	req.NoError(err)
+	req.Equal(first, retry)
+
+	_, err = m.Write("key=champ:1 family=wrestlers qualifier=name value=Randy idempotencyKey=abc")
+	req.ErrorIs(err, litetable.ErrConflict)
+}
+
+func TestManager_Commit_IdempotencyKey(t *testing.T) {
+	m := &Manager{}
+	_, err := m.Commit("", 1, nil, []litetable.MutationQuery{{
+		Operation: litetable.OperationWrite,
+		Query:     "key=champ:1 family=wrestlers qualifier=name value=John idempotencyKey=abc",
+	}})
+	require.ErrorIs(t, err, litetable.ErrInvalidArgument)
+}
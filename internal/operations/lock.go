@@ -0,0 +1,183 @@
+package operations
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultLockTTL is how long a lease lasts when the request does not say.
+	defaultLockTTL = 30 * time.Second
+	// maxLockTTL is the longest lease, so a crashed client cannot hold a row for long.
+	maxLockTTL = time.Hour
+)
+
+// rowLocks holds the advisory leases on rows. Leases are kept in memory only: a restart
+// releases every lease, and the fencing tokens continue from the clock, so they keep increasing
+// across restarts.
+type rowLocks struct {
+	mutex     sync.Mutex
+	leases    map[lockKey]lease
+	lastToken uint64
+	// prune is the number of leases at which expired leases are removed
+	prune int
+	now   func() time.Time
+}
+
+type lockKey struct {
+	table  string
+	rowKey string
+}
+
+type lease struct {
+	owner string
+	litetable.RowLease
+}
+
+func newRowLocks() *rowLocks {
+	return &rowLocks{
+		leases:    make(map[lockKey]lease),
+		lastToken: uint64(time.Now().UnixNano()),
+		prune:     1024,
+		now:       time.Now,
+	}
+}
+
+// lock grants owner a lease on a row for ttl, or renews the lease owner already holds. A
+// renewed lease keeps its fencing token.
+func (l *rowLocks) lock(table, rowKey, owner string, ttl time.Duration) (litetable.RowLease,
+	error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now().UnixNano()
+	key := lockKey{table: table, rowKey: rowKey}
+	current, held := l.leases[key]
+	if held && current.ExpiresAt > now && current.owner != owner {
+		return litetable.RowLease{}, litetable.NewError(litetable.ErrorCodeConflict,
+			"row %s is locked until %s", rowKey,
+			time.Unix(0, current.ExpiresAt).UTC().Format(time.RFC3339Nano))
+	}
+
+	if !held || current.ExpiresAt <= now {
+		// tokens come from the clock when it is ahead, so they survive a restart
+		l.lastToken = max(l.lastToken+1, uint64(now))
+		current = lease{owner: owner, RowLease: litetable.RowLease{Token: l.lastToken}}
+	}
+	current.ExpiresAt = now + int64(ttl)
+	l.leases[key] = current
+
+	if len(l.leases) >= l.prune {
+		l.pruneExpired(now)
+	}
+	return current.RowLease, nil
+}
+
+// unlock releases the lease on a row with the given fencing token.
+func (l *rowLocks) unlock(table, rowKey string, token uint64) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	key := lockKey{table: table, rowKey: rowKey}
+	if err := l.checkLocked(key, token); err != nil {
+		return err
+	}
+	delete(l.leases, key)
+	return nil
+}
+
+// check returns a conflict unless the lease with the given fencing token still holds the row.
+func (l *rowLocks) check(table, rowKey string, token uint64) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	err := l.checkLocked(lockKey{table: table, rowKey: rowKey}, token)
+	if errors.Is(err, litetable.ErrNotFound) {
+		return litetable.NewError(litetable.ErrorCodeConflict,
+			"lease on row %s has expired or was released", rowKey)
+	}
+	return err
+}
+
+func (l *rowLocks) checkLocked(key lockKey, token uint64) error {
+	current, held := l.leases[key]
+	if !held || current.ExpiresAt <= l.now().UnixNano() {
+		return litetable.NewError(litetable.ErrorCodeNotFound, "row %s is not locked",
+			key.rowKey)
+	}
+	if current.Token != token {
+		return litetable.NewError(litetable.ErrorCodeConflict,
+			"row %s is locked with a different fencing token", key.rowKey)
+	}
+	return nil
+}
+
+// pruneExpired removes expired leases and sets the next prune to twice the leases left, so
+// pruning costs O(1) per lock on average.
+func (l *rowLocks) pruneExpired(now int64) {
+	for key, current := range l.leases {
+		if current.ExpiresAt <= now {
+			delete(l.leases, key)
+		}
+	}
+	l.prune = max(1024, 2*len(l.leases))
+}
+
+// LockRow takes or renews an advisory lease on a row. Leases do not block reads or writes;
+// clients that pass the fencing token with a write have it rejected once the lease is lost.
+func (m *Manager) LockRow(table, rowKey, owner string, ttl time.Duration) (litetable.RowLease,
+	error) {
+	if rowKey == "" {
+		return litetable.RowLease{}, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"missing key")
+	}
+	if owner == "" {
+		return litetable.RowLease{}, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"missing owner")
+	}
+	if ttl == 0 {
+		ttl = defaultLockTTL
+	}
+	if ttl < 0 || ttl > maxLockTTL {
+		return litetable.RowLease{}, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"lock ttl must be positive and at most %s, got %s", maxLockTTL, ttl)
+	}
+	if _, err := m.storage(table, "lock"); err != nil {
+		return litetable.RowLease{}, err
+	}
+	return m.locks.lock(tableName(table), rowKey, owner, ttl)
+}
+
+// UnlockRow releases the lease on a row with the given fencing token.
+func (m *Manager) UnlockRow(table, rowKey string, token uint64) error {
+	return m.locks.unlock(tableName(table), rowKey, token)
+}
+
+// checkFence rejects a change made with a fencing token unless its lease still holds the row.
+// A zero token is a change made without a lease.
+func (m *Manager) checkFence(table, rowKey string, token uint64) error {
+	if token == 0 {
+		return nil
+	}
+	return m.locks.check(tableName(table), rowKey, token)
+}
+
+// parseFence parses the fencing token of a write or delete.
+func parseFence(value string) (uint64, error) {
+	fence, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"invalid fence value: %s", value)
+	}
+	return fence, nil
+}
+
+// tableName returns the name of a table, naming the default table when it is empty.
+func tableName(table string) string {
+	if table == "" {
+		return litetable.DefaultTable
+	}
+	return table
+}
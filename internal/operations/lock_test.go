@@ -0,0 +1,136 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestManager_LockRow(t *testing.T) {
+	req := require.New(t)
+	now := time.Now()
+	m := &Manager{locks: newRowLocks()}
+	m.locks.now = func() time.Time { return now }
+
+	lease, err := m.LockRow("", "champ:1", "worker-1", 0)
+	req.NoError(err)
+	req.Equal(now.Add(defaultLockTTL).UnixNano(), lease.ExpiresAt)
+
+	// the owner renews the lease and keeps its token
+	renewed, err := m.LockRow(litetable.DefaultTable, "champ:1", "worker-1", time.Minute)
+	req.NoError(err)
+	req.Equal(lease.Token, renewed.Token)
+	req.Equal(now.Add(time.Minute).UnixNano(), renewed.ExpiresAt)
+
+	_, err = m.LockRow("", "champ:1", "worker-2", 0)
+	req.ErrorIs(err, litetable.ErrConflict)
+
+	// other rows are locked separately
+	other, err := m.LockRow("", "champ:2", "worker-2", 0)
+	req.NoError(err)
+	req.Greater(other.Token, lease.Token)
+
+	// an expired lease goes to the next owner with a larger token
+	now = now.Add(time.Minute)
+	next, err := m.LockRow("", "champ:1", "worker-2", 0)
+	req.NoError(err)
+	req.Greater(next.Token, other.Token)
+
+	req.ErrorIs(m.UnlockRow("", "champ:1", lease.Token), litetable.ErrConflict)
+	req.NoError(m.UnlockRow("", "champ:1", next.Token))
+	req.ErrorIs(m.UnlockRow("", "champ:1", next.Token), litetable.ErrNotFound)
+}
+
+func TestManager_LockRow_errors(t *testing.T) {
+	tests := map[string]struct {
+		table, rowKey, owner string
+		ttl                  time.Duration
+		expectErr            error
+	}{
+		"missing key": {
+			owner:     "worker-1",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"missing owner": {
+			rowKey:    "champ:1",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"negative ttl": {
+			rowKey:    "champ:1",
+			owner:     "worker-1",
+			ttl:       -time.Second,
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"ttl too long": {
+			rowKey:    "champ:1",
+			owner:     "worker-1",
+			ttl:       2 * maxLockTTL,
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"unknown table": {
+			table:     "wwe",
+			rowKey:    "champ:1",
+			owner:     "worker-1",
+			expectErr: litetable.ErrNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := &Manager{locks: newRowLocks()}
+			_, err := m.LockRow(tc.table, tc.rowKey, tc.owner, tc.ttl)
+			require.ErrorIs(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestManager_fence(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	w := NewMockwriteAhead(ctrl)
+	w.EXPECT().Apply(gomock.Any()).Return(nil).AnyTimes()
+	s := NewMockshardManager(ctrl)
+	expectExpiresAt(s)
+	m := &Manager{writeAhead: w, shardStorage: s, locks: newRowLocks()}
+
+	lease, err := m.LockRow("", "champ:1", "worker-1", 0)
+	req.NoError(err)
+
+	s.EXPECT().Apply("champ:1", "main", []string{"name"}, gomock.Any(), gomock.Any(),
+		int64(0)).Return(nil)
+	_, err = m.Write("key=champ:1 family=main qualifier=name value=Ahri fence=" +
+		strconv.FormatUint(lease.Token, 10))
+	req.NoError(err)
+
+	_, err = m.Write("key=champ:1 family=main qualifier=name value=Zed fence=" +
+		strconv.FormatUint(lease.Token+1, 10))
+	req.ErrorIs(err, litetable.ErrConflict)
+
+	_, err = m.Write("key=champ:1 family=main qualifier=name value=Zed fence=abc")
+	req.ErrorIs(err, litetable.ErrInvalidArgument)
+
+	// the delete is rejected once the lease is released
+	req.NoError(m.UnlockRow("", "champ:1", lease.Token))
+	err = m.Delete("key=champ:1 family=main fence=" + strconv.FormatUint(lease.Token, 10))
+	req.ErrorIs(err, litetable.ErrConflict)
+}
+
+func TestRowLocks_pruneExpired(t *testing.T) {
+	req := require.New(t)
+	now := time.Now()
+	l := newRowLocks()
+	l.now = func() time.Time { return now }
+	l.prune = 2
+
+	_, err := l.lock(litetable.DefaultTable, "champ:1", "worker-1", time.Second)
+	req.NoError(err)
+	now = now.Add(time.Minute)
+	_, err = l.lock(litetable.DefaultTable, "champ:2", "worker-1", time.Second)
+	req.NoError(err)
+
+	req.Len(l.leases, 1)
+	req.Equal(1024, l.prune)
+}
@@ -2,40 +2,98 @@ package operations
 
 import (
 	"errors"
+	"github.com/litetable/litetable-db/internal/engine"
 	"github.com/litetable/litetable-db/internal/litetable"
 	"github.com/litetable/litetable-db/internal/shard_storage/wal"
+	"sync/atomic"
 )
 
 //go:generate mockgen -destination=manager_mock.go -package=operations -source=manager.go
 
 type writeAhead interface {
 	Apply(e *wal.Entry) error
+	ApplyBatch(entries []*wal.Entry) error
+	Sync() error
 }
 
 type shardManager interface {
 	GetRowByFamily(key, family string) (*litetable.Data, bool)
-	FilterRowsByPrefix(prefix string) (*litetable.Data, bool)
-	FilterRowsByRegex(regex string) (*litetable.Data, bool)
+	FilterRowsByPrefix(prefix string, page litetable.ScanPage) (*litetable.Data,
+		litetable.ScanStats, bool)
+	FilterRowsByRegex(regex string, page litetable.ScanPage) (*litetable.Data,
+		litetable.ScanStats, bool, error)
+	ScanCached(prefix, family string, latest int,
+		scan func() (map[string]*litetable.Row, bool, error)) (map[string]*litetable.Row, error)
+	CachesScans() bool
+	ReadView() (litetable.RowReader, bool)
 
 	IsFamilyAllowed(family string) bool
 	UpdateFamilies(families []string) error
+	GetFamilies() []string
+	CloneFamily(source, target string) (int, int, error)
+	ExpiresAt(family string, timestamp, expiresAt int64) int64
 
 	Apply(rowKey, family string, qualifiers []string, values [][]byte, timestamp int64,
 		expiresAt int64) error
 	Delete(key, family string, qualifiers []string, timestamp int64,
 		expiresAt int64) error
+	Flush() error
+	ListBackups() ([]*litetable.BackupManifest, error)
+	Usage() litetable.TableUsage
+	Sequence() uint64
+	Commit(readAt uint64, reads []string, mutations []litetable.Mutation,
+		accepted func() error) (uint64, error)
+	ApplyBatch(mutations []litetable.Mutation) []error
+	Tombstones(prefix string, limit int) ([]litetable.Tombstone, bool)
+}
+
+// diskMonitor refuses writes while the disk is low on space.
+type diskMonitor interface {
+	CheckWrite() error
+}
+
+// tableCatalog holds the tables other than the default table.
+type tableCatalog interface {
+	Table(name string) (engine.StorageEngine, error)
+	ListTables() []string
+	CreateTable(name string, families []string) error
+	DropTable(name string) error
+	Flush() error
 }
 
 type Manager struct {
 	writeAhead   writeAhead
 	defaultTTL   int64
 	shardStorage shardManager
-	isHealthy    bool
+	tables       tableCatalog
+	disk         diskMonitor
+	backlogs     []Backlog
+	locks        *rowLocks
+	idempotency  *idempotencyCache
+	// coalescer groups concurrent writes when coalescing is on
+	coalescer *coalescer
+	// names are the rules row keys and family names are held to; nil uses the defaults
+	names atomic.Pointer[litetable.NameRules]
+	// clock gives writes and deletes their timestamps
+	clock     hybridClock
+	isHealthy bool
 }
 
 type Config struct {
 	WAL          writeAhead
 	ShardStorage shardManager
+	// Tables is the table catalog. Without one, only the default table exists.
+	Tables tableCatalog
+	// Coalesce groups concurrent writes into one WAL record and shard lock. Off when nil.
+	Coalesce *CoalesceConfig
+	// Names are the rules row keys and family names are held to.
+	Names litetable.NameRules
+	// Disk refuses writes and transactions while the disk is low on space, before they reach
+	// the WAL. Deletes are still accepted, so space can be freed. Off when nil.
+	Disk diskMonitor
+	// Backlogs refuse writes, deletes and transactions while one of them is over its limit,
+	// before they reach the WAL. Off when empty.
+	Backlogs []Backlog
 }
 
 func (c *Config) validate() error {
@@ -57,10 +115,18 @@ func New(cfg *Config) (*Manager, error) {
 		return nil, err
 	}
 
-	return &Manager{
+	m := &Manager{
 		writeAhead:   cfg.WAL,
 		defaultTTL:   3600, // configure default for 1 hour
 		shardStorage: cfg.ShardStorage,
+		tables:       cfg.Tables,
+		disk:         cfg.Disk,
+		backlogs:     cfg.Backlogs,
+		locks:        newRowLocks(),
+		idempotency:  newIdempotencyCache(),
+		coalescer:    newCoalescer(cfg.Coalesce),
 		isHealthy:    true,
-	}, nil
+	}
+	m.SetNameRules(cfg.Names)
+	return m, nil
 }
@@ -12,6 +12,7 @@ package operations
 import (
 	reflect "reflect"
 
+	engine "github.com/litetable/litetable-db/internal/engine"
 	litetable "github.com/litetable/litetable-db/internal/litetable"
 	wal "github.com/litetable/litetable-db/internal/shard_storage/wal"
 	gomock "go.uber.org/mock/gomock"
@@ -54,6 +55,34 @@ func (mr *MockwriteAheadMockRecorder) Apply(e any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockwriteAhead)(nil).Apply), e)
 }
 
+// ApplyBatch mocks base method.
+func (m *MockwriteAhead) ApplyBatch(entries []*wal.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBatch", entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyBatch indicates an expected call of ApplyBatch.
+func (mr *MockwriteAheadMockRecorder) ApplyBatch(entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBatch", reflect.TypeOf((*MockwriteAhead)(nil).ApplyBatch), entries)
+}
+
+// Sync mocks base method.
+func (m *MockwriteAhead) Sync() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockwriteAheadMockRecorder) Sync() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockwriteAhead)(nil).Sync))
+}
+
 // MockshardManager is a mock of shardManager interface.
 type MockshardManager struct {
 	ctrl     *gomock.Controller
@@ -91,6 +120,65 @@ func (mr *MockshardManagerMockRecorder) Apply(rowKey, family, qualifiers, values
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockshardManager)(nil).Apply), rowKey, family, qualifiers, values, timestamp, expiresAt)
 }
 
+// ApplyBatch mocks base method.
+func (m *MockshardManager) ApplyBatch(mutations []litetable.Mutation) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBatch", mutations)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// ApplyBatch indicates an expected call of ApplyBatch.
+func (mr *MockshardManagerMockRecorder) ApplyBatch(mutations any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBatch", reflect.TypeOf((*MockshardManager)(nil).ApplyBatch), mutations)
+}
+
+// CachesScans mocks base method.
+func (m *MockshardManager) CachesScans() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CachesScans")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CachesScans indicates an expected call of CachesScans.
+func (mr *MockshardManagerMockRecorder) CachesScans() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CachesScans", reflect.TypeOf((*MockshardManager)(nil).CachesScans))
+}
+
+// CloneFamily mocks base method.
+func (m *MockshardManager) CloneFamily(source, target string) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloneFamily", source, target)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CloneFamily indicates an expected call of CloneFamily.
+func (mr *MockshardManagerMockRecorder) CloneFamily(source, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneFamily", reflect.TypeOf((*MockshardManager)(nil).CloneFamily), source, target)
+}
+
+// Commit mocks base method.
+func (m *MockshardManager) Commit(readAt uint64, reads []string, mutations []litetable.Mutation, accepted func() error) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", readAt, reads, mutations, accepted)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockshardManagerMockRecorder) Commit(readAt, reads, mutations, accepted any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockshardManager)(nil).Commit), readAt, reads, mutations, accepted)
+}
+
 // Delete mocks base method.
 func (m *MockshardManager) Delete(key, family string, qualifiers []string, timestamp, expiresAt int64) error {
 	m.ctrl.T.Helper()
@@ -105,34 +193,79 @@ func (mr *MockshardManagerMockRecorder) Delete(key, family, qualifiers, timestam
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockshardManager)(nil).Delete), key, family, qualifiers, timestamp, expiresAt)
 }
 
+// ExpiresAt mocks base method.
+func (m *MockshardManager) ExpiresAt(family string, timestamp, expiresAt int64) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpiresAt", family, timestamp, expiresAt)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// ExpiresAt indicates an expected call of ExpiresAt.
+func (mr *MockshardManagerMockRecorder) ExpiresAt(family, timestamp, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpiresAt", reflect.TypeOf((*MockshardManager)(nil).ExpiresAt), family, timestamp, expiresAt)
+}
+
 // FilterRowsByPrefix mocks base method.
-func (m *MockshardManager) FilterRowsByPrefix(prefix string) (*litetable.Data, bool) {
+func (m *MockshardManager) FilterRowsByPrefix(prefix string, page litetable.ScanPage) (*litetable.Data, litetable.ScanStats, bool) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FilterRowsByPrefix", prefix)
+	ret := m.ctrl.Call(m, "FilterRowsByPrefix", prefix, page)
 	ret0, _ := ret[0].(*litetable.Data)
-	ret1, _ := ret[1].(bool)
-	return ret0, ret1
+	ret1, _ := ret[1].(litetable.ScanStats)
+	ret2, _ := ret[2].(bool)
+	return ret0, ret1, ret2
 }
 
 // FilterRowsByPrefix indicates an expected call of FilterRowsByPrefix.
-func (mr *MockshardManagerMockRecorder) FilterRowsByPrefix(prefix any) *gomock.Call {
+func (mr *MockshardManagerMockRecorder) FilterRowsByPrefix(prefix, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterRowsByPrefix", reflect.TypeOf((*MockshardManager)(nil).FilterRowsByPrefix), prefix)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterRowsByPrefix", reflect.TypeOf((*MockshardManager)(nil).FilterRowsByPrefix), prefix, page)
 }
 
 // FilterRowsByRegex mocks base method.
-func (m *MockshardManager) FilterRowsByRegex(regex string) (*litetable.Data, bool) {
+func (m *MockshardManager) FilterRowsByRegex(regex string, page litetable.ScanPage) (*litetable.Data, litetable.ScanStats, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FilterRowsByRegex", regex)
+	ret := m.ctrl.Call(m, "FilterRowsByRegex", regex, page)
 	ret0, _ := ret[0].(*litetable.Data)
-	ret1, _ := ret[1].(bool)
-	return ret0, ret1
+	ret1, _ := ret[1].(litetable.ScanStats)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
 }
 
 // FilterRowsByRegex indicates an expected call of FilterRowsByRegex.
-func (mr *MockshardManagerMockRecorder) FilterRowsByRegex(regex any) *gomock.Call {
+func (mr *MockshardManagerMockRecorder) FilterRowsByRegex(regex, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterRowsByRegex", reflect.TypeOf((*MockshardManager)(nil).FilterRowsByRegex), regex, page)
+}
+
+// Flush mocks base method.
+func (m *MockshardManager) Flush() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockshardManagerMockRecorder) Flush() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockshardManager)(nil).Flush))
+}
+
+// GetFamilies mocks base method.
+func (m *MockshardManager) GetFamilies() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFamilies")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetFamilies indicates an expected call of GetFamilies.
+func (mr *MockshardManagerMockRecorder) GetFamilies() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterRowsByRegex", reflect.TypeOf((*MockshardManager)(nil).FilterRowsByRegex), regex)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFamilies", reflect.TypeOf((*MockshardManager)(nil).GetFamilies))
 }
 
 // GetRowByFamily mocks base method.
@@ -164,6 +297,80 @@ func (mr *MockshardManagerMockRecorder) IsFamilyAllowed(family any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFamilyAllowed", reflect.TypeOf((*MockshardManager)(nil).IsFamilyAllowed), family)
 }
 
+// ListBackups mocks base method.
+func (m *MockshardManager) ListBackups() ([]*litetable.BackupManifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBackups")
+	ret0, _ := ret[0].([]*litetable.BackupManifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBackups indicates an expected call of ListBackups.
+func (mr *MockshardManagerMockRecorder) ListBackups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBackups", reflect.TypeOf((*MockshardManager)(nil).ListBackups))
+}
+
+// ReadView mocks base method.
+func (m *MockshardManager) ReadView() (litetable.RowReader, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadView")
+	ret0, _ := ret[0].(litetable.RowReader)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// ReadView indicates an expected call of ReadView.
+func (mr *MockshardManagerMockRecorder) ReadView() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadView", reflect.TypeOf((*MockshardManager)(nil).ReadView))
+}
+
+// ScanCached mocks base method.
+func (m *MockshardManager) ScanCached(prefix, family string, latest int, scan func() (map[string]*litetable.Row, bool, error)) (map[string]*litetable.Row, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanCached", prefix, family, latest, scan)
+	ret0, _ := ret[0].(map[string]*litetable.Row)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanCached indicates an expected call of ScanCached.
+func (mr *MockshardManagerMockRecorder) ScanCached(prefix, family, latest, scan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanCached", reflect.TypeOf((*MockshardManager)(nil).ScanCached), prefix, family, latest, scan)
+}
+
+// Sequence mocks base method.
+func (m *MockshardManager) Sequence() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sequence")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// Sequence indicates an expected call of Sequence.
+func (mr *MockshardManagerMockRecorder) Sequence() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sequence", reflect.TypeOf((*MockshardManager)(nil).Sequence))
+}
+
+// Tombstones mocks base method.
+func (m *MockshardManager) Tombstones(prefix string, limit int) ([]litetable.Tombstone, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tombstones", prefix, limit)
+	ret0, _ := ret[0].([]litetable.Tombstone)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Tombstones indicates an expected call of Tombstones.
+func (mr *MockshardManagerMockRecorder) Tombstones(prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tombstones", reflect.TypeOf((*MockshardManager)(nil).Tombstones), prefix, limit)
+}
+
 // UpdateFamilies mocks base method.
 func (m *MockshardManager) UpdateFamilies(families []string) error {
 	m.ctrl.T.Helper()
@@ -177,3 +384,148 @@ func (mr *MockshardManagerMockRecorder) UpdateFamilies(families any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFamilies", reflect.TypeOf((*MockshardManager)(nil).UpdateFamilies), families)
 }
+
+// Usage mocks base method.
+func (m *MockshardManager) Usage() litetable.TableUsage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage")
+	ret0, _ := ret[0].(litetable.TableUsage)
+	return ret0
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockshardManagerMockRecorder) Usage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockshardManager)(nil).Usage))
+}
+
+// MockdiskMonitor is a mock of diskMonitor interface.
+type MockdiskMonitor struct {
+	ctrl     *gomock.Controller
+	recorder *MockdiskMonitorMockRecorder
+}
+
+// MockdiskMonitorMockRecorder is the mock recorder for MockdiskMonitor.
+type MockdiskMonitorMockRecorder struct {
+	mock *MockdiskMonitor
+}
+
+// NewMockdiskMonitor creates a new mock instance.
+func NewMockdiskMonitor(ctrl *gomock.Controller) *MockdiskMonitor {
+	mock := &MockdiskMonitor{ctrl: ctrl}
+	mock.recorder = &MockdiskMonitorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockdiskMonitor) EXPECT() *MockdiskMonitorMockRecorder {
+	return m.recorder
+}
+
+// CheckWrite mocks base method.
+func (m *MockdiskMonitor) CheckWrite() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckWrite")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckWrite indicates an expected call of CheckWrite.
+func (mr *MockdiskMonitorMockRecorder) CheckWrite() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckWrite", reflect.TypeOf((*MockdiskMonitor)(nil).CheckWrite))
+}
+
+// MocktableCatalog is a mock of tableCatalog interface.
+type MocktableCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MocktableCatalogMockRecorder
+}
+
+// MocktableCatalogMockRecorder is the mock recorder for MocktableCatalog.
+type MocktableCatalogMockRecorder struct {
+	mock *MocktableCatalog
+}
+
+// NewMocktableCatalog creates a new mock instance.
+func NewMocktableCatalog(ctrl *gomock.Controller) *MocktableCatalog {
+	mock := &MocktableCatalog{ctrl: ctrl}
+	mock.recorder = &MocktableCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktableCatalog) EXPECT() *MocktableCatalogMockRecorder {
+	return m.recorder
+}
+
+// CreateTable mocks base method.
+func (m *MocktableCatalog) CreateTable(name string, families []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTable", name, families)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTable indicates an expected call of CreateTable.
+func (mr *MocktableCatalogMockRecorder) CreateTable(name, families any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTable", reflect.TypeOf((*MocktableCatalog)(nil).CreateTable), name, families)
+}
+
+// DropTable mocks base method.
+func (m *MocktableCatalog) DropTable(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropTable", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropTable indicates an expected call of DropTable.
+func (mr *MocktableCatalogMockRecorder) DropTable(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropTable", reflect.TypeOf((*MocktableCatalog)(nil).DropTable), name)
+}
+
+// Flush mocks base method.
+func (m *MocktableCatalog) Flush() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MocktableCatalogMockRecorder) Flush() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MocktableCatalog)(nil).Flush))
+}
+
+// ListTables mocks base method.
+func (m *MocktableCatalog) ListTables() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTables")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ListTables indicates an expected call of ListTables.
+func (mr *MocktableCatalogMockRecorder) ListTables() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTables", reflect.TypeOf((*MocktableCatalog)(nil).ListTables))
+}
+
+// Table mocks base method.
+func (m *MocktableCatalog) Table(name string) (engine.StorageEngine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Table", name)
+	ret0, _ := ret[0].(engine.StorageEngine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Table indicates an expected call of Table.
+func (mr *MocktableCatalogMockRecorder) Table(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Table", reflect.TypeOf((*MocktableCatalog)(nil).Table), name)
+}
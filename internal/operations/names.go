@@ -0,0 +1,35 @@
+package operations
+
+import "github.com/litetable/litetable-db/internal/litetable"
+
+// SetNameRules replaces the rules row keys and family names are held to, without restarting.
+func (m *Manager) SetNameRules(rules litetable.NameRules) {
+	m.names.Store(&rules)
+}
+
+func (m *Manager) nameRules() litetable.NameRules {
+	if rules := m.names.Load(); rules != nil {
+		return *rules
+	}
+	return litetable.NameRules{}
+}
+
+// checkNames checks the row key and the families written to, so that a write cannot store a
+// key or create a family that would break the text query protocol.
+func (m *Manager) checkNames(rowKey string, families ...string) error {
+	if err := m.nameRules().CheckRowKey(rowKey); err != nil {
+		return err
+	}
+	return m.checkFamilies(families)
+}
+
+// checkFamilies checks family names against the family name pattern.
+func (m *Manager) checkFamilies(families []string) error {
+	rules := m.nameRules()
+	for _, family := range families {
+		if err := rules.CheckFamily(family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
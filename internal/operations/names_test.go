@@ -0,0 +1,90 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_nameRules(t *testing.T) {
+	lowercase, err := litetable.NewNameRules(8, "^[a-z]+$")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		rules     *litetable.NameRules
+		run       func(m *Manager) error
+		mockSetup func(w *MockwriteAhead, s *MockshardManager)
+	}{
+		"write with a control character in the key": {
+			run: func(m *Manager) error {
+				_, err := m.Write("key=champ%0A1 family=wrestlers qualifier=name value=John")
+				return err
+			},
+			mockSetup: func(w *MockwriteAhead, _ *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+		},
+		"write to a family the pattern rejects": {
+			rules: &lowercase,
+			run: func(m *Manager) error {
+				_, err := m.Write("key=champ:1 family=Wrestlers qualifier=name value=John")
+				return err
+			},
+			mockSetup: func(w *MockwriteAhead, _ *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+		},
+		"write with a key over the configured length": {
+			rules: &lowercase,
+			run: func(m *Manager) error {
+				_, err := m.Write("key=champ:123 family=wrestlers qualifier=name value=John")
+				return err
+			},
+			mockSetup: func(w *MockwriteAhead, _ *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+		},
+		"delete with invalid UTF-8 in the key": {
+			run: func(m *Manager) error {
+				return m.Delete("key=champ\xff family=wrestlers")
+			},
+			mockSetup: func(w *MockwriteAhead, _ *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+		},
+		"create a family the pattern rejects": {
+			run: func(m *Manager) error {
+				return m.CreateFamilies("", []string{"wrestlers", "bad/family"})
+			},
+			mockSetup: func(_ *MockwriteAhead, _ *MockshardManager) {},
+		},
+		"transaction write to a family the pattern rejects": {
+			rules: &lowercase,
+			run: func(m *Manager) error {
+				_, err := m.Commit("", 0, nil, []litetable.MutationQuery{{
+					Operation: litetable.OperationWrite,
+					Query:     "key=champ family=Titles qualifier=wwe value=16",
+				}})
+				return err
+			},
+			mockSetup: func(_ *MockwriteAhead, _ *MockshardManager) {},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			w := NewMockwriteAhead(ctrl)
+			s := NewMockshardManager(ctrl)
+			tc.mockSetup(w, s)
+
+			m := &Manager{writeAhead: w, shardStorage: s}
+			if tc.rules != nil {
+				m.SetNameRules(*tc.rules)
+			}
+			// nothing is stored, since the storage mock expects no calls
+			require.ErrorIs(t, tc.run(m), litetable.ErrInvalidArgument)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a path into a JSON value: field names separated by dots, with a number indexing
+// an array, as in address.city or items.0.name.
+type jsonPath struct {
+	raw    string
+	fields []string
+}
+
+func parseJSONPath(value string) (jsonPath, error) {
+	fields := strings.Split(value, ".")
+	for _, field := range fields {
+		if field == "" {
+			return jsonPath{}, newError(errInvalidFormat,
+				"jsonPath must be field names separated by dots. received %s", value)
+		}
+	}
+	return jsonPath{raw: value, fields: fields}, nil
+}
+
+// lookup returns the JSON value at the path, or false when value does not have it.
+func (p jsonPath) lookup(value json.RawMessage) (json.RawMessage, bool) {
+	current := bytes.TrimSpace(value)
+	for _, field := range p.fields {
+		if len(current) == 0 {
+			return nil, false
+		}
+		switch current[0] {
+		case '{':
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(current, &object); err != nil {
+				return nil, false
+			}
+			next, ok := object[field]
+			if !ok {
+				return nil, false
+			}
+			current = bytes.TrimSpace(next)
+		case '[':
+			var array []json.RawMessage
+			if err := json.Unmarshal(current, &array); err != nil {
+				return nil, false
+			}
+			i, err := strconv.Atoi(field)
+			if err != nil || i < 0 || i >= len(array) {
+				return nil, false
+			}
+			current = bytes.TrimSpace(array[i])
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// projectJSON returns a JSON object holding the value at each path of a JSON value, keyed by the
+// path. Paths the value does not have are left out.
+func projectJSON(value []byte, paths []jsonPath) ([]byte, error) {
+	if !json.Valid(value) {
+		return nil, errors.New("value is not JSON")
+	}
+	projected := make(map[string]json.RawMessage, len(paths))
+	for _, path := range paths {
+		if field, ok := path.lookup(value); ok {
+			projected[path.raw] = field
+		}
+	}
+	return json.Marshal(projected)
+}
+
+// project replaces the values of the rows read by their projection on the query's JSON paths, so
+// only the requested fields of large documents are returned. Tombstones have no value and are
+// left as they are. A value that is not JSON fails the read rather than being returned whole.
+// The values replaced are the copies the read made, never the stored ones.
+func (r *readQuery) project(rows map[string]*litetable.Row) error {
+	if len(r.jsonPaths) == 0 {
+		return nil
+	}
+	for _, row := range rows {
+		for qualifier, values := range row.Columns[r.family] {
+			for i, v := range values {
+				if v.IsTombstone {
+					continue
+				}
+				projected, err := projectJSON(v.Value, r.jsonPaths)
+				if err != nil {
+					return litetable.NewError(litetable.ErrorCodeInvalidArgument,
+						"cannot apply jsonPath to qualifier %s of row %s: %v", qualifier, row.Key,
+						err)
+				}
+				values[i].Value = projected
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_projectJSON(t *testing.T) {
+	doc := `{"name": "Ahri", "region": {"name": "Ionia", "capital": null},
+		"roles": ["mage", "assassin"], "wins": 1.50}`
+
+	tests := map[string]struct {
+		value   string
+		paths   []string
+		want    string
+		wantErr bool
+	}{
+		"fields": {
+			value: doc,
+			paths: []string{"name", "wins"},
+			want:  `{"name":"Ahri","wins":1.50}`,
+		},
+		"nested fields and array indexes": {
+			value: doc,
+			paths: []string{"region.name", "region.capital", "roles.1"},
+			want:  `{"region.capital":null,"region.name":"Ionia","roles.1":"assassin"}`,
+		},
+		"whole objects": {
+			value: doc,
+			paths: []string{"region"},
+			want:  `{"region":{"name":"Ionia","capital":null}}`,
+		},
+		"missing paths are left out": {
+			value: doc,
+			paths: []string{"title", "roles.2", "roles.first", "name.first"},
+			want:  `{}`,
+		},
+		"not JSON": {
+			value:   "Ahri",
+			paths:   []string{"name"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			var paths []jsonPath
+			for _, p := range tc.paths {
+				path, err := parseJSONPath(p)
+				req.NoError(err)
+				paths = append(paths, path)
+			}
+
+			got, err := projectJSON([]byte(tc.value), paths)
+			if tc.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.JSONEq(tc.want, string(got))
+		})
+	}
+}
+
+func Test_parseJSONPath(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		"field":          {value: "name", want: []string{"name"}},
+		"nested":         {value: "items.0.name", want: []string{"items", "0", "name"}},
+		"empty":          {value: "", wantErr: true},
+		"empty field":    {value: "address..city", wantErr: true},
+		"trailing dot":   {value: "address.", wantErr: true},
+		"leading dot":    {value: ".address", wantErr: true},
+		"single dot":     {value: ".", wantErr: true},
+		"quoted is kept": {value: `"name"`, want: []string{`"name"`}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			path, err := parseJSONPath(tc.value)
+			if tc.wantErr {
+				req.ErrorIs(err, litetable.ErrInvalidArgument)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.want, path.fields)
+		})
+	}
+}
+
+func TestManager_Read_jsonPath(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	storage := NewMockshardManager(ctrl)
+	stored := []byte(`{"name": "Ahri", "region": {"name": "Ionia"}, "lore": "..."}`)
+	storage.EXPECT().IsFamilyAllowed("champs").Return(true)
+	storage.EXPECT().Sequence().Return(uint64(1))
+	storage.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{}).Return(&litetable.Data{
+		"champ:1": {"champs": {
+			"doc": {
+				{Value: stored, Timestamp: 1},
+				{Timestamp: 2, IsTombstone: true, ExpiresAt: 10},
+			},
+		}},
+	}, litetable.ScanStats{Examined: 2}, true)
+
+	// the projection is not cached, since the scan cache holds whole values
+	m := &Manager{shardStorage: storage}
+	rows, _, err := m.Read(
+		"prefix=champ: family=champs jsonPath=region.name jsonPath=name includeTombstones=true")
+	req.NoError(err)
+	values := rows["champ:1"].Columns["champs"]["doc"]
+	req.Len(values, 2)
+	req.True(values[0].IsTombstone)
+	req.Nil(values[0].Value)
+	req.JSONEq(`{"name":"Ahri","region.name":"Ionia"}`, string(values[1].Value))
+
+	// the stored value is left as it was
+	req.JSONEq(`{"name": "Ahri", "region": {"name": "Ionia"}, "lore": "..."}`, string(stored))
+}
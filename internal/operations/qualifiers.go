@@ -0,0 +1,42 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+)
+
+// ListQualifiers runs a read query for a single row and returns the names of its qualifiers in
+// the family, sorted, with the number of versions a read would return for each, instead of the
+// values. It lets clients discover the columns of wide rows without reading their cells.
+func (m *Manager) ListQualifiers(query string) ([]litetable.QualifierVersions, error) {
+	parsed, err := parseRead(query)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.rowKey == "" {
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"qualifiers are listed for a single row key")
+	}
+	if parsed.aggregations.any() {
+		return nil, newError(errInvalidFormat, "aggregate is only supported by aggregate queries")
+	}
+
+	data, found, err := m.scan(parsed, "list qualifiers")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return []litetable.QualifierVersions{}, nil
+	}
+
+	// the version counts are counted as an aggregation, so no value is copied
+	parsed.aggregations.count = true
+	aggregates := parsed.aggregate(*data)
+	qualifiers := make([]litetable.QualifierVersions, 0, len(aggregates))
+	for _, agg := range aggregates {
+		qualifiers = append(qualifiers, litetable.QualifierVersions{
+			Name:     agg.Qualifier,
+			Versions: agg.Count,
+		})
+	}
+	return qualifiers, nil
+}
@@ -0,0 +1,92 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_ListQualifiers(t *testing.T) {
+	row := &litetable.Data{
+		"champ:1": {"wrestlers": {
+			"name": {
+				{Value: []byte("John"), Timestamp: 10, Seq: 1},
+				{Value: []byte("Johnny"), Timestamp: 20, Seq: 2},
+			},
+			"deleted": {
+				{Value: []byte("x"), Timestamp: 10, Seq: 1},
+				{Timestamp: 20, IsTombstone: true, Seq: 3},
+			},
+		}},
+	}
+
+	tests := map[string]struct {
+		query     string
+		mockSetup func(m *MockshardManager)
+		expected  []litetable.QualifierVersions
+		expectErr error
+	}{
+		"every qualifier": {
+			query: "key=champ:1 family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+			expected: []litetable.QualifierVersions{{Name: "name", Versions: 2}},
+		},
+		"with tombstones": {
+			query: "key=champ:1 family=wrestlers includeTombstones=true",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+			expected: []litetable.QualifierVersions{
+				{Name: "deleted", Versions: 2},
+				{Name: "name", Versions: 2},
+			},
+		},
+		"at a sequence number": {
+			query: "key=champ:1 family=wrestlers readAt=2",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+			expected: []litetable.QualifierVersions{
+				{Name: "deleted", Versions: 1},
+				{Name: "name", Versions: 2},
+			},
+		},
+		"missing row": {
+			query: "key=champ:2 family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:2", "wrestlers").Return(nil, false)
+			},
+			expected: []litetable.QualifierVersions{},
+		},
+		"prefix": {
+			query:     "prefix=champ: family=wrestlers",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			storage := NewMockshardManager(gomock.NewController(t))
+			if tc.mockSetup != nil {
+				tc.mockSetup(storage)
+			}
+
+			m := &Manager{shardStorage: storage}
+			got, err := m.ListQualifiers(tc.query)
+			if tc.expectErr != nil {
+				req.ErrorIs(err, tc.expectErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.expected, got)
+		})
+	}
+}
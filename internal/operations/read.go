@@ -1,76 +1,231 @@
 package operations
 
 import (
-	"fmt"
+	"errors"
 	"github.com/litetable/litetable-db/internal/litetable"
+	"maps"
+	"slices"
 	"sort"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 )
 
-func (m *Manager) Read(query string) (map[string]*litetable.Row, error) {
+// Read runs a read query. Rows that do not exist are not an error: a query that matches nothing
+// returns an empty result set, so clients can check for a row before writing it. The column
+// family must exist. The cost of the read is returned with the rows.
+func (m *Manager) Read(query string) (map[string]*litetable.Row, litetable.Cost, error) {
 	// Parse the query
 	parsed, err := parseRead(query)
 	if err != nil {
-		return nil, err
+		return nil, litetable.Cost{}, err
 	}
 
-	if !m.shardStorage.IsFamilyAllowed(parsed.family) {
-		return nil, fmt.Errorf("column family does not exist: %s", parsed.family)
+	if parsed.aggregations.any() {
+		return nil, litetable.Cost{}, newError(errInvalidFormat,
+			"aggregate is only supported by aggregate queries")
 	}
 
-	// Alt case 1: Row key prefix filtering
-	if parsed.rowKeyPrefix != "" {
-		d, found := m.shardStorage.FilterRowsByPrefix(parsed.rowKeyPrefix)
-		if !found {
-			return nil, fmt.Errorf("no rows found with prefix: %s", parsed.rowKeyPrefix)
-		}
-
-		result := parsed.processFilteredData(*d)
-		if len(result) == 0 {
-			return nil, fmt.Errorf("no matching rows found with prefix: %s", parsed.rowKeyPrefix)
-		}
-		return result, nil
+	if parsed.cacheable() {
+		return m.readCached(parsed)
 	}
+	return m.read(parsed)
+}
 
-	// Alt case 2: Row key regex matching
-	if parsed.rowKeyRegex != "" {
-		data, found := m.shardStorage.FilterRowsByRegex(parsed.rowKeyRegex)
-		if !found {
-			return nil, fmt.Errorf("no rows found matching regex: %s", parsed.rowKeyRegex)
-		}
-
-		result := parsed.processFilteredData(*data)
-		if len(result) == 0 {
-			return nil, fmt.Errorf("no matching rows found with regex: %s", parsed.rowKeyRegex)
+// readCached runs a plain prefix read through the scan cache of its table. A read answered from
+// the cache costs nothing.
+func (m *Manager) readCached(parsed *readQuery) (map[string]*litetable.Row, litetable.Cost,
+	error) {
+	storage, err := m.storage(parsed.table, "read")
+	if err != nil {
+		return nil, litetable.Cost{}, err
+	}
+	if !storage.CachesScans() {
+		return m.read(parsed)
+	}
 
-		}
+	var cost litetable.Cost
+	scanned := false
+	rows, err := storage.ScanCached(parsed.rowKeyPrefix, parsed.family, parsed.latest,
+		func() (map[string]*litetable.Row, bool, error) {
+			// the scan stays paged, and only a scan that read every row of the prefix within
+			// the page is cached, so a large prefix is neither read whole nor kept
+			rows, c, err := m.read(parsed)
+			cost, scanned = c, true
+			return rows, !parsed.more, err
+		})
+	if err == nil && !scanned {
+		countRead(parsed)
+	}
+	return rows, cost, err
+}
 
-		return result, nil
+// read runs a read query against storage.
+func (m *Manager) read(parsed *readQuery) (map[string]*litetable.Row, litetable.Cost, error) {
+	reader, err := m.reader(parsed, "read")
+	if err != nil {
+		return nil, litetable.Cost{}, err
+	}
+	data, found, err := parsed.scan(reader)
+	if err != nil {
+		return nil, litetable.Cost{}, err
+	}
+	if !found {
+		return map[string]*litetable.Row{}, parsed.cost, nil
 	}
 
-	// default to read by rowKey:
-	data, exists := m.shardStorage.GetRowByFamily(parsed.rowKey, parsed.family)
-	if !exists {
-		return nil, fmt.Errorf("row not found: %s", parsed.rowKey)
+	// Alt case: row key prefix or regex filtering
+	if parsed.rowKey == "" {
+		rows, err := parsed.filterRows(reader, *data)
+		if err != nil {
+			return nil, parsed.cost, err
+		}
+		return rows, parsed.cost, nil
 	}
 
 	// Create a proper Row structure with the data
 	row, err := parsed.readRowKey(data)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, litetable.ErrNotFound) {
+			return map[string]*litetable.Row{}, parsed.cost, nil
+		}
+		return nil, parsed.cost, err
+	}
+
+	// every qualifier may have been filtered out by tombstones
+	if len(row.Columns[parsed.family]) == 0 {
+		return map[string]*litetable.Row{}, parsed.cost, nil
 	}
 
 	r := map[string]*litetable.Row{
 		row.Key: row,
 	}
+	if err = parsed.project(r); err != nil {
+		return nil, parsed.cost, err
+	}
+
+	return r, parsed.cost, nil
+}
 
-	return r, nil
+// scan returns the stored rows a read query matches, reading a single row by its key by
+// default. found is false when nothing matched.
+func (m *Manager) scan(parsed *readQuery, operation string) (*litetable.Data, bool, error) {
+	reader, err := m.reader(parsed, operation)
+	if err != nil {
+		return nil, false, err
+	}
+	return parsed.scan(reader)
+}
+
+// reader returns the reader of the table a read query reads, once the family is checked.
+func (m *Manager) reader(parsed *readQuery, operation string) (litetable.RowReader, error) {
+	storage, err := m.storage(parsed.table, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	if !storage.IsFamilyAllowed(parsed.family) {
+		return nil, litetable.NewError(litetable.ErrorCodeFamilyMissing,
+			"column family does not exist: %s", parsed.family)
+	}
+	countRead(parsed)
+
+	// a scan reads every shard at the same point, so it never sees a write in some shards but
+	// not in others
+	if parsed.readAt == 0 && (parsed.rowKeyPrefix != "" || parsed.rowKeyRegex != "") {
+		parsed.readAt = storage.Sequence()
+	}
+
+	// eventually consistent reads use the read view of the table when it keeps one
+	var reader litetable.RowReader = storage
+	if parsed.eventual {
+		if view, ok := storage.ReadView(); ok {
+			reader = view
+		}
+	}
+	return reader, nil
+}
+
+// scan reads the rows the query matches from reader, or the page of them after r.after when
+// the query is paged.
+func (r *readQuery) scan(reader litetable.RowReader) (*litetable.Data, bool, error) {
+	// a scan costs every row key it examined, whether or not it matched
+	page := litetable.ScanPage{After: r.after, MaxBytes: r.pageBytes}
+	var data *litetable.Data
+	var stats litetable.ScanStats
+	var found bool
+	var err error
+	switch {
+	case r.rowKeyPrefix != "":
+		data, stats, found = reader.FilterRowsByPrefix(r.rowKeyPrefix, page)
+	case r.rowKeyRegex != "":
+		data, stats, found, err = reader.FilterRowsByRegex(r.rowKeyRegex, page)
+	default:
+		data, found = reader.GetRowByFamily(r.rowKey, r.family)
+		if found {
+			stats.Examined = 1
+		}
+	}
+	r.cost.RowsScanned += stats.Examined
+	r.more = stats.More
+	if err != nil {
+		return nil, false, err
+	}
+	return data, found, nil
+}
+
+// filterRows filters and projects the rows of a prefix or regex scan. A scan for a page stops
+// once the rows it read pass the page size, before they are filtered, so it is continued after
+// its last row until the rows kept pass the page size, which tells the caller to truncate them,
+// or no rows are left.
+func (r *readQuery) filterRows(reader litetable.RowReader,
+	data litetable.Data) (map[string]*litetable.Row, error) {
+	rows := make(map[string]*litetable.Row)
+	size := 0
+	for {
+		page := r.processFilteredData(data)
+		if err := r.project(page); err != nil {
+			return nil, err
+		}
+		for rowKey, row := range page {
+			rows[rowKey] = row
+			size += rowBytes(row)
+		}
+		if !r.more || size > r.pageBytes {
+			return rows, nil
+		}
+
+		r.after = slices.Max(slices.Collect(maps.Keys(data)))
+		next, found, err := r.scan(reader)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return rows, nil
+		}
+		data = *next
+	}
+}
+
+// rowBytes is the size of the key, the family and qualifier names and the values of a read row,
+// which its encoding in a response is never smaller than.
+func rowBytes(row *litetable.Row) int {
+	n := len(row.Key)
+	for family, qualifiers := range row.Columns {
+		n += len(family)
+		for qualifier, values := range qualifiers {
+			n += len(qualifier)
+			for _, v := range values {
+				n += len(v.Value)
+			}
+		}
+	}
+	return n
 }
 
 // readQuery are the parameters for any supported read query
 type readQuery struct {
+	table        string
 	rowKey       string
 	rowKeyPrefix string
 	rowKeyRegex  string
@@ -78,28 +233,85 @@ type readQuery struct {
 	qualifiers   []string
 	latest       int       // Number of most recent versions to return
 	timestamp    time.Time // Reserved for future use
+	// includeTombstones returns tombstones and expired values with their expiry instead of
+	// filtering them out, for debugging and CDC reconciliation
+	includeTombstones bool
+	// readAt hides the values of mutations numbered after it. Zero reads the latest values.
+	readAt uint64
+	// asOf reads the versions visible at this time in Unix nanoseconds, hiding the values and
+	// tombstones timestamped after it and the values that had expired by then. Zero reads the
+	// versions visible now.
+	asOf int64
+	// ascending lists versions oldest first instead of newest first
+	ascending bool
+	// after skips the rows of a prefix or regex read up to and including this row key, to read
+	// the next page of a truncated read
+	after string
+	// pageBytes stops a prefix or regex read once the rows it returns pass this many bytes, so a
+	// page of a large scan does not read every row. Zero reads every row.
+	pageBytes int
+	// more is set when the last scan for a page stopped before its last matching row
+	more bool
+	// eventual reads the read view of the table instead of locking its shards
+	eventual bool
+	// aggregations are computed instead of returning the values
+	aggregations aggregations
+	// jsonPaths replace every value returned with the fields of the JSON value at these paths
+	jsonPaths []jsonPath
+	// values is the slab the returned value slices are copied into
+	values litetable.ValueSlab
+	// cost counts the rows the query read and the values it copied
+	cost litetable.Cost
+}
+
+// cacheable reports whether the query is a strongly consistent prefix read of the latest values
+// that only names a family and how many versions to return, as dashboards send, so its rows can
+// be cached.
+func (q *readQuery) cacheable() bool {
+	return q.rowKeyPrefix != "" && len(q.qualifiers) == 0 && q.readAt == 0 && q.asOf == 0 &&
+		!q.includeTombstones && !q.ascending && q.after == "" && !q.eventual &&
+		len(q.jsonPaths) == 0
+}
+
+// filterPool holds the scratch slices getLatestN filters values into before the latest N are
+// copied out.
+var filterPool = sync.Pool{
+	New: func() any { return new([]litetable.TimestampedValue) },
 }
 
-// parseRead parses a query and returns a ReadQuery which is used to safely run an operation.
-// If there are any errors, it will return a operations.Error
+// parseRead parses a query in the grammar version it names and returns a ReadQuery which is
+// used to safely run an operation. If there are any errors, it will return a operations.Error
 func parseRead(input string) (*readQuery, error) {
-	parts := strings.Fields(input)
+	version, query, err := queryVersion(input)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenizeQuery(version, query)
+	if err != nil {
+		return nil, err
+	}
+	return parseReadTokens(tokens, query)
+}
+
+// parseReadTokens parses the parameters of a read query.
+func parseReadTokens(tokens []queryToken, input string) (*readQuery, error) {
 	parsed := &readQuery{
 		qualifiers: []string{},
 		latest:     0, // 0 means all versions
 	}
 
-	for _, part := range parts {
-		kv := strings.SplitN(part, "=", 2)
-		if len(kv) != 2 {
+	for _, token := range tokens {
+		if !token.ok {
 			return nil, newError(errInvalidFormat,
 				"queries must include at least a column family and a search key, got: %s",
 				input)
 		}
 
-		key, value := kv[0], kv[1]
+		key, value := token.name, token.value
 
 		switch key {
+		case "table":
+			parsed.table = value
 		case "key":
 			parsed.rowKey = value
 		case "prefix":
@@ -121,6 +333,66 @@ func parseRead(input string) (*readQuery, error) {
 					"latest must be greater than 0. received %d", n)
 			}
 			parsed.latest = n
+		case "includeTombstones":
+			include, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, newError(errInvalidFormat,
+					"includeTombstones must be true or false. received %s", value)
+			}
+			parsed.includeTombstones = include
+		case "readAt":
+			seq, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, newError(errInvalidFormat,
+					"readAt must be a sequence number. received %s", value)
+			}
+			parsed.readAt = seq
+		case "asOf":
+			asOf, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || asOf <= 0 {
+				return nil, newError(errInvalidFormat,
+					"asOf must be a timestamp in Unix nanoseconds. received %s", value)
+			}
+			parsed.asOf = asOf
+		case "order":
+			switch value {
+			case "asc":
+				parsed.ascending = true
+			case "desc":
+				parsed.ascending = false
+			default:
+				return nil, newError(errInvalidFormat,
+					"order must be asc or desc. received %s", value)
+			}
+		case "after":
+			parsed.after = value
+		case "pageBytes":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return nil, newError(errInvalidFormat,
+					"pageBytes must be a positive number. received %s", value)
+			}
+			parsed.pageBytes = n
+		case "consistency":
+			switch value {
+			case "strong":
+				parsed.eventual = false
+			case "eventual":
+				parsed.eventual = true
+			default:
+				return nil, newError(errInvalidFormat,
+					"consistency must be strong or eventual. received %s", value)
+			}
+		case "aggregate":
+			if err := parsed.aggregations.add(value); err != nil {
+				return nil, err
+			}
+		case "jsonPath":
+			path, err := parseJSONPath(value)
+			if err != nil {
+				return nil, err
+			}
+			parsed.jsonPaths = append(parsed.jsonPaths, path)
 		case "timestamp":
 			t, err := time.Parse(time.RFC3339, value)
 			if err != nil {
@@ -156,6 +428,14 @@ func parseRead(input string) (*readQuery, error) {
 				"or rowKeyRegex")
 	}
 
+	if parsed.after != "" && parsed.rowKey != "" {
+		return nil, newError(errInvalidFormat, "after only applies to prefix and regex queries")
+	}
+	if parsed.pageBytes > 0 && parsed.rowKey != "" {
+		return nil, newError(errInvalidFormat,
+			"pageBytes only applies to prefix and regex queries")
+	}
+
 	// Family is always required
 	if parsed.family == "" {
 		return nil, newError(errInvalidFormat, "missing family")
@@ -170,90 +450,156 @@ func (r *readQuery) readRowKey(data *litetable.Data) (*litetable.Row, error) {
 	// Check if the row exists
 	row, exists := (*data)[r.rowKey]
 	if !exists {
-		return nil, fmt.Errorf("row not found: %s", r.rowKey)
+		return nil, litetable.NewError(litetable.ErrorCodeNotFound, "row not found: %s", r.rowKey)
 	}
 
 	// Check if the family exists
 	family, exists := row[r.family]
 	if !exists {
-		return nil, fmt.Errorf("family not found: %s", r.family)
+		return nil, litetable.NewError(litetable.ErrorCodeNotFound, "family not found: %s", r.family)
 	}
 
-	// Create result container
+	return r.filterFamily(r.rowKey, family), nil
+}
+
+// filterFamily builds the result row for a family of a stored row, keeping the requested
+// qualifiers and only the versions getLatestN returns. Qualifiers left without any versions are
+// dropped, so the family may end up empty.
+func (r *readQuery) filterFamily(rowKey string, family litetable.VersionedQualifier) *litetable.Row {
 	result := &litetable.Row{
-		Key:     r.rowKey,
+		Key:     rowKey,
 		Columns: make(map[string]litetable.VersionedQualifier),
 	}
 	result.Columns[r.family] = make(litetable.VersionedQualifier)
 
 	// If no qualifiers specified, return all qualifiers in the family
-	if len(r.qualifiers) == 0 {
-		for qualifier, values := range family {
-			filteredValues := r.getLatestN(values, r.latest)
-			// Only add qualifier if it has values after tombstone filtering
-			if len(filteredValues) > 0 {
-				result.Columns[r.family][qualifier] = filteredValues
-			}
+	qualifiers := r.qualifiers
+	if len(qualifiers) == 0 {
+		qualifiers = make([]string, 0, len(family))
+		for qualifier := range family {
+			qualifiers = append(qualifiers, qualifier)
 		}
-	} else {
-		// Return only requested qualifiers
-		for _, qualifier := range r.qualifiers {
-			values, exists := family[qualifier]
-			if !exists {
-				continue // Skip non-existing qualifiers
-			}
-			result.Columns[r.family][qualifier] = r.getLatestN(values, r.latest)
+	}
+
+	// make room for every value the family can return in one allocation
+	var size int
+	for _, qualifier := range qualifiers {
+		n := len(family[qualifier])
+		if r.latest > 0 && r.latest < n {
+			n = r.latest
 		}
+		size += n
 	}
+	r.values.Grow(size)
 
-	return result, nil
+	for _, qualifier := range qualifiers {
+		values, exists := family[qualifier]
+		if !exists {
+			continue // Skip non-existing qualifiers
+		}
+		// Only add qualifier if it has values after tombstone filtering
+		if filteredValues := r.getLatestN(values, r.latest); len(filteredValues) > 0 {
+			result.Columns[r.family][qualifier] = filteredValues
+			r.cost.CellsCopied += len(filteredValues)
+		}
+	}
+
+	return result
 }
 
-// getLatestN returns the latest N values from a slice of TimestampedValue.
+// getLatestN returns a copy of the latest N values from a slice of TimestampedValue, as filtered
+// by latestN.
 func (r *readQuery) getLatestN(values []litetable.TimestampedValue, n int) []litetable.TimestampedValue {
+	scratch := filterPool.Get().(*[]litetable.TimestampedValue)
+	defer releaseScratch(scratch)
+	return r.values.Copy(r.latestN(values, n, scratch))
+}
+
+// latestN returns the latest N values from a slice of TimestampedValue in the scratch slice,
+// newest first unless the query is in ascending order. Tombstones, the values they shadow and
+// expired values are filtered out unless the query includes tombstones, in which case the latest
+// N entries are returned as they are stored. The stored values are shared with snapshots and
+// other readers, so they are sorted in the scratch slice.
+func (r *readQuery) latestN(values []litetable.TimestampedValue, n int,
+	scratch *[]litetable.TimestampedValue) []litetable.TimestampedValue {
 	if len(values) == 0 {
 		return nil
 	}
 
-	// Sort by timestamp descending (newest first)
-	sort.Slice(values, func(i, j int) bool {
-		return values[i].Timestamp > values[j].Timestamp
-	})
-
-	// Filter out values based on tombstones
-	var tombstoneTimestamp int64
-	var hasTombstone bool
-	valuesCopy := make([]litetable.TimestampedValue, 0, len(values))
-
-	// First pass: Find the newest tombstone (if any)
-	for _, v := range values {
-		if v.IsTombstone {
-			if !hasTombstone || v.Timestamp > tombstoneTimestamp {
-				tombstoneTimestamp = v.Timestamp
-				hasTombstone = true
+	valuesCopy := (*scratch)[:0]
+	// values are usually appended oldest first, so they are collected in reverse to leave little
+	// for the sort to do
+	if r.includeTombstones {
+		for i := len(values) - 1; i >= 0; i-- {
+			if r.visible(values[i]) {
+				valuesCopy = append(valuesCopy, values[i])
+			}
+		}
+	} else {
+		// First pass: Find the newest tombstone (if any)
+		var tombstoneTimestamp int64
+		var hasTombstone bool
+		for _, v := range values {
+			if v.IsTombstone && r.visible(v) {
+				if !hasTombstone || v.Timestamp > tombstoneTimestamp {
+					tombstoneTimestamp = v.Timestamp
+					hasTombstone = true
+				}
 			}
 		}
-	}
 
-	// Second pass: Keep only values newer than the tombstone
-	for _, v := range values {
-		if !v.IsTombstone && (!hasTombstone || v.Timestamp > tombstoneTimestamp) {
-			valuesCopy = append(valuesCopy, v)
+		// Second pass: Keep only values newer than the tombstone that have not expired
+		now := time.Now().UnixNano()
+		if r.asOf > 0 {
+			now = r.asOf
+		}
+		for i := len(values) - 1; i >= 0; i-- {
+			v := values[i]
+			if v.IsExpired(now) || !r.visible(v) {
+				continue
+			}
+			if !v.IsTombstone && (!hasTombstone || v.Timestamp > tombstoneTimestamp) {
+				valuesCopy = append(valuesCopy, v)
+			}
 		}
 	}
+	*scratch = valuesCopy
 
 	// If no valid values after filtering, return nil
 	if len(valuesCopy) == 0 {
 		return nil
 	}
 
+	// Sort by timestamp descending (newest first). The values were collected newest write
+	// first, so the stable sort lists writes with the same timestamp newest write first too.
+	sort.SliceStable(valuesCopy, func(i, j int) bool {
+		return valuesCopy[i].Timestamp > valuesCopy[j].Timestamp
+	})
+
 	// If n is 0 or greater than the length, return all values
 	if n <= 0 || n >= len(valuesCopy) {
-		return valuesCopy
+		n = len(valuesCopy)
 	}
 
 	// Otherwise, return the top n values
-	return valuesCopy[:n]
+	latest := valuesCopy[:n]
+	if r.ascending {
+		slices.Reverse(latest)
+	}
+	return latest
+}
+
+// releaseScratch returns a scratch slice to filterPool, dropping its references to the values
+// before it is reused.
+func releaseScratch(scratch *[]litetable.TimestampedValue) {
+	clear(*scratch)
+	*scratch = (*scratch)[:0]
+	filterPool.Put(scratch)
+}
+
+// visible reports whether a value was stored by a mutation the query can see.
+func (r *readQuery) visible(v litetable.TimestampedValue) bool {
+	return (r.readAt == 0 || v.Seq <= r.readAt) && (r.asOf == 0 || v.Timestamp <= r.asOf)
 }
 
 // processFilteredData takes raw data returned from sharded storage and applies
@@ -268,35 +614,7 @@ func (r *readQuery) processFilteredData(data litetable.Data) map[string]*litetab
 			continue
 		}
 
-		// Create result container for this row
-		result := &litetable.Row{
-			Key:     rowKey,
-			Columns: make(map[string]litetable.VersionedQualifier),
-		}
-		result.Columns[r.family] = make(litetable.VersionedQualifier)
-
-		// If no qualifiers specified, return all qualifiers in the family
-		if len(r.qualifiers) == 0 {
-			for qualifier, values := range family {
-				filteredValues := r.getLatestN(values, r.latest)
-				// Only add qualifier if it has values after tombstone filtering
-				if len(filteredValues) > 0 {
-					result.Columns[r.family][qualifier] = filteredValues
-				}
-			}
-		} else {
-			// Return only requested qualifiers
-			for _, qualifier := range r.qualifiers {
-				values, exists := family[qualifier]
-				if !exists {
-					continue // Skip non-existing qualifiers
-				}
-				filteredValues := r.getLatestN(values, r.latest)
-				if len(filteredValues) > 0 {
-					result.Columns[r.family][qualifier] = filteredValues
-				}
-			}
-		}
+		result := r.filterFamily(rowKey, family)
 
 		// Only add non-empty rows to results
 		if len(result.Columns[r.family]) > 0 {
@@ -0,0 +1,566 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestManager_Read(t *testing.T) {
+	now := int64(1_000)
+	row := &litetable.Data{
+		"champ:1": {
+			"wrestlers": {
+				"name":    {{Value: []byte("John"), Timestamp: now}},
+				"deleted": {{Value: nil, Timestamp: now, IsTombstone: true}},
+				"session": {{Value: []byte("abc"), Timestamp: now, ExpiresAt: now + 1}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		query      string
+		mockSetup  func(m *MockshardManager)
+		expectRows []string
+		expectErr  error
+		expectCost *litetable.Cost
+	}{
+		"family does not exist": {
+			query: "key=champ:1 family=nope",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("nope").Return(false)
+			},
+			expectErr: litetable.ErrFamilyMissing,
+		},
+		"missing row is an empty result": {
+			query: "key=champ:2 family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:2", "wrestlers").Return(nil, false)
+			},
+		},
+		"fully tombstoned qualifier is an empty result": {
+			query: "key=champ:1 family=wrestlers qualifier=deleted",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+		},
+		"expired qualifier is an empty result": {
+			query: "key=champ:1 family=wrestlers qualifier=session",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+		},
+		"tombstones and expired values on request": {
+			query: "key=champ:1 family=wrestlers qualifier=deleted qualifier=session " +
+				"includeTombstones=true",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+			expectRows: []string{"champ:1"},
+		},
+		"invalid order": {
+			query:     "key=champ:1 family=wrestlers order=oldest",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid includeTombstones": {
+			query:     "key=champ:1 family=wrestlers includeTombstones=maybe",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"existing row": {
+			query: "key=champ:1 family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+			expectRows: []string{"champ:1"},
+		},
+		"prefix without matches is an empty result": {
+			query: "prefix=champ: family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				expectScanCache(m, "champ:", "wrestlers", 0)
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(0))
+				m.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{}).
+					Return(&litetable.Data{}, litetable.ScanStats{Examined: 2}, false)
+			},
+		},
+		"prefix filters tombstones like a key read": {
+			query: "prefix=champ: family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				expectScanCache(m, "champ:", "wrestlers", 0)
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(0))
+				m.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{}).
+					Return(&litetable.Data{
+						"champ:1": (*row)["champ:1"],
+						"champ:2": {"wrestlers": {
+							"name": {{Value: nil, Timestamp: now, IsTombstone: true}},
+						}},
+					}, litetable.ScanStats{Examined: 2}, true)
+			},
+			expectRows: []string{"champ:1"},
+		},
+		"regex without matches is an empty result": {
+			query: "regex=^champ family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(0))
+				m.EXPECT().FilterRowsByRegex("^champ", litetable.ScanPage{}).
+					Return(&litetable.Data{}, litetable.ScanStats{Examined: 2}, false, nil)
+			},
+		},
+		"regex scan over its budget": {
+			query: "regex=^champ family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(0))
+				m.EXPECT().FilterRowsByRegex("^champ", litetable.ScanPage{}).
+					Return(nil, litetable.ScanStats{Examined: 2}, false, litetable.NewError(
+						litetable.ErrorCodeExhausted, "regex scan exceeded its budget"))
+			},
+			expectErr: litetable.ErrExhausted,
+		},
+		"prefix read from the scan cache": {
+			query: "prefix=champ: family=wrestlers latest=1",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().CachesScans().Return(true)
+				m.EXPECT().ScanCached("champ:", "wrestlers", 1, gomock.Any()).Return(
+					map[string]*litetable.Row{"champ:1": {Key: "champ:1"}}, nil)
+			},
+			expectRows: []string{"champ:1"},
+			expectCost: &litetable.Cost{},
+		},
+		"prefix read of a qualifier skips the scan cache": {
+			query: "prefix=champ: family=wrestlers qualifier=name",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(0))
+				m.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{}).
+					Return(&litetable.Data{}, litetable.ScanStats{Examined: 5}, false)
+			},
+			// a scan matching nothing still costs the row keys it examined
+			expectCost: &litetable.Cost{RowsScanned: 5},
+		},
+		"eventual read uses the read view": {
+			query: "key=champ:1 family=wrestlers consistency=eventual",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().ReadView().Return(staticView{data: row}, true)
+			},
+			expectRows: []string{"champ:1"},
+		},
+		"eventual read without a read view locks the shards": {
+			query: "key=champ:1 family=wrestlers consistency=eventual",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().ReadView().Return(nil, false)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+			expectRows: []string{"champ:1"},
+		},
+		"unknown consistency": {
+			query:     "key=champ:1 family=wrestlers consistency=linearizable",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"prefix scan hides writes made after it started": {
+			query: "prefix=champ: family=wrestlers",
+			mockSetup: func(m *MockshardManager) {
+				expectScanCache(m, "champ:", "wrestlers", 0)
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(3))
+				m.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{}).
+					Return(&litetable.Data{
+						"champ:1": {"wrestlers": {
+							"name": {{Value: []byte("John"), Timestamp: now, Seq: 3}},
+						}},
+						"champ:2": {"wrestlers": {
+							"name": {{Value: []byte("Randy"), Timestamp: now, Seq: 4}},
+						}},
+					}, litetable.ScanStats{Examined: 2}, true)
+			},
+			expectRows: []string{"champ:1"},
+		},
+		"readAt hides a later tombstone": {
+			query: "key=champ:1 family=wrestlers readAt=2",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(&litetable.Data{
+					"champ:1": {"wrestlers": {
+						"name": {
+							{Timestamp: now + 1, IsTombstone: true, Seq: 5},
+							{Value: []byte("John"), Timestamp: now, Seq: 1},
+						},
+					}},
+				}, true)
+			},
+			expectRows: []string{"champ:1"},
+		},
+		"readAt hides a later write": {
+			query: "regex=^champ family=wrestlers readAt=2",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().FilterRowsByRegex("^champ", litetable.ScanPage{}).Return(&litetable.Data{
+					"champ:1": {"wrestlers": {
+						"name": {{Value: []byte("John"), Timestamp: now, Seq: 3}},
+					}},
+				}, litetable.ScanStats{Examined: 2}, true, nil)
+			},
+		},
+		"after skips the rows of earlier pages": {
+			query: "prefix=champ: family=wrestlers after=champ:1",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(3))
+				m.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{After: "champ:1"}).
+					Return(&litetable.Data{
+						"champ:2": {"wrestlers": {"name": {{Value: []byte("Vi"), Timestamp: now}}}},
+					}, litetable.ScanStats{Examined: 2}, true)
+			},
+			expectRows: []string{"champ:2"},
+			// both row keys are examined, but only champ:2 is read
+			expectCost: &litetable.Cost{RowsScanned: 2, CellsCopied: 1},
+		},
+		"after of a row read": {
+			query:     "key=champ:1 family=wrestlers after=champ:0",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"a page scan goes on until its rows pass the page size": {
+			query: "prefix=champ: family=wrestlers pageBytes=10",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().CachesScans().Return(false)
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(3))
+				// the first page only holds a tombstone, so the scan goes on after it
+				m.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{MaxBytes: 10}).
+					Return(&litetable.Data{
+						"champ:1": {"wrestlers": {
+							"name": {{Timestamp: now, IsTombstone: true}},
+						}},
+					}, litetable.ScanStats{Examined: 3, More: true}, true)
+				m.EXPECT().FilterRowsByPrefix("champ:",
+					litetable.ScanPage{After: "champ:1", MaxBytes: 10}).
+					Return(&litetable.Data{
+						"champ:2": {"wrestlers": {"name": {{Value: []byte("Vi"), Timestamp: now}}}},
+					}, litetable.ScanStats{Examined: 3}, true)
+			},
+			expectRows: []string{"champ:2"},
+			expectCost: &litetable.Cost{RowsScanned: 6, CellsCopied: 1},
+		},
+		"pageBytes of a row read": {
+			query:     "key=champ:1 family=wrestlers pageBytes=10",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid pageBytes": {
+			query:     "prefix=champ: family=wrestlers pageBytes=0",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid readAt": {
+			query:     "key=champ:1 family=wrestlers readAt=-1",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"asOf hides a later write": {
+			query: "prefix=champ: family=wrestlers asOf=999",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().Sequence().Return(uint64(3))
+				m.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{}).
+					Return(&litetable.Data{
+						"champ:1": {"wrestlers": {
+							"name": {{Value: []byte("John"), Timestamp: now - 2, Seq: 1}},
+						}},
+						"champ:2": {"wrestlers": {
+							"name": {{Value: []byte("Randy"), Timestamp: now, Seq: 2}},
+						}},
+					}, litetable.ScanStats{Examined: 2}, true)
+			},
+			expectRows: []string{"champ:1"},
+		},
+		"invalid asOf": {
+			query:     "key=champ:1 family=wrestlers asOf=yesterday",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"jsonPath of a value that is not JSON": {
+			query: "key=champ:1 family=wrestlers jsonPath=name",
+			mockSetup: func(m *MockshardManager) {
+				m.EXPECT().IsFamilyAllowed("wrestlers").Return(true)
+				m.EXPECT().GetRowByFamily("champ:1", "wrestlers").Return(row, true)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid jsonPath": {
+			query:     "key=champ:1 family=wrestlers jsonPath=address..city",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid query": {
+			query:     "family=wrestlers",
+			expectErr: litetable.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			ctrl := gomock.NewController(t)
+			storage := NewMockshardManager(ctrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(storage)
+			}
+
+			m := &Manager{shardStorage: storage}
+			got, cost, err := m.Read(tc.query)
+			if tc.expectErr != nil {
+				req.Error(err)
+				req.True(errors.Is(err, tc.expectErr))
+				return
+			}
+
+			req.NoError(err)
+			req.NotNil(got)
+			var keys []string
+			for key := range got {
+				keys = append(keys, key)
+			}
+			req.ElementsMatch(tc.expectRows, keys)
+			if tc.expectCost != nil {
+				req.Equal(*tc.expectCost, cost)
+			}
+		})
+	}
+}
+
+func TestReadQuery_getLatestN(t *testing.T) {
+	values := []litetable.TimestampedValue{
+		{Value: []byte("v1"), Timestamp: 1},
+		{Timestamp: 2, IsTombstone: true, ExpiresAt: 100},
+		{Value: []byte("v3"), Timestamp: 3},
+		{Value: []byte("v4"), Timestamp: 4, ExpiresAt: 5},
+	}
+
+	tests := map[string]struct {
+		query *readQuery
+		n     int
+		want  []int64
+	}{
+		"tombstones and expired values are filtered": {
+			query: &readQuery{},
+			want:  []int64{3},
+		},
+		"tombstones and expired values are included": {
+			query: &readQuery{includeTombstones: true},
+			want:  []int64{4, 3, 2, 1},
+		},
+		"latest counts every included entry": {
+			query: &readQuery{includeTombstones: true},
+			n:     2,
+			want:  []int64{4, 3},
+		},
+		"ascending order": {
+			query: &readQuery{includeTombstones: true, ascending: true},
+			want:  []int64{1, 2, 3, 4},
+		},
+		"ascending order keeps the latest n": {
+			query: &readQuery{includeTombstones: true, ascending: true},
+			n:     2,
+			want:  []int64{3, 4},
+		},
+		"asOf before the tombstone": {
+			query: &readQuery{asOf: 1},
+			want:  []int64{1},
+		},
+		"asOf after the tombstone": {
+			query: &readQuery{asOf: 2},
+		},
+		"asOf before the value expired": {
+			query: &readQuery{asOf: 4},
+			want:  []int64{4, 3},
+		},
+		"asOf includes the entries stored by then": {
+			query: &readQuery{includeTombstones: true, asOf: 2},
+			want:  []int64{2, 1},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := make([]litetable.TimestampedValue, len(values))
+			copy(input, values)
+
+			var got []int64
+			for _, v := range tc.query.getLatestN(input, tc.n) {
+				got = append(got, v.Timestamp)
+			}
+			require.Equal(t, tc.want, got)
+
+			// the stored values are shared, so they are not reordered
+			require.Equal(t, values, input)
+		})
+	}
+}
+
+func TestReadQuery_getLatestN_EqualTimestamps(t *testing.T) {
+	values := []litetable.TimestampedValue{
+		{Value: []byte("a"), Timestamp: 1},
+		{Value: []byte("b"), Timestamp: 1},
+		{Value: []byte("c"), Timestamp: 1},
+	}
+
+	var got []string
+	for _, v := range (&readQuery{}).getLatestN(values, 0) {
+		got = append(got, string(v.Value))
+	}
+	require.Equal(t, []string{"c", "b", "a"}, got)
+
+	got = got[:0]
+	for _, v := range (&readQuery{ascending: true}).getLatestN(values, 0) {
+		got = append(got, string(v.Value))
+	}
+	require.Equal(t, []string{"a", "b", "c"}, got)
+}
+
+func BenchmarkManager_Read(b *testing.B) {
+	data := litetable.Data{}
+	for i := 0; i < 100; i++ {
+		qualifiers := litetable.VersionedQualifier{}
+		for _, q := range []string{"name", "role", "lane", "region", "rank"} {
+			for ts := int64(1); ts <= 5; ts++ {
+				qualifiers[q] = append(qualifiers[q],
+					litetable.TimestampedValue{Value: []byte(q), Timestamp: ts})
+			}
+		}
+		data[fmt.Sprintf("champ:%d", i)] = map[string]litetable.VersionedQualifier{
+			"wrestlers": qualifiers,
+		}
+	}
+
+	ctrl := gomock.NewController(b)
+	storage := NewMockshardManager(ctrl)
+	storage.EXPECT().IsFamilyAllowed("wrestlers").Return(true).AnyTimes()
+	storage.EXPECT().FilterRowsByPrefix("champ:", litetable.ScanPage{}).
+		Return(&data, litetable.ScanStats{Examined: 2}, true).AnyTimes()
+	m := &Manager{shardStorage: storage}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := m.Read("prefix=champ: family=wrestlers latest=2"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// FuzzParseRead locks in the read grammar: space separated name=value pairs, where a value runs
+// from the first = to the next space and is not decoded.
+func FuzzParseRead(f *testing.F) {
+	f.Add("champ:1", "main", "name")
+	f.Fuzz(func(t *testing.T, key, family, qualifier string) {
+		req := require.New(t)
+		parsed, err := parseRead("key=" + key + " family=" + family + " qualifier=" + qualifier)
+		if err == nil {
+			// a query that parses has a single search key and a family
+			req.NotEmpty(parsed.rowKey)
+			req.Empty(parsed.rowKeyPrefix)
+			req.Empty(parsed.rowKeyRegex)
+			req.NotEmpty(parsed.family)
+		}
+		// a space splits a value into more pairs, so only values without one are read back
+		if strings.ContainsFunc(key+family+qualifier, unicode.IsSpace) {
+			return
+		}
+
+		if key == "" || family == "" {
+			req.Error(err)
+			return
+		}
+		req.NoError(err)
+		req.Equal(key, parsed.rowKey)
+		req.Equal(family, parsed.family)
+		req.Equal([]string{qualifier}, parsed.qualifiers)
+	})
+}
+
+// expectScanCache expects a prefix read to go through the scan cache of its table and miss.
+func expectScanCache(m *MockshardManager, prefix, family string, latest int) {
+	m.EXPECT().CachesScans().Return(true)
+	m.EXPECT().ScanCached(prefix, family, latest, gomock.Any()).DoAndReturn(
+		func(_, _ string, _ int, scan func() (map[string]*litetable.Row, bool, error)) (
+			map[string]*litetable.Row, error) {
+			rows, _, err := scan()
+			return rows, err
+		})
+}
+
+// staticView is a read view holding the same rows for every read.
+type staticView struct {
+	data *litetable.Data
+}
+
+func (v staticView) GetRowByFamily(_, _ string) (*litetable.Data, bool) {
+	return v.data, true
+}
+
+func (v staticView) FilterRowsByPrefix(_ string, _ litetable.ScanPage) (*litetable.Data,
+	litetable.ScanStats, bool) {
+	return v.data, litetable.ScanStats{Examined: len(*v.data)}, true
+}
+
+func (v staticView) FilterRowsByRegex(_ string, _ litetable.ScanPage) (*litetable.Data,
+	litetable.ScanStats, bool, error) {
+	return v.data, litetable.ScanStats{Examined: len(*v.data)}, true, nil
+}
+
+type fakeCDC struct{}
+
+func (fakeCDC) Emit(*v1.CDCEvent) {}
+
+func (fakeCDC) Spill(*v1.CDCEvent) bool { return false }
+
+// TestManager_Read_scanCachePages reads a prefix larger than a page with the scan cache on. The
+// scan stays paged and is not cached, while a prefix that fits in the page is.
+func TestManager_Read_scanCachePages(t *testing.T) {
+	req := require.New(t)
+	storage, _, err := shard_storage.New(&shard_storage.Config{
+		RootDir:        t.TempDir(),
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+		ScanCacheTTL:   time.Minute,
+	})
+	req.NoError(err)
+	req.NoError(storage.UpdateFamilies([]string{"main"}))
+	now := time.Now().UnixNano()
+	value := []byte(strings.Repeat("x", 100))
+	for i := range 20 {
+		req.NoError(storage.Apply(fmt.Sprintf("champ:%02d", i), "main", []string{"lore"},
+			[][]byte{value}, now, 0))
+	}
+	req.NoError(storage.Apply("wwe:1", "main", []string{"lore"}, [][]byte{value}, now, 0))
+	m := &Manager{shardStorage: storage}
+
+	for range 2 {
+		rows, cost, err := m.Read("prefix=champ: family=main pageBytes=300")
+		req.NoError(err)
+		req.Less(len(rows), 20)
+		// the first page was not cached, so the next read scans again
+		req.NotZero(cost.RowsScanned)
+	}
+
+	_, cost, err := m.Read("prefix=wwe: family=main pageBytes=300")
+	req.NoError(err)
+	req.NotZero(cost.RowsScanned)
+	rows, cost, err := m.Read("prefix=wwe: family=main pageBytes=300")
+	req.NoError(err)
+	req.Len(rows, 1)
+	req.Zero(cost.RowsScanned)
+}
@@ -0,0 +1,105 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"slices"
+)
+
+// ExportSchema returns every table with its column families, the default table first.
+func (m *Manager) ExportSchema() (litetable.Schema, error) {
+	schema := litetable.Schema{Version: litetable.SchemaVersion}
+	for _, table := range m.ListTables() {
+		storage, err := m.storage(table, "export_schema")
+		if err != nil {
+			return litetable.Schema{}, err
+		}
+		families := storage.GetFamilies()
+		slices.Sort(families)
+		schema.Tables = append(schema.Tables, litetable.TableSchema{
+			Name:     table,
+			Families: families,
+		})
+	}
+	return schema, nil
+}
+
+// ImportSchema creates the tables and column families of schema that do not exist yet and
+// returns them. Existing tables and families are left as they are, so a schema can be imported
+// again after it grows. The whole schema is checked before anything is created; with dryRun,
+// nothing is.
+func (m *Manager) ImportSchema(schema litetable.Schema, dryRun bool) ([]litetable.TableSchema,
+	error) {
+	if schema.Version < 1 || schema.Version > litetable.SchemaVersion {
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"schema version %d is not supported, expected 1 to %d", schema.Version,
+			litetable.SchemaVersion)
+	}
+
+	existing := m.ListTables()
+	seen := make(map[string]struct{}, len(schema.Tables))
+	var changes []litetable.TableSchema
+	for _, table := range schema.Tables {
+		if _, exists := seen[table.Name]; exists {
+			return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+				"table %s appears more than once in the schema", table.Name)
+		}
+		seen[table.Name] = struct{}{}
+
+		if err := m.checkFamilies(table.Families); err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(existing, table.Name) {
+			if m.tables == nil {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"tables are not supported by this server")
+			}
+			if !litetable.ValidTableName(table.Name) {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"table name must match %s, got %q", litetable.TableNamePattern(), table.Name)
+			}
+			changes = append(changes, litetable.TableSchema{
+				Name:     table.Name,
+				Families: slices.Clone(table.Families),
+			})
+			continue
+		}
+
+		storage, err := m.storage(table.Name, "import_schema")
+		if err != nil {
+			return nil, err
+		}
+		var missing []string
+		for _, family := range table.Families {
+			if !storage.IsFamilyAllowed(family) && !slices.Contains(missing, family) {
+				missing = append(missing, family)
+			}
+		}
+		if len(missing) > 0 {
+			changes = append(changes, litetable.TableSchema{Name: table.Name, Families: missing})
+		}
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+
+	for _, change := range changes {
+		if !slices.Contains(existing, change.Name) {
+			if err := m.tables.CreateTable(change.Name, change.Families); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		storage, err := m.storage(change.Name, "import_schema")
+		if err != nil {
+			return nil, err
+		}
+		if err = storage.UpdateFamilies(change.Families); err != nil {
+			return nil, litetable.WrapError(litetable.ErrorCodeInternal, err,
+				"failed to create families of table %s", change.Name)
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,133 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_ExportSchema(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	defaultTable := NewMockshardManager(ctrl)
+	defaultTable.EXPECT().GetFamilies().Return([]string{"main", "analytics"})
+	table := NewMockshardManager(ctrl)
+	table.EXPECT().GetFamilies().Return([]string{"wrestlers"})
+	catalog := NewMocktableCatalog(ctrl)
+	catalog.EXPECT().ListTables().Return([]string{"wwe"})
+	catalog.EXPECT().Table("wwe").Return(tableEngine{table}, nil)
+
+	m := &Manager{shardStorage: defaultTable, tables: catalog}
+	schema, err := m.ExportSchema()
+	req.NoError(err)
+	req.Equal(litetable.Schema{
+		Version: litetable.SchemaVersion,
+		Tables: []litetable.TableSchema{
+			{Name: litetable.DefaultTable, Families: []string{"analytics", "main"}},
+			{Name: "wwe", Families: []string{"wrestlers"}},
+		},
+	}, schema)
+}
+
+func TestManager_ImportSchema(t *testing.T) {
+	schema := litetable.Schema{
+		Version: litetable.SchemaVersion,
+		Tables: []litetable.TableSchema{
+			{Name: litetable.DefaultTable, Families: []string{"main", "analytics"}},
+			{Name: "wwe", Families: []string{"wrestlers"}},
+		},
+	}
+
+	tests := map[string]struct {
+		schema    litetable.Schema
+		dryRun    bool
+		mockSetup func(c *MocktableCatalog, defaultTable *MockshardManager)
+		expected  []litetable.TableSchema
+		expectErr error
+	}{
+		"creates missing tables and families": {
+			schema: schema,
+			mockSetup: func(c *MocktableCatalog, defaultTable *MockshardManager) {
+				c.EXPECT().ListTables().Return(nil)
+				defaultTable.EXPECT().IsFamilyAllowed("main").Return(true)
+				defaultTable.EXPECT().IsFamilyAllowed("analytics").Return(false)
+				defaultTable.EXPECT().UpdateFamilies([]string{"analytics"}).Return(nil)
+				c.EXPECT().CreateTable("wwe", []string{"wrestlers"}).Return(nil)
+			},
+			expected: []litetable.TableSchema{
+				{Name: litetable.DefaultTable, Families: []string{"analytics"}},
+				{Name: "wwe", Families: []string{"wrestlers"}},
+			},
+		},
+		"dry run creates nothing": {
+			schema: schema,
+			dryRun: true,
+			mockSetup: func(c *MocktableCatalog, defaultTable *MockshardManager) {
+				c.EXPECT().ListTables().Return(nil)
+				defaultTable.EXPECT().IsFamilyAllowed("main").Return(true)
+				defaultTable.EXPECT().IsFamilyAllowed("analytics").Return(false)
+			},
+			expected: []litetable.TableSchema{
+				{Name: litetable.DefaultTable, Families: []string{"analytics"}},
+				{Name: "wwe", Families: []string{"wrestlers"}},
+			},
+		},
+		"nothing to create": {
+			schema: litetable.Schema{
+				Version: litetable.SchemaVersion,
+				Tables:  []litetable.TableSchema{{Name: litetable.DefaultTable}},
+			},
+			mockSetup: func(c *MocktableCatalog, _ *MockshardManager) {
+				c.EXPECT().ListTables().Return(nil)
+			},
+		},
+		"unsupported version": {
+			schema:    litetable.Schema{Version: litetable.SchemaVersion + 1},
+			mockSetup: func(*MocktableCatalog, *MockshardManager) {},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"duplicate table": {
+			schema: litetable.Schema{
+				Version: litetable.SchemaVersion,
+				Tables:  []litetable.TableSchema{{Name: "wwe"}, {Name: "wwe"}},
+			},
+			mockSetup: func(c *MocktableCatalog, _ *MockshardManager) {
+				c.EXPECT().ListTables().Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid table name creates nothing": {
+			schema: litetable.Schema{
+				Version: litetable.SchemaVersion,
+				Tables: []litetable.TableSchema{
+					{Name: "wwe", Families: []string{"wrestlers"}},
+					{Name: "../aew"},
+				},
+			},
+			mockSetup: func(c *MocktableCatalog, _ *MockshardManager) {
+				c.EXPECT().ListTables().Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			ctrl := gomock.NewController(t)
+			catalog := NewMocktableCatalog(ctrl)
+			defaultTable := NewMockshardManager(ctrl)
+			tc.mockSetup(catalog, defaultTable)
+
+			m := &Manager{shardStorage: defaultTable, tables: catalog}
+			created, err := m.ImportSchema(tc.schema, tc.dryRun)
+			if tc.expectErr != nil {
+				req.ErrorIs(err, tc.expectErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.expected, created)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package operations
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+)
+
+var tableOperations = metrics.NewCounterVec("litetable_table_operations_total",
+	"Operations run against each table.", "table", "operation")
+
+// storage returns the storage of a table and counts the operation against it. An empty name is
+// the default table.
+func (m *Manager) storage(table, operation string) (shardManager, error) {
+	if table == "" || table == litetable.DefaultTable {
+		tableOperations.With(litetable.DefaultTable, operation).Inc()
+		return m.shardStorage, nil
+	}
+	if m.tables == nil {
+		return nil, litetable.NewError(litetable.ErrorCodeNotFound, "table %s does not exist",
+			table)
+	}
+
+	storage, err := m.tables.Table(table)
+	if err != nil {
+		return nil, err
+	}
+	tableOperations.With(table, operation).Inc()
+	return storage, nil
+}
+
+// CreateTable creates a table with its column families.
+func (m *Manager) CreateTable(name string, families []string) error {
+	if m.tables == nil {
+		return litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"tables are not supported by this server")
+	}
+	if err := m.checkFamilies(families); err != nil {
+		return err
+	}
+	return m.tables.CreateTable(name, families)
+}
+
+// DropTable deletes a table and all of its data.
+func (m *Manager) DropTable(name string) error {
+	if m.tables == nil {
+		return litetable.NewError(litetable.ErrorCodeNotFound, "table %s does not exist", name)
+	}
+	return m.tables.DropTable(name)
+}
+
+// ListTables returns the default table followed by every other table, sorted.
+func (m *Manager) ListTables() []string {
+	tables := []string{litetable.DefaultTable}
+	if m.tables != nil {
+		tables = append(tables, m.tables.ListTables()...)
+	}
+	return tables
+}
+
+// TableStats returns the space a table and its column families take, with their quotas.
+func (m *Manager) TableStats(table string) (litetable.TableUsage, error) {
+	storage, err := m.storage(table, "stats")
+	if err != nil {
+		return litetable.TableUsage{}, err
+	}
+	return storage.Usage(), nil
+}
+
+// ListTombstones returns up to limit tombstones of a table that have not expired, on rows
+// starting with prefix, and whether there were more.
+func (m *Manager) ListTombstones(table, prefix string, limit int) ([]litetable.Tombstone, bool,
+	error) {
+	storage, err := m.storage(table, "tombstones")
+	if err != nil {
+		return nil, false, err
+	}
+	tombstones, more := storage.Tombstones(prefix, limit)
+	return tombstones, more, nil
+}
+
+// Sequence returns the sequence number of the latest mutation to a table. Reads made with it as
+// readAt see the table as it is now, however many writes follow.
+func (m *Manager) Sequence(table string) (uint64, error) {
+	storage, err := m.storage(table, "sequence")
+	if err != nil {
+		return 0, err
+	}
+	return storage.Sequence(), nil
+}
@@ -0,0 +1,160 @@
+package operations
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// tableEngine is the storage engine of a table in tests.
+type tableEngine struct {
+	*MockshardManager
+}
+
+func (tableEngine) Start() error { return nil }
+func (tableEngine) Stop() error  { return nil }
+func (tableEngine) Name() string { return "table" }
+
+func (tableEngine) LoadReport() litetable.LoadReport { return litetable.LoadReport{} }
+
+func TestManager_tables(t *testing.T) {
+	tests := map[string]struct {
+		query     string
+		mockSetup func(c *MocktableCatalog, defaultTable, table *MockshardManager)
+		expectErr error
+	}{
+		"no table is the default table": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John",
+			mockSetup: func(_ *MocktableCatalog, defaultTable, _ *MockshardManager) {
+				defaultTable.EXPECT().Apply("champ:1", "wrestlers", []string{"name"},
+					gomock.Any(), gomock.Any(), int64(0)).Return(nil)
+			},
+		},
+		"default table by name": {
+			query: "table=default key=champ:1 family=wrestlers qualifier=name value=John",
+			mockSetup: func(_ *MocktableCatalog, defaultTable, _ *MockshardManager) {
+				defaultTable.EXPECT().Apply("champ:1", "wrestlers", []string{"name"},
+					gomock.Any(), gomock.Any(), int64(0)).Return(nil)
+			},
+		},
+		"named table": {
+			query: "table=wwe key=champ:1 family=wrestlers qualifier=name value=John",
+			mockSetup: func(c *MocktableCatalog, _, table *MockshardManager) {
+				c.EXPECT().Table("wwe").Return(tableEngine{table}, nil)
+				table.EXPECT().Apply("champ:1", "wrestlers", []string{"name"},
+					gomock.Any(), gomock.Any(), int64(0)).Return(nil)
+			},
+		},
+		"unknown table": {
+			query: "table=aew key=champ:1 family=wrestlers qualifier=name value=John",
+			mockSetup: func(c *MocktableCatalog, _, _ *MockshardManager) {
+				c.EXPECT().Table("aew").Return(nil,
+					litetable.NewError(litetable.ErrorCodeNotFound, "table aew does not exist"))
+			},
+			expectErr: litetable.ErrNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			ctrl := gomock.NewController(t)
+
+			w := NewMockwriteAhead(ctrl)
+			w.EXPECT().Apply(gomock.Any()).Return(nil)
+			catalog := NewMocktableCatalog(ctrl)
+			defaultTable := NewMockshardManager(ctrl)
+			expectExpiresAt(defaultTable)
+			table := NewMockshardManager(ctrl)
+			expectExpiresAt(table)
+			tc.mockSetup(catalog, defaultTable, table)
+
+			m := &Manager{writeAhead: w, shardStorage: defaultTable, tables: catalog}
+			_, err := m.Write(tc.query)
+			if tc.expectErr != nil {
+				req.ErrorIs(err, tc.expectErr)
+				return
+			}
+			req.NoError(err)
+		})
+	}
+}
+
+func TestManager_tablesWithoutCatalog(t *testing.T) {
+	req := require.New(t)
+	m := &Manager{shardStorage: NewMockshardManager(gomock.NewController(t))}
+
+	_, _, err := m.Read("table=wwe key=champ:1 family=wrestlers")
+	req.ErrorIs(err, litetable.ErrNotFound)
+	req.ErrorIs(m.CreateTable("wwe", nil), litetable.ErrInvalidArgument)
+	req.ErrorIs(m.DropTable("wwe"), litetable.ErrNotFound)
+	req.Equal([]string{litetable.DefaultTable}, m.ListTables())
+	_, err = m.TableStats("wwe")
+	req.ErrorIs(err, litetable.ErrNotFound)
+}
+
+func TestManager_TableStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := NewMockshardManager(ctrl)
+	want := litetable.TableUsage{Usage: litetable.Usage{Bytes: 4, Rows: 1}}
+	s.EXPECT().Usage().Return(want)
+
+	m := &Manager{shardStorage: s}
+	got, err := m.TableStats("")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestManager_ListTombstones(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := NewMockshardManager(ctrl)
+	want := []litetable.Tombstone{{RowKey: "champ:1", Family: "main", Qualifier: "name"}}
+	s.EXPECT().Tombstones("champ", 10).Return(want, true)
+
+	m := &Manager{shardStorage: s}
+	got, more, err := m.ListTombstones("", "champ", 10)
+	require.NoError(t, err)
+	require.True(t, more)
+	require.Equal(t, want, got)
+
+	_, _, err = m.ListTombstones("wwe", "champ", 10)
+	require.ErrorIs(t, err, litetable.ErrNotFound)
+}
+
+func TestManager_Sequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := NewMockshardManager(ctrl)
+	s.EXPECT().Sequence().Return(uint64(42))
+
+	m := &Manager{shardStorage: s}
+	got, err := m.Sequence("")
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), got)
+
+	_, err = m.Sequence("wwe")
+	require.ErrorIs(t, err, litetable.ErrNotFound)
+}
+
+func TestManager_ListTables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	catalog := NewMocktableCatalog(ctrl)
+	catalog.EXPECT().ListTables().Return([]string{"aew", "wwe"})
+
+	m := &Manager{tables: catalog}
+	require.Equal(t, []string{litetable.DefaultTable, "aew", "wwe"}, m.ListTables())
+}
+
+func TestManager_Flush_tables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w := NewMockwriteAhead(ctrl)
+	w.EXPECT().Sync().Return(nil)
+	s := NewMockshardManager(ctrl)
+	s.EXPECT().Flush().Return(nil)
+	catalog := NewMocktableCatalog(ctrl)
+	catalog.EXPECT().Flush().Return(errors.New("disk full"))
+
+	m := &Manager{writeAhead: w, shardStorage: s, tables: catalog}
+	require.ErrorIs(t, m.Flush(), &litetable.Error{Code: litetable.ErrorCodeInternal})
+}
@@ -0,0 +1,163 @@
+package operations
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	wal2 "github.com/litetable/litetable-db/internal/shard_storage/wal"
+	"time"
+)
+
+var transactions = metrics.NewCounterVec("litetable_transactions_total",
+	"Transactions by result: committed, conflict or failed.", "result")
+
+// Commit runs the writes and deletes of a transaction at once. Clients begin a transaction by
+// taking the sequence number of the table, read with it as readAt, and commit their changes with
+// the rows they read. The commit fails with a conflict if any of those rows, or a row it
+// changes, was changed after readAt, and returns the sequence number and timestamp the
+// mutations of the transaction share.
+func (m *Manager) Commit(table string, readAt uint64, reads []string,
+	queries []litetable.MutationQuery) (litetable.CommitResult, error) {
+	result, err := m.commit(table, readAt, reads, queries)
+	switch {
+	case err == nil:
+		transactions.With("committed").Inc()
+	case errors.Is(err, litetable.ErrConflict):
+		transactions.With("conflict").Inc()
+	default:
+		transactions.With("failed").Inc()
+	}
+	return result, err
+}
+
+func (m *Manager) commit(table string, readAt uint64, reads []string,
+	queries []litetable.MutationQuery) (litetable.CommitResult, error) {
+	if len(queries) == 0 {
+		return litetable.CommitResult{}, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"transaction has no mutations")
+	}
+	if err := m.checkDisk(); err != nil {
+		return litetable.CommitResult{}, err
+	}
+	if err := m.admit(); err != nil {
+		return litetable.CommitResult{}, err
+	}
+
+	storage, err := m.storage(table, "commit")
+	if err != nil {
+		return litetable.CommitResult{}, err
+	}
+
+	// every mutation of the transaction is written at the same time
+	result := litetable.CommitResult{Timestamp: m.clock.now()}
+	mutations := make([]litetable.Mutation, 0, len(queries))
+	sync := false
+	for _, q := range queries {
+		parsed, err := parseMutation(q, result.Timestamp)
+		if err != nil {
+			return litetable.CommitResult{}, err
+		}
+		if parsed.table != "" && tableName(parsed.table) != tableName(table) {
+			return litetable.CommitResult{}, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+				"mutation of row %s is for table %s, not the table of the transaction",
+				parsed.rowKey, parsed.table)
+		}
+		if err = m.checkNames(parsed.rowKey, parsed.writtenFamilies()...); err != nil {
+			return litetable.CommitResult{}, err
+		}
+		if err = m.checkFence(table, parsed.rowKey, parsed.fence); err != nil {
+			return litetable.CommitResult{}, err
+		}
+		m.clock.observe(parsed.timestamp)
+		sync = sync || parsed.sync == syncBackup
+		mutations = append(mutations, parsed.mutations...)
+	}
+
+	// the transaction is logged once storage has accepted it: conflicts are an expected outcome
+	// of optimistic transactions, and the WAL should not record mutations that never happened
+	logCommit := func() error {
+		for _, q := range queries {
+			if err := m.logWrite(&wal2.Entry{
+				Operation: q.Operation,
+				Query:     []byte(q.Query),
+				Timestamp: time.Now(),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if result.Sequence, err = storage.Commit(readAt, reads, mutations, logCommit); err != nil {
+		return litetable.CommitResult{}, err
+	}
+	countMutations(mutations...)
+	if sync {
+		if err = m.Flush(); err != nil {
+			return litetable.CommitResult{}, err
+		}
+	}
+	return result, nil
+}
+
+// mutationQuery is a parsed write or delete of a transaction, with the options of its query. A
+// write to several families is one mutation per family.
+type mutationQuery struct {
+	mutations []litetable.Mutation
+	rowKey    string
+	timestamp int64
+	table     string
+	fence     uint64
+	sync      string
+}
+
+// writtenFamilies returns the families the mutation writes to. Deletes write to none.
+func (q *mutationQuery) writtenFamilies() []string {
+	var families []string
+	for _, mutation := range q.mutations {
+		if mutation.Operation == litetable.OperationWrite {
+			families = append(families, mutation.Family)
+		}
+	}
+	return families
+}
+
+// parseMutation parses a write or delete of a transaction made at now.
+func parseMutation(q litetable.MutationQuery, now int64) (*mutationQuery, error) {
+	switch q.Operation {
+	case litetable.OperationWrite:
+		parsed, err := parseWriteQuery(q.Query, now)
+		if err != nil {
+			return nil, err
+		}
+		return &mutationQuery{
+			mutations: parsed.mutations(),
+			rowKey:    parsed.rowKey,
+			timestamp: parsed.timestamp,
+			table:     parsed.table,
+			fence:     parsed.fence,
+			sync:      parsed.sync,
+		}, nil
+	case litetable.OperationDelete:
+		parsed, err := parseDeleteQuery(q.Query, now)
+		if err != nil {
+			return nil, err
+		}
+		return &mutationQuery{
+			mutations: []litetable.Mutation{{
+				Operation:  q.Operation,
+				RowKey:     parsed.rowKey,
+				Family:     parsed.family,
+				Qualifiers: parsed.qualifiers,
+				Timestamp:  parsed.timestamp,
+				ExpiresAt:  parsed.expiresAt,
+			}},
+			rowKey:    parsed.rowKey,
+			timestamp: parsed.timestamp,
+			table:     parsed.table,
+			fence:     parsed.fence,
+		}, nil
+	default:
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"transactions only write and delete, got %s", q.Operation)
+	}
+}
@@ -0,0 +1,145 @@
+package operations
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	wal2 "github.com/litetable/litetable-db/internal/shard_storage/wal"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestManager_Commit(t *testing.T) {
+	errDiskFull := errors.New("disk full")
+	write := litetable.MutationQuery{
+		Operation: litetable.OperationWrite,
+		Query:     "key=champ:1 family=main qualifier=name value=Ahri",
+	}
+	remove := litetable.MutationQuery{
+		Operation: litetable.OperationDelete,
+		Query:     "key=champ:2 family=main",
+	}
+
+	tests := map[string]struct {
+		table     string
+		queries   []litetable.MutationQuery
+		mockSetup func(s *MockshardManager)
+		walErr    error
+		expectErr error
+	}{
+		"committed": {
+			queries: []litetable.MutationQuery{write, remove},
+			mockSetup: func(s *MockshardManager) {
+				commit := s.EXPECT().Commit(uint64(7), []string{"champ:3"}, gomock.Any(),
+					gomock.Any())
+				commit.DoAndReturn(func(_ uint64, _ []string, mutations []litetable.Mutation,
+					accepted func() error) (uint64, error) {
+					require.Len(t, mutations, 2)
+					require.Equal(t, "champ:1", mutations[0].RowKey)
+					require.Equal(t, [][]byte{[]byte("Ahri")}, mutations[0].Values)
+					require.Equal(t, litetable.OperationDelete, mutations[1].Operation)
+					require.Equal(t, "champ:2", mutations[1].RowKey)
+					// the mutations of a transaction share one timestamp
+					require.Equal(t, mutations[0].Timestamp, mutations[1].Timestamp)
+					return 9, accepted()
+				})
+			},
+		},
+		"conflict": {
+			queries: []litetable.MutationQuery{write},
+			mockSetup: func(s *MockshardManager) {
+				// the storage rejects the transaction before accepting it
+				s.EXPECT().Commit(uint64(7), []string{"champ:3"}, gomock.Any(),
+					gomock.Any()).Return(uint64(0),
+					litetable.NewError(litetable.ErrorCodeConflict, "row champ:1 changed"))
+			},
+			expectErr: litetable.ErrConflict,
+		},
+		"WAL failure": {
+			queries: []litetable.MutationQuery{write},
+			walErr:  errDiskFull,
+			mockSetup: func(s *MockshardManager) {
+				commit := s.EXPECT().Commit(uint64(7), []string{"champ:3"}, gomock.Any(),
+					gomock.Any())
+				commit.DoAndReturn(func(_ uint64, _ []string, _ []litetable.Mutation,
+					accepted func() error) (uint64, error) {
+					return 0, accepted()
+				})
+			},
+			expectErr: errDiskFull,
+		},
+		"no mutations": {
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid mutation": {
+			queries: []litetable.MutationQuery{{
+				Operation: litetable.OperationWrite,
+				Query:     "key=champ:1 family=main",
+			}},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"read is not a mutation": {
+			queries: []litetable.MutationQuery{{
+				Operation: litetable.OperationRead,
+				Query:     "key=champ:1 family=main",
+			}},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"mutation of another table": {
+			queries: []litetable.MutationQuery{{
+				Operation: litetable.OperationWrite,
+				Query:     "table=wwe key=champ:1 family=main qualifier=name value=Ahri",
+			}},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"mutation naming the table of the transaction": {
+			table: litetable.DefaultTable,
+			queries: []litetable.MutationQuery{{
+				Operation: litetable.OperationWrite,
+				Query:     "table=default key=champ:1 family=main qualifier=name value=Ahri",
+			}},
+			mockSetup: func(s *MockshardManager) {
+				s.EXPECT().Commit(uint64(7), []string{"champ:3"}, gomock.Any(),
+					gomock.Any()).Return(uint64(9), nil)
+			},
+		},
+		"fenced mutation without the lease": {
+			queries: []litetable.MutationQuery{{
+				Operation: litetable.OperationDelete,
+				Query:     "key=champ:2 family=main fence=3",
+			}},
+			expectErr: litetable.ErrConflict,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			ctrl := gomock.NewController(t)
+			w := NewMockwriteAhead(ctrl)
+			// the WAL is only written once the storage accepts the transaction
+			logged := 0
+			w.EXPECT().Apply(gomock.Any()).DoAndReturn(func(*wal2.Entry) error {
+				logged++
+				return tc.walErr
+			}).AnyTimes()
+			s := NewMockshardManager(ctrl)
+			if tc.mockSetup != nil {
+				tc.mockSetup(s)
+			}
+
+			m := &Manager{writeAhead: w, shardStorage: s, locks: newRowLocks()}
+			result, err := m.Commit(tc.table, 7, []string{"champ:3"}, tc.queries)
+			if tc.expectErr != nil {
+				req.ErrorIs(err, tc.expectErr)
+				if tc.walErr == nil {
+					req.Zero(logged)
+				}
+				return
+			}
+			req.NoError(err)
+			req.Equal(uint64(9), result.Sequence)
+			req.NotZero(result.Timestamp)
+		})
+	}
+}
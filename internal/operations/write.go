@@ -1,42 +1,100 @@
 package operations
 
 import (
-	"fmt"
 	"github.com/litetable/litetable-db/internal/litetable"
 	wal2 "github.com/litetable/litetable-db/internal/shard_storage/wal"
-	"net/url"
+	"math"
 	"strconv"
 	"strings"
 	"time"
 )
 
+const (
+	// syncMemory acknowledges a write once it is in memory. This is the default.
+	syncMemory = "memory"
+	// syncBackup acknowledges a write once it is in an fsynced backup.
+	syncBackup = "backup"
+)
+
+// Write runs a write query. A write with an idempotencyKey is applied once: retries with the same
+// key and query get the rows of the first write back, with the same timestamps.
 func (m *Manager) Write(query string) (map[string]*litetable.Row, error) {
-	if err := m.writeAhead.Apply(&wal2.Entry{
+	key, request := idempotencyKey(query)
+	if key == "" {
+		return m.write(query)
+	}
+
+	result, replayed, err := m.idempotency.do(key, request, func() (any, error) {
+		return m.write(request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if replayed {
+		idempotentReplays.With("write").Inc()
+	}
+	return result.(map[string]*litetable.Row), nil
+}
+
+func (m *Manager) write(query string) (map[string]*litetable.Row, error) {
+	if err := m.checkDisk(); err != nil {
+		return nil, err
+	}
+	if err := m.admit(); err != nil {
+		return nil, err
+	}
+
+	entry := &wal2.Entry{
 		Operation: litetable.OperationWrite,
 		Query:     []byte(query),
 		Timestamp: time.Now(),
-	}); err != nil {
-		return nil, err
+	}
+	// coalesced writes are logged with their group once they are parsed
+	if m.coalescer == nil {
+		if err := m.logWrite(entry); err != nil {
+			return nil, err
+		}
 	}
 
 	// Parse the query
-	parsed, err := parseWriteQuery(query)
+	parsed, err := parseWriteQuery(query, m.clock.now())
 	if err != nil {
 		return nil, err
 	}
+	m.clock.observe(parsed.timestamp)
+	if err = m.checkNames(parsed.rowKey, parsed.families()...); err != nil {
+		return nil, err
+	}
 
-	// Use the shard_storage Apply method to write data
-	err = m.shardStorage.Apply(
-		parsed.rowKey,
-		parsed.family,
-		parsed.qualifiers,
-		parsed.values,
-		parsed.timestamp,
-		parsed.expiresAt,
-	)
+	storage, err := m.storage(parsed.table, "write")
 	if err != nil {
 		return nil, err
 	}
+	if err = m.checkFence(parsed.table, parsed.rowKey, parsed.fence); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case m.coalescer != nil && len(parsed.cells) == 1:
+		err = m.coalescer.apply(m.writeAhead, storage, entry, parsed.mutations()[0])
+	case m.coalescer != nil:
+		// writes to several families are not coalesced, since they are applied together
+		if err = m.logWrite(entry); err == nil {
+			err = applyCells(storage, parsed)
+		}
+	default:
+		err = applyCells(storage, parsed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	countMutations(parsed.mutations()...)
+
+	if parsed.sync == syncBackup {
+		if err = m.Flush(); err != nil {
+			return nil, err
+		}
+	}
 
 	// The data has been saved, now let's just return what's written
 	// Create response with all written values
@@ -44,18 +102,20 @@ func (m *Manager) Write(query string) (map[string]*litetable.Row, error) {
 		Key:     parsed.rowKey,
 		Columns: make(map[string]litetable.VersionedQualifier),
 	}
-	row.Columns[parsed.family] = make(litetable.VersionedQualifier)
+	for _, cells := range parsed.cells {
+		row.Columns[cells.family] = make(litetable.VersionedQualifier)
 
-	for i, qualifier := range parsed.qualifiers {
-		timestampedValue := litetable.TimestampedValue{
-			Value:     parsed.values[i],
-			Timestamp: parsed.timestamp,
-		}
-
-		// Store result
-		values := []litetable.TimestampedValue{timestampedValue}
-		row.Columns[parsed.family][qualifier] = values
+		for i, qualifier := range cells.qualifiers {
+			timestampedValue := litetable.TimestampedValue{
+				Value:     cells.values[i],
+				Timestamp: parsed.timestamp,
+				ExpiresAt: storage.ExpiresAt(cells.family, parsed.timestamp, parsed.expiresAt),
+			}
 
+			// Store result
+			values := []litetable.TimestampedValue{timestampedValue}
+			row.Columns[cells.family][qualifier] = values
+		}
 	}
 
 	result := map[string]*litetable.Row{
@@ -65,85 +125,258 @@ func (m *Manager) Write(query string) (map[string]*litetable.Row, error) {
 	return result, nil
 }
 
+// checkDisk refuses a write while the disk is low on space.
+func (m *Manager) checkDisk() error {
+	if m.disk == nil {
+		return nil
+	}
+	return m.disk.CheckWrite()
+}
+
+// logWrite appends a write to the WAL.
+func (m *Manager) logWrite(entry *wal2.Entry) error {
+	if err := m.writeAhead.Apply(entry); err != nil {
+		return litetable.WrapError(litetable.ErrorCodeInternal, err, "failed to write to WAL")
+	}
+	return nil
+}
+
+// applyCells writes the cells of a parsed write to storage.
+func applyCells(storage shardManager, parsed *writeQuery) error {
+	if len(parsed.cells) == 1 {
+		// Use the shard_storage Apply method to write data
+		cells := parsed.cells[0]
+		return storage.Apply(
+			parsed.rowKey,
+			cells.family,
+			cells.qualifiers,
+			cells.values,
+			parsed.timestamp,
+			parsed.expiresAt,
+		)
+	}
+	// the families are written as one transaction that cannot conflict, so they are applied
+	// together under the shard lock
+	_, err := storage.Commit(math.MaxUint64, nil, parsed.mutations(), nil)
+	return err
+}
+
 // writeQuery are the possible values to be passed in the query that manipulate the write
 // behavior to the table.
 //
-// Note: ttl is globally applied to all rows in the write.
+// Note: ttl is globally applied to all rows in the write. It is in seconds, while timestamp and
+// expiresAt are Unix nanoseconds. Without a ttl, the values inherit the default TTL of their
+// family, if it has one.
 type writeQuery struct {
-	rowKey     string
-	family     string
-	qualifiers []string
-	values     [][]byte
-	timestamp  int64
-	expiresAt  int64
-	// ttl is the time the row should no longer be relevant from the time written
+	table  string
+	rowKey string
+	// cells are the values written to each family. A family applies to the qualifiers that
+	// follow it, and the qualifiers before the first family belong to it.
+	cells     []familyCells
+	timestamp int64
+	expiresAt int64
+	// ttl is the number of seconds the values are readable for after they are written
 	ttl int64
+	// sync is the durability required before the write is acknowledged
+	sync string
+	// fence is the fencing token of the row lease the write is made under
+	fence uint64
+}
+
+// parseWriteQuery parses a write query string in the grammar version it names into a
+// structured form, with the values written at now unless the query sets a timestamp, which
+// cannot be far in the future.
+func parseWriteQuery(input string, now int64) (*writeQuery, error) {
+	version, query, err := queryVersion(input)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenizeQuery(version, query)
+	if err != nil {
+		return nil, err
+	}
+	return parseWriteTokens(tokens, now, grammars[version].decode)
 }
 
-// parseWriteQuery parses a write query string into a structured form
-func parseWriteQuery(input string) (*writeQuery, error) {
-	parts := strings.Fields(input)
+// parseWriteTokens parses the parameters of a write query, reading the values written with
+// decode.
+func parseWriteTokens(
+	tokens []queryToken, now int64, decode func(string) (string, error),
+) (*writeQuery, error) {
 	parsed := &writeQuery{
-		qualifiers: []string{},
-		values:     [][]byte{},
-		timestamp:  time.Now().UnixNano(),
-		expiresAt:  0,
-		ttl:        0,
+		timestamp: now,
+		expiresAt: 0,
+		ttl:       0,
+	}
+	// current is the family the qualifiers and values are written to
+	current := func() *familyCells {
+		if len(parsed.cells) == 0 {
+			parsed.cells = append(parsed.cells, familyCells{})
+		}
+		return &parsed.cells[len(parsed.cells)-1]
 	}
 
-	for _, part := range parts {
-		kv := strings.SplitN(part, "=", 2)
-		if len(kv) != 2 {
-			return nil, fmt.Errorf("invalid format: %s", part)
+	for _, token := range tokens {
+		if !token.ok {
+			return nil, newError(errInvalidFormat, "%s", token.raw)
 		}
 
-		key, value := kv[0], kv[1]
-		key = strings.TrimLeft(key, "-")
+		key, value := strings.TrimLeft(token.name, "-"), token.value
 
 		// Decode URL-encoded values
-		decodedValue, err := url.QueryUnescape(value)
+		decodedValue, err := decode(value)
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode value: %s", err)
+			return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+				"failed to decode value: %s", err)
 		}
 
 		switch key {
+		case "table":
+			parsed.table = decodedValue
 		case "key":
 			parsed.rowKey = decodedValue
 		case "family":
-			parsed.family = decodedValue
+			if cells := current(); cells.family == "" {
+				cells.family = decodedValue
+			} else {
+				parsed.cells = append(parsed.cells, familyCells{family: decodedValue})
+			}
 		case "qualifier":
-			parsed.qualifiers = append(parsed.qualifiers, decodedValue)
+			cells := current()
+			cells.qualifiers = append(cells.qualifiers, decodedValue)
 		case "value":
-			parsed.values = append(parsed.values, []byte(decodedValue))
+			cells := current()
+			cells.values = append(cells.values, []byte(decodedValue))
 		case "ttl":
 			ttlSec, err := strconv.ParseInt(value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid ttl value: %s", value)
+			if err != nil || ttlSec < 0 {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"invalid ttl value: %s", value)
+			}
+			if ttlSec > int64(litetable.MaxTTL/time.Second) {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"ttl %d is longer than %s", ttlSec, litetable.MaxTTL)
 			}
 			parsed.ttl = ttlSec
-			// expires at should be the write time + ttl
-			expiresAtTimestamp := parsed.timestamp + ttlSec
-			parsed.expiresAt = expiresAtTimestamp
+		case "sync":
+			if decodedValue != syncMemory && decodedValue != syncBackup {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"invalid sync value: %s", decodedValue)
+			}
+			parsed.sync = decodedValue
+		case "fence":
+			if parsed.fence, err = parseFence(value); err != nil {
+				return nil, err
+			}
+		case "timestamp":
+			timestamp, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || timestamp <= 0 {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"invalid timestamp value: %s", value)
+			}
+			if timestamp > now+int64(maxTimestampSkew) {
+				return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+					"timestamp %d is more than %s in the future", timestamp, maxTimestampSkew)
+			}
+			parsed.timestamp = timestamp
+		case "idempotencyKey":
+			// single writes take the key off before parsing, so this is a transaction write
+			return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+				"idempotency keys are only supported by single writes")
 		}
 	}
 
+	// expires at should be the write time + ttl
+	if parsed.ttl > 0 {
+		parsed.expiresAt = parsed.timestamp + parsed.ttl*int64(time.Second)
+	}
+
 	// Validation checks remain the same
 	if parsed.rowKey == "" {
-		return nil, fmt.Errorf("missing key")
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument, "missing key")
 	}
-	if parsed.family == "" {
-		return nil, fmt.Errorf("missing family")
+	if len(parsed.cells) == 0 {
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument, "missing family")
 	}
-	if len(parsed.qualifiers) == 0 {
-		return nil, fmt.Errorf("missing qualifier")
+	families := make(map[string]bool, len(parsed.cells))
+	for _, cells := range parsed.cells {
+		if err := cells.validate(); err != nil {
+			return nil, err
+		}
+		if families[cells.family] {
+			return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+				"family %s is written twice", cells.family)
+		}
+		families[cells.family] = true
 	}
-	if len(parsed.values) == 0 {
-		return nil, fmt.Errorf("missing value")
+
+	return parsed, nil
+}
+
+// familyCells are the qualifiers and values a write stores in one family.
+type familyCells struct {
+	family     string
+	qualifiers []string
+	values     [][]byte
+}
+
+func (c *familyCells) validate() error {
+	if c.family == "" {
+		return litetable.NewError(litetable.ErrorCodeInvalidArgument, "missing family")
+	}
+	if len(c.qualifiers) == 0 {
+		return litetable.NewError(litetable.ErrorCodeInvalidArgument, "missing qualifier")
+	}
+	if len(c.values) == 0 {
+		return litetable.NewError(litetable.ErrorCodeInvalidArgument, "missing value")
+	}
+	if len(c.qualifiers) != len(c.values) {
+		return litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"number of qualifiers (%d) doesn't match number of values (%d)",
+			len(c.qualifiers), len(c.values))
 	}
-	if len(parsed.qualifiers) != len(parsed.values) {
-		return nil, fmt.Errorf("number of qualifiers (%d) doesn't match number of values (%d)",
-			len(parsed.qualifiers), len(parsed.values))
+	return nil
+}
+
+// families returns the families the write stores values in.
+func (w *writeQuery) families() []string {
+	families := make([]string, 0, len(w.cells))
+	for _, cells := range w.cells {
+		families = append(families, cells.family)
 	}
+	return families
+}
 
-	return parsed, nil
+// mutations returns the write as one mutation per family.
+func (w *writeQuery) mutations() []litetable.Mutation {
+	mutations := make([]litetable.Mutation, 0, len(w.cells))
+	for _, cells := range w.cells {
+		mutations = append(mutations, litetable.Mutation{
+			Operation:  litetable.OperationWrite,
+			RowKey:     w.rowKey,
+			Family:     cells.family,
+			Qualifiers: cells.qualifiers,
+			Values:     cells.values,
+			Timestamp:  w.timestamp,
+			ExpiresAt:  w.expiresAt,
+		})
+	}
+	return mutations
+}
+
+// Flush blocks until every acknowledged write is synced to the WAL and merged into a backup.
+func (m *Manager) Flush() error {
+	if err := m.writeAhead.Sync(); err != nil {
+		return litetable.WrapError(litetable.ErrorCodeInternal, err, "failed to sync WAL")
+	}
+	if err := m.shardStorage.Flush(); err != nil {
+		return litetable.WrapError(litetable.ErrorCodeInternal, err, "failed to flush backup")
+	}
+	if m.tables != nil {
+		if err := m.tables.Flush(); err != nil {
+			return litetable.WrapError(litetable.ErrorCodeInternal, err,
+				"failed to flush tables")
+		}
+	}
+	return nil
 }
@@ -0,0 +1,264 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"math"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestManager_Write(t *testing.T) {
+	tests := map[string]struct {
+		query     string
+		mockSetup func(w *MockwriteAhead, s *MockshardManager)
+		expectErr error
+	}{
+		"memory sync does not flush": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John sync=memory",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(),
+					gomock.Any(), int64(0)).Return(nil)
+			},
+		},
+		"backup sync flushes after applying": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John sync=backup",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				gomock.InOrder(
+					s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(),
+						gomock.Any(), int64(0)).Return(nil),
+					w.EXPECT().Sync().Return(nil),
+					s.EXPECT().Flush().Return(nil),
+				)
+			},
+		},
+		"failed flush is an internal error": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John sync=backup",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(),
+					gomock.Any(), int64(0)).Return(nil)
+				w.EXPECT().Sync().Return(nil)
+				s.EXPECT().Flush().Return(errors.New("disk full"))
+			},
+			expectErr: &litetable.Error{Code: litetable.ErrorCodeInternal},
+		},
+		"ttl is in seconds": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John ttl=60",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(),
+					gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_, _ string, _ []string, _ [][]byte, timestamp,
+						expiresAt int64) error {
+						if expiresAt-timestamp != int64(60*time.Second) {
+							return errors.New("wrong expiry")
+						}
+						return nil
+					})
+			},
+		},
+		"several families are applied together": {
+			query: "family=wrestlers key=champ:1 qualifier=name value=John " +
+				"family=titles qualifier=wwe value=16 qualifier=us value=5",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Commit(uint64(math.MaxUint64), nil, gomock.Any(), nil).DoAndReturn(
+					func(_ uint64, _ []string, mutations []litetable.Mutation,
+						_ func() error) (uint64, error) {
+						if len(mutations) != 2 || mutations[0].Family != "wrestlers" ||
+							mutations[1].Family != "titles" ||
+							len(mutations[1].Qualifiers) != 2 ||
+							mutations[0].Timestamp != mutations[1].Timestamp {
+							return 0, errors.New("wrong mutations")
+						}
+						return 1, nil
+					})
+			},
+		},
+		"qualifiers before the family": {
+			query: "qualifier=name value=John key=champ:1 family=wrestlers",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(),
+					gomock.Any(), int64(0)).Return(nil)
+			},
+		},
+		"family written twice": {
+			query: "family=wrestlers key=champ:1 qualifier=name value=John " +
+				"family=wrestlers qualifier=nickname value=Champ",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"family without qualifiers": {
+			query: "family=wrestlers key=champ:1 qualifier=name value=John family=titles",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"explicit timestamp for a backfill": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John timestamp=1000 ttl=60",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(),
+					int64(1000), int64(1000)+int64(60*time.Second)).Return(nil)
+			},
+		},
+		"timestamp in the near future": {
+			query: fmt.Sprintf("key=champ:1 family=wrestlers qualifier=name value=John "+
+				"timestamp=%d", time.Now().Add(30*time.Second).UnixNano()),
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+				s.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(),
+					gomock.Any(), int64(0)).Return(nil)
+			},
+		},
+		"timestamp far in the future": {
+			query: fmt.Sprintf("key=champ:1 family=wrestlers qualifier=name value=John "+
+				"timestamp=%d", time.Now().Add(time.Hour).UnixNano()),
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid timestamp": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John timestamp=yesterday",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"negative ttl": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John ttl=-1",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"ttl past the maximum": {
+			query: fmt.Sprintf("key=champ:1 family=wrestlers qualifier=name value=John ttl=%d",
+				math.MaxInt64/int64(time.Second)),
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+		"invalid sync": {
+			query: "key=champ:1 family=wrestlers qualifier=name value=John sync=disk",
+			mockSetup: func(w *MockwriteAhead, s *MockshardManager) {
+				w.EXPECT().Apply(gomock.Any()).Return(nil)
+			},
+			expectErr: litetable.ErrInvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			ctrl := gomock.NewController(t)
+			writeAhead := NewMockwriteAhead(ctrl)
+			storage := NewMockshardManager(ctrl)
+			expectExpiresAt(storage)
+			tc.mockSetup(writeAhead, storage)
+
+			m := &Manager{writeAhead: writeAhead, shardStorage: storage}
+			got, err := m.Write(tc.query)
+			if tc.expectErr != nil {
+				req.Error(err)
+				req.True(errors.Is(err, tc.expectErr))
+				return
+			}
+
+			req.NoError(err)
+			req.Contains(got, "champ:1")
+		})
+	}
+}
+
+func TestManager_Write_defaultTTL(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	writeAhead := NewMockwriteAhead(ctrl)
+	writeAhead.EXPECT().Apply(gomock.Any()).Return(nil)
+	storage := NewMockshardManager(ctrl)
+	storage.EXPECT().Apply("champ:1", "wrestlers", []string{"name"}, gomock.Any(),
+		int64(1000), int64(0)).Return(nil)
+	storage.EXPECT().ExpiresAt("wrestlers", int64(1000), int64(0)).Return(int64(5000))
+
+	// the values written are returned with the expiry the family gives them
+	m := &Manager{writeAhead: writeAhead, shardStorage: storage}
+	got, err := m.Write("key=champ:1 family=wrestlers qualifier=name value=John timestamp=1000")
+	req.NoError(err)
+	req.Equal(int64(5000), got["champ:1"].Columns["wrestlers"]["name"][0].ExpiresAt)
+}
+
+// expectExpiresAt expects writes to ask their table when the values they return expire, and
+// returns the expiry the values were written with.
+func expectExpiresAt(m *MockshardManager) {
+	m.EXPECT().ExpiresAt(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _, expiresAt int64) int64 {
+			return expiresAt
+		}).AnyTimes()
+}
+
+func TestManager_Write_lowDisk(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	disk := NewMockdiskMonitor(ctrl)
+	disk.EXPECT().CheckWrite().Return(litetable.NewError(litetable.ErrorCodeExhausted,
+		"write refused")).Times(3)
+
+	// nothing reaches the WAL or the storage while the disk is low
+	m := &Manager{
+		writeAhead:   NewMockwriteAhead(ctrl),
+		shardStorage: NewMockshardManager(ctrl),
+		disk:         disk,
+	}
+	_, err := m.Write("key=champ:1 family=wrestlers qualifier=name value=John")
+	req.ErrorIs(err, litetable.ErrExhausted)
+
+	_, err = m.Commit("", 0, nil, []litetable.MutationQuery{{
+		Operation: litetable.OperationWrite,
+		Query:     "key=champ:1 family=wrestlers qualifier=name value=John",
+	}})
+	req.ErrorIs(err, litetable.ErrExhausted)
+
+	_, _, err = m.CloneFamily("", "wrestlers", "copy")
+	req.ErrorIs(err, litetable.ErrExhausted)
+}
+
+// FuzzParseWriteQuery locks in the write grammar: space separated name=value pairs with URL
+// encoded values, so any value, spaces, = and unicode included, is written back as it was sent.
+func FuzzParseWriteQuery(f *testing.F) {
+	f.Add("champ:1", "main", "name", "Ahri")
+	f.Fuzz(func(t *testing.T, key, family, qualifier, value string) {
+		req := require.New(t)
+		now := time.Now().UnixNano()
+		parsed, err := parseWriteQuery(fmt.Sprintf("key=%s family=%s qualifier=%s value=%s",
+			url.QueryEscape(key), url.QueryEscape(family), url.QueryEscape(qualifier),
+			url.QueryEscape(value)), now)
+
+		if key == "" || family == "" {
+			req.Error(err)
+			return
+		}
+		req.NoError(err)
+		req.Equal(key, parsed.rowKey)
+		req.Equal([]familyCells{{
+			family:     family,
+			qualifiers: []string{qualifier},
+			values:     [][]byte{[]byte(value)},
+		}}, parsed.cells)
+		req.Equal(now, parsed.timestamp)
+		req.Zero(parsed.expiresAt)
+	})
+}
@@ -0,0 +1,62 @@
+// Package requestid generates and propagates the correlation IDs attached to every request so
+// logs from each layer that served it can be tied together.
+package requestid
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"unicode"
+)
+
+const (
+	// Header is the HTTP header and gRPC metadata key carrying the request ID.
+	Header = "x-request-id"
+	// LogField is the log field the request ID is written to.
+	LogField = "request_id"
+
+	maxLength = 128
+)
+
+type ctxKey struct{}
+
+// New generates a request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Resolve returns the caller-supplied ID if it is usable, otherwise a new one.
+func Resolve(id string) string {
+	if id == "" || len(id) > maxLength {
+		return New()
+	}
+	for _, r := range id {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return New()
+		}
+	}
+	return id
+}
+
+// NewContext returns a context carrying the request ID and a logger that writes it on every
+// entry. Downstream code should log through zerolog.Ctx(ctx).
+func NewContext(ctx context.Context, id string) context.Context {
+	logger := log.With().Str(LogField, id).Logger()
+	return logger.WithContext(context.WithValue(ctx, ctxKey{}, id))
+}
+
+// FromContext returns the request ID stored in the context, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Logger returns the request scoped logger, falling back to the global logger for contexts that
+// did not come through a request.
+func Logger(ctx context.Context) *zerolog.Logger {
+	if l := zerolog.Ctx(ctx); l.GetLevel() != zerolog.Disabled {
+		return l
+	}
+	return &log.Logger
+}
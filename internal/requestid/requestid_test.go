@@ -0,0 +1,51 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := map[string]struct {
+		id   string
+		keep bool
+	}{
+		"empty":          {id: "", keep: false},
+		"caller id":      {id: "abc-123", keep: true},
+		"too long":       {id: strings.Repeat("a", maxLength+1), keep: false},
+		"control chars":  {id: "abc\n123", keep: false},
+		"non ascii":      {id: "abc✓", keep: false},
+		"printable text": {id: "trace/1:2", keep: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Resolve(tc.id)
+			require.NotEmpty(t, got)
+			require.Equal(t, tc.keep, got == tc.id)
+		})
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	req := require.New(t)
+
+	var buf bytes.Buffer
+	original := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = original }()
+
+	req.Empty(FromContext(context.Background()))
+	req.Equal(&log.Logger, Logger(context.Background()))
+
+	ctx := NewContext(context.Background(), "abc-123")
+	req.Equal("abc-123", FromContext(ctx))
+
+	Logger(ctx).Info().Msg("hello")
+	req.Contains(buf.String(), `"request_id":"abc-123"`)
+}
@@ -0,0 +1,340 @@
+// Package router is a gRPC proxy in front of a cluster of LiteTable servers. It spreads the rows
+// over the nodes, sends single row requests to the node owning the row and fans scans and schema
+// changes out to every node, so clients can talk to one endpoint.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/logging"
+	server "github.com/litetable/litetable-db/internal/server/grpc"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"maps"
+	"net"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Router implements the app.Dependency interface for the routing proxy.
+type Router struct {
+	proto.UnimplementedLitetableServiceServer
+
+	address  string
+	port     int
+	server   *grpc.Server
+	listener net.Listener
+	topology *topology
+	logger   zerolog.Logger
+}
+
+type Config struct {
+	Address string
+	Port    int
+	// Nodes are the addresses of the LiteTable servers, host:port of their gRPC server
+	Nodes []string
+}
+
+func (c *Config) validate() error {
+	var errGrp []error
+	if c.Address == "" {
+		errGrp = append(errGrp, fmt.Errorf("address required"))
+	}
+	if c.Port == 0 {
+		errGrp = append(errGrp, fmt.Errorf("port required"))
+	}
+	if len(c.Nodes) == 0 {
+		errGrp = append(errGrp, fmt.Errorf("nodes required"))
+	}
+	return errors.Join(errGrp...)
+}
+
+// New creates a router for the nodes of cfg.
+func New(cfg *Config) (*Router, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	t, err := newTopology(cfg.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Address, cfg.Port))
+	if err != nil {
+		_ = t.close()
+		return nil, fmt.Errorf("failed to create listener on port %d: %w", cfg.Port, err)
+	}
+
+	r := &Router{
+		address:  cfg.Address,
+		port:     cfg.Port,
+		listener: lis,
+		topology: t,
+		logger:   logging.For("router"),
+	}
+	r.server = grpc.NewServer()
+	r.server.RegisterService(&proto.LitetableService_ServiceDesc, r)
+	return r, nil
+}
+
+func (r *Router) Start() error {
+	r.logger.Info().Int("nodes", len(r.topology.nodes)).
+		Msgf("router listening at %s:%d", r.address, r.port)
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := r.server.Serve(r.listener); err != nil {
+			r.logger.Error().Err(err).Msg("router failed")
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	// Block briefly for error or nil return
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(500 * time.Millisecond):
+		return nil
+	}
+}
+
+func (r *Router) Stop() error {
+	r.server.GracefulStop()
+	return r.topology.close()
+}
+
+func (r *Router) Name() string {
+	return "Router"
+}
+
+// Read sends a read of one row to its owner, and a prefix or regex read to every node, merging
+// the rows they own. When nodes truncate their rows, the merged rows are cut at the earliest row
+// key a node stopped at, since the rows after it may be missing from that node's page.
+func (r *Router) Read(ctx context.Context, msg *proto.ReadRequest) (*proto.LitetableData, error) {
+	if msg.GetQueryType() == proto.QueryType_EXACT {
+		return r.topology.owner(msg.GetRowKey()).client.Read(ctx, msg)
+	}
+
+	merged := &proto.LitetableData{}
+	var mutex sync.Mutex
+	var cut string
+	err := r.fanOut(ctx, func(ctx context.Context, n *node) error {
+		data, err := n.client.Read(ctx, msg)
+		if err != nil {
+			return err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		if data.GetTruncated() {
+			last, err := server.DecodePageToken(data.GetNextPageToken())
+			if err != nil {
+				return fmt.Errorf("invalid page token from %s: %w", n.address, err)
+			}
+			if cut == "" || last < cut {
+				cut = last
+			}
+		}
+		r.merge(merged, n, data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(merged.Entries, func(i, j int) bool {
+		return merged.Entries[i].GetKey() < merged.Entries[j].GetKey()
+	})
+	if cut != "" {
+		truncate(merged, cut)
+	}
+	return merged, nil
+}
+
+// truncate drops the merged rows after a row key, and sets the page token reading them.
+func truncate(merged *proto.LitetableData, cut string) {
+	maps.DeleteFunc(merged.Rows, func(key string, _ *proto.Row) bool { return key > cut })
+	merged.Entries = slices.DeleteFunc(merged.Entries, func(entry *proto.RowEntry) bool {
+		return entry.GetKey() > cut
+	})
+	merged.Truncated = true
+	merged.NextPageToken = server.EncodePageToken(cut)
+}
+
+// merge adds the rows a node owns to a scan's result. Rows left on a node that no longer owns
+// them, after the topology changed, are stale and left out.
+func (r *Router) merge(merged *proto.LitetableData, n *node, data *proto.LitetableData) {
+	for key, row := range data.GetRows() {
+		if r.topology.owner(key) != n {
+			continue
+		}
+		if merged.Rows == nil {
+			merged.Rows = make(map[string]*proto.Row)
+		}
+		merged.Rows[key] = row
+	}
+	for _, entry := range data.GetEntries() {
+		if r.topology.owner(entry.GetKey()) == n {
+			merged.Entries = append(merged.Entries, entry)
+		}
+	}
+}
+
+func (r *Router) ListQualifiers(ctx context.Context,
+	msg *proto.ListQualifiersRequest) (*proto.ListQualifiersResponse, error) {
+	return r.topology.owner(msg.GetRowKey()).client.ListQualifiers(ctx, msg)
+}
+
+func (r *Router) Write(ctx context.Context, msg *proto.WriteRequest) (*proto.LitetableData,
+	error) {
+	return r.topology.owner(msg.GetRowKey()).client.Write(ctx, msg)
+}
+
+func (r *Router) Delete(ctx context.Context, msg *proto.DeleteRequest) (*proto.Empty, error) {
+	return r.topology.owner(msg.GetRowKey()).client.Delete(ctx, msg)
+}
+
+func (r *Router) LockRow(ctx context.Context, msg *proto.LockRowRequest) (*proto.LockRowResponse,
+	error) {
+	return r.topology.owner(msg.GetRowKey()).client.LockRow(ctx, msg)
+}
+
+func (r *Router) UnlockRow(ctx context.Context, msg *proto.UnlockRowRequest) (*proto.Empty,
+	error) {
+	return r.topology.owner(msg.GetRowKey()).client.UnlockRow(ctx, msg)
+}
+
+// CreateFamily creates the families on every node, since any of them may own a row using them.
+func (r *Router) CreateFamily(ctx context.Context, msg *proto.CreateFamilyRequest) (*proto.Empty,
+	error) {
+	return &proto.Empty{}, r.fanOut(ctx, func(ctx context.Context, n *node) error {
+		_, err := n.client.CreateFamily(ctx, msg)
+		return err
+	})
+}
+
+// CloneFamily clones the family on every node, each of which copies the rows it owns, and
+// returns the rows and cells copied by all of them.
+func (r *Router) CloneFamily(ctx context.Context,
+	msg *proto.CloneFamilyRequest) (*proto.CloneFamilyResponse, error) {
+	var rows, cells atomic.Uint64
+	err := r.fanOut(ctx, func(ctx context.Context, n *node) error {
+		resp, err := n.client.CloneFamily(ctx, msg)
+		if err != nil {
+			return err
+		}
+		rows.Add(resp.GetRows())
+		cells.Add(resp.GetCells())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.CloneFamilyResponse{Rows: rows.Load(), Cells: cells.Load()}, nil
+}
+
+func (r *Router) CreateTable(ctx context.Context, msg *proto.CreateTableRequest) (*proto.Empty,
+	error) {
+	return &proto.Empty{}, r.fanOut(ctx, func(ctx context.Context, n *node) error {
+		_, err := n.client.CreateTable(ctx, msg)
+		return err
+	})
+}
+
+func (r *Router) DropTable(ctx context.Context, msg *proto.DropTableRequest) (*proto.Empty,
+	error) {
+	return &proto.Empty{}, r.fanOut(ctx, func(ctx context.Context, n *node) error {
+		_, err := n.client.DropTable(ctx, msg)
+		return err
+	})
+}
+
+// ListTables returns the tables of the first node; every node has the same tables when they are
+// created through the router.
+func (r *Router) ListTables(ctx context.Context, msg *proto.Empty) (*proto.ListTablesResponse,
+	error) {
+	return r.topology.nodes[0].client.ListTables(ctx, msg)
+}
+
+// ExportSchema returns the schema of the first node; every node has the same schema when it is
+// changed through the router.
+func (r *Router) ExportSchema(ctx context.Context, msg *proto.Empty) (*proto.Schema, error) {
+	return r.topology.nodes[0].client.ExportSchema(ctx, msg)
+}
+
+// ImportSchema imports the schema on every node and returns what any of them created.
+func (r *Router) ImportSchema(ctx context.Context,
+	msg *proto.ImportSchemaRequest) (*proto.ImportSchemaResponse, error) {
+	var mutex sync.Mutex
+	created := make(map[string][]string)
+	err := r.fanOut(ctx, func(ctx context.Context, n *node) error {
+		resp, err := n.client.ImportSchema(ctx, msg)
+		if err != nil {
+			return err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, table := range resp.GetCreated() {
+			families := created[table.GetName()]
+			for _, family := range table.GetFamilies() {
+				if !slices.Contains(families, family) {
+					families = append(families, family)
+				}
+			}
+			created[table.GetName()] = families
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.ImportSchemaResponse{}
+	for _, name := range slices.Sorted(maps.Keys(created)) {
+		resp.Created = append(resp.Created, &proto.TableSchema{
+			Name:     name,
+			Families: created[name],
+		})
+	}
+	return resp, nil
+}
+
+func (r *Router) Flush(ctx context.Context, msg *proto.Empty) (*proto.Empty, error) {
+	return &proto.Empty{}, r.fanOut(ctx, func(ctx context.Context, n *node) error {
+		_, err := n.client.Flush(ctx, msg)
+		return err
+	})
+}
+
+// fanOut calls every node at once and returns the first error. The other calls are cancelled
+// once one fails.
+func (r *Router) fanOut(ctx context.Context, call func(ctx context.Context, n *node) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(r.topology.nodes))
+	for _, n := range r.topology.nodes {
+		go func() {
+			err := call(ctx, n)
+			if err != nil {
+				r.logger.Warn().Err(err).Str("node", n.address).Msg("node request failed")
+			}
+			errs <- err
+		}()
+	}
+
+	var first error
+	for range r.topology.nodes {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+			cancel()
+		}
+	}
+	return first
+}
@@ -0,0 +1,317 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	server "github.com/litetable/litetable-db/internal/server/grpc"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testNode is a LiteTable server keeping the rows written to it.
+type testNode struct {
+	proto.UnimplementedLitetableServiceServer
+	mutex    sync.Mutex
+	rows     map[string]*proto.Row
+	families []string
+	// pageSize truncates ordered scans to this many rows
+	pageSize int
+}
+
+func (n *testNode) Write(_ context.Context, msg *proto.WriteRequest) (*proto.LitetableData,
+	error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	row := &proto.Row{Key: msg.GetRowKey()}
+	n.rows[msg.GetRowKey()] = row
+	return &proto.LitetableData{Rows: map[string]*proto.Row{row.Key: row}}, nil
+}
+
+func (n *testNode) Read(_ context.Context, msg *proto.ReadRequest) (*proto.LitetableData,
+	error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	after, err := server.DecodePageToken(msg.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	data := &proto.LitetableData{}
+	for key, row := range n.rows {
+		if key != msg.GetRowKey() && (msg.GetQueryType() != proto.QueryType_PREFIX ||
+			!strings.HasPrefix(key, msg.GetRowKey())) || key <= after {
+			continue
+		}
+		if msg.GetOrdered() {
+			data.Entries = append(data.Entries, &proto.RowEntry{Key: key})
+			continue
+		}
+		if data.Rows == nil {
+			data.Rows = make(map[string]*proto.Row)
+		}
+		data.Rows[key] = row
+	}
+	sort.Slice(data.Entries, func(i, j int) bool {
+		return data.Entries[i].GetKey() < data.Entries[j].GetKey()
+	})
+	if n.pageSize > 0 && len(data.Entries) > n.pageSize {
+		data.Entries = data.Entries[:n.pageSize]
+		data.Truncated = true
+		data.NextPageToken = server.EncodePageToken(data.Entries[n.pageSize-1].GetKey())
+	}
+	return data, nil
+}
+
+func (n *testNode) CreateFamily(_ context.Context, msg *proto.CreateFamilyRequest) (*proto.Empty,
+	error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if len(msg.GetFamily()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "family required")
+	}
+	n.families = append(n.families, msg.GetFamily()...)
+	return &proto.Empty{}, nil
+}
+
+func (n *testNode) ImportSchema(_ context.Context,
+	msg *proto.ImportSchemaRequest) (*proto.ImportSchemaResponse, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	resp := &proto.ImportSchemaResponse{}
+	for _, table := range msg.GetSchema().GetTables() {
+		var created []string
+		for _, family := range table.GetFamilies() {
+			if !slices.Contains(n.families, family) {
+				n.families = append(n.families, family)
+				created = append(created, family)
+			}
+		}
+		if len(created) > 0 {
+			resp.Created = append(resp.Created,
+				&proto.TableSchema{Name: table.GetName(), Families: created})
+		}
+	}
+	return resp, nil
+}
+
+// CloneFamily reports every row of the node as cloned, with two cells each.
+func (n *testNode) CloneFamily(_ context.Context,
+	msg *proto.CloneFamilyRequest) (*proto.CloneFamilyResponse, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.families = append(n.families, msg.GetTarget())
+	return &proto.CloneFamilyResponse{
+		Rows:  uint64(len(n.rows)),
+		Cells: 2 * uint64(len(n.rows)),
+	}, nil
+}
+
+// startNodes serves count test nodes and returns a router in front of them.
+func startNodes(t *testing.T, count int) (*Router, map[string]*testNode) {
+	nodes := make(map[string]*testNode, count)
+	var addresses []string
+	for range count {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		srv := grpc.NewServer()
+		n := &testNode{rows: make(map[string]*proto.Row)}
+		srv.RegisterService(&proto.LitetableService_ServiceDesc, n)
+		go func() { _ = srv.Serve(lis) }()
+		t.Cleanup(srv.Stop)
+
+		nodes[lis.Addr().String()] = n
+		addresses = append(addresses, lis.Addr().String())
+	}
+
+	topology, err := newTopology(addresses)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = topology.close() })
+	return &Router{topology: topology}, nodes
+}
+
+func TestRouter_routing(t *testing.T) {
+	req := require.New(t)
+	ctx := context.Background()
+	r, nodes := startNodes(t, 3)
+
+	for i := range 30 {
+		_, err := r.Write(ctx, &proto.WriteRequest{RowKey: fmt.Sprintf("champ:%02d", i)})
+		req.NoError(err)
+	}
+
+	// every row is written to its owner alone, and the rows are spread over every node
+	for address, n := range nodes {
+		req.NotEmpty(n.rows, address)
+		for key := range n.rows {
+			req.Equal(address, r.topology.owner(key).address)
+		}
+	}
+
+	data, err := r.Read(ctx, &proto.ReadRequest{RowKey: "champ:07"})
+	req.NoError(err)
+	req.Contains(data.GetRows(), "champ:07")
+
+	data, err = r.Read(ctx, &proto.ReadRequest{RowKey: "champ:1",
+		QueryType: proto.QueryType_PREFIX})
+	req.NoError(err)
+	req.Len(data.GetRows(), 10)
+
+	data, err = r.Read(ctx, &proto.ReadRequest{RowKey: "champ:", QueryType: proto.QueryType_PREFIX,
+		Ordered: true})
+	req.NoError(err)
+	req.Len(data.GetEntries(), 30)
+	for i, entry := range data.GetEntries() {
+		req.Equal(fmt.Sprintf("champ:%02d", i), entry.GetKey())
+	}
+}
+
+func TestRouter_staleRows(t *testing.T) {
+	req := require.New(t)
+	r, nodes := startNodes(t, 2)
+
+	// a row left on a node that does not own it is not returned by scans
+	for address, n := range nodes {
+		if r.topology.owner("champ:1").address != address {
+			n.rows["champ:1"] = &proto.Row{Key: "champ:1"}
+		}
+	}
+	data, err := r.Read(context.Background(), &proto.ReadRequest{RowKey: "champ:",
+		QueryType: proto.QueryType_PREFIX})
+	req.NoError(err)
+	req.Empty(data.GetRows())
+}
+
+func TestRouter_pages(t *testing.T) {
+	req := require.New(t)
+	r, nodes := startNodes(t, 3)
+	for _, n := range nodes {
+		n.pageSize = 2
+	}
+	for i := range 20 {
+		_, err := r.Write(context.Background(),
+			&proto.WriteRequest{RowKey: fmt.Sprintf("champ:%02d", i)})
+		req.NoError(err)
+	}
+
+	// paging through a scan returns every row once, in order, although the nodes stop at
+	// different rows
+	var keys []string
+	msg := &proto.ReadRequest{RowKey: "champ:", QueryType: proto.QueryType_PREFIX, Ordered: true}
+	for {
+		data, err := r.Read(context.Background(), msg)
+		req.NoError(err)
+		for _, entry := range data.GetEntries() {
+			keys = append(keys, entry.GetKey())
+		}
+		if !data.GetTruncated() {
+			break
+		}
+		msg.PageToken = data.GetNextPageToken()
+	}
+	req.Len(keys, 20)
+	for i, key := range keys {
+		req.Equal(fmt.Sprintf("champ:%02d", i), key)
+	}
+}
+
+func TestRouter_fanOut(t *testing.T) {
+	req := require.New(t)
+	r, nodes := startNodes(t, 3)
+
+	_, err := r.CreateFamily(context.Background(),
+		&proto.CreateFamilyRequest{Family: []string{"main"}})
+	req.NoError(err)
+	for _, n := range nodes {
+		req.Equal([]string{"main"}, n.families)
+	}
+
+	_, err = r.CreateFamily(context.Background(), &proto.CreateFamilyRequest{})
+	req.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestRouter_ImportSchema(t *testing.T) {
+	req := require.New(t)
+	r, nodes := startNodes(t, 3)
+	i := 0
+	for _, n := range nodes {
+		// only one node is missing the analytics family
+		n.families = []string{"main"}
+		if i == 0 {
+			n.families = append(n.families, "analytics")
+		}
+		i++
+	}
+
+	resp, err := r.ImportSchema(context.Background(), &proto.ImportSchemaRequest{
+		Schema: &proto.Schema{Version: 1, Tables: []*proto.TableSchema{
+			{Name: "default", Families: []string{"main", "analytics"}},
+		}},
+	})
+	req.NoError(err)
+	req.Len(resp.GetCreated(), 1)
+	req.Equal("default", resp.GetCreated()[0].GetName())
+	req.Equal([]string{"analytics"}, resp.GetCreated()[0].GetFamilies())
+	for _, n := range nodes {
+		req.ElementsMatch([]string{"main", "analytics"}, n.families)
+	}
+}
+
+func TestRouter_CloneFamily(t *testing.T) {
+	req := require.New(t)
+	r, nodes := startNodes(t, 3)
+	for i := range 10 {
+		_, err := r.Write(context.Background(), &proto.WriteRequest{
+			RowKey: fmt.Sprintf("champ:%d", i),
+			Family: "main",
+		})
+		req.NoError(err)
+	}
+
+	resp, err := r.CloneFamily(context.Background(), &proto.CloneFamilyRequest{
+		Source: "main",
+		Target: "main_copy",
+	})
+	req.NoError(err)
+	req.Equal(uint64(10), resp.GetRows())
+	req.Equal(uint64(20), resp.GetCells())
+	for _, n := range nodes {
+		req.Contains(n.families, "main_copy")
+	}
+}
+
+func TestTopology_owner(t *testing.T) {
+	req := require.New(t)
+	three, err := newTopology([]string{"node-a:9443", "node-b:9443", "node-c:9443"})
+	req.NoError(err)
+	defer func() { _ = three.close() }()
+	two, err := newTopology([]string{"node-a:9443", "node-b:9443"})
+	req.NoError(err)
+	defer func() { _ = two.close() }()
+
+	// removing a node only moves the rows it owned
+	moved := 0
+	for i := range 1000 {
+		key := fmt.Sprintf("champ:%d", i)
+		owner := three.owner(key).address
+		if owner == "node-c:9443" {
+			moved++
+			continue
+		}
+		req.Equal(owner, two.owner(key).address)
+	}
+	req.Greater(moved, 200)
+	req.Less(moved, 466)
+
+	_, err = newTopology([]string{"node-a:9443", "node-a:9443"})
+	req.EqualError(err, "node node-a:9443 is listed twice")
+	_, err = newTopology(nil)
+	req.Error(err)
+}
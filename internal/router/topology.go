@@ -0,0 +1,95 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"hash/fnv"
+)
+
+// node is a LiteTable server of the cluster.
+type node struct {
+	address string
+	conn    *grpc.ClientConn
+	client  proto.LitetableServiceClient
+}
+
+// topology is the set of nodes the rows are spread over. A row is owned by the node with the
+// highest hash of its address and the row key, so adding or removing a node only moves the rows
+// it gains or loses.
+type topology struct {
+	nodes []*node
+}
+
+// newTopology connects to every node. Connections are made lazily, so a node that is down
+// does not keep the router from starting.
+func newTopology(addresses []string) (*topology, error) {
+	if len(addresses) == 0 {
+		return nil, errors.New("at least one node is required")
+	}
+
+	t := &topology{}
+	seen := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		if _, ok := seen[address]; ok {
+			_ = t.close()
+			return nil, fmt.Errorf("node %s is listed twice", address)
+		}
+		seen[address] = struct{}{}
+
+		conn, err := grpc.NewClient(address,
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			_ = t.close()
+			return nil, fmt.Errorf("failed to create client for node %s: %w", address, err)
+		}
+		t.nodes = append(t.nodes, &node{
+			address: address,
+			conn:    conn,
+			client:  proto.NewLitetableServiceClient(conn),
+		})
+	}
+	return t, nil
+}
+
+// owner returns the node that owns a row.
+func (t *topology) owner(rowKey string) *node {
+	var owner *node
+	var highest uint64
+	for _, n := range t.nodes {
+		if score := ownership(n.address, rowKey); owner == nil || score > highest {
+			owner, highest = n, score
+		}
+	}
+	return owner
+}
+
+// ownership scores how strongly a node claims a row.
+func ownership(address, rowKey string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(address))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(rowKey))
+
+	// FNV barely changes the high bits for the last bytes written, so they are mixed in before
+	// the scores are compared
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func (t *topology) close() error {
+	var errGrp []error
+	for _, n := range t.nodes {
+		if err := n.conn.Close(); err != nil {
+			errGrp = append(errGrp, fmt.Errorf("failed to close node %s: %w", n.address, err))
+		}
+	}
+	return errors.Join(errGrp...)
+}
@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// aggregateNames are the query names of the aggregations.
+var aggregateNames = map[proto.Aggregation]string{
+	proto.Aggregation_COUNT:         "count",
+	proto.Aggregation_MIN_TIMESTAMP: "minTimestamp",
+	proto.Aggregation_MAX_TIMESTAMP: "maxTimestamp",
+	proto.Aggregation_SUM:           "sum",
+}
+
+// Aggregate computes aggregations over the cells a read would return, per qualifier.
+func (l *lt) Aggregate(ctx context.Context, msg *proto.AggregateRequest) (
+	*proto.AggregateResponse, error) {
+	if err := l.validateRead(msg.GetRead()); err != nil {
+		return nil, err
+	}
+	if len(msg.GetAggregations()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "aggregations required")
+	}
+
+	query := readQuery(msg.GetRead())
+	for _, aggregation := range msg.GetAggregations() {
+		name, ok := aggregateNames[aggregation]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown aggregation: %d",
+				aggregation)
+		}
+		query += " aggregate=" + name
+	}
+
+	aggregates, cost, err := l.operations.Aggregate(query)
+	addCost(ctx, cost)
+	if err != nil {
+		return nil, toStatus(err, "failed to aggregate data")
+	}
+	return &proto.AggregateResponse{Aggregates: toProtoAggregates(aggregates)}, nil
+}
+
+func toProtoAggregates(aggregates []*litetable.Aggregate) []*proto.QualifierAggregate {
+	result := make([]*proto.QualifierAggregate, 0, len(aggregates))
+	for _, agg := range aggregates {
+		result = append(result, &proto.QualifierAggregate{
+			Family:           agg.Family,
+			Qualifier:        agg.Qualifier,
+			Count:            agg.Count,
+			MinTimestampUnix: agg.MinTimestamp,
+			MaxTimestampUnix: agg.MaxTimestamp,
+			Sum:              agg.Sum,
+			NonNumeric:       agg.NonNumeric,
+		})
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestLt_Aggregate(t *testing.T) {
+	read := &proto.ReadRequest{
+		RowKey:    "match:",
+		QueryType: proto.QueryType_PREFIX,
+		Family:    "stats",
+		Table:     "wwe",
+	}
+
+	tests := map[string]struct {
+		request      *proto.AggregateRequest
+		mockSetup    func(m *Mockoperations)
+		expected     []*proto.QualifierAggregate
+		expectedCode codes.Code
+	}{
+		"missing read": {
+			request: &proto.AggregateRequest{
+				Aggregations: []proto.Aggregation{proto.Aggregation_COUNT},
+			},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"missing aggregations": {
+			request:      &proto.AggregateRequest{Read: read},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"unknown aggregation": {
+			request: &proto.AggregateRequest{
+				Read:         read,
+				Aggregations: []proto.Aggregation{proto.Aggregation(42)},
+			},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"missing family": {
+			request: &proto.AggregateRequest{
+				Read:         read,
+				Aggregations: []proto.Aggregation{proto.Aggregation_COUNT},
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().Aggregate(gomock.Any()).Return(nil, litetable.Cost{},
+					litetable.NewError(litetable.ErrorCodeFamilyMissing, "no stats"))
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		"aggregates": {
+			request: &proto.AggregateRequest{
+				Read: read,
+				Aggregations: []proto.Aggregation{
+					proto.Aggregation_COUNT,
+					proto.Aggregation_SUM,
+				},
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().Aggregate("v2:family=stats prefix=match: table=wwe aggregate=count "+
+					"aggregate=sum").Return([]*litetable.Aggregate{
+					{Family: "stats", Qualifier: "score", Count: 4, Sum: 17.5, NonNumeric: 1},
+				}, litetable.Cost{RowsScanned: 2, CellsCopied: 4}, nil)
+			},
+			expected: []*proto.QualifierAggregate{
+				{Family: "stats", Qualifier: "score", Count: 4, Sum: 17.5, NonNumeric: 1},
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.Aggregate(context.Background(), tc.request)
+			if tc.expectedCode != codes.OK {
+				req.Nil(resp)
+				req.Equal(tc.expectedCode, status.Code(err))
+				return
+			}
+			req.NoError(err)
+			req.Len(resp.GetAggregates(), len(tc.expected))
+			for i, agg := range tc.expected {
+				req.Equal(agg.String(), resp.GetAggregates()[i].String())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/pkg/proto"
+)
+
+// ListBackups returns the backup catalog, oldest first.
+func (l *lt) ListBackups(_ context.Context, _ *proto.Empty) (*proto.ListBackupsResponse, error) {
+	backups, err := l.operations.ListBackups()
+	if err != nil {
+		return nil, toStatus(err, "failed to list backups")
+	}
+
+	resp := &proto.ListBackupsResponse{
+		Backups: make([]*proto.BackupInfo, 0, len(backups)),
+	}
+	for _, b := range backups {
+		resp.Backups = append(resp.Backups, &proto.BackupInfo{
+			File:              b.File,
+			FormatVersion:     int32(b.FormatVersion),
+			CreatedAtUnix:     b.CreatedAt,
+			Rows:              int64(b.Rows),
+			Bytes:             b.Bytes,
+			Checksum:          b.Checksum,
+			FirstSnapshotUnix: b.FirstSnapshot,
+			LastSnapshotUnix:  b.LastSnapshot,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	litetable2 "github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestLt_ListBackups(t *testing.T) {
+	t.Run("catalog", func(t *testing.T) {
+		req := require.New(t)
+		mockOps := NewMockoperations(gomock.NewController(t))
+		mockOps.EXPECT().ListBackups().Return([]*litetable2.BackupManifest{
+			{
+				File:          "backup-1.db",
+				FormatVersion: 1,
+				CreatedAt:     10,
+				Rows:          2,
+				Bytes:         30,
+				Checksum:      "abc",
+				FirstSnapshot: 4,
+				LastSnapshot:  8,
+			},
+			{File: "backup-2.db", CreatedAt: 20},
+		}, nil)
+
+		svc := &lt{operations: mockOps}
+		resp, err := svc.ListBackups(context.Background(), &proto.Empty{})
+		req.NoError(err)
+		req.Len(resp.GetBackups(), 2)
+
+		first := resp.GetBackups()[0]
+		req.Equal("backup-1.db", first.GetFile())
+		req.Equal(int32(1), first.GetFormatVersion())
+		req.Equal(int64(10), first.GetCreatedAtUnix())
+		req.Equal(int64(2), first.GetRows())
+		req.Equal(int64(30), first.GetBytes())
+		req.Equal("abc", first.GetChecksum())
+		req.Equal(int64(4), first.GetFirstSnapshotUnix())
+		req.Equal(int64(8), first.GetLastSnapshotUnix())
+		req.Equal("backup-2.db", resp.GetBackups()[1].GetFile())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		req := require.New(t)
+		mockOps := NewMockoperations(gomock.NewController(t))
+		mockOps.EXPECT().ListBackups().Return(nil, errors.New("permission denied"))
+
+		svc := &lt{operations: mockOps}
+		resp, err := svc.ListBackups(context.Background(), &proto.Empty{})
+		req.Nil(resp)
+		st, ok := status.FromError(err)
+		req.True(ok)
+		req.Equal(codes.Internal, st.Code())
+	})
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"slices"
+)
+
+// The features listed by the Capabilities RPC. A name is never reused for another feature, so
+// clients can rely on its absence meaning the server lacks the feature.
+const (
+	// reads
+	featurePrefixScan   = "prefix_scan"
+	featureRegexScan    = "regex_scan"
+	featureReadAt       = "read_at"
+	featureAsOf         = "as_of_reads"
+	featureJSONPaths    = "json_paths"
+	featureOrderedReads = "ordered_reads"
+	featureAggregations = "aggregations"
+	featureQualifiers   = "list_qualifiers"
+	featureTombstones   = "list_tombstones"
+	featureEventual     = "eventual_reads"
+	// writes
+	featureMultiFamilyWrites = "multi_family_writes"
+	featureDurableWrites     = "durable_writes"
+	featureIdempotentWrites  = "idempotent_writes"
+	featureTimestampedWrites = "timestamped_writes"
+	featureTransactions      = "transactions"
+	featureRowLeases         = "row_leases"
+	// administration
+	featureTables  = "tables"
+	featureBackups = "backup_catalog"
+	featureSchema  = "schema_import_export"
+	featureClone   = "clone_family"
+	// transport
+	featureGzip = "gzip_compression"
+	// optional services, listed only when the server is set up with them
+	featureWatch          = "watch"
+	featureInfo           = "info"
+	featureCDCSubscribers = "cdc_subscribers"
+)
+
+// features are the features every server of this build supports.
+var features = []string{
+	featurePrefixScan,
+	featureRegexScan,
+	featureReadAt,
+	featureAsOf,
+	featureJSONPaths,
+	featureOrderedReads,
+	featureAggregations,
+	featureQualifiers,
+	featureTombstones,
+	featureEventual,
+	featureMultiFamilyWrites,
+	featureDurableWrites,
+	featureIdempotentWrites,
+	featureTimestampedWrites,
+	featureTransactions,
+	featureRowLeases,
+	featureTables,
+	featureBackups,
+	featureSchema,
+	featureClone,
+	featureGzip,
+}
+
+// Capabilities lists the features the server supports, so clients can degrade gracefully
+// against older servers.
+func (l *lt) Capabilities(_ context.Context, _ *proto.Empty) (*proto.CapabilitiesResponse,
+	error) {
+	supported := slices.Clone(features)
+	if l.watcher != nil {
+		supported = append(supported, featureWatch)
+	}
+	if l.info != nil {
+		supported = append(supported, featureInfo)
+	}
+	if l.subscribers != nil {
+		supported = append(supported, featureCDCSubscribers)
+	}
+	slices.Sort(supported)
+	return &proto.CapabilitiesResponse{Features: supported}, nil
+}
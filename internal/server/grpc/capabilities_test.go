@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/buildinfo"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"slices"
+	"testing"
+)
+
+func TestLt_Capabilities(t *testing.T) {
+	tests := map[string]struct {
+		svc func(ctrl *gomock.Controller) *lt
+		// optional are the optional services expected to be listed
+		optional []string
+	}{
+		"without optional services": {
+			svc: func(*gomock.Controller) *lt { return &lt{} },
+		},
+		"with watch, info and subscribers": {
+			svc: func(ctrl *gomock.Controller) *lt {
+				return &lt{
+					watcher:     NewMockwatcher(ctrl),
+					info:        buildinfo.New("memory", 8),
+					subscribers: NewMockcdcSubscribers(ctrl),
+				}
+			},
+			optional: []string{featureWatch, featureInfo, featureCDCSubscribers},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			resp, err := tc.svc(gomock.NewController(t)).Capabilities(context.Background(),
+				&proto.Empty{})
+			req.NoError(err)
+
+			got := resp.GetFeatures()
+			req.True(slices.IsSorted(got))
+			req.Subset(got, []string{featurePrefixScan, featureRegexScan, featureTransactions})
+			req.Len(got, len(features)+len(tc.optional))
+			for _, f := range []string{featureWatch, featureInfo, featureCDCSubscribers} {
+				req.Equal(slices.Contains(tc.optional, f), slices.Contains(got, f), f)
+			}
+		})
+	}
+}
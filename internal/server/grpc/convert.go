@@ -3,6 +3,8 @@ package grpc
 import (
 	litetable2 "github.com/litetable/litetable-db/internal/litetable"
 	"github.com/litetable/litetable-db/pkg/proto"
+	"maps"
+	"slices"
 )
 
 func convertToProtoData(rows map[string]*litetable2.Row) *proto.LitetableData {
@@ -11,42 +13,87 @@ func convertToProtoData(rows map[string]*litetable2.Row) *proto.LitetableData {
 	}
 
 	for rowKey, row := range rows {
-		protoRow := &proto.Row{
-			Key:  row.Key,
-			Cols: make(map[string]*proto.VersionedQualifier),
+		protoData.Rows[rowKey] = convertToProtoRow(row)
+	}
+
+	return protoData
+}
+
+// convertToProtoRow converts a row of the rows map.
+func convertToProtoRow(row *litetable2.Row) *proto.Row {
+	protoRow := &proto.Row{
+		Key:  row.Key,
+		Cols: make(map[string]*proto.VersionedQualifier),
+	}
+
+	for familyName, versionedQualifiers := range row.Columns {
+		columnFamily := &proto.VersionedQualifier{
+			Qualifiers: make(map[string]*proto.QualifierValues),
 		}
 
-		for familyName, versionedQualifiers := range row.Columns {
-			columnFamily := &proto.VersionedQualifier{
-				Qualifiers: make(map[string]*proto.QualifierValues),
+		for qualifierName, timestampedValues := range versionedQualifiers {
+			columnFamily.Qualifiers[qualifierName] = &proto.QualifierValues{
+				Values: convertToProtoValues(timestampedValues),
 			}
+		}
+
+		protoRow.Cols[familyName] = columnFamily
+	}
+
+	return protoRow
+}
 
-			for qualifierName, timestampedValues := range versionedQualifiers {
-				qualifierValues := &proto.QualifierValues{
-					Values: make([]*proto.TimestampedValue, 0, len(timestampedValues)),
-				}
+// convertToOrderedProtoData returns rows as entries sorted by row key, with families and
+// qualifiers sorted by name, so the response is the same for the same rows.
+func convertToOrderedProtoData(rows map[string]*litetable2.Row) *proto.LitetableData {
+	protoData := &proto.LitetableData{
+		Entries: make([]*proto.RowEntry, 0, len(rows)),
+	}
 
-				for _, tv := range timestampedValues {
-					protoTv := &proto.TimestampedValue{
-						Value:         tv.Value,
-						TimestampUnix: tv.Timestamp,
-					}
+	for _, rowKey := range slices.Sorted(maps.Keys(rows)) {
+		protoData.Entries = append(protoData.Entries, convertToRowEntry(rows[rowKey]))
+	}
 
-					if tv.ExpiresAt == 0 {
-						protoTv.ExpiresAtUnix = tv.ExpiresAt
-					}
+	return protoData
+}
 
-					qualifierValues.Values = append(qualifierValues.Values, protoTv)
-				}
+// convertToRowEntry converts a row to an entry of an ordered response.
+func convertToRowEntry(row *litetable2.Row) *proto.RowEntry {
+	entry := &proto.RowEntry{
+		Key:      row.Key,
+		Families: make([]*proto.FamilyEntry, 0, len(row.Columns)),
+	}
 
-				columnFamily.Qualifiers[qualifierName] = qualifierValues
-			}
+	for _, familyName := range slices.Sorted(maps.Keys(row.Columns)) {
+		versionedQualifiers := row.Columns[familyName]
+		family := &proto.FamilyEntry{
+			Name:       familyName,
+			Qualifiers: make([]*proto.QualifierEntry, 0, len(versionedQualifiers)),
+		}
 
-			protoRow.Cols[familyName] = columnFamily
+		for _, qualifierName := range slices.Sorted(maps.Keys(versionedQualifiers)) {
+			family.Qualifiers = append(family.Qualifiers, &proto.QualifierEntry{
+				Name:   qualifierName,
+				Values: convertToProtoValues(versionedQualifiers[qualifierName]),
+			})
 		}
 
-		protoData.Rows[rowKey] = protoRow
+		entry.Families = append(entry.Families, family)
 	}
 
-	return protoData
+	return entry
+}
+
+// convertToProtoValues converts the versions of a qualifier, keeping their order.
+func convertToProtoValues(values []litetable2.TimestampedValue) []*proto.TimestampedValue {
+	protoValues := make([]*proto.TimestampedValue, 0, len(values))
+	for _, tv := range values {
+		protoValues = append(protoValues, &proto.TimestampedValue{
+			Value:         tv.Value,
+			TimestampUnix: tv.Timestamp,
+			Tombstone:     tv.IsTombstone,
+			ExpiresAtUnix: tv.ExpiresAt,
+		})
+	}
+	return protoValues
 }
@@ -77,12 +77,14 @@ func TestConvertToProtoData(t *testing.T) {
 								Qualifiers: map[string]*proto.QualifierValues{
 									"a": {
 										Values: []*proto.TimestampedValue{
-											{Value: []byte("one"), TimestampUnix: 2000},
+											{Value: []byte("one"), TimestampUnix: 2000,
+												ExpiresAtUnix: 5000},
 										},
 									},
 									"b": {
 										Values: []*proto.TimestampedValue{
-											{Value: []byte("two"), TimestampUnix: 3000},
+											{Value: []byte("two"), TimestampUnix: 3000,
+												ExpiresAtUnix: 6000},
 										},
 									},
 								},
@@ -101,3 +103,37 @@ func TestConvertToProtoData(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertToOrderedProtoData(t *testing.T) {
+	req := require.New(t)
+	rows := map[string]*litetable2.Row{
+		"row2": {Key: "row2", Columns: map[string]litetable2.VersionedQualifier{
+			"b": {"q": {{Value: []byte("v"), Timestamp: 1}}},
+			"a": {
+				"z": {{Value: []byte("new"), Timestamp: 2}, {Value: []byte("old"), Timestamp: 1}},
+				"y": {{Value: []byte("v"), Timestamp: 1}},
+			},
+		}},
+		"row1": {Key: "row1", Columns: map[string]litetable2.VersionedQualifier{
+			"a": {"q": {{Value: []byte("v"), Timestamp: 1}}},
+		}},
+	}
+
+	got := convertToOrderedProtoData(rows)
+	req.Empty(got.GetRows())
+	req.Len(got.GetEntries(), 2)
+	req.Equal("row1", got.GetEntries()[0].GetKey())
+
+	row2 := got.GetEntries()[1]
+	req.Equal("row2", row2.GetKey())
+	req.Len(row2.GetFamilies(), 2)
+	req.Equal("a", row2.GetFamilies()[0].GetName())
+	req.Equal("b", row2.GetFamilies()[1].GetName())
+
+	qualifiers := row2.GetFamilies()[0].GetQualifiers()
+	req.Len(qualifiers, 2)
+	req.Equal("y", qualifiers[0].GetName())
+	req.Equal("z", qualifiers[1].GetName())
+	req.Equal([]byte("new"), qualifiers[1].GetValues()[0].GetValue())
+	req.Equal([]byte("old"), qualifiers[1].GetValues()[1].GetValue())
+}
@@ -0,0 +1,252 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpc2 "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+const (
+	// ClientHeader is the gRPC metadata key naming the client a read is accounted to. It is not
+	// authenticated, so it only tells cooperating clients apart.
+	ClientHeader = "x-litetable-client"
+	// DefaultCostWindow is how long the cost of a client adds up against its quota when
+	// Limits.CostWindow is not set.
+	DefaultCostWindow = time.Minute
+
+	// anonymousClient is the client of reads without a usable client header
+	anonymousClient = "anonymous"
+	// otherClients labels the metrics of the clients seen after the first maxClientLabels, so a
+	// client naming itself anew on every read cannot grow /metrics without bound
+	otherClients    = "other"
+	maxClientLabels = 100
+	maxClientLength = 128
+	// bytesPerCost are the response bytes that cost as much as a scanned row or a copied cell
+	bytesPerCost = 1024
+)
+
+var (
+	rowsScanned = metrics.NewCounterVec("litetable_query_rows_scanned_total",
+		"Rows read from storage by reads, by client.", "client")
+	cellsCopied = metrics.NewCounterVec("litetable_query_cells_copied_total",
+		"Values copied into read results or aggregated, by client.", "client")
+	bytesReturned = metrics.NewCounterVec("litetable_query_bytes_returned_total",
+		"Encoded bytes of read responses, by client.", "client")
+	queryCost = metrics.NewCounterVec("litetable_query_cost_total",
+		"Cost of reads, in rows scanned, cells copied and KiB returned, by client.", "client")
+	quotaRejections = metrics.NewCounterVec("litetable_query_quota_rejections_total",
+		"Reads refused because the client used up its cost quota, by client.", "client")
+	costAlerts = metrics.NewCounterVec("litetable_query_cost_alerts_total",
+		"Windows in which a client passed its alert cost, by client.", "client")
+)
+
+// ClientQuota bounds the cost of the reads of one client over each cost window. Zero values are
+// unlimited.
+type ClientQuota struct {
+	// MaxCost refuses the client's reads with RESOURCE_EXHAUSTED once they cost this much, until
+	// the window ends.
+	MaxCost int64
+	// AlertCost logs a warning the first time the client's reads cost this much in a window.
+	AlertCost int64
+}
+
+// cost is the cost of one read, which the handler adds to as it runs.
+type cost struct {
+	litetable.Cost
+	bytes int
+}
+
+// total weighs a read's cost in a single unit.
+func (c *cost) total() int64 {
+	return int64(c.RowsScanned) + int64(c.CellsCopied) + int64(c.bytes/bytesPerCost)
+}
+
+type costKey struct{}
+
+// addCost adds the work of a read to the cost of the request, if it is accounted.
+func addCost(ctx context.Context, c litetable.Cost) {
+	if acc, ok := ctx.Value(costKey{}).(*cost); ok {
+		acc.RowsScanned += c.RowsScanned
+		acc.CellsCopied += c.CellsCopied
+	}
+}
+
+// usage is the cost of a client in the current window.
+type usage struct {
+	start   time.Time
+	cost    int64
+	alerted bool
+}
+
+// accountant charges the cost of reads to the clients sending them, and refuses the reads of
+// clients over their quota.
+type accountant struct {
+	// validator holds the current quotas and cost window; without it, reads are unlimited
+	validator *atomic.Pointer[validator]
+	now       func() time.Time
+
+	mutex  sync.Mutex
+	usages map[string]*usage
+	// labels are the clients the metrics are labelled with
+	labels map[string]bool
+}
+
+func newAccountant(v *atomic.Pointer[validator]) *accountant {
+	return &accountant{
+		validator: v,
+		now:       time.Now,
+		usages:    make(map[string]*usage),
+		labels:    make(map[string]bool),
+	}
+}
+
+// unaryInterceptor accounts the cost of reads to their client, refusing them while the client
+// is over its quota. Other requests pass through.
+func (a *accountant) unaryInterceptor(ctx context.Context, req any, _ *grpc2.UnaryServerInfo,
+	handler grpc2.UnaryHandler) (any, error) {
+	switch req.(type) {
+	case *proto.ReadRequest, *proto.AggregateRequest:
+	default:
+		return handler(ctx, req)
+	}
+
+	client := clientName(ctx)
+	if err := a.admit(client); err != nil {
+		return nil, err
+	}
+
+	c := &cost{}
+	resp, err := handler(context.WithValue(ctx, costKey{}, c), req)
+	if msg, ok := resp.(protobuf.Message); ok && err == nil {
+		c.bytes = protobuf.Size(msg)
+	}
+	a.charge(ctx, client, c)
+	return resp, err
+}
+
+// admit returns a RESOURCE_EXHAUSTED error, with the time left in the window as the retry
+// delay, if the client used up its quota.
+func (a *accountant) admit(client string) error {
+	quota, window := a.quota(client)
+	if quota.MaxCost <= 0 {
+		return nil
+	}
+
+	a.mutex.Lock()
+	u := a.usage(client, window)
+	used, retry := u.cost, u.start.Add(window).Sub(a.now())
+	a.mutex.Unlock()
+	if used < quota.MaxCost {
+		return nil
+	}
+
+	quotaRejections.With(a.label(client)).Inc()
+	st, err := status.New(codes.ResourceExhausted, "query cost quota of client "+client+
+		" exceeded").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retry)})
+	if err != nil {
+		return status.Errorf(codes.ResourceExhausted, "query cost quota of client %s exceeded",
+			client)
+	}
+	return st.Err()
+}
+
+// charge adds the cost of a read to its client's usage and metrics.
+func (a *accountant) charge(ctx context.Context, client string, c *cost) {
+	label := a.label(client)
+	rowsScanned.With(label).Add(float64(c.RowsScanned))
+	cellsCopied.With(label).Add(float64(c.CellsCopied))
+	bytesReturned.With(label).Add(float64(c.bytes))
+	queryCost.With(label).Add(float64(c.total()))
+
+	quota, window := a.quota(client)
+	if quota.MaxCost <= 0 && quota.AlertCost <= 0 {
+		return
+	}
+
+	a.mutex.Lock()
+	u := a.usage(client, window)
+	u.cost += c.total()
+	alert := quota.AlertCost > 0 && u.cost >= quota.AlertCost && !u.alerted
+	if alert {
+		u.alerted = true
+	}
+	used := u.cost
+	a.mutex.Unlock()
+
+	if alert {
+		costAlerts.With(label).Inc()
+		requestid.Logger(ctx).Warn().
+			Str("client", client).
+			Int64("cost", used).
+			Int64("alert_cost", quota.AlertCost).
+			Dur("window", window).
+			Msg("client passed its alert cost")
+	}
+}
+
+// quota returns the quota of a client and the cost window.
+func (a *accountant) quota(client string) (ClientQuota, time.Duration) {
+	if a.validator == nil {
+		return ClientQuota{}, DefaultCostWindow
+	}
+	v := a.validator.Load()
+	return v.clientQuotas[client], v.costWindow
+}
+
+// usage returns the usage of a client in the current window, starting a new window when the
+// last one ended. The caller holds the lock.
+func (a *accountant) usage(client string, window time.Duration) *usage {
+	now := a.now()
+	u, ok := a.usages[client]
+	if !ok || !now.Before(u.start.Add(window)) {
+		u = &usage{start: now}
+		a.usages[client] = u
+	}
+	return u
+}
+
+// label returns the metric label of a client.
+func (a *accountant) label(client string) string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.labels[client] {
+		return client
+	}
+	if len(a.labels) >= maxClientLabels {
+		return otherClients
+	}
+	a.labels[client] = true
+	return client
+}
+
+// clientName returns the client named by the client header of a request, or anonymous when it
+// names none or is not printable ASCII.
+func clientName(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return anonymousClient
+	}
+	values := md.Get(ClientHeader)
+	if len(values) == 0 || values[0] == "" || len(values[0]) > maxClientLength {
+		return anonymousClient
+	}
+	for _, r := range values[0] {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return anonymousClient
+		}
+	}
+	return values[0]
+}
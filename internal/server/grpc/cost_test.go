@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// clientContext is the context of a request from a client.
+func clientContext(client string) context.Context {
+	return metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(ClientHeader, client))
+}
+
+func TestAccountant(t *testing.T) {
+	req := require.New(t)
+	var limits atomic.Pointer[validator]
+	v, err := newValidator(Limits{
+		ClientQuotas: map[string]ClientQuota{"reports": {MaxCost: 100, AlertCost: 50}},
+		CostWindow:   time.Minute,
+	})
+	req.NoError(err)
+	limits.Store(v)
+
+	now := time.Unix(1000, 0)
+	a := newAccountant(&limits)
+	a.now = func() time.Time { return now }
+
+	// a scan of 40 rows and cells, returning a response of about 2KiB
+	handler := func(ctx context.Context, _ any) (any, error) {
+		addCost(ctx, litetable.Cost{RowsScanned: 20, CellsCopied: 20})
+		return &proto.LitetableData{Rows: map[string]*proto.Row{
+			"champ:1": {Key: strings.Repeat("a", 2048)},
+		}}, nil
+	}
+	read := func(client string) error {
+		_, err := a.unaryInterceptor(clientContext(client), &proto.ReadRequest{}, nil, handler)
+		return err
+	}
+
+	scanned := rowsScanned.With("reports").Value()
+	returned := bytesReturned.With("reports").Value()
+	alerts := costAlerts.With("reports").Value()
+
+	// the second read passes the alert cost and the third the quota
+	for range 3 {
+		req.NoError(read("reports"))
+	}
+	req.Equal(scanned+60, rowsScanned.With("reports").Value())
+	req.Greater(bytesReturned.With("reports").Value(), returned+3*2048)
+	req.Equal(alerts+1, costAlerts.With("reports").Value())
+
+	// over the quota, reads are refused until the window ends
+	now = now.Add(20 * time.Second)
+	err = read("reports")
+	req.Equal(codes.ResourceExhausted, status.Code(err))
+	details := status.Convert(err).Details()
+	req.Len(details, 1)
+	req.Equal(40*time.Second, details[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+
+	// other clients are not limited
+	req.NoError(read("dashboards"))
+	req.NoError(read(""))
+
+	now = now.Add(40 * time.Second)
+	req.NoError(read("reports"))
+
+	// writes are not accounted
+	_, err = a.unaryInterceptor(clientContext("reports"), &proto.WriteRequest{}, nil,
+		func(ctx context.Context, _ any) (any, error) {
+			addCost(ctx, litetable.Cost{RowsScanned: 1000})
+			return &proto.LitetableData{}, nil
+		})
+	req.NoError(err)
+	req.NoError(read("reports"))
+}
+
+func TestAccountant_label(t *testing.T) {
+	a := newAccountant(nil)
+	for i := range maxClientLabels {
+		require.Equal(t, fmt.Sprint(i), a.label(fmt.Sprint(i)))
+	}
+	require.Equal(t, otherClients, a.label("one too many"))
+	require.Equal(t, "1", a.label("1"))
+}
+
+func TestClientName(t *testing.T) {
+	tests := map[string]struct {
+		ctx  context.Context
+		want string
+	}{
+		"named": {
+			ctx:  clientContext("reports"),
+			want: "reports",
+		},
+		"no metadata": {
+			ctx:  context.Background(),
+			want: anonymousClient,
+		},
+		"empty": {
+			ctx:  clientContext(""),
+			want: anonymousClient,
+		},
+		"not printable": {
+			ctx:  clientContext("reports\n"),
+			want: anonymousClient,
+		},
+		"too long": {
+			ctx:  clientContext(strings.Repeat("a", maxClientLength+1)),
+			want: anonymousClient,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, clientName(tc.ctx))
+		})
+	}
+}
@@ -3,8 +3,8 @@ package grpc
 import (
 	"context"
 	"errors"
+	"github.com/litetable/litetable-db/internal/requestid"
 	"github.com/litetable/litetable-db/pkg/proto"
-	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"time"
@@ -28,11 +28,24 @@ func (l *lt) CreateFamily(ctx context.Context, msg *proto.CreateFamilyRequest) (
 		return nil, err
 	}
 
-	log.Debug().Msgf("CreateFamily request: %v", msg)
+	requestid.Logger(ctx).Debug().Msgf("CreateFamily request: %v", msg)
 
-	if err := l.operations.CreateFamilies(msg.GetFamily()); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create family: %v", err)
+	if err := l.operations.CreateFamilies(msg.GetTable(), msg.GetFamily()); err != nil {
+		return nil, toStatus(err, "failed to create family")
 	}
-	log.Debug().Msgf("CreateFamily successful: %v", time.Since(start))
+	requestid.Logger(ctx).Debug().Msgf("CreateFamily successful: %v", time.Since(start))
 	return nil, nil
 }
+
+// CloneFamily creates a column family holding a copy of the rows of another.
+func (l *lt) CloneFamily(ctx context.Context, msg *proto.CloneFamilyRequest) (
+	*proto.CloneFamilyResponse, error) {
+	start := time.Now()
+	rows, cells, err := l.operations.CloneFamily(msg.GetTable(), msg.GetSource(),
+		msg.GetTarget())
+	if err != nil {
+		return nil, toStatus(err, "failed to clone family")
+	}
+	requestid.Logger(ctx).Debug().Msgf("CloneFamily successful: %v", time.Since(start))
+	return &proto.CloneFamilyResponse{Rows: uint64(rows), Cells: uint64(cells)}, nil
+}
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"errors"
+	litetable2 "github.com/litetable/litetable-db/internal/litetable"
 	"github.com/litetable/litetable-db/pkg/proto"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -30,7 +31,7 @@ func TestLt_CreateFamily(t *testing.T) {
 			request: &proto.CreateFamilyRequest{Family: []string{"testFamily"}},
 			mockSetup: func(m *Mockoperations) {
 				m.EXPECT().
-					CreateFamilies([]string{"testFamily"}).
+					CreateFamilies("", []string{"testFamily"}).
 					Return(errors.New("backend error"))
 			},
 			expectedCode:    codes.Internal,
@@ -40,7 +41,7 @@ func TestLt_CreateFamily(t *testing.T) {
 			request: &proto.CreateFamilyRequest{Family: []string{"validFamily"}},
 			mockSetup: func(m *Mockoperations) {
 				m.EXPECT().
-					CreateFamilies([]string{"validFamily"}).
+					CreateFamilies("", []string{"validFamily"}).
 					Return(nil)
 			},
 			expectedCode:    codes.OK,
@@ -80,3 +81,45 @@ func TestLt_CreateFamily(t *testing.T) {
 		})
 	}
 }
+
+func TestLt_CloneFamily(t *testing.T) {
+	tests := map[string]struct {
+		mockSetup    func(m *Mockoperations)
+		expectedCode codes.Code
+	}{
+		"clone": {
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().CloneFamily("stats", "main", "main_copy").Return(3, 7, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"existing target": {
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().CloneFamily("stats", "main", "main_copy").
+					Return(0, 0, litetable2.NewError(litetable2.ErrorCodeConflict,
+						"family main_copy already exists"))
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.CloneFamily(context.Background(), &proto.CloneFamilyRequest{
+				Table:  "stats",
+				Source: "main",
+				Target: "main_copy",
+			})
+			req.Equal(tc.expectedCode, status.Code(err))
+			if tc.expectedCode == codes.OK {
+				req.Equal(uint64(3), resp.GetRows())
+				req.Equal(uint64(7), resp.GetCells())
+			}
+		})
+	}
+}
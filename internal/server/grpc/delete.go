@@ -26,15 +26,23 @@ func (l *lt) Delete(ctx context.Context, msg *proto.DeleteRequest) (*proto.Empty
 		return nil, err
 	}
 
-	// Ex: DELETE family="family" rowKey="rowKey" qualifier="qualifier"
-	queryStr := "key=" + msg.GetRowKey()
+	if err := l.operations.Delete(deleteQuery(msg)); err != nil {
+		return nil, toStatus(err, "failed to delete data")
+	}
+	return &proto.Empty{}, nil
+}
+
+// deleteQuery builds the query of a delete.
+func deleteQuery(msg *proto.DeleteRequest) string {
+	// Ex: v2:key=rowKey family=family qualifier=qualifier
+	queryStr := queryVersion + "key=" + queryValue(msg.GetRowKey())
 
 	if msg.GetFamily() != "" {
-		queryStr += " family=" + msg.GetFamily()
+		queryStr += " family=" + queryValue(msg.GetFamily())
 	}
 
 	for _, qualifier := range msg.GetQualifiers() {
-		queryStr += " qualifier=" + qualifier
+		queryStr += " qualifier=" + queryValue(qualifier)
 	}
 
 	// The timestamp signals where we should place the tombstone
@@ -49,8 +57,11 @@ func (l *lt) Delete(ctx context.Context, msg *proto.DeleteRequest) (*proto.Empty
 		queryStr += " ttl=" + fmt.Sprintf("%d", ttl)
 	}
 
-	if err := l.operations.Delete(queryStr); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to delete data: %v", err)
+	if msg.GetTable() != "" {
+		queryStr += " table=" + queryValue(msg.GetTable())
 	}
-	return &proto.Empty{}, nil
+	if msg.GetFencingToken() != 0 {
+		queryStr += fmt.Sprintf(" fence=%d", msg.GetFencingToken())
+	}
+	return queryStr
 }
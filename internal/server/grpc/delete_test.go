@@ -35,7 +35,7 @@ func TestLt_Delete(t *testing.T) {
 			mockSetup: func(m *Mockoperations) {
 				// Expected query: key=rk family=fam qualifier=q1
 				m.EXPECT().
-					Delete("key=rk family=fam qualifier=q1").
+					Delete("v2:key=rk family=fam qualifier=q1").
 					Return(errors.New("boom"))
 			},
 			expectedCode:    codes.Internal,
@@ -51,7 +51,7 @@ func TestLt_Delete(t *testing.T) {
 			},
 			mockSetup: func(m *Mockoperations) {
 				m.EXPECT().
-					Delete("key=rk family=fam qualifier=q1 qualifier=q2 timestamp=12345 ttl=60").
+					Delete("v2:key=rk family=fam qualifier=q1 qualifier=q2 timestamp=12345 ttl=60").
 					Return(nil)
 			},
 			expectedCode:    codes.OK,
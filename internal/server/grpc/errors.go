@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain attached to every LiteTable status.
+const errorDomain = "litetable.io"
+
+// grpcCode maps a litetable.ErrorCode onto the closest gRPC status code.
+func grpcCode(code litetable.ErrorCode) codes.Code {
+	switch code {
+	case litetable.ErrorCodeNotFound:
+		return codes.NotFound
+	case litetable.ErrorCodeInvalidArgument:
+		return codes.InvalidArgument
+	case litetable.ErrorCodeFamilyMissing:
+		return codes.FailedPrecondition
+	case litetable.ErrorCodeConflict:
+		return codes.AlreadyExists
+	case litetable.ErrorCodeExhausted:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// toStatus converts an error from the operations layer into a gRPC status error. The
+// litetable.ErrorCode is attached as an ErrorInfo reason so clients never need to match on the
+// message.
+func toStatus(err error, msg string) error {
+	return toStatusCode(err, grpcCode(litetable.CodeOf(err)), msg)
+}
+
+// toStatusCode is toStatus with the gRPC code chosen by the caller.
+func toStatusCode(err error, grpcCode codes.Code, msg string) error {
+	code := litetable.CodeOf(err)
+	st := status.New(grpcCode, fmt.Sprintf("%s: %v", msg, err))
+
+	withDetails, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
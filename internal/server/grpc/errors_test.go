@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func Test_toStatus(t *testing.T) {
+	tests := map[string]struct {
+		err          error
+		expectedCode codes.Code
+		expectedMsg  string
+		reason       string
+	}{
+		"untyped error is internal": {
+			err:          errors.New("boom"),
+			expectedCode: codes.Internal,
+			expectedMsg:  "failed to read data: boom",
+			reason:       "INTERNAL",
+		},
+		"not found": {
+			err:          litetable.NewError(litetable.ErrorCodeNotFound, "row not found: champ:1"),
+			expectedCode: codes.NotFound,
+			expectedMsg:  "failed to read data: row not found: champ:1",
+			reason:       "NOT_FOUND",
+		},
+		"invalid argument": {
+			err:          litetable.NewError(litetable.ErrorCodeInvalidArgument, "missing key"),
+			expectedCode: codes.InvalidArgument,
+			reason:       "INVALID_ARGUMENT",
+		},
+		"family missing": {
+			err:          litetable.NewError(litetable.ErrorCodeFamilyMissing, "nope"),
+			expectedCode: codes.FailedPrecondition,
+			reason:       "FAMILY_MISSING",
+		},
+		"conflict": {
+			err:          litetable.NewError(litetable.ErrorCodeConflict, "exists"),
+			expectedCode: codes.AlreadyExists,
+			reason:       "CONFLICT",
+		},
+		"exhausted": {
+			err:          litetable.NewError(litetable.ErrorCodeExhausted, "quota"),
+			expectedCode: codes.ResourceExhausted,
+			reason:       "EXHAUSTED",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			st, ok := status.FromError(toStatus(tc.err, "failed to read data"))
+			req.True(ok)
+			req.Equal(tc.expectedCode, st.Code())
+			if tc.expectedMsg != "" {
+				req.Equal(tc.expectedMsg, st.Message())
+			}
+
+			req.Len(st.Details(), 1)
+			info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+			req.True(ok)
+			req.Equal(tc.reason, info.GetReason())
+			req.Equal(errorDomain, info.GetDomain())
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"time"
+)
+
+// Flush blocks until every acknowledged write is in an fsynced backup, for clients that need a
+// durability acknowledgement for writes made without sync.
+func (l *lt) Flush(ctx context.Context, _ *proto.Empty) (*proto.Empty, error) {
+	start := time.Now()
+	if err := l.operations.Flush(); err != nil {
+		return nil, toStatus(err, "failed to flush")
+	}
+	requestid.Logger(ctx).Debug().Msgf("Flush successful: %v", time.Since(start))
+	return &proto.Empty{}, nil
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestLt_Flush(t *testing.T) {
+	tests := map[string]struct {
+		flushErr     error
+		expectedCode codes.Code
+	}{
+		"flushed": {
+			expectedCode: codes.OK,
+		},
+		"flush failure": {
+			flushErr:     errors.New("disk full"),
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			ctrl := gomock.NewController(t)
+
+			mockOps := NewMockoperations(ctrl)
+			mockOps.EXPECT().Flush().Return(tc.flushErr)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.Flush(context.Background(), &proto.Empty{})
+			if tc.expectedCode == codes.OK {
+				req.NoError(err)
+				req.NotNil(resp)
+				return
+			}
+
+			req.Nil(resp)
+			st, ok := status.FromError(err)
+			req.True(ok)
+			req.Equal(tc.expectedCode, st.Code())
+			req.Contains(st.Message(), "failed to flush")
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package grpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/litetable/litetable-db/internal/buildinfo"
 	"github.com/litetable/litetable-db/pkg/proto"
 	"github.com/rs/zerolog/log"
 	grpc2 "google.golang.org/grpc"
+	channelz "google.golang.org/grpc/channelz/service"
 	"google.golang.org/grpc/reflection"
 	"net"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,12 +21,41 @@ type Server struct {
 	server   grpcServer
 	port     int
 	listener net.Listener
+
+	// validator is swapped atomically when the limits are reloaded
+	validator atomic.Pointer[validator]
+	// stopping is closed on Stop to end the Watch streams, which would otherwise keep a graceful
+	// stop waiting
+	stopping chan struct{}
 }
 
 type Config struct {
 	Address    string
 	Port       int
 	Operations operations
+	// Limits bound the size and shape of incoming requests
+	Limits Limits
+	// Transport tunes message sizes, stream concurrency and keepalive enforcement
+	Transport Transport
+	// Reflection registers the reflection service, so tools like grpcurl can list and call the
+	// API without the proto files. It exposes the whole API, so leave it off in production.
+	Reflection bool
+	// Channelz registers the channelz service, which grpcdebug uses to inspect the server's
+	// connections and requests. Like Reflection, it is meant for non-production environments.
+	Channelz bool
+	// Stats exports request counts by method and code, message bytes and open connections on
+	// /metrics.
+	Stats bool
+	// Watcher serves the Watch RPC from the CDC stream. Without one, Watch is unimplemented.
+	Watcher watcher
+	// Subscribers lists and kicks the clients of the CDC stream. Without it, ListCDCSubscribers
+	// and KickCDCSubscriber are unimplemented.
+	Subscribers cdcSubscribers
+	// Info is returned by the Info RPC. Without it, Info is unimplemented.
+	Info *buildinfo.Info
+	// Recorder records the shape of the reads, writes and deletes that pass validation. Without
+	// one, nothing is recorded.
+	Recorder recorder
 }
 
 func (c *Config) validate() error {
@@ -46,27 +79,92 @@ func NewServer(cfg *Config) (*Server, error) {
 		return nil, err
 	}
 
+	v, err := newValidator(cfg.Limits)
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.Transport.setCompression(); err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		address:  cfg.Address,
+		port:     cfg.Port,
+		stopping: make(chan struct{}),
+	}
+	s.validator.Store(v)
+
 	// Create a new gRPC server
-	srv := grpc2.NewServer()
+	// logging is outermost so recovered panics and rejected requests are logged too
+	interceptors := []grpc2.UnaryServerInterceptor{
+		loggingInterceptor,
+		recoveryInterceptor,
+		s.validationInterceptor,
+	}
+	if cfg.Recorder != nil {
+		interceptors = append(interceptors, recordingInterceptor(cfg.Recorder))
+	}
+	interceptors = append(interceptors, newAccountant(&s.validator).unaryInterceptor)
+	opts := append(cfg.Transport.serverOptions(), grpc2.ChainUnaryInterceptor(interceptors...))
+	if cfg.Stats {
+		opts = append(opts, grpc2.StatsHandler(statsHandler{}))
+	}
+	srv := grpc2.NewServer(opts...)
 
 	l := &lt{
-		operations: cfg.Operations,
+		operations:  cfg.Operations,
+		watcher:     cfg.Watcher,
+		subscribers: cfg.Subscribers,
+		info:        cfg.Info,
+		stopping:    s.stopping,
+		validator:   &s.validator,
 	}
 
 	srv.RegisterService(&proto.LitetableService_ServiceDesc, l)
-	reflection.Register(srv)
+	if cfg.Reflection {
+		reflection.Register(srv)
+	}
+	if cfg.Channelz {
+		channelz.RegisterChannelzServiceToServer(srv)
+	}
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Address, cfg.Port))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create listener on port %d: %w", cfg.Port, err)
 	}
 
-	return &Server{
-		address:  cfg.Address,
-		server:   srv,
-		port:     cfg.Port,
-		listener: lis,
-	}, nil
+	s.server = srv
+	s.listener = lis
+	return s, nil
+}
+
+// validationInterceptor validates requests against the current limits.
+func (s *Server) validationInterceptor(ctx context.Context, req any,
+	info *grpc2.UnaryServerInfo, handler grpc2.UnaryHandler) (any, error) {
+	return s.validator.Load().unaryInterceptor(ctx, req, info, handler)
+}
+
+// ValidateLimits checks request limits without applying them, so a reload can check every
+// setting before it changes any.
+func ValidateLimits(l Limits) error {
+	_, err := newValidator(l)
+	return err
+}
+
+// SetLimits replaces the request limits without restarting the server. The current limits are
+// kept if the new ones are invalid.
+func (s *Server) SetLimits(l Limits) error {
+	v, err := newValidator(l)
+	if err != nil {
+		return err
+	}
+	s.validator.Store(v)
+	return nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
 }
 
 func (s *Server) Start() error {
@@ -96,6 +194,9 @@ func (s *Server) Start() error {
 
 func (s *Server) Stop() error {
 	log.Info().Msg("Stopping gRPC server")
+	if s.stopping != nil {
+		close(s.stopping)
+	}
 	s.server.GracefulStop()
 	return nil
 }
@@ -54,6 +54,63 @@ func TestNewServer(t *testing.T) {
 	}
 }
 
+func TestNewServer_debugServices(t *testing.T) {
+	tests := map[string]struct {
+		cfg  func(c *Config)
+		want []string
+	}{
+		"off by default": {
+			cfg:  func(c *Config) {},
+			want: []string{proto.LitetableService_ServiceDesc.ServiceName},
+		},
+		"reflection and channelz": {
+			cfg: func(c *Config) {
+				c.Reflection = true
+				c.Channelz = true
+			},
+			want: []string{
+				proto.LitetableService_ServiceDesc.ServiceName,
+				"grpc.reflection.v1.ServerReflection",
+				"grpc.reflection.v1alpha.ServerReflection",
+				"grpc.channelz.v1.Channelz",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			ctrl := gomock.NewController(t)
+
+			cfg := &Config{
+				Address:    "127.0.0.1",
+				Port:       freePort(t),
+				Operations: NewMockoperations(ctrl),
+				Stats:      true,
+			}
+			tc.cfg(cfg)
+
+			s, err := NewServer(cfg)
+			req.NoError(err)
+			defer s.listener.Close()
+
+			var services []string
+			for service := range s.server.(*grpc.Server).GetServiceInfo() {
+				services = append(services, service)
+			}
+			req.ElementsMatch(tc.want, services)
+		})
+	}
+}
+
+// freePort returns a port that was free when it was checked.
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
 func TestServer_Name(t *testing.T) {
 	s := &Server{}
 	require.Equal(t, "gRPC Server", s.Name())
@@ -65,7 +122,7 @@ func TestGRPCServer_Real(t *testing.T) {
 
 	mockOps := NewMockoperations(ctrl)
 	mockOps.EXPECT().
-		CreateFamilies([]string{"testFamily"}).
+		CreateFamilies("", []string{"testFamily"}).
 		Return(nil)
 
 	// bind to a free port
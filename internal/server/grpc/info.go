@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/buildinfo"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// Info returns the version and build of the server, so clients can check compatibility during
+// a rollout.
+func (l *lt) Info(_ context.Context, _ *proto.Empty) (*proto.InfoResponse, error) {
+	if l.info == nil {
+		return nil, status.Errorf(codes.Unimplemented, "info is not enabled on this server")
+	}
+
+	info := l.info.At(time.Now())
+	return &proto.InfoResponse{
+		Version:               info.Version,
+		Commit:                info.Commit,
+		GoVersion:             info.GoVersion,
+		ApiVersion:            info.APIVersion,
+		ProtobufVersion:       info.ProtobufVersion,
+		GrpcVersion:           info.GRPCVersion,
+		BackupFormatVersion:   int32(info.BackupFormatVersion),
+		DeltaFormatVersion:    int32(info.DeltaFormatVersion),
+		SnapshotFormatVersion: int32(info.SnapshotFormatVersion),
+		StorageEngine:         info.StorageEngine,
+		ShardCount:            int32(info.ShardCount),
+		StartedAtUnix:         info.StartedAt,
+		UptimeSeconds:         info.UptimeSeconds,
+		Startup:               startupToProto(info.Startup),
+	}, nil
+}
+
+// startupToProto converts the startup report, which is nil until the server has started.
+func startupToProto(startup *buildinfo.Startup) *proto.StartupReport {
+	if startup == nil {
+		return nil
+	}
+	report := &proto.StartupReport{Addresses: startup.Addresses}
+	for _, t := range startup.Tables {
+		shardRows := make([]int64, len(t.ShardRows))
+		for i, rows := range t.ShardRows {
+			shardRows[i] = int64(rows)
+		}
+		report.Tables = append(report.Tables, &proto.TableLoad{
+			Table:      t.Table,
+			Backup:     t.Backup,
+			Snapshots:  int32(t.Snapshots),
+			Rows:       int64(t.Rows),
+			ShardRows:  shardRows,
+			RowSkew:    t.RowSkew,
+			Families:   t.Families,
+			DurationMs: t.Duration.Milliseconds(),
+		})
+	}
+	return report
+}
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/buildinfo"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestLt_Info(t *testing.T) {
+	t.Run("info", func(t *testing.T) {
+		req := require.New(t)
+		info := buildinfo.New("memory", 8)
+		info.StartedAt = time.Now().Add(-time.Minute).UnixNano()
+
+		svc := &lt{info: info}
+		resp, err := svc.Info(context.Background(), &proto.Empty{})
+		req.NoError(err)
+		req.Equal(buildinfo.Version, resp.GetVersion())
+		req.Equal("litetable.server.v1", resp.GetApiVersion())
+		req.Equal(int32(1), resp.GetBackupFormatVersion())
+		req.Equal(int32(1), resp.GetDeltaFormatVersion())
+		req.Equal(int32(1), resp.GetSnapshotFormatVersion())
+		req.Equal("memory", resp.GetStorageEngine())
+		req.Equal(int32(8), resp.GetShardCount())
+		req.Equal(info.StartedAt, resp.GetStartedAtUnix())
+		req.Equal(int64(60), resp.GetUptimeSeconds())
+		req.Nil(resp.GetStartup())
+	})
+
+	t.Run("startup", func(t *testing.T) {
+		req := require.New(t)
+		info := buildinfo.New("memory", 2)
+		info.Startup = &buildinfo.Startup{
+			Tables: []litetable.LoadReport{{
+				Table:     "default",
+				Backup:    "backup-1.db",
+				Snapshots: 2,
+				Rows:      3,
+				ShardRows: []int{1, 2},
+				RowSkew:   4.0 / 3,
+				Families:  []string{"main"},
+				Duration:  1500 * time.Millisecond,
+			}},
+			Addresses: map[string]string{"grpc": "127.0.0.1:9090"},
+		}
+
+		svc := &lt{info: info}
+		resp, err := svc.Info(context.Background(), &proto.Empty{})
+		req.NoError(err)
+		req.Equal(map[string]string{"grpc": "127.0.0.1:9090"}, resp.GetStartup().GetAddresses())
+		req.Len(resp.GetStartup().GetTables(), 1)
+		table := resp.GetStartup().GetTables()[0]
+		req.Equal("default", table.GetTable())
+		req.Equal("backup-1.db", table.GetBackup())
+		req.Equal(int32(2), table.GetSnapshots())
+		req.Equal(int64(3), table.GetRows())
+		req.Equal([]int64{1, 2}, table.GetShardRows())
+		req.InDelta(1.33, table.GetRowSkew(), 0.01)
+		req.Equal([]string{"main"}, table.GetFamilies())
+		req.Equal(int64(1500), table.GetDurationMs())
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		svc := &lt{}
+		_, err := svc.Info(context.Background(), &proto.Empty{})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
@@ -1,18 +1,55 @@
 package grpc
 
 import (
+	"github.com/litetable/litetable-db/internal/buildinfo"
+	cdc "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
 	litetable2 "github.com/litetable/litetable-db/internal/litetable"
 	"github.com/litetable/litetable-db/pkg/proto"
 	"net"
+	"sync/atomic"
+	"time"
 )
 
 //go:generate mockgen -destination=./litetable_mock.go -package=grpc -source=./litetable.go
 
 type operations interface {
-	CreateFamilies(families []string) error
-	Read(query string) (map[string]*litetable2.Row, error)
+	CreateFamilies(table string, families []string) error
+	CloneFamily(table, source, target string) (int, int, error)
+	Read(query string) (map[string]*litetable2.Row, litetable2.Cost, error)
+	Aggregate(query string) ([]*litetable2.Aggregate, litetable2.Cost, error)
+	ListQualifiers(query string) ([]litetable2.QualifierVersions, error)
 	Write(query string) (map[string]*litetable2.Row, error)
 	Delete(query string) error
+	Flush() error
+	ListBackups() ([]*litetable2.BackupManifest, error)
+	CreateTable(name string, families []string) error
+	DropTable(name string) error
+	ListTables() []string
+	ExportSchema() (litetable2.Schema, error)
+	ImportSchema(schema litetable2.Schema, dryRun bool) ([]litetable2.TableSchema, error)
+	TableStats(table string) (litetable2.TableUsage, error)
+	ListTombstones(table, prefix string, limit int) ([]litetable2.Tombstone, bool, error)
+	Sequence(table string) (uint64, error)
+	Commit(table string, readAt uint64, reads []string,
+		queries []litetable2.MutationQuery) (litetable2.CommitResult, error)
+	LockRow(table, rowKey, owner string, ttl time.Duration) (litetable2.RowLease, error)
+	UnlockRow(table, rowKey string, token uint64) error
+}
+
+type watcher interface {
+	Watch(rowKey, prefix string) (*cdc.Watch, error)
+	Unwatch(w *cdc.Watch)
+}
+
+type cdcSubscribers interface {
+	Subscribers() []cdc.Subscriber
+	Sequence() uint64
+	Kick(id string) error
+}
+
+// recorder records the shape of the requests the server accepts.
+type recorder interface {
+	Record(req any)
 }
 
 type grpcServer interface {
@@ -23,4 +60,21 @@ type grpcServer interface {
 type lt struct {
 	proto.UnimplementedLitetableServiceServer
 	operations operations
+	watcher    watcher
+	// subscribers serves the CDC subscriber RPCs; without it, they are unimplemented
+	subscribers cdcSubscribers
+	// info is returned by the Info RPC; without it, Info is unimplemented
+	info *buildinfo.Info
+	// stopping is closed when the server stops
+	stopping <-chan struct{}
+	// validator holds the current limits; without it, the defaults apply
+	validator *atomic.Pointer[validator]
+}
+
+// maxResponseSize returns the largest encoded size of the rows of a prefix or regex read.
+func (l *lt) maxResponseSize() int {
+	if l.validator == nil {
+		return DefaultMaxResponseSize
+	}
+	return l.validator.Load().maxResponseSize
 }
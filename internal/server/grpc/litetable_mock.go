@@ -12,7 +12,9 @@ package grpc
 import (
 	net "net"
 	reflect "reflect"
+	time "time"
 
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
 	litetable "github.com/litetable/litetable-db/internal/litetable"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,18 +42,79 @@ func (m *Mockoperations) EXPECT() *MockoperationsMockRecorder {
 	return m.recorder
 }
 
+// Aggregate mocks base method.
+func (m *Mockoperations) Aggregate(query string) ([]*litetable.Aggregate, litetable.Cost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Aggregate", query)
+	ret0, _ := ret[0].([]*litetable.Aggregate)
+	ret1, _ := ret[1].(litetable.Cost)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Aggregate indicates an expected call of Aggregate.
+func (mr *MockoperationsMockRecorder) Aggregate(query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*Mockoperations)(nil).Aggregate), query)
+}
+
+// CloneFamily mocks base method.
+func (m *Mockoperations) CloneFamily(table, source, target string) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloneFamily", table, source, target)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CloneFamily indicates an expected call of CloneFamily.
+func (mr *MockoperationsMockRecorder) CloneFamily(table, source, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneFamily", reflect.TypeOf((*Mockoperations)(nil).CloneFamily), table, source, target)
+}
+
+// Commit mocks base method.
+func (m *Mockoperations) Commit(table string, readAt uint64, reads []string, queries []litetable.MutationQuery) (litetable.CommitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit", table, readAt, reads, queries)
+	ret0, _ := ret[0].(litetable.CommitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockoperationsMockRecorder) Commit(table, readAt, reads, queries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*Mockoperations)(nil).Commit), table, readAt, reads, queries)
+}
+
 // CreateFamilies mocks base method.
-func (m *Mockoperations) CreateFamilies(families []string) error {
+func (m *Mockoperations) CreateFamilies(table string, families []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateFamilies", families)
+	ret := m.ctrl.Call(m, "CreateFamilies", table, families)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateFamilies indicates an expected call of CreateFamilies.
-func (mr *MockoperationsMockRecorder) CreateFamilies(families any) *gomock.Call {
+func (mr *MockoperationsMockRecorder) CreateFamilies(table, families any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFamilies", reflect.TypeOf((*Mockoperations)(nil).CreateFamilies), families)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFamilies", reflect.TypeOf((*Mockoperations)(nil).CreateFamilies), table, families)
+}
+
+// CreateTable mocks base method.
+func (m *Mockoperations) CreateTable(name string, families []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTable", name, families)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTable indicates an expected call of CreateTable.
+func (mr *MockoperationsMockRecorder) CreateTable(name, families any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTable", reflect.TypeOf((*Mockoperations)(nil).CreateTable), name, families)
 }
 
 // Delete mocks base method.
@@ -68,13 +131,147 @@ func (mr *MockoperationsMockRecorder) Delete(query any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Mockoperations)(nil).Delete), query)
 }
 
+// DropTable mocks base method.
+func (m *Mockoperations) DropTable(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropTable", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropTable indicates an expected call of DropTable.
+func (mr *MockoperationsMockRecorder) DropTable(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropTable", reflect.TypeOf((*Mockoperations)(nil).DropTable), name)
+}
+
+// ExportSchema mocks base method.
+func (m *Mockoperations) ExportSchema() (litetable.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportSchema")
+	ret0, _ := ret[0].(litetable.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportSchema indicates an expected call of ExportSchema.
+func (mr *MockoperationsMockRecorder) ExportSchema() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSchema", reflect.TypeOf((*Mockoperations)(nil).ExportSchema))
+}
+
+// Flush mocks base method.
+func (m *Mockoperations) Flush() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockoperationsMockRecorder) Flush() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*Mockoperations)(nil).Flush))
+}
+
+// ImportSchema mocks base method.
+func (m *Mockoperations) ImportSchema(schema litetable.Schema, dryRun bool) ([]litetable.TableSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSchema", schema, dryRun)
+	ret0, _ := ret[0].([]litetable.TableSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportSchema indicates an expected call of ImportSchema.
+func (mr *MockoperationsMockRecorder) ImportSchema(schema, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSchema", reflect.TypeOf((*Mockoperations)(nil).ImportSchema), schema, dryRun)
+}
+
+// ListBackups mocks base method.
+func (m *Mockoperations) ListBackups() ([]*litetable.BackupManifest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBackups")
+	ret0, _ := ret[0].([]*litetable.BackupManifest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBackups indicates an expected call of ListBackups.
+func (mr *MockoperationsMockRecorder) ListBackups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBackups", reflect.TypeOf((*Mockoperations)(nil).ListBackups))
+}
+
+// ListQualifiers mocks base method.
+func (m *Mockoperations) ListQualifiers(query string) ([]litetable.QualifierVersions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQualifiers", query)
+	ret0, _ := ret[0].([]litetable.QualifierVersions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQualifiers indicates an expected call of ListQualifiers.
+func (mr *MockoperationsMockRecorder) ListQualifiers(query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQualifiers", reflect.TypeOf((*Mockoperations)(nil).ListQualifiers), query)
+}
+
+// ListTables mocks base method.
+func (m *Mockoperations) ListTables() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTables")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ListTables indicates an expected call of ListTables.
+func (mr *MockoperationsMockRecorder) ListTables() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTables", reflect.TypeOf((*Mockoperations)(nil).ListTables))
+}
+
+// ListTombstones mocks base method.
+func (m *Mockoperations) ListTombstones(table, prefix string, limit int) ([]litetable.Tombstone, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTombstones", table, prefix, limit)
+	ret0, _ := ret[0].([]litetable.Tombstone)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTombstones indicates an expected call of ListTombstones.
+func (mr *MockoperationsMockRecorder) ListTombstones(table, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTombstones", reflect.TypeOf((*Mockoperations)(nil).ListTombstones), table, prefix, limit)
+}
+
+// LockRow mocks base method.
+func (m *Mockoperations) LockRow(table, rowKey, owner string, ttl time.Duration) (litetable.RowLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockRow", table, rowKey, owner, ttl)
+	ret0, _ := ret[0].(litetable.RowLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockRow indicates an expected call of LockRow.
+func (mr *MockoperationsMockRecorder) LockRow(table, rowKey, owner, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockRow", reflect.TypeOf((*Mockoperations)(nil).LockRow), table, rowKey, owner, ttl)
+}
+
 // Read mocks base method.
-func (m *Mockoperations) Read(query string) (map[string]*litetable.Row, error) {
+func (m *Mockoperations) Read(query string) (map[string]*litetable.Row, litetable.Cost, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Read", query)
 	ret0, _ := ret[0].(map[string]*litetable.Row)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(litetable.Cost)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Read indicates an expected call of Read.
@@ -83,6 +280,50 @@ func (mr *MockoperationsMockRecorder) Read(query any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*Mockoperations)(nil).Read), query)
 }
 
+// Sequence mocks base method.
+func (m *Mockoperations) Sequence(table string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sequence", table)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sequence indicates an expected call of Sequence.
+func (mr *MockoperationsMockRecorder) Sequence(table any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sequence", reflect.TypeOf((*Mockoperations)(nil).Sequence), table)
+}
+
+// TableStats mocks base method.
+func (m *Mockoperations) TableStats(table string) (litetable.TableUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TableStats", table)
+	ret0, _ := ret[0].(litetable.TableUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TableStats indicates an expected call of TableStats.
+func (mr *MockoperationsMockRecorder) TableStats(table any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TableStats", reflect.TypeOf((*Mockoperations)(nil).TableStats), table)
+}
+
+// UnlockRow mocks base method.
+func (m *Mockoperations) UnlockRow(table, rowKey string, token uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockRow", table, rowKey, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockRow indicates an expected call of UnlockRow.
+func (mr *MockoperationsMockRecorder) UnlockRow(table, rowKey, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockRow", reflect.TypeOf((*Mockoperations)(nil).UnlockRow), table, rowKey, token)
+}
+
 // Write mocks base method.
 func (m *Mockoperations) Write(query string) (map[string]*litetable.Row, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +339,156 @@ func (mr *MockoperationsMockRecorder) Write(query any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*Mockoperations)(nil).Write), query)
 }
 
+// Mockwatcher is a mock of watcher interface.
+type Mockwatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockwatcherMockRecorder
+}
+
+// MockwatcherMockRecorder is the mock recorder for Mockwatcher.
+type MockwatcherMockRecorder struct {
+	mock *Mockwatcher
+}
+
+// NewMockwatcher creates a new mock instance.
+func NewMockwatcher(ctrl *gomock.Controller) *Mockwatcher {
+	mock := &Mockwatcher{ctrl: ctrl}
+	mock.recorder = &MockwatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockwatcher) EXPECT() *MockwatcherMockRecorder {
+	return m.recorder
+}
+
+// Unwatch mocks base method.
+func (m *Mockwatcher) Unwatch(w *v1.Watch) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Unwatch", w)
+}
+
+// Unwatch indicates an expected call of Unwatch.
+func (mr *MockwatcherMockRecorder) Unwatch(w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unwatch", reflect.TypeOf((*Mockwatcher)(nil).Unwatch), w)
+}
+
+// Watch mocks base method.
+func (m *Mockwatcher) Watch(rowKey, prefix string) (*v1.Watch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", rowKey, prefix)
+	ret0, _ := ret[0].(*v1.Watch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockwatcherMockRecorder) Watch(rowKey, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*Mockwatcher)(nil).Watch), rowKey, prefix)
+}
+
+// MockcdcSubscribers is a mock of cdcSubscribers interface.
+type MockcdcSubscribers struct {
+	ctrl     *gomock.Controller
+	recorder *MockcdcSubscribersMockRecorder
+}
+
+// MockcdcSubscribersMockRecorder is the mock recorder for MockcdcSubscribers.
+type MockcdcSubscribersMockRecorder struct {
+	mock *MockcdcSubscribers
+}
+
+// NewMockcdcSubscribers creates a new mock instance.
+func NewMockcdcSubscribers(ctrl *gomock.Controller) *MockcdcSubscribers {
+	mock := &MockcdcSubscribers{ctrl: ctrl}
+	mock.recorder = &MockcdcSubscribersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcdcSubscribers) EXPECT() *MockcdcSubscribersMockRecorder {
+	return m.recorder
+}
+
+// Kick mocks base method.
+func (m *MockcdcSubscribers) Kick(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Kick", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Kick indicates an expected call of Kick.
+func (mr *MockcdcSubscribersMockRecorder) Kick(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Kick", reflect.TypeOf((*MockcdcSubscribers)(nil).Kick), id)
+}
+
+// Sequence mocks base method.
+func (m *MockcdcSubscribers) Sequence() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sequence")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// Sequence indicates an expected call of Sequence.
+func (mr *MockcdcSubscribersMockRecorder) Sequence() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sequence", reflect.TypeOf((*MockcdcSubscribers)(nil).Sequence))
+}
+
+// Subscribers mocks base method.
+func (m *MockcdcSubscribers) Subscribers() []v1.Subscriber {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribers")
+	ret0, _ := ret[0].([]v1.Subscriber)
+	return ret0
+}
+
+// Subscribers indicates an expected call of Subscribers.
+func (mr *MockcdcSubscribersMockRecorder) Subscribers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribers", reflect.TypeOf((*MockcdcSubscribers)(nil).Subscribers))
+}
+
+// Mockrecorder is a mock of recorder interface.
+type Mockrecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockrecorderMockRecorder
+}
+
+// MockrecorderMockRecorder is the mock recorder for Mockrecorder.
+type MockrecorderMockRecorder struct {
+	mock *Mockrecorder
+}
+
+// NewMockrecorder creates a new mock instance.
+func NewMockrecorder(ctrl *gomock.Controller) *Mockrecorder {
+	mock := &Mockrecorder{ctrl: ctrl}
+	mock.recorder = &MockrecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrecorder) EXPECT() *MockrecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *Mockrecorder) Record(req any) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", req)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockrecorderMockRecorder) Record(req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*Mockrecorder)(nil).Record), req)
+}
+
 // MockgrpcServer is a mock of grpcServer interface.
 type MockgrpcServer struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// LockRow takes or renews an advisory lease on a row.
+func (l *lt) LockRow(ctx context.Context, msg *proto.LockRowRequest) (*proto.LockRowResponse,
+	error) {
+	start := time.Now()
+	if msg.GetRowKey() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "rowKey required")
+	}
+	if msg.GetOwner() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "owner required")
+	}
+
+	ttl := time.Duration(msg.GetTtl()) * time.Second
+	lease, err := l.operations.LockRow(msg.GetTable(), msg.GetRowKey(), msg.GetOwner(), ttl)
+	if err != nil {
+		return nil, toStatus(err, "failed to lock row")
+	}
+	requestid.Logger(ctx).Debug().Msgf("LockRow successful: %v", time.Since(start))
+	return &proto.LockRowResponse{
+		FencingToken:  lease.Token,
+		ExpiresAtUnix: lease.ExpiresAt,
+	}, nil
+}
+
+// UnlockRow releases a row lease before it expires.
+func (l *lt) UnlockRow(ctx context.Context, msg *proto.UnlockRowRequest) (*proto.Empty, error) {
+	start := time.Now()
+	if msg.GetRowKey() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "rowKey required")
+	}
+
+	err := l.operations.UnlockRow(msg.GetTable(), msg.GetRowKey(), msg.GetFencingToken())
+	if err != nil {
+		return nil, toStatus(err, "failed to unlock row")
+	}
+	requestid.Logger(ctx).Debug().Msgf("UnlockRow successful: %v", time.Since(start))
+	return &proto.Empty{}, nil
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestLt_LockRow(t *testing.T) {
+	tests := map[string]struct {
+		request      *proto.LockRowRequest
+		mockSetup    func(m *Mockoperations)
+		expected     *proto.LockRowResponse
+		expectedCode codes.Code
+	}{
+		"missing row key": {
+			request:      &proto.LockRowRequest{Owner: "worker-1"},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"missing owner": {
+			request:      &proto.LockRowRequest{RowKey: "champ:1"},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"locked by another owner": {
+			request: &proto.LockRowRequest{RowKey: "champ:1", Owner: "worker-2"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().LockRow("", "champ:1", "worker-2", time.Duration(0)).Return(
+					litetable.RowLease{},
+					litetable.NewError(litetable.ErrorCodeConflict, "row champ:1 is locked"))
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		"locked": {
+			request: &proto.LockRowRequest{
+				RowKey: "champ:1",
+				Owner:  "worker-1",
+				Ttl:    10,
+				Table:  "wwe",
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().LockRow("wwe", "champ:1", "worker-1", 10*time.Second).Return(
+					litetable.RowLease{Token: 7, ExpiresAt: 1000}, nil)
+			},
+			expected:     &proto.LockRowResponse{FencingToken: 7, ExpiresAtUnix: 1000},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.LockRow(context.Background(), tc.request)
+			if tc.expectedCode == codes.OK {
+				req.NoError(err)
+				req.Equal(tc.expected.GetFencingToken(), resp.GetFencingToken())
+				req.Equal(tc.expected.GetExpiresAtUnix(), resp.GetExpiresAtUnix())
+				return
+			}
+			req.Nil(resp)
+			req.Equal(tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestLt_UnlockRow(t *testing.T) {
+	tests := map[string]struct {
+		request      *proto.UnlockRowRequest
+		mockSetup    func(m *Mockoperations)
+		expectedCode codes.Code
+	}{
+		"missing row key": {
+			request:      &proto.UnlockRowRequest{FencingToken: 7},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"not locked": {
+			request: &proto.UnlockRowRequest{RowKey: "champ:1", FencingToken: 7},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().UnlockRow("", "champ:1", uint64(7)).Return(
+					litetable.NewError(litetable.ErrorCodeNotFound, "row champ:1 is not locked"))
+			},
+			expectedCode: codes.NotFound,
+		},
+		"unlocked": {
+			request: &proto.UnlockRowRequest{RowKey: "champ:1", FencingToken: 7, Table: "wwe"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().UnlockRow("wwe", "champ:1", uint64(7)).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.UnlockRow(context.Background(), tc.request)
+			if tc.expectedCode == codes.OK {
+				req.NoError(err)
+				req.NotNil(resp)
+				return
+			}
+			req.Nil(resp)
+			req.Equal(tc.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/rs/zerolog"
+	grpc2 "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// loggingInterceptor assigns each call a request ID, taken from the incoming metadata when the
+// client supplies one, returns it in the response header, and logs the outcome of the call.
+func loggingInterceptor(ctx context.Context, req any, info *grpc2.UnaryServerInfo,
+	handler grpc2.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestid.Header); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	id = requestid.Resolve(id)
+	ctx = requestid.NewContext(ctx, id)
+
+	// the header can only fail to send on a closed stream, which the handler will report
+	_ = grpc2.SetHeader(ctx, metadata.Pairs(requestid.Header, id))
+
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	event := requestid.Logger(ctx).WithLevel(grpcLogLevel(err))
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event = event.Str("peer", p.Addr.String())
+	}
+	event.
+		Str("method", info.FullMethod).
+		Str("code", code.String()).
+		Dur("duration", time.Since(start)).
+		Err(err).
+		Msg("gRPC request")
+
+	return resp, err
+}
+
+// grpcLogLevel raises the level of failed calls so server faults stand out from bad requests.
+func grpcLogLevel(err error) zerolog.Level {
+	if err == nil {
+		return zerolog.InfoLevel
+	}
+	switch status.Code(err) {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		return zerolog.ErrorLevel
+	default:
+		return zerolog.WarnLevel
+	}
+}
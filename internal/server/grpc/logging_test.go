@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	grpc2 "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestLoggingInterceptor(t *testing.T) {
+	info := &grpc2.UnaryServerInfo{FullMethod: "/litetable.LitetableService/Read"}
+
+	t.Run("propagates the caller id", func(t *testing.T) {
+		req := require.New(t)
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(requestid.Header, "abc-123"))
+
+		var seen string
+		_, err := loggingInterceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+			seen = requestid.FromContext(ctx)
+			return nil, nil
+		})
+		req.NoError(err)
+		req.Equal("abc-123", seen)
+	})
+
+	t.Run("generates an id and returns the handler error", func(t *testing.T) {
+		req := require.New(t)
+		handlerErr := status.Error(codes.NotFound, "nope")
+
+		var seen string
+		_, err := loggingInterceptor(context.Background(), nil, info,
+			func(ctx context.Context, _ any) (any, error) {
+				seen = requestid.FromContext(ctx)
+				return nil, handlerErr
+			})
+		req.Equal(handlerErr, err)
+		req.NotEmpty(seen)
+	})
+}
+
+func TestGRPCLogLevel(t *testing.T) {
+	req := require.New(t)
+	req.Equal(zerolog.InfoLevel, grpcLogLevel(nil))
+	req.Equal(zerolog.WarnLevel, grpcLogLevel(status.Error(codes.InvalidArgument, "bad")))
+	req.Equal(zerolog.ErrorLevel, grpcLogLevel(status.Error(codes.Internal, "boom")))
+}
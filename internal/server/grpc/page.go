@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"encoding/base64"
+	litetable2 "github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	protobuf "google.golang.org/protobuf/proto"
+	"maps"
+	"slices"
+)
+
+// DefaultMaxResponseSize is the largest encoded size of the rows of a prefix or regex read when
+// Limits.MaxResponseSize is not set. It is the largest message grpc-go clients receive by
+// default.
+const DefaultMaxResponseSize = 4 << 20 // 4MB
+
+// EncodePageToken returns the page token of a read resuming after a row key. Clients treat the
+// token as opaque.
+func EncodePageToken(rowKey string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(rowKey))
+}
+
+// DecodePageToken returns the row key a page token resumes after.
+func DecodePageToken(token string) (string, error) {
+	rowKey, err := base64.RawURLEncoding.DecodeString(token)
+	return string(rowKey), err
+}
+
+// convertToPagedProtoData converts the rows of a prefix or regex read in row key order, until
+// their encoded size would pass maxSize. The rows left out are flagged with truncated and a next
+// page token. The first row is always kept, so every page makes progress.
+func convertToPagedProtoData(rows map[string]*litetable2.Row, ordered bool,
+	maxSize int) *proto.LitetableData {
+	protoData := &proto.LitetableData{}
+	if !ordered {
+		protoData.Rows = make(map[string]*proto.Row)
+	}
+
+	var size int
+	var last string
+	for _, rowKey := range slices.Sorted(maps.Keys(rows)) {
+		// a row on its own is sized like it is in the response, as the fields concatenate
+		var rowSize int
+		var add func()
+		if ordered {
+			entry := convertToRowEntry(rows[rowKey])
+			rowSize = protobuf.Size(&proto.LitetableData{Entries: []*proto.RowEntry{entry}})
+			add = func() { protoData.Entries = append(protoData.Entries, entry) }
+		} else {
+			row := convertToProtoRow(rows[rowKey])
+			rowSize = protobuf.Size(&proto.LitetableData{Rows: map[string]*proto.Row{
+				rowKey: row,
+			}})
+			add = func() { protoData.Rows[rowKey] = row }
+		}
+
+		if last != "" && size+rowSize > maxSize {
+			protoData.Truncated = true
+			protoData.NextPageToken = EncodePageToken(last)
+			break
+		}
+		add()
+		size += rowSize
+		last = rowKey
+	}
+
+	return protoData
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"fmt"
+	litetable2 "github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	protobuf "google.golang.org/protobuf/proto"
+	"strings"
+	"testing"
+)
+
+func TestConvertToPagedProtoData(t *testing.T) {
+	rows := make(map[string]*litetable2.Row)
+	for i := range 10 {
+		key := fmt.Sprintf("champ:%d", i)
+		rows[key] = &litetable2.Row{Key: key, Columns: map[string]litetable2.VersionedQualifier{
+			"main": {"bio": {{Value: []byte(strings.Repeat("a", 100)), Timestamp: 1}}},
+		}}
+	}
+	whole := protobuf.Size(convertToProtoData(rows))
+
+	tests := map[string]struct {
+		ordered  bool
+		maxSize  int
+		wantRows int
+	}{
+		"fits": {
+			maxSize:  whole,
+			wantRows: 10,
+		},
+		"truncated": {
+			maxSize:  whole / 2,
+			wantRows: 5,
+		},
+		"truncated ordered": {
+			ordered:  true,
+			maxSize:  whole / 2,
+			wantRows: 5,
+		},
+		"a row larger than the limit is returned on its own": {
+			maxSize:  1,
+			wantRows: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			data := convertToPagedProtoData(rows, tc.ordered, tc.maxSize)
+
+			var keys []string
+			for key := range data.GetRows() {
+				keys = append(keys, key)
+			}
+			for _, entry := range data.GetEntries() {
+				keys = append(keys, entry.GetKey())
+			}
+			req.Len(keys, tc.wantRows)
+			req.Equal(tc.wantRows < len(rows), data.GetTruncated())
+			if tc.wantRows == len(rows) {
+				req.Empty(data.GetNextPageToken())
+				return
+			}
+
+			// the rows are the first ones by key, and the token resumes after the last of them
+			for i := range tc.wantRows {
+				req.Contains(keys, fmt.Sprintf("champ:%d", i))
+			}
+			if tc.wantRows > 1 {
+				req.LessOrEqual(protobuf.Size(&proto.LitetableData{
+					Rows: data.GetRows(), Entries: data.GetEntries(),
+				}), tc.maxSize)
+			}
+			after, err := DecodePageToken(data.GetNextPageToken())
+			req.NoError(err)
+			req.Equal(fmt.Sprintf("champ:%d", tc.wantRows-1), after)
+		})
+	}
+}
+
+func TestDecodePageToken(t *testing.T) {
+	req := require.New(t)
+	rowKey, err := DecodePageToken(EncodePageToken("champ:1"))
+	req.NoError(err)
+	req.Equal("champ:1", rowKey)
+
+	_, err = DecodePageToken("champ:1")
+	req.Error(err)
+}
@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/pkg/proto"
+)
+
+// ListQualifiers returns the qualifiers of a row's family and their version counts.
+func (l *lt) ListQualifiers(_ context.Context, msg *proto.ListQualifiersRequest) (
+	*proto.ListQualifiersResponse, error) {
+	read := &proto.ReadRequest{
+		RowKey:            msg.GetRowKey(),
+		Family:            msg.GetFamily(),
+		Table:             msg.GetTable(),
+		ReadAt:            msg.GetReadAt(),
+		IncludeTombstones: msg.GetIncludeTombstones(),
+	}
+	if err := l.validateRead(read); err != nil {
+		return nil, err
+	}
+
+	qualifiers, err := l.operations.ListQualifiers(readQuery(read))
+	if err != nil {
+		return nil, toStatus(err, "failed to list qualifiers")
+	}
+
+	resp := &proto.ListQualifiersResponse{
+		Qualifiers: make([]*proto.QualifierVersions, 0, len(qualifiers)),
+	}
+	for _, q := range qualifiers {
+		resp.Qualifiers = append(resp.Qualifiers, &proto.QualifierVersions{
+			Name:     q.Name,
+			Versions: q.Versions,
+		})
+	}
+	return resp, nil
+}
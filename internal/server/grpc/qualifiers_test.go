@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestLt_ListQualifiers(t *testing.T) {
+	req := require.New(t)
+	mockOps := NewMockoperations(gomock.NewController(t))
+	mockOps.EXPECT().ListQualifiers("v2:family=wrestlers key=champ:1 table=wwe readAt=3").Return(
+		[]litetable.QualifierVersions{{Name: "name", Versions: 2}}, nil)
+	mockOps.EXPECT().ListQualifiers("v2:family=nope key=champ:1").Return(nil,
+		litetable.NewError(litetable.ErrorCodeFamilyMissing, "column family does not exist"))
+
+	svc := &lt{operations: mockOps}
+	resp, err := svc.ListQualifiers(context.Background(), &proto.ListQualifiersRequest{
+		RowKey: "champ:1",
+		Family: "wrestlers",
+		Table:  "wwe",
+		ReadAt: 3,
+	})
+	req.NoError(err)
+	req.Len(resp.GetQualifiers(), 1)
+	req.Equal("name", resp.GetQualifiers()[0].GetName())
+	req.Equal(uint64(2), resp.GetQualifiers()[0].GetVersions())
+
+	_, err = svc.ListQualifiers(context.Background(), &proto.ListQualifiersRequest{
+		RowKey: "champ:1",
+		Family: "nope",
+	})
+	req.Equal(codes.FailedPrecondition, status.Code(err))
+
+	_, err = svc.ListQualifiers(context.Background(), &proto.ListQualifiersRequest{
+		Family: "wrestlers",
+	})
+	req.Equal(codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"strings"
+	"unicode"
+)
+
+// queryVersion prefixes every query the server builds. Version 2 of the text query grammar
+// quotes and escapes values, so keys, values and patterns reach the parser as they were sent.
+const queryVersion = "v2:"
+
+// queryEscaper escapes the characters that are special inside a quoted value.
+var queryEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// queryValue writes a value of a version 2 query. A value holding whitespace, a quote or a
+// backslash is quoted, as the grammar would otherwise split it or read an escape in it.
+func queryValue(value string) string {
+	special := strings.ContainsFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '\\'
+	})
+	if !special {
+		return value
+	}
+	return `"` + queryEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	operations2 "github.com/litetable/litetable-db/internal/operations"
+	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/litetable/litetable-db/internal/shard_storage/wal"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type fakeCDC struct{}
+
+func (fakeCDC) Emit(*v1.CDCEvent) {}
+
+func (fakeCDC) Spill(*v1.CDCEvent) bool { return false }
+
+func TestQueryValue(t *testing.T) {
+	req := require.New(t)
+	req.Equal("champ:1", queryValue("champ:1"))
+	req.Equal("a=b", queryValue("a=b"))
+	req.Equal(`"a b"`, queryValue("a b"))
+	req.Equal(`"Jinx \"the loose cannon\""`, queryValue(`Jinx "the loose cannon"`))
+	req.Equal(`"^champ:\\d"`, queryValue(`^champ:\d`))
+}
+
+// TestQueries_roundTrip sends the queries the server builds through the query parser, so keys,
+// values and patterns with whitespace, quotes, backslashes or = come back as they were sent.
+func TestQueries_roundTrip(t *testing.T) {
+	req := require.New(t)
+	dir := t.TempDir()
+	storage, _, err := shard_storage.New(&shard_storage.Config{
+		RootDir:        dir,
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+	})
+	req.NoError(err)
+	req.NoError(storage.UpdateFamilies([]string{"main"}))
+	writeAhead, err := wal.New(&wal.Config{Path: dir})
+	req.NoError(err)
+	ops, err := operations2.New(&operations2.Config{WAL: writeAhead, ShardStorage: storage})
+	req.NoError(err)
+	l := &lt{operations: ops}
+	ctx := context.Background()
+
+	value := []byte("Jinx \"the loose cannon\" \\ key=champ:2\n")
+	_, err = l.Write(ctx, &proto.WriteRequest{
+		RowKey: "champ=1",
+		Family: "main",
+		Qualifiers: []*proto.ColumnQualifier{
+			{Name: `lore\"`, Value: value},
+		},
+	})
+	req.NoError(err)
+	_, err = l.Write(ctx, &proto.WriteRequest{
+		RowKey:     "champ=2",
+		Family:     "main",
+		Qualifiers: []*proto.ColumnQualifier{{Name: "name", Value: []byte("Vi")}},
+	})
+	req.NoError(err)
+
+	read := func(msg *proto.ReadRequest) map[string]*proto.Row {
+		data, err := l.Read(ctx, msg)
+		req.NoError(err)
+		return data.GetRows()
+	}
+
+	rows := read(&proto.ReadRequest{RowKey: "champ=1", Family: "main"})
+	req.Len(rows, 1)
+	lore := rows["champ=1"].GetCols()["main"].GetQualifiers()[`lore\"`]
+	req.Equal(value, lore.GetValues()[0].GetValue())
+
+	// a pattern with a space and a backslash escape matches as it was sent
+	rows = read(&proto.ReadRequest{
+		RowKey: `^champ=\d$|no such row`, Family: "main", QueryType: proto.QueryType_REGEX,
+	})
+	req.Len(rows, 2)
+
+	_, err = l.Delete(ctx, &proto.DeleteRequest{RowKey: "champ=2", Family: "main"})
+	req.NoError(err)
+	rows = read(&proto.ReadRequest{
+		RowKey: "champ=", Family: "main", QueryType: proto.QueryType_PREFIX,
+	})
+	req.Len(rows, 1)
+}
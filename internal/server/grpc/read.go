@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/litetable/litetable-db/internal/requestid"
 	"github.com/litetable/litetable-db/pkg/proto"
-	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"strconv"
 	"time"
 )
 
@@ -26,28 +27,60 @@ func (l *lt) validateRead(msg *proto.ReadRequest) error {
 func (l *lt) Read(ctx context.Context, msg *proto.ReadRequest) (*proto.LitetableData,
 	error) {
 	now := time.Now()
-	log.Debug().Msgf("Read request: %v", msg)
+	requestid.Logger(ctx).Debug().Msgf("Read request: %v", msg)
 	if err := l.validateRead(msg); err != nil {
 		return nil, err
 	}
 
-	// Ex: READ family="family" rowKey="rowKey" qualifier="qualifier" latest=5
-	queryStr := "family=" + msg.GetFamily()
+	query := readQuery(msg)
+	if msg.GetPageToken() != "" {
+		after, err := DecodePageToken(msg.GetPageToken())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token")
+		}
+		query += " after=" + queryValue(after)
+	}
+	// the scan stops once it has read a response worth of rows, rather than reading every match
+	// for a page of them
+	if msg.GetQueryType() != proto.QueryType_EXACT {
+		query += " pageBytes=" + strconv.Itoa(l.maxResponseSize())
+	}
+
+	result, cost, err := l.operations.Read(query)
+	addCost(ctx, cost)
+	if err != nil {
+		return nil, toStatus(err, "failed to read data")
+	}
+
+	requestid.Logger(ctx).Debug().Msgf("Read latency: %v", time.Since(now))
+	if msg.GetQueryType() != proto.QueryType_EXACT {
+		return convertToPagedProtoData(result, msg.GetOrdered(), l.maxResponseSize()), nil
+	}
+	if msg.GetOrdered() {
+		return convertToOrderedProtoData(result), nil
+	}
+	return convertToProtoData(result), nil
+}
+
+// readQuery builds the read query for a read request.
+func readQuery(msg *proto.ReadRequest) string {
+	// Ex: v2:family=family key=rowKey qualifier=qualifier latest=5
+	queryStr := queryVersion + "family=" + queryValue(msg.GetFamily())
 	if msg.GetQueryType() == proto.QueryType_EXACT {
-		queryStr += " key=" + msg.GetRowKey()
+		queryStr += " key=" + queryValue(msg.GetRowKey())
 	}
 
 	if msg.GetQueryType() == proto.QueryType_PREFIX {
-		queryStr += " prefix=" + msg.GetRowKey()
+		queryStr += " prefix=" + queryValue(msg.GetRowKey())
 	}
 
 	if msg.GetQueryType() == proto.QueryType_REGEX {
-		queryStr += " regex=" + msg.GetRowKey()
+		queryStr += " regex=" + queryValue(msg.GetRowKey())
 	}
 
 	if len(msg.GetQualifiers()) > 0 {
 		for _, qualifier := range msg.GetQualifiers() {
-			queryStr += " qualifier=" + qualifier
+			queryStr += " qualifier=" + queryValue(qualifier)
 		}
 	}
 
@@ -55,11 +88,33 @@ func (l *lt) Read(ctx context.Context, msg *proto.ReadRequest) (*proto.Litetable
 		queryStr += fmt.Sprintf(" latest=%d", msg.GetLatest())
 	}
 
-	result, err := l.operations.Read(queryStr)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to read data: %v", err)
+	if msg.GetIncludeTombstones() {
+		queryStr += " includeTombstones=true"
 	}
 
-	log.Debug().Msgf("Read latency: %v", time.Since(now))
-	return convertToProtoData(result), nil
+	if msg.GetTable() != "" {
+		queryStr += " table=" + queryValue(msg.GetTable())
+	}
+
+	if msg.GetReadAt() > 0 {
+		queryStr += fmt.Sprintf(" readAt=%d", msg.GetReadAt())
+	}
+
+	if msg.GetAsOfUnix() > 0 {
+		queryStr += fmt.Sprintf(" asOf=%d", msg.GetAsOfUnix())
+	}
+
+	for _, path := range msg.GetJsonPaths() {
+		queryStr += " jsonPath=" + queryValue(path)
+	}
+
+	if msg.GetOrder() == proto.Order_ASC {
+		queryStr += " order=asc"
+	}
+
+	if msg.GetConsistency() == proto.Consistency_EVENTUAL {
+		queryStr += " consistency=eventual"
+	}
+
+	return queryStr
 }
@@ -34,11 +34,11 @@ func TestLt_Read(t *testing.T) {
 				RowKey:    "key1",
 				QueryType: proto.QueryType_EXACT,
 			},
-			expectedQuery: "family=fam key=key1",
+			expectedQuery: "v2:family=fam key=key1",
 			mockSetup: func(m *Mockoperations) {
 				m.EXPECT().
-					Read("family=fam key=key1").
-					Return(nil, errors.New("boom"))
+					Read("v2:family=fam key=key1").
+					Return(nil, litetable2.Cost{}, errors.New("boom"))
 			},
 			expectedCode:    codes.Internal,
 			expectedMessage: "failed to read data: boom",
@@ -51,10 +51,11 @@ func TestLt_Read(t *testing.T) {
 				Qualifiers: []string{"a", "b"},
 				Latest:     2,
 			},
-			expectedQuery: "family=fam prefix=r1 qualifier=a qualifier=b latest=2",
+			expectedQuery: "v2:family=fam prefix=r1 qualifier=a qualifier=b latest=2 " +
+				"pageBytes=4194304",
 			mockSetup: func(m *Mockoperations) {
 				m.EXPECT().
-					Read("family=fam prefix=r1 qualifier=a qualifier=b latest=2").
+					Read("v2:family=fam prefix=r1 qualifier=a qualifier=b latest=2 pageBytes=4194304").
 					Return(map[string]*litetable2.Row{
 						"r1": {
 							Key: "r1",
@@ -64,11 +65,129 @@ func TestLt_Read(t *testing.T) {
 								},
 							},
 						},
-					}, nil)
+					}, litetable2.Cost{RowsScanned: 1, CellsCopied: 1}, nil)
 			},
 			expectedCode:    codes.OK,
 			expectedMessage: "",
 		},
+		"tombstones are requested in the query": {
+			request: &proto.ReadRequest{
+				Family:            "fam",
+				RowKey:            "r1",
+				IncludeTombstones: true,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Read("v2:family=fam key=r1 includeTombstones=true").
+					Return(map[string]*litetable2.Row{"r1": {Key: "r1"}}, litetable2.Cost{}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"read_at is passed to the query": {
+			request: &proto.ReadRequest{
+				Family:    "fam",
+				RowKey:    "r",
+				QueryType: proto.QueryType_PREFIX,
+				ReadAt:    42,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Read("v2:family=fam prefix=r readAt=42 pageBytes=4194304").
+					Return(map[string]*litetable2.Row{"r1": {Key: "r1"}}, litetable2.Cost{}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"as_of_unix is passed to the query": {
+			request: &proto.ReadRequest{
+				Family:   "fam",
+				RowKey:   "r",
+				AsOfUnix: 1700000000000000000,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Read("v2:family=fam key=r asOf=1700000000000000000").
+					Return(map[string]*litetable2.Row{"r1": {Key: "r1"}}, litetable2.Cost{}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"json_paths are passed to the query": {
+			request: &proto.ReadRequest{
+				Family:    "fam",
+				RowKey:    "r",
+				JsonPaths: []string{"name", "region.name"},
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Read("v2:family=fam key=r jsonPath=name jsonPath=region.name").
+					Return(map[string]*litetable2.Row{"r1": {Key: "r1"}}, litetable2.Cost{}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"ordered read": {
+			request: &proto.ReadRequest{
+				Family:    "fam",
+				RowKey:    "r",
+				QueryType: proto.QueryType_PREFIX,
+				Ordered:   true,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Read("v2:family=fam prefix=r pageBytes=4194304").
+					Return(map[string]*litetable2.Row{"r1": {Key: "r1"}}, litetable2.Cost{}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"page token resumes after its row": {
+			request: &proto.ReadRequest{
+				Family:    "fam",
+				RowKey:    "r",
+				QueryType: proto.QueryType_PREFIX,
+				PageToken: EncodePageToken("r0"),
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Read("v2:family=fam prefix=r after=r0 pageBytes=4194304").
+					Return(map[string]*litetable2.Row{"r1": {Key: "r1"}}, litetable2.Cost{}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"invalid page token": {
+			request: &proto.ReadRequest{
+				Family:    "fam",
+				RowKey:    "r",
+				QueryType: proto.QueryType_PREFIX,
+				PageToken: "not base64!",
+			},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "invalid page token",
+		},
+		"ascending order is passed to the query": {
+			request: &proto.ReadRequest{
+				Family: "fam",
+				RowKey: "r1",
+				Order:  proto.Order_ASC,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Read("v2:family=fam key=r1 order=asc").
+					Return(map[string]*litetable2.Row{"r1": {Key: "r1"}}, litetable2.Cost{}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"eventual consistency is passed to the query": {
+			request: &proto.ReadRequest{
+				Family:      "fam",
+				RowKey:      "r",
+				QueryType:   proto.QueryType_PREFIX,
+				Consistency: proto.Consistency_EVENTUAL,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Read("v2:family=fam prefix=r consistency=eventual pageBytes=4194304").
+					Return(map[string]*litetable2.Row{"r1": {Key: "r1"}}, litetable2.Cost{}, nil)
+			},
+			expectedCode: codes.OK,
+		},
 	}
 
 	for name, tc := range tests {
@@ -92,6 +211,12 @@ func TestLt_Read(t *testing.T) {
 			if tc.expectedCode == codes.OK {
 				req.NoError(err)
 				req.NotNil(resp)
+				if tc.request.GetOrdered() {
+					req.Empty(resp.GetRows())
+					req.Len(resp.GetEntries(), 1)
+					req.Equal("r1", resp.GetEntries()[0].GetKey())
+					return
+				}
 				row, ok := resp.Rows["r1"]
 				req.True(ok)
 				req.Equal("r1", row.Key)
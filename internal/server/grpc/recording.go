@@ -0,0 +1,16 @@
+package grpc
+
+import (
+	"context"
+	grpc2 "google.golang.org/grpc"
+)
+
+// recordingInterceptor hands every request that passed validation to the recorder before it is
+// handled, so the recording keeps the order requests arrived in.
+func recordingInterceptor(r recorder) grpc2.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc2.UnaryServerInfo,
+		handler grpc2.UnaryHandler) (any, error) {
+		r.Record(req)
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	grpc2 "google.golang.org/grpc"
+	"testing"
+)
+
+func TestRecordingInterceptor(t *testing.T) {
+	req := require.New(t)
+	msg := &proto.ReadRequest{RowKey: "champ:1", Family: "main"}
+	r := NewMockrecorder(gomock.NewController(t))
+	r.EXPECT().Record(msg)
+
+	info := &grpc2.UnaryServerInfo{FullMethod: "/litetable.LitetableService/Read"}
+	resp, err := recordingInterceptor(r)(context.Background(), msg, info,
+		func(ctx context.Context, _ any) (any, error) {
+			return "ok", nil
+		})
+	req.NoError(err)
+	req.Equal("ok", resp)
+}
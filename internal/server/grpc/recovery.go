@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"github.com/litetable/litetable-db/internal/requestid"
+	grpc2 "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+)
+
+var handlerPanics = metrics.NewCounterVec("litetable_grpc_handler_panics_total",
+	"gRPC handler panics recovered by the server.", "method")
+
+// recoveryInterceptor turns a panic in a handler into an Internal error so one bad request
+// cannot take the server down.
+func recoveryInterceptor(ctx context.Context, req any, info *grpc2.UnaryServerInfo,
+	handler grpc2.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			handlerPanics.With(info.FullMethod).Inc()
+			requestid.Logger(ctx).Error().
+				Str("method", info.FullMethod).
+				Interface("panic", r).
+				Bytes("stack", debug.Stack()).
+				Msg("recovered panic in gRPC handler")
+
+			resp = nil
+			err = status.Errorf(codes.Internal, "internal error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	grpc2 "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestRecoveryInterceptor(t *testing.T) {
+	info := &grpc2.UnaryServerInfo{FullMethod: "/litetable.LitetableService/Write"}
+
+	t.Run("recovers a panic", func(t *testing.T) {
+		req := require.New(t)
+		before := handlerPanics.With(info.FullMethod).Value()
+
+		resp, err := recoveryInterceptor(context.Background(), nil, info,
+			func(ctx context.Context, _ any) (any, error) {
+				panic("boom")
+			})
+		req.Nil(resp)
+		req.Equal(codes.Internal, status.Code(err))
+		req.Equal(before+1, handlerPanics.With(info.FullMethod).Value())
+	})
+
+	t.Run("passes through", func(t *testing.T) {
+		req := require.New(t)
+
+		resp, err := recoveryInterceptor(context.Background(), nil, info,
+			func(ctx context.Context, _ any) (any, error) {
+				return "ok", nil
+			})
+		req.NoError(err)
+		req.Equal("ok", resp)
+	})
+}
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"time"
+)
+
+// ExportSchema returns every table with its column families.
+func (l *lt) ExportSchema(_ context.Context, _ *proto.Empty) (*proto.Schema, error) {
+	schema, err := l.operations.ExportSchema()
+	if err != nil {
+		return nil, toStatus(err, "failed to export schema")
+	}
+	return &proto.Schema{
+		Version: int32(schema.Version),
+		Tables:  toProtoTableSchemas(schema.Tables),
+	}, nil
+}
+
+// ImportSchema creates the tables and column families of a schema that do not exist yet.
+func (l *lt) ImportSchema(ctx context.Context,
+	msg *proto.ImportSchemaRequest) (*proto.ImportSchemaResponse, error) {
+	start := time.Now()
+	schema := litetable.Schema{Version: int(msg.GetSchema().GetVersion())}
+	for _, table := range msg.GetSchema().GetTables() {
+		schema.Tables = append(schema.Tables, litetable.TableSchema{
+			Name:     table.GetName(),
+			Families: table.GetFamilies(),
+		})
+	}
+
+	created, err := l.operations.ImportSchema(schema, msg.GetDryRun())
+	if err != nil {
+		return nil, toStatus(err, "failed to import schema")
+	}
+	requestid.Logger(ctx).Debug().Msgf("ImportSchema successful: %v", time.Since(start))
+	return &proto.ImportSchemaResponse{Created: toProtoTableSchemas(created)}, nil
+}
+
+func toProtoTableSchemas(tables []litetable.TableSchema) []*proto.TableSchema {
+	out := make([]*proto.TableSchema, 0, len(tables))
+	for _, table := range tables {
+		out = append(out, &proto.TableSchema{
+			Name:     table.Name,
+			Families: table.Families,
+		})
+	}
+	return out
+}
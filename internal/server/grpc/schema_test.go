@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestLt_ExportSchema(t *testing.T) {
+	req := require.New(t)
+	mockOps := NewMockoperations(gomock.NewController(t))
+	mockOps.EXPECT().ExportSchema().Return(litetable.Schema{
+		Version: 1,
+		Tables: []litetable.TableSchema{
+			{Name: "default", Families: []string{"main"}},
+			{Name: "wwe", Families: []string{"wrestlers"}},
+		},
+	}, nil)
+
+	svc := &lt{operations: mockOps}
+	resp, err := svc.ExportSchema(context.Background(), &proto.Empty{})
+	req.NoError(err)
+	req.Equal(int32(1), resp.GetVersion())
+	req.Len(resp.GetTables(), 2)
+	req.Equal("wwe", resp.GetTables()[1].GetName())
+	req.Equal([]string{"wrestlers"}, resp.GetTables()[1].GetFamilies())
+}
+
+func TestLt_ImportSchema(t *testing.T) {
+	tests := map[string]struct {
+		request      *proto.ImportSchemaRequest
+		mockSetup    func(m *Mockoperations)
+		expected     []string
+		expectedCode codes.Code
+	}{
+		"created": {
+			request: &proto.ImportSchemaRequest{Schema: &proto.Schema{
+				Version: 1,
+				Tables:  []*proto.TableSchema{{Name: "wwe", Families: []string{"wrestlers"}}},
+			}, DryRun: true},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().ImportSchema(litetable.Schema{
+					Version: 1,
+					Tables: []litetable.TableSchema{
+						{Name: "wwe", Families: []string{"wrestlers"}},
+					},
+				}, true).Return([]litetable.TableSchema{
+					{Name: "wwe", Families: []string{"wrestlers"}},
+				}, nil)
+			},
+			expected:     []string{"wwe"},
+			expectedCode: codes.OK,
+		},
+		"unsupported version": {
+			request: &proto.ImportSchemaRequest{Schema: &proto.Schema{Version: 2}},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().ImportSchema(litetable.Schema{Version: 2}, false).Return(nil,
+					litetable.NewError(litetable.ErrorCodeInvalidArgument,
+						"schema version 2 is not supported, expected 1 to 1"))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.ImportSchema(context.Background(), tc.request)
+			if tc.expectedCode != codes.OK {
+				req.Equal(tc.expectedCode, status.Code(err))
+				return
+			}
+			req.NoError(err)
+			var created []string
+			for _, table := range resp.GetCreated() {
+				created = append(created, table.GetName())
+			}
+			req.Equal(tc.expected, created)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	rpcsHandled = metrics.NewCounterVec("litetable_grpc_rpcs_total",
+		"gRPC requests completed by the server.", "method", "code")
+	receivedBytes = metrics.NewCounter("litetable_grpc_received_bytes_total",
+		"Bytes of gRPC request messages received, as sent on the wire.")
+	sentBytes = metrics.NewCounter("litetable_grpc_sent_bytes_total",
+		"Bytes of gRPC response messages sent, as sent on the wire.")
+	openConnections = metrics.NewGauge("litetable_grpc_connections",
+		"Open gRPC client connections.")
+)
+
+// statsHandler exports the requests, message bytes and connections of the server on /metrics.
+type statsHandler struct{}
+
+type methodKey struct{}
+
+func (statsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, methodKey{}, info.FullMethodName)
+}
+
+func (statsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	switch s := s.(type) {
+	case *stats.InPayload:
+		receivedBytes.Add(float64(s.WireLength))
+	case *stats.OutPayload:
+		sentBytes.Add(float64(s.WireLength))
+	case *stats.End:
+		method, _ := ctx.Value(methodKey{}).(string)
+		rpcsHandled.With(method, status.Code(s.Error).String()).Inc()
+	}
+}
+
+func (statsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (statsHandler) HandleConn(_ context.Context, s stats.ConnStats) {
+	switch s.(type) {
+	case *stats.ConnBegin:
+		openConnections.Add(1)
+	case *stats.ConnEnd:
+		openConnections.Add(-1)
+	}
+}
@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestStatsHandler(t *testing.T) {
+	req := require.New(t)
+	h := statsHandler{}
+	method := "/litetable.LitetableService/Read"
+
+	ok := rpcsHandled.With(method, "OK")
+	notFound := rpcsHandled.With(method, "NotFound")
+	okBefore, notFoundBefore := ok.Value(), notFound.Value()
+	receivedBefore, sentBefore := receivedBytes.Value(), sentBytes.Value()
+	connsBefore := openConnections.Value()
+
+	h.HandleConn(context.Background(), &stats.ConnBegin{})
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: method})
+	h.HandleRPC(ctx, &stats.InPayload{WireLength: 10})
+	h.HandleRPC(ctx, &stats.OutPayload{WireLength: 25})
+	h.HandleRPC(ctx, &stats.End{})
+	h.HandleRPC(ctx, &stats.End{Error: status.Error(codes.NotFound, "row not found")})
+
+	req.Equal(okBefore+1, ok.Value())
+	req.Equal(notFoundBefore+1, notFound.Value())
+	req.Equal(receivedBefore+10, receivedBytes.Value())
+	req.Equal(sentBefore+25, sentBytes.Value())
+	req.Equal(connsBefore+1, openConnections.Value())
+
+	h.HandleConn(context.Background(), &stats.ConnEnd{})
+	req.Equal(connsBefore, openConnections.Value())
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// ListCDCSubscribers lists the clients of the change stream with how far each has been sent.
+func (l *lt) ListCDCSubscribers(_ context.Context, _ *proto.Empty) (
+	*proto.ListCDCSubscribersResponse, error) {
+	if l.subscribers == nil {
+		return nil, status.Errorf(codes.Unimplemented,
+			"CDC subscribers are not available on this server")
+	}
+
+	now := time.Now()
+	subscribers := l.subscribers.Subscribers()
+	res := &proto.ListCDCSubscribersResponse{
+		Subscribers: make([]*proto.CDCSubscriber, 0, len(subscribers)),
+		Sequence:    l.subscribers.Sequence(),
+	}
+	for _, sub := range subscribers {
+		res.Subscribers = append(res.Subscribers, &proto.CDCSubscriber{
+			Id:              sub.ID,
+			Kind:            sub.Kind,
+			RowKey:          sub.RowKey,
+			Prefix:          sub.Prefix,
+			LastSequence:    sub.LastSequence,
+			QueueDepth:      int64(sub.QueueDepth),
+			ConnectedAtUnix: sub.ConnectedAt.UnixNano(),
+			AgeSeconds:      int64(now.Sub(sub.ConnectedAt).Seconds()),
+		})
+	}
+	return res, nil
+}
+
+// KickCDCSubscriber disconnects a client of the change stream.
+func (l *lt) KickCDCSubscriber(_ context.Context, msg *proto.KickCDCSubscriberRequest) (
+	*proto.Empty, error) {
+	if l.subscribers == nil {
+		return nil, status.Errorf(codes.Unimplemented,
+			"CDC subscribers are not available on this server")
+	}
+	if msg.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "id required")
+	}
+
+	if err := l.subscribers.Kick(msg.GetId()); err != nil {
+		return nil, toStatus(err, "failed to kick CDC subscriber")
+	}
+	return &proto.Empty{}, nil
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+	cdc "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestLt_ListCDCSubscribers(t *testing.T) {
+	t.Run("subscribers", func(t *testing.T) {
+		req := require.New(t)
+		subscribers := NewMockcdcSubscribers(gomock.NewController(t))
+		connectedAt := time.Now().Add(-time.Minute)
+		subscribers.EXPECT().Subscribers().Return([]cdc.Subscriber{
+			{ID: "indexer", Kind: cdc.SubscriberStream, LastSequence: 40, QueueDepth: 2,
+				ConnectedAt: connectedAt},
+			{ID: "watch-1", Kind: cdc.SubscriberWatch, Prefix: "champ:", LastSequence: 38,
+				QueueDepth: 1, ConnectedAt: connectedAt},
+		})
+		subscribers.EXPECT().Sequence().Return(uint64(42))
+
+		svc := &lt{subscribers: subscribers}
+		resp, err := svc.ListCDCSubscribers(context.Background(), &proto.Empty{})
+		req.NoError(err)
+		req.Equal(uint64(42), resp.GetSequence())
+		req.Len(resp.GetSubscribers(), 2)
+
+		stream := resp.GetSubscribers()[0]
+		req.Equal("indexer", stream.GetId())
+		req.Equal("stream", stream.GetKind())
+		req.Equal(uint64(40), stream.GetLastSequence())
+		req.Equal(int64(2), stream.GetQueueDepth())
+		req.Equal(connectedAt.UnixNano(), stream.GetConnectedAtUnix())
+		req.Equal(int64(60), stream.GetAgeSeconds())
+
+		watch := resp.GetSubscribers()[1]
+		req.Equal("watch", watch.GetKind())
+		req.Equal("champ:", watch.GetPrefix())
+		req.Empty(watch.GetRowKey())
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		svc := &lt{}
+		_, err := svc.ListCDCSubscribers(context.Background(), &proto.Empty{})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+func TestLt_KickCDCSubscriber(t *testing.T) {
+	tests := map[string]struct {
+		id       string
+		kickErr  error
+		kicks    bool
+		wantCode codes.Code
+	}{
+		"kicked": {
+			id:       "indexer",
+			kicks:    true,
+			wantCode: codes.OK,
+		},
+		"unknown subscriber": {
+			id:       "nobody",
+			kicks:    true,
+			kickErr:  litetable.NewError(litetable.ErrorCodeNotFound, "no CDC subscriber nobody"),
+			wantCode: codes.NotFound,
+		},
+		"missing id": {
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			subscribers := NewMockcdcSubscribers(gomock.NewController(t))
+			if tc.kicks {
+				subscribers.EXPECT().Kick(tc.id).Return(tc.kickErr)
+			}
+
+			svc := &lt{subscribers: subscribers}
+			_, err := svc.KickCDCSubscriber(context.Background(),
+				&proto.KickCDCSubscriberRequest{Id: tc.id})
+			require.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+
+	t.Run("not enabled", func(t *testing.T) {
+		svc := &lt{}
+		_, err := svc.KickCDCSubscriber(context.Background(),
+			&proto.KickCDCSubscriberRequest{Id: "indexer"})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
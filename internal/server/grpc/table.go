@@ -0,0 +1,138 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// defaultTombstoneLimit is the most tombstones ListTombstones returns when a request sets no
+// limit.
+const defaultTombstoneLimit = 1000
+
+// CreateTable creates a table with its own keyspace and column families.
+func (l *lt) CreateTable(ctx context.Context, msg *proto.CreateTableRequest) (*proto.Empty,
+	error) {
+	start := time.Now()
+	if msg.GetName() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name required")
+	}
+
+	if err := l.operations.CreateTable(msg.GetName(), msg.GetFamily()); err != nil {
+		return nil, toStatus(err, "failed to create table")
+	}
+	requestid.Logger(ctx).Debug().Msgf("CreateTable successful: %v", time.Since(start))
+	return &proto.Empty{}, nil
+}
+
+// DropTable deletes a table and all of its data.
+func (l *lt) DropTable(ctx context.Context, msg *proto.DropTableRequest) (*proto.Empty, error) {
+	start := time.Now()
+	if msg.GetName() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name required")
+	}
+
+	if err := l.operations.DropTable(msg.GetName()); err != nil {
+		return nil, toStatus(err, "failed to drop table")
+	}
+	requestid.Logger(ctx).Debug().Msgf("DropTable successful: %v", time.Since(start))
+	return &proto.Empty{}, nil
+}
+
+// ListTables returns the name of every table.
+func (l *lt) ListTables(_ context.Context, _ *proto.Empty) (*proto.ListTablesResponse, error) {
+	return &proto.ListTablesResponse{Tables: l.operations.ListTables()}, nil
+}
+
+// TableStats returns the space a table and its column families take, with their quotas and the
+// stats of the families.
+func (l *lt) TableStats(_ context.Context,
+	msg *proto.TableStatsRequest) (*proto.TableStatsResponse, error) {
+	usage, err := l.operations.TableStats(msg.GetTable())
+	if err != nil {
+		return nil, toStatus(err, "failed to get table stats")
+	}
+
+	table := msg.GetTable()
+	if table == "" {
+		table = litetable.DefaultTable
+	}
+	resp := &proto.TableStatsResponse{
+		Table:    table,
+		Usage:    toProtoUsage(usage.Usage),
+		Families: make(map[string]*proto.Usage, len(usage.Families)),
+	}
+	for family, u := range usage.Families {
+		resp.Families[family] = toProtoUsage(u)
+	}
+	if !usage.StatsAt.IsZero() {
+		resp.StatsComputedAtUnix = usage.StatsAt.UnixNano()
+		resp.FamilyStats = make(map[string]*proto.FamilyStats, len(usage.Stats))
+		for family, stats := range usage.Stats {
+			resp.FamilyStats[family] = &proto.FamilyStats{
+				Rows:              stats.Rows,
+				Qualifiers:        stats.Qualifiers,
+				Cells:             stats.Cells,
+				Bytes:             stats.Bytes,
+				MaxVersions:       stats.MaxVersions,
+				VersionDepth:      stats.VersionDepth,
+				CompressedCells:   stats.CompressedCells,
+				CompressedBytes:   stats.CompressedBytes,
+				UncompressedBytes: stats.UncompressedBytes,
+			}
+		}
+	}
+	return resp, nil
+}
+
+// ListTombstones returns the tombstones of a table that have not expired.
+func (l *lt) ListTombstones(_ context.Context,
+	msg *proto.ListTombstonesRequest) (*proto.ListTombstonesResponse, error) {
+	limit := int(msg.GetLimit())
+	if limit == 0 {
+		limit = defaultTombstoneLimit
+	}
+	tombstones, more, err := l.operations.ListTombstones(msg.GetTable(), msg.GetPrefix(), limit)
+	if err != nil {
+		return nil, toStatus(err, "failed to list tombstones")
+	}
+
+	resp := &proto.ListTombstonesResponse{
+		Tombstones: make([]*proto.Tombstone, 0, len(tombstones)),
+		Truncated:  more,
+	}
+	for _, t := range tombstones {
+		resp.Tombstones = append(resp.Tombstones, &proto.Tombstone{
+			RowKey:        t.RowKey,
+			Family:        t.Family,
+			Qualifier:     t.Qualifier,
+			TimestampUnix: t.Timestamp,
+			ExpiresAtUnix: t.ExpiresAt,
+			Scheduled:     t.Scheduled,
+		})
+	}
+	return resp, nil
+}
+
+// Sequence returns the sequence number of the latest mutation to a table.
+func (l *lt) Sequence(_ context.Context, msg *proto.SequenceRequest) (*proto.SequenceResponse,
+	error) {
+	seq, err := l.operations.Sequence(msg.GetTable())
+	if err != nil {
+		return nil, toStatus(err, "failed to get sequence")
+	}
+	return &proto.SequenceResponse{Sequence: seq}, nil
+}
+
+func toProtoUsage(u litetable.Usage) *proto.Usage {
+	return &proto.Usage{
+		Bytes:    u.Bytes,
+		Rows:     u.Rows,
+		MaxBytes: u.MaxBytes,
+		MaxRows:  u.MaxRows,
+	}
+}
@@ -0,0 +1,297 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestLt_CreateTable(t *testing.T) {
+	tests := map[string]struct {
+		request      *proto.CreateTableRequest
+		mockSetup    func(m *Mockoperations)
+		expectedCode codes.Code
+	}{
+		"missing name": {
+			request:      &proto.CreateTableRequest{},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"table exists": {
+			request: &proto.CreateTableRequest{Name: "wwe"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().CreateTable("wwe", nil).Return(
+					litetable.NewError(litetable.ErrorCodeConflict, "table wwe already exists"))
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		"created": {
+			request: &proto.CreateTableRequest{Name: "wwe", Family: []string{"wrestlers"}},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().CreateTable("wwe", []string{"wrestlers"}).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.CreateTable(context.Background(), tc.request)
+			if tc.expectedCode == codes.OK {
+				req.NoError(err)
+				req.NotNil(resp)
+				return
+			}
+			req.Nil(resp)
+			req.Equal(tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestLt_DropTable(t *testing.T) {
+	tests := map[string]struct {
+		request      *proto.DropTableRequest
+		mockSetup    func(m *Mockoperations)
+		expectedCode codes.Code
+	}{
+		"missing name": {
+			request:      &proto.DropTableRequest{},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"missing table": {
+			request: &proto.DropTableRequest{Name: "wwe"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().DropTable("wwe").Return(
+					litetable.NewError(litetable.ErrorCodeNotFound, "table wwe does not exist"))
+			},
+			expectedCode: codes.NotFound,
+		},
+		"dropped": {
+			request: &proto.DropTableRequest{Name: "wwe"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().DropTable("wwe").Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.DropTable(context.Background(), tc.request)
+			if tc.expectedCode == codes.OK {
+				req.NoError(err)
+				req.NotNil(resp)
+				return
+			}
+			req.Nil(resp)
+			req.Equal(tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestLt_ListTables(t *testing.T) {
+	mockOps := NewMockoperations(gomock.NewController(t))
+	mockOps.EXPECT().ListTables().Return([]string{litetable.DefaultTable, "wwe"})
+
+	svc := &lt{operations: mockOps}
+	resp, err := svc.ListTables(context.Background(), &proto.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, []string{litetable.DefaultTable, "wwe"}, resp.GetTables())
+}
+
+func TestLt_TableStats(t *testing.T) {
+	tests := map[string]struct {
+		request      *proto.TableStatsRequest
+		mockSetup    func(m *Mockoperations)
+		expected     *proto.TableStatsResponse
+		expectedCode codes.Code
+	}{
+		"default table": {
+			request: &proto.TableStatsRequest{},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().TableStats("").Return(litetable.TableUsage{
+					Usage: litetable.Usage{Bytes: 14, Rows: 2, MaxBytes: 100},
+					Families: map[string]litetable.Usage{
+						"main": {Bytes: 14, Rows: 2, MaxRows: 10},
+					},
+				}, nil)
+			},
+			expected: &proto.TableStatsResponse{
+				Table: litetable.DefaultTable,
+				Usage: &proto.Usage{Bytes: 14, Rows: 2, MaxBytes: 100},
+				Families: map[string]*proto.Usage{
+					"main": {Bytes: 14, Rows: 2, MaxRows: 10},
+				},
+			},
+			expectedCode: codes.OK,
+		},
+		"family stats": {
+			request: &proto.TableStatsRequest{Table: "wwe"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().TableStats("wwe").Return(litetable.TableUsage{
+					Stats: map[string]litetable.FamilyStats{
+						"main": {Rows: 2, Qualifiers: 3, Cells: 5, Bytes: 300, MaxVersions: 3,
+							VersionDepth:    []int64{2, 0, 1, 0, 0, 0, 0, 0},
+							CompressedCells: 1, CompressedBytes: 40, UncompressedBytes: 120},
+					},
+					StatsAt: time.Unix(0, 42),
+				}, nil)
+			},
+			expected: &proto.TableStatsResponse{
+				Table: "wwe",
+				Usage: &proto.Usage{},
+				FamilyStats: map[string]*proto.FamilyStats{
+					"main": {Rows: 2, Qualifiers: 3, Cells: 5, Bytes: 300, MaxVersions: 3,
+						VersionDepth:    []int64{2, 0, 1, 0, 0, 0, 0, 0},
+						CompressedCells: 1, CompressedBytes: 40, UncompressedBytes: 120},
+				},
+				StatsComputedAtUnix: 42,
+			},
+			expectedCode: codes.OK,
+		},
+		"missing table": {
+			request: &proto.TableStatsRequest{Table: "wwe"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().TableStats("wwe").Return(litetable.TableUsage{},
+					litetable.NewError(litetable.ErrorCodeNotFound, "table wwe does not exist"))
+			},
+			expectedCode: codes.NotFound,
+		},
+		"internal error": {
+			request: &proto.TableStatsRequest{Table: "wwe"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().TableStats("wwe").Return(litetable.TableUsage{}, errors.New("boom"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.TableStats(context.Background(), tc.request)
+			if tc.expectedCode == codes.OK {
+				req.NoError(err)
+				req.Equal(tc.expected.GetTable(), resp.GetTable())
+				req.Equal(tc.expected.GetUsage().String(), resp.GetUsage().String())
+				req.Len(resp.GetFamilies(), len(tc.expected.GetFamilies()))
+				for family, usage := range tc.expected.GetFamilies() {
+					req.Equal(usage.String(), resp.GetFamilies()[family].String())
+				}
+				req.Equal(tc.expected.GetStatsComputedAtUnix(), resp.GetStatsComputedAtUnix())
+				req.Len(resp.GetFamilyStats(), len(tc.expected.GetFamilyStats()))
+				for family, stats := range tc.expected.GetFamilyStats() {
+					req.Equal(stats.String(), resp.GetFamilyStats()[family].String())
+				}
+				return
+			}
+			req.Nil(resp)
+			req.Equal(tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestLt_ListTombstones(t *testing.T) {
+	tests := map[string]struct {
+		request      *proto.ListTombstonesRequest
+		mockSetup    func(m *Mockoperations)
+		expected     *proto.ListTombstonesResponse
+		expectedCode codes.Code
+	}{
+		"default limit": {
+			request: &proto.ListTombstonesRequest{Prefix: "champ"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().ListTombstones("", "champ", defaultTombstoneLimit).Return(
+					[]litetable.Tombstone{{RowKey: "champ:1", Family: "main", Qualifier: "name",
+						Timestamp: 10, ExpiresAt: 20, Scheduled: true}}, false, nil)
+			},
+			expected: &proto.ListTombstonesResponse{
+				Tombstones: []*proto.Tombstone{{RowKey: "champ:1", Family: "main",
+					Qualifier: "name", TimestampUnix: 10, ExpiresAtUnix: 20, Scheduled: true}},
+			},
+			expectedCode: codes.OK,
+		},
+		"truncated": {
+			request: &proto.ListTombstonesRequest{Table: "wwe", Limit: 1},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().ListTombstones("wwe", "", 1).Return(
+					[]litetable.Tombstone{{RowKey: "champ:1", Family: "main", Qualifier: "name"}},
+					true, nil)
+			},
+			expected: &proto.ListTombstonesResponse{
+				Tombstones: []*proto.Tombstone{{RowKey: "champ:1", Family: "main",
+					Qualifier: "name"}},
+				Truncated: true,
+			},
+			expectedCode: codes.OK,
+		},
+		"missing table": {
+			request: &proto.ListTombstonesRequest{Table: "aew"},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().ListTombstones("aew", "", defaultTombstoneLimit).Return(nil, false,
+					litetable.NewError(litetable.ErrorCodeNotFound, "table aew does not exist"))
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.ListTombstones(context.Background(), tc.request)
+			if tc.expectedCode == codes.OK {
+				req.NoError(err)
+				req.Equal(tc.expected.GetTruncated(), resp.GetTruncated())
+				req.Len(resp.GetTombstones(), len(tc.expected.GetTombstones()))
+				for i, tombstone := range tc.expected.GetTombstones() {
+					req.Equal(tombstone.String(), resp.GetTombstones()[i].String())
+				}
+				return
+			}
+			req.Nil(resp)
+			req.Equal(tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestLt_Sequence(t *testing.T) {
+	req := require.New(t)
+	mockOps := NewMockoperations(gomock.NewController(t))
+	mockOps.EXPECT().Sequence("wwe").Return(uint64(42), nil)
+	mockOps.EXPECT().Sequence("aew").Return(uint64(0),
+		litetable.NewError(litetable.ErrorCodeNotFound, "table aew does not exist"))
+
+	svc := &lt{operations: mockOps}
+	resp, err := svc.Sequence(context.Background(), &proto.SequenceRequest{Table: "wwe"})
+	req.NoError(err)
+	req.Equal(uint64(42), resp.GetSequence())
+
+	_, err = svc.Sequence(context.Background(), &proto.SequenceRequest{Table: "aew"})
+	req.Equal(codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// BeginTransaction returns the sequence number to read a transaction at.
+func (l *lt) BeginTransaction(_ context.Context,
+	msg *proto.BeginTransactionRequest) (*proto.BeginTransactionResponse, error) {
+	seq, err := l.operations.Sequence(msg.GetTable())
+	if err != nil {
+		return nil, toStatus(err, "failed to begin transaction")
+	}
+	return &proto.BeginTransactionResponse{ReadAt: seq}, nil
+}
+
+// Commit applies the mutations of a transaction at once.
+func (l *lt) Commit(ctx context.Context, msg *proto.CommitRequest) (*proto.CommitResponse,
+	error) {
+	start := time.Now()
+	if len(msg.GetMutations()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "mutations required")
+	}
+
+	queries := make([]litetable.MutationQuery, 0, len(msg.GetMutations()))
+	for i, mutation := range msg.GetMutations() {
+		switch {
+		case mutation.GetWrite() != nil:
+			if err := l.validateWrite(mutation.GetWrite()); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "mutations[%d]: %v", i,
+					status.Convert(err).Message())
+			}
+			queries = append(queries, litetable.MutationQuery{
+				Operation: litetable.OperationWrite,
+				Query:     writeQuery(mutation.GetWrite()),
+			})
+		case mutation.GetDelete() != nil:
+			if err := l.validateDelete(mutation.GetDelete()); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "mutations[%d]: %v", i,
+					status.Convert(err).Message())
+			}
+			queries = append(queries, litetable.MutationQuery{
+				Operation: litetable.OperationDelete,
+				Query:     deleteQuery(mutation.GetDelete()),
+			})
+		default:
+			return nil, status.Errorf(codes.InvalidArgument,
+				"mutations[%d]: write or delete required", i)
+		}
+	}
+
+	result, err := l.operations.Commit(msg.GetTable(), msg.GetReadAt(), msg.GetReadRows(), queries)
+	if err != nil {
+		if errors.Is(err, litetable.ErrConflict) {
+			return nil, toStatusCode(err, codes.Aborted, "transaction aborted")
+		}
+		return nil, toStatus(err, "failed to commit transaction")
+	}
+	requestid.Logger(ctx).Debug().Msgf("Commit successful: %v", time.Since(start))
+	return &proto.CommitResponse{
+		Sequence:      result.Sequence,
+		TimestampUnix: result.Timestamp,
+	}, nil
+}
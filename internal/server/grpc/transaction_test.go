@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestLt_BeginTransaction(t *testing.T) {
+	mockOps := NewMockoperations(gomock.NewController(t))
+	mockOps.EXPECT().Sequence("wwe").Return(uint64(42), nil)
+
+	svc := &lt{operations: mockOps}
+	resp, err := svc.BeginTransaction(context.Background(),
+		&proto.BeginTransactionRequest{Table: "wwe"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), resp.GetReadAt())
+}
+
+func TestLt_Commit(t *testing.T) {
+	write := &proto.TransactionMutation{Mutation: &proto.TransactionMutation_Write{
+		Write: &proto.WriteRequest{
+			RowKey:     "champ:1",
+			Family:     "main",
+			Qualifiers: []*proto.ColumnQualifier{{Name: "name", Value: []byte("Ahri")}},
+		},
+	}}
+	remove := &proto.TransactionMutation{Mutation: &proto.TransactionMutation_Delete{
+		Delete: &proto.DeleteRequest{RowKey: "champ:2", Family: "main"},
+	}}
+
+	tests := map[string]struct {
+		request      *proto.CommitRequest
+		mockSetup    func(m *Mockoperations)
+		expectedCode codes.Code
+	}{
+		"no mutations": {
+			request:      &proto.CommitRequest{ReadAt: 7},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"empty mutation": {
+			request: &proto.CommitRequest{
+				Mutations: []*proto.TransactionMutation{{}},
+			},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"write without qualifiers": {
+			request: &proto.CommitRequest{
+				Mutations: []*proto.TransactionMutation{{
+					Mutation: &proto.TransactionMutation_Write{
+						Write: &proto.WriteRequest{RowKey: "champ:1", Family: "main"},
+					},
+				}},
+			},
+			mockSetup:    func(m *Mockoperations) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		"conflict": {
+			request: &proto.CommitRequest{
+				ReadAt:    7,
+				Mutations: []*proto.TransactionMutation{write},
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().Commit("", uint64(7), nil, gomock.Any()).Return(
+					litetable.CommitResult{},
+					litetable.NewError(litetable.ErrorCodeConflict, "row champ:1 changed"))
+			},
+			expectedCode: codes.Aborted,
+		},
+		"committed": {
+			request: &proto.CommitRequest{
+				Table:     "wwe",
+				ReadAt:    7,
+				ReadRows:  []string{"champ:3"},
+				Mutations: []*proto.TransactionMutation{write, remove},
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().Commit("wwe", uint64(7), []string{"champ:3"},
+					[]litetable.MutationQuery{
+						{
+							Operation: litetable.OperationWrite,
+							Query:     "v2:family=main key=champ:1 qualifier=name value=Ahri",
+						},
+						{
+							Operation: litetable.OperationDelete,
+							Query:     "v2:key=champ:2 family=main",
+						},
+					}).Return(litetable.CommitResult{Sequence: 9, Timestamp: 1000}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			mockOps := NewMockoperations(gomock.NewController(t))
+			tc.mockSetup(mockOps)
+
+			svc := &lt{operations: mockOps}
+			resp, err := svc.Commit(context.Background(), tc.request)
+			if tc.expectedCode == codes.OK {
+				req.NoError(err)
+				req.Equal(uint64(9), resp.GetSequence())
+				req.Equal(int64(1000), resp.GetTimestampUnix())
+				return
+			}
+			req.Nil(resp)
+			req.Equal(tc.expectedCode, status.Code(err))
+		})
+	}
+}
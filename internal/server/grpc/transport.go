@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"fmt"
+	grpc2 "google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+	"math"
+	"time"
+)
+
+// DefaultMaxRecvMsgSize is the largest message the server receives when
+// Transport.MaxRecvMsgSize is not set, which is the grpc-go default.
+const DefaultMaxRecvMsgSize = 4 << 20 // 4MB
+
+// Transport tunes the connections of the gRPC server. Zero values use the grpc-go defaults. The
+// transport is fixed when the server is created, so changes need a restart.
+type Transport struct {
+	// MaxRecvMsgSize is the largest request in bytes. It must be larger than the largest value
+	// written in one request. Defaults to DefaultMaxRecvMsgSize.
+	MaxRecvMsgSize int
+	// MaxSendMsgSize is the largest response in bytes. Unlimited by default.
+	MaxSendMsgSize int
+	// MaxConcurrentStreams limits the concurrent requests on a single client connection.
+	MaxConcurrentStreams int
+	// KeepaliveMinTime is the shortest interval at which clients may send keepalive pings.
+	// Clients that ping more often are disconnected. Defaults to 5 minutes.
+	KeepaliveMinTime time.Duration
+	// KeepalivePermitWithoutStream lets clients send keepalive pings with no request in flight.
+	KeepalivePermitWithoutStream bool
+	// CompressionLevel is the gzip level of the responses to clients that send gzip compressed
+	// requests, from 1 (fastest) to 9 (smallest). Defaults to gzip's default level.
+	CompressionLevel int
+}
+
+// setCompression sets the level of the gzip compressor, which the server registers for clients
+// to enable. The compressor is shared by the whole process.
+func (t Transport) setCompression() error {
+	if t.CompressionLevel == 0 {
+		return nil
+	}
+	if err := gzip.SetLevel(t.CompressionLevel); err != nil {
+		return fmt.Errorf("invalid compression level %d: %w", t.CompressionLevel, err)
+	}
+	return nil
+}
+
+// serverOptions returns the grpc-go options for the configured transport.
+func (t Transport) serverOptions() []grpc2.ServerOption {
+	var opts []grpc2.ServerOption
+	if t.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc2.MaxRecvMsgSize(t.MaxRecvMsgSize))
+	}
+	if t.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc2.MaxSendMsgSize(t.MaxSendMsgSize))
+	}
+	if t.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc2.MaxConcurrentStreams(
+			uint32(min(t.MaxConcurrentStreams, math.MaxUint32))))
+	}
+	if t.KeepaliveMinTime > 0 || t.KeepalivePermitWithoutStream {
+		opts = append(opts, grpc2.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             t.KeepaliveMinTime,
+			PermitWithoutStream: t.KeepalivePermitWithoutStream,
+		}))
+	}
+	return opts
+}
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransport_serverOptions(t *testing.T) {
+	tests := map[string]struct {
+		transport Transport
+		want      int
+	}{
+		"defaults": {},
+		"message sizes": {
+			transport: Transport{MaxRecvMsgSize: 1 << 20, MaxSendMsgSize: 1 << 20},
+			want:      2,
+		},
+		"everything": {
+			transport: Transport{
+				MaxRecvMsgSize:               1 << 20,
+				MaxSendMsgSize:               1 << 20,
+				MaxConcurrentStreams:         100,
+				KeepaliveMinTime:             time.Minute,
+				KeepalivePermitWithoutStream: true,
+			},
+			want: 4,
+		},
+		"keepalive without streams only": {
+			transport: Transport{KeepalivePermitWithoutStream: true},
+			want:      1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Len(t, tc.transport.serverOptions(), tc.want)
+		})
+	}
+}
+
+func TestTransport_maxRecvMsgSize(t *testing.T) {
+	req := require.New(t)
+	ctrl := gomock.NewController(t)
+	ops := NewMockoperations(ctrl)
+	ops.EXPECT().CreateFamilies("", []string{"small"}).Return(nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	req.NoError(err)
+
+	srv := grpc.NewServer(Transport{MaxRecvMsgSize: 1024}.serverOptions()...)
+	proto.RegisterLitetableServiceServer(srv, &lt{operations: ops})
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	req.NoError(err)
+	defer conn.Close()
+	client := proto.NewLitetableServiceClient(conn)
+
+	_, err = client.CreateFamily(context.Background(),
+		&proto.CreateFamilyRequest{Family: []string{"small"}})
+	req.NoError(err)
+
+	// a request over the limit is rejected before it reaches the handler
+	_, err = client.CreateFamily(context.Background(),
+		&proto.CreateFamilyRequest{Family: []string{strings.Repeat("f", 2048)}})
+	req.Equal(codes.ResourceExhausted, status.Code(err))
+}
+
+func TestTransport_setCompression(t *testing.T) {
+	req := require.New(t)
+	req.ErrorContains(Transport{CompressionLevel: 10}.setCompression(),
+		"invalid compression level 10")
+	req.NoError(Transport{CompressionLevel: 9}.setCompression())
+
+	ctrl := gomock.NewController(t)
+	ops := NewMockoperations(ctrl)
+	ops.EXPECT().CreateFamilies("", []string{"main"}).Return(nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	req.NoError(err)
+	srv := grpc.NewServer()
+	proto.RegisterLitetableServiceServer(srv, &lt{operations: ops})
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	req.NoError(err)
+	defer conn.Close()
+
+	// a compressed request is answered
+	_, err = proto.NewLitetableServiceClient(conn).CreateFamily(context.Background(),
+		&proto.CreateFamilyRequest{Family: []string{"main"}}, grpc.UseCompressor(gzip.Name))
+	req.NoError(err)
+}
@@ -0,0 +1,406 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpc2 "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxQualifiers    = 1000
+	defaultMaxValueSize     = 1 << 20 // 1MB
+	maxIdempotencyKeyLength = 256
+	maxTombstoneLimit       = 10000
+)
+
+// Limits are the request size and shape limits enforced by the validation interceptor. Zero
+// values fall back to the defaults, which for row keys and family names are those of
+// litetable.NameRules.
+type Limits struct {
+	MaxRowKeyLength   int
+	MaxQualifiers     int
+	MaxValueSize      int
+	FamilyNamePattern string
+	// MaxResponseSize is the largest encoded size of the rows of a prefix or regex read. A read
+	// matching more is truncated, with a page token to read the rest. Defaults to
+	// DefaultMaxResponseSize.
+	MaxResponseSize int
+	// ClientQuotas bound the cost of the reads of each client, keyed by the name it sends in
+	// ClientHeader. Clients without a quota are unlimited.
+	ClientQuotas map[string]ClientQuota
+	// CostWindow is how long the cost of a client adds up against its quota. Defaults to
+	// DefaultCostWindow.
+	CostWindow time.Duration
+}
+
+// validator checks incoming requests against the configured Limits.
+type validator struct {
+	names         litetable.NameRules
+	maxQualifiers int
+	maxValueSize  int
+	// maxResponseSize, clientQuotas and costWindow are read by the handlers and the accountant
+	// rather than checked on requests
+	maxResponseSize int
+	clientQuotas    map[string]ClientQuota
+	costWindow      time.Duration
+}
+
+func newValidator(l Limits) (*validator, error) {
+	names, err := litetable.NewNameRules(l.MaxRowKeyLength, l.FamilyNamePattern)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{
+		names:           names,
+		maxQualifiers:   l.MaxQualifiers,
+		maxValueSize:    l.MaxValueSize,
+		maxResponseSize: l.MaxResponseSize,
+		clientQuotas:    l.ClientQuotas,
+		costWindow:      l.CostWindow,
+	}
+	if v.maxQualifiers <= 0 {
+		v.maxQualifiers = defaultMaxQualifiers
+	}
+	if v.maxValueSize <= 0 {
+		v.maxValueSize = defaultMaxValueSize
+	}
+	if v.maxResponseSize <= 0 {
+		v.maxResponseSize = DefaultMaxResponseSize
+	}
+	if v.costWindow <= 0 {
+		v.costWindow = DefaultCostWindow
+	}
+
+	return v, nil
+}
+
+// unaryInterceptor rejects malformed requests before they reach a handler. Required fields are
+// left to the handlers; the interceptor only checks the shape of values that are present.
+func (v *validator) unaryInterceptor(ctx context.Context, req any, _ *grpc2.UnaryServerInfo,
+	handler grpc2.UnaryHandler) (any, error) {
+	if violations := v.validate(req); len(violations) > 0 {
+		return nil, invalidRequest(violations)
+	}
+	return handler(ctx, req)
+}
+
+// validate returns every field violation found in a request.
+func (v *validator) validate(req any) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	switch msg := req.(type) {
+	case *proto.ReadRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		// a regex query carries a pattern in the row key, which is not held to the name rules
+		if msg.GetQueryType() == proto.QueryType_REGEX {
+			violations = append(violations, v.regex("row_key", msg.GetRowKey())...)
+		} else {
+			violations = append(violations, v.rowKey("row_key", msg.GetRowKey())...)
+		}
+		violations = append(violations, v.family("family", msg.GetFamily())...)
+		violations = append(violations, v.qualifierCount(len(msg.GetQualifiers()))...)
+		for i, q := range msg.GetQualifiers() {
+			violations = append(violations, v.name(fmt.Sprintf("qualifiers[%d]", i), q)...)
+		}
+		if msg.GetLatest() < 0 {
+			violations = append(violations, violation("latest", "cannot be negative"))
+		}
+		if msg.GetAsOfUnix() < 0 {
+			violations = append(violations, violation("as_of_unix", "cannot be negative"))
+		}
+		for i, path := range msg.GetJsonPaths() {
+			violations = append(violations, jsonPath(fmt.Sprintf("json_paths[%d]", i), path)...)
+		}
+		violations = append(violations, v.pageToken(msg)...)
+		// a read view can be behind read_at, so it could miss writes the read must see
+		if msg.GetConsistency() == proto.Consistency_EVENTUAL && msg.GetReadAt() > 0 {
+			violations = append(violations, violation("consistency",
+				"must be STRONG when read_at is set"))
+		}
+	case *proto.AggregateRequest:
+		if msg.GetRead() != nil {
+			for _, fv := range v.validate(msg.GetRead()) {
+				fv.Field = "read." + fv.Field
+				violations = append(violations, fv)
+			}
+			if len(msg.GetRead().GetJsonPaths()) > 0 {
+				violations = append(violations, violation("read.json_paths",
+					"is not supported by aggregations"))
+			}
+		}
+	case *proto.ListQualifiersRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		violations = append(violations, v.rowKey("row_key", msg.GetRowKey())...)
+		violations = append(violations, v.family("family", msg.GetFamily())...)
+	case *proto.WriteRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		violations = append(violations, v.rowKey("row_key", msg.GetRowKey())...)
+		violations = append(violations, v.family("family", msg.GetFamily())...)
+		count := len(msg.GetQualifiers())
+		violations = append(violations, v.columns("qualifiers", msg.GetQualifiers())...)
+		for i, cells := range msg.GetFamilies() {
+			field := fmt.Sprintf("families[%d]", i)
+			violations = append(violations, v.family(field+".family", cells.GetFamily())...)
+			violations = append(violations, v.columns(field+".qualifiers",
+				cells.GetQualifiers())...)
+			count += len(cells.GetQualifiers())
+		}
+		violations = append(violations, v.qualifierCount(count)...)
+		if msg.GetTtl() < 0 {
+			violations = append(violations, violation("ttl", "cannot be negative"))
+		}
+		if msg.GetTimestampUnix() < 0 {
+			violations = append(violations, violation("timestamp_unix", "cannot be negative"))
+		}
+		if len(msg.GetIdempotencyKey()) > maxIdempotencyKeyLength {
+			violations = append(violations, violation("idempotency_key",
+				"must be at most %d bytes", maxIdempotencyKeyLength))
+		} else {
+			violations = append(violations, v.name("idempotency_key",
+				msg.GetIdempotencyKey())...)
+		}
+	case *proto.DeleteRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		violations = append(violations, v.rowKey("row_key", msg.GetRowKey())...)
+		violations = append(violations, v.family("family", msg.GetFamily())...)
+		violations = append(violations, v.qualifierCount(len(msg.GetQualifiers()))...)
+		for i, q := range msg.GetQualifiers() {
+			violations = append(violations, v.name(fmt.Sprintf("qualifiers[%d]", i), q)...)
+		}
+		if msg.GetTtl() < 0 {
+			violations = append(violations, violation("ttl", "cannot be negative"))
+		}
+	case *proto.CreateFamilyRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		violations = append(violations, v.families(msg.GetFamily())...)
+	case *proto.CloneFamilyRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		if msg.GetSource() == "" {
+			violations = append(violations, violation("source", "is required"))
+		}
+		violations = append(violations, v.family("source", msg.GetSource())...)
+		if msg.GetTarget() == "" {
+			violations = append(violations, violation("target", "is required"))
+		}
+		violations = append(violations, v.family("target", msg.GetTarget())...)
+	case *proto.CreateTableRequest:
+		violations = append(violations, v.table("name", msg.GetName())...)
+		violations = append(violations, v.families(msg.GetFamily())...)
+	case *proto.DropTableRequest:
+		violations = append(violations, v.table("name", msg.GetName())...)
+	case *proto.ImportSchemaRequest:
+		if msg.GetSchema() == nil {
+			violations = append(violations, violation("schema", "is required"))
+		}
+		for i, table := range msg.GetSchema().GetTables() {
+			field := fmt.Sprintf("schema.tables[%d]", i)
+			if table.GetName() == "" {
+				violations = append(violations, violation(field+".name", "is required"))
+			}
+			violations = append(violations, v.table(field+".name", table.GetName())...)
+			for j, family := range table.GetFamilies() {
+				field := fmt.Sprintf("%s.families[%d]", field, j)
+				if family == "" {
+					violations = append(violations, violation(field, "is required"))
+					continue
+				}
+				violations = append(violations, v.family(field, family)...)
+			}
+		}
+	case *proto.TableStatsRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+	case *proto.ListTombstonesRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		violations = append(violations, v.rowKey("prefix", msg.GetPrefix())...)
+		if msg.GetLimit() < 0 || msg.GetLimit() > maxTombstoneLimit {
+			violations = append(violations, violation("limit", "must be between 0 and %d",
+				maxTombstoneLimit))
+		}
+	case *proto.SequenceRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+	case *proto.BeginTransactionRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+	case *proto.CommitRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		for i, key := range msg.GetReadRows() {
+			violations = append(violations, v.rowKey(fmt.Sprintf("read_rows[%d]", i), key)...)
+		}
+		for i, mutation := range msg.GetMutations() {
+			field, nested := fmt.Sprintf("mutations[%d].write", i), any(mutation.GetWrite())
+			if mutation.GetDelete() != nil {
+				field, nested = fmt.Sprintf("mutations[%d].delete", i), mutation.GetDelete()
+			} else if mutation.GetWrite() == nil {
+				continue
+			}
+			for _, fv := range v.validate(nested) {
+				fv.Field = field + "." + fv.Field
+				violations = append(violations, fv)
+			}
+		}
+	case *proto.LockRowRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		violations = append(violations, v.rowKey("row_key", msg.GetRowKey())...)
+		if msg.GetTtl() < 0 {
+			violations = append(violations, violation("ttl", "cannot be negative"))
+		}
+	case *proto.UnlockRowRequest:
+		violations = append(violations, v.table("table", msg.GetTable())...)
+		violations = append(violations, v.rowKey("row_key", msg.GetRowKey())...)
+	}
+
+	return violations
+}
+
+func (v *validator) rowKey(field, key string) []*errdetails.BadRequest_FieldViolation {
+	if key == "" {
+		return nil
+	}
+	if len(key) > v.names.MaxRowKeyLength() {
+		return []*errdetails.BadRequest_FieldViolation{
+			violation(field, "must be at most %d bytes", v.names.MaxRowKeyLength()),
+		}
+	}
+	return v.name(field, key)
+}
+
+// regex checks a regex pattern fits in a row key. Patterns are quoted in the read query, so
+// they may hold whitespace.
+func (v *validator) regex(field, pattern string) []*errdetails.BadRequest_FieldViolation {
+	if len(pattern) > v.names.MaxRowKeyLength() {
+		return []*errdetails.BadRequest_FieldViolation{
+			violation(field, "must be at most %d bytes", v.names.MaxRowKeyLength()),
+		}
+	}
+	return nil
+}
+
+// pageToken checks the page token of a read resumes a prefix or regex read after a valid row key.
+func (v *validator) pageToken(msg *proto.ReadRequest) []*errdetails.BadRequest_FieldViolation {
+	if msg.GetPageToken() == "" {
+		return nil
+	}
+	if msg.GetQueryType() == proto.QueryType_EXACT {
+		return []*errdetails.BadRequest_FieldViolation{
+			violation("page_token", "only applies to prefix and regex reads"),
+		}
+	}
+	rowKey, err := DecodePageToken(msg.GetPageToken())
+	if err != nil || rowKey == "" {
+		return []*errdetails.BadRequest_FieldViolation{
+			violation("page_token", "is not a valid page token"),
+		}
+	}
+	return v.rowKey("page_token", rowKey)
+}
+
+// jsonPath checks that a path names fields separated by dots, without the whitespace or = that
+// would split the read query.
+func jsonPath(field, path string) []*errdetails.BadRequest_FieldViolation {
+	valid := path != "" && !strings.ContainsAny(path, " \t\r\n=")
+	for _, name := range strings.Split(path, ".") {
+		valid = valid && name != ""
+	}
+	if valid {
+		return nil
+	}
+	return []*errdetails.BadRequest_FieldViolation{
+		violation(field, "must be field names separated by dots"),
+	}
+}
+
+func (v *validator) family(field, family string) []*errdetails.BadRequest_FieldViolation {
+	if family == "" || v.names.FamilyPattern().MatchString(family) {
+		return nil
+	}
+	return []*errdetails.BadRequest_FieldViolation{
+		violation(field, "must match %s", v.names.FamilyPattern().String()),
+	}
+}
+
+func (v *validator) families(families []string) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for i, f := range families {
+		field := fmt.Sprintf("family[%d]", i)
+		if f == "" {
+			violations = append(violations, violation(field, "is required"))
+			continue
+		}
+		violations = append(violations, v.family(field, f)...)
+	}
+	return violations
+}
+
+func (v *validator) table(field, table string) []*errdetails.BadRequest_FieldViolation {
+	if table == "" || litetable.ValidTableName(table) {
+		return nil
+	}
+	return []*errdetails.BadRequest_FieldViolation{
+		violation(field, "must match %s", litetable.TableNamePattern()),
+	}
+}
+
+// columns checks the qualifiers and values of a write.
+func (v *validator) columns(field string,
+	qualifiers []*proto.ColumnQualifier) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for i, q := range qualifiers {
+		field := fmt.Sprintf("%s[%d]", field, i)
+		if q.GetName() == "" {
+			violations = append(violations, violation(field+".name", "is required"))
+		}
+		violations = append(violations, v.name(field+".name", q.GetName())...)
+		if len(q.GetValue()) > v.maxValueSize {
+			violations = append(violations, violation(field+".value",
+				"must be at most %d bytes", v.maxValueSize))
+		}
+	}
+	return violations
+}
+
+func (v *validator) qualifierCount(n int) []*errdetails.BadRequest_FieldViolation {
+	if n <= v.maxQualifiers {
+		return nil
+	}
+	return []*errdetails.BadRequest_FieldViolation{
+		violation("qualifiers", "must contain at most %d entries", v.maxQualifiers),
+	}
+}
+
+// name checks keys and qualifiers: they must be valid UTF-8 without whitespace or control
+// characters, which would otherwise break the text query protocol.
+func (v *validator) name(field, name string) []*errdetails.BadRequest_FieldViolation {
+	if problem := litetable.NameViolation(name); problem != "" {
+		return []*errdetails.BadRequest_FieldViolation{violation(field, "%s", problem)}
+	}
+	return nil
+}
+
+func violation(field, format string, args ...any) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	}
+}
+
+// invalidRequest builds an InvalidArgument status carrying every field violation.
+func invalidRequest(violations []*errdetails.BadRequest_FieldViolation) error {
+	msg := "invalid request"
+	if len(violations) > 0 {
+		msg = fmt.Sprintf("invalid request: %s %s", violations[0].GetField(),
+			violations[0].GetDescription())
+	}
+
+	st := status.New(codes.InvalidArgument, msg)
+	withDetails, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
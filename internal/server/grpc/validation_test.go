@@ -0,0 +1,326 @@
+package grpc
+
+import (
+	"context"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"testing"
+)
+
+func TestNewValidator(t *testing.T) {
+	req := require.New(t)
+
+	v, err := newValidator(Limits{})
+	req.NoError(err)
+	req.Equal(litetable.DefaultMaxRowKeyLength, v.names.MaxRowKeyLength())
+	req.Equal(defaultMaxQualifiers, v.maxQualifiers)
+	req.Equal(defaultMaxValueSize, v.maxValueSize)
+	req.Equal(litetable.DefaultFamilyNamePattern, v.names.FamilyPattern().String())
+
+	_, err = newValidator(Limits{FamilyNamePattern: "["})
+	req.Error(err)
+}
+
+func TestValidator_validate(t *testing.T) {
+	v, err := newValidator(Limits{MaxRowKeyLength: 8, MaxQualifiers: 2, MaxValueSize: 4})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		req    any
+		fields []string
+	}{
+		"valid write": {
+			req: &proto.WriteRequest{
+				RowKey: "champ:1",
+				Family: "main",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "name", Value: []byte("Ahri")},
+				},
+			},
+		},
+		"empty fields are left to handlers": {
+			req: &proto.WriteRequest{},
+		},
+		"row key too long": {
+			req:    &proto.WriteRequest{RowKey: "champion:1", Family: "main"},
+			fields: []string{"row_key"},
+		},
+		"row key with whitespace": {
+			req:    &proto.DeleteRequest{RowKey: "a b", Family: "main"},
+			fields: []string{"row_key"},
+		},
+		"invalid family": {
+			req:    &proto.ReadRequest{RowKey: "champ:1", Family: "ma in"},
+			fields: []string{"family"},
+		},
+		"too many qualifiers": {
+			req: &proto.ReadRequest{
+				RowKey:     "champ:1",
+				Family:     "main",
+				Qualifiers: []string{"a", "b", "c"},
+			},
+			fields: []string{"qualifiers"},
+		},
+		"write qualifier violations": {
+			req: &proto.WriteRequest{
+				RowKey: "champ:1",
+				Family: "main",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "", Value: []byte("x")},
+					{Name: "lore", Value: []byte("too long")},
+				},
+			},
+			fields: []string{"qualifiers[0].name", "qualifiers[1].value"},
+		},
+		"regex read with metacharacters": {
+			req: &proto.ReadRequest{
+				RowKey:    `^a.*\d`,
+				Family:    "main",
+				QueryType: proto.QueryType_REGEX,
+			},
+		},
+		"regex with whitespace": {
+			req: &proto.ReadRequest{
+				RowKey:    "a b",
+				Family:    "main",
+				QueryType: proto.QueryType_REGEX,
+			},
+		},
+
+		"negative write ttl": {
+			req:    &proto.WriteRequest{RowKey: "champ:1", Family: "main", Ttl: -1},
+			fields: []string{"ttl"},
+		},
+		"negative ttl": {
+			req:    &proto.DeleteRequest{RowKey: "champ:1", Ttl: -1},
+			fields: []string{"ttl"},
+		},
+		"create family": {
+			req:    &proto.CreateFamilyRequest{Family: []string{"main", "", "bad name"}},
+			fields: []string{"family[1]", "family[2]"},
+		},
+		"table": {
+			req:    &proto.ReadRequest{RowKey: "champ:1", Family: "main", Table: "../main"},
+			fields: []string{"table"},
+		},
+		"create table": {
+			req:    &proto.CreateTableRequest{Name: "bad name", Family: []string{"main", ""}},
+			fields: []string{"name", "family[1]"},
+		},
+		"table stats": {
+			req:    &proto.TableStatsRequest{Table: "bad name"},
+			fields: []string{"table"},
+		},
+		"list tombstones": {
+			req: &proto.ListTombstonesRequest{
+				Table: "bad name", Prefix: "champ 1", Limit: maxTombstoneLimit + 1,
+			},
+			fields: []string{"table", "prefix", "limit"},
+		},
+		"drop table": {
+			req:    &proto.DropTableRequest{Name: "wrestlers"},
+			fields: nil,
+		},
+		"import schema": {
+			req: &proto.ImportSchemaRequest{Schema: &proto.Schema{
+				Version: 1,
+				Tables: []*proto.TableSchema{
+					{Name: "default", Families: []string{"main"}},
+					{Name: "bad name", Families: []string{"", "bad family"}},
+					{},
+				},
+			}},
+			fields: []string{"schema.tables[1].name", "schema.tables[1].families[0]",
+				"schema.tables[1].families[1]", "schema.tables[2].name"},
+		},
+		"import schema without schema": {
+			req:    &proto.ImportSchemaRequest{DryRun: true},
+			fields: []string{"schema"},
+		},
+		"lock row": {
+			req:    &proto.LockRowRequest{RowKey: "champ 1", Table: "bad name", Ttl: -1},
+			fields: []string{"table", "row_key", "ttl"},
+		},
+		"aggregate": {
+			req: &proto.AggregateRequest{
+				Read: &proto.ReadRequest{RowKey: "match 1", Family: "bad family", Latest: -1},
+			},
+			fields: []string{"read.row_key", "read.family", "read.latest"},
+		},
+		"page tokens": {
+			req: &proto.ReadRequest{
+				RowKey: "champ:1", Family: "main", PageToken: EncodePageToken("champ:0"),
+			},
+			fields: []string{"page_token"},
+		},
+		"page token of a scan": {
+			req: &proto.ReadRequest{RowKey: "champ:", Family: "main",
+				QueryType: proto.QueryType_PREFIX, PageToken: EncodePageToken("champ 0")},
+			fields: []string{"page_token"},
+		},
+		"negative as of": {
+			req:    &proto.ReadRequest{RowKey: "champ:1", Family: "main", AsOfUnix: -1},
+			fields: []string{"as_of_unix"},
+		},
+		"json paths": {
+			req: &proto.ReadRequest{RowKey: "champ:1", Family: "main",
+				JsonPaths: []string{"region.name", "region..name", "region name", "a=b", ""}},
+			fields: []string{"json_paths[1]", "json_paths[2]", "json_paths[3]", "json_paths[4]"},
+		},
+		"aggregate json paths": {
+			req: &proto.AggregateRequest{
+				Read: &proto.ReadRequest{RowKey: "match:1", Family: "main",
+					JsonPaths: []string{"score"}},
+			},
+			fields: []string{"read.json_paths"},
+		},
+		"clone family": {
+			req:    &proto.CloneFamilyRequest{Source: "bad family"},
+			fields: []string{"source", "target"},
+		},
+		"eventual read at a sequence": {
+			req: &proto.ReadRequest{RowKey: "champ:1", Family: "main", ReadAt: 7,
+				Consistency: proto.Consistency_EVENTUAL},
+			fields: []string{"consistency"},
+		},
+		"write families": {
+			req: &proto.WriteRequest{
+				RowKey: "champ:1",
+				Families: []*proto.FamilyCells{{
+					Family:     "bad family",
+					Qualifiers: []*proto.ColumnQualifier{{Name: "bad name"}},
+				}},
+			},
+			fields: []string{"families[0].family", "families[0].qualifiers[0].name"},
+		},
+		"write options": {
+			req: &proto.WriteRequest{
+				RowKey:         "champ:1",
+				IdempotencyKey: "retry 1",
+				TimestampUnix:  -1,
+			},
+			fields: []string{"timestamp_unix", "idempotency_key"},
+		},
+		"list qualifiers": {
+			req: &proto.ListQualifiersRequest{
+				RowKey: "champ 1", Family: "bad family", Table: "bad name",
+			},
+			fields: []string{"table", "row_key", "family"},
+		},
+		"commit": {
+			req: &proto.CommitRequest{
+				ReadRows: []string{"champ 1"},
+				Mutations: []*proto.TransactionMutation{
+					{Mutation: &proto.TransactionMutation_Write{
+						Write: &proto.WriteRequest{RowKey: "champ:1", Family: "bad family"},
+					}},
+					{Mutation: &proto.TransactionMutation_Delete{
+						Delete: &proto.DeleteRequest{RowKey: "champ:2", Ttl: -1},
+					}},
+				},
+			},
+			fields: []string{"read_rows[0]", "mutations[0].write.family",
+				"mutations[1].delete.ttl"},
+		},
+		"sequence": {
+			req:    &proto.SequenceRequest{Table: "bad name"},
+			fields: []string{"table"},
+		},
+		"unlock row": {
+			req:    &proto.UnlockRowRequest{RowKey: "champ:1", FencingToken: 7},
+			fields: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			violations := v.validate(tc.req)
+
+			var fields []string
+			for _, fv := range violations {
+				fields = append(fields, fv.GetField())
+			}
+			require.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestValidator_unaryInterceptor(t *testing.T) {
+	v, err := newValidator(Limits{})
+	require.NoError(t, err)
+
+	called := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		called = true
+		return &proto.LitetableData{}, nil
+	}
+
+	t.Run("rejects before handler", func(t *testing.T) {
+		req := require.New(t)
+		called = false
+
+		resp, err := v.unaryInterceptor(context.Background(), &proto.WriteRequest{
+			RowKey: strings.Repeat("k", litetable.DefaultMaxRowKeyLength+1),
+			Family: "main",
+		}, nil, handler)
+		req.Nil(resp)
+		req.False(called)
+
+		st, ok := status.FromError(err)
+		req.True(ok)
+		req.Equal(codes.InvalidArgument, st.Code())
+		req.Len(st.Details(), 1)
+
+		badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+		req.True(ok)
+		req.Equal("row_key", badRequest.GetFieldViolations()[0].GetField())
+	})
+
+	t.Run("refuses parameters injected in a prefix", func(t *testing.T) {
+		req := require.New(t)
+		called = false
+
+		_, err := v.unaryInterceptor(context.Background(), &proto.ReadRequest{
+			RowKey:    "user table=other includeTombstones=true readAt=1",
+			Family:    "main",
+			QueryType: proto.QueryType_PREFIX,
+		}, nil, handler)
+		req.False(called)
+		req.Equal(codes.InvalidArgument, status.Code(err))
+		req.ErrorContains(err, "row_key cannot contain whitespace")
+	})
+
+	t.Run("passes valid requests through", func(t *testing.T) {
+		req := require.New(t)
+		called = false
+
+		resp, err := v.unaryInterceptor(context.Background(), &proto.ReadRequest{
+			RowKey: "champ:1",
+			Family: "main",
+		}, nil, handler)
+		req.NoError(err)
+		req.NotNil(resp)
+		req.True(called)
+	})
+}
+
+func TestServer_SetLimits(t *testing.T) {
+	req := require.New(t)
+	s := &Server{}
+	req.NoError(s.SetLimits(Limits{MaxQualifiers: 1}))
+	req.Equal(1, s.validator.Load().maxQualifiers)
+
+	// invalid limits keep the current validator
+	req.Error(s.SetLimits(Limits{FamilyNamePattern: "["}))
+	req.Equal(1, s.validator.Load().maxQualifiers)
+
+	// limits are checked without being applied
+	req.Error(ValidateLimits(Limits{FamilyNamePattern: "["}))
+	req.NoError(ValidateLimits(Limits{MaxQualifiers: 2}))
+	req.Equal(1, s.validator.Load().maxQualifiers)
+}
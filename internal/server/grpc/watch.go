@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Watch streams the writes and deletes of a row or key prefix until the client cancels.
+func (l *lt) Watch(msg *proto.WatchRequest, stream proto.LitetableService_WatchServer) error {
+	if l.watcher == nil {
+		return status.Errorf(codes.Unimplemented, "watch is not enabled on this server")
+	}
+	if (msg.GetRowKey() == "") == (msg.GetPrefix() == "") {
+		return status.Errorf(codes.InvalidArgument, "either rowKey or prefix required")
+	}
+
+	w, err := l.watcher.Watch(msg.GetRowKey(), msg.GetPrefix())
+	if err != nil {
+		return toStatus(err, "failed to watch")
+	}
+	defer l.watcher.Unwatch(w)
+
+	// the header tells the client the watch is in place, so it can read the rows and be sure
+	// to hear of any later change
+	if err = stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	requestid.Logger(ctx).Debug().Str("row-key", msg.GetRowKey()).
+		Str("prefix", msg.GetPrefix()).Msg("Watch started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-l.stopping:
+			return status.Errorf(codes.Unavailable, "server is stopping")
+		case evt, ok := <-w.Events():
+			if !ok {
+				if w.Overflowed() {
+					return status.Errorf(codes.ResourceExhausted,
+						"watch fell behind; re-read the watched rows and watch again")
+				}
+				if w.Kicked() {
+					return status.Errorf(codes.Aborted, "watch was ended by an operator")
+				}
+				return status.Errorf(codes.Unavailable, "change stream stopped")
+			}
+			if err = stream.Send(&proto.WatchEvent{
+				RowKey:        evt.RowKey,
+				Family:        evt.Family,
+				Qualifier:     evt.Qualifier,
+				Value:         evt.Value,
+				TimestampUnix: evt.Timestamp,
+				Tombstone:     evt.IsTombstone,
+				ExpiresAtUnix: evt.ExpiresAt,
+				Sequence:      evt.Sequence,
+				Table:         evt.Table,
+				TableSequence: evt.TableSequence,
+				NodeId:        evt.NodeID,
+				OldValue:      evt.OldValue,
+				HasOldValue:   evt.HasOldValue,
+			}); err != nil {
+				return err
+			}
+			w.Sent(evt)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package grpc
+
+import (
+	"context"
+	cdc "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+	"time"
+)
+
+// watchClient serves the Watch RPC from a CDC server and returns a client for it.
+func watchClient(t *testing.T, watcher watcher,
+	stopping chan struct{}) proto.LitetableServiceClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	proto.RegisterLitetableServiceServer(srv, &lt{watcher: watcher, stopping: stopping})
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return proto.NewLitetableServiceClient(conn)
+}
+
+// startCDC starts a CDC server on a free port.
+func startCDC(t *testing.T) *cdc.Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	s := cdc.New(&cdc.Config{Port: port, NodeID: "node-a"})
+	require.NoError(t, s.Start())
+	t.Cleanup(func() { _ = s.Stop() })
+	return s
+}
+
+func TestLt_Watch(t *testing.T) {
+	req := require.New(t)
+	cdcServer := startCDC(t)
+	client := watchClient(t, cdcServer, nil)
+
+	stream, err := client.Watch(context.Background(), &proto.WatchRequest{Prefix: "champ:"})
+	req.NoError(err)
+	// the header is sent once the watch is in place, so the events below are not missed
+	header, err := stream.Header()
+	req.NoError(err)
+	req.NotNil(header)
+
+	cdcServer.Emit(&cdc.CDCEvent{
+		Operation: litetable.OperationWrite,
+		RowKey:    "title:1",
+		Family:    "main",
+	})
+	cdcServer.Emit(&cdc.CDCEvent{
+		Operation:     litetable.OperationWrite,
+		RowKey:        "champ:1",
+		Family:        "main",
+		Qualifier:     "name",
+		Value:         []byte("Ahri"),
+		Timestamp:     10,
+		Table:         "champs",
+		TableSequence: 7,
+		OldValue:      []byte("Jinx"),
+		HasOldValue:   true,
+	})
+	cdcServer.Emit(&cdc.CDCEvent{
+		Operation:   litetable.OperationDelete,
+		RowKey:      "champ:1",
+		Family:      "main",
+		Qualifier:   "name",
+		Timestamp:   11,
+		IsTombstone: true,
+	})
+
+	evt, err := stream.Recv()
+	req.NoError(err)
+	req.Equal("champ:1", evt.GetRowKey())
+	req.Equal([]byte("Ahri"), evt.GetValue())
+	req.False(evt.GetTombstone())
+	req.Equal(uint64(2), evt.GetSequence())
+	req.Equal("champs", evt.GetTable())
+	req.Equal(uint64(7), evt.GetTableSequence())
+	req.Equal("node-a", evt.GetNodeId())
+	req.Equal([]byte("Jinx"), evt.GetOldValue())
+	req.True(evt.GetHasOldValue())
+
+	evt, err = stream.Recv()
+	req.NoError(err)
+	req.Equal(int64(11), evt.GetTimestampUnix())
+	req.True(evt.GetTombstone())
+	req.False(evt.GetHasOldValue())
+
+	// the stream ends once the change stream stops
+	req.NoError(cdcServer.Stop())
+	_, err = stream.Recv()
+	req.Equal(codes.Unavailable, status.Code(err))
+}
+
+func TestLt_Watch_kicked(t *testing.T) {
+	req := require.New(t)
+	cdcServer := startCDC(t)
+	client := watchClient(t, cdcServer, nil)
+
+	stream, err := client.Watch(context.Background(), &proto.WatchRequest{RowKey: "champ:1"})
+	req.NoError(err)
+	_, err = stream.Header()
+	req.NoError(err)
+
+	cdcServer.Emit(&cdc.CDCEvent{Operation: litetable.OperationWrite, RowKey: "champ:1"})
+	_, err = stream.Recv()
+	req.NoError(err)
+	req.Eventually(func() bool {
+		subscribers := cdcServer.Subscribers()
+		return len(subscribers) == 1 && subscribers[0].LastSequence == cdcServer.Sequence()
+	}, time.Second, time.Millisecond)
+
+	req.NoError(cdcServer.Kick(cdcServer.Subscribers()[0].ID))
+	_, err = stream.Recv()
+	req.Equal(codes.Aborted, status.Code(err))
+	req.Empty(cdcServer.Subscribers())
+}
+
+func TestLt_Watch_errors(t *testing.T) {
+	tests := map[string]struct {
+		watcher      bool
+		request      *proto.WatchRequest
+		stopping     bool
+		expectedCode codes.Code
+	}{
+		"not enabled": {
+			request:      &proto.WatchRequest{RowKey: "champ:1"},
+			expectedCode: codes.Unimplemented,
+		},
+		"neither row key nor prefix": {
+			watcher:      true,
+			request:      &proto.WatchRequest{},
+			expectedCode: codes.InvalidArgument,
+		},
+		"both row key and prefix": {
+			watcher:      true,
+			request:      &proto.WatchRequest{RowKey: "champ:1", Prefix: "champ:"},
+			expectedCode: codes.InvalidArgument,
+		},
+		"server stopping": {
+			watcher:      true,
+			request:      &proto.WatchRequest{RowKey: "champ:1"},
+			stopping:     true,
+			expectedCode: codes.Unavailable,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var w watcher
+			if tc.watcher {
+				w = cdc.New(&cdc.Config{})
+			}
+			stopping := make(chan struct{})
+			if tc.stopping {
+				close(stopping)
+			}
+
+			stream, err := watchClient(t, w, stopping).Watch(context.Background(), tc.request)
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -3,24 +3,38 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/requestid"
 	"github.com/litetable/litetable-db/pkg/proto"
-	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"net/url"
 	"time"
 )
 
 func (l *lt) validateWrite(msg *proto.WriteRequest) error {
 	var errGrp []error
-	if msg.GetFamily() == "" {
-		errGrp = append(errGrp, status.Errorf(codes.InvalidArgument, "family required"))
-	}
 	if msg.GetRowKey() == "" {
 		errGrp = append(errGrp, status.Errorf(codes.InvalidArgument, "rowKey required"))
 	}
-	if len(msg.GetQualifiers()) == 0 {
-		errGrp = append(errGrp, status.Errorf(codes.InvalidArgument, "qualifiers required"))
+	// family and qualifiers may be left out when the cells are all in families
+	if msg.GetFamily() != "" || len(msg.GetQualifiers()) > 0 || len(msg.GetFamilies()) == 0 {
+		if msg.GetFamily() == "" {
+			errGrp = append(errGrp, status.Errorf(codes.InvalidArgument, "family required"))
+		}
+		if len(msg.GetQualifiers()) == 0 {
+			errGrp = append(errGrp, status.Errorf(codes.InvalidArgument,
+				"qualifiers required"))
+		}
+	}
+	for i, cells := range msg.GetFamilies() {
+		if cells.GetFamily() == "" {
+			errGrp = append(errGrp, status.Errorf(codes.InvalidArgument,
+				"families[%d]: family required", i))
+		}
+		if len(cells.GetQualifiers()) == 0 {
+			errGrp = append(errGrp, status.Errorf(codes.InvalidArgument,
+				"families[%d]: qualifiers required", i))
+		}
 	}
 	return errors.Join(errGrp...)
 }
@@ -31,24 +45,60 @@ func (l *lt) Write(ctx context.Context, msg *proto.WriteRequest) (*proto.Litetab
 		return nil, err
 	}
 	now := time.Now()
-	log.Debug().Msgf("Write request: %v", msg)
-	// Ex: WRITE family="family" rowKey="rowKey" qualifier="qualifier" value="value"
-	queryStr := "family=" + msg.GetFamily()
-	queryStr += " key=" + msg.GetRowKey()
-	for _, qualifier := range msg.GetQualifiers() {
-		queryStr += " qualifier=" + qualifier.GetName()
-		if len(qualifier.GetValue()) > 0 {
-			// URL encode binary values to preserve all bytes properly
-			encodedValue := url.QueryEscape(string(qualifier.GetValue()))
-			queryStr += " value=" + encodedValue
-		}
-	}
-
-	result, err := l.operations.Write(queryStr)
+	requestid.Logger(ctx).Debug().Msgf("Write request: %v", msg)
+	result, err := l.operations.Write(writeQuery(msg))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to write data: %v", err)
+		return nil, toStatus(err, "failed to write data")
 	}
 
-	log.Debug().Msgf("Write latest: %v", time.Since(now))
+	requestid.Logger(ctx).Debug().Msgf("Write latest: %v", time.Since(now))
 	return convertToProtoData(result), nil
 }
+
+// writeQuery builds the query of a write.
+func writeQuery(msg *proto.WriteRequest) string {
+	// Ex: v2:family=family key=rowKey qualifier=qualifier value="a value"
+	queryStr := queryVersion
+	if msg.GetFamily() != "" || len(msg.GetQualifiers()) > 0 {
+		queryStr += "family=" + queryValue(msg.GetFamily()) + " "
+	}
+	queryStr += "key=" + queryValue(msg.GetRowKey())
+	queryStr += qualifiersQuery(msg.GetQualifiers())
+	// each family applies to the qualifiers that follow it
+	for _, cells := range msg.GetFamilies() {
+		queryStr += " family=" + queryValue(cells.GetFamily())
+		queryStr += qualifiersQuery(cells.GetQualifiers())
+	}
+	if ttl := msg.GetTtl(); ttl > 0 {
+		queryStr += " ttl=" + fmt.Sprintf("%d", ttl)
+	}
+	if msg.GetSync() == proto.WriteSync_BACKUP {
+		queryStr += " sync=backup"
+	}
+	if msg.GetTable() != "" {
+		queryStr += " table=" + queryValue(msg.GetTable())
+	}
+	if msg.GetFencingToken() != 0 {
+		queryStr += fmt.Sprintf(" fence=%d", msg.GetFencingToken())
+	}
+	if msg.GetTimestampUnix() > 0 {
+		queryStr += fmt.Sprintf(" timestamp=%d", msg.GetTimestampUnix())
+	}
+	if msg.GetIdempotencyKey() != "" {
+		queryStr += " idempotencyKey=" + queryValue(msg.GetIdempotencyKey())
+	}
+	return queryStr
+}
+
+// qualifiersQuery builds the qualifiers and values of a write query.
+func qualifiersQuery(qualifiers []*proto.ColumnQualifier) string {
+	var queryStr string
+	for _, qualifier := range qualifiers {
+		queryStr += " qualifier=" + queryValue(qualifier.GetName())
+		if len(qualifier.GetValue()) > 0 {
+			// values are written as they are, so every byte is kept
+			queryStr += " value=" + queryValue(string(qualifier.GetValue()))
+		}
+	}
+	return queryStr
+}
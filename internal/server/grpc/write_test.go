@@ -36,28 +36,28 @@ func TestLt_Write(t *testing.T) {
 					{Name: "q1", Value: []byte("v1")},
 				},
 			},
-			expectedQuery: "family=f1 key=r1 qualifier=q1 value=v1",
+			expectedQuery: "v2:family=f1 key=r1 qualifier=q1 value=v1",
 			mockSetup: func(m *Mockoperations) {
 				// URL encoding of "v1" = "v1" (no special chars)
 				m.EXPECT().
-					Write("family=f1 key=r1 qualifier=q1 value=v1").
+					Write("v2:family=f1 key=r1 qualifier=q1 value=v1").
 					Return(nil, errors.New("db down"))
 			},
 			expectedCode:    codes.Internal,
 			expectedMessage: "failed to write data: db down",
 		},
-		"successful write with encoded value": {
+		"successful write with a quoted value": {
 			request: &proto.WriteRequest{
 				Family: "f2",
 				RowKey: "r2",
 				Qualifiers: []*proto.ColumnQualifier{
-					{Name: "q2", Value: []byte("hello world!")}, // space & bang will be encoded
+					{Name: "q2", Value: []byte("hello world!")}, // the space is quoted
 				},
 			},
-			expectedQuery: "family=f2 key=r2 qualifier=q2 value=hello+world%21",
+			expectedQuery: "v2:family=f2 key=r2 qualifier=q2 value=\"hello world!\"",
 			mockSetup: func(m *Mockoperations) {
 				m.EXPECT().
-					Write("family=f2 key=r2 qualifier=q2 value=hello+world%21").
+					Write("v2:family=f2 key=r2 qualifier=q2 value=\"hello world!\"").
 					Return(map[string]*litetable2.Row{
 						"r2": {
 							Key: "r2",
@@ -72,6 +72,143 @@ func TestLt_Write(t *testing.T) {
 			expectedCode:    codes.OK,
 			expectedMessage: "",
 		},
+		"ttl is passed to the query": {
+			request: &proto.WriteRequest{
+				Family: "f2",
+				RowKey: "r2",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "q2", Value: []byte("v2")},
+				},
+				Ttl: 60,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Write("v2:family=f2 key=r2 qualifier=q2 value=v2 ttl=60").
+					Return(map[string]*litetable2.Row{"r2": {Key: "r2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"backup sync is passed to the query": {
+			request: &proto.WriteRequest{
+				Family: "f2",
+				RowKey: "r2",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "q2", Value: []byte("v2")},
+				},
+				Sync: proto.WriteSync_BACKUP,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Write("v2:family=f2 key=r2 qualifier=q2 value=v2 sync=backup").
+					Return(map[string]*litetable2.Row{"r2": {Key: "r2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"table is passed to the query": {
+			request: &proto.WriteRequest{
+				Family: "f2",
+				RowKey: "r2",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "q2", Value: []byte("v2")},
+				},
+				Table: "wwe",
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Write("v2:family=f2 key=r2 qualifier=q2 value=v2 table=wwe").
+					Return(map[string]*litetable2.Row{"r2": {Key: "r2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"fencing token is passed to the query": {
+			request: &proto.WriteRequest{
+				Family: "f2",
+				RowKey: "r2",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "q2", Value: []byte("v2")},
+				},
+				FencingToken: 42,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Write("v2:family=f2 key=r2 qualifier=q2 value=v2 fence=42").
+					Return(map[string]*litetable2.Row{"r2": {Key: "r2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"several families": {
+			request: &proto.WriteRequest{
+				Family: "f1",
+				RowKey: "r2",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "q1", Value: []byte("v1")},
+				},
+				Families: []*proto.FamilyCells{{
+					Family:     "f2",
+					Qualifiers: []*proto.ColumnQualifier{{Name: "q2", Value: []byte("v2")}},
+				}},
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Write("v2:family=f1 key=r2 qualifier=q1 value=v1 family=f2 qualifier=q2 value=v2").
+					Return(map[string]*litetable2.Row{"r2": {Key: "r2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"only families": {
+			request: &proto.WriteRequest{
+				RowKey: "r2",
+				Families: []*proto.FamilyCells{{
+					Family:     "f2",
+					Qualifiers: []*proto.ColumnQualifier{{Name: "q2", Value: []byte("v2")}},
+				}},
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Write("v2:key=r2 family=f2 qualifier=q2 value=v2").
+					Return(map[string]*litetable2.Row{"r2": {Key: "r2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"family without qualifiers": {
+			request: &proto.WriteRequest{
+				RowKey:   "r2",
+				Families: []*proto.FamilyCells{{Family: "f2"}},
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		"timestamp is passed to the query": {
+			request: &proto.WriteRequest{
+				Family: "f2",
+				RowKey: "r2",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "q2", Value: []byte("v2")},
+				},
+				TimestampUnix: 1000,
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Write("v2:family=f2 key=r2 qualifier=q2 value=v2 timestamp=1000").
+					Return(map[string]*litetable2.Row{"r2": {Key: "r2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		"idempotency key is passed to the query": {
+			request: &proto.WriteRequest{
+				Family: "f2",
+				RowKey: "r2",
+				Qualifiers: []*proto.ColumnQualifier{
+					{Name: "q2", Value: []byte("v2")},
+				},
+				IdempotencyKey: "retry-1",
+			},
+			mockSetup: func(m *Mockoperations) {
+				m.EXPECT().
+					Write("v2:family=f2 key=r2 qualifier=q2 value=v2 idempotencyKey=retry-1").
+					Return(map[string]*litetable2.Row{"r2": {Key: "r2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
 	}
 
 	for name, tc := range tests {
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/rs/zerolog"
+	"net/http"
+	"time"
+)
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// withRequestLogging assigns each request an ID, taken from the X-Request-Id header when the
+// client supplies one, echoes it in the response, and logs the outcome of the request.
+func withRequestLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := requestid.Resolve(r.Header.Get(requestid.Header))
+		ctx := requestid.NewContext(r.Context(), id)
+		w.Header().Set(requestid.Header, id)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		level := zerolog.InfoLevel
+		switch {
+		case rec.status >= http.StatusInternalServerError:
+			level = zerolog.ErrorLevel
+		case rec.status >= http.StatusBadRequest:
+			level = zerolog.WarnLevel
+		}
+
+		requestid.Logger(ctx).WithLevel(level).
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("peer", r.RemoteAddr).
+			Int("status", rec.status).
+			Dur("duration", time.Since(start)).
+			Msg("HTTP request")
+	})
+}
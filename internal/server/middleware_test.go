@@ -0,0 +1,50 @@
+package server
+
+import (
+	"github.com/litetable/litetable-db/internal/requestid"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithRequestLogging(t *testing.T) {
+	tests := map[string]struct {
+		header string
+		status int
+	}{
+		"generates an id": {
+			status: http.StatusOK,
+		},
+		"propagates the caller id": {
+			header: "abc-123",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			var seen string
+			h := withRequestLogging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = requestid.FromContext(r.Context())
+				w.WriteHeader(tc.status)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/health", nil)
+			if tc.header != "" {
+				r.Header.Set(requestid.Header, tc.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			req.Equal(tc.status, w.Code)
+			req.NotEmpty(seen)
+			req.Equal(seen, w.Header().Get(requestid.Header))
+			if tc.header != "" {
+				req.Equal(tc.header, seen)
+			}
+		})
+	}
+}
@@ -2,8 +2,12 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/litetable/litetable-db/internal/buildinfo"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"github.com/litetable/litetable-db/internal/requestid"
 	"github.com/rs/zerolog/log"
 	"net/http"
 	"time"
@@ -26,11 +30,14 @@ type Server struct {
 	port    int
 	router  *http.ServeMux
 	server  httpServer // Add this field
+	info    *buildinfo.Info
 }
 
 type Config struct {
 	Address string
 	Port    int
+	// Info is served on /version. Without it, /version is not served.
+	Info *buildinfo.Info
 }
 
 // validate checks the configuration for any errors
@@ -61,13 +68,23 @@ func New(cfg *Config) (*Server, error) {
 		address: cfg.Address,
 		port:    cfg.Port,
 		server:  &realHTTPServer{s: server},
+		info:    cfg.Info,
 	}
 	mux.HandleFunc("GET /health", m.Health)
-	server.Handler = mux
+	mux.Handle("GET /metrics", metrics.Handler())
+	if cfg.Info != nil {
+		mux.HandleFunc("GET /version", m.Version)
+	}
+	server.Handler = withRequestLogging(mux)
 
 	return m, nil
 }
 
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.server.Addr()
+}
+
 func (s *Server) Start() error {
 	log.Info().Msgf("HTTP server listening on %s", s.server.Addr())
 
@@ -113,15 +130,25 @@ func (s *Server) Name() string {
 }
 
 func (s *Server) Health(w http.ResponseWriter, r *http.Request) {
-	log.Debug().Msg("incoming health check")
+	logger := requestid.Logger(r.Context())
+	logger.Debug().Msg("incoming health check")
 	// Handle HTTP requests here
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	response := `{"status": "ok"}`
-	log.Debug().Msg("Health check response: " + response)
+	logger.Debug().Msg("Health check response: " + response)
 	_, _ = w.Write([]byte(response))
 }
 
+// Version returns the version and build of the server, so operators can check what is running
+// during a rollout.
+func (s *Server) Version(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.info.At(time.Now())); err != nil {
+		requestid.Logger(r.Context()).Error().Err(err).Msg("failed to write version")
+	}
+}
+
 func (r *realHTTPServer) ListenAndServe() error {
 	return r.s.ListenAndServe()
 }
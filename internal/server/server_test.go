@@ -1,14 +1,17 @@
 package server
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/litetable/litetable-db/internal/buildinfo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -183,3 +186,38 @@ func TestServer_Stop(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_Version(t *testing.T) {
+	tests := map[string]struct {
+		info       *buildinfo.Info
+		wantStatus int
+	}{
+		"info":        {info: buildinfo.New("memory", 8), wantStatus: http.StatusOK},
+		"not enabled": {wantStatus: http.StatusNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			srv, err := New(&Config{Address: "localhost", Port: 8080, Info: tc.info})
+			req.NoError(err)
+
+			rec := httptest.NewRecorder()
+			srv.server.(*realHTTPServer).s.Handler.ServeHTTP(rec,
+				httptest.NewRequest(http.MethodGet, "/version", nil))
+			req.Equal(tc.wantStatus, rec.Code)
+			if tc.info == nil {
+				return
+			}
+
+			var got buildinfo.Info
+			req.NoError(json.Unmarshal(rec.Body.Bytes(), &got))
+			req.Equal(buildinfo.Version, got.Version)
+			req.Equal("litetable.server.v1", got.APIVersion)
+			req.Equal(1, got.BackupFormatVersion)
+			req.Equal("memory", got.StorageEngine)
+			req.Equal(8, got.ShardCount)
+			req.Equal(tc.info.StartedAt, got.StartedAt)
+		})
+	}
+}
@@ -1,18 +1,48 @@
 package shard_storage
 
 import (
-	"fmt"
 	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
 	"github.com/litetable/litetable-db/internal/litetable"
 	"github.com/litetable/litetable-db/internal/shard_storage/reaper"
-	"github.com/rs/zerolog/log"
+	"maps"
+	"slices"
 )
 
+// ExpiresAt returns when a value of the family written at timestamp with expiresAt expires.
+// Values written without a TTL inherit the default of their family.
+func (m *Manager) ExpiresAt(family string, timestamp, expiresAt int64) int64 {
+	if ttl := m.familyPolicies[family].DefaultTTL; expiresAt == 0 && ttl > 0 {
+		return timestamp + int64(ttl)
+	}
+	return expiresAt
+}
+
 func (m *Manager) Apply(rowKey, family string, qualifiers []string, values [][]byte,
 	timestamp int64, expiresAt int64) error {
 	// Check if the family is allowed
 	if !m.IsFamilyAllowed(family) {
-		return fmt.Errorf("column family not allowed: %s", family)
+		return litetable.NewError(litetable.ErrorCodeFamilyMissing, "column family not allowed: %s",
+			family)
+	}
+
+	expiresAt = m.ExpiresAt(family, timestamp, expiresAt)
+
+	values, err := m.familyPolicies[family].encode(family, qualifiers, values)
+	if err != nil {
+		return err
+	}
+	stored := m.prepareWrite(family, values)
+
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
+	// the usage of the other shards is summed before this shard is locked, since a shard is
+	// never locked while holding another
+	familyQuota := m.familyPolicies[family].Quota
+	limited := m.quota.limited() || familyQuota.limited()
+	var used, familyUsed usage
+	if limited {
+		used, familyUsed = m.usageOf(family)
 	}
 
 	// find the shard index
@@ -25,68 +55,167 @@ func (m *Manager) Apply(rowKey, family string, qualifiers []string, values [][]b
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	// Ensure data structures exist
-	if s.data == nil {
-		s.data = make(map[string]map[string]litetable.VersionedQualifier)
+	r, exists := s.data[rowKey]
+	hasFamily := false
+	if exists {
+		_, hasFamily = r.family(family)
 	}
+	if limited {
+		if err := m.quota.check("table", used, writeUsage(stored.bytes, !exists)); err != nil {
+			return err
+		}
+		if err := familyQuota.check("family "+family, familyUsed,
+			writeUsage(stored.bytes, !hasFamily)); err != nil {
+			return err
+		}
+	}
+
+	m.write(s, rowKey, family, qualifiers, values, stored, timestamp, expiresAt,
+		m.nextSequence())
+	return nil
+}
 
-	if _, exists := s.data[rowKey]; !exists {
-		s.data[rowKey] = make(map[string]litetable.VersionedQualifier)
+// write stores the values of a write in a shard, numbered seq, as prepared by prepareWrite. The
+// caller holds the shard lock and has checked the family and quotas.
+func (m *Manager) write(s *shard, rowKey, family string, qualifiers []string, values [][]byte,
+	stored storedWrite, timestamp, expiresAt int64, seq uint64) {
+	if s.data == nil {
+		s.data = make(map[string]*row)
 	}
 
-	if _, exists := s.data[rowKey][family]; !exists {
-		s.data[rowKey][family] = make(map[string][]litetable.TimestampedValue)
+	r, exists := s.data[rowKey]
+	hasFamily := false
+	if exists {
+		_, hasFamily = r.family(family)
+	} else {
+		r = &row{}
+		s.data[rowKey] = r
 	}
+	fam := r.addFamily(family)
+	s.addUsage(family, stored.bytes, !exists, !hasFamily)
 
 	// Write all qualifier-value pairs with the same timestamp
 	for i, qualifier := range qualifiers {
-		value := values[i]
-
-		newValue := litetable.TimestampedValue{
-			Value:     value,
+		oldValue, hasOldValue := m.oldValue(fam[qualifier])
+		value := litetable.TimestampedValue{
+			Value:     stored.values[i],
 			Timestamp: timestamp,
+			ExpiresAt: expiresAt,
+			Seq:       seq,
 		}
-
-		// If we have an expiration time, mark as tombstone
-		// TODO: fix this bug. If we add a tombstone to a qualifier,
-		//  it won't return from call - duh!
-		if expiresAt > 0 {
-			newValue.IsTombstone = true
-			newValue.ExpiresAt = expiresAt
+		if stored.compressed != nil {
+			value.Compressed = stored.compressed[i]
 		}
-
-		s.data[rowKey][family][qualifier] = append(
-			s.data[rowKey][family][qualifier], newValue,
-		)
+		appendValue(fam, qualifier, value)
 
 		// Emit CDC event for each qualifier
-		if m.cdc != nil {
-			m.cdc.Emit(&v1.CDCEvent{
-				Operation:   litetable.OperationWrite,
-				RowKey:      rowKey,
-				Family:      family,
-				Qualifier:   qualifier,
-				Value:       newValue.Value,
-				Timestamp:   newValue.Timestamp,
-				IsTombstone: newValue.IsTombstone,
-				ExpiresAt:   expiresAt,
-			})
-		}
+		m.emit(&v1.CDCEvent{
+			Operation:     litetable.OperationWrite,
+			RowKey:        rowKey,
+			Family:        family,
+			Qualifier:     qualifier,
+			Value:         values[i],
+			Timestamp:     timestamp,
+			ExpiresAt:     expiresAt,
+			Table:         m.table,
+			TableSequence: seq,
+			OldValue:      oldValue,
+			HasOldValue:   hasOldValue,
+		})
 	}
 
-	// Handle garbage collection if an expiresAt time is passed
+	// Values written with a TTL are collected by the reaper once they expire
 	if expiresAt > 0 {
-		log.Debug().Msg("calling reaper on write operation")
+		m.logger.Debug().Msg("calling reaper on write operation")
 		m.reaper.Reap(&reaper.ReapParams{
 			RowKey:     rowKey,
 			Family:     family,
 			Qualifiers: qualifiers,
 			Timestamp:  timestamp,
 			ExpiresAt:  expiresAt,
+			Expiry:     true,
 		})
 	}
 
 	m.MarkRowChanged(family, rowKey)
+}
 
-	return nil
+// ApplyBatch applies a group of writes, returning the error of each. Unlike Commit, the writes
+// are independent: one that fails its family or quota check does not stop the others. Each
+// shard is locked once for all of its writes, which are applied in the order given with their
+// own sequence numbers.
+func (m *Manager) ApplyBatch(mutations []litetable.Mutation) []error {
+	errs := make([]error, len(mutations))
+	stored := make([]storedWrite, len(mutations))
+	written := make(map[string]int64)
+	byShard := make(map[int][]int)
+	for i, mutation := range mutations {
+		if !m.IsFamilyAllowed(mutation.Family) {
+			errs[i] = litetable.NewError(litetable.ErrorCodeFamilyMissing,
+				"column family not allowed: %s", mutation.Family)
+			continue
+		}
+		mutations[i].ExpiresAt = m.ExpiresAt(mutation.Family, mutation.Timestamp,
+			mutation.ExpiresAt)
+		values, err := m.familyPolicies[mutation.Family].encode(mutation.Family,
+			mutation.Qualifiers, mutation.Values)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		mutations[i].Values = values
+		stored[i] = m.prepareWrite(mutation.Family, values)
+		written[mutation.Family] += stored[i].bytes
+		shardKey := m.getShardIndex(mutation.RowKey)
+		byShard[shardKey] = append(byShard[shardKey], i)
+	}
+
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
+	// the usage of the shards is summed before they are locked, like in Apply, and the writes
+	// of the batch are added to it as they are applied
+	used, familyUsed := m.transactionUsage(written)
+
+	for _, shardKey := range slices.Sorted(maps.Keys(byShard)) {
+		s := m.shardMap[shardKey]
+		s.mutex.Lock()
+		for _, i := range byShard[shardKey] {
+			mutation := mutations[i]
+			added, familyAdded, err := m.checkWrite(s, mutation, stored[i].bytes, used,
+				familyUsed[mutation.Family])
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			used = used.add(added)
+			familyUsed[mutation.Family] = familyUsed[mutation.Family].add(familyAdded)
+			m.write(s, mutation.RowKey, mutation.Family, mutation.Qualifiers, mutation.Values,
+				stored[i], mutation.Timestamp, mutation.ExpiresAt, m.nextSequence())
+		}
+		s.mutex.Unlock()
+	}
+	return errs
+}
+
+// checkWrite checks that a write storing bytes fits the quotas of the table and its family, and
+// returns the usage it adds to each. The caller holds the shard lock.
+func (m *Manager) checkWrite(s *shard, mutation litetable.Mutation, bytes int64, used,
+	familyUsed usage) (usage, usage, error) {
+	r, exists := s.data[mutation.RowKey]
+	hasFamily := false
+	if exists {
+		_, hasFamily = r.family(mutation.Family)
+	}
+	added, familyAdded := writeUsage(bytes, !exists), writeUsage(bytes, !hasFamily)
+
+	if err := m.quota.check("table", used, added); err != nil {
+		return usage{}, usage{}, err
+	}
+	familyQuota := m.familyPolicies[mutation.Family].Quota
+	if err := familyQuota.check("family "+mutation.Family, familyUsed,
+		familyAdded); err != nil {
+		return usage{}, usage{}, err
+	}
+	return added, familyAdded, nil
 }
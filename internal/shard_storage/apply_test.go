@@ -0,0 +1,197 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestManager_Apply_ttl(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	m.familyPolicies = map[string]FamilyPolicy{"main": {DefaultTTL: time.Hour}}
+
+	now := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")}, now, 0))
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Akali")}, now+1,
+		now+2))
+
+	row, ok := m.GetRowByFamily("champ:1", "main")
+	req.True(ok)
+	values := (*row)["champ:1"]["main"]["name"]
+	req.Len(values, 2)
+
+	// the first write inherits the family TTL and the second overrides it; neither is a tombstone
+	req.Equal(now+int64(time.Hour), values[0].ExpiresAt)
+	req.Equal(now+2, values[1].ExpiresAt)
+	req.Equal(now+int64(time.Hour), m.ExpiresAt("main", now, 0))
+	req.Equal(now+2, m.ExpiresAt("main", now+1, now+2))
+	for _, v := range values {
+		req.False(v.IsTombstone)
+	}
+
+	// only the expired value is collected
+	m.takeChanges()
+	expired := expiredCells.With(m.table).Value()
+	cells, bytes := m.DeleteExpiredValues("champ:1", "main", []string{"name"})
+	req.Equal(1, cells)
+	req.Equal(litetable.TimestampedValue{Value: []byte("Akali")}.Size(), bytes)
+	req.Equal(expired+1, expiredCells.With(m.table).Value())
+	row, ok = m.GetRowByFamily("champ:1", "main")
+	req.True(ok)
+	values = (*row)["champ:1"]["main"]["name"]
+	req.Len(values, 1)
+	req.Equal([]byte("Ahri"), values[0].Value)
+	req.Contains(m.takeChanges()["champ:1"], "main")
+
+	// nothing else has expired
+	cells, _ = m.DeleteExpiredValues("champ:1", "main", []string{"name"})
+	req.Zero(cells)
+	cells, _ = m.DeleteExpiredValues("champ:2", "main", []string{"name"})
+	req.Zero(cells)
+}
+
+func TestManager_DeleteExpiredValues_removesEmptyRow(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	now := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "main", []string{"name", "role"},
+		[][]byte{[]byte("Ahri"), []byte("mage")}, now, now))
+
+	cells, _ := m.DeleteExpiredValues("champ:1", "main", []string{"name", "role"})
+	req.Equal(2, cells)
+	_, ok := m.GetRowByFamily("champ:1", "main")
+	req.False(ok)
+}
+
+func TestManager_ApplyBatch(t *testing.T) {
+	now := time.Now().UnixNano()
+	write := func(key, family, value string) litetable.Mutation {
+		return litetable.Mutation{
+			Operation:  litetable.OperationWrite,
+			RowKey:     key,
+			Family:     family,
+			Qualifiers: []string{"name"},
+			Values:     [][]byte{[]byte(value)},
+			Timestamp:  now,
+		}
+	}
+
+	tests := map[string]struct {
+		quota     Quota
+		mutations []litetable.Mutation
+		// expectErrs are the errors of each write, with nil for writes that are applied
+		expectErrs []error
+	}{
+		"writes across shards": {
+			mutations: []litetable.Mutation{
+				write("champ:1", "main", "Ahri"),
+				write("champ:2", "main", "Zed"),
+				write("champ:3", "main", "Lux"),
+				write("champ:1", "main", "Jinx"),
+			},
+			expectErrs: []error{nil, nil, nil, nil},
+		},
+		"missing family fails alone": {
+			mutations: []litetable.Mutation{
+				write("champ:1", "main", "Ahri"),
+				write("champ:2", "nope", "Zed"),
+			},
+			expectErrs: []error{nil, litetable.ErrFamilyMissing},
+		},
+		"earlier writes count against the quota": {
+			quota: Quota{MaxRows: 2},
+			mutations: []litetable.Mutation{
+				write("champ:1", "main", "Ahri"),
+				write("champ:1", "main", "Ahri"),
+				write("champ:2", "main", "Zed"),
+				write("champ:3", "main", "Lux"),
+			},
+			expectErrs: []error{nil, nil, nil, litetable.ErrExhausted},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			m := newTestManager(t)
+			m.quota = tc.quota
+			before := m.Sequence()
+
+			errs := m.ApplyBatch(tc.mutations)
+			req.Len(errs, len(tc.mutations))
+
+			applied := 0
+			for i, mutation := range tc.mutations {
+				if tc.expectErrs[i] != nil {
+					req.ErrorIs(errs[i], tc.expectErrs[i])
+					continue
+				}
+				req.NoError(errs[i])
+				applied++
+
+				row, ok := m.GetRowByFamily(mutation.RowKey, mutation.Family)
+				req.True(ok)
+				values := (*row)[mutation.RowKey][mutation.Family]["name"]
+				req.True(slices.ContainsFunc(values, func(v litetable.TimestampedValue) bool {
+					return string(v.Value) == string(mutation.Values[0])
+				}))
+			}
+			// every applied write has its own sequence number
+			req.Equal(before+uint64(applied), m.Sequence())
+		})
+	}
+}
+
+func TestManager_Apply_cdcEvents(t *testing.T) {
+	tests := map[string]struct {
+		oldValues bool
+		// wantOld are the old values expected on the events, nil for none
+		wantOld [][]byte
+	}{
+		"old values": {
+			oldValues: true,
+			wantOld:   [][]byte{nil, []byte("Ahri"), []byte("Jinx"), nil},
+		},
+		"no old values": {wantOld: [][]byte{nil, nil, nil, nil}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			events := &recordingCDC{}
+			m, _, err := New(&Config{
+				RootDir:        t.TempDir(),
+				FlushThreshold: 60,
+				SnapshotTimer:  5,
+				CDCEmitter:     events,
+				Table:          "champs",
+				CDCOldValues:   tc.oldValues,
+			})
+			req.NoError(err)
+			req.NoError(m.UpdateFamilies([]string{"main"}))
+
+			now := time.Now().UnixNano()
+			for i, value := range []string{"Ahri", "Jinx"} {
+				req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte(value)},
+					now+int64(i), 0))
+			}
+			req.NoError(m.Delete("champ:1", "main", []string{"name"}, now+2, now+20))
+			// a tombstone is not an old value
+			req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Vi")},
+				now+3, 0))
+
+			req.Len(events.events, 4)
+			for i, evt := range events.events {
+				req.Equal("champs", evt.Table)
+				req.Equal(uint64(i+1), evt.TableSequence)
+				req.Equal(tc.wantOld[i] != nil, evt.HasOldValue, i)
+				req.Equal(tc.wantOld[i], evt.OldValue, i)
+			}
+			req.Equal(litetable.OperationDelete, events.events[2].Operation)
+		})
+	}
+}
@@ -10,70 +10,302 @@
 package shard_storage
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/litetable/litetable-db/internal/litetable"
-	"github.com/rs/zerolog/log"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"time"
 )
 
-// saveBackup creates a new backup file with the provided data. It does not interact with the memory
-// cache.
-func (m *Manager) saveBackup(data *litetable.Data) error {
+// saveBackup creates a new backup file with the provided data and records it in the backup
+// catalog. It does not interact with the memory cache. The backup is encoded one row at a time,
+// so saving a large dataset does not hold a second copy of it in memory.
+func (m *Manager) saveBackup(data *litetable.Data, covered snapshotRange) error {
 	start := time.Now()
 	filename := filepath.Join(m.dataDir, fmt.Sprintf("backup-%d.db", start.UnixNano()))
 
-	dataBytes, err := json.Marshal(data)
+	sum, size, err := writeDataStream(filename, m.writeThrottle, func(w io.Writer) error {
+		if err := encodeBackup(w, *data); err != nil {
+			return err
+		}
+		// the temporary file is partly written, and neither synced nor renamed
+		faultPoint("backup_mid_write")
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to serialize snapshot: %w", err)
+		return fmt.Errorf("failed to write snapshot file: %w", err)
 	}
 
-	if err = os.WriteFile(filename, dataBytes, 0644); err != nil {
-		return fmt.Errorf("failed to write snapshot file: %w", err)
+	if err = writeManifest(filename, &litetable.BackupManifest{
+		File:          filepath.Base(filename),
+		FormatVersion: BackupFormatVersion,
+		CreatedAt:     start.UnixNano(),
+		Rows:          len(*data),
+		Bytes:         size,
+		Checksum:      sum,
+		FirstSnapshot: covered.first,
+		LastSnapshot:  covered.last,
+		Sequence:      covered.sequence,
+	}); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
 	}
 
-	log.Debug().Str("duration", time.Since(start).String()).Msgf("Backup saved to %s", filename)
+	m.logger.Debug().Str("duration", time.Since(start).String()).Msgf("Backup saved to %s", filename)
 	return nil
 }
 
-// loadFromLatestBackup loads the latest backup file into the data cache.
-func (m *Manager) loadFromLatestBackup() error {
-	start := time.Now()
-	latest, err := m.getLatestBackup()
+// encodeBackup writes the data as a JSON object, one row at a time. Rows are written in key
+// order, so the output is the same as marshalling the whole map at once.
+func encodeBackup(w io.Writer, data litetable.Data) error {
+	keys := make([]string, 0, len(data))
+	for rowKey := range data {
+		keys = append(keys, rowKey)
+	}
+	sort.Strings(keys)
+
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+	for i, rowKey := range keys {
+		key, err := json.Marshal(rowKey)
+		if err != nil {
+			return fmt.Errorf("failed to serialize row key %s: %w", rowKey, err)
+		}
+		row, err := json.Marshal(data[rowKey])
+		if err != nil {
+			return fmt.Errorf("failed to serialize row %s: %w", rowKey, err)
+		}
+
+		if i > 0 {
+			key = append([]byte(","), key...)
+		}
+		if _, err = w.Write(append(append(key, ':'), row...)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "}")
+	return err
+}
+
+// writeDataFile writes a backup or snapshot file followed by a checksum file next to it, so the
+// data can be verified before it is relied on. The data file is written within the budget of
+// throttle, which may be nil.
+func writeDataFile(filename string, throttle *WriteThrottle, data []byte) error {
+	_, _, err := writeDataStream(filename, throttle, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	return err
+}
+
+// writeDataStream is writeDataFile for data produced by encode, which writes through a buffer
+// to the file. It returns the hex SHA-256 and the size of what was written.
+func writeDataStream(filename string, throttle *WriteThrottle,
+	encode func(w io.Writer) error) (string, int64, error) {
+	hash := sha256.New()
+	counter := &countingWriter{}
+	err := writeFileAtomic(filename, func(file io.Writer) error {
+		buffered := bufio.NewWriterSize(
+			io.MultiWriter(throttle.writer(file), hash, counter), 1<<20)
+		if err := encode(buffered); err != nil {
+			return err
+		}
+		return buffered.Flush()
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get latest snapshot: %w", err)
+		return "", 0, err
 	}
 
-	// TODO: handle case where data is empty or missing
-	if latest == "" {
-		log.Debug().Msg("No snapshots found, nothing to load")
-		return nil
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if err = writeFileSync(filename+checksumSuffix, []byte(sum+"\n")); err != nil {
+		return "", 0, err
+	}
+	return sum, counter.n, nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// removeDataFile removes a backup or snapshot file and its checksum file, if it has one.
+func removeDataFile(filename string) error {
+	if err := os.Remove(filename); err != nil {
+		return err
+	}
+	if err := os.Remove(filename + checksumSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// writeFileSync writes data to a file and syncs it to stable storage before returning.
+func writeFileSync(filename string, data []byte) error {
+	return writeFileAtomic(filename, func(file io.Writer) error {
+		_, err := file.Write(data)
+		return err
+	})
+}
+
+// writeFileAtomic writes a file so a crash never leaves it truncated: write fills a temporary
+// file, which is synced and then renamed over filename, and the rename is synced by syncing the
+// directory. A failed write removes the temporary file.
+func writeFileAtomic(filename string, write func(file io.Writer) error) error {
+	tmp := filename + tempSuffix
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err = faults.failWrite(filename); err == nil {
+		err = write(file)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, filename)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
 	}
+	return syncDir(filepath.Dir(filename))
+}
 
-	dataBytes, err := os.ReadFile(latest)
+// syncDir syncs a directory, so the files created or renamed in it survive a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
 	if err != nil {
-		return fmt.Errorf("failed to read snapshot %s: %w", latest, err)
+		return err
+	}
+	if err = d.Sync(); err != nil {
+		_ = d.Close()
+		return err
 	}
+	return d.Close()
+}
 
-	var loadedData litetable.Data
-	if err := json.Unmarshal(dataBytes, &loadedData); err != nil {
-		return fmt.Errorf("failed to parse snapshot %s: %w", latest, err)
+// removeTempFiles removes the temporary files left in dir by writes interrupted by a crash.
+func removeTempFiles(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+tempSuffix))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err = os.Remove(file); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// Distribute data to shards concurrently, this is a blocking operation and will take some time
-	// based on the size of the data set, the number of shards and the number of logical CPU cores
-	// available on the system.
-	if err = m.distributeDataToShards(loadedData); err != nil {
+// loadFromLatestBackup loads the latest backup file into the data cache, and reports what it
+// loaded.
+func (m *Manager) loadFromLatestBackup() error {
+	start := time.Now()
+	loadedData, report, sequence, err := m.readLatestData()
+	if err != nil {
+		return err
+	}
+	// restoreSequence continues from the mark or from the loaded data, whichever is larger
+	m.sequence.Store(sequence)
+	if report.Backup == "" && report.Snapshots == 0 {
+		m.logger.Debug().Msg("No snapshots found, nothing to load")
+	} else if err = m.distributeDataToShards(loadedData); err != nil {
+		// Distribute data to shards concurrently, this is a blocking operation and will take
+		// some time based on the size of the data set, the number of shards and the number of
+		// logical CPU cores available on the system.
 		return fmt.Errorf("failed to distribute data to shards: %w", err)
 	}
 
-	log.Debug().Str("duration", time.Since(start).String()).Msg("Data loaded from backup")
+	report.Table = m.table
+	report.Rows = len(loadedData)
+	report.ShardRows = make([]int, len(m.shardMap))
+	families := make(map[string]struct{})
+	for rowKey, rowFamilies := range loadedData {
+		report.ShardRows[m.getShardIndex(rowKey)]++
+		for family := range rowFamilies {
+			families[family] = struct{}{}
+		}
+	}
+	report.RowSkew = skew(report.ShardRows)
+	report.Families = slices.Sorted(maps.Keys(families))
+	report.Duration = time.Since(start)
+	m.loadReport = report
+
+	m.logger.Info().Str("table", report.Table).Str("backup", report.Backup).
+		Int("snapshots", report.Snapshots).Int("rows", report.Rows).
+		Ints("shardRows", report.ShardRows).Float64("rowSkew", report.RowSkew).
+		Strs("families", report.Families).Dur("duration", report.Duration).
+		Msg("Table loaded")
 	return nil
 }
 
+// LoadReport returns what the table loaded from disk when it started.
+func (m *Manager) LoadReport() litetable.LoadReport {
+	return m.loadReport
+}
+
+// readLatestData reads the latest backup with its deltas and applies the snapshots that have not
+// been merged into it. The report names the backup and counts the snapshots read, and neither
+// is set when there was nothing to read. It also returns the largest sequence mark recorded by
+// what it read, which is zero for data written before marks were recorded.
+func (m *Manager) readLatestData() (litetable.Data, litetable.LoadReport, uint64, error) {
+	var report litetable.LoadReport
+	catalog, err := m.ListBackups()
+	if err != nil {
+		return nil, report, 0, fmt.Errorf("failed to get latest snapshot: %w", err)
+	}
+
+	loadedData := make(litetable.Data)
+	var sequence uint64
+	if len(catalog) > 0 {
+		manifest := catalog[len(catalog)-1]
+		latest := filepath.Join(m.dataDir, manifest.File)
+		if loadedData, sequence, err = m.readBackup(latest); err != nil {
+			return nil, report, 0, err
+		}
+		sequence = max(sequence, manifest.Sequence)
+		report.Backup = manifest.File
+	}
+
+	// A crash between saving a snapshot and merging it leaves changes that are in no backup.
+	// Snapshots replace whole families, so applying one that was merged before the crash again
+	// changes nothing.
+	snapshotFiles, err := m.snapshotFiles()
+	if err != nil {
+		return nil, report, 0, err
+	}
+	for _, file := range snapshotFiles {
+		snapshot, err := readSnapshot(file)
+		if err != nil {
+			return nil, report, 0, err
+		}
+		m.applyChanges(loadedData, snapshot.SnapshotData)
+		sequence = max(sequence, snapshot.Sequence)
+	}
+	report.Snapshots = len(snapshotFiles)
+
+	return loadedData, report, sequence, nil
+}
+
 // loadLatestBackup attempts to read and parse the latest backup file.
 func (m *Manager) loadLatestBackup() (litetable.Data, error) {
 	latest, err := m.getLatestBackup()
@@ -83,67 +315,81 @@ func (m *Manager) loadLatestBackup() (litetable.Data, error) {
 	if latest == "" {
 		return make(litetable.Data), nil
 	}
+	data, _, err := m.readBackup(latest)
+	return data, err
+}
 
-	data, err := os.ReadFile(latest)
+// readBackup reads a full backup and applies its deltas, oldest first. It returns the largest
+// sequence mark of the deltas.
+func (m *Manager) readBackup(file string) (litetable.Data, uint64, error) {
+	data, err := os.ReadFile(file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read backup %s: %w", latest, err)
+		return nil, 0, fmt.Errorf("failed to read backup %s: %w", file, err)
 	}
 
 	var parsed litetable.Data
 	if err := json.Unmarshal(data, &parsed); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal backup %s: %w", latest, err)
+		return nil, 0, fmt.Errorf("failed to unmarshal backup %s: %w", file, err)
+	}
+	if parsed == nil {
+		parsed = make(litetable.Data)
+	}
+
+	deltas, err := m.deltasFor(filepath.Base(file))
+	if err != nil {
+		return nil, 0, err
+	}
+	var sequence uint64
+	for _, d := range deltas {
+		for _, c := range d.Changes {
+			m.applyChanges(parsed, c)
+		}
+		sequence = max(sequence, d.Sequence)
 	}
-	return parsed, nil
+	return parsed, sequence, nil
 }
 
-// getLatestBackup returns the latest full-snapshot file in the data directory.
+// getLatestBackup returns the newest backup in the catalog.
 func (m *Manager) getLatestBackup() (string, error) {
-	files, err := filepath.Glob(filepath.Join(m.dataDir, backupFileGlob))
+	catalog, err := m.ListBackups()
 	if err != nil {
 		return "", err
 	}
 
-	if len(files) == 0 {
+	if len(catalog) == 0 {
 		// No snapshots yet, nothing to load
 		return "", nil
 	}
 
-	// Find the newest snapshot file
-	latest := files[0]
-	for _, file := range files {
-		if file > latest {
-			latest = file
-		}
-	}
-
-	return latest, nil
+	return filepath.Join(m.dataDir, catalog[len(catalog)-1].File), nil
 }
 
-// maintainBackupLimit checks the number of snapshot files in the directory and prunes the oldest
-// ones if the limit is exceeded.
+// maintainBackupLimit prunes every backup in the catalog that is neither one of the newest
+// maxSnapshotLimit backups nor kept by the backup retention rules.
 func (m *Manager) maintainBackupLimit() {
-	// List all snapshot files
-	files, err := filepath.Glob(filepath.Join(m.dataDir, backupFileGlob))
+	catalog, err := m.ListBackups()
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to list snapshot files")
+		m.logger.Error().Err(err).Msg("Failed to list snapshot files")
 		return
 	}
 
 	// If we're under the limit, no pruning needed
-	if len(files) <= m.maxSnapshotLimit {
+	if len(catalog) <= m.maxSnapshotLimit {
 		return
 	}
 
-	// Sort files by name (which contains timestamp)
-	// This works because the timestamp format ensures lexicographical sorting matches chronological order
-	sort.Strings(files)
-
-	// Delete the oldest files, keeping only the configured limit
-	for i := 0; i < len(files)-m.maxSnapshotLimit; i++ {
-		if err = os.Remove(files[i]); err != nil {
-			log.Error().Err(err).Msgf("Failed to remove old snapshot %s:\n", files[i])
+	keep := m.backupRetention.retained(catalog, m.maxSnapshotLimit, time.Now())
+	for _, backup := range catalog {
+		if keep[backup.File] {
+			continue
+		}
+		file := filepath.Join(m.dataDir, backup.File)
+		if err = removeBackup(file); err != nil {
+			m.logger.Error().Err(err).Msgf("Failed to remove old snapshot %s:\n", file)
 		} else {
-			log.Debug().Msgf("Pruned old snapshot: %s\n", files[i])
+			m.logger.Debug().Msgf("Pruned old snapshot: %s\n", file)
 		}
 	}
+
+	m.pruneDeltas()
 }
@@ -1,9 +1,15 @@
 package shard_storage
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -82,3 +88,242 @@ func extractTimestamp(filename string) int64 {
 	}
 	return timestamp
 }
+
+func TestEncodeBackup(t *testing.T) {
+	tests := map[string]litetable.Data{
+		"empty": {},
+		"rows": {
+			"champ:2": {"main": {"name": {{Value: []byte("Annie"), Timestamp: 2}}}},
+			"champ:1": {"main": {
+				"name": {{Value: []byte("Ahri"), Timestamp: 1}},
+				"role": {{Timestamp: 3, IsTombstone: true, ExpiresAt: 4}},
+			}},
+			"<html>&": {"main": {"name": {{Value: []byte("escaped"), Timestamp: 5}}}},
+		},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			var out bytes.Buffer
+			req.NoError(encodeBackup(&out, data))
+
+			want, err := json.Marshal(data)
+			req.NoError(err)
+			req.Equal(string(want), out.String())
+		})
+	}
+}
+
+func TestWriteDataStream(t *testing.T) {
+	req := require.New(t)
+	filename := filepath.Join(t.TempDir(), "backup-1.db")
+	data := litetable.Data{
+		"champ:1": {"main": {"name": {{Value: []byte("Ahri"), Timestamp: 1}}}},
+	}
+
+	sum, size, err := writeDataStream(filename, nil, func(w io.Writer) error {
+		return encodeBackup(w, data)
+	})
+	req.NoError(err)
+
+	written, err := os.ReadFile(filename)
+	req.NoError(err)
+	req.Equal(int64(len(written)), size)
+
+	actual := sha256.Sum256(written)
+	req.Equal(hex.EncodeToString(actual[:]), sum)
+	checksum, err := os.ReadFile(filename + checksumSuffix)
+	req.NoError(err)
+	req.Equal(sum+"\n", string(checksum))
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	req := require.New(t)
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "backup-1.db")
+	req.NoError(writeFileSync(filename, []byte("{}")))
+
+	// a failed write leaves the previous file in place and no temporary file behind
+	err := writeFileAtomic(filename, func(file io.Writer) error {
+		_, _ = file.Write([]byte(`{"champ:1":`))
+		return fmt.Errorf("disk full")
+	})
+	req.EqualError(err, "disk full")
+
+	data, err := os.ReadFile(filename)
+	req.NoError(err)
+	req.Equal("{}", string(data))
+	req.NoFileExists(filename + tempSuffix)
+}
+
+func TestNew_removesTempFiles(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	stale := []string{
+		filepath.Join(m.dataDir, "backup-1.db"+tempSuffix),
+		filepath.Join(m.snapshotDir, "ss-incr-1.db"+tempSuffix),
+	}
+	for _, file := range stale {
+		req.NoError(os.WriteFile(file, []byte("{"), 0644))
+	}
+
+	_, _, err := New(&Config{
+		RootDir:        m.rootDir,
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+	})
+	req.NoError(err)
+	for _, file := range stale {
+		req.NoFileExists(file)
+	}
+}
+
+// TestManager_loadFromLatestBackup_formats restores the data directories checked in under
+// testdata/formats, one for each generation of the files on disk. They are never regenerated: a
+// format change adds a generation, and every older one must still restore the same data.
+//   - v0 was written before checksums, manifests, delta backups and snapshot epochs
+//   - v1 has checksums, a manifest for each backup, a delta backup and snapshots with epochs
+func TestManager_loadFromLatestBackup_formats(t *testing.T) {
+	const (
+		v0 = int64(1700000000) * int64(time.Second)
+		v1 = int64(1710000000) * int64(time.Second)
+		// farFuture is when the tombstones and TTLs of the v1 files expire
+		farFuture = int64(4102444800) * int64(time.Second)
+	)
+	sec := int64(time.Second)
+
+	tests := map[string]struct {
+		// rows are the versions of the name of each row after the restore, oldest first. A row
+		// without versions was deleted.
+		rows          map[string][]litetable.TimestampedValue
+		formatVersion int
+		createdAt     int64
+	}{
+		"v0": {
+			rows: map[string][]litetable.TimestampedValue{
+				"champ:1": {{Value: []byte("Ahri"), Timestamp: v0 - 10*sec}},
+				"champ:2": nil,
+				"champ:3": {{Value: []byte("Ashe"), Timestamp: v0 + 50*sec}},
+			},
+			createdAt: v0,
+		},
+		"v1": {
+			rows: map[string][]litetable.TimestampedValue{
+				"champ:1": {
+					{Value: []byte("Ahri"), Timestamp: v1 - 10*sec, Seq: 1},
+					{Value: []byte("Akali"), Timestamp: v1 + 120*sec, Seq: 4},
+				},
+				"champ:2": {
+					{Value: []byte("Annie"), Timestamp: v1 - 9*sec, Seq: 2},
+					{Timestamp: v1 + 130*sec, IsTombstone: true, ExpiresAt: farFuture, Seq: 5},
+				},
+				"champ:3": {{Value: []byte("Ashe"), Timestamp: v1 + 250*sec, Seq: 6}},
+				"champ:4": nil,
+			},
+			formatVersion: 1,
+			createdAt:     v1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			// the restore runs on a copy, so the checked in files are never changed
+			root := t.TempDir()
+			req.NoError(os.CopyFS(root, os.DirFS(filepath.Join("testdata", "formats", name))))
+
+			m, _, err := New(&Config{
+				RootDir:        root,
+				FlushThreshold: 60,
+				SnapshotTimer:  5,
+				CDCEmitter:     fakeCDC{},
+			})
+			req.NoError(err)
+			req.Equal([]string{"main"}, m.GetFamilies())
+			req.NoError(m.loadFromLatestBackup())
+
+			for rowKey, want := range tc.rows {
+				row, ok := m.GetRowByFamily(rowKey, "main")
+				if want == nil {
+					req.False(ok, rowKey)
+					continue
+				}
+				req.True(ok, rowKey)
+				req.Equal(want, (*row)[rowKey]["main"]["name"], rowKey)
+			}
+
+			catalog, err := m.ListBackups()
+			req.NoError(err)
+			req.Len(catalog, 1)
+			req.Equal(tc.formatVersion, catalog[0].FormatVersion)
+			req.Equal(tc.createdAt, catalog[0].CreatedAt)
+
+			report, err := Verify(root)
+			req.NoError(err)
+			req.True(report.OK())
+
+			// merged into the current format, the data reads back the same
+			req.NoError(m.ApplyDirectSnapshots())
+			backup, err := m.loadLatestBackup()
+			req.NoError(err)
+			for rowKey, want := range tc.rows {
+				req.Equal(want, backup[rowKey]["main"]["name"], rowKey)
+			}
+			report, err = Verify(root)
+			req.NoError(err)
+			req.True(report.OK())
+		})
+	}
+}
+
+func TestManager_loadFromLatestBackup_report(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	// nothing to load is still reported
+	req.NoError(m.loadFromLatestBackup())
+	req.Equal(litetable.DefaultTable, m.LoadReport().Table)
+	req.Empty(m.LoadReport().Backup)
+	req.Zero(m.LoadReport().Rows)
+	req.Len(m.LoadReport().ShardRows, len(m.shardMap))
+
+	apply := func(rowKey string) {
+		req.NoError(m.Apply(rowKey, "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+			time.Now().UnixNano(), 0))
+	}
+	apply("champ:1")
+	apply("champ:2")
+	req.NoError(m.Flush())
+	// a snapshot not merged into the backup yet is applied over it
+	apply("champ:3")
+	req.NoError(m.createDirectSnapshot())
+
+	restarted, _, err := New(&Config{
+		RootDir:        m.rootDir,
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+		Table:          "champions",
+	})
+	req.NoError(err)
+	req.NoError(restarted.loadFromLatestBackup())
+
+	report := restarted.LoadReport()
+	req.Equal("champions", report.Table)
+	catalog, err := m.ListBackups()
+	req.NoError(err)
+	req.Equal(catalog[len(catalog)-1].File, report.Backup)
+	req.Equal(1, report.Snapshots)
+	req.Equal(3, report.Rows)
+	shardRows := 0
+	for _, rows := range report.ShardRows {
+		shardRows += rows
+	}
+	req.Equal(3, shardRows)
+	req.GreaterOrEqual(report.RowSkew, 1.0)
+	req.Equal([]string{"main"}, report.Families)
+	req.Positive(report.Duration)
+}
@@ -0,0 +1,205 @@
+package shard_storage
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/logging"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// BalanceSlots is the number of hash slots a slot map assigns to shards.
+const BalanceSlots = 1024
+
+const (
+	// SchemeHash places a row on the shard its key hash modulo the shard count names, as the
+	// storage does today.
+	SchemeHash = "hash"
+	// SchemeSlots places a row in one of BalanceSlots hash slots, and assigns the slots to
+	// shards so every shard holds about as many bytes.
+	SchemeSlots = "slots"
+)
+
+// ShardBalance is how the rows of a table spread over a number of shards with one scheme.
+type ShardBalance struct {
+	Scheme string
+	Shards int
+	// Rows and Bytes are the rows and the estimated value bytes of each shard
+	Rows  []int
+	Bytes []int64
+	// RowSkew and ByteSkew are the largest shard over the mean shard: 1 is perfectly even
+	RowSkew  float64
+	ByteSkew float64
+	// Moved is the fraction of rows on another shard than where the storage has them today
+	Moved float64
+}
+
+// BalanceReport compares the spread of a table's rows over its current shards with other shard
+// counts and schemes.
+type BalanceReport struct {
+	Rows  int
+	Bytes int64
+	// Current is the spread the storage has today
+	Current *ShardBalance
+	// Candidates are the spreads of the other shard counts, by hash and by slot map
+	Candidates []*ShardBalance
+}
+
+// AnalyzeBalance reads the latest data under rootDir, as the storage would load it, and reports
+// how its rows spread over current shards and over each of counts with a hash or a slot map.
+// Nothing is changed on disk.
+func AnalyzeBalance(rootDir string, current int, counts []int) (*BalanceReport, error) {
+	if _, err := os.Stat(rootDir); err != nil {
+		return nil, err
+	}
+	if current < 1 {
+		return nil, fmt.Errorf("current shard count must be at least 1, got %d", current)
+	}
+	for _, count := range counts {
+		if count < 1 || count > BalanceSlots {
+			return nil, fmt.Errorf("shard counts must be between 1 and %d, got %d",
+				BalanceSlots, count)
+		}
+	}
+
+	m := &Manager{
+		dataDir:     filepath.Join(rootDir, backupDirName),
+		snapshotDir: filepath.Join(rootDir, snapshotDir),
+		logger:      logging.For("shard_storage"),
+	}
+	data, _, _, err := m.readLatestData()
+	if err != nil {
+		return nil, err
+	}
+	return analyzeBalance(data, current, counts), nil
+}
+
+// balanceRow is a row key with its hash slot and size.
+type balanceRow struct {
+	key   string
+	slot  int
+	bytes int64
+}
+
+func analyzeBalance(data litetable.Data, current int, counts []int) *BalanceReport {
+	report := &BalanceReport{Rows: len(data)}
+	rows := make([]balanceRow, 0, len(data))
+	for key, families := range data {
+		row := balanceRow{key: key, slot: shardIndex(key, BalanceSlots)}
+		for _, qualifiers := range families {
+			_, bytes := cellSizes(qualifiers)
+			row.bytes += bytes
+		}
+		report.Bytes += row.bytes
+		rows = append(rows, row)
+	}
+
+	today := func(row balanceRow) int { return shardIndex(row.key, current) }
+	report.Current = spread(rows, SchemeHash, current, today, today)
+	for _, count := range counts {
+		report.Candidates = append(report.Candidates,
+			spread(rows, SchemeHash, count, func(row balanceRow) int {
+				return shardIndex(row.key, count)
+			}, today))
+
+		slots := slotMap(rows, count)
+		report.Candidates = append(report.Candidates,
+			spread(rows, SchemeSlots, count, func(row balanceRow) int {
+				return slots[row.slot]
+			}, today))
+	}
+	return report
+}
+
+// slotMap assigns the hash slots to count shards, the largest slots first, each to the shard
+// holding the fewest bytes so far.
+func slotMap(rows []balanceRow, count int) []int {
+	slotBytes := make([]int64, BalanceSlots)
+	for _, row := range rows {
+		slotBytes[row.slot] += row.bytes
+	}
+	order := make([]int, BalanceSlots)
+	for slot := range order {
+		order[slot] = slot
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(slotBytes[b], slotBytes[a])
+	})
+
+	slots := make([]int, BalanceSlots)
+	shardBytes := make([]int64, count)
+	for _, slot := range order {
+		shard := slices.Index(shardBytes, slices.Min(shardBytes))
+		slots[slot] = shard
+		shardBytes[shard] += slotBytes[slot]
+	}
+	return slots
+}
+
+// spread places every row on a shard and measures the result against where the rows are today.
+func spread(rows []balanceRow, scheme string, count int, shardOf,
+	today func(balanceRow) int) *ShardBalance {
+	b := &ShardBalance{
+		Scheme: scheme,
+		Shards: count,
+		Rows:   make([]int, count),
+		Bytes:  make([]int64, count),
+	}
+	moved := 0
+	for _, row := range rows {
+		shard := shardOf(row)
+		b.Rows[shard]++
+		b.Bytes[shard] += row.bytes
+		if shard != today(row) {
+			moved++
+		}
+	}
+	b.RowSkew = skew(b.Rows)
+	b.ByteSkew = skew(b.Bytes)
+	if len(rows) > 0 {
+		b.Moved = float64(moved) / float64(len(rows))
+	}
+	return b
+}
+
+// skew returns the largest value over the mean, or 1 when there is nothing to spread.
+func skew[T int | int64](values []T) float64 {
+	var total T
+	for _, v := range values {
+		total += v
+	}
+	if total == 0 {
+		return 1
+	}
+	mean := float64(total) / float64(len(values))
+	return float64(slices.Max(values)) / mean
+}
+
+// Write prints the report in a human-readable form, with the best candidate by byte skew.
+func (r *BalanceReport) Write(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "%d rows, %d bytes\n", r.Rows, r.Bytes)
+	_, _ = fmt.Fprintf(w, "%-8s %-6s %9s %10s %7s\n", "scheme", "shards", "row skew", "byte skew",
+		"moved")
+	line := func(b *ShardBalance, note string) {
+		_, _ = fmt.Fprintf(w, "%-8s %-6d %9.2f %10.2f %6.1f%%%s\n", b.Scheme, b.Shards, b.RowSkew,
+			b.ByteSkew, b.Moved*100, note)
+	}
+	line(r.Current, " (current)")
+
+	best := r.Current
+	for _, b := range r.Candidates {
+		line(b, "")
+		if b.ByteSkew < best.ByteSkew {
+			best = b
+		}
+	}
+	if best == r.Current {
+		_, _ = fmt.Fprintln(w, "no candidate is better balanced than the current shards")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "best: %d shards by %s, byte skew %.2f -> %.2f, moving %.1f%% of rows\n",
+		best.Shards, best.Scheme, r.Current.ByteSkew, best.ByteSkew, best.Moved*100)
+}
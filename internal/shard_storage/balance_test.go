@@ -0,0 +1,68 @@
+package shard_storage
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeBalance(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	now := time.Now().UnixNano()
+	for i := range 500 {
+		req.NoError(m.Apply(fmt.Sprintf("champ:%d", i), "main", []string{"name"},
+			[][]byte{[]byte("Ahri")}, now, 0))
+	}
+	// one row holding far more than the others skews its shard
+	req.NoError(m.Apply("champ:big", "main", []string{"bio"},
+		[][]byte{bytes.Repeat([]byte("a"), 50_000)}, now, 0))
+	req.NoError(m.Flush())
+
+	report, err := AnalyzeBalance(m.rootDir, 2, []int{2, 4})
+	req.NoError(err)
+	req.Equal(501, report.Rows)
+
+	req.Equal(SchemeHash, report.Current.Scheme)
+	req.Zero(report.Current.Moved)
+	req.Len(report.Candidates, 4)
+	for _, b := range append(report.Candidates, report.Current) {
+		var rows int
+		var size int64
+		for shard := range b.Shards {
+			rows += b.Rows[shard]
+			size += b.Bytes[shard]
+		}
+		req.Equal(report.Rows, rows)
+		req.Equal(report.Bytes, size)
+		req.GreaterOrEqual(b.ByteSkew, 1.0)
+	}
+
+	// the same count by hash is where the rows are today
+	req.Equal(report.Current.Rows, report.Candidates[0].Rows)
+	// a slot map is at least as even as the hash of the same count
+	for i := 0; i < len(report.Candidates); i += 2 {
+		hash, slots := report.Candidates[i], report.Candidates[i+1]
+		req.Equal(SchemeSlots, slots.Scheme)
+		req.Equal(hash.Shards, slots.Shards)
+		req.LessOrEqual(slots.ByteSkew, hash.ByteSkew)
+	}
+
+	var out bytes.Buffer
+	report.Write(&out)
+	req.Contains(out.String(), "501 rows")
+	req.Contains(out.String(), "(current)")
+
+	_, err = AnalyzeBalance(m.rootDir, 2, []int{0})
+	req.ErrorContains(err, "shard counts must be between 1 and 1024")
+}
+
+func TestSkew(t *testing.T) {
+	require.Equal(t, 1.0, skew([]int{0, 0}))
+	require.Equal(t, 1.0, skew([]int{5, 5}))
+	require.Equal(t, 1.5, skew([]int64{3, 1}))
+	require.Equal(t, 4.0, skew([]int{4, 0, 0, 0}))
+}
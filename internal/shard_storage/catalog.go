@@ -0,0 +1,121 @@
+package shard_storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// BackupFormatVersion is the version recorded in the manifest of every new backup
+	BackupFormatVersion = 1
+	// DeltaFormatVersion and SnapshotFormatVersion are the versions of new delta backups and
+	// snapshots
+	DeltaFormatVersion    = 1
+	SnapshotFormatVersion = 1
+	// manifestSuffix replaces the .db extension of a backup file to name its manifest
+	manifestSuffix = ".manifest.json"
+)
+
+// snapshotRange is the span of incremental snapshots merged into a backup, with the largest
+// sequence mark among them.
+type snapshotRange struct {
+	first, last int64
+	sequence    uint64
+}
+
+// manifestPath returns the path of the manifest describing a backup file.
+func manifestPath(backupFile string) string {
+	return strings.TrimSuffix(backupFile, filepath.Ext(backupFile)) + manifestSuffix
+}
+
+// writeManifest records a backup in the catalog. It is written after the backup itself, so a
+// manifest always describes a complete backup.
+func writeManifest(backupFile string, manifest *litetable.BackupManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to serialize manifest: %w", err)
+	}
+	return writeFileSync(manifestPath(backupFile), data)
+}
+
+// removeBackup removes a backup along with its checksum and manifest.
+func removeBackup(backupFile string) error {
+	if err := removeDataFile(backupFile); err != nil {
+		return err
+	}
+	if err := os.Remove(manifestPath(backupFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ListBackups returns the backup catalog, oldest first. Backups written before manifests are
+// listed with the creation time in their file name and their size on disk, and so are backups
+// whose manifest cannot be read, so one bad manifest never hides the other backups.
+func (m *Manager) ListBackups() ([]*litetable.BackupManifest, error) {
+	files, err := filepath.Glob(filepath.Join(m.dataDir, backupFileGlob))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	catalog := make([]*litetable.BackupManifest, 0, len(files))
+	for _, file := range files {
+		manifest, err := readManifest(file)
+		if err != nil {
+			m.logger.Warn().Err(err).Str("backup", file).
+				Msg("ignoring unreadable backup manifest")
+			if manifest, err = inferManifest(file); err != nil {
+				m.logger.Warn().Err(err).Str("backup", file).Msg("skipping unreadable backup")
+				continue
+			}
+		}
+		catalog = append(catalog, manifest)
+	}
+
+	sort.Slice(catalog, func(i, j int) bool {
+		if catalog[i].CreatedAt != catalog[j].CreatedAt {
+			return catalog[i].CreatedAt < catalog[j].CreatedAt
+		}
+		return catalog[i].File < catalog[j].File
+	})
+	return catalog, nil
+}
+
+// readManifest reads the manifest of a backup file, inferring one if the backup predates
+// manifests.
+func readManifest(backupFile string) (*litetable.BackupManifest, error) {
+	data, err := os.ReadFile(manifestPath(backupFile))
+	if err == nil {
+		var manifest litetable.BackupManifest
+		if err = json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest for %s: %w", backupFile, err)
+		}
+		return &manifest, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read manifest for %s: %w", backupFile, err)
+	}
+	return inferManifest(backupFile)
+}
+
+// inferManifest describes a backup file without a manifest from its name and size.
+func inferManifest(backupFile string) (*litetable.BackupManifest, error) {
+	info, err := os.Stat(backupFile)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &litetable.BackupManifest{
+		File:  filepath.Base(backupFile),
+		Bytes: info.Size(),
+	}
+	if _, err = fmt.Sscanf(manifest.File, "backup-%d.db", &manifest.CreatedAt); err != nil {
+		manifest.CreatedAt = info.ModTime().UnixNano()
+	}
+	return manifest, nil
+}
@@ -0,0 +1,86 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManager_ListBackups(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	// a backup from before manifests is listed from its file name and size
+	legacy := filepath.Join(m.dataDir, "backup-200.db")
+	req.NoError(os.WriteFile(legacy, []byte("{}"), 0644))
+
+	// the manifest decides the order, not the file name
+	named := filepath.Join(m.dataDir, "backup-300.db")
+	req.NoError(writeDataFile(named, nil, []byte("{}")))
+	req.NoError(writeManifest(named, &litetable.BackupManifest{
+		File:          "backup-300.db",
+		FormatVersion: BackupFormatVersion,
+		CreatedAt:     100,
+	}))
+
+	catalog, err := m.ListBackups()
+	req.NoError(err)
+	req.Len(catalog, 2)
+	req.Equal("backup-300.db", catalog[0].File)
+	req.Equal(&litetable.BackupManifest{File: "backup-200.db", CreatedAt: 200, Bytes: 2}, catalog[1])
+
+	latest, err := m.getLatestBackup()
+	req.NoError(err)
+	req.Equal(legacy, latest)
+
+	// a corrupt manifest is ignored, and its backup listed as if it had none
+	corrupt := filepath.Join(m.dataDir, "backup-400.db")
+	req.NoError(os.WriteFile(corrupt, []byte("{}"), 0644))
+	req.NoError(os.WriteFile(manifestPath(corrupt), []byte("{not json"), 0644))
+	catalog, err = m.ListBackups()
+	req.NoError(err)
+	req.Len(catalog, 3)
+	req.Equal(&litetable.BackupManifest{File: "backup-400.db", CreatedAt: 400, Bytes: 2}, catalog[2])
+
+	latest, err = m.getLatestBackup()
+	req.NoError(err)
+	req.Equal(corrupt, latest)
+}
+
+func TestManager_saveBackup_manifest(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	before := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.Flush())
+
+	catalog, err := m.ListBackups()
+	req.NoError(err)
+	req.Len(catalog, 1)
+
+	manifest := catalog[0]
+	req.Equal(BackupFormatVersion, manifest.FormatVersion)
+	req.Equal(1, manifest.Rows)
+	req.Greater(manifest.CreatedAt, before)
+	req.Greater(manifest.LastSnapshot, before)
+	req.Equal(manifest.FirstSnapshot, manifest.LastSnapshot)
+	req.Len(manifest.Checksum, 64)
+
+	info, err := os.Stat(filepath.Join(m.dataDir, manifest.File))
+	req.NoError(err)
+	req.Equal(info.Size(), manifest.Bytes)
+
+	// pruning removes the manifest with the backup
+	m.maxSnapshotLimit = 0
+	m.maintainBackupLimit()
+	req.NoFileExists(manifestPath(filepath.Join(m.dataDir, manifest.File)))
+
+	catalog, err = m.ListBackups()
+	req.NoError(err)
+	req.Empty(catalog)
+}
@@ -0,0 +1,105 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/shard_storage/reaper"
+	"slices"
+)
+
+// CloneFamily creates the family target holding a copy of every row of the family source, and
+// returns the number of rows and cells copied.
+//
+// The copy is copy-on-write: target shares the stored values of source, which are never changed
+// in place, so cloning costs a slice header per qualifier rather than the size of the values.
+// Every shard is locked for the clone, so it is a consistent copy as of one point and no write
+// to target can land before it. The cells keep their timestamps, expiries and sequence numbers,
+// and no CDC events are emitted for them.
+//
+// The families are checked once every shard is locked, and changes to the allowed families wait
+// for the clone, so two clones or a clone and a CreateFamilies to the same target cannot both
+// succeed.
+func (m *Manager) CloneFamily(source, target string) (int, int, error) {
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
+	for _, s := range m.shardMap {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+	}
+	m.familiesMutex.Lock()
+	defer m.familiesMutex.Unlock()
+
+	if !m.IsFamilyAllowed(source) {
+		return 0, 0, litetable.NewError(litetable.ErrorCodeFamilyMissing,
+			"column family not allowed: %s", source)
+	}
+	if m.IsFamilyAllowed(target) {
+		return 0, 0, litetable.NewError(litetable.ErrorCodeConflict, "family %s already exists",
+			target)
+	}
+
+	// the clone takes as much space as the source family
+	var used, cloned usage
+	for _, s := range m.shardMap {
+		used = used.add(s.usage)
+		cloned = cloned.add(s.familyUsage[source])
+	}
+	if err := m.quota.check("table", used, usage{bytes: cloned.bytes}); err != nil {
+		return 0, 0, err
+	}
+	if err := m.familyPolicies[target].Quota.check("family "+target, usage{},
+		cloned); err != nil {
+		return 0, 0, err
+	}
+
+	// the family is created while every shard is locked, so a write to it waits for the clone
+	if err := m.updateFamilies([]string{target}); err != nil {
+		return 0, 0, err
+	}
+
+	var rows, cells int
+	for _, s := range m.shardMap {
+		for rowKey, r := range s.data {
+			qualifiers, ok := r.family(source)
+			if !ok || len(qualifiers) == 0 {
+				continue
+			}
+
+			fam := r.addFamily(target)
+			var bytes int64
+			for qualifier, values := range qualifiers {
+				// clipping the slice makes the next write to either family copy it
+				fam[qualifier] = slices.Clip(values)
+				bytes += valueBytes(values)
+				cells += len(values)
+				m.reapClone(rowKey, target, qualifier, values)
+			}
+			s.addUsage(target, bytes, false, true)
+			m.MarkRowChanged(target, rowKey)
+			rows++
+		}
+	}
+
+	m.logger.Info().Str("source", source).Str("target", target).Int("rows", rows).
+		Int("cells", cells).Msg("Cloned column family")
+	return rows, cells, nil
+}
+
+// reapClone schedules the collection of the cloned tombstones and values with a TTL, which the
+// reap entries of the source family do not cover.
+func (m *Manager) reapClone(rowKey, family, qualifier string,
+	values []litetable.TimestampedValue) {
+	for _, v := range values {
+		if v.ExpiresAt == 0 {
+			continue
+		}
+		m.reaper.Reap(&reaper.ReapParams{
+			RowKey:     rowKey,
+			Family:     family,
+			Qualifiers: []string{qualifier},
+			Timestamp:  v.Timestamp,
+			ExpiresAt:  v.ExpiresAt,
+			Expiry:     !v.IsTombstone,
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package shard_storage
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestManager_CloneFamily(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	req.NoError(m.UpdateFamilies([]string{"stats"}))
+	now := time.Now().UnixNano()
+
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")}, now, 0))
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Jinx")}, now+1, 0))
+	req.NoError(m.Apply("champ:2", "main", []string{"name"}, [][]byte{[]byte("Vi")}, now, 0))
+	req.NoError(m.Delete("champ:2", "main", []string{"name"}, now+1, now+int64(time.Hour)))
+	req.NoError(m.Apply("champ:3", "stats", []string{"wins"}, [][]byte{[]byte("10")}, now, 0))
+
+	rows, cells, err := m.CloneFamily("main", "main_copy")
+	req.NoError(err)
+	req.Equal(2, rows)
+	req.Equal(4, cells)
+	req.True(m.IsFamilyAllowed("main_copy"))
+
+	// the clone holds every version and tombstone of the source
+	source, found := m.GetRowByFamily("champ:1", "main")
+	req.True(found)
+	clone, found := m.GetRowByFamily("champ:1", "main_copy")
+	req.True(found)
+	req.Equal((*source)["champ:1"]["main"], (*clone)["champ:1"]["main_copy"])
+	clone, found = m.GetRowByFamily("champ:2", "main_copy")
+	req.True(found)
+	req.True((*clone)["champ:2"]["main_copy"]["name"][0].IsTombstone)
+	_, found = m.GetRowByFamily("champ:3", "main_copy")
+	req.False(found)
+
+	// writes to the clone do not reach the source, nor the other way around
+	req.NoError(m.Apply("champ:1", "main_copy", []string{"name"}, [][]byte{[]byte("Vex")},
+		now+2, 0))
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Zed")}, now+3, 0))
+	source, _ = m.GetRowByFamily("champ:1", "main")
+	clone, _ = m.GetRowByFamily("champ:1", "main_copy")
+	req.Len((*source)["champ:1"]["main"]["name"], 3)
+	req.Equal([]byte("Zed"), (*source)["champ:1"]["main"]["name"][2].Value)
+	req.Len((*clone)["champ:1"]["main_copy"]["name"], 3)
+	req.Equal([]byte("Vex"), (*clone)["champ:1"]["main_copy"]["name"][2].Value)
+
+	// the clone is in the next snapshot
+	req.NoError(m.Flush())
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Len(backup["champ:2"]["main_copy"]["name"], 2)
+}
+
+func TestManager_CloneFamily_errors(t *testing.T) {
+	tests := map[string]struct {
+		source, target string
+		quota          Quota
+		wantErr        error
+	}{
+		"missing source": {
+			source:  "missing",
+			target:  "copy",
+			wantErr: litetable.ErrFamilyMissing,
+		},
+		"existing target": {
+			source:  "main",
+			target:  "main",
+			wantErr: litetable.ErrConflict,
+		},
+		"over the table quota": {
+			source:  "main",
+			target:  "copy",
+			quota:   Quota{MaxBytes: 6},
+			wantErr: litetable.ErrExhausted,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			m := newTestManager(t)
+			req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+				time.Now().UnixNano(), 0))
+			m.quota = tc.quota
+
+			_, _, err := m.CloneFamily(tc.source, tc.target)
+			req.True(errors.Is(err, tc.wantErr), err)
+			req.False(m.IsFamilyAllowed("copy"))
+		})
+	}
+}
+
+func TestManager_CloneFamily_concurrent(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+
+	// only one of several clones to the same target succeeds, and the target is counted once
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, errs[i] = m.CloneFamily("main", "copy")
+		}()
+	}
+	wg.Wait()
+
+	var cloned int
+	for _, err := range errs {
+		if err == nil {
+			cloned++
+			continue
+		}
+		req.True(errors.Is(err, litetable.ErrConflict), err)
+	}
+	req.Equal(1, cloned)
+	usage := m.Usage()
+	req.Equal(int64(4), usage.Families["copy"].Bytes)
+	req.Equal(int64(1), usage.Families["copy"].Rows)
+}
@@ -0,0 +1,135 @@
+package shard_storage
+
+import (
+	"bytes"
+	"github.com/klauspost/compress/zstd"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"maps"
+	"slices"
+)
+
+// minCompressSize is the size of the smallest value compressed. Shorter values rarely shrink by
+// more than the zstd frame header adds.
+const minCompressSize = 64
+
+var decompressionErrors = metrics.NewCounter("litetable_decompression_errors_total",
+	"Stored values that could not be decompressed and were read as stored.")
+
+// The encoder and decoder are shared by every table. EncodeAll and DecodeAll are safe for
+// concurrent use, and neither constructor fails without options.
+var (
+	encoder, _ = zstd.NewWriter(nil)
+	decoder, _ = zstd.NewReader(nil)
+)
+
+// compress returns the value to store for a write to a compressed family, and whether it is
+// compressed. Values that are short or do not shrink are stored as written.
+func compress(value []byte) ([]byte, bool) {
+	if len(value) < minCompressSize {
+		return value, false
+	}
+	compressed := encoder.EncodeAll(value, nil)
+	if len(compressed) >= len(value) {
+		return value, false
+	}
+	// the encoder's buffer is sized for the input, so the value is copied to release it
+	return bytes.Clone(compressed), true
+}
+
+// storedWrite holds the values of a write as they are stored, compressed when their family is,
+// with the bytes they take once stored. Quotas are checked and usage counted on those bytes, so
+// the two agree.
+type storedWrite struct {
+	values     [][]byte
+	compressed []bool
+	bytes      int64
+}
+
+// prepareWrite returns the values of a write to family as they will be stored. It is called
+// before any shard is locked, so compression never runs while a shard is held.
+func (m *Manager) prepareWrite(family string, values [][]byte) storedWrite {
+	stored := storedWrite{values: values}
+	if m.familyPolicies[family].Compress {
+		stored.values = make([][]byte, len(values))
+		stored.compressed = make([]bool, len(values))
+		for i, value := range values {
+			stored.values[i], stored.compressed[i] = compress(value)
+		}
+	}
+	for _, value := range stored.values {
+		stored.bytes += int64(len(value))
+	}
+	return stored
+}
+
+// uncompressedSize returns the size of a compressed value before compression, which the zstd
+// frame header records.
+func uncompressedSize(value []byte) int64 {
+	var header zstd.Header
+	if err := header.Decode(value); err != nil || !header.HasFCS {
+		return 0
+	}
+	return int64(header.FrameContentSize)
+}
+
+// hasCompressed reports whether any of the values is stored compressed.
+func hasCompressed(values []litetable.TimestampedValue) bool {
+	for _, v := range values {
+		if v.Compressed {
+			return true
+		}
+	}
+	return false
+}
+
+// decompressed returns a copy of values with every compressed value decompressed. Stored values
+// are never changed in place, so the slice is copied first. A value that fails to decompress is
+// logged and returned as stored, rather than failing the whole read.
+func (m *Manager) decompressed(values []litetable.TimestampedValue) []litetable.TimestampedValue {
+	out := slices.Clone(values)
+	for i, v := range out {
+		if !v.Compressed {
+			continue
+		}
+		value, err := decoder.DecodeAll(v.Value, nil)
+		if err != nil {
+			decompressionErrors.Inc()
+			m.logger.Error().Err(err).Int64("timestamp", v.Timestamp).
+				Msg("failed to decompress value")
+			continue
+		}
+		out[i].Value = value
+		out[i].Compressed = false
+	}
+	return out
+}
+
+// readQualifiers returns the qualifiers of a family as a read returns them, decompressed. The
+// qualifiers are returned as they are when none has a compressed value, and are otherwise
+// copied.
+func (m *Manager) readQualifiers(
+	qualifiers litetable.VersionedQualifier) litetable.VersionedQualifier {
+	out := qualifiers
+	copied := false
+	for qualifier, values := range qualifiers {
+		if !hasCompressed(values) {
+			continue
+		}
+		if !copied {
+			out = maps.Clone(qualifiers)
+			copied = true
+		}
+		out[qualifier] = m.decompressed(values)
+	}
+	return out
+}
+
+// readColumns returns a row in its litetable.Data form as a read returns it, decompressed.
+func (m *Manager) readColumns(r *row) map[string]litetable.VersionedQualifier {
+	columns := r.columns()
+	for family, qualifiers := range columns {
+		columns[family] = m.readQualifiers(qualifiers)
+	}
+	return columns
+}
@@ -0,0 +1,164 @@
+package shard_storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_compress(t *testing.T) {
+	random := make([]byte, 256)
+	_, _ = rand.Read(random)
+
+	tests := map[string]struct {
+		value      []byte
+		compressed bool
+	}{
+		"short": {
+			value: []byte("Ahri"),
+		},
+		"incompressible": {
+			value: random,
+		},
+		"text": {
+			value:      []byte(strings.Repeat("the nine-tailed fox ", 50)),
+			compressed: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			stored, compressed := compress(tc.value)
+			req.Equal(tc.compressed, compressed)
+			if !compressed {
+				req.Equal(tc.value, stored)
+				return
+			}
+			req.Less(len(stored), len(tc.value))
+			req.Equal(int64(len(tc.value)), uncompressedSize(stored))
+		})
+	}
+}
+
+func TestManager_Apply_compressed(t *testing.T) {
+	req := require.New(t)
+	events := &recordingCDC{}
+	m, _, err := New(&Config{
+		RootDir:        t.TempDir(),
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     events,
+		CDCOldValues:   true,
+		FamilyPolicies: map[string]FamilyPolicy{"bios": {Compress: true}},
+	})
+	req.NoError(err)
+	req.NoError(m.UpdateFamilies([]string{"bios"}))
+
+	bio := []byte(strings.Repeat("Ahri roams the land of Ionia. ", 40))
+	now := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "bios", []string{"lore", "title"},
+		[][]byte{bio, []byte("fox")}, now, 0))
+	req.NoError(m.Apply("champ:1", "bios", []string{"lore"}, [][]byte{bio}, now+1, 0))
+
+	// the long value is stored compressed and takes its stored size
+	s := m.shardMap[m.getShardIndex("champ:1")]
+	qualifiers, _ := s.data["champ:1"].family("bios")
+	stored := qualifiers["lore"][0]
+	req.True(stored.Compressed)
+	req.False(qualifiers["title"][0].Compressed)
+	req.Equal(2*int64(len(stored.Value))+3, m.Usage().Families["bios"].Bytes)
+
+	// reads and CDC events see the value as written
+	data, found := m.GetRowByFamily("champ:1", "bios")
+	req.True(found)
+	req.Equal(bio, (*data)["champ:1"]["bios"]["lore"][0].Value)
+	req.False((*data)["champ:1"]["bios"]["lore"][0].Compressed)
+	req.Equal([]byte("fox"), (*data)["champ:1"]["bios"]["title"][0].Value)
+	data, _, found = m.FilterRowsByPrefix("champ:", litetable.ScanPage{})
+	req.True(found)
+	req.Equal(bio, (*data)["champ:1"]["bios"]["lore"][1].Value)
+	req.Len(events.events, 3)
+	req.Equal(bio, events.events[0].Value)
+	req.Equal(bio, events.events[2].OldValue)
+
+	// the stored value was not changed by the reads
+	req.True(bytes.Equal(stored.Value, qualifiers["lore"][0].Value))
+
+	m.computeStats()
+	stats := m.Usage().Stats["bios"]
+	req.Equal(int64(2), stats.CompressedCells)
+	req.Equal(2*int64(len(stored.Value)), stats.CompressedBytes)
+	req.Equal(2*int64(len(bio)), stats.UncompressedBytes)
+
+	// backups keep the values compressed
+	req.NoError(m.Flush())
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Equal(stored, backup["champ:1"]["bios"]["lore"][0])
+}
+
+func TestManager_readQualifiers(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	plain := litetable.VersionedQualifier{
+		"name": {{Value: []byte("Ahri"), Timestamp: 1}},
+	}
+	req.Equal(plain, m.readQualifiers(plain))
+
+	value, compressed := compress([]byte(strings.Repeat("fox ", 100)))
+	req.True(compressed)
+	mixed := litetable.VersionedQualifier{
+		"name": {{Value: []byte("Ahri"), Timestamp: 1}},
+		"lore": {{Value: value, Timestamp: 1, Compressed: true}},
+	}
+	read := m.readQualifiers(mixed)
+	req.Equal([]byte(strings.Repeat("fox ", 100)), read["lore"][0].Value)
+	req.Equal(value, mixed["lore"][0].Value)
+
+	// a value that fails to decompress is read as stored
+	corrupt := litetable.VersionedQualifier{
+		"lore": {{Value: []byte("not zstd"), Timestamp: 1, Compressed: true}},
+	}
+	errors := decompressionErrors.Value()
+	req.Equal([]byte("not zstd"), m.readQualifiers(corrupt)["lore"][0].Value)
+	req.Equal(errors+1, decompressionErrors.Value())
+}
+
+func TestManager_Apply_compressedQuota(t *testing.T) {
+	req := require.New(t)
+	m, _, err := New(&Config{
+		RootDir:        t.TempDir(),
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+		FamilyPolicies: map[string]FamilyPolicy{"bios": {
+			Compress: true,
+			Quota:    Quota{MaxBytes: 512},
+		}},
+	})
+	req.NoError(err)
+	req.NoError(m.UpdateFamilies([]string{"bios"}))
+
+	// the quota is checked against the stored size, which usage counts, not the size written
+	bio := []byte(strings.Repeat("Ahri roams the land of Ionia. ", 40))
+	stored, compressed := compress(bio)
+	req.True(compressed)
+	now := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "bios", []string{"lore"}, [][]byte{bio}, now, 0))
+	req.Equal(int64(len(stored)), m.Usage().Families["bios"].Bytes)
+
+	errs := m.ApplyBatch([]litetable.Mutation{{RowKey: "champ:2", Family: "bios",
+		Qualifiers: []string{"lore"}, Values: [][]byte{bio}, Timestamp: now}})
+	req.NoError(errs[0])
+	_, err = m.Commit(0, nil, []litetable.Mutation{{Operation: litetable.OperationWrite,
+		RowKey: "champ:3", Family: "bios", Qualifiers: []string{"lore"}, Values: [][]byte{bio},
+		Timestamp: now}}, nil)
+	req.NoError(err)
+	req.Equal(3*int64(len(stored)), m.Usage().Families["bios"].Bytes)
+}
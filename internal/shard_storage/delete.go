@@ -1,17 +1,18 @@
 package shard_storage
 
 import (
-	"fmt"
 	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
 	"github.com/litetable/litetable-db/internal/litetable"
 	"github.com/litetable/litetable-db/internal/shard_storage/reaper"
-	"github.com/rs/zerolog/log"
 	"sort"
 	"time"
 )
 
 func (m *Manager) Delete(key, family string, qualifiers []string, timestamp int64,
 	expiresAt int64) error {
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
 	// find the shard index
 	shardKey := m.getShardIndex(key)
 
@@ -25,61 +26,89 @@ func (m *Manager) Delete(key, family string, qualifiers []string, timestamp int6
 	// check if the row exists
 	row, exists := s.data[key]
 	if !exists {
-		return fmt.Errorf("row not found: %s", key)
+		return litetable.NewError(litetable.ErrorCodeNotFound, "row not found: %s", key)
+	}
+	if err := m.checkDelete(row, key, family); err != nil {
+		return err
 	}
 
+	m.tombstone(row, key, family, qualifiers, timestamp, expiresAt, m.nextSequence())
+	return nil
+}
+
+// checkDelete returns an error unless the family of a delete exists on the row. An empty family
+// deletes the whole row.
+func (m *Manager) checkDelete(row *row, key, family string) error {
+	if family == "" {
+		return nil
+	}
+	if !m.IsFamilyAllowed(family) {
+		return litetable.NewError(litetable.ErrorCodeFamilyMissing, "family not allowed: %s",
+			family)
+	}
+	if _, exists := row.family(family); !exists {
+		return litetable.NewError(litetable.ErrorCodeNotFound, "family %s not found on key: %s",
+			family, key)
+	}
+	return nil
+}
+
+// tombstone marks the cells of a delete for garbage collection, numbered seq. The caller holds
+// the shard lock and has checked the delete.
+func (m *Manager) tombstone(row *row, key, family string, qualifiers []string, timestamp,
+	expiresAt int64, seq uint64) {
 	// if the family is empty, we should mark the entire row key for garbage collection
 	if family == "" {
-		for familyName, quals := range row {
+		for _, f := range row.families {
 			// for every qualifier insert a tombstone at the time
-			for q := range quals {
-				fmt.Println("Adding tombstone to qualifier:", q, familyName)
+			for q := range f.qualifiers {
+				m.logger.Debug().
+					Str("key", key).
+					Str("family", f.name).
+					Str("qualifier", q).
+					Msg("adding tombstone to qualifier")
 				// add tombstone markers to all qualifiers
 				m.addTombstone(
-					row,
+					f.qualifiers,
 					key,
-					familyName,
+					f.name,
 					q,
 					timestamp,
 					expiresAt,
+					seq,
 				)
 			}
 		}
 	} else {
 		// if the family is specified, we will only append tombstones based on if qualifiers
 		// are provided
-		// validate the family and make sure it exists
-		if !m.IsFamilyAllowed(family) {
-			return fmt.Errorf("family not allowed: %s", family)
-		}
-		fam, exists := row[family]
-		if !exists {
-			return fmt.Errorf("family %s not found on key: %s", family, key)
-		}
+		fam, _ := row.family(family)
 
 		// if there are no provided qualifiers, we should mark the whole family for deletion
 		if len(qualifiers) == 0 {
 			// Mark entire family for deletion
 			for q := range fam {
 				m.addTombstone(
-					row,
+					fam,
 					key,
 					family,
 					q,
 					timestamp,
 					expiresAt,
+					seq,
 				)
 				// TODO: all tombstones should send a CDC event
 			}
 		} else {
 			for _, q := range qualifiers {
 				m.addTombstone(
-					row,
+					fam,
 					key,
 					family,
 					q,
 					timestamp,
 					expiresAt,
+					seq,
 				)
 			}
 		}
@@ -96,31 +125,37 @@ func (m *Manager) Delete(key, family string, qualifiers []string, timestamp int6
 		Timestamp:  timestamp,
 		ExpiresAt:  expiresAt,
 	})
-	return nil
 }
 
 // addTombstone adds a tombstone marker for a cell at the passed in timestamp.
 // expiresAt is a time that is configured within the Litetable configuration, but
 // can be overridden with a provided TTL.
 func (m *Manager) addTombstone(
-	row map[string]litetable.VersionedQualifier,
+	qualifiers litetable.VersionedQualifier,
 	key,
 	family,
 	qualifier string,
 	timestamp int64,
 	expiresAt int64,
+	seq uint64,
 ) {
-	values := row[family][qualifier]
+	values, exists := qualifiers[qualifier]
+	if !exists {
+		qualifier = intern(qualifier)
+	}
+	oldValue, hasOldValue := m.oldValue(values)
 
 	tombstone := litetable.TimestampedValue{
 		Value:       nil,
 		Timestamp:   timestamp,
 		IsTombstone: true,
 		ExpiresAt:   expiresAt,
+		Seq:         seq,
 	}
 
-	// Insert the tombstone
-	values = append(values, tombstone)
+	// Insert the tombstone into a new slice, since sorting reorders the values that snapshots and
+	// readers may still hold
+	values = append(values[:len(values):len(values)], tombstone)
 
 	// Sort versions descending by Timestamp
 	sort.Slice(values, func(i, j int) bool {
@@ -128,23 +163,32 @@ func (m *Manager) addTombstone(
 	})
 
 	// we are iterating on the actual memory map here.
-	row[family][qualifier] = values
-
-	m.cdc.Emit(&v1.CDCEvent{
-		Operation:   litetable.OperationDelete,
-		RowKey:      key,
-		Family:      family,
-		Qualifier:   qualifier,
-		Value:       tombstone.Value,
-		Timestamp:   tombstone.Timestamp,
-		IsTombstone: tombstone.IsTombstone,
-		ExpiresAt:   tombstone.ExpiresAt,
+	qualifiers[qualifier] = values
+
+	m.emit(&v1.CDCEvent{
+		Operation:     litetable.OperationDelete,
+		RowKey:        key,
+		Family:        family,
+		Qualifier:     qualifier,
+		Value:         tombstone.Value,
+		Timestamp:     tombstone.Timestamp,
+		IsTombstone:   tombstone.IsTombstone,
+		ExpiresAt:     tombstone.ExpiresAt,
+		Table:         m.table,
+		TableSequence: seq,
+		OldValue:      oldValue,
+		HasOldValue:   hasOldValue,
 	})
 }
 
-// DeleteExpiredTombstones removes expired tombstones and returns true if changes were made
+// DeleteExpiredTombstones removes expired tombstones and the versions they hide. It returns the
+// number of cells removed, an estimate of the bytes reclaimed, and true once nothing is left for
+// the tombstones to collect.
 func (m *Manager) DeleteExpiredTombstones(rowKey, family string, qualifiers []string,
-	timestamp int64) bool {
+	timestamp int64) (int, int64, bool) {
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
 	// Determine which shard this row belongs to
 	shardIdx := m.getShardIndex(rowKey)
 	sh := m.shardMap[shardIdx]
@@ -155,30 +199,33 @@ func (m *Manager) DeleteExpiredTombstones(rowKey, family string, qualifiers []st
 	// Check if the row exists
 	row, exists := sh.data[rowKey]
 	if !exists {
-		log.Debug().Msgf("Row %s does not exist", rowKey)
-		return true
+		m.logger.Debug().Msgf("Row %s does not exist", rowKey)
+		return 0, 0, true
 	}
 
 	// Check if the family exists
-	familyData, exists := row[family]
+	familyData, exists := row.family(family)
 	if !exists {
-		log.Debug().Msgf("Family %s does not exist in row %s", family, rowKey)
-		return true
+		m.logger.Debug().Msgf("Family %s does not exist in row %s", family, rowKey)
+		return 0, 0, true
 	}
 
 	changed := false
+	var cells int
+	var bytes int64
 
 	now := time.Now().UnixNano()
 	// if we have no qualifiers, we should GC the entire family
 	if len(qualifiers) == 0 {
-		delete(row, family)
+		cells, bytes = cellSizes(familyData)
+		row.deleteFamily(family)
 		changed = true
 	} else {
 		// For any qualifier in the params, we should parse and compare timestamps
 		for _, qualifier := range qualifiers {
 			values, ex := familyData[qualifier]
 			if !ex {
-				log.Debug().Msgf("Qualifier %s does not exist in family %s", qualifier, family)
+				m.logger.Debug().Msgf("Qualifier %s does not exist in family %s", qualifier, family)
 				continue
 			}
 
@@ -191,6 +238,8 @@ func (m *Manager) DeleteExpiredTombstones(rowKey, family string, qualifiers []st
 				} else if entry.Timestamp > timestamp {
 					remainingValues = append(remainingValues, entry)
 				} else {
+					cells++
+					bytes += entry.Size()
 					changed = true
 				}
 			}
@@ -206,19 +255,85 @@ func (m *Manager) DeleteExpiredTombstones(rowKey, family string, qualifiers []st
 
 		// Clean up empty structures
 		if len(familyData) == 0 {
-			delete(row, family)
+			row.deleteFamily(family)
 		}
 	}
 
 	// If there is no data in the row key, it does not need to exist
-	if len(row) == 0 {
+	if row.isEmpty() {
+		delete(sh.data, rowKey)
+	}
+
+	// mark the change behind the barrier, so a snapshot never sees the change without the mark
+	if changed {
+		m.MarkRowChanged(family, rowKey)
+	}
+	return cells, bytes, changed
+}
+
+// DeleteExpiredValues removes the values written with a TTL that has passed. It returns the
+// number of cells removed and an estimate of the bytes reclaimed. Values written without a TTL
+// and tombstones are left alone.
+func (m *Manager) DeleteExpiredValues(rowKey, family string, qualifiers []string) (int, int64) {
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
+	sh := m.shardMap[m.getShardIndex(rowKey)]
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+
+	row, exists := sh.data[rowKey]
+	if !exists {
+		return 0, 0
+	}
+	familyData, exists := row.family(family)
+	if !exists {
+		return 0, 0
+	}
+
+	var cells int
+	var bytes int64
+	now := time.Now().UnixNano()
+	for _, qualifier := range qualifiers {
+		values, exists := familyData[qualifier]
+		if !exists {
+			continue
+		}
+
+		remaining, n, b := unexpired(values, now)
+		if n == 0 {
+			continue
+		}
+		cells += n
+		bytes += b
+
+		if len(remaining) > 0 {
+			familyData[qualifier] = remaining
+		} else {
+			delete(familyData, qualifier)
+		}
+	}
+
+	if len(familyData) == 0 {
+		row.deleteFamily(family)
+	}
+	if row.isEmpty() {
 		delete(sh.data, rowKey)
 	}
 
-	return changed
+	if cells > 0 {
+		m.MarkRowChanged(family, rowKey)
+	}
+	m.countExpired(cells, bytes)
+	return cells, bytes
 }
 
-func (m *Manager) DeleteRowFamily(rowKey, family string) bool {
+// DeleteRowFamily removes a family from a row. It returns the number of cells removed and an
+// estimate of the bytes reclaimed.
+func (m *Manager) DeleteRowFamily(rowKey, family string) (int, int64) {
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
 	// find the shard index
 	shardKey := m.getShardIndex(rowKey)
 
@@ -232,12 +347,28 @@ func (m *Manager) DeleteRowFamily(rowKey, family string) bool {
 	// check if the row exists
 	row, exists := s.data[rowKey]
 	if !exists {
-		return true
+		return 0, 0
 	}
 
 	// delete the family
-	delete(row, family)
+	qualifiers, _ := row.family(family)
+	cells, bytes := cellSizes(qualifiers)
+	row.deleteFamily(family)
+	m.MarkRowChanged(family, rowKey)
+
+	m.logger.Debug().Msgf("successfully deleted family %s from row %s", family, rowKey)
+	return cells, bytes
+}
 
-	log.Debug().Msgf("successfully deleted family %s from row %s", family, rowKey)
-	return true
+// cellSizes returns the number of cells of a family and an estimate of the bytes they take.
+func cellSizes(qualifiers litetable.VersionedQualifier) (int, int64) {
+	var cells int
+	var bytes int64
+	for _, values := range qualifiers {
+		cells += len(values)
+		for _, v := range values {
+			bytes += v.Size()
+		}
+	}
+	return cells, bytes
 }
@@ -0,0 +1,58 @@
+package shard_storage
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManager_Delete_copyOnWrite(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	now := time.Now().UnixNano()
+	for i := int64(0); i < 3; i++ {
+		req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")}, now+i,
+			0))
+	}
+
+	// hold the values the way a snapshot or reader does
+	row, ok := m.GetRowByFamily("champ:1", "main")
+	req.True(ok)
+	held := (*row)["champ:1"]["main"]["name"]
+	before := append(held[:0:0], held...)
+
+	req.NoError(m.Delete("champ:1", "main", []string{"name"}, now+10, now+20))
+
+	req.Equal(before, held)
+	row, ok = m.GetRowByFamily("champ:1", "main")
+	req.True(ok)
+	values := (*row)["champ:1"]["main"]["name"]
+	req.Len(values, 4)
+	req.True(values[0].IsTombstone)
+}
+
+func TestManager_DeleteExpiredTombstones(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	now := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")}, now, 0))
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Akali")}, now+20,
+		0))
+	req.NoError(m.Delete("champ:1", "main", []string{"name"}, now+10, now-1))
+
+	// the tombstone and the version it hides are collected; the later version is kept
+	cells, bytes, done := m.DeleteExpiredTombstones("champ:1", "main", []string{"name"}, now+10)
+	req.True(done)
+	req.Equal(2, cells)
+	req.Equal(int64(4+25+25), bytes)
+
+	row, ok := m.GetRowByFamily("champ:1", "main")
+	req.True(ok)
+	req.Len((*row)["champ:1"]["main"]["name"], 1)
+
+	cells, _, done = m.DeleteExpiredTombstones("champ:2", "main", []string{"name"}, now+10)
+	req.True(done)
+	req.Zero(cells)
+}
@@ -0,0 +1,141 @@
+package shard_storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	deltaPrefix   = "delta"
+	deltaFileGlob = "delta-*.db"
+)
+
+// deltaBackupData holds the snapshots merged since a full backup. The backup is restored by
+// applying the changes of every delta on its base, oldest first.
+type deltaBackupData struct {
+	Version int `json:"version"`
+	// Base is the name of the full backup file the delta applies to
+	Base          string       `json:"base"`
+	CreatedAt     int64        `json:"createdAt"`
+	FirstSnapshot int64        `json:"firstSnapshot"`
+	LastSnapshot  int64        `json:"lastSnapshot"`
+	Changes       []rowChanges `json:"changes"`
+	// Sequence is the largest sequence mark of the merged snapshots
+	Sequence uint64 `json:"sequence,omitempty"`
+}
+
+// saveDelta writes the changes from the merged snapshots as a delta on the base backup.
+func (m *Manager) saveDelta(base string, changes []rowChanges, covered snapshotRange) error {
+	start := time.Now()
+	filename := filepath.Join(m.dataDir, fmt.Sprintf("%s-%d.db", deltaPrefix, start.UnixNano()))
+
+	dataBytes, err := json.Marshal(&deltaBackupData{
+		Version:       DeltaFormatVersion,
+		Base:          base,
+		CreatedAt:     start.UnixNano(),
+		FirstSnapshot: covered.first,
+		LastSnapshot:  covered.last,
+		Changes:       changes,
+		Sequence:      covered.sequence,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to serialize delta backup: %w", err)
+	}
+
+	if err = writeDataFile(filename, m.writeThrottle, dataBytes); err != nil {
+		return fmt.Errorf("failed to write delta backup file: %w", err)
+	}
+
+	m.logger.Debug().Str("duration", time.Since(start).String()).Msgf("Delta backup saved to %s",
+		filename)
+	return nil
+}
+
+// latestDeltaChain returns the file name of the latest full backup and its deltas, oldest
+// first. The base is empty if there is no backup yet.
+func (m *Manager) latestDeltaChain() (string, []*deltaBackupData, error) {
+	latest, err := m.getLatestBackup()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get latest backup: %w", err)
+	}
+	if latest == "" {
+		return "", nil, nil
+	}
+
+	base := filepath.Base(latest)
+	deltas, err := m.deltasFor(base)
+	if err != nil {
+		return "", nil, err
+	}
+	return base, deltas, nil
+}
+
+// deltasFor returns the deltas applying to a full backup, oldest first.
+func (m *Manager) deltasFor(base string) ([]*deltaBackupData, error) {
+	deltas, _, err := m.readDeltas()
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []*deltaBackupData
+	for _, d := range deltas {
+		if d.Base == base {
+			chain = append(chain, d)
+		}
+	}
+	return chain, nil
+}
+
+// readDeltas reads every delta in the backup directory, oldest first, along with its file.
+func (m *Manager) readDeltas() ([]*deltaBackupData, []string, error) {
+	files, err := filepath.Glob(filepath.Join(m.dataDir, deltaFileGlob))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list delta backups: %w", err)
+	}
+	sort.Strings(files)
+
+	deltas := make([]*deltaBackupData, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read delta backup %s: %w", file, err)
+		}
+
+		var delta deltaBackupData
+		if err = json.Unmarshal(data, &delta); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse delta backup %s: %w", file, err)
+		}
+		deltas = append(deltas, &delta)
+	}
+	return deltas, files, nil
+}
+
+// pruneDeltas removes the deltas whose base backup has been pruned, since they can no longer be
+// restored.
+func (m *Manager) pruneDeltas() {
+	deltas, files, err := m.readDeltas()
+	if err != nil {
+		m.logger.Error().Err(err).Msg("Failed to list delta backups")
+		return
+	}
+
+	for i, d := range deltas {
+		if fileExists(filepath.Join(m.dataDir, d.Base)) {
+			continue
+		}
+		if err = removeDataFile(files[i]); err != nil {
+			m.logger.Error().Err(err).Msgf("Failed to remove delta backup %s", files[i])
+		} else {
+			m.logger.Debug().Msgf("Pruned delta backup: %s", files[i])
+		}
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
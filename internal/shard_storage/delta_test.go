@@ -0,0 +1,141 @@
+package shard_storage
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManager_ApplyDirectSnapshots_deltas(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	m.fullBackupInterval = 3
+	m.maxSnapshotLimit = 10
+
+	write := func(rowKey, value string) {
+		req.NoError(m.Apply(rowKey, "main", []string{"name"}, [][]byte{[]byte(value)},
+			time.Now().UnixNano(), 0))
+		req.NoError(m.Flush())
+	}
+	countFiles := func(glob string) int {
+		files, err := filepath.Glob(filepath.Join(m.dataDir, glob))
+		req.NoError(err)
+		return len(files)
+	}
+
+	// the first merge has no backup to build on
+	write("champ:1", "Ahri")
+	req.Equal(1, countFiles(backupFileGlob))
+	req.Equal(0, countFiles(deltaFileGlob))
+
+	// the next two merges are deltas on it
+	write("champ:2", "Annie")
+	write("champ:1", "Akali")
+	req.Equal(1, countFiles(backupFileGlob))
+	req.Equal(2, countFiles(deltaFileGlob))
+
+	base, deltas, err := m.latestDeltaChain()
+	req.NoError(err)
+	req.Len(deltas, 2)
+	for _, d := range deltas {
+		req.Equal(base, d.Base)
+	}
+
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Len(backup, 2)
+	req.Len(backup["champ:1"]["main"]["name"], 2)
+	req.Equal([]byte("Akali"), backup["champ:1"]["main"]["name"][1].Value)
+
+	// a restart restores the backup and its deltas
+	restarted, _, err := New(&Config{
+		RootDir:        m.rootDir,
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+	})
+	req.NoError(err)
+	req.NoError(restarted.loadFromLatestBackup())
+	row, ok := restarted.GetRowByFamily("champ:2", "main")
+	req.True(ok)
+	req.Equal([]byte("Annie"), (*row)["champ:2"]["main"]["name"][0].Value)
+
+	// the third merge since the full backup writes a new one covering all of them
+	write("champ:3", "Ashe")
+	req.Equal(2, countFiles(backupFileGlob))
+
+	base, deltas, err = m.latestDeltaChain()
+	req.NoError(err)
+	req.Empty(deltas)
+
+	catalog, err := m.ListBackups()
+	req.NoError(err)
+	latest := catalog[len(catalog)-1]
+	req.Equal(base, latest.File)
+	req.Equal(3, latest.Rows)
+	req.Less(latest.FirstSnapshot, latest.LastSnapshot)
+
+	report, err := Verify(m.rootDir)
+	req.NoError(err)
+	req.True(report.OK())
+}
+
+func TestManager_pruneDeltas(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	m.fullBackupInterval = 2
+	m.maxSnapshotLimit = 1
+
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.Flush())
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Akali")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.Flush())
+
+	deltas, files, err := m.readDeltas()
+	req.NoError(err)
+	req.Len(deltas, 1)
+
+	// the delta is kept until its base is pruned
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ashe")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.Flush())
+	req.FileExists(files[0])
+
+	m.maintainBackupLimit()
+	req.NoFileExists(filepath.Join(m.dataDir, deltas[0].Base))
+	req.NoFileExists(files[0])
+	req.NoFileExists(files[0] + checksumSuffix)
+}
+
+func TestVerify_delta(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	req.NoError(writeDataFile(filepath.Join(m.dataDir, "delta-100.db"), nil,
+		[]byte(`{"version":1,"base":"backup-1.db","createdAt":100,"firstSnapshot":50,`+
+			`"lastSnapshot":60,"changes":[{"champ:1":{"main":{"name":[`+
+			`{"value":"QWhyaQ==","timestamp":70}]}},"champ:2":null}]}`)))
+
+	report, err := Verify(m.rootDir)
+	req.NoError(err)
+	req.Len(report.Files, 1)
+
+	f := report.Files[0]
+	req.Equal("delta", f.Kind)
+	req.Equal(2, f.Rows)
+	req.Equal([]string{
+		"base backup backup-1.db does not exist",
+		"champ:1/main/name has a value at 70, after the snapshot was taken",
+	}, f.Problems)
+
+	// with its base in place, only the timestamp is wrong
+	req.NoError(os.WriteFile(filepath.Join(m.dataDir, "backup-1.db"), []byte("{}"), 0644))
+	report, err = Verify(m.rootDir)
+	req.NoError(err)
+	req.Len(report.Files, 2)
+	req.Len(report.Files[1].Problems, 1)
+}
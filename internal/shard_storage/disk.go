@@ -0,0 +1,109 @@
+package shard_storage
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/logging"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"github.com/rs/zerolog"
+	"sync"
+	"time"
+)
+
+// diskCheckInterval is how long the free space read from the disk is used before it is read
+// again.
+const diskCheckInterval = 5 * time.Second
+
+var (
+	diskFreeBytes = metrics.NewGauge("litetable_disk_free_bytes",
+		"Free space on the disk holding the data directory.")
+	diskLowSpace = metrics.NewGauge("litetable_disk_low_space",
+		"1 while the free space of the data directory is below the configured minimum.")
+	diskRejected = metrics.NewCounterVec("litetable_disk_low_space_rejections_total",
+		"Snapshots, backups and writes refused because the disk was low on space.", "operation")
+)
+
+// DiskMonitor watches the free space of the disk holding the data directory. Below the minimum,
+// snapshots and backups are refused with an Exhausted error before anything is written, rather
+// than failing part way through a file, and so are writes when the monitor rejects them. The
+// changes a refused snapshot would have saved are kept in memory and the WAL until there is
+// space again. One monitor can be shared by every table on a disk. A nil DiskMonitor, or one
+// without a minimum, refuses nothing.
+type DiskMonitor struct {
+	dir          string
+	minFree      uint64
+	rejectWrites bool
+	// freeBytes reads the free space of a directory
+	freeBytes func(dir string) (uint64, error)
+	logger    zerolog.Logger
+
+	mutex   sync.Mutex
+	checked time.Time
+	free    uint64
+	known   bool
+}
+
+// NewDiskMonitor creates a monitor of the disk holding dir that refuses snapshots and backups
+// below minFree bytes of free space, and writes too when rejectWrites is set. A minFree of 0
+// only reports the free space.
+func NewDiskMonitor(dir string, minFree int64, rejectWrites bool) *DiskMonitor {
+	return &DiskMonitor{
+		dir:          dir,
+		minFree:      uint64(max(minFree, 0)),
+		rejectWrites: rejectWrites,
+		freeBytes:    freeBytes,
+		logger:       logging.For("disk"),
+	}
+}
+
+// Check returns an Exhausted error when the disk is below the minimum free space, counting the
+// operation it refuses.
+func (d *DiskMonitor) Check(operation string) error {
+	if d == nil {
+		return nil
+	}
+	// the free space is read without a minimum too, so it is reported
+	free, ok := d.freeSpace()
+	if d.minFree == 0 || !ok || free >= d.minFree {
+		return nil
+	}
+	diskRejected.With(operation).Inc()
+	return litetable.NewError(litetable.ErrorCodeExhausted,
+		"%s refused: %d bytes free on the disk of %s, below the minimum of %d", operation, free,
+		d.dir, d.minFree)
+}
+
+// CheckWrite is Check for writes, which are only refused when the monitor rejects writes.
+func (d *DiskMonitor) CheckWrite() error {
+	if d == nil || !d.rejectWrites {
+		return nil
+	}
+	return d.Check("write")
+}
+
+// freeSpace returns the free space of the disk, read at most once every diskCheckInterval, and
+// whether it is known. A disk whose free space cannot be read is never reported as low.
+func (d *DiskMonitor) freeSpace() (uint64, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if now := time.Now(); now.Sub(d.checked) >= diskCheckInterval {
+		d.checked = now
+		free, err := d.freeBytes(d.dir)
+		if err != nil {
+			if !errors.Is(err, errors.ErrUnsupported) {
+				d.logger.Warn().Err(err).Str("dir", d.dir).Msg("failed to read free disk space")
+			}
+			d.known = false
+			return 0, false
+		}
+		d.free, d.known = free, true
+		diskFreeBytes.Set(float64(free))
+		if free < d.minFree {
+			diskLowSpace.Set(1)
+		} else {
+			diskLowSpace.Set(0)
+		}
+	}
+	return d.free, d.known
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package shard_storage
+
+import "errors"
+
+// freeBytes is not supported on this platform, so the disk is never reported as low on space.
+func freeBytes(string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
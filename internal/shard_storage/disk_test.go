@@ -0,0 +1,115 @@
+package shard_storage
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newFakeDiskMonitor creates a monitor reading the free space from free.
+func newFakeDiskMonitor(minFree int64, rejectWrites bool, free *uint64) *DiskMonitor {
+	d := NewDiskMonitor("/data", minFree, rejectWrites)
+	d.freeBytes = func(string) (uint64, error) { return *free, nil }
+	return d
+}
+
+func TestDiskMonitor_Check(t *testing.T) {
+	bytesFree := func(n uint64) *uint64 { return &n }
+	tests := map[string]struct {
+		monitor      *DiskMonitor
+		wantErr      bool
+		wantWriteErr bool
+	}{
+		"nil": {monitor: nil},
+		"no minimum": {
+			monitor: NewDiskMonitor("/data", 0, true),
+		},
+		"above the minimum": {
+			monitor: newFakeDiskMonitor(100, true, bytesFree(500)),
+		},
+		"below the minimum": {
+			monitor: newFakeDiskMonitor(1000, false, bytesFree(500)),
+			wantErr: true,
+		},
+		"below the minimum rejecting writes": {
+			monitor:      newFakeDiskMonitor(1000, true, bytesFree(500)),
+			wantErr:      true,
+			wantWriteErr: true,
+		},
+		"free space unknown": {
+			monitor: func() *DiskMonitor {
+				d := NewDiskMonitor("/data", 1000, true)
+				d.freeBytes = func(string) (uint64, error) { return 0, errors.ErrUnsupported }
+				return d
+			}(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			err := tc.monitor.Check("snapshot")
+			writeErr := tc.monitor.CheckWrite()
+			if !tc.wantErr {
+				req.NoError(err)
+				req.NoError(writeErr)
+				return
+			}
+			req.ErrorIs(err, litetable.ErrExhausted)
+			req.ErrorContains(err, "snapshot refused: 500 bytes free")
+			if tc.wantWriteErr {
+				req.ErrorIs(writeErr, litetable.ErrExhausted)
+			} else {
+				req.NoError(writeErr)
+			}
+		})
+	}
+}
+
+func TestDiskMonitor_freeSpace(t *testing.T) {
+	req := require.New(t)
+	free := uint64(500)
+	var reads int
+	d := NewDiskMonitor("/data", 1000, false)
+	d.freeBytes = func(string) (uint64, error) {
+		reads++
+		return free, nil
+	}
+
+	req.Error(d.Check("backup"))
+	// the free space is read again only after diskCheckInterval
+	free = 5000
+	req.Error(d.Check("backup"))
+	req.Equal(1, reads)
+
+	d.checked = time.Now().Add(-diskCheckInterval)
+	req.NoError(d.Check("backup"))
+	req.Equal(2, reads)
+}
+
+func TestManager_createDirectSnapshot_lowDisk(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	free := uint64(0)
+	m.disk = newFakeDiskMonitor(1000, false, &free)
+
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+
+	// nothing is written while the disk is low, and the changes wait for the next snapshot
+	req.ErrorIs(m.createDirectSnapshot(), litetable.ErrExhausted)
+	req.ErrorIs(m.ApplyDirectSnapshots(), litetable.ErrExhausted)
+	snapshots, err := filepath.Glob(filepath.Join(m.snapshotDir, "*"))
+	req.NoError(err)
+	req.Empty(snapshots)
+
+	free = 5000
+	m.disk.checked = time.Time{}
+	req.NoError(m.Flush())
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Equal([]byte("Ahri"), backup["champ:1"]["main"]["name"][0].Value)
+}
@@ -0,0 +1,69 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+)
+
+var (
+	expiredCells = metrics.NewCounterVec("litetable_expired_cells_total",
+		"Values written with a TTL removed after they expired.", "table")
+	expiredBytes = metrics.NewCounterVec("litetable_expired_bytes_total",
+		"Estimated bytes freed by removing values written with a TTL after they expired.", "table")
+)
+
+// countExpired records the expired values removed from the table.
+func (m *Manager) countExpired(cells int, bytes int64) {
+	if cells == 0 {
+		return
+	}
+	expiredCells.With(m.table).Add(float64(cells))
+	expiredBytes.With(m.table).Add(float64(bytes))
+}
+
+// unexpired returns the values that have not expired at now in a new slice, since readers may
+// still hold the current one, with the number of values removed and an estimate of their bytes.
+// It returns values itself when none expired.
+func unexpired(values []litetable.TimestampedValue, now int64) ([]litetable.TimestampedValue,
+	int, int64) {
+	var cells int
+	var bytes int64
+	for _, v := range values {
+		if v.IsExpired(now) {
+			cells++
+			bytes += v.Size()
+		}
+	}
+	if cells == 0 {
+		return values, 0, 0
+	}
+
+	remaining := make([]litetable.TimestampedValue, 0, len(values)-cells)
+	for _, v := range values {
+		if !v.IsExpired(now) {
+			remaining = append(remaining, v)
+		}
+	}
+	return remaining, cells, bytes
+}
+
+// expire removes the expired values of every qualifier of a family, and the qualifiers left
+// without values. The caller holds the shard lock.
+func expire(qualifiers litetable.VersionedQualifier, now int64) (int, int64) {
+	var cells int
+	var bytes int64
+	for qualifier, values := range qualifiers {
+		remaining, n, b := unexpired(values, now)
+		if n == 0 {
+			continue
+		}
+		cells += n
+		bytes += b
+		if len(remaining) > 0 {
+			qualifiers[qualifier] = remaining
+		} else {
+			delete(qualifiers, qualifier)
+		}
+	}
+	return cells, bytes
+}
@@ -0,0 +1,57 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_unexpired(t *testing.T) {
+	tests := map[string]struct {
+		values    []litetable.TimestampedValue
+		wantKept  []int64
+		wantCells int
+	}{
+		"nothing expired": {
+			values: []litetable.TimestampedValue{
+				{Timestamp: 1},
+				{Timestamp: 2, ExpiresAt: 20},
+			},
+			wantKept: []int64{1, 2},
+		},
+		"expired values are removed": {
+			values: []litetable.TimestampedValue{
+				{Timestamp: 1},
+				{Timestamp: 2, ExpiresAt: 5, Value: []byte("abc")},
+				{Timestamp: 3, ExpiresAt: 20},
+			},
+			wantKept:  []int64{1, 3},
+			wantCells: 1,
+		},
+		"tombstones are left to the reaper": {
+			values: []litetable.TimestampedValue{
+				{Timestamp: 1, ExpiresAt: 5, IsTombstone: true},
+			},
+			wantKept: []int64{1},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			input := append([]litetable.TimestampedValue(nil), tc.values...)
+
+			kept, cells, bytes := unexpired(input, 10)
+			var timestamps []int64
+			for _, v := range kept {
+				timestamps = append(timestamps, v.Timestamp)
+			}
+			req.Equal(tc.wantKept, timestamps)
+			req.Equal(tc.wantCells, cells)
+			req.Equal(tc.wantCells > 0, bytes > 0)
+
+			// the stored values are shared, so they are not modified
+			req.Equal(tc.values, input)
+		})
+	}
+}
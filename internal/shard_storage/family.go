@@ -13,6 +13,13 @@ func (m *Manager) FamilyLockFile() string {
 }
 
 func (m *Manager) UpdateFamilies(new []string) error {
+	m.familiesMutex.Lock()
+	defer m.familiesMutex.Unlock()
+	return m.updateFamilies(new)
+}
+
+// updateFamilies adds families to the allowed families. The caller holds familiesMutex.
+func (m *Manager) updateFamilies(new []string) error {
 	// create a copy of configured families
 	newFamilies := make([]string, len(m.allowedFamilies))
 	copy(newFamilies, m.allowedFamilies)
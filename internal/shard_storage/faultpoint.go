@@ -0,0 +1,7 @@
+//go:build !crashtest
+
+package shard_storage
+
+// faultPoint marks a point in persistence where the crash recovery tests kill the process. It
+// does nothing outside builds with the crashtest tag.
+func faultPoint(string) {}
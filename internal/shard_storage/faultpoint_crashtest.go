@@ -0,0 +1,51 @@
+//go:build crashtest
+
+package shard_storage
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// crashAtEnv names the fault point the process is killed at, as name or name:n to kill it the
+// nth time the point is reached.
+const crashAtEnv = "LITETABLE_CRASH_AT"
+
+var crash struct {
+	once  sync.Once
+	mutex sync.Mutex
+	point string
+	after int
+	hits  int
+}
+
+// faultPoint kills the process, the way a power loss or an OOM kill would, when it reaches the
+// point named by LITETABLE_CRASH_AT.
+func faultPoint(name string) {
+	crash.once.Do(func() {
+		point, count, _ := strings.Cut(os.Getenv(crashAtEnv), ":")
+		crash.point, crash.after = point, 1
+		if n, err := strconv.Atoi(count); err == nil {
+			crash.after = n
+		}
+	})
+	if name != crash.point {
+		return
+	}
+
+	crash.mutex.Lock()
+	crash.hits++
+	hit := crash.hits == crash.after
+	crash.mutex.Unlock()
+	if !hit {
+		return
+	}
+
+	// nothing is flushed or cleaned up on the way out
+	if p, err := os.FindProcess(os.Getpid()); err == nil {
+		_ = p.Kill()
+	}
+	select {}
+}
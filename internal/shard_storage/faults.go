@@ -0,0 +1,23 @@
+package shard_storage
+
+// faultInjector makes persistence fail on purpose, so tests can drive its recovery paths, such as
+// the retry of a failed snapshot, deterministically. Only the tests of this package replace
+// faults, and the default injects nothing.
+type faultInjector interface {
+	// failWrite returns the error the write of filename fails with, or nil to write it
+	failWrite(filename string) error
+	// delayLock is called before an operation takes the persistence lock, and returns once it
+	// may take it
+	delayLock(operation string)
+	// corruptSnapshot is called with each snapshot written, and may damage it on disk
+	corruptSnapshot(filename string)
+}
+
+var faults faultInjector = noFaults{}
+
+// noFaults is the faultInjector outside of tests.
+type noFaults struct{}
+
+func (noFaults) failWrite(string) error { return nil }
+func (noFaults) delayLock(string)       {}
+func (noFaults) corruptSnapshot(string) {}
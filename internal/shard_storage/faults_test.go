@@ -0,0 +1,158 @@
+package shard_storage
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errInjected = errors.New("injected write failure")
+
+// testFaults injects the faults it is set up with.
+type testFaults struct {
+	mutex sync.Mutex
+	// writeFailures is how many of the next file writes fail
+	writeFailures int
+	// held holds back the operations taking the persistence lock until it is closed, after
+	// sending them on waiting
+	held    map[string]chan struct{}
+	waiting chan string
+	// corrupt is whether the snapshots written are truncated
+	corrupt bool
+}
+
+// injectFaults makes persistence fail as f says until the end of the test.
+func injectFaults(t *testing.T, f *testFaults) {
+	faults = f
+	t.Cleanup(func() { faults = noFaults{} })
+}
+
+func (f *testFaults) failWrite(string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.writeFailures == 0 {
+		return nil
+	}
+	f.writeFailures--
+	return errInjected
+}
+
+func (f *testFaults) delayLock(operation string) {
+	if release, ok := f.held[operation]; ok {
+		f.waiting <- operation
+		<-release
+	}
+}
+
+func (f *testFaults) corruptSnapshot(filename string) {
+	if f.corrupt {
+		_ = os.Truncate(filename, 10)
+	}
+}
+
+func TestManager_createDirectSnapshot_failedWrites(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	injectFaults(t, &testFaults{writeFailures: 2})
+
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+
+	// each failed snapshot leaves no file behind and keeps the row for the next one
+	for range 2 {
+		req.ErrorIs(m.createDirectSnapshot(), errInjected)
+		files, err := filepath.Glob(filepath.Join(m.snapshotDir, "*"))
+		req.NoError(err)
+		req.Empty(files)
+	}
+
+	req.NoError(m.createDirectSnapshot())
+	files, err := m.snapshotFiles()
+	req.NoError(err)
+	req.Len(files, 1)
+	snapshot, err := readSnapshot(files[0])
+	req.NoError(err)
+	req.Contains(snapshot.SnapshotData, "champ:1")
+}
+
+func TestManager_ApplyDirectSnapshots_failedWrite(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.createDirectSnapshot())
+
+	// the snapshots of a failed backup are kept and merged by the next one
+	injectFaults(t, &testFaults{writeFailures: 1})
+	req.ErrorIs(m.ApplyDirectSnapshots(), errInjected)
+	files, err := m.snapshotFiles()
+	req.NoError(err)
+	req.Len(files, 1)
+
+	req.NoError(m.ApplyDirectSnapshots())
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Equal([]byte("Ahri"), backup["champ:1"]["main"]["name"][0].Value)
+	files, err = m.snapshotFiles()
+	req.NoError(err)
+	req.Empty(files)
+}
+
+func TestManager_ApplyDirectSnapshots_corruptSnapshot(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.Flush())
+
+	injectFaults(t, &testFaults{corrupt: true})
+	req.NoError(m.Apply("champ:2", "main", []string{"name"}, [][]byte{[]byte("Jinx")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.createDirectSnapshot())
+
+	report, err := Verify(m.rootDir)
+	req.NoError(err)
+	req.False(report.OK())
+
+	// a snapshot that cannot be read is never merged, or removed, and the backup is untouched
+	req.ErrorContains(m.ApplyDirectSnapshots(), "failed to parse snapshot")
+	files, err := m.snapshotFiles()
+	req.NoError(err)
+	req.Len(files, 1)
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Contains(backup, "champ:1")
+	req.NotContains(backup, "champ:2")
+}
+
+func TestManager_ApplyDirectSnapshots_delayedLock(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	release := make(chan struct{})
+	f := &testFaults{
+		held:    map[string]chan struct{}{"backup": release},
+		waiting: make(chan string, 1),
+	}
+	injectFaults(t, f)
+
+	applied := make(chan error, 1)
+	go func() { applied <- m.ApplyDirectSnapshots() }()
+	req.Equal("backup", <-f.waiting)
+
+	// a snapshot saved while the backup waits for the lock is merged by it
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.createDirectSnapshot())
+	close(release)
+	req.NoError(<-applied)
+
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Equal([]byte("Ahri"), backup["champ:1"]["main"]["name"][0].Value)
+}
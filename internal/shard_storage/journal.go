@@ -0,0 +1,90 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/metrics"
+	"sync/atomic"
+)
+
+var snapshotBacklog = metrics.NewGauge("litetable_snapshot_backlog_changes",
+	"Changes to rows of every table waiting for the next snapshot.")
+
+// pendingChanges is the number of changes in the journals of every table, which is what the
+// snapshot backlog gauge reports.
+var pendingChanges atomic.Int64
+
+// changeJournal is an append-only log of the families changed on a shard since the last
+// snapshot. Appends are lock-free, so marking a change never waits on other writers or the
+// snapshotter, and the snapshotter takes the whole log in a single swap instead of copying it.
+//
+// A row changed many times between snapshots has one entry per change; the snapshotter
+// collapses them.
+type changeJournal struct {
+	head atomic.Pointer[changeEntry]
+}
+
+// changeEntry is a changed family of a row. Entries are linked newest first.
+type changeEntry struct {
+	rowKey string
+	family string
+	next   *changeEntry
+}
+
+// append records a change to the family of a row.
+func (j *changeJournal) append(rowKey, family string) {
+	e := &changeEntry{rowKey: rowKey, family: family}
+	for {
+		e.next = j.head.Load()
+		if j.head.CompareAndSwap(e.next, e) {
+			countChanges(1)
+			return
+		}
+	}
+}
+
+// take empties the journal and returns its entries, newest first.
+func (j *changeJournal) take() *changeEntry {
+	head := j.head.Swap(nil)
+	n := 0
+	for e := head; e != nil; e = e.next {
+		n++
+	}
+	countChanges(-n)
+	return head
+}
+
+// countChanges adds n changes to the snapshot backlog.
+func countChanges(n int) {
+	pendingChanges.Add(int64(n))
+	snapshotBacklog.Add(float64(n))
+}
+
+// SnapshotBacklog returns the number of changes to rows of every table that the next snapshots
+// have yet to save. A row changed many times counts every change.
+func SnapshotBacklog() int {
+	return int(max(pendingChanges.Load(), 0))
+}
+
+// MarkRowChanged records that the family of a row changed, so the next snapshot copies it and
+// no cached prefix read returns the row as it was.
+func (m *Manager) MarkRowChanged(family, rowKey string) {
+	s := m.shardMap[m.getShardIndex(rowKey)]
+	s.changes.append(rowKey, family)
+	s.changeCount.Add(1)
+	m.scanCache.invalidate(rowKey)
+}
+
+// takeChanges empties the journal of every shard and returns the changed families by row key.
+func (m *Manager) takeChanges() map[string]map[string]struct{} {
+	changes := make(map[string]map[string]struct{})
+	for _, s := range m.shardMap {
+		for e := s.changes.take(); e != nil; e = e.next {
+			families, exists := changes[e.rowKey]
+			if !exists {
+				families = make(map[string]struct{})
+				changes[e.rowKey] = families
+			}
+			families[e.family] = struct{}{}
+		}
+	}
+	return changes
+}
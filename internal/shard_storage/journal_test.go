@@ -0,0 +1,59 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestManager_MarkRowChanged(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	backlog := SnapshotBacklog()
+
+	const writers, marks = 8, 500
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for w := 0; w < writers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < marks; i++ {
+				m.MarkRowChanged(fmt.Sprintf("family:%d", w), fmt.Sprintf("champ:%d", i))
+			}
+		}()
+	}
+	wg.Wait()
+	req.Equal(backlog+writers*marks, SnapshotBacklog())
+
+	// repeated marks of a row collapse into its set of families
+	changes := m.takeChanges()
+	req.Len(changes, marks)
+	for rowKey, families := range changes {
+		req.Len(families, writers, rowKey)
+	}
+
+	// taking the changes empties the journals
+	req.Equal(backlog, SnapshotBacklog())
+	req.Empty(m.takeChanges())
+	m.MarkRowChanged("main", "champ:1")
+	req.Equal(map[string]map[string]struct{}{"champ:1": {"main": {}}}, m.takeChanges())
+}
+
+func BenchmarkManager_MarkRowChanged(b *testing.B) {
+	m := newTestManager(b)
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("champ:%d", i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.MarkRowChanged("main", keys[i%len(keys)])
+			i++
+		}
+	})
+}
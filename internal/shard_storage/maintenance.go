@@ -0,0 +1,132 @@
+package shard_storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// throttleFactor is how much the heavy maintenance is slowed outside the maintenance windows.
+const throttleFactor = 10
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// MaintenanceWindow is a recurring period, in the server's local time, in which heavy maintenance
+// runs at full speed.
+type MaintenanceWindow struct {
+	// Days are the weekdays the window opens on. Empty is every day.
+	Days []time.Weekday
+	// Start is the time of day the window opens, as an offset from midnight.
+	Start time.Duration
+	// Length is how long the window stays open. A window may run past midnight.
+	Length time.Duration
+}
+
+// ParseMaintenanceWindow parses a window such as "02:00-04:00" or "mon-fri,sun 23:00-01:00": an
+// optional comma separated list of weekdays or weekday ranges, then the times the window opens
+// and closes. A window that closes before it opens runs past midnight.
+func ParseMaintenanceWindow(s string) (MaintenanceWindow, error) {
+	var w MaintenanceWindow
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return w, fmt.Errorf("invalid maintenance window %q: want [days] HH:MM-HH:MM", s)
+	}
+
+	if len(fields) == 2 {
+		for _, part := range strings.Split(fields[0], ",") {
+			first, last, isRange := strings.Cut(part, "-")
+			from, ok := weekdays[first]
+			to, okTo := weekdays[last]
+			if !isRange {
+				to, okTo = from, ok
+			}
+			if !ok || !okTo {
+				return w, fmt.Errorf("invalid maintenance window %q: unknown day %q", s, part)
+			}
+			// a range such as fri-mon wraps around the week
+			for d := from; ; d = (d + 1) % 7 {
+				w.Days = append(w.Days, d)
+				if d == to {
+					break
+				}
+			}
+		}
+	}
+
+	opens, closes, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return w, fmt.Errorf("invalid maintenance window %q: want [days] HH:MM-HH:MM", s)
+	}
+	start, err := parseTimeOfDay(opens)
+	if err != nil {
+		return w, fmt.Errorf("invalid maintenance window %q: %w", s, err)
+	}
+	end, err := parseTimeOfDay(closes)
+	if err != nil {
+		return w, fmt.Errorf("invalid maintenance window %q: %w", s, err)
+	}
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	w.Start, w.Length = start, end-start
+	return w, nil
+}
+
+// parseTimeOfDay parses HH:MM as an offset from midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t falls in the window.
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	// the window either opened today or, running past midnight, yesterday
+	for _, day := range []time.Time{midnight, midnight.AddDate(0, 0, -1)} {
+		if !w.opensOn(day.Weekday()) {
+			continue
+		}
+		opens := day.Add(w.Start)
+		if !t.Before(opens) && t.Before(opens.Add(w.Length)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w MaintenanceWindow) opensOn(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenanceWindows are the windows in which heavy maintenance runs at full speed: full backup
+// rewrites, which compact the deltas written between them, and the reaper's scan of the family
+// policies. Outside every window, full rewrites wait and the scan is throttled. Without windows,
+// maintenance always runs at full speed.
+type MaintenanceWindows []MaintenanceWindow
+
+// Open reports whether heavy maintenance runs at full speed at t.
+func (ws MaintenanceWindows) Open(t time.Time) bool {
+	if len(ws) == 0 {
+		return true
+	}
+	for _, w := range ws {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// closedWindows are maintenance windows that are not open now.
+func closedWindows() MaintenanceWindows {
+	return MaintenanceWindows{{
+		Days:   []time.Weekday{(time.Now().Weekday() + 3) % 7},
+		Length: time.Hour,
+	}}
+}
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    MaintenanceWindow
+		wantErr string
+	}{
+		"every day": {
+			value: "02:00-04:30",
+			want:  MaintenanceWindow{Start: 2 * time.Hour, Length: 150 * time.Minute},
+		},
+		"days and ranges": {
+			value: "Sat,Sun 01:00-05:00",
+			want: MaintenanceWindow{
+				Days:   []time.Weekday{time.Saturday, time.Sunday},
+				Start:  time.Hour,
+				Length: 4 * time.Hour,
+			},
+		},
+		"range across the week and midnight": {
+			value: "fri-mon 23:00-01:00",
+			want: MaintenanceWindow{
+				Days: []time.Weekday{time.Friday, time.Saturday, time.Sunday,
+					time.Monday},
+				Start:  23 * time.Hour,
+				Length: 2 * time.Hour,
+			},
+		},
+		"unknown day": {
+			value:   "someday 01:00-02:00",
+			wantErr: `unknown day "someday"`,
+		},
+		"missing end": {
+			value:   "01:00",
+			wantErr: "want [days] HH:MM-HH:MM",
+		},
+		"invalid time": {
+			value:   "01:00-25:00",
+			wantErr: `invalid time of day "25:00"`,
+		},
+		"empty": {
+			value:   " ",
+			wantErr: "want [days] HH:MM-HH:MM",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			got, err := ParseMaintenanceWindow(tc.value)
+			if tc.wantErr != "" {
+				req.ErrorContains(err, tc.wantErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(tc.want, got)
+		})
+	}
+}
+
+func TestMaintenanceWindow_Contains(t *testing.T) {
+	// 2025-06-06 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.June, day, hour, minute, 0, 0, time.Local)
+	}
+	w, err := ParseMaintenanceWindow("fri 23:00-01:00")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		t    time.Time
+		want bool
+	}{
+		"before opening":            {t: at(6, 22, 59), want: false},
+		"at opening":                {t: at(6, 23, 0), want: true},
+		"after midnight":            {t: at(7, 0, 30), want: true},
+		"at closing":                {t: at(7, 1, 0), want: false},
+		"same time on another day":  {t: at(5, 23, 30), want: false},
+		"past midnight of thursday": {t: at(6, 0, 30), want: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, w.Contains(tc.t))
+		})
+	}
+
+	require.True(t, MaintenanceWindows(nil).Open(time.Now()))
+	require.False(t, closedWindows().Open(time.Now()))
+}
+
+func TestManager_ApplyDirectSnapshots_maintenance(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	m.fullBackupInterval = 1
+	m.maxSnapshotLimit = 10
+	m.maintenance = closedWindows()
+
+	write := func(rowKey string) {
+		req.NoError(m.Apply(rowKey, "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+			time.Now().UnixNano(), 0))
+		req.NoError(m.Flush())
+	}
+	countFiles := func(glob string) int {
+		files, err := filepath.Glob(filepath.Join(m.dataDir, glob))
+		req.NoError(err)
+		return len(files)
+	}
+
+	// without a backup to build on, a full backup is written outside the windows
+	write("champ:1")
+	req.Equal(1, countFiles(backupFileGlob))
+
+	// later full backups wait for a window
+	write("champ:2")
+	write("champ:3")
+	req.Equal(1, countFiles(backupFileGlob))
+	req.Equal(2, countFiles(deltaFileGlob))
+
+	m.maintenance = nil
+	write("champ:4")
+	req.Equal(2, countFiles(backupFileGlob))
+
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Len(backup, 4)
+}
+
+func TestManager_ReclaimByPolicy_maintenance(t *testing.T) {
+	req := require.New(t)
+
+	shards, err := initializeDataShards(&shardConfig{count: 1})
+	req.NoError(err)
+
+	m := &Manager{
+		shardCount:     1,
+		shardMap:       shards,
+		familyPolicies: map[string]FamilyPolicy{"main": {MaxVersions: 1}},
+		maintenance:    closedWindows(),
+	}
+
+	now := time.Now().UnixNano()
+	for i := range 40 {
+		key := fmt.Sprintf("champ:%d", i)
+		m.shardMap[0].data[key] = newRow(map[string]litetable.VersionedQualifier{
+			"main": {"name": {
+				{Value: []byte("Ahri"), Timestamp: now},
+				{Value: []byte("Annie"), Timestamp: now - 1},
+			}},
+		})
+	}
+
+	// outside the windows the budget is throttled to a tenth
+	cells, _ := m.ReclaimByPolicy(2 * throttleFactor)
+	req.Equal(2, cells)
+
+	m.maintenance = nil
+	cells, _ = m.ReclaimByPolicy(2 * throttleFactor)
+	req.Equal(2*throttleFactor, cells)
+}
@@ -6,22 +6,28 @@ import (
 	"fmt"
 	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
 	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/logging"
 	"github.com/litetable/litetable-db/internal/shard_storage/reaper"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 	"hash/fnv"
 	"os"
 	"path/filepath"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type cdc interface {
 	Emit(params *v1.CDCEvent)
+	Spill(params *v1.CDCEvent) bool
 }
 
 type garbageCollector interface {
 	Reap(p *reaper.ReapParams)
+	Drain() error
+	Pending() ([]reaper.ReapParams, error)
+	SetInterval(seconds int) error
 }
 
 const (
@@ -29,11 +35,16 @@ const (
 	snapshotDir        = ".snapshots"
 	dataFamilyLockFile = "families.config.json"
 	backupFileGlob     = "backup-*.db"
+	// checksumSuffix names the file holding the SHA-256 of a backup or snapshot file
+	checksumSuffix = ".sha256"
+	// tempSuffix names the file a backup or snapshot is written to before it is renamed
+	tempSuffix = ".tmp"
 )
 
 var (
 	standardSnapshotPruneTime = 1 // TODO: make this not run every minute
 	defaultShardCount         = 2
+	defaultGCInterval         = 10
 )
 
 // Manager handles persistent storage operations to a disk
@@ -41,22 +52,76 @@ type Manager struct {
 	rootDir string
 	dataDir string
 	mutex   sync.RWMutex
-
-	backupTimer      time.Duration
+	// familiesMutex serializes changes to the allowed families, so a check that a family does
+	// not exist holds until it is created
+	familiesMutex sync.Mutex
+
+	// backupTimer and snapshotTimer are durations in nanoseconds. SetTimers changes them at
+	// runtime and signals timersChanged so the background loop resets its tickers.
+	backupTimer      atomic.Int64
+	timersChanged    chan struct{}
 	maxSnapshotLimit int
+	backupRetention  BackupRetention
+	// fullBackupInterval is the number of merges per full backup; the merges in between are
+	// written as deltas on the latest full backup
+	fullBackupInterval int
+	// maintenance are the windows in which full backups are rewritten and policies scanned at
+	// full speed
+	maintenance MaintenanceWindows
+	// writeThrottle bounds the bytes per second written to snapshot, backup and delta files
+	writeThrottle *WriteThrottle
+	// disk refuses snapshots and backups while the disk is low on space
+	disk *DiskMonitor
 
 	allowedFamilies []string // Maps family names to allowed columns
 	familiesFile    string   // Path to store allowed family configuration
 
 	// create a house for the snapshot process
-	changedRows   map[string]map[string]struct{} // initialized when first row is marked
-	snapshotTimer time.Duration
+	snapshotTimer atomic.Int64
 	snapshotDir   string
+	// persistMutex serializes snapshots and merges between the background loop and Flush
+	persistMutex sync.Mutex
+	// barrier is held for reading by every change to the shards and for writing while a
+	// snapshot is copied, so each snapshot is one logical point in time across all shards.
+	// epoch counts the snapshots taken behind the barrier.
+	barrier sync.RWMutex
+	epoch   uint64
+	// sequence numbers the mutations, so reads can see every shard at the same point
+	sequence atomic.Uint64
 
 	// garbage collection
 	reaper garbageCollector
 
+	// retention policies enforced incrementally by the reaper
+	familyPolicies map[string]FamilyPolicy
+	quota          Quota
+	policyMutex    sync.Mutex
+	policyCursor   policyCursor
+
+	// stats are the family stats from the last run of the stats job, every statsInterval
+	stats         atomic.Pointer[tableStats]
+	statsInterval time.Duration
+
+	// maxRegexProgram and regexScanBudget bound the cost of the pattern of a regex scan and the
+	// time it holds the read lock of each shard
+	maxRegexProgram int
+	regexScanBudget time.Duration
+	// scanCache holds the rows of recent prefix reads; it is nil when they are not cached
+	scanCache *scanCache
+	// readViewInterval is the time between refreshes of the read views; 0 keeps none
+	readViewInterval time.Duration
+	// loadReport is what Start loaded from disk
+	loadReport litetable.LoadReport
+
 	cdc cdc
+	// table names the table on CDC events
+	table string
+	// cdcOldValues reports the value each change replaces on its CDC events
+	cdcOldValues bool
+	// outbox holds the CDC events until a snapshot holds their changes; it is nil when events
+	// are emitted as the changes are made
+	outbox *outbox
+	logger zerolog.Logger
 
 	procCtx   context.Context
 	ctxCancel context.CancelFunc
@@ -73,6 +138,55 @@ type Config struct {
 	MaxSnapshotLimit int
 	ShardCount       int
 	CDCEmitter       cdc
+	// Table is the name of the table the storage holds, for its CDC events. Defaults to the
+	// default table.
+	Table string
+	// CDCOldValues reports the latest value of a cell before each change on its CDC event.
+	CDCOldValues bool
+	// CDCDurable holds each CDC event until a snapshot holds its change, so a change a crash
+	// erases is never emitted. Events are delayed by up to the snapshot timer.
+	CDCDurable bool
+	// CDCOutboxSize bounds the events CDCDurable holds. Defaults to DefaultCDCOutboxSize.
+	CDCOutboxSize int
+	// CDCOverflowPolicy is what happens to the event held longest when the held events are at
+	// CDCOutboxSize: one of the CDC overflow policies. Defaults to blocking, which emits it.
+	CDCOverflowPolicy string
+	// GCInterval is the number of seconds between garbage collections. Defaults to 10.
+	GCInterval int
+	// FamilyPolicies are optional retention rules keyed by family name.
+	FamilyPolicies map[string]FamilyPolicy
+	// Quota limits the space the whole table takes.
+	Quota Quota
+	// BackupRetention keeps backups by age in addition to the newest MaxSnapshotLimit.
+	BackupRetention BackupRetention
+	// FullBackupInterval rewrites the full backup on every nth merge of snapshots and writes a
+	// delta on it otherwise. 0 or 1 rewrites the full backup on every merge.
+	FullBackupInterval int
+	// StatsInterval is the time between runs of the job that computes the stats of each family.
+	// Defaults to 10 minutes.
+	StatsInterval time.Duration
+	// MaintenanceWindows hold full backup rewrites and throttle the family policy scan outside
+	// of them. Without windows, both always run at full speed.
+	MaintenanceWindows MaintenanceWindows
+	// WriteThrottle bounds the bytes per second written to snapshot, backup and delta files. It
+	// can be shared with the storage of other tables. Nil is unlimited.
+	WriteThrottle *WriteThrottle
+	// DiskMonitor refuses snapshots and backups while the disk is low on space. It can be shared
+	// with the storage of other tables. Nil never refuses.
+	DiskMonitor *DiskMonitor
+	// MaxRegexProgram is the most instructions the row key regex of a scan may compile to.
+	// Defaults to DefaultMaxRegexProgram.
+	MaxRegexProgram int
+	// RegexScanBudget is how long a regex scan may read one shard before it is abandoned.
+	// Defaults to DefaultRegexScanBudget.
+	RegexScanBudget time.Duration
+	// ScanCacheTTL caches the rows of prefix reads for this long, or until a row under the
+	// prefix changes. Values that expire meanwhile are returned until then. 0 does not cache.
+	ScanCacheTTL time.Duration
+	// ReadViewInterval keeps a copy of each shard, refreshed this often, that eventually
+	// consistent reads use without waiting on the shard locks. It takes as much memory as the
+	// rows. 0 keeps no copies, and eventually consistent reads lock the shards.
+	ReadViewInterval time.Duration
 }
 
 func (c *Config) validate() error {
@@ -98,9 +212,66 @@ func (c *Config) validate() error {
 		errGrp = append(errGrp, fmt.Errorf("shard count must be between 1 and 50"))
 	}
 
+	if c.GCInterval < 0 {
+		errGrp = append(errGrp, fmt.Errorf("gc interval cannot be negative"))
+	}
+
+	if c.StatsInterval < 0 {
+		errGrp = append(errGrp, fmt.Errorf("stats interval cannot be negative"))
+	}
+
+	if c.FullBackupInterval < 0 {
+		errGrp = append(errGrp, fmt.Errorf("full backup interval cannot be negative"))
+	}
+
+	if c.MaxRegexProgram < 0 {
+		errGrp = append(errGrp, fmt.Errorf("max regex program cannot be negative"))
+	}
+
+	if c.RegexScanBudget < 0 {
+		errGrp = append(errGrp, fmt.Errorf("regex scan budget cannot be negative"))
+	}
+
+	if c.ScanCacheTTL < 0 {
+		errGrp = append(errGrp, fmt.Errorf("scan cache ttl cannot be negative"))
+	}
+
+	if c.ReadViewInterval < 0 {
+		errGrp = append(errGrp, fmt.Errorf("read view interval cannot be negative"))
+	}
+
+	if err := c.BackupRetention.validate(); err != nil {
+		errGrp = append(errGrp, err)
+	}
+
 	if c.CDCEmitter == nil {
 		errGrp = append(errGrp, fmt.Errorf("CDC emitter is required"))
 	}
+
+	for family, policy := range c.FamilyPolicies {
+		if policy.MaxAge < 0 {
+			errGrp = append(errGrp, fmt.Errorf("max age for family %s cannot be negative", family))
+		}
+		if policy.MaxVersions < 0 {
+			errGrp = append(errGrp, fmt.Errorf("max versions for family %s cannot be negative",
+				family))
+		}
+		if policy.DefaultTTL < 0 {
+			errGrp = append(errGrp, fmt.Errorf("default ttl for family %s cannot be negative",
+				family))
+		}
+		if err := policy.Quota.validate("family " + family); err != nil {
+			errGrp = append(errGrp, err)
+		}
+		for qualifier, t := range policy.Types {
+			if err := t.validate(family, qualifier); err != nil {
+				errGrp = append(errGrp, err)
+			}
+		}
+	}
+	if err := c.Quota.validate("the table"); err != nil {
+		errGrp = append(errGrp, err)
+	}
 	return errors.Join(errGrp...)
 }
 
@@ -120,30 +291,71 @@ func New(cfg *Config) (*Manager, *reaper.Reaper, error) {
 		return nil, nil, fmt.Errorf("failed to create snapshot directory: %w", err)
 	}
 
+	// a crash while writing can leave a temporary file, which is never complete
+	for _, dir := range []string{backupDir, snapDir} {
+		if err := removeTempFiles(dir); err != nil {
+			return nil, nil, fmt.Errorf("failed to remove temporary files: %w", err)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	if cfg.ShardCount == 0 {
 		cfg.ShardCount = defaultShardCount
 	}
 
-	log.Debug().Int("shard_count", cfg.ShardCount).Msg("Shard count")
-
 	m := &Manager{
 		rootDir:          cfg.RootDir,
 		dataDir:          backupDir,
-		snapshotTimer:    time.Duration(cfg.SnapshotTimer) * time.Second,
-		backupTimer:      time.Duration(cfg.FlushThreshold) * time.Second,
+		timersChanged:    make(chan struct{}, 1),
 		allowedFamilies:  make([]string, 0),
 		familiesFile:     filepath.Join(cfg.RootDir, dataFamilyLockFile),
 		maxSnapshotLimit: cfg.MaxSnapshotLimit,
+		backupRetention:  cfg.BackupRetention,
 		snapshotDir:      snapDir,
 		mutex:            sync.RWMutex{},
 		procCtx:          ctx,
 		ctxCancel:        cancel,
 
-		shardCount: cfg.ShardCount,
-		cdc:        cfg.CDCEmitter,
+		fullBackupInterval: cfg.FullBackupInterval,
+		maintenance:        cfg.MaintenanceWindows,
+		writeThrottle:      cfg.WriteThrottle,
+		disk:               cfg.DiskMonitor,
+
+		shardCount:     cfg.ShardCount,
+		cdc:            cfg.CDCEmitter,
+		table:          cfg.Table,
+		cdcOldValues:   cfg.CDCOldValues,
+		familyPolicies: cfg.FamilyPolicies,
+		quota:          cfg.Quota,
+		statsInterval:  cfg.StatsInterval,
+		logger:         logging.For("shard_storage"),
+
+		maxRegexProgram:  cfg.MaxRegexProgram,
+		regexScanBudget:  cfg.RegexScanBudget,
+		readViewInterval: cfg.ReadViewInterval,
+	}
+	m.snapshotTimer.Store(int64(time.Duration(cfg.SnapshotTimer) * time.Second))
+	m.backupTimer.Store(int64(time.Duration(cfg.FlushThreshold) * time.Second))
+	if m.statsInterval == 0 {
+		m.statsInterval = defaultStatsInterval
+	}
+	if m.maxRegexProgram == 0 {
+		m.maxRegexProgram = DefaultMaxRegexProgram
 	}
+	if m.regexScanBudget == 0 {
+		m.regexScanBudget = DefaultRegexScanBudget
+	}
+	if cfg.ScanCacheTTL > 0 {
+		m.scanCache = newScanCache(cfg.ScanCacheTTL)
+	}
+	if m.table == "" {
+		m.table = litetable.DefaultTable
+	}
+	if cfg.CDCDurable {
+		m.outbox = newOutbox(cfg.CDCOutboxSize, cfg.CDCOverflowPolicy)
+	}
+	m.logger.Debug().Int("shard_count", cfg.ShardCount).Msg("Shard count")
 
 	// load any existing column families
 	if err := m.loadAllowedFamilies(); err != nil {
@@ -165,10 +377,17 @@ func New(cfg *Config) (*Manager, *reaper.Reaper, error) {
 	}
 
 	// create a garbage collector
+	gcInterval := cfg.GCInterval
+	if gcInterval == 0 {
+		gcInterval = defaultGCInterval
+	}
+	// one reaper worker collects each shard
 	gc, err := reaper.New(&reaper.Config{
 		Path:       cfg.RootDir,
 		Storage:    m,
-		GCInterval: 10,
+		GCInterval: gcInterval,
+		Shards:     cfg.ShardCount,
+		ShardOf:    m.getShardIndex,
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create garbage collector: %w", err)
@@ -185,41 +404,117 @@ func (m *Manager) Start() error {
 	if err := m.loadFromLatestBackup(); err != nil {
 		return err
 	}
+	m.recountUsage()
+	m.restoreSequence()
+	m.computeStats()
+	if m.readViewInterval > 0 {
+		m.refreshViews()
+		go m.runViewRefresh()
+	}
 
 	// Start the background process for snapshots
 	go func() {
-		snapshotTicker := time.NewTicker(m.snapshotTimer)
+		snapshotTicker := time.NewTicker(time.Duration(m.snapshotTimer.Load()))
 		// whatever the snapshot is, add 50%
-		snapshotMerge := time.NewTicker(m.backupTimer + (m.backupTimer / 2))
+		snapshotMerge := time.NewTicker(m.mergeInterval())
 		pruneTicker := time.NewTicker(time.Duration(standardSnapshotPruneTime) * time.Minute)
+		statsTicker := time.NewTicker(m.statsInterval)
 
 		defer func() {
 			snapshotTicker.Stop()
+			snapshotMerge.Stop()
 			pruneTicker.Stop()
+			statsTicker.Stop()
 		}()
 
 		for {
 			select {
 			case <-m.procCtx.Done():
 				return
+			case <-m.timersChanged:
+				snapshotTicker.Reset(time.Duration(m.snapshotTimer.Load()))
+				snapshotMerge.Reset(m.mergeInterval())
 			case <-snapshotTicker.C:
 				err := m.createDirectSnapshot()
 				if err != nil {
-					fmt.Printf("failed to save snapshot: %v\n", err)
+					m.logger.Error().Err(err).Msg("failed to save snapshot")
 				}
 			case <-snapshotMerge.C:
 				err := m.ApplyDirectSnapshots()
 				if err != nil {
-					fmt.Printf("failed to merge snapshot: %v\n", err)
+					m.logger.Error().Err(err).Msg("failed to merge snapshot")
 				}
+				m.recountUsage()
 			case <-pruneTicker.C:
 				m.maintainBackupLimit()
+			case <-statsTicker.C:
+				m.computeStats()
 			}
 		}
 	}()
 	return nil
 }
 
+// runViewRefresh refreshes the read views until the manager stops.
+func (m *Manager) runViewRefresh() {
+	ticker := time.NewTicker(m.readViewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.procCtx.Done():
+			return
+		case <-ticker.C:
+			m.refreshViews()
+		}
+	}
+}
+
+// mergeInterval is the time between snapshot merges: the backup timer plus 50%.
+func (m *Manager) mergeInterval() time.Duration {
+	backup := time.Duration(m.backupTimer.Load())
+	return backup + (backup / 2)
+}
+
+// ValidateTimers checks snapshot, backup and garbage collection intervals, in seconds, as
+// SetTimers would, without applying them. A zero gc interval uses the default.
+func ValidateTimers(snapshot, backup, gc int) error {
+	var errGrp []error
+	if snapshot < 1 {
+		errGrp = append(errGrp, fmt.Errorf("snapshot timer must be greater than 0"))
+	}
+	if backup <= 0 {
+		errGrp = append(errGrp, fmt.Errorf("flush threshold must be greater than 0"))
+	}
+	if gc < 0 {
+		errGrp = append(errGrp, fmt.Errorf("gc interval cannot be negative"))
+	}
+	return errors.Join(errGrp...)
+}
+
+// SetTimers changes the snapshot, backup and garbage collection intervals, in seconds, without
+// restarting the manager. A zero gc interval uses the default. Nothing is changed if any value
+// is invalid.
+func (m *Manager) SetTimers(snapshot, backup, gc int) error {
+	if err := ValidateTimers(snapshot, backup, gc); err != nil {
+		return err
+	}
+	if gc == 0 {
+		gc = defaultGCInterval
+	}
+
+	if err := m.reaper.SetInterval(gc); err != nil {
+		return err
+	}
+
+	m.snapshotTimer.Store(int64(time.Duration(snapshot) * time.Second))
+	m.backupTimer.Store(int64(time.Duration(backup) * time.Second))
+	select {
+	case m.timersChanged <- struct{}{}:
+	default: // a reset is already pending and will pick up the new timers
+	}
+	return nil
+}
+
 // Stop is a blocking operation that flushes any remaining data to a snapshot before
 // allowing the process to shut down.
 func (m *Manager) Stop() error {
@@ -227,6 +522,12 @@ func (m *Manager) Stop() error {
 		m.ctxCancel()
 	}
 
+	// every tombstone in the final snapshot needs a durable reap entry, otherwise it would never
+	// be collected after a restart
+	if err := m.reaper.Drain(); err != nil {
+		m.logger.Error().Err(err).Msg("failed to drain reaper queue")
+	}
+
 	// Flush any remaining data
 	err := m.createDirectSnapshot()
 	if err != nil {
@@ -237,6 +538,20 @@ func (m *Manager) Stop() error {
 	return m.ApplyDirectSnapshots()
 }
 
+// Flush blocks until every change made before the call is merged into an fsynced backup. It
+// rewrites the backup, so it is far more expensive than a write and should be used sparingly.
+func (m *Manager) Flush() error {
+	// as on Stop, tombstones in the backup need a durable reap entry
+	if err := m.reaper.Drain(); err != nil {
+		return fmt.Errorf("failed to drain reaper queue: %w", err)
+	}
+
+	if err := m.createDirectSnapshot(); err != nil {
+		return fmt.Errorf("failed to flush data: %w", err)
+	}
+	return m.ApplyDirectSnapshots()
+}
+
 func (m *Manager) Name() string {
 	return "Shard Storage"
 }
@@ -275,7 +590,7 @@ func (m *Manager) distributeDataToShards(loadedData litetable.Data) error {
 	for i := range m.shardMap {
 		m.shardMap[i].mutex.Lock()
 		if m.shardMap[i].data == nil {
-			m.shardMap[i].data = make(litetable.Data)
+			m.shardMap[i].data = make(map[string]*row)
 		}
 		m.shardMap[i].mutex.Unlock()
 	}
@@ -291,7 +606,7 @@ func (m *Manager) distributeDataToShards(loadedData litetable.Data) error {
 
 				// Add the data to the shard
 				m.shardMap[shardIdx].mutex.Lock()
-				m.shardMap[shardIdx].data[item.key] = item.families
+				m.shardMap[shardIdx].data[item.key] = newRow(item.families)
 				m.shardMap[shardIdx].mutex.Unlock()
 			}
 		}()
@@ -320,7 +635,12 @@ func (m *Manager) distributeDataToShards(loadedData litetable.Data) error {
 // getShardIndex determines which shard a particular row key belongs to.
 // It uses a consistent hashing approach to distribute keys evenly across shards.
 func (m *Manager) getShardIndex(rowKey string) int {
-	if m.shardCount <= 0 {
+	return shardIndex(rowKey, m.shardCount)
+}
+
+// shardIndex returns the shard of a row key among count shards.
+func shardIndex(rowKey string, count int) int {
+	if count <= 0 {
 		return 0
 	}
 
@@ -330,25 +650,5 @@ func (m *Manager) getShardIndex(rowKey string) int {
 	hash := h.Sum32()
 
 	// Modulo to get shard index within range
-	return int(hash % uint32(m.shardCount))
-}
-
-// MarkRowChanged will save the row key and family name to the changedRows map.
-//
-// We are using an empty struct{} because it takes 0 bytes.
-func (m *Manager) MarkRowChanged(family, rowKey string) {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
-	if m.changedRows == nil {
-		m.changedRows = make(map[string]map[string]struct{})
-	}
-
-	if _, exists := m.changedRows[rowKey]; !exists {
-		m.changedRows[rowKey] = make(map[string]struct{})
-	}
-
-	// Add the family to the row key (this may be overwriting, but that's okay because
-	// we just want to make sure the family is in the map)
-	m.changedRows[rowKey][family] = struct{}{}
+	return int(hash % uint32(count))
 }
@@ -0,0 +1,139 @@
+package shard_storage
+
+import (
+	"errors"
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/shard_storage/reaper"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeReaper struct {
+	interval int
+	pending  []reaper.ReapParams
+}
+
+func (f *fakeReaper) Reap(*reaper.ReapParams) {}
+func (f *fakeReaper) Drain() error            { return nil }
+func (f *fakeReaper) Pending() ([]reaper.ReapParams, error) {
+	return f.pending, nil
+}
+func (f *fakeReaper) SetInterval(seconds int) error {
+	if seconds <= 0 {
+		return errors.New("GCInterval must be greater than 0")
+	}
+	f.interval = seconds
+	return nil
+}
+
+type fakeCDC struct{}
+
+func (fakeCDC) Emit(*v1.CDCEvent) {}
+
+func (fakeCDC) Spill(*v1.CDCEvent) bool { return false }
+
+// newTestManager creates a manager in a temporary directory with the "main" family.
+func newTestManager(t testing.TB) *Manager {
+	m, _, err := New(&Config{
+		RootDir:        t.TempDir(),
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.UpdateFamilies([]string{"main"}))
+	return m
+}
+
+func TestManager_Flush(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.Flush())
+
+	backup, err := m.loadLatestBackup()
+	req.NoError(err)
+	req.Equal([]byte("Ahri"), backup["champ:1"]["main"]["name"][0].Value)
+
+	// the snapshot was merged, and nothing is left to snapshot
+	snapshots, err := filepath.Glob(filepath.Join(m.snapshotDir, snapshotFileGlob))
+	req.NoError(err)
+	req.Empty(snapshots)
+	req.Empty(m.takeChanges())
+}
+
+func TestManager_SetTimers(t *testing.T) {
+	tests := map[string]struct {
+		snapshot, backup, gc int
+		wantGC               int
+		wantErr              string
+	}{
+		"valid": {
+			snapshot: 5, backup: 60, gc: 30,
+			wantGC: 30,
+		},
+		"default gc interval": {
+			snapshot: 5, backup: 60,
+			wantGC: defaultGCInterval,
+		},
+		"invalid values change nothing": {
+			snapshot: 0, backup: -1, gc: -1,
+			wantErr: "snapshot timer must be greater than 0\n" +
+				"flush threshold must be greater than 0\ngc interval cannot be negative",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			gc := &fakeReaper{interval: 1}
+			m := &Manager{
+				reaper:        gc,
+				timersChanged: make(chan struct{}, 1),
+			}
+			m.snapshotTimer.Store(int64(time.Second))
+			m.backupTimer.Store(int64(time.Second))
+
+			req.Equal(tc.wantErr == "", ValidateTimers(tc.snapshot, tc.backup, tc.gc) == nil)
+			err := m.SetTimers(tc.snapshot, tc.backup, tc.gc)
+			if tc.wantErr != "" {
+				req.EqualError(err, tc.wantErr)
+				req.Equal(int64(time.Second), m.snapshotTimer.Load())
+				req.Equal(1, gc.interval)
+				req.Len(m.timersChanged, 0)
+				return
+			}
+
+			req.NoError(err)
+			req.Equal(int64(time.Duration(tc.snapshot)*time.Second), m.snapshotTimer.Load())
+			req.Equal(time.Duration(tc.backup)*time.Second*3/2, m.mergeInterval())
+			req.Equal(tc.wantGC, gc.interval)
+			req.Len(m.timersChanged, 1)
+		})
+	}
+}
+
+// recordingCDC keeps the events emitted and spilled to it.
+type recordingCDC struct {
+	mutex   sync.Mutex
+	events  []*v1.CDCEvent
+	spilled []*v1.CDCEvent
+}
+
+func (r *recordingCDC) Emit(evt *v1.CDCEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, evt)
+}
+
+func (r *recordingCDC) Spill(evt *v1.CDCEvent) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spilled = append(r.spilled, evt)
+	return true
+}
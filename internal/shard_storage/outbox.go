@@ -0,0 +1,114 @@
+package shard_storage
+
+import (
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"sort"
+	"sync"
+)
+
+// DefaultCDCOutboxSize is the most CDC events held for durable CDC when the config does not say.
+const DefaultCDCOutboxSize = 100000
+
+var outboxOverflows = metrics.NewCounterVec("litetable_cdc_outbox_overflow_total",
+	"Durable CDC events let go of before a snapshot held their changes, because the outbox was "+
+		"full, by the overflow policy applied to them.", "policy")
+
+// outbox holds the CDC events of the changes that are not in a snapshot yet, so subscribers
+// never see a change that a crash could still erase. A snapshot holds every change numbered up
+// to the sequence at its barrier, so once it is written the events up to that sequence are
+// released, in sequence order.
+type outbox struct {
+	mutex  sync.Mutex
+	events []*v1.CDCEvent
+	// size bounds the held events, and policy is the CDC overflow policy applied to the event
+	// held longest when another is added to a full outbox
+	size   int
+	policy string
+}
+
+func newOutbox(size int, policy string) *outbox {
+	if size == 0 {
+		size = DefaultCDCOutboxSize
+	}
+	if policy == "" {
+		policy = v1.OverflowBlock
+	}
+	return &outbox{size: size, policy: policy}
+}
+
+// add holds an event until its change is in a snapshot. When the outbox is full, it lets go of
+// the event held longest and returns it.
+func (o *outbox) add(evt *v1.CDCEvent) *v1.CDCEvent {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	var overflowed *v1.CDCEvent
+	if len(o.events) >= o.size {
+		overflowed = o.events[0]
+		o.events[0] = nil
+		o.events = o.events[1:]
+	}
+	o.events = append(o.events, evt)
+	return overflowed
+}
+
+// release removes and returns the events of the changes numbered up to seq, in sequence order.
+func (o *outbox) release(seq uint64) []*v1.CDCEvent {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	var released, held []*v1.CDCEvent
+	for _, evt := range o.events {
+		if evt.TableSequence <= seq {
+			released = append(released, evt)
+		} else {
+			held = append(held, evt)
+		}
+	}
+	o.events = held
+
+	// shards add their events concurrently, so they are not in order; the events of one
+	// mutation keep the order they were added in
+	sort.SliceStable(released, func(i, j int) bool {
+		return released[i].TableSequence < released[j].TableSequence
+	})
+	return released
+}
+
+// emit sends an event to the CDC stream, or holds it in the outbox until its change is durable.
+func (m *Manager) emit(evt *v1.CDCEvent) {
+	if m.cdc == nil {
+		return
+	}
+	if m.outbox != nil {
+		if evt = m.outbox.add(evt); evt != nil {
+			m.overflowEvent(evt)
+		}
+		return
+	}
+	m.cdc.Emit(evt)
+}
+
+// overflowEvent applies the CDC overflow policy to an event the full outbox let go of before a
+// snapshot holds its change: it is dropped, spilled for replay, or, by default, emitted.
+func (m *Manager) overflowEvent(evt *v1.CDCEvent) {
+	outboxOverflows.With(m.outbox.policy).Inc()
+	switch m.outbox.policy {
+	case v1.OverflowDropOldest:
+	case v1.OverflowSpill:
+		m.cdc.Spill(evt)
+	default:
+		m.cdc.Emit(evt)
+	}
+}
+
+// releaseEvents sends the held events of the changes numbered up to seq, now that a snapshot
+// holds them.
+func (m *Manager) releaseEvents(seq uint64) {
+	if m.outbox == nil {
+		return
+	}
+	for _, evt := range m.outbox.release(seq) {
+		m.cdc.Emit(evt)
+	}
+}
@@ -0,0 +1,119 @@
+package shard_storage
+
+import (
+	"fmt"
+	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManager_durableCDC(t *testing.T) {
+	req := require.New(t)
+	events := &recordingCDC{}
+	m, _, err := New(&Config{
+		RootDir:        t.TempDir(),
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     events,
+		CDCDurable:     true,
+	})
+	req.NoError(err)
+	req.NoError(m.UpdateFamilies([]string{"main"}))
+
+	now := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")}, now, 0))
+	req.NoError(m.Apply("champ:2", "main", []string{"name"}, [][]byte{[]byte("Jinx")}, now, 0))
+
+	// nothing is emitted until a snapshot holds the changes
+	injectFaults(t, &testFaults{writeFailures: 1})
+	req.ErrorIs(m.createDirectSnapshot(), errInjected)
+	req.Empty(events.events)
+
+	req.NoError(m.createDirectSnapshot())
+	req.Len(events.events, 2)
+	req.Equal("champ:1", events.events[0].RowKey)
+	req.Equal(uint64(1), events.events[0].TableSequence)
+	req.Equal(uint64(2), events.events[1].TableSequence)
+
+	req.NoError(m.Delete("champ:1", "main", []string{"name"}, now+1, now+20))
+	req.Len(events.events, 2)
+	req.NoError(m.createDirectSnapshot())
+	req.Len(events.events, 3)
+	req.Equal(litetable.OperationDelete, events.events[2].Operation)
+	req.Equal(uint64(3), events.events[2].TableSequence)
+}
+
+func TestOutbox_release(t *testing.T) {
+	req := require.New(t)
+	o := newOutbox(0, "")
+	for _, seq := range []uint64{2, 1, 3, 1} {
+		o.add(&v1.CDCEvent{TableSequence: seq})
+	}
+
+	released := o.release(2)
+	req.Len(released, 3)
+	for i, seq := range []uint64{1, 1, 2} {
+		req.Equal(seq, released[i].TableSequence)
+	}
+	req.Empty(o.release(2))
+	req.Len(o.release(3), 1)
+}
+
+func TestManager_durableCDC_overflow(t *testing.T) {
+	tests := map[string]struct {
+		policy      string
+		wantEmitted []string
+		wantSpilled []string
+	}{
+		"block emits the event held longest": {
+			wantEmitted: []string{"champ:1", "champ:2", "champ:3"},
+		},
+		"drop_oldest drops it": {
+			policy:      v1.OverflowDropOldest,
+			wantEmitted: []string{"champ:2", "champ:3"},
+		},
+		"spill spills it": {
+			policy:      v1.OverflowSpill,
+			wantEmitted: []string{"champ:2", "champ:3"},
+			wantSpilled: []string{"champ:1"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			events := &recordingCDC{}
+			m, _, err := New(&Config{
+				RootDir:           t.TempDir(),
+				FlushThreshold:    60,
+				SnapshotTimer:     5,
+				CDCEmitter:        events,
+				CDCDurable:        true,
+				CDCOutboxSize:     2,
+				CDCOverflowPolicy: tc.policy,
+			})
+			req.NoError(err)
+			req.NoError(m.UpdateFamilies([]string{"main"}))
+
+			now := time.Now().UnixNano()
+			for i := 1; i <= 3; i++ {
+				req.NoError(m.Apply(fmt.Sprintf("champ:%d", i), "main", []string{"name"},
+					[][]byte{[]byte("Ahri")}, now, 0))
+			}
+			req.NoError(m.createDirectSnapshot())
+
+			req.Equal(tc.wantEmitted, rowKeys(events.events))
+			req.Equal(tc.wantSpilled, rowKeys(events.spilled))
+		})
+	}
+}
+
+func rowKeys(events []*v1.CDCEvent) []string {
+	var keys []string
+	for _, evt := range events {
+		keys = append(keys, evt.RowKey)
+	}
+	return keys
+}
@@ -0,0 +1,184 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"slices"
+	"sort"
+	"time"
+)
+
+// FamilyPolicy are the retention rules the reaper enforces on every cell of a column family,
+// independent of explicit deletes, the quota writes to the family are held to and how its
+// values are stored.
+type FamilyPolicy struct {
+	// MaxAge removes versions older than this duration. Zero keeps versions regardless of age.
+	MaxAge time.Duration
+	// MaxVersions keeps only the newest N versions of a qualifier. Zero keeps every version.
+	MaxVersions int
+	// DefaultTTL expires values written to the family without a TTL of their own. Zero keeps
+	// them until they are deleted.
+	DefaultTTL time.Duration
+	// Quota limits the space the family takes in each table.
+	Quota Quota
+	// Compress stores the values written to the family compressed with zstd, for families of
+	// large text payloads. Values that do not shrink are stored as written.
+	Compress bool
+	// Types declares the type of the values of qualifiers, by qualifier name. Writes of values
+	// that are not of the type fail.
+	Types map[string]ValueType
+}
+
+// policyCursor tracks the progress of the incremental policy scan. Each shard is scanned from a
+// copy of its row keys, so a pass over a large shard can be spread over many reaper ticks.
+type policyCursor struct {
+	shard int
+	// next is the shard scanned after this one, so the first scan starts at shard 0
+	next int
+	keys []string
+	pos  int
+}
+
+// ReclaimByPolicy enforces the configured family policies on at most budget rows, continuing
+// where the previous call stopped. Outside the maintenance windows the budget is throttled. It
+// returns the number of cells removed and an estimate of the bytes reclaimed.
+//
+// The scan also removes the values of every family whose TTL has passed, which are counted as
+// expired rather than returned. The reaper removes them without the scan as well, but a value
+// whose reap entry was lost in a crash would otherwise stay in memory, hidden from reads.
+func (m *Manager) ReclaimByPolicy(budget int) (int, int64) {
+	if budget <= 0 || len(m.shardMap) == 0 {
+		return 0, 0
+	}
+	if !m.maintenance.Open(time.Now()) {
+		budget = max(budget/throttleFactor, 1)
+	}
+
+	m.policyMutex.Lock()
+	defer m.policyMutex.Unlock()
+
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
+	c := &m.policyCursor
+	if c.pos >= len(c.keys) {
+		// move on to the next shard and take a copy of its row keys
+		c.shard = c.next % len(m.shardMap)
+		c.next = c.shard + 1
+		c.pos = 0
+
+		s := m.shardMap[c.shard]
+		s.RLock()
+		c.keys = make([]string, 0, len(s.data))
+		for rowKey := range s.data {
+			c.keys = append(c.keys, rowKey)
+		}
+		s.RUnlock()
+	}
+
+	end := c.pos + budget
+	if end > len(c.keys) {
+		end = len(c.keys)
+	}
+	window := c.keys[c.pos:end]
+	c.pos = end
+
+	type changedFamily struct {
+		family string
+		rowKey string
+	}
+	var changed []changedFamily
+	var cells, expired int
+	var bytes, expiredSize int64
+	now := time.Now().UnixNano()
+
+	s := m.shardMap[c.shard]
+	s.Lock()
+	for _, rowKey := range window {
+		row, exists := s.data[rowKey]
+		if !exists {
+			continue
+		}
+
+		// families are deleted from the row as they empty, so the loop ranges over a copy
+		for _, f := range slices.Clone(row.families) {
+			n, b := m.familyPolicies[f.name].trim(f.qualifiers, now)
+			cells += n
+			bytes += b
+			en, eb := expire(f.qualifiers, now)
+			expired += en
+			expiredSize += eb
+			if n+en == 0 {
+				continue
+			}
+			changed = append(changed, changedFamily{family: f.name, rowKey: rowKey})
+
+			if len(f.qualifiers) == 0 {
+				row.deleteFamily(f.name)
+			}
+		}
+
+		if row.isEmpty() {
+			delete(s.data, rowKey)
+		}
+	}
+	s.Unlock()
+
+	for _, c := range changed {
+		m.MarkRowChanged(c.family, c.rowKey)
+	}
+	m.countExpired(expired, expiredSize)
+
+	return cells, bytes
+}
+
+// trim removes every version that falls outside the policy. Tombstones are left for the reaper's
+// delete flow and do not count as versions. Values are copied before they are modified because
+// readers may still hold the previous slice.
+func (p FamilyPolicy) trim(qualifiers litetable.VersionedQualifier, now int64) (int, int64) {
+	if p.MaxAge <= 0 && p.MaxVersions <= 0 {
+		return 0, 0
+	}
+
+	var removed int
+	var bytes int64
+	oldest := now - int64(p.MaxAge)
+
+	for qualifier, values := range qualifiers {
+		sorted := make([]litetable.TimestampedValue, len(values))
+		copy(sorted, values)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Timestamp > sorted[j].Timestamp
+		})
+
+		kept := sorted[:0]
+		versions := 0
+		for _, v := range sorted {
+			if v.IsTombstone {
+				kept = append(kept, v)
+				continue
+			}
+
+			expired := p.MaxAge > 0 && v.Timestamp < oldest
+			tooMany := p.MaxVersions > 0 && versions >= p.MaxVersions
+			if expired || tooMany {
+				removed++
+				bytes += v.Size()
+				continue
+			}
+
+			versions++
+			kept = append(kept, v)
+		}
+
+		if len(kept) == len(values) {
+			continue
+		}
+		if len(kept) == 0 {
+			delete(qualifiers, qualifier)
+			continue
+		}
+		qualifiers[qualifier] = kept
+	}
+
+	return removed, bytes
+}
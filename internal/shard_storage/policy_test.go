@@ -0,0 +1,186 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFamilyPolicy_trim(t *testing.T) {
+	now := time.Now().UnixNano()
+	hourAgo := now - int64(time.Hour)
+	dayAgo := now - int64(24*time.Hour)
+
+	tests := map[string]struct {
+		policy        FamilyPolicy
+		values        []litetable.TimestampedValue
+		expectRemoved int
+		expectKept    []int64
+	}{
+		"no limits keeps everything": {
+			policy: FamilyPolicy{},
+			values: []litetable.TimestampedValue{
+				{Value: []byte("a"), Timestamp: dayAgo},
+				{Value: []byte("b"), Timestamp: now},
+			},
+			expectKept: []int64{dayAgo, now},
+		},
+		"max versions keeps the newest": {
+			policy: FamilyPolicy{MaxVersions: 1},
+			values: []litetable.TimestampedValue{
+				{Value: []byte("a"), Timestamp: dayAgo},
+				{Value: []byte("b"), Timestamp: now},
+				{Value: []byte("c"), Timestamp: hourAgo},
+			},
+			expectRemoved: 2,
+			expectKept:    []int64{now},
+		},
+		"max age drops old versions": {
+			policy: FamilyPolicy{MaxAge: 2 * time.Hour},
+			values: []litetable.TimestampedValue{
+				{Value: []byte("a"), Timestamp: dayAgo},
+				{Value: []byte("b"), Timestamp: hourAgo},
+			},
+			expectRemoved: 1,
+			expectKept:    []int64{hourAgo},
+		},
+		"tombstones are not versions": {
+			policy: FamilyPolicy{MaxVersions: 1},
+			values: []litetable.TimestampedValue{
+				{Timestamp: now, IsTombstone: true},
+				{Value: []byte("b"), Timestamp: hourAgo},
+				{Value: []byte("c"), Timestamp: dayAgo},
+			},
+			expectRemoved: 1,
+			expectKept:    []int64{now, hourAgo},
+		},
+		"everything expired removes the qualifier": {
+			policy: FamilyPolicy{MaxAge: time.Minute},
+			values: []litetable.TimestampedValue{
+				{Value: []byte("a"), Timestamp: dayAgo},
+			},
+			expectRemoved: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			qualifiers := litetable.VersionedQualifier{"q": tc.values}
+
+			removed, bytes := tc.policy.trim(qualifiers, now)
+			req.Equal(tc.expectRemoved, removed)
+			if tc.expectRemoved > 0 {
+				req.Greater(bytes, int64(0))
+			}
+
+			if len(tc.expectKept) == 0 {
+				req.NotContains(qualifiers, "q")
+				return
+			}
+
+			var kept []int64
+			for _, v := range qualifiers["q"] {
+				kept = append(kept, v.Timestamp)
+			}
+			req.ElementsMatch(tc.expectKept, kept)
+		})
+	}
+}
+
+func TestManager_ReclaimByPolicy(t *testing.T) {
+	req := require.New(t)
+
+	shards, err := initializeDataShards(&shardConfig{count: 2})
+	req.NoError(err)
+
+	m := &Manager{
+		shardCount: 2,
+		shardMap:   shards,
+		familyPolicies: map[string]FamilyPolicy{
+			"wrestlers": {MaxVersions: 1},
+		},
+	}
+
+	now := time.Now().UnixNano()
+	for _, key := range []string{"champ:1", "champ:2", "champ:3", "champ:4"} {
+		s := m.shardMap[m.getShardIndex(key)]
+		s.data[key] = newRow(map[string]litetable.VersionedQualifier{
+			"wrestlers": {
+				"championships": {
+					{Value: []byte("16"), Timestamp: now},
+					{Value: []byte("15"), Timestamp: now - 1},
+				},
+			},
+			"untouched": {
+				"q": {
+					{Value: []byte("1"), Timestamp: now},
+					{Value: []byte("2"), Timestamp: now - 1},
+				},
+			},
+		})
+	}
+
+	// the first pass starts at shard 0
+	req.NotEmpty(m.shardMap[0].data)
+	req.NotEmpty(m.shardMap[1].data)
+	cells, _ := m.ReclaimByPolicy(10)
+	req.Equal(len(m.shardMap[0].data), cells)
+	for key, r := range m.shardMap[0].data {
+		req.Len(r.columns()["wrestlers"]["championships"], 1, key)
+	}
+	for key, r := range m.shardMap[1].data {
+		req.Len(r.columns()["wrestlers"]["championships"], 2, key)
+	}
+
+	// a budget of one row per call needs several calls to cover the other shard
+	for i := 0; i < 10; i++ {
+		n, _ := m.ReclaimByPolicy(1)
+		cells += n
+	}
+	req.Equal(4, cells)
+
+	for _, s := range m.shardMap {
+		for key, r := range s.data {
+			row := r.columns()
+			req.Len(row["wrestlers"]["championships"], 1, key)
+			req.Equal([]byte("16"), row["wrestlers"]["championships"][0].Value)
+			req.Len(row["untouched"]["q"], 2, key)
+		}
+	}
+	req.Len(m.takeChanges(), 4)
+}
+
+func TestManager_ReclaimByPolicy_expired(t *testing.T) {
+	req := require.New(t)
+
+	shards, err := initializeDataShards(&shardConfig{count: 1})
+	req.NoError(err)
+	m := &Manager{shardCount: 1, shardMap: shards, table: "expiry_test"}
+
+	// values expire without a reap entry or a family policy
+	now := time.Now().UnixNano()
+	m.shardMap[0].data["champ:1"] = newRow(map[string]litetable.VersionedQualifier{
+		"sessions": {
+			"token": {{Value: []byte("abc"), Timestamp: now - 2, ExpiresAt: now - 1}},
+		},
+		"wrestlers": {
+			"name": {
+				{Value: []byte("John"), Timestamp: now - 2},
+				{Value: []byte("Randy"), Timestamp: now - 1, ExpiresAt: now - 1},
+			},
+		},
+	})
+	cells := expiredCells.With("expiry_test").Value()
+
+	n, _ := m.ReclaimByPolicy(10)
+	req.Zero(n, "expired values are not policy reclaims")
+	req.Equal(cells+2, expiredCells.With("expiry_test").Value())
+
+	row := m.shardMap[0].data["champ:1"].columns()
+	req.NotContains(row, "sessions")
+	req.Len(row["wrestlers"]["name"], 1)
+	req.Equal([]byte("John"), row["wrestlers"]["name"][0].Value)
+	req.Len(m.takeChanges()["champ:1"], 2)
+}
@@ -0,0 +1,159 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+)
+
+// Quota limits the space a table or column family takes, so one tenant cannot use all the
+// memory of the server. Zero values are unlimited.
+//
+// Writes are checked against the usage of every shard, which writes to other shards can change
+// at the same time, so concurrent writes can overshoot a quota by the size of the writes in
+// flight.
+type Quota struct {
+	// MaxBytes is the most value bytes, counting every version and tombstone.
+	MaxBytes int64
+	// MaxRows is the most rows.
+	MaxRows int64
+}
+
+func (q Quota) limited() bool {
+	return q.MaxBytes > 0 || q.MaxRows > 0
+}
+
+func (q Quota) validate(name string) error {
+	if q.MaxBytes < 0 {
+		return fmt.Errorf("max bytes for %s cannot be negative", name)
+	}
+	if q.MaxRows < 0 {
+		return fmt.Errorf("max rows for %s cannot be negative", name)
+	}
+	return nil
+}
+
+// check returns an Exhausted error if adding the bytes and rows of added would take used past
+// the quota.
+func (q Quota) check(name string, used, added usage) error {
+	if q.MaxBytes > 0 && used.bytes+added.bytes > q.MaxBytes {
+		return litetable.NewError(litetable.ErrorCodeExhausted,
+			"%s quota of %d bytes exceeded: %d bytes used", name, q.MaxBytes, used.bytes)
+	}
+	if q.MaxRows > 0 && added.rows > 0 && used.rows+added.rows > q.MaxRows {
+		return litetable.NewError(litetable.ErrorCodeExhausted,
+			"%s quota of %d rows exceeded", name, q.MaxRows)
+	}
+	return nil
+}
+
+// usage is the space a table or family takes in one shard. Writes add to it as they go;
+// deletes and garbage collection are only accounted for when the usage is recounted.
+type usage struct {
+	bytes int64
+	rows  int64
+}
+
+func (u usage) add(o usage) usage {
+	return usage{bytes: u.bytes + o.bytes, rows: u.rows + o.rows}
+}
+
+// writeUsage is the usage a write of bytes adds, counting a row when it is new.
+func writeUsage(bytes int64, newRow bool) usage {
+	u := usage{bytes: bytes}
+	if newRow {
+		u.rows = 1
+	}
+	return u
+}
+
+func valueBytes(values []litetable.TimestampedValue) int64 {
+	var n int64
+	for _, v := range values {
+		n += int64(len(v.Value))
+	}
+	return n
+}
+
+// addUsage records a write of bytes to a family of a row. The caller holds the shard lock.
+func (s *shard) addUsage(family string, bytes int64, newRow, newFamily bool) {
+	if s.familyUsage == nil {
+		s.familyUsage = make(map[string]usage)
+	}
+	s.usage = s.usage.add(writeUsage(bytes, newRow))
+	s.familyUsage[family] = s.familyUsage[family].add(writeUsage(bytes, newFamily))
+}
+
+// recountUsage recomputes the usage of every shard from its rows, crediting the space freed by
+// deletes and garbage collection since the last count.
+func (m *Manager) recountUsage() {
+	for _, s := range m.shardMap {
+		var total usage
+		families := make(map[string]usage)
+
+		s.mutex.Lock()
+		for _, r := range s.data {
+			total.rows++
+			for _, f := range r.families {
+				used := usage{rows: 1}
+				for _, values := range f.qualifiers {
+					used.bytes += valueBytes(values)
+				}
+				families[f.name] = families[f.name].add(used)
+				total.bytes += used.bytes
+			}
+		}
+		s.usage, s.familyUsage = total, families
+		s.mutex.Unlock()
+	}
+}
+
+// usageOf sums the usage of the table and of a family over every shard.
+func (m *Manager) usageOf(family string) (usage, usage) {
+	var table, fam usage
+	for _, s := range m.shardMap {
+		s.mutex.RLock()
+		table = table.add(s.usage)
+		fam = fam.add(s.familyUsage[family])
+		s.mutex.RUnlock()
+	}
+	return table, fam
+}
+
+// Usage returns the space the table and each of its families take, with their quotas and the
+// stats of the families from the last run of the stats job.
+func (m *Manager) Usage() litetable.TableUsage {
+	var table usage
+	families := make(map[string]usage)
+	for _, family := range m.GetFamilies() {
+		families[family] = usage{}
+	}
+	for _, s := range m.shardMap {
+		s.mutex.RLock()
+		table = table.add(s.usage)
+		for family, used := range s.familyUsage {
+			families[family] = families[family].add(used)
+		}
+		s.mutex.RUnlock()
+	}
+
+	result := litetable.TableUsage{
+		Usage:    m.quota.usage(table),
+		Families: make(map[string]litetable.Usage, len(families)),
+	}
+	for family, used := range families {
+		result.Families[family] = m.familyPolicies[family].Quota.usage(used)
+	}
+	if stats := m.stats.Load(); stats != nil {
+		result.Stats, result.StatsAt = stats.families, stats.at
+	}
+	return result
+}
+
+func (q Quota) usage(used usage) litetable.Usage {
+	return litetable.Usage{
+		Bytes:    used.bytes,
+		Rows:     used.rows,
+		MaxBytes: q.MaxBytes,
+		MaxRows:  q.MaxRows,
+	}
+}
@@ -0,0 +1,120 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManager_Apply_quota(t *testing.T) {
+	write := func(m *Manager, key, family, value string) error {
+		return m.Apply(key, family, []string{"name"}, [][]byte{[]byte(value)},
+			time.Now().UnixNano(), 0)
+	}
+
+	tests := map[string]struct {
+		quota    Quota
+		policies map[string]FamilyPolicy
+		// the first write always fits, the second is checked against the quota
+		key, family, value string
+		wantErr            string
+	}{
+		"table bytes": {
+			quota:   Quota{MaxBytes: 6},
+			key:     "champ:1",
+			family:  "main",
+			value:   "Ahri",
+			wantErr: "table quota of 6 bytes exceeded: 4 bytes used",
+		},
+		"table rows": {
+			quota:   Quota{MaxRows: 1},
+			key:     "champ:2",
+			family:  "main",
+			value:   "Zed",
+			wantErr: "table quota of 1 rows exceeded",
+		},
+		"existing row within the table row quota": {
+			quota:  Quota{MaxRows: 1},
+			key:    "champ:1",
+			family: "main",
+			value:  "Zed",
+		},
+		"family bytes": {
+			policies: map[string]FamilyPolicy{"main": {Quota: Quota{MaxBytes: 5}}},
+			key:      "champ:2",
+			family:   "main",
+			value:    "Zed",
+			wantErr:  "family main quota of 5 bytes exceeded: 4 bytes used",
+		},
+		"family rows": {
+			policies: map[string]FamilyPolicy{"main": {Quota: Quota{MaxRows: 1}}},
+			key:      "champ:2",
+			family:   "main",
+			value:    "Zed",
+			wantErr:  "family main quota of 1 rows exceeded",
+		},
+		"other family is not limited": {
+			policies: map[string]FamilyPolicy{"main": {Quota: Quota{MaxRows: 1}}},
+			key:      "champ:2",
+			family:   "other",
+			value:    "Zed",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			m := newTestManager(t)
+			req.NoError(m.UpdateFamilies([]string{"other"}))
+			m.quota = tc.quota
+			m.familyPolicies = tc.policies
+
+			req.NoError(write(m, "champ:1", "main", "Ahri"))
+			err := write(m, tc.key, tc.family, tc.value)
+			if tc.wantErr == "" {
+				req.NoError(err)
+				return
+			}
+			req.ErrorIs(err, litetable.ErrExhausted)
+			req.EqualError(err, tc.wantErr)
+		})
+	}
+}
+
+func TestManager_Usage(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	req.NoError(m.UpdateFamilies([]string{"other"}))
+	m.quota = Quota{MaxBytes: 100}
+	m.familyPolicies = map[string]FamilyPolicy{"main": {Quota: Quota{MaxRows: 10}}}
+
+	now := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "main", []string{"name", "title"},
+		[][]byte{[]byte("Ahri"), []byte("Fox")}, now, 0))
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")}, now, 0))
+	req.NoError(m.Apply("champ:2", "main", []string{"name"}, [][]byte{[]byte("Zed")}, now, 0))
+
+	want := litetable.TableUsage{
+		Usage: litetable.Usage{Bytes: 14, Rows: 2, MaxBytes: 100},
+		Families: map[string]litetable.Usage{
+			"main":  {Bytes: 14, Rows: 2, MaxRows: 10},
+			"other": {},
+		},
+	}
+	req.Equal(want, m.Usage())
+
+	// deletes are credited when the usage is recounted
+	cells, _ := m.DeleteRowFamily("champ:2", "main")
+	req.Equal(1, cells)
+	req.Equal(want, m.Usage())
+
+	m.recountUsage()
+	req.Equal(litetable.TableUsage{
+		Usage: litetable.Usage{Bytes: 11, Rows: 2, MaxBytes: 100},
+		Families: map[string]litetable.Usage{
+			"main":  {Bytes: 11, Rows: 1, MaxRows: 10},
+			"other": {},
+		},
+	}, m.Usage())
+}
@@ -2,10 +2,12 @@ package shard_storage
 
 import (
 	"github.com/litetable/litetable-db/internal/litetable"
-	"github.com/rs/zerolog/log"
-	"regexp"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // GetRowByFamily returns the data attached to a row key and family: this would be a
@@ -27,12 +29,12 @@ func (m *Manager) GetRowByFamily(key, family string) (*litetable.Data, bool) {
 	}
 
 	// Check if the family exists
-	fam, exists := row[family]
+	fam, exists := row.family(family)
 	if !exists {
 		return nil, false
 	}
 
-	log.Debug().Msgf("found row %s in shard %d", key, shardKey)
+	m.logger.Debug().Msgf("found row %s in shard %d", key, shardKey)
 
 	// Create result structure
 	result := make(litetable.Data)
@@ -40,7 +42,7 @@ func (m *Manager) GetRowByFamily(key, family string) (*litetable.Data, bool) {
 	result[key][family] = make(litetable.VersionedQualifier)
 
 	// Copy qualifier data to result
-	for qualifier, values := range fam {
+	for qualifier, values := range m.readQualifiers(fam) {
 		result[key][family][qualifier] = values
 	}
 
@@ -48,12 +50,16 @@ func (m *Manager) GetRowByFamily(key, family string) (*litetable.Data, bool) {
 }
 
 // FilterRowsByPrefix has to query all shards to find all rows that match the data. Prefix queries
-// are expensive in that they require locking all shards and scanning all data.
-func (m *Manager) FilterRowsByPrefix(prefix string) (*litetable.Data, bool) {
+// are expensive in that they require locking all shards and scanning all data, so every row key
+// of the table counts as examined. A page of rows only collects the matching keys while the
+// shards are scanned, and copies no more rows than fill it.
+func (m *Manager) FilterRowsByPrefix(prefix string, page litetable.ScanPage) (*litetable.Data,
+	litetable.ScanStats, bool) {
 	result := make(litetable.Data)
+	var keys []string
 	var mutex sync.Mutex
 	var wg sync.WaitGroup
-	matchFound := false
+	var examined atomic.Int64
 
 	wg.Add(len(m.shardMap))
 
@@ -63,45 +69,57 @@ func (m *Manager) FilterRowsByPrefix(prefix string) (*litetable.Data, bool) {
 
 			// Local results for this shard
 			localMatches := make(litetable.Data)
-			localFound := false
+			var localKeys []string
 
 			shard.RLock()
+			examined.Add(int64(len(shard.data)))
 			for rowKey, rowData := range shard.data {
-				if strings.HasPrefix(rowKey, prefix) {
-					localMatches[rowKey] = rowData
-					localFound = true
+				if !strings.HasPrefix(rowKey, prefix) || page.Skips(rowKey) {
+					continue
+				}
+				if page.MaxBytes > 0 {
+					localKeys = append(localKeys, rowKey)
+				} else {
+					localMatches[rowKey] = m.readColumns(rowData)
 				}
 			}
 			shard.RUnlock()
 
 			// If we found matches, merge them into the result under lock
-			if localFound {
-				mutex.Lock()
-				for k, v := range localMatches {
-					result[k] = v
-				}
-				matchFound = matchFound || localFound
-				mutex.Unlock()
-			}
+			mutex.Lock()
+			maps.Copy(result, localMatches)
+			keys = append(keys, localKeys...)
+			mutex.Unlock()
 		}(s)
 	}
 
 	wg.Wait()
-	return &result, matchFound
+	stats := litetable.ScanStats{Examined: int(examined.Load())}
+	if page.MaxBytes > 0 {
+		result, stats.More = m.readPage(keys, page.MaxBytes)
+	}
+	return &result, stats, len(result) > 0
 }
 
-func (m *Manager) FilterRowsByRegex(regex string) (*litetable.Data, bool) {
+// FilterRowsByRegex scans all shards for the rows with a key matching regex. The pattern is
+// refused when it compiles to too large a program, and the scan is abandoned with an
+// EXHAUSTED error once any shard takes longer than the scan budget, so a pathological pattern
+// cannot hold the read locks of every shard. A page of rows is read as FilterRowsByPrefix reads
+// it.
+func (m *Manager) FilterRowsByRegex(regex string, page litetable.ScanPage) (*litetable.Data,
+	litetable.ScanStats, bool, error) {
+	reg, err := compileRowKeyRegex(regex, m.maxRegexProgram)
+	if err != nil {
+		return nil, litetable.ScanStats{}, false, err
+	}
+
 	result := make(litetable.Data)
+	var keys []string
 	var mutex sync.Mutex
 	var wg sync.WaitGroup
-	matchFound := false
-
-	// Compile regex once, outside the goroutines
-	reg, err := regexp.Compile(regex)
-	if err != nil {
-		// If regex is invalid, return empty result
-		return &result, false
-	}
+	// exceeded stops the other shards once one shard is over the budget
+	var exceeded atomic.Bool
+	var examined atomic.Int64
 
 	wg.Add(len(m.shardMap))
 
@@ -111,29 +129,96 @@ func (m *Manager) FilterRowsByRegex(regex string) (*litetable.Data, bool) {
 
 			// Local results for this shard
 			localMatches := make(litetable.Data)
-			localFound := false
+			var localKeys []string
 
 			shard.RLock()
+			deadline := time.Now().Add(m.regexScanBudget)
+			checked := 0
 			for rowKey, rowData := range shard.data {
-				if reg.MatchString(rowKey) {
-					localMatches[rowKey] = rowData
-					localFound = true
+				if checked++; checked%regexCheckInterval == 0 &&
+					(exceeded.Load() || time.Now().After(deadline)) {
+					exceeded.Store(true)
+					break
+				}
+				if page.Skips(rowKey) || !reg.MatchString(rowKey) {
+					continue
+				}
+				if page.MaxBytes > 0 {
+					localKeys = append(localKeys, rowKey)
+				} else {
+					localMatches[rowKey] = m.readColumns(rowData)
 				}
 			}
 			shard.RUnlock()
+			examined.Add(int64(checked))
 
 			// If we found matches, merge them into the result under lock
-			if localFound {
-				mutex.Lock()
-				for k, v := range localMatches {
-					result[k] = v
-				}
-				matchFound = matchFound || localFound
-				mutex.Unlock()
-			}
+			mutex.Lock()
+			maps.Copy(result, localMatches)
+			keys = append(keys, localKeys...)
+			mutex.Unlock()
 		}(s)
 	}
 
 	wg.Wait()
-	return &result, matchFound
+	stats := litetable.ScanStats{Examined: int(examined.Load())}
+	if exceeded.Load() {
+		regexScansAbandoned.Inc()
+		return nil, stats, false, errRegexBudget(m.regexScanBudget)
+	}
+	if page.MaxBytes > 0 {
+		result, stats.More = m.readPage(keys, page.MaxBytes)
+	}
+	return &result, stats, len(result) > 0, nil
+}
+
+// readPage copies the rows of keys in key order until they pass maxBytes. Each row is read under
+// its own shard lock once the scan is done, so a row removed since its key was collected is
+// skipped, and the scan's readAt hides the values written since.
+func (m *Manager) readPage(keys []string, maxBytes int) (litetable.Data, bool) {
+	return pageRows(keys, maxBytes, func(key string) (map[string]litetable.VersionedQualifier,
+		bool) {
+		s := m.shardMap[m.getShardIndex(key)]
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		r, exists := s.data[key]
+		if !exists {
+			return nil, false
+		}
+		return m.readColumns(r), true
+	})
+}
+
+// pageRows reads the rows of keys in key order until they pass maxBytes, and reports whether any
+// were left unread. The first row is always read.
+func pageRows(keys []string, maxBytes int,
+	read func(key string) (map[string]litetable.VersionedQualifier, bool)) (litetable.Data,
+	bool) {
+	slices.Sort(keys)
+	rows := make(litetable.Data)
+	size := 0
+	for _, key := range keys {
+		if size > maxBytes {
+			return rows, true
+		}
+		families, exists := read(key)
+		if !exists {
+			continue
+		}
+		rows[key] = families
+		size += rowBytes(key, families)
+	}
+	return rows, false
+}
+
+// rowBytes is the size of the key, the family and qualifier names and the values of a row.
+func rowBytes(key string, families map[string]litetable.VersionedQualifier) int {
+	n := len(key)
+	for family, qualifiers := range families {
+		n += len(family)
+		for qualifier, values := range qualifiers {
+			n += len(qualifier) + int(valueBytes(values))
+		}
+	}
+	return n
 }
@@ -0,0 +1,75 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_FilterRows_page(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	m.readViewInterval = time.Minute
+
+	now := time.Now().UnixNano()
+	for i := range 10 {
+		req.NoError(m.Apply(fmt.Sprintf("champ:%d", i), "main", []string{"name"},
+			[][]byte{[]byte(strings.Repeat("a", 100))}, now, 0))
+	}
+	req.NoError(m.Apply("zed:1", "main", []string{"name"}, [][]byte{[]byte("Zed")}, now, 0))
+	m.refreshViews()
+	view, _ := m.ReadView()
+
+	// each row is 115 bytes of key, names and value, so a page of 250 bytes holds 3 rows
+	scans := map[string]func(page litetable.ScanPage) (*litetable.Data, litetable.ScanStats){
+		"prefix": func(page litetable.ScanPage) (*litetable.Data, litetable.ScanStats) {
+			data, stats, _ := m.FilterRowsByPrefix("champ:", page)
+			return data, stats
+		},
+		"regex": func(page litetable.ScanPage) (*litetable.Data, litetable.ScanStats) {
+			data, stats, _, err := m.FilterRowsByRegex("^champ:", page)
+			req.NoError(err)
+			return data, stats
+		},
+		"view prefix": func(page litetable.ScanPage) (*litetable.Data, litetable.ScanStats) {
+			data, stats, _ := view.FilterRowsByPrefix("champ:", page)
+			return data, stats
+		},
+		"view regex": func(page litetable.ScanPage) (*litetable.Data, litetable.ScanStats) {
+			data, stats, _, err := view.FilterRowsByRegex("^champ:", page)
+			req.NoError(err)
+			return data, stats
+		},
+	}
+
+	for name, scan := range scans {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			var keys []string
+			page := litetable.ScanPage{MaxBytes: 250}
+			for {
+				data, stats := scan(page)
+				req.Equal(11, stats.Examined)
+				req.LessOrEqual(len(*data), 3)
+				rows := slices.Sorted(maps.Keys(*data))
+				keys = append(keys, rows...)
+				if !stats.More {
+					break
+				}
+				page.After = rows[len(rows)-1]
+			}
+			req.Equal([]string{"champ:0", "champ:1", "champ:2", "champ:3", "champ:4", "champ:5",
+				"champ:6", "champ:7", "champ:8", "champ:9"}, keys)
+
+			// a row larger than the page is read on its own
+			data, stats := scan(litetable.ScanPage{After: "champ:4", MaxBytes: 1})
+			req.Equal([]string{"champ:5"}, slices.Collect(maps.Keys(*data)))
+			req.True(stats.More)
+		})
+	}
+}
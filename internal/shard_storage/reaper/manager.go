@@ -3,32 +3,68 @@ package reaper
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/litetable/litetable-db/internal/litetable"
-	"github.com/rs/zerolog/log"
+	"github.com/litetable/litetable-db/internal/logging"
+	"github.com/rs/zerolog"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
+	// reaperFile is the GC log written before logs were kept per shard. It is replayed into the
+	// shard logs on Start and removed.
 	reaperFile = ".reaper.gc.log"
+	// shardFilePattern names the GC log of each shard.
+	shardFilePattern = ".reaper.gc.%d.log"
+
+	// defaultPolicyScanBudget is the number of rows checked against the family policies on
+	// every GC tick.
+	defaultPolicyScanBudget = 1000
+
+	// defaultQueueSize is the number of reap entries buffered in memory for each shard before
+	// Reap starts writing straight to the GC log.
+	defaultQueueSize = 10000
 )
 
 type storage interface {
 	GetRowByFamily(key, family string) (*litetable.Data, bool)
-	DeleteRowFamily(rowKey, family string) bool
-	DeleteExpiredTombstones(rowKey, family string, qualifiers []string, timestamp int64) bool
+	DeleteRowFamily(rowKey, family string) (int, int64)
+	DeleteExpiredTombstones(rowKey, family string, qualifiers []string,
+		timestamp int64) (int, int64, bool)
+	DeleteExpiredValues(rowKey, family string, qualifiers []string) (int, int64)
 	MarkRowChanged(family, rowKey string)
+	ReclaimByPolicy(budget int) (int, int64)
 }
 
+// Reaper collects expired tombstones and values with one worker per shard, so cleanup of a hot
+// shard never waits on the queue, lock or GC log of another. Family policies are enforced by a
+// separate loop, since they walk the shards on their own.
 type Reaper struct {
-	filePath  string
-	collector chan ReapParams
+	dir     string
+	workers []*worker
+	// shardOf returns the shard, and so the worker, of a row key
+	shardOf func(rowKey string) int
 
 	storageManager storage
-	mutex          sync.Mutex
-	reapInterval   time.Duration
+	// reapInterval is the time between collections in nanoseconds. SetInterval changes it at
+	// runtime and signals intervalChanged so the loops reset their tickers.
+	reapInterval    atomic.Int64
+	intervalChanged chan struct{}
+
+	// policyScanBudget bounds the work done enforcing family policies on each tick
+	policyScanBudget int
+
+	// queueMutex guards sends on the worker queues against Stop, so no entry is queued after the
+	// final drain.
+	queueMutex sync.RWMutex
+	stopped    bool
+	loops      sync.WaitGroup
+
+	logger zerolog.Logger
 
 	procCtx context.Context
 	cancel  context.CancelFunc
@@ -38,6 +74,18 @@ type Config struct {
 	Path       string
 	Storage    storage
 	GCInterval int
+	// PolicyScanBudget is the number of rows checked against the family retention policies on
+	// every GC tick. Defaults to 1000.
+	PolicyScanBudget int
+	// QueueSize is the number of reap entries buffered in memory for each shard. When a queue is
+	// full, entries are written directly to the GC log instead of blocking the caller. Defaults
+	// to 10000.
+	QueueSize int
+	// Shards is the number of shards of the storage, each collected by its own worker with its
+	// own GC log. Defaults to 1.
+	Shards int
+	// ShardOf returns the shard of a row key, in [0, Shards). Required with more than one shard.
+	ShardOf func(rowKey string) int
 }
 
 func (c *Config) validate() error {
@@ -51,6 +99,18 @@ func (c *Config) validate() error {
 	if c.GCInterval <= 0 {
 		errGrp = append(errGrp, errors.New("GCInterval must be greater than 0"))
 	}
+	if c.PolicyScanBudget < 0 {
+		errGrp = append(errGrp, errors.New("PolicyScanBudget cannot be negative"))
+	}
+	if c.QueueSize < 0 {
+		errGrp = append(errGrp, errors.New("QueueSize cannot be negative"))
+	}
+	if c.Shards < 0 {
+		errGrp = append(errGrp, errors.New("Shards cannot be negative"))
+	}
+	if c.Shards > 1 && c.ShardOf == nil {
+		errGrp = append(errGrp, errors.New("ShardOf is required with more than one shard"))
+	}
 	return errors.Join(errGrp...)
 }
 
@@ -60,64 +120,121 @@ func New(cfg *Config) (*Reaper, error) {
 		return nil, err
 	}
 
-	filePath := filepath.Join(cfg.Path, reaperFile)
+	budget := cfg.PolicyScanBudget
+	if budget == 0 {
+		budget = defaultPolicyScanBudget
+	}
+
+	queueSize := cfg.QueueSize
+	if queueSize == 0 {
+		queueSize = defaultQueueSize
+	}
+
+	shards := max(cfg.Shards, 1)
+	shardOf := cfg.ShardOf
+	if shards == 1 {
+		shardOf = func(string) int { return 0 }
+	}
+
 	// create a cancel context to ensure all garbage collection processes are shut down gracefully
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Reaper{
-		filePath:       filePath,
-		collector:      make(chan ReapParams, 10000),
-		storageManager: cfg.Storage,
-		reapInterval:   time.Duration(cfg.GCInterval) * time.Second,
-		mutex:          sync.Mutex{},
-		procCtx:        ctx,
-		cancel:         cancel,
-	}, nil
+	r := &Reaper{
+		dir:             cfg.Path,
+		shardOf:         shardOf,
+		storageManager:  cfg.Storage,
+		intervalChanged: make(chan struct{}, 1),
+		procCtx:         ctx,
+		cancel:          cancel,
+
+		policyScanBudget: budget,
+		logger:           logging.For("reaper"),
+	}
+	r.reapInterval.Store(int64(time.Duration(cfg.GCInterval) * time.Second))
+
+	r.workers = make([]*worker, shards)
+	for i := range r.workers {
+		r.workers[i] = &worker{
+			shard:           i,
+			filePath:        filepath.Join(cfg.Path, fmt.Sprintf(shardFilePattern, i)),
+			collector:       make(chan ReapParams, queueSize),
+			intervalChanged: make(chan struct{}, 1),
+			storageManager:  cfg.Storage,
+			logger:          r.logger.With().Int("shard", i).Logger(),
+		}
+	}
+
+	return r, nil
 }
 
 func (r *Reaper) Start() error {
-	// Verify the log file exists
-	if err := r.verifyLogFile(); err != nil {
+	// Re-register anything left in the GC logs by a previous run
+	if err := r.replay(); err != nil {
 		return err
 	}
 
-	// Start the reaper
+	// Start a worker for every shard
+	for _, w := range r.workers {
+		r.loops.Add(1)
+		go func() {
+			defer r.loops.Done()
+			w.run(r.procCtx, &r.reapInterval)
+		}()
+	}
+
+	// Start the family policy loop
+	r.loops.Add(1)
 	go func() {
-		ticker := time.NewTicker(r.reapInterval)
+		defer r.loops.Done()
+		ticker := time.NewTicker(time.Duration(r.reapInterval.Load()))
 		defer ticker.Stop()
 		for {
 			select {
 			case <-r.procCtx.Done():
 				return
-			case p := <-r.collector:
-				err := r.write(&p)
-				if err != nil {
-					log.Error().Err(err).Msg("failed to write GCParams to log file")
-				}
+			case <-r.intervalChanged:
+				ticker.Reset(time.Duration(r.reapInterval.Load()))
 			case <-ticker.C:
-				// Run the garbage collector
-				r.garbageCollector()
+				r.enforcePolicies()
 			}
 		}
 	}()
 	return nil
 }
 
+// Stop shuts down the worker loops and writes any queued entries to the GC logs. Reap remains
+// safe to call after Stop: late entries are written straight to the GC logs and replayed on the
+// next Start.
 func (r *Reaper) Stop() error {
+	// stop queueing; the channels are never closed because producers may still call Reap
+	r.queueMutex.Lock()
+	r.stopped = true
+	r.queueMutex.Unlock()
+
 	// kill the process context
 	if r.cancel != nil {
 		r.cancel()
 	}
-	
-	// Stop the reaper collection
-	close(r.collector)
 
-	// kill the process context
-	if r.cancel != nil {
-		r.cancel()
+	// Wait for the loops to finish
+	r.loops.Wait()
+
+	return r.Drain()
+}
+
+// SetInterval changes the time between garbage collections, in seconds, without restarting
+// the reaper.
+func (r *Reaper) SetInterval(seconds int) error {
+	if seconds <= 0 {
+		return errors.New("GCInterval must be greater than 0")
 	}
 
-	// Wait for the reaper to finish
+	r.reapInterval.Store(int64(time.Duration(seconds) * time.Second))
+	// a reset that is already pending will pick up the new interval
+	signal(r.intervalChanged)
+	for _, w := range r.workers {
+		signal(w.intervalChanged)
+	}
 	return nil
 }
 
@@ -125,23 +242,83 @@ func (r *Reaper) Name() string {
 	return "Reaper"
 }
 
-// verifyLogFile checks if the log file exists, and creates it if it doesn't.
-func (r *Reaper) verifyLogFile() error {
-	_, err := os.Stat(r.filePath)
+// worker returns the worker of the shard of a row key.
+func (r *Reaper) worker(rowKey string) *worker {
+	return r.workers[r.shardOf(rowKey)]
+}
+
+// replay loads the GC logs left by a previous run into the workers. Entries in the logs were
+// accepted but never collected, most likely because the process stopped (or crashed) before
+// they expired. Every entry is sent to the worker of its shard, so logs written before logs were
+// kept per shard, or with another number of shards, are moved into the current logs and removed.
+func (r *Reaper) replay() error {
+	files, err := filepath.Glob(filepath.Join(r.dir, ".reaper.gc.*.log"))
 	if err != nil {
-		if !os.IsNotExist(err) {
-			return err // Return error if it's something other than "file not exist"
+		return fmt.Errorf("failed to find GC logs: %w", err)
+	}
+	files = append([]string{filepath.Join(r.dir, reaperFile)}, files...)
+
+	// hold every worker, so no entry is persisted between reading the logs and rewriting them
+	for _, w := range r.workers {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+	}
+
+	entries := make([][]ReapParams, len(r.workers))
+	var stale []string
+	var total int
+	for _, file := range files {
+		logged, err := readGCLog(file, r.logger)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to replay GC log: %w", err)
+		}
+		for _, p := range logged {
+			shard := r.shardOf(p.RowKey)
+			entries[shard] = append(entries[shard], p)
+		}
+		total += len(logged)
+		if !r.owns(file) {
+			stale = append(stale, file)
 		}
+	}
 
-		// Create file if it doesn't exist
-		file, fileErr := os.Create(r.filePath)
-		if fileErr != nil {
-			return fileErr
+	// the logs hold every entry persisted so far, including those persisted before Start
+	for i, w := range r.workers {
+		w.pending = entries[i]
+		if err = w.rewriteGCLog(w.pending); err != nil {
+			return fmt.Errorf("failed to replay GC log: %w", err)
+		}
+	}
+	// the entries of the stale logs are in the shard logs now
+	for _, file := range stale {
+		if err = os.Remove(file); err != nil {
+			return fmt.Errorf("failed to remove replayed GC log: %w", err)
 		}
-		defer func(file *os.File) {
-			_ = file.Close()
-		}(file)
-		return nil // Successfully created the file
 	}
-	return nil // File already exists
+
+	if total > 0 {
+		r.logger.Info().Int("entries", total).Msg("replayed pending reap entries from GC logs")
+	}
+	return nil
+}
+
+// owns reports whether a GC log belongs to one of the workers.
+func (r *Reaper) owns(file string) bool {
+	for _, w := range r.workers {
+		if w.filePath == file {
+			return true
+		}
+	}
+	return false
+}
+
+// signal sends on a channel that holds a single pending notification, unless one is pending.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
 }
@@ -1,14 +1,54 @@
 package reaper
 
 import (
-	"bufio"
-	"encoding/json"
-	"fmt"
-	"github.com/rs/zerolog/log"
-	"os"
-	"time"
+	"errors"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"sync/atomic"
 )
 
+var (
+	policyReclaimedCells = metrics.NewCounter("litetable_reaper_policy_reclaimed_cells_total",
+		"Cells removed by family maxAge/maxVersions policies.")
+	policyReclaimedBytes = metrics.NewCounter("litetable_reaper_policy_reclaimed_bytes_total",
+		"Estimated bytes reclaimed by family maxAge/maxVersions policies.")
+	reapOverflow = metrics.NewCounter("litetable_reaper_queue_overflow_total",
+		"Reap entries written directly to the GC log because the queue was full or stopped.")
+	reapQueueDepth = metrics.NewGauge("litetable_reaper_queue_depth",
+		"Reap entries in the queues of every table waiting to be written to the GC logs.")
+
+	gcPasses = metrics.NewCounter("litetable_reaper_passes_total",
+		"Garbage collection passes over the GC log.")
+	gcPassSeconds = metrics.NewCounter("litetable_reaper_pass_duration_seconds_total",
+		"Time spent in garbage collection passes.")
+	gcLastPassSeconds = metrics.NewGauge("litetable_reaper_last_pass_duration_seconds",
+		"Duration of the latest garbage collection pass of any table.")
+	gcEntriesProcessed = metrics.NewCounter("litetable_reaper_entries_processed_total",
+		"Reap entries checked by garbage collection passes.")
+	gcReclaimedCells = metrics.NewCounter("litetable_reaper_reclaimed_cells_total",
+		"Cells removed by collecting expired tombstones and values written with a TTL.")
+	gcReclaimedBytes = metrics.NewCounter("litetable_reaper_reclaimed_bytes_total",
+		"Estimated bytes freed by collecting expired tombstones and values written with a TTL.")
+	gcLogEntries = metrics.NewGauge("litetable_reaper_log_entries",
+		"Reap entries in the GC logs of every table.")
+	gcLogBytes = metrics.NewGauge("litetable_reaper_log_bytes",
+		"Size of the GC logs of every table.")
+)
+
+// queued is the number of reap entries in the queues of every table.
+var queued atomic.Int64
+
+// QueueDepth returns the number of reap entries in the queues of every table that are waiting
+// to be written to the GC logs.
+func QueueDepth() int {
+	return int(queued.Load())
+}
+
+// countQueued adds n entries to the queue depth.
+func countQueued(n int) {
+	queued.Add(int64(n))
+	reapQueueDepth.Add(float64(n))
+}
+
 // ReapParams are the required parameters for the Reapers Garbage Collection process.
 type ReapParams struct {
 	RowKey     string   `json:"rowKey"`
@@ -16,166 +56,75 @@ type ReapParams struct {
 	Qualifiers []string `json:"qualifiers"`
 	Timestamp  int64    `json:"timestamp"`
 	ExpiresAt  int64    `json:"expiresAt"`
+	// Expiry marks values written with a TTL, rather than a tombstone. Only the expired values
+	// are collected, so older versions written without a TTL are kept.
+	Expiry bool `json:"expiry,omitempty"`
 }
 
-// Reap will take in GCParams and throw it into the Garbage Collector.
+// Reap will take in GCParams and throw it into the queue of the worker of its shard. Reap never
+// blocks on a full queue: when the queue is full, or the reaper is stopped, the entry is written
+// directly to the GC log of the shard.
 func (r *Reaper) Reap(p *ReapParams) {
-	r.collector <- *p
-}
-
-// write will append the GCParams to the GC log file.
-func (r *Reaper) write(p *ReapParams) error {
-	// open the file
-	file, err := os.OpenFile(r.filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0640)
-	if err != nil {
-		return err
-	}
-	defer func(file *os.File) {
-		closeErr := file.Close()
-		if closeErr != nil {
-			log.Error().Err(closeErr).Str("file", r.filePath).Msg("failed to close file")
+	w := r.worker(p.RowKey)
+	r.queueMutex.RLock()
+	if !r.stopped {
+		// the entry is counted before it is queued, so taking it never counts below zero
+		countQueued(1)
+		select {
+		case w.collector <- *p:
+			r.queueMutex.RUnlock()
+			return
+		default:
+			countQueued(-1)
 		}
-	}(file)
-
-	data, err := json.Marshal(p)
-	if err != nil {
-		log.Error().Err(err).Msg("failed to marshal GCParams")
-		return err
 	}
+	r.queueMutex.RUnlock()
 
-	_, err = file.WriteString(string(data) + "\n")
-	if err != nil {
-		log.Error().Err(err).Msg("failed to write GCParams to log file")
-		return err
+	reapOverflow.Inc()
+	if err := w.persist(*p); err != nil {
+		w.logger.Error().Err(err).Msg("failed to write overflowed GCParams to log file")
 	}
-
-	return nil
 }
 
-// garbageCollector runs the garbage collection over tombstones.
-func (r *Reaper) garbageCollector() {
-	// Open the file
-	file, err := os.Open(r.filePath)
-	if err != nil {
-		log.Error().Err(err).Msg("Error opening GC log file")
-		return
-	}
-	defer file.Close()
-
-	// Current time to check expiration
-	now := time.Now()
-	nowUnix := now.UnixNano()
-
-	// Read the file line by line
-	var entries []ReapParams
-	var activeEntries []ReapParams
-	var processed int
-	var removed int
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) == 0 {
-			continue
-		}
-
-		var params ReapParams
-		if err = json.Unmarshal([]byte(line), &params); err != nil {
-			log.Error().Err(err).Msg("Error unmarshalling GC log entry")
-			continue
-		}
-		entries = append(entries, params)
+// Drain writes every queued reap entry to the GC logs. It is safe to call while the reaper is
+// running, and is used by shard storage to make sure every tombstone in its final snapshot has a
+// durable reap entry.
+func (r *Reaper) Drain() error {
+	var errs []error
+	for _, w := range r.workers {
+		errs = append(errs, w.drain())
 	}
-
-	if err = scanner.Err(); err != nil {
-		log.Error().Err(err).Msg("Error reading GC log file")
-		return
-	}
-
-	// Process each entry
-	for _, params := range entries {
-		processed++
-
-		// Check if it's expired
-		if nowUnix > params.ExpiresAt {
-			// if there are no qualifiers, we should delete the entire family
-			if len(params.Qualifiers) == 0 {
-				log.Debug().Msgf("Deleting entire family %s for row %s", params.Family, params.RowKey)
-				// Delete the entire family
-				if deleted := r.storageManager.DeleteRowFamily(params.RowKey,
-					params.Family); deleted {
-					removed++
-					// if deleted, we need to report this change to the snapshot server
-					r.storageManager.MarkRowChanged(params.Family, params.RowKey)
-				} else {
-					log.Debug().Msgf("Failed to delete family %s for row %s", params.Family, params.RowKey)
-				}
-				continue
-			}
-			
-			// Process the tombstone for this entry
-			if deleted := r.didDeleteTombstone(&params); deleted {
-				removed++
-
-				// if deleted, we need to report this change to the snapshot server
-				r.storageManager.MarkRowChanged(params.Family, params.RowKey)
-			} else {
-				// the entry is still valid and should remain in the file
-				activeEntries = append(activeEntries, params)
-			}
-		} else {
-			// Keep the entry for next time
-			activeEntries = append(activeEntries, params)
-		}
-	}
-
-	// lock to prevent concurrent writes to file
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	// Rewrite the file with only active entries
-	if err = r.rewriteGCLog(activeEntries); err != nil {
-		log.Error().Err(err).Msg("Error rewriting GC log file")
-	}
-
-	log.
-		Debug().
-		Str("duration", time.Since(now).String()).
-		Msgf("Garbage collection complete: processed %d entries, "+
-			"removed %d",
-			processed,
-			removed)
+	return errors.Join(errs...)
 }
 
-func (r *Reaper) didDeleteTombstone(params *ReapParams) bool {
-	return r.storageManager.DeleteExpiredTombstones(params.RowKey, params.Family, params.Qualifiers, params.Timestamp)
-}
+// Pending returns a copy of the reap entries that have not been collected yet, shard by shard.
+// The queues are drained first, so every entry accepted before the call is included.
+func (r *Reaper) Pending() ([]ReapParams, error) {
+	err := r.Drain()
 
-// rewriteGCLog rewrites the GC log file with only active entries.
-func (r *Reaper) rewriteGCLog(entries []ReapParams) error {
-	// Truncate the file (effectively delete all content)
-	file, err := os.OpenFile(r.filePath, os.O_WRONLY|os.O_TRUNC, 0640)
-	if err != nil {
-		return fmt.Errorf("failed to truncate GC log file: %w", err)
+	var pending []ReapParams
+	for _, w := range r.workers {
+		w.mutex.Lock()
+		pending = append(pending, w.pending...)
+		w.mutex.Unlock()
 	}
-	defer file.Close()
-
-	// Write the active entries back to the file
-	for _, entry := range entries {
-		data, err := json.Marshal(entry)
-		if err != nil {
-			return fmt.Errorf("failed to marshal entry: %w", err)
-		}
+	return pending, err
+}
 
-		if _, err := file.WriteString(string(data) + "\n"); err != nil {
-			return fmt.Errorf("failed to write active entry: %w", err)
-		}
+// enforcePolicies reclaims cells that fall outside the family retention policies, and expired
+// values whose reap entries were lost. Work is bounded by the policy scan budget, so a full pass
+// over large shards is spread over several ticks.
+func (r *Reaper) enforcePolicies() {
+	cells, bytes := r.storageManager.ReclaimByPolicy(r.policyScanBudget)
+	if cells == 0 {
+		return
 	}
 
-	// Ensure data is written to disk
-	if err := file.Sync(); err != nil {
-		return fmt.Errorf("failed to sync GC log file: %w", err)
-	}
+	policyReclaimedCells.Add(float64(cells))
+	policyReclaimedBytes.Add(float64(bytes))
 
-	return nil
+	r.logger.Debug().
+		Int("cells", cells).
+		Int64("bytes", bytes).
+		Msg("reclaimed cells by family policy")
 }
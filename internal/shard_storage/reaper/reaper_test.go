@@ -0,0 +1,395 @@
+package reaper
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStorage records the calls the reaper makes against storage.
+type fakeStorage struct {
+	mu        sync.Mutex
+	reaped    []string
+	families  []string
+	expired   []string
+	changed   []string
+	deleteRes bool
+	// cells is the number of cells each delete reports, at 10 bytes a cell
+	cells int
+
+	policyCalls int
+	policyCells int
+}
+
+func (f *fakeStorage) GetRowByFamily(_, _ string) (*litetable.Data, bool) {
+	return nil, false
+}
+
+func (f *fakeStorage) DeleteRowFamily(rowKey, _ string) (int, int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.families = append(f.families, rowKey)
+	return f.cells, int64(f.cells * 10)
+}
+
+func (f *fakeStorage) DeleteExpiredTombstones(rowKey, _ string, _ []string,
+	_ int64) (int, int64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reaped = append(f.reaped, rowKey)
+	return f.cells, int64(f.cells * 10), f.deleteRes
+}
+
+func (f *fakeStorage) DeleteExpiredValues(rowKey, _ string, _ []string) (int, int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.expired = append(f.expired, rowKey)
+	return 0, 0
+}
+
+func (f *fakeStorage) MarkRowChanged(_, rowKey string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.changed = append(f.changed, rowKey)
+}
+
+func (f *fakeStorage) ReclaimByPolicy(_ int) (int, int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.policyCalls++
+	return f.policyCells, int64(f.policyCells * 10)
+}
+
+func newTestReaper(t *testing.T, dir string, s storage) *Reaper {
+	t.Helper()
+	r, err := New(&Config{
+		Path:       dir,
+		Storage:    s,
+		GCInterval: 3600, // never tick during a test
+	})
+	require.NoError(t, err)
+	return r
+}
+
+func TestNew(t *testing.T) {
+	t.Run("invalid config", func(t *testing.T) {
+		got, err := New(&Config{})
+		require.Error(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("shards without ShardOf", func(t *testing.T) {
+		got, err := New(&Config{
+			Path:       t.TempDir(),
+			Storage:    &fakeStorage{},
+			GCInterval: 10,
+			Shards:     2,
+		})
+		require.EqualError(t, err, "ShardOf is required with more than one shard")
+		require.Nil(t, got)
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		got, err := New(&Config{
+			Path:       t.TempDir(),
+			Storage:    &fakeStorage{},
+			GCInterval: 10,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, got)
+	})
+}
+
+func TestReaper_replay(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestReaper(t, dir, &fakeStorage{})
+	w := r.workers[0]
+
+	require.NoError(t, w.write(&ReapParams{RowKey: "row1", Family: "fam", Qualifiers: []string{"q"}}))
+	require.NoError(t, w.write(&ReapParams{RowKey: "row2", Family: "fam"}))
+
+	restarted := newTestReaper(t, dir, &fakeStorage{})
+	require.NoError(t, restarted.Start())
+	defer restarted.cancel()
+
+	require.Len(t, restarted.workers[0].pending, 2)
+	require.Equal(t, "row1", restarted.workers[0].pending[0].RowKey)
+	require.Equal(t, "row2", restarted.workers[0].pending[1].RowKey)
+}
+
+func TestReaper_shards(t *testing.T) {
+	dir := t.TempDir()
+	shardOf := func(rowKey string) int {
+		if strings.HasPrefix(rowKey, "champ") {
+			return 1
+		}
+		return 0
+	}
+	newShardedReaper := func(shards int) *Reaper {
+		r, err := New(&Config{
+			Path:       dir,
+			Storage:    &fakeStorage{},
+			GCInterval: 3600,
+			Shards:     shards,
+			ShardOf:    func(rowKey string) int { return shardOf(rowKey) % shards },
+		})
+		require.NoError(t, err)
+		return r
+	}
+
+	// a GC log written before logs were kept per shard
+	legacy := filepath.Join(dir, reaperFile)
+	require.NoError(t, os.WriteFile(legacy,
+		[]byte(`{"rowKey":"champ:1","family":"main"}`+"\n"+`{"rowKey":"item:1","family":"main"}`+
+			"\n"), 0640))
+
+	r := newShardedReaper(2)
+	require.NoError(t, r.Start())
+	require.NoFileExists(t, legacy)
+	require.Len(t, r.workers[0].pending, 1)
+	require.Len(t, r.workers[1].pending, 1)
+
+	// entries go to the queue and log of their shard
+	r.Reap(&ReapParams{RowKey: "champ:2", Family: "main"})
+	require.NoError(t, r.Stop())
+
+	entries, err := readGCLog(r.workers[1].filePath, r.logger)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "champ:2", entries[1].RowKey)
+
+	pending, err := r.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	require.Equal(t, "item:1", pending[0].RowKey)
+
+	// a restart with fewer shards moves the entries of the extra logs
+	restarted := newShardedReaper(1)
+	require.NoError(t, restarted.Start())
+	defer restarted.cancel()
+	require.Len(t, restarted.workers[0].pending, 3)
+	require.NoFileExists(t, r.workers[1].filePath)
+}
+
+// TestReaper_crashBetweenDeleteAndGC simulates a process that accepts a delete and dies before the
+// tombstone expires. A new reaper against the same directory must still collect it.
+func TestReaper_crashBetweenDeleteAndGC(t *testing.T) {
+	dir := t.TempDir()
+
+	first := newTestReaper(t, dir, &fakeStorage{})
+	require.NoError(t, first.Start())
+
+	expiresAt := time.Now().Add(50 * time.Millisecond).UnixNano()
+	first.Reap(&ReapParams{
+		RowKey:     "champ:1",
+		Family:     "wrestlers",
+		Qualifiers: []string{"championships"},
+		Timestamp:  time.Now().UnixNano(),
+		ExpiresAt:  expiresAt,
+	})
+
+	// wait for the entry to reach the GC log, then "crash" without running Stop or a GC pass
+	require.Eventually(t, func() bool {
+		entries, err := readGCLog(first.workers[0].filePath, first.logger)
+		return err == nil && len(entries) == 1
+	}, time.Second, 10*time.Millisecond)
+	first.cancel()
+
+	// wait for the tombstone to expire and restart
+	time.Sleep(60 * time.Millisecond)
+	s := &fakeStorage{deleteRes: true}
+	second := newTestReaper(t, dir, s)
+	require.NoError(t, second.Start())
+	defer second.cancel()
+
+	second.workers[0].garbageCollector()
+
+	require.Equal(t, []string{"champ:1"}, s.reaped)
+	require.Equal(t, []string{"champ:1"}, s.changed)
+
+	entries, err := readGCLog(second.workers[0].filePath, second.logger)
+	require.NoError(t, err)
+	require.Empty(t, entries, "collected entries must be removed from the GC log")
+	require.Empty(t, second.workers[0].pending)
+}
+
+func TestReaper_garbageCollector(t *testing.T) {
+	dir := t.TempDir()
+	s := &fakeStorage{deleteRes: true, cells: 2}
+	r := newTestReaper(t, dir, s)
+	w := r.workers[0]
+
+	passesBefore := gcPasses.Value()
+	processedBefore := gcEntriesProcessed.Value()
+	cellsBefore := gcReclaimedCells.Value()
+	bytesBefore := gcReclaimedBytes.Value()
+	logEntriesBefore := gcLogEntries.Value()
+	logBytesBefore := gcLogBytes.Value()
+
+	expired := time.Now().Add(-time.Minute).UnixNano()
+	future := time.Now().Add(time.Hour).UnixNano()
+
+	require.NoError(t, w.persist(ReapParams{RowKey: "expired", Family: "fam", Qualifiers: []string{"q"},
+		ExpiresAt: expired}))
+	require.NoError(t, w.persist(ReapParams{RowKey: "family", Family: "fam", ExpiresAt: expired}))
+	require.NoError(t, w.persist(ReapParams{RowKey: "future", Family: "fam", Qualifiers: []string{"q"},
+		ExpiresAt: future}))
+	// an expired TTL is collected once, even if the values were already gone
+	require.NoError(t, w.persist(ReapParams{RowKey: "ttl", Family: "fam", Qualifiers: []string{"q"},
+		ExpiresAt: expired, Expiry: true}))
+	require.Equal(t, logEntriesBefore+4, gcLogEntries.Value())
+
+	w.garbageCollector()
+
+	// the tombstone and family entries each reclaim two cells; the TTL entry finds none
+	require.Equal(t, passesBefore+1, gcPasses.Value())
+	require.Equal(t, processedBefore+4, gcEntriesProcessed.Value())
+	require.Equal(t, cellsBefore+4, gcReclaimedCells.Value())
+	require.Equal(t, bytesBefore+40, gcReclaimedBytes.Value())
+
+	require.Equal(t, []string{"expired"}, s.reaped)
+	require.Equal(t, []string{"family"}, s.families)
+	require.Equal(t, []string{"ttl"}, s.expired)
+	require.Len(t, w.pending, 1)
+	require.Equal(t, "future", w.pending[0].RowKey)
+
+	entries, err := readGCLog(w.filePath, w.logger)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "future", entries[0].RowKey)
+
+	info, err := os.Stat(w.filePath)
+	require.NoError(t, err)
+	require.Equal(t, logEntriesBefore+1, gcLogEntries.Value())
+	require.Equal(t, logBytesBefore+float64(info.Size()), gcLogBytes.Value())
+}
+
+func TestReaper_enforcePolicies(t *testing.T) {
+	s := &fakeStorage{policyCells: 3}
+	r := newTestReaper(t, t.TempDir(), s)
+	require.Equal(t, defaultPolicyScanBudget, r.policyScanBudget)
+
+	cellsBefore := policyReclaimedCells.Value()
+	bytesBefore := policyReclaimedBytes.Value()
+
+	r.enforcePolicies()
+
+	require.Equal(t, 1, s.policyCalls)
+	require.Equal(t, cellsBefore+3, policyReclaimedCells.Value())
+	require.Equal(t, bytesBefore+30, policyReclaimedBytes.Value())
+}
+
+func TestReaper_ReapOverflow(t *testing.T) {
+	dir := t.TempDir()
+	r, err := New(&Config{
+		Path:       dir,
+		Storage:    &fakeStorage{},
+		GCInterval: 3600,
+		QueueSize:  1,
+	})
+	require.NoError(t, err)
+
+	overflowBefore := reapOverflow.Value()
+	queuedBefore := QueueDepth()
+
+	// the reaper is not running, so the first entry fills the queue and the second overflows
+	done := make(chan struct{})
+	go func() {
+		r.Reap(&ReapParams{RowKey: "queued"})
+		r.Reap(&ReapParams{RowKey: "overflow"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Reap blocked on a full queue")
+	}
+
+	require.Equal(t, overflowBefore+1, reapOverflow.Value())
+	require.Equal(t, queuedBefore+1, QueueDepth())
+	entries, err := readGCLog(r.workers[0].filePath, r.logger)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "overflow", entries[0].RowKey)
+
+	// draining moves the queued entry to disk
+	require.NoError(t, r.Drain())
+	require.Equal(t, queuedBefore, QueueDepth())
+	entries, err = readGCLog(r.workers[0].filePath, r.logger)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestReaper_Pending(t *testing.T) {
+	r, err := New(&Config{
+		Path:       t.TempDir(),
+		Storage:    &fakeStorage{},
+		GCInterval: 3600,
+		QueueSize:  4,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, r.workers[0].persist(ReapParams{RowKey: "logged"}))
+	r.Reap(&ReapParams{RowKey: "queued"})
+
+	// the queued entry is drained to the log, so both are pending
+	pending, err := r.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, "logged", pending[0].RowKey)
+	require.Equal(t, "queued", pending[1].RowKey)
+
+	entries, err := readGCLog(r.workers[0].filePath, r.logger)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// the copy does not share the pending list
+	pending[0].RowKey = "changed"
+	require.Equal(t, "logged", r.workers[0].pending[0].RowKey)
+}
+
+func TestReaper_StopDrainsAndAcceptsLateEntries(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestReaper(t, dir, &fakeStorage{})
+	require.NoError(t, r.Start())
+
+	for i := 0; i < 100; i++ {
+		r.Reap(&ReapParams{RowKey: "before-stop"})
+	}
+	require.NoError(t, r.Stop())
+
+	// a producer racing with shutdown must not panic and must not lose the entry
+	require.NotPanics(t, func() {
+		r.Reap(&ReapParams{RowKey: "after-stop"})
+	})
+
+	entries, err := readGCLog(r.workers[0].filePath, r.logger)
+	require.NoError(t, err)
+	require.Len(t, entries, 101)
+	require.Equal(t, "after-stop", entries[100].RowKey)
+
+	// a restart picks everything up
+	restarted := newTestReaper(t, dir, &fakeStorage{})
+	require.NoError(t, restarted.Start())
+	defer restarted.cancel()
+	require.Len(t, restarted.workers[0].pending, 101)
+}
+
+func TestReaper_SetInterval(t *testing.T) {
+	req := require.New(t)
+	r := newTestReaper(t, t.TempDir(), &fakeStorage{})
+
+	req.Error(r.SetInterval(0))
+	req.Equal(int64(time.Hour), r.reapInterval.Load())
+
+	req.NoError(r.SetInterval(5))
+	req.NoError(r.SetInterval(7)) // coalesces with the pending reset
+	req.Equal(int64(7*time.Second), r.reapInterval.Load())
+	req.Len(r.intervalChanged, 1)
+}
@@ -0,0 +1,282 @@
+package reaper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// worker collects the reap entries of one shard. It has its own queue, pending list and GC log,
+// so it never contends with the workers of other shards.
+type worker struct {
+	shard     int
+	filePath  string
+	collector chan ReapParams
+
+	// pending holds every reap entry of the shard that has not been collected yet. The GC log on
+	// disk is the durable copy of pending and is replayed into memory on Start, so tombstones
+	// written before a crash are still collected after a restart.
+	pending []ReapParams
+	// logEntries and logBytes are the size of the GC log, as last written
+	logEntries int
+	logBytes   int64
+	mutex      sync.Mutex
+
+	// intervalChanged signals the loop to reset its ticker
+	intervalChanged chan struct{}
+
+	storageManager storage
+	logger         zerolog.Logger
+}
+
+// run persists queued entries and collects the expired ones every interval until ctx is done.
+func (w *worker) run(ctx context.Context, interval *atomic.Int64) {
+	ticker := time.NewTicker(time.Duration(interval.Load()))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.intervalChanged:
+			ticker.Reset(time.Duration(interval.Load()))
+		case p := <-w.collector:
+			countQueued(-1)
+			if err := w.persist(p); err != nil {
+				w.logger.Error().Err(err).Msg("failed to write GCParams to log file")
+			}
+		case <-ticker.C:
+			w.garbageCollector()
+		}
+	}
+}
+
+// drain writes every queued reap entry to the GC log.
+func (w *worker) drain() error {
+	var errs []error
+	for {
+		select {
+		case p := <-w.collector:
+			countQueued(-1)
+			if err := w.persist(p); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// persist appends an entry to the GC log and registers it as pending, keeping the two in step.
+func (w *worker) persist(p ReapParams) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.pending = append(w.pending, p)
+	return w.write(&p)
+}
+
+// write will append the GCParams to the GC log file.
+func (w *worker) write(p *ReapParams) error {
+	// open the file
+	file, err := os.OpenFile(w.filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		closeErr := file.Close()
+		if closeErr != nil {
+			w.logger.Error().Err(closeErr).Str("file", w.filePath).Msg("failed to close file")
+		}
+	}(file)
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		w.logger.Error().Err(err).Msg("failed to marshal GCParams")
+		return err
+	}
+
+	_, err = file.WriteString(string(data) + "\n")
+	if err != nil {
+		w.logger.Error().Err(err).Msg("failed to write GCParams to log file")
+		return err
+	}
+
+	w.setLogSize(w.logEntries+1, w.logBytes+int64(len(data))+1)
+	return nil
+}
+
+// setLogSize records the number of entries and bytes in the GC log. The log size gauges add up
+// the GC logs of every table, so they are moved by the change. The caller holds the mutex.
+func (w *worker) setLogSize(entries int, bytes int64) {
+	gcLogEntries.Add(float64(entries - w.logEntries))
+	gcLogBytes.Add(float64(bytes - w.logBytes))
+	w.logEntries, w.logBytes = entries, bytes
+}
+
+// readGCLog reads all entries from a GC log file. Lines that cannot be parsed are skipped.
+func readGCLog(path string, logger zerolog.Logger) ([]ReapParams, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []ReapParams
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 {
+			continue
+		}
+
+		var params ReapParams
+		if err = json.Unmarshal([]byte(line), &params); err != nil {
+			logger.Error().Err(err).Msg("Error unmarshalling GC log entry")
+			continue
+		}
+		entries = append(entries, params)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// garbageCollector runs the garbage collection over the tombstones of the shard.
+func (w *worker) garbageCollector() {
+	// Current time to check expiration
+	now := time.Now()
+	nowUnix := now.UnixNano()
+
+	// take the pending entries; anything registered while we work is kept separately
+	w.mutex.Lock()
+	entries := w.pending
+	w.pending = nil
+	w.mutex.Unlock()
+
+	var activeEntries []ReapParams
+	var processed int
+	var removed int
+	var cells int
+	var bytes int64
+
+	// Process each entry
+	for _, params := range entries {
+		processed++
+
+		// Check if it's expired
+		if nowUnix > params.ExpiresAt {
+			// if there are no qualifiers, we should delete the entire family
+			if len(params.Qualifiers) == 0 {
+				w.logger.Debug().Msgf("Deleting entire family %s for row %s", params.Family, params.RowKey)
+				// Delete the entire family
+				n, b := w.storageManager.DeleteRowFamily(params.RowKey, params.Family)
+				cells += n
+				bytes += b
+				removed++
+				// report this change to the snapshot server
+				w.storageManager.MarkRowChanged(params.Family, params.RowKey)
+				continue
+			}
+
+			// values written with a TTL have expired, whether or not any are left to remove
+			if params.Expiry {
+				n, b := w.storageManager.DeleteExpiredValues(params.RowKey, params.Family,
+					params.Qualifiers)
+				if n > 0 {
+					removed++
+				}
+				cells += n
+				bytes += b
+				continue
+			}
+
+			// Process the tombstone for this entry
+			n, b, deleted := w.storageManager.DeleteExpiredTombstones(params.RowKey,
+				params.Family, params.Qualifiers, params.Timestamp)
+			cells += n
+			bytes += b
+			if deleted {
+				removed++
+
+				// if deleted, we need to report this change to the snapshot server
+				w.storageManager.MarkRowChanged(params.Family, params.RowKey)
+			} else {
+				// the entry is still valid and should remain in the file
+				activeEntries = append(activeEntries, params)
+			}
+		} else {
+			// Keep the entry for next time
+			activeEntries = append(activeEntries, params)
+		}
+	}
+
+	// lock to prevent concurrent writes to file
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.pending = append(activeEntries, w.pending...)
+
+	// Rewrite the file with only active entries
+	if err := w.rewriteGCLog(w.pending); err != nil {
+		w.logger.Error().Err(err).Msg("Error rewriting GC log file")
+	}
+
+	duration := time.Since(now)
+	gcPasses.Inc()
+	gcPassSeconds.Add(duration.Seconds())
+	gcLastPassSeconds.Set(duration.Seconds())
+	gcEntriesProcessed.Add(float64(processed))
+	gcReclaimedCells.Add(float64(cells))
+	gcReclaimedBytes.Add(float64(bytes))
+
+	w.logger.
+		Debug().
+		Str("duration", duration.String()).
+		Int("cells", cells).
+		Int64("bytes", bytes).
+		Msgf("Garbage collection complete: processed %d entries, "+
+			"removed %d",
+			processed,
+			removed)
+}
+
+// rewriteGCLog rewrites the GC log file with only active entries.
+func (w *worker) rewriteGCLog(entries []ReapParams) error {
+	// Truncate the file (effectively delete all content)
+	file, err := os.OpenFile(w.filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return fmt.Errorf("failed to truncate GC log file: %w", err)
+	}
+	defer file.Close()
+
+	// Write the active entries back to the file
+	var size int64
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal entry: %w", err)
+		}
+
+		n, err := file.WriteString(string(data) + "\n")
+		if err != nil {
+			return fmt.Errorf("failed to write active entry: %w", err)
+		}
+		size += int64(n)
+	}
+
+	// Ensure data is written to disk
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync GC log file: %w", err)
+	}
+
+	w.setLogSize(len(entries), size)
+	return nil
+}
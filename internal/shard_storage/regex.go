@@ -0,0 +1,73 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"regexp"
+	"regexp/syntax"
+	"time"
+)
+
+const (
+	// DefaultMaxRegexProgram is the most instructions a row key regex may compile to when
+	// Config.MaxRegexProgram is not set.
+	DefaultMaxRegexProgram = 2000
+	// DefaultRegexScanBudget is how long a regex scan may read one shard when
+	// Config.RegexScanBudget is not set.
+	DefaultRegexScanBudget = 250 * time.Millisecond
+
+	// regexCheckInterval is the number of row keys matched between checks of the time budget
+	regexCheckInterval = 256
+)
+
+var regexScansAbandoned = metrics.NewCounter("litetable_regex_scans_abandoned_total",
+	"Regex scans abandoned because a shard took longer than the scan budget.")
+
+// compileRowKeyRegex compiles the row key regex of a scan. RE2 matches in time linear in the
+// length of the key, but every instruction of the program is paid for on every key, so patterns
+// that compile to more than maxProgram instructions are refused. Compiled patterns are cached, so
+// a pattern scanned over and over is only compiled once.
+func compileRowKeyRegex(pattern string, maxProgram int) (*regexp.Regexp, error) {
+	compiled, ok := rowKeyRegexes.get(pattern)
+	if !ok {
+		var err error
+		compiled, err = compileRegex(pattern)
+		if err != nil {
+			return nil, err
+		}
+		rowKeyRegexes.add(pattern, compiled)
+	}
+	if compiled.size > maxProgram {
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"row key regex is too complex: it compiles to %d instructions, at most %d are allowed",
+			compiled.size, maxProgram)
+	}
+	return compiled.regexp, nil
+}
+
+// compileRegex compiles a pattern and measures its program.
+func compileRegex(pattern string) (*compiledRegex, error) {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"invalid row key regex: %v", err)
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"invalid row key regex: %v", err)
+	}
+	reg, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+			"invalid row key regex: %v", err)
+	}
+	return &compiledRegex{regexp: reg, size: len(prog.Inst)}, nil
+}
+
+// errRegexBudget is returned by a regex scan that took longer than its budget on a shard.
+func errRegexBudget(budget time.Duration) error {
+	return litetable.NewError(litetable.ErrorCodeExhausted,
+		"regex scan exceeded its budget of %s per shard: narrow the pattern or use a prefix",
+		budget)
+}
@@ -0,0 +1,83 @@
+package shard_storage
+
+import (
+	"container/list"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"regexp"
+	"sync"
+)
+
+// regexCacheSize is the number of compiled row key regexes kept for reuse
+const regexCacheSize = 256
+
+var (
+	regexCacheHits = metrics.NewCounter("litetable_regex_cache_hits_total",
+		"Regex scans that reused a compiled pattern.")
+	regexCacheMisses = metrics.NewCounter("litetable_regex_cache_misses_total",
+		"Regex scans that compiled their pattern.")
+)
+
+// rowKeyRegexes caches the compiled row key regexes of every table, since dashboards send the
+// same few patterns over and over.
+var rowKeyRegexes = newRegexCache(regexCacheSize)
+
+// compiledRegex is a compiled pattern with the number of instructions of its program.
+type compiledRegex struct {
+	regexp *regexp.Regexp
+	size   int
+}
+
+// regexCache is an LRU cache of compiled regexes keyed by pattern. A regexp.Regexp is safe for
+// concurrent use, so a cached pattern is shared by every scan using it.
+type regexCache struct {
+	mutex    sync.Mutex
+	capacity int
+	// order holds the patterns, most recently used first
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// regexCacheEntry is the value of an element of regexCache.order.
+type regexCacheEntry struct {
+	pattern  string
+	compiled *compiledRegex
+}
+
+func newRegexCache(capacity int) *regexCache {
+	return &regexCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns the compiled pattern and marks it used.
+func (c *regexCache) get(pattern string) (*compiledRegex, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.entries[pattern]
+	if !ok {
+		regexCacheMisses.Inc()
+		return nil, false
+	}
+	regexCacheHits.Inc()
+	c.order.MoveToFront(elem)
+	return elem.Value.(*regexCacheEntry).compiled, true
+}
+
+// add caches a compiled pattern, evicting the least recently used pattern when the cache is
+// full.
+func (c *regexCache) add(pattern string, compiled *compiledRegex) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if elem, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[pattern] = c.order.PushFront(&regexCacheEntry{pattern: pattern, compiled: compiled})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*regexCacheEntry).pattern)
+	}
+}
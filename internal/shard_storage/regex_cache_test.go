@@ -0,0 +1,50 @@
+package shard_storage
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRegexCache(t *testing.T) {
+	req := require.New(t)
+	cache := newRegexCache(2)
+
+	compile := func(pattern string) *compiledRegex {
+		compiled, err := compileRegex(pattern)
+		req.NoError(err)
+		return compiled
+	}
+	ahri, jinx, zed := compile("^ahri"), compile("^jinx"), compile("^zed")
+
+	cache.add("^ahri", ahri)
+	cache.add("^jinx", jinx)
+	got, ok := cache.get("^ahri")
+	req.True(ok)
+	req.Same(ahri, got)
+
+	// ^jinx was used least recently, so it makes room for ^zed
+	cache.add("^zed", zed)
+	_, ok = cache.get("^jinx")
+	req.False(ok)
+	for pattern, want := range map[string]*compiledRegex{"^ahri": ahri, "^zed": zed} {
+		got, ok = cache.get(pattern)
+		req.True(ok)
+		req.Same(want, got)
+	}
+}
+
+func TestCompileRowKeyRegex_cached(t *testing.T) {
+	req := require.New(t)
+	hits := regexCacheHits.Value()
+
+	first, err := compileRowKeyRegex("^cached:[0-9]+$", DefaultMaxRegexProgram)
+	req.NoError(err)
+	second, err := compileRowKeyRegex("^cached:[0-9]+$", DefaultMaxRegexProgram)
+	req.NoError(err)
+	req.Same(first, second)
+	req.Equal(hits+1, regexCacheHits.Value())
+
+	// the cached program is still checked against the limit of each table
+	_, err = compileRowKeyRegex("^cached:[0-9]+$", 2)
+	req.ErrorContains(err, "row key regex is too complex")
+}
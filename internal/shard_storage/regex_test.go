@@ -0,0 +1,77 @@
+package shard_storage
+
+import (
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCompileRowKeyRegex(t *testing.T) {
+	tests := map[string]struct {
+		pattern string
+		wantErr string
+	}{
+		"simple": {
+			pattern: "^champ:[0-9]+$",
+		},
+		"invalid": {
+			pattern: "champ:(",
+			wantErr: "invalid row key regex",
+		},
+		"too complex": {
+			pattern: "(champ|wwe|aew){300}",
+			wantErr: "row key regex is too complex",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			reg, err := compileRowKeyRegex(tc.pattern, DefaultMaxRegexProgram)
+			if tc.wantErr != "" {
+				req.ErrorContains(err, tc.wantErr)
+				req.True(errors.Is(err, litetable.ErrInvalidArgument))
+				return
+			}
+			req.NoError(err)
+			req.True(reg.MatchString("champ:1"))
+		})
+	}
+}
+
+func TestManager_FilterRowsByRegex(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	now := time.Now().UnixNano()
+	for i := range 2000 {
+		req.NoError(m.Apply(fmt.Sprintf("champ:%d", i), "main", []string{"name"},
+			[][]byte{[]byte("Ahri")}, now, 0))
+	}
+
+	data, stats, found, err := m.FilterRowsByRegex("^champ:1[0-9]{3}$", litetable.ScanPage{})
+	req.NoError(err)
+	req.True(found)
+	req.Len(*data, 1000)
+	req.Equal(2000, stats.Examined)
+
+	// every key is examined even when none matches
+	_, stats, found, err = m.FilterRowsByRegex("^zed:", litetable.ScanPage{})
+	req.NoError(err)
+	req.False(found)
+	req.Equal(2000, stats.Examined)
+
+	_, _, _, err = m.FilterRowsByRegex("champ:(", litetable.ScanPage{})
+	req.True(errors.Is(err, litetable.ErrInvalidArgument))
+
+	// every shard holds more rows than are matched between checks of the budget
+	abandoned := regexScansAbandoned.Value()
+	m.regexScanBudget = time.Nanosecond
+	_, _, found, err = m.FilterRowsByRegex("^champ:", litetable.ScanPage{})
+	req.True(errors.Is(err, litetable.ErrExhausted))
+	req.False(found)
+	req.Equal(abandoned+1, regexScansAbandoned.Value())
+}
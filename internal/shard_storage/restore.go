@@ -0,0 +1,74 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/logging"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// RestoreReport is the result of restoring a table into memory without serving it.
+type RestoreReport struct {
+	// Verify checks the files the table is restored from
+	Verify *VerifyReport
+	// Load is what the restore loaded, as the table would report it on start
+	Load litetable.LoadReport
+	// Err is why the restore failed, nil when it did not
+	Err error
+}
+
+// OK reports whether every file passed verification and the table was restored.
+func (r *RestoreReport) OK() bool {
+	return r.Err == nil && r.Verify.OK()
+}
+
+// Write prints the report in a human-readable form.
+func (r *RestoreReport) Write(w io.Writer) {
+	r.Verify.Write(w)
+	if r.Err != nil {
+		_, _ = fmt.Fprintf(w, "restore failed: %v\n", r.Err)
+		return
+	}
+
+	backup := r.Load.Backup
+	if backup == "" {
+		backup = "no backup"
+	}
+	_, _ = fmt.Fprintf(w, "restored %d rows from %s and %d snapshots in %s\n", r.Load.Rows,
+		backup, r.Load.Snapshots, r.Load.Duration)
+	_, _ = fmt.Fprintf(w, "rows by shard: %v (row skew %.2f)\n", r.Load.ShardRows,
+		r.Load.RowSkew)
+	_, _ = fmt.Fprintf(w, "families: %s\n", strings.Join(r.Load.Families, ", "))
+}
+
+// ValidateRestore verifies the files of a table under rootDir and restores them into shardCount
+// memory shards, as the table would on start. Nothing is started and nothing on disk is
+// changed, so it is safe to run against a copy of the data directory on a scratch machine. It
+// only returns an error if the directory cannot be read.
+func ValidateRestore(rootDir, table string, shardCount int) (*RestoreReport, error) {
+	verify, err := Verify(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	shards, err := initializeDataShards(&shardConfig{count: shardCount})
+	if err != nil {
+		return nil, err
+	}
+	m := &Manager{
+		rootDir:     rootDir,
+		dataDir:     filepath.Join(rootDir, backupDirName),
+		snapshotDir: filepath.Join(rootDir, snapshotDir),
+		table:       table,
+		shardCount:  shardCount,
+		shardMap:    shards,
+		logger:      logging.For("shard_storage"),
+	}
+
+	report := &RestoreReport{Verify: verify}
+	report.Err = m.loadFromLatestBackup()
+	report.Load = m.loadReport
+	return report, nil
+}
@@ -0,0 +1,56 @@
+package shard_storage
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestValidateRestore(t *testing.T) {
+	req := require.New(t)
+
+	// an empty directory restores an empty table
+	report, err := ValidateRestore(t.TempDir(), "default", 4)
+	req.NoError(err)
+	req.True(report.OK())
+	req.Zero(report.Load.Rows)
+
+	m := newTestManager(t)
+	for _, rowKey := range []string{"champ:1", "champ:2", "champ:3"} {
+		req.NoError(m.Apply(rowKey, "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+			time.Now().UnixNano(), 0))
+	}
+	req.NoError(m.Flush())
+
+	report, err = ValidateRestore(m.rootDir, "champions", 4)
+	req.NoError(err)
+	req.True(report.OK())
+	req.Equal("champions", report.Load.Table)
+	req.Equal(3, report.Load.Rows)
+	req.Len(report.Load.ShardRows, 4)
+	req.Equal([]string{"main"}, report.Load.Families)
+
+	var out bytes.Buffer
+	report.Write(&out)
+	req.Contains(out.String(), "restored 3 rows from backup-")
+	req.Contains(out.String(), "families: main")
+
+	// a corrupt backup fails verification and the restore
+	backups, err := filepath.Glob(filepath.Join(m.dataDir, backupFileGlob))
+	req.NoError(err)
+	req.NoError(os.WriteFile(backups[0], []byte("{"), 0644))
+	report, err = ValidateRestore(m.rootDir, "champions", 4)
+	req.NoError(err)
+	req.False(report.OK())
+	req.Error(report.Err)
+
+	out.Reset()
+	report.Write(&out)
+	req.Contains(out.String(), "restore failed")
+
+	_, err = ValidateRestore(filepath.Join(m.rootDir, "missing"), "champions", 4)
+	req.Error(err)
+}
@@ -0,0 +1,68 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"time"
+)
+
+// BackupRetention are the age based rules for pruning backups, on top of the number of backups
+// kept by MaxSnapshotLimit. A backup is kept if any rule keeps it, so "keep 7 dailies and 4
+// weeklies" is KeepDaily: 7, KeepWeekly: 4. Zero disables a rule.
+type BackupRetention struct {
+	// Days keeps every backup created within the last N days.
+	Days int
+	// KeepDaily keeps the newest backup of each of the last N days that have a backup.
+	KeepDaily int
+	// KeepWeekly keeps the newest backup of each of the last N ISO weeks that have a backup.
+	KeepWeekly int
+}
+
+func (r BackupRetention) validate() error {
+	if r.Days < 0 || r.KeepDaily < 0 || r.KeepWeekly < 0 {
+		return fmt.Errorf("backup retention rules cannot be negative")
+	}
+	return nil
+}
+
+// retained returns the files of the backups to keep. The catalog is ordered oldest first and the
+// newest keepLast backups are always kept.
+func (r BackupRetention) retained(catalog []*litetable.BackupManifest, keepLast int,
+	now time.Time) map[string]bool {
+	keep := make(map[string]bool, len(catalog))
+	cutoff := now.AddDate(0, 0, -r.Days)
+
+	var days, weeks int
+	var lastDay, lastWeek string
+
+	// walk newest first, so the first backup seen in a day or week is its newest
+	for i := len(catalog) - 1; i >= 0; i-- {
+		backup := catalog[i]
+		created := time.Unix(0, backup.CreatedAt)
+
+		if len(catalog)-i <= keepLast {
+			keep[backup.File] = true
+		}
+		if r.Days > 0 && created.After(cutoff) {
+			keep[backup.File] = true
+		}
+
+		if day := created.Format(time.DateOnly); day != lastDay {
+			lastDay = day
+			days++
+			if days <= r.KeepDaily {
+				keep[backup.File] = true
+			}
+		}
+
+		year, week := created.ISOWeek()
+		if w := fmt.Sprintf("%d-%d", year, week); w != lastWeek {
+			lastWeek = w
+			weeks++
+			if weeks <= r.KeepWeekly {
+				keep[backup.File] = true
+			}
+		}
+	}
+	return keep
+}
@@ -0,0 +1,107 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestBackupRetention_retained(t *testing.T) {
+	// Wednesday, two backups a day for 21 days back: backup-<days ago>-<hour>
+	now := time.Date(2025, 5, 14, 20, 0, 0, 0, time.Local)
+	var catalog []*litetable.BackupManifest
+	for daysAgo := 20; daysAgo >= 0; daysAgo-- {
+		for _, hour := range []int{6, 18} {
+			created := time.Date(2025, 5, 14-daysAgo, hour, 0, 0, 0, time.Local)
+			catalog = append(catalog, &litetable.BackupManifest{
+				File:      fmt.Sprintf("backup-%d-%d", daysAgo, hour),
+				CreatedAt: created.UnixNano(),
+			})
+		}
+	}
+
+	tests := map[string]struct {
+		retention BackupRetention
+		keepLast  int
+		want      []string
+	}{
+		"count only": {
+			keepLast: 3,
+			want:     []string{"backup-0-18", "backup-0-6", "backup-1-18"},
+		},
+		"by age": {
+			// created after 20:00 yesterday
+			retention: BackupRetention{Days: 1},
+			keepLast:  1,
+			want:      []string{"backup-0-18", "backup-0-6"},
+		},
+		"daily": {
+			retention: BackupRetention{KeepDaily: 3},
+			keepLast:  1,
+			want:      []string{"backup-0-18", "backup-1-18", "backup-2-18"},
+		},
+		"weekly": {
+			// the newest backup of this week and of the two before it, which end on Sundays
+			retention: BackupRetention{KeepWeekly: 3},
+			keepLast:  1,
+			want:      []string{"backup-0-18", "backup-3-18", "backup-10-18"},
+		},
+		"dailies and weeklies": {
+			retention: BackupRetention{KeepDaily: 2, KeepWeekly: 2},
+			keepLast:  1,
+			want:      []string{"backup-0-18", "backup-1-18", "backup-3-18"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			keep := tc.retention.retained(catalog, tc.keepLast, now)
+
+			var got []string
+			for file := range keep {
+				got = append(got, file)
+			}
+			require.ElementsMatch(t, tc.want, got)
+		})
+	}
+}
+
+func TestManager_maintainBackupLimit_retention(t *testing.T) {
+	req := require.New(t)
+	dir := t.TempDir()
+	m := &Manager{
+		dataDir:          dir,
+		maxSnapshotLimit: 1,
+		backupRetention:  BackupRetention{KeepDaily: 2},
+	}
+
+	// three backups on one day and one on each of the three days before
+	now := time.Date(2025, 5, 14, 12, 0, 0, 0, time.Local)
+	created := []time.Time{
+		now.AddDate(0, 0, -3),
+		now.AddDate(0, 0, -2),
+		now.AddDate(0, 0, -1),
+		now.Add(-2 * time.Second),
+		now.Add(-time.Second),
+		now,
+	}
+	for _, c := range created {
+		name := fmt.Sprintf("backup-%d.db", c.UnixNano())
+		req.NoError(os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644))
+	}
+
+	m.maintainBackupLimit()
+
+	files, err := filepath.Glob(filepath.Join(dir, backupFileGlob))
+	req.NoError(err)
+	sort.Strings(files)
+	req.Equal([]string{
+		filepath.Join(dir, fmt.Sprintf("backup-%d.db", created[2].UnixNano())),
+		filepath.Join(dir, fmt.Sprintf("backup-%d.db", created[5].UnixNano())),
+	}, files)
+}
@@ -0,0 +1,144 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"sort"
+	"unique"
+)
+
+// row is the in-memory form of a row. A row rarely has more than a handful of families, so they
+// are kept in a slice sorted by name rather than a map, and family and qualifier names are
+// interned so every row shares one copy of each name.
+//
+// The value slices of a row are copy-on-write: a stored value is never modified in place. A
+// write appends past the end of a slice, and anything else that changes the values, such as a
+// delete or the reaper, stores a new slice. Snapshots and readers can keep a slice after the
+// shard is unlocked without copying it.
+type row struct {
+	families []rowFamily
+}
+
+type rowFamily struct {
+	name       string
+	qualifiers litetable.VersionedQualifier
+}
+
+// newRow builds a row from its litetable.Data form, interning the family and qualifier names.
+func newRow(families map[string]litetable.VersionedQualifier) *row {
+	r := &row{families: make([]rowFamily, 0, len(families))}
+	for name, qualifiers := range families {
+		if qualifiers == nil {
+			continue
+		}
+		interned := make(litetable.VersionedQualifier, len(qualifiers))
+		for qualifier, values := range qualifiers {
+			interned[intern(qualifier)] = values
+		}
+		r.families = append(r.families, rowFamily{name: intern(name), qualifiers: interned})
+	}
+	sort.Slice(r.families, func(i, j int) bool {
+		return r.families[i].name < r.families[j].name
+	})
+	return r
+}
+
+// intern returns the canonical copy of s.
+func intern(s string) string {
+	return unique.Make(s).Value()
+}
+
+func (r *row) search(name string) (int, bool) {
+	i := sort.Search(len(r.families), func(i int) bool {
+		return r.families[i].name >= name
+	})
+	return i, i < len(r.families) && r.families[i].name == name
+}
+
+// family returns the qualifiers of a family.
+func (r *row) family(name string) (litetable.VersionedQualifier, bool) {
+	i, ok := r.search(name)
+	if !ok {
+		return nil, false
+	}
+	return r.families[i].qualifiers, true
+}
+
+// addFamily returns the qualifiers of a family, adding the family if the row does not have it.
+func (r *row) addFamily(name string) litetable.VersionedQualifier {
+	i, ok := r.search(name)
+	if ok {
+		return r.families[i].qualifiers
+	}
+
+	qualifiers := make(litetable.VersionedQualifier)
+	r.families = append(r.families, rowFamily{})
+	copy(r.families[i+1:], r.families[i:])
+	r.families[i] = rowFamily{name: intern(name), qualifiers: qualifiers}
+	return qualifiers
+}
+
+// deleteFamily removes a family from the row.
+func (r *row) deleteFamily(name string) {
+	if i, ok := r.search(name); ok {
+		r.families = append(r.families[:i], r.families[i+1:]...)
+	}
+}
+
+func (r *row) isEmpty() bool {
+	return len(r.families) == 0
+}
+
+// columns returns the row in its litetable.Data form. The qualifiers are shared with the row.
+func (r *row) columns() map[string]litetable.VersionedQualifier {
+	columns := make(map[string]litetable.VersionedQualifier, len(r.families))
+	for _, f := range r.families {
+		columns[f.name] = f.qualifiers
+	}
+	return columns
+}
+
+// appendValue appends a value to a qualifier, interning the qualifier name when it is new.
+func appendValue(qualifiers litetable.VersionedQualifier, qualifier string,
+	value litetable.TimestampedValue) {
+	values, ok := qualifiers[qualifier]
+	if !ok {
+		qualifier = intern(qualifier)
+	}
+	qualifiers[qualifier] = append(values, value)
+}
+
+// oldValue returns the latest value of a cell before a change, for its CDC event, if the
+// storage reports old values and the cell has one that is not a tombstone.
+func (m *Manager) oldValue(values []litetable.TimestampedValue) ([]byte, bool) {
+	if !m.cdcOldValues || len(values) == 0 {
+		return nil, false
+	}
+	latest := values[0]
+	for _, v := range values[1:] {
+		if v.Timestamp > latest.Timestamp ||
+			(v.Timestamp == latest.Timestamp && v.Seq > latest.Seq) {
+			latest = v
+		}
+	}
+	if latest.IsTombstone {
+		return nil, false
+	}
+	if latest.Compressed {
+		return m.decompressed([]litetable.TimestampedValue{latest})[0].Value, true
+	}
+	return latest.Value, true
+}
+
+// changedAfter reports whether a mutation numbered after seq changed the row.
+func (r *row) changedAfter(seq uint64) bool {
+	for _, f := range r.families {
+		for _, values := range f.qualifiers {
+			for _, v := range values {
+				if v.Seq > seq {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
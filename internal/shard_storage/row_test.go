@@ -0,0 +1,132 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestRow_families(t *testing.T) {
+	req := require.New(t)
+	r := &row{}
+
+	stats := r.addFamily("stats")
+	stats["wins"] = []litetable.TimestampedValue{{Value: []byte("3")}}
+	r.addFamily("main")
+	r.addFamily("tags")
+	req.Equal(stats, r.addFamily("stats"))
+
+	names := func() []string {
+		var names []string
+		for _, f := range r.families {
+			names = append(names, f.name)
+		}
+		return names
+	}
+	req.Equal([]string{"main", "stats", "tags"}, names())
+
+	got, ok := r.family("stats")
+	req.True(ok)
+	req.Equal(stats, got)
+	_, ok = r.family("missing")
+	req.False(ok)
+
+	r.deleteFamily("main")
+	r.deleteFamily("missing")
+	req.Equal([]string{"stats", "tags"}, names())
+	req.Equal(map[string]litetable.VersionedQualifier{"stats": stats, "tags": {}}, r.columns())
+
+	r.deleteFamily("stats")
+	r.deleteFamily("tags")
+	req.True(r.isEmpty())
+}
+
+func TestNewRow(t *testing.T) {
+	req := require.New(t)
+	values := []litetable.TimestampedValue{{Value: []byte("Ahri"), Timestamp: 1}}
+
+	a := newRow(map[string]litetable.VersionedQualifier{
+		strings.Clone("main"): {strings.Clone("name"): values},
+		"deleted":             nil,
+	})
+	b := newRow(map[string]litetable.VersionedQualifier{
+		strings.Clone("main"): {strings.Clone("name"): values},
+	})
+
+	req.Equal(map[string]litetable.VersionedQualifier{"main": {"name": values}}, a.columns())
+
+	// both rows share one copy of the family and qualifier names
+	req.Equal(unsafe.StringData(a.families[0].name), unsafe.StringData(b.families[0].name))
+	for qa := range a.families[0].qualifiers {
+		for qb := range b.families[0].qualifiers {
+			req.Equal(unsafe.StringData(qa), unsafe.StringData(qb))
+		}
+	}
+}
+
+// benchFamilies are cloned on every write, as they would be when parsed from a query.
+var benchFamilies = []string{"main", "stats", "tags"}
+
+func BenchmarkManager_Apply(b *testing.B) {
+	m := newTestManager(b)
+	require.NoError(b, m.UpdateFamilies(benchFamilies))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		family := strings.Clone(benchFamilies[i%len(benchFamilies)])
+		_ = m.Apply(fmt.Sprintf("row:%d", i%10000), family, []string{strings.Clone("name")},
+			[][]byte{[]byte("Ahri")}, int64(i), 0)
+	}
+}
+
+func BenchmarkManager_GetRowByFamily(b *testing.B) {
+	m := newTestManager(b)
+	require.NoError(b, m.UpdateFamilies(benchFamilies))
+	for i := 0; i < 10000; i++ {
+		for _, family := range benchFamilies {
+			_ = m.Apply(fmt.Sprintf("row:%d", i), family, []string{"name", "role"},
+				[][]byte{[]byte("Ahri"), []byte("mage")}, time.Now().UnixNano(), 0)
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.GetRowByFamily(fmt.Sprintf("row:%d", i%10000), "stats")
+	}
+}
+
+// BenchmarkManager_rowMemory reports the heap held per row and the time spent in a full GC
+// once the rows are written.
+func BenchmarkManager_rowMemory(b *testing.B) {
+	const rows = 50000
+	for i := 0; i < b.N; i++ {
+		m := newTestManager(b)
+		require.NoError(b, m.UpdateFamilies(benchFamilies))
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		for r := 0; r < rows; r++ {
+			for _, family := range benchFamilies {
+				_ = m.Apply(fmt.Sprintf("row:%d", r), strings.Clone(family),
+					[]string{strings.Clone("name"), strings.Clone("role")},
+					[][]byte{[]byte("Ahri"), []byte("mage")}, int64(r), 0)
+			}
+		}
+		m.takeChanges()
+
+		start := time.Now()
+		runtime.GC()
+		gc := time.Since(start)
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/rows, "heap-B/row")
+		b.ReportMetric(float64(after.HeapObjects-before.HeapObjects)/rows, "objects/row")
+		b.ReportMetric(float64(gc.Microseconds()), "gc-µs")
+		runtime.KeepAlive(m)
+	}
+}
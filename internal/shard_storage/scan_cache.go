@@ -0,0 +1,129 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxScanCacheEntries is the most prefix reads a table keeps cached
+const maxScanCacheEntries = 1024
+
+var (
+	scanCacheHits = metrics.NewCounter("litetable_scan_cache_hits_total",
+		"Prefix reads answered from the scan cache.")
+	scanCacheMisses = metrics.NewCounter("litetable_scan_cache_misses_total",
+		"Prefix reads that scanned the shards with the scan cache on.")
+)
+
+// scanKey identifies a cached prefix read.
+type scanKey struct {
+	prefix string
+	family string
+	latest int
+}
+
+type scanEntry struct {
+	rows    map[string]*litetable.Row
+	expires time.Time
+}
+
+// scanCache holds the rows of recent prefix reads for a short time, for read-mostly tables
+// whose dashboards send the same reads over and over. A change to a row drops every cached read
+// whose prefix the row key starts with.
+type scanCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mutex   sync.Mutex
+	entries map[scanKey]*scanEntry
+	// generation counts the changes that dropped reads, so a read that scanned while a row
+	// changed is not cached
+	generation uint64
+}
+
+func newScanCache(ttl time.Duration) *scanCache {
+	return &scanCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[scanKey]*scanEntry),
+	}
+}
+
+// CachesScans reports whether the table keeps the rows of recent prefix reads.
+func (m *Manager) CachesScans() bool {
+	return m.scanCache != nil
+}
+
+// ScanCached returns the cached rows of a prefix read, or the rows of scan, which are cached
+// when the table has a scan cache, scan reports its rows are complete, and no row changed while
+// scan ran. A scan that stopped at a page of the prefix is not complete, so it is never cached
+// as the rows of the whole prefix. Cached rows are shared by every read they answer, so they
+// must not be changed.
+func (m *Manager) ScanCached(prefix, family string, latest int,
+	scan func() (map[string]*litetable.Row, bool, error)) (map[string]*litetable.Row, error) {
+	c := m.scanCache
+	if c == nil {
+		rows, _, err := scan()
+		return rows, err
+	}
+	key := scanKey{prefix: prefix, family: family, latest: latest}
+
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	if ok && c.now().Before(entry.expires) {
+		c.mutex.Unlock()
+		scanCacheHits.Inc()
+		return entry.rows, nil
+	}
+	generation := c.generation
+	c.mutex.Unlock()
+
+	scanCacheMisses.Inc()
+	rows, complete, err := scan()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !complete || c.generation != generation {
+		return rows, nil
+	}
+	if _, ok = c.entries[key]; !ok && len(c.entries) >= maxScanCacheEntries {
+		c.evictExpired()
+		if len(c.entries) >= maxScanCacheEntries {
+			return rows, nil
+		}
+	}
+	c.entries[key] = &scanEntry{rows: rows, expires: c.now().Add(c.ttl)}
+	return rows, nil
+}
+
+// invalidate drops the cached reads whose prefix the row key starts with.
+func (c *scanCache) invalidate(rowKey string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// a read scanning right now may have missed the change, whether it is cached yet or not
+	c.generation++
+	for key := range c.entries {
+		if strings.HasPrefix(rowKey, key.prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// evictExpired drops the reads cached for longer than the ttl. The caller holds the lock.
+func (c *scanCache) evictExpired() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManager_ScanCached(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	m.scanCache = newScanCache(time.Minute)
+	now := time.Unix(1000, 0)
+	m.scanCache.now = func() time.Time { return now }
+
+	scans := 0
+	scan := func() (map[string]*litetable.Row, bool, error) {
+		scans++
+		return map[string]*litetable.Row{"champ:1": {Key: "champ:1"}}, true, nil
+	}
+	read := func(prefix string) {
+		rows, err := m.ScanCached(prefix, "main", 0, scan)
+		req.NoError(err)
+		req.Contains(rows, "champ:1")
+	}
+
+	read("champ:")
+	read("champ:")
+	req.Equal(1, scans)
+
+	// a change outside the prefix keeps the read, and one under it drops it
+	req.NoError(m.Apply("wwe:1", "main", []string{"name"}, [][]byte{[]byte("Cena")},
+		time.Now().UnixNano(), 0))
+	read("champ:")
+	req.Equal(1, scans)
+	req.NoError(m.Apply("champ:2", "main", []string{"name"}, [][]byte{[]byte("Jinx")},
+		time.Now().UnixNano(), 0))
+	read("champ:")
+	req.Equal(2, scans)
+
+	// the family and versions are part of the key
+	_, err := m.ScanCached("champ:", "main", 1, scan)
+	req.NoError(err)
+	req.Equal(3, scans)
+
+	now = now.Add(time.Minute)
+	read("champ:")
+	req.Equal(4, scans)
+
+	// a read that scanned while a row changed is not cached
+	_, err = m.ScanCached("aew:", "main", 0, func() (map[string]*litetable.Row, bool, error) {
+		m.MarkRowChanged("main", "aew:1")
+		return scan()
+	})
+	req.NoError(err)
+	_, err = m.ScanCached("aew:", "main", 0, scan)
+	req.NoError(err)
+	req.Equal(6, scans)
+
+	// a scan that stopped at a page of the prefix is not cached
+	partial := func() (map[string]*litetable.Row, bool, error) {
+		scans++
+		return map[string]*litetable.Row{"wwe:1": {Key: "wwe:1"}}, false, nil
+	}
+	for range 2 {
+		_, err = m.ScanCached("wwe:", "main", 0, partial)
+		req.NoError(err)
+	}
+	req.Equal(8, scans)
+}
+
+func TestManager_ScanCached_disabled(t *testing.T) {
+	m := newTestManager(t)
+	scans := 0
+	for range 2 {
+		_, err := m.ScanCached("champ:", "main", 0, func() (map[string]*litetable.Row, bool,
+			error) {
+			scans++
+			return nil, true, nil
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, scans)
+}
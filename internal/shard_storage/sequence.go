@@ -0,0 +1,35 @@
+package shard_storage
+
+// Sequence returns the sequence number of the latest mutation. A read that keeps only the values
+// with a sequence number up to it sees every shard at the same point, even while writes land.
+func (m *Manager) Sequence() uint64 {
+	return m.sequence.Load()
+}
+
+// nextSequence numbers a mutation. It must be called with the shard of the mutation locked
+// until the mutation is stored: a reader that loads the sequence and then locks the shard
+// waits for every mutation numbered up to it.
+func (m *Manager) nextSequence() uint64 {
+	return m.sequence.Add(1)
+}
+
+// restoreSequence continues the sequence from the largest number in the loaded data or the
+// high-water mark loaded with it, whichever is larger, so a restart never numbers new mutations
+// below ones handed out before it, even when deletes or the reaper removed their values.
+func (m *Manager) restoreSequence() {
+	latest := m.sequence.Load()
+	for _, s := range m.shardMap {
+		s.mutex.RLock()
+		for _, r := range s.data {
+			for _, f := range r.families {
+				for _, values := range f.qualifiers {
+					for _, v := range values {
+						latest = max(latest, v.Seq)
+					}
+				}
+			}
+		}
+		s.mutex.RUnlock()
+	}
+	m.sequence.Store(latest)
+}
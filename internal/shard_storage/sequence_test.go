@@ -0,0 +1,58 @@
+package shard_storage
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManager_Sequence(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	req.Zero(m.Sequence())
+
+	now := time.Now().UnixNano()
+	req.NoError(m.Apply("champ:1", "main", []string{"name", "title"},
+		[][]byte{[]byte("Ahri"), []byte("Fox")}, now, 0))
+	req.NoError(m.Apply("champ:2", "main", []string{"name"}, [][]byte{[]byte("Zed")}, now, 0))
+	req.NoError(m.Delete("champ:1", "main", []string{"title"}, now+1, now+int64(time.Hour)))
+	req.Equal(uint64(3), m.Sequence())
+
+	// every value of a mutation shares its number
+	row, ok := m.GetRowByFamily("champ:1", "main")
+	req.True(ok)
+	family := (*row)["champ:1"]["main"]
+	req.Equal(uint64(1), family["name"][0].Seq)
+	req.Equal(uint64(3), family["title"][0].Seq)
+	req.True(family["title"][0].IsTombstone)
+	req.Equal(uint64(1), family["title"][1].Seq)
+
+	// a restart continues from the numbers in the backup
+	req.NoError(m.Flush())
+	restarted, _, err := New(&Config{
+		RootDir:        m.rootDir,
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+	})
+	req.NoError(err)
+	req.NoError(restarted.loadFromLatestBackup())
+	restarted.restoreSequence()
+	req.Equal(uint64(3), restarted.Sequence())
+
+	// the numbers of values reaped before a restart are not handed out again
+	req.NoError(restarted.Apply("champ:3", "main", []string{"name"}, [][]byte{[]byte("Jinx")},
+		now+2, 0))
+	restarted.DeleteRowFamily("champ:3", "main")
+	req.NoError(restarted.Flush())
+	again, _, err := New(&Config{
+		RootDir:        m.rootDir,
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+	})
+	req.NoError(err)
+	req.NoError(again.loadFromLatestBackup())
+	again.restoreSequence()
+	req.Equal(uint64(4), again.Sequence())
+}
@@ -21,16 +21,15 @@ package shard_storage
 
 import (
 	"fmt"
-	"github.com/litetable/litetable-db/internal/litetable"
 	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
-// shard is a manager for a single shard of in-memory litetable.Data.
+// shard is a manager for a single shard of in-memory rows.
 type shard struct {
-	data  litetable.Data
+	data  map[string]*row
 	mutex sync.RWMutex
 
 	// there should always be some degree of randomness to the backup timer to prevent all shards
@@ -38,7 +37,15 @@ type shard struct {
 	backupTimer time.Duration
 
 	// each shard must monitor their own changes for the snapshot
-	changedRows map[string]map[string]struct{} // initialized when first row is marked
+	changes changeJournal
+	// changeCount counts the changes, so the read view is only copied again after one
+	changeCount atomic.Uint64
+	// view is the copy of the rows read without the lock by eventually consistent reads
+	view atomic.Pointer[shardView]
+
+	// usage is the space the rows of the shard take, in total and by family
+	usage       usage
+	familyUsage map[string]usage
 
 	// Track if this shard has been initialized with data
 	initialized atomic.Bool
@@ -59,9 +66,8 @@ func initializeDataShards(cfg *shardConfig) ([]*shard, error) {
 	for i := 0; i < cfg.count; i++ {
 		// Create a new shard with default values
 		shards[i] = &shard{
-			data:        make(litetable.Data),
-			mutex:       sync.RWMutex{},
-			changedRows: make(map[string]map[string]struct{}),
+			data:  make(map[string]*row),
+			mutex: sync.RWMutex{},
 
 			// Add small random jitter to backup timers to prevent all shards
 			// from backing up simultaneously (between 0-500ms)
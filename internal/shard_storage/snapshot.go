@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/litetable/litetable-db/internal/litetable"
-	"github.com/rs/zerolog/log"
 	"os"
 	"path/filepath"
 	"sort"
@@ -21,43 +20,62 @@ type directSnapshotData struct {
 	Version           int                                                `json:"version"`
 	SnapshotTimestamp int64                                              `json:"snapshotTimestamp"`
 	SnapshotData      map[string]map[string]litetable.VersionedQualifier `json:"snapshotData"`
+	// Epoch orders the snapshots taken by one process; every change before the epoch's barrier
+	// is in the snapshot and none after it.
+	Epoch uint64 `json:"epoch"`
+	// Sequence is the sequence number of the latest mutation when the snapshot was taken. It is
+	// kept as the high-water mark of the sequence, since the data may no longer hold it once
+	// rows are deleted or reaped.
+	Sequence uint64 `json:"sequence,omitempty"`
 }
 
 // createDirectSnapshot creates a new snapshot of changed rows directly from memory
 // without any complex merging logic
 func (m *Manager) createDirectSnapshot() error {
+	faults.delayLock("snapshot")
+	m.persistMutex.Lock()
+	defer m.persistMutex.Unlock()
+
+	// the changed rows stay marked for the next snapshot
+	if err := m.disk.Check("snapshot"); err != nil {
+		return err
+	}
+
 	start := time.Now()
 
+	// Hold off every change to the shards until the changed rows are copied, so the snapshot is
+	// a single point in time rather than each shard at the moment it was copied. The pause is
+	// proportional to the number of rows changed since the last snapshot.
+	m.barrier.Lock()
+
+	// Take the changed rows so writes made while the snapshot is written are kept for the next one
+	changedRowsCopy := m.takeChanges()
+	// every change numbered up to durable is in this snapshot or an earlier one
+	durable := m.Sequence()
+
 	// Skip if nothing to do
-	if len(m.changedRows) == 0 {
-		log.Debug().Msg("no changes to snapshot")
+	if len(changedRowsCopy) == 0 {
+		m.barrier.Unlock()
+		m.logger.Debug().Msg("no changes to snapshot")
+		m.releaseEvents(durable)
 		return nil
 	}
 
+	m.epoch++
 	snapshotTime := time.Now().UnixNano()
-	log.Info().Msgf("creating direct snapshot: %d", snapshotTime)
+	m.logger.Info().Uint64("epoch", m.epoch).Msgf("creating direct snapshot: %d", snapshotTime)
 
 	// Create snapshot data
 	snapshot := &directSnapshotData{
-		Version:           1,
+		Version:           SnapshotFormatVersion,
+		Epoch:             m.epoch,
+		Sequence:          durable,
 		SnapshotTimestamp: snapshotTime,
 		SnapshotData:      make(map[string]map[string]litetable.VersionedQualifier),
 	}
 
-	// Lock the mutex around working with the changed rows
-	m.mutex.RLock()
-	// Make a copy of changed rows so we can release the lock quickly
-	changedRowsCopy := make(map[string]map[string]struct{}, len(m.changedRows))
-	for rowKey, families := range m.changedRows {
-		familiesCopy := make(map[string]struct{}, len(families))
-		for family := range families {
-			familiesCopy[family] = struct{}{}
-		}
-		changedRowsCopy[rowKey] = familiesCopy
-	}
-	m.mutex.RUnlock()
-
-	// Process each changed row by doing a direct copy from memory
+	// Process each changed row by copying its qualifiers from memory. The value slices are
+	// copy-on-write, so the snapshot references them rather than copying the values.
 	for rowKey, changedFamilies := range changedRowsCopy {
 		// Determine which shard this row belongs to
 		shardIdx := m.getShardIndex(rowKey)
@@ -73,37 +91,32 @@ func (m *Manager) createDirectSnapshot() error {
 			// we need to ensure it's deleted from the backup too
 			sh.mutex.RUnlock()
 			snapshot.SnapshotData[rowKey] = nil // null marker indicates deletion
-			log.Debug().Msgf("row %s marked as deleted in snapshot", rowKey)
+			m.logger.Debug().Msgf("row %s marked as deleted in snapshot", rowKey)
 			continue
 		}
 
-		// Create a deep copy of the row data
+		// Copy the qualifiers of the changed families
 		snapshotRow := make(map[string]litetable.VersionedQualifier)
 
 		for familyName := range changedFamilies {
-			family, exists := row[familyName]
+			family, exists := row.family(familyName)
 			if !exists {
 				// Family doesn't exist but was marked as changed - it was deleted
 				snapshotRow[familyName] = nil
-				log.Debug().Msgf("family %s marked as deleted in row %s", familyName, rowKey)
+				m.logger.Debug().Msgf("family %s marked as deleted in row %s", familyName, rowKey)
 				continue
 			}
 
-			// Deep copy the family data
 			familyCopy := make(litetable.VersionedQualifier)
 			for qualifier, values := range family {
 				// Skip tombstone qualifiers when their expiration time has passed,
 				// This is cleanup for any qualifier that is deleted. We want to make sure to
 				// reclaim that space in the backup.
-				if len(values) > 0 && values[0].IsTombstone && values[0].ExpiresAt <= time.Now().
-					UnixNano() {
+				if len(values) > 0 && values[0].IsTombstone && values[0].ExpiresAt <= snapshotTime {
 					continue
 				}
 
-				// Deep copy the values
-				valuesCopy := make([]litetable.TimestampedValue, len(values))
-				copy(valuesCopy, values)
-				familyCopy[qualifier] = valuesCopy
+				familyCopy[qualifier] = values
 			}
 
 			snapshotRow[familyName] = familyCopy
@@ -112,122 +125,196 @@ func (m *Manager) createDirectSnapshot() error {
 		snapshot.SnapshotData[rowKey] = snapshotRow
 		sh.mutex.RUnlock()
 	}
+	m.barrier.Unlock()
 
 	// Serialize and save to disk
 	filename := filepath.Join(m.snapshotDir, fmt.Sprintf("%s-%d.db", snapshotPrefix, snapshotTime))
 	dataBytes, err := json.Marshal(snapshot)
 	if err != nil {
+		m.restoreChangedRows(changedRowsCopy)
 		return fmt.Errorf("failed to serialize direct snapshot: %w", err)
 	}
 
-	if err = os.WriteFile(filename, dataBytes, 0644); err != nil {
+	faultPoint("snapshot_before_write")
+	if err = writeDataFile(filename, m.writeThrottle, dataBytes); err != nil {
+		m.restoreChangedRows(changedRowsCopy)
 		return fmt.Errorf("failed to write direct snapshot file: %w", err)
 	}
+	faults.corruptSnapshot(filename)
+	faultPoint("snapshot_after_write")
 
-	// Reset changed rows tracking
-	m.mutex.Lock()
-	m.changedRows = make(map[string]map[string]struct{})
-	m.mutex.Unlock()
-
-	log.Info().Str("duration", time.Since(start).String()).Msgf("Direct snapshot saved to %s", filename)
+	m.logger.Info().Str("duration", time.Since(start).String()).Msgf("Direct snapshot saved to %s", filename)
+	m.releaseEvents(durable)
 	return nil
 }
 
+// restoreChangedRows marks the rows of a failed snapshot as changed again, so they are picked up
+// by the next one.
+func (m *Manager) restoreChangedRows(rows map[string]map[string]struct{}) {
+	for rowKey, families := range rows {
+		for family := range families {
+			m.MarkRowChanged(family, rowKey)
+		}
+	}
+}
+
 // ApplyDirectSnapshots applies all direct snapshots to the main backup file
 func (m *Manager) ApplyDirectSnapshots() error {
+	faults.delayLock("backup")
+	m.persistMutex.Lock()
+	defer m.persistMutex.Unlock()
+
+	// the snapshots are merged once there is space for the backup
+	if err := m.disk.Check("backup"); err != nil {
+		return err
+	}
+
 	start := time.Now()
 
 	// Find all snapshot files
-	snapshotFiles, err := filepath.Glob(filepath.Join(m.snapshotDir, snapshotFileGlob))
+	snapshotFiles, err := m.snapshotFiles()
 	if err != nil {
-		return fmt.Errorf("failed to list direct snapshot files: %w", err)
+		return err
 	}
 
 	if len(snapshotFiles) == 0 {
-		log.Debug().Msg("no direct snapshots to apply")
+		m.logger.Debug().Msg("no direct snapshots to apply")
 		return nil
 	}
 
-	// Sort files by name (which includes timestamp) to process in order
-	sort.Strings(snapshotFiles)
-
-	// Load current backup
-	backup, err := m.loadLatestBackup()
-	if err != nil {
-		log.Warn().Err(err).Msg("couldn't load existing backup, starting fresh")
-		backup = make(litetable.Data)
-	}
-
-	// Apply each snapshot in order
-	snapshotsApplied := 0
+	// Read every snapshot, in order
+	var covered snapshotRange
+	changes := make([]rowChanges, 0, len(snapshotFiles))
 	rowsModified := 0
-
 	for _, file := range snapshotFiles {
-		data, err := os.ReadFile(file)
+		snapshot, err := readSnapshot(file)
 		if err != nil {
-			return fmt.Errorf("failed to read snapshot %s: %w", file, err)
+			return err
 		}
-
-		var snapshot directSnapshotData
-		if err := json.Unmarshal(data, &snapshot); err != nil {
-			return fmt.Errorf("failed to parse snapshot %s: %w", file, err)
+		if covered.first == 0 || snapshot.SnapshotTimestamp < covered.first {
+			covered.first = snapshot.SnapshotTimestamp
 		}
+		if snapshot.SnapshotTimestamp > covered.last {
+			covered.last = snapshot.SnapshotTimestamp
+		}
+		covered.sequence = max(covered.sequence, snapshot.Sequence)
 
-		// Apply changes from this snapshot
-		for rowKey, rowData := range snapshot.SnapshotData {
-			if rowData == nil {
-				// Explicit deletion marker
-				delete(backup, rowKey)
-				rowsModified++
-				log.Debug().Msgf("deleted row %s from backup", rowKey)
-				continue
-			}
-
-			// Update or create row
-			if _, exists := backup[rowKey]; !exists {
-				backup[rowKey] = make(map[string]litetable.VersionedQualifier)
-			}
+		changes = append(changes, snapshot.SnapshotData)
+		rowsModified += len(snapshot.SnapshotData)
+	}
 
-			for familyName, qualifiers := range rowData {
-				if qualifiers == nil {
-					// Family deletion marker
-					delete(backup[rowKey], familyName)
-					log.Debug().Msgf("deleted family %s from row %s in backup", familyName, rowKey)
-				} else {
-					// Replace family data with snapshot data
-					backup[rowKey][familyName] = qualifiers
-				}
-			}
+	// Between full backups, only the changes are written, as a delta on the latest backup
+	base, deltas, err := m.latestDeltaChain()
+	if err != nil {
+		return err
+	}
 
-			// Clean up empty row if needed
-			if len(backup[rowKey]) == 0 {
-				delete(backup, rowKey)
-				log.Debug().Msgf("row %s became empty and was removed from backup", rowKey)
-			}
+	// a full backup that is due waits for a maintenance window, unless there is nothing to write
+	// a delta on
+	full := base == "" ||
+		len(deltas)+1 >= m.fullBackupInterval && m.maintenance.Open(time.Now())
+	if !full {
+		if err = m.saveDelta(base, changes, covered); err != nil {
+			return fmt.Errorf("failed to save delta backup after applying snapshots: %w", err)
+		}
+	} else {
+		// Load current backup, including its deltas
+		backup, err := m.loadLatestBackup()
+		if err != nil {
+			m.logger.Warn().Err(err).Msg("couldn't load existing backup, starting fresh")
+			backup = make(litetable.Data)
+		} else if len(deltas) > 0 {
+			// the new backup also covers the snapshots merged into the deltas
+			covered.first = deltas[0].FirstSnapshot
+		}
 
-			rowsModified++
+		for _, c := range changes {
+			m.applyChanges(backup, c)
 		}
 
-		snapshotsApplied++
+		// Save updated backup
+		if err := m.saveBackup(&backup, covered); err != nil {
+			return fmt.Errorf("failed to save backup after applying snapshots: %w", err)
+		}
 	}
 
-	// Save updated backup
-	if err := m.saveBackup(&backup); err != nil {
-		return fmt.Errorf("failed to save backup after applying snapshots: %w", err)
-	}
+	faultPoint("backup_before_cleanup")
 
 	// Clean up processed snapshot files
 	for _, file := range snapshotFiles {
-		if err := os.Remove(file); err != nil {
-			log.Error().Err(err).Msgf("failed to remove processed snapshot: %s", file)
+		if err := removeDataFile(file); err != nil {
+			m.logger.Error().Err(err).Msgf("failed to remove processed snapshot: %s", file)
 		}
 	}
 
-	log.Info().
+	m.logger.Info().
 		Str("duration", time.Since(start).String()).
-		Int("snapshots_applied", snapshotsApplied).
+		Int("snapshots_applied", len(snapshotFiles)).
 		Int("rows_modified", rowsModified).
 		Msg("applied direct snapshots to backup")
 
 	return nil
 }
+
+// snapshotFiles returns the snapshots waiting to be merged into a backup, oldest first.
+func (m *Manager) snapshotFiles() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(m.snapshotDir, snapshotFileGlob))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list direct snapshot files: %w", err)
+	}
+	// the names hold the snapshot timestamps
+	sort.Strings(files)
+	return files, nil
+}
+
+// readSnapshot reads and parses a snapshot file.
+func readSnapshot(file string) (*directSnapshotData, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", file, err)
+	}
+
+	var snapshot directSnapshotData
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", file, err)
+	}
+	return &snapshot, nil
+}
+
+// rowChanges are the changes in a snapshot: a nil row or family marks a deletion, and any other
+// family replaces the family in the backup.
+type rowChanges map[string]map[string]litetable.VersionedQualifier
+
+// applyChanges applies the changes from a snapshot to a backup.
+func (m *Manager) applyChanges(backup litetable.Data, changes rowChanges) {
+	for rowKey, rowData := range changes {
+		if rowData == nil {
+			// Explicit deletion marker
+			delete(backup, rowKey)
+			m.logger.Debug().Msgf("deleted row %s from backup", rowKey)
+			continue
+		}
+
+		// Update or create row
+		if _, exists := backup[rowKey]; !exists {
+			backup[rowKey] = make(map[string]litetable.VersionedQualifier)
+		}
+
+		for familyName, qualifiers := range rowData {
+			if qualifiers == nil {
+				// Family deletion marker
+				delete(backup[rowKey], familyName)
+				m.logger.Debug().Msgf("deleted family %s from row %s in backup", familyName, rowKey)
+			} else {
+				// Replace family data with snapshot data
+				backup[rowKey][familyName] = qualifiers
+			}
+		}
+
+		// Clean up empty row if needed
+		if len(backup[rowKey]) == 0 {
+			delete(backup, rowKey)
+			m.logger.Debug().Msgf("row %s became empty and was removed from backup", rowKey)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package shard_storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManager_createDirectSnapshot_epoch(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	// nothing changed, so no snapshot and no new epoch
+	req.NoError(m.createDirectSnapshot())
+	req.Zero(m.epoch)
+
+	for i, key := range []string{"champ:1", "champ:2"} {
+		req.NoError(m.Apply(key, "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+			time.Now().UnixNano(), 0))
+		req.NoError(m.createDirectSnapshot())
+		req.Equal(uint64(i+1), m.epoch)
+	}
+
+	files, err := filepath.Glob(filepath.Join(m.snapshotDir, snapshotFileGlob))
+	req.NoError(err)
+	req.Len(files, 2)
+
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		req.NoError(err)
+
+		var snapshot directSnapshotData
+		req.NoError(json.Unmarshal(data, &snapshot))
+		req.Equal(uint64(i+1), snapshot.Epoch)
+		req.Len(snapshot.SnapshotData, 1)
+	}
+}
+
+func TestManager_barrier(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	// a snapshot holding the barrier keeps writes out until the copy is done
+	m.barrier.Lock()
+	done := make(chan error, 1)
+	go func() {
+		done <- m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+			time.Now().UnixNano(), 0)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("write completed while the snapshot barrier was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.barrier.Unlock()
+	select {
+	case err := <-done:
+		req.NoError(err)
+	case <-time.After(time.Second):
+		t.Fatal("write did not complete after the barrier was released")
+	}
+	req.Contains(m.takeChanges(), "champ:1")
+}
+
+func BenchmarkManager_createDirectSnapshot(b *testing.B) {
+	m := newTestManager(b)
+	now := time.Now().UnixNano()
+	for i := 0; i < 1000; i++ {
+		for v := int64(0); v < 3; v++ {
+			require.NoError(b, m.Apply(fmt.Sprintf("champ:%d", i), "main",
+				[]string{"name", "role", "lane"},
+				[][]byte{[]byte("Ahri"), []byte("mage"), []byte("mid")}, now+v, 0))
+		}
+	}
+	changed := m.takeChanges()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.restoreChangedRows(changed)
+		require.NoError(b, m.createDirectSnapshot())
+	}
+}
+
+func TestManager_loadFromLatestBackup_snapshots(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	now := time.Now().UnixNano()
+
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")}, now, 0))
+	req.NoError(m.Apply("champ:2", "main", []string{"name"}, [][]byte{[]byte("Annie")}, now, 0))
+	req.NoError(m.Flush())
+
+	// a crash after these snapshots are saved leaves them unmerged
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Akali")}, now+1,
+		0))
+	req.NoError(m.createDirectSnapshot())
+	req.NoError(m.Apply("champ:3", "main", []string{"name"}, [][]byte{[]byte("Ashe")}, now+2, 0))
+	req.NoError(m.Delete("champ:2", "main", nil, now+2, now+int64(time.Hour)))
+	req.NoError(m.createDirectSnapshot())
+
+	restarted, _, err := New(&Config{
+		RootDir:        m.rootDir,
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+	})
+	req.NoError(err)
+	req.NoError(restarted.loadFromLatestBackup())
+
+	row, ok := restarted.GetRowByFamily("champ:1", "main")
+	req.True(ok)
+	req.Len((*row)["champ:1"]["main"]["name"], 2)
+	req.Equal([]byte("Akali"), (*row)["champ:1"]["main"]["name"][1].Value)
+	_, ok = restarted.GetRowByFamily("champ:3", "main")
+	req.True(ok)
+	row, ok = restarted.GetRowByFamily("champ:2", "main")
+	req.True(ok)
+	req.True((*row)["champ:2"]["main"]["name"][0].IsTombstone)
+}
@@ -0,0 +1,63 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"maps"
+	"slices"
+	"time"
+)
+
+// defaultStatsInterval is the time between runs of the stats job.
+const defaultStatsInterval = 10 * time.Minute
+
+// cellOverhead estimates the bytes a cell takes beside its value and names: its timestamp,
+// expiry, sequence number, tombstone flag and value slice header.
+const cellOverhead = 56
+
+// tableStats are the family stats from one run of the stats job.
+type tableStats struct {
+	families map[string]litetable.FamilyStats
+	at       time.Time
+}
+
+// computeStats walks every shard and replaces the family stats. Each shard is only read locked
+// while it is walked, so the stats are not of a single point in time, which capacity planning
+// does not need.
+func (m *Manager) computeStats() {
+	families := make(map[string]litetable.FamilyStats)
+	for _, s := range m.shardMap {
+		s.mutex.RLock()
+		for key, r := range s.data {
+			for _, f := range r.families {
+				stats, ok := families[f.name]
+				if !ok {
+					stats.VersionDepth = make([]int64, len(litetable.VersionDepthBounds)+1)
+				}
+				stats.Rows++
+				for qualifier, values := range f.qualifiers {
+					versions := int64(len(values))
+					stats.Qualifiers++
+					stats.Cells += versions
+					stats.Bytes += valueBytes(values) +
+						versions*int64(len(key)+len(qualifier)+cellOverhead)
+					stats.MaxVersions = max(stats.MaxVersions, versions)
+					bucket, _ := slices.BinarySearch(litetable.VersionDepthBounds, versions)
+					stats.VersionDepth[bucket]++
+					for _, v := range values {
+						if v.Compressed {
+							stats.CompressedCells++
+							stats.CompressedBytes += int64(len(v.Value))
+							stats.UncompressedBytes += uncompressedSize(v.Value)
+						}
+					}
+				}
+				families[f.name] = stats
+			}
+		}
+		s.mutex.RUnlock()
+	}
+
+	m.stats.Store(&tableStats{families: families, at: time.Now()})
+	m.logger.Debug().Strs("families", slices.Sorted(maps.Keys(families))).
+		Msg("computed family stats")
+}
@@ -0,0 +1,66 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManager_computeStats(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	req.NoError(m.UpdateFamilies([]string{"titles"}))
+
+	// no stats until the job runs
+	req.Nil(m.Usage().Stats)
+	req.True(m.Usage().StatsAt.IsZero())
+
+	now := time.Now().UnixNano()
+	write := func(key, family, qualifier, value string) {
+		req.NoError(m.Apply(key, family, []string{qualifier}, [][]byte{[]byte(value)}, now, 0))
+	}
+	// champ:1 name has 3 versions, every other qualifier has 1
+	write("champ:1", "main", "name", "Ahri")
+	write("champ:1", "main", "name", "Zed")
+	write("champ:1", "main", "name", "Lux")
+	write("champ:1", "main", "role", "mid")
+	write("champ:2", "main", "name", "Jinx")
+	write("champ:2", "titles", "wins", "16")
+
+	m.computeStats()
+	usage := m.Usage()
+	req.False(usage.StatsAt.IsZero())
+
+	main := usage.Stats["main"]
+	req.Equal(int64(2), main.Rows)
+	req.Equal(int64(3), main.Qualifiers)
+	req.Equal(int64(5), main.Cells)
+	req.Equal(int64(3), main.MaxVersions)
+	// two qualifiers with 1 version and one with 3, in the bucket of up to 4
+	req.Equal([]int64{2, 0, 1, 0, 0, 0, 0, 0}, main.VersionDepth)
+	valueBytes := int64(len("AhriZedLuxmidJinx"))
+	cellBytes := int64(3*len("champ:1name") + len("champ:1role") + len("champ:2name"))
+	req.Equal(valueBytes+cellBytes+5*cellOverhead, main.Bytes)
+
+	titles := usage.Stats["titles"]
+	req.Equal(int64(1), titles.Rows)
+	req.Equal(int64(1), titles.Cells)
+}
+
+func TestManager_computeStats_deepVersions(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	now := time.Now().UnixNano()
+	for i := range 100 {
+		req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+			now+int64(i), 0))
+	}
+
+	m.computeStats()
+	main := m.Usage().Stats["main"]
+	req.Equal(int64(100), main.MaxVersions)
+	// more versions than the last bound fall in the last bucket
+	req.Equal(int64(1), main.VersionDepth[len(litetable.VersionDepthBounds)])
+}
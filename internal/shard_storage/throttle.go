@@ -0,0 +1,90 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/metrics"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	backgroundWriteBytes = metrics.NewCounter("litetable_background_write_bytes_total",
+		"Bytes written to snapshot, backup and delta files.")
+	backgroundWriteThrottled = metrics.NewCounter(
+		"litetable_background_write_throttled_seconds_total",
+		"Time snapshot, backup and delta writes waited for the background write budget.")
+)
+
+// WriteThrottle is a token bucket bounding the bytes per second written to snapshot, backup and
+// delta files, so background persistence does not saturate the disk and slow down foreground
+// writes. One throttle can be shared by every table on a disk. A nil WriteThrottle, or one with
+// a rate of 0, does not throttle.
+type WriteThrottle struct {
+	mutex sync.Mutex
+	// rate is the budget in bytes per second, which is also the most the bucket holds
+	rate int64
+	// tokens are the bytes that can be written without waiting. They go negative when a write
+	// takes more than the bucket holds, and later writes wait for the debt to be paid.
+	tokens float64
+	last   time.Time
+}
+
+// NewWriteThrottle creates a throttle with a budget of bytesPerSecond. 0 is unlimited.
+func NewWriteThrottle(bytesPerSecond int64) *WriteThrottle {
+	t := &WriteThrottle{}
+	t.SetRate(bytesPerSecond)
+	return t
+}
+
+// SetRate changes the budget, in bytes per second, and refills the bucket. 0 is unlimited.
+func (t *WriteThrottle) SetRate(bytesPerSecond int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rate = max(bytesPerSecond, 0)
+	t.tokens = float64(t.rate)
+	t.last = time.Now()
+}
+
+// wait takes n bytes from the budget and sleeps until the budget covers them.
+func (t *WriteThrottle) wait(n int) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	if t.rate == 0 {
+		t.mutex.Unlock()
+		return
+	}
+	now := time.Now()
+	t.tokens = min(t.tokens+now.Sub(t.last).Seconds()*float64(t.rate), float64(t.rate))
+	t.last = now
+	t.tokens -= float64(n)
+	var delay time.Duration
+	if t.tokens < 0 {
+		delay = time.Duration(-t.tokens / float64(t.rate) * float64(time.Second))
+	}
+	t.mutex.Unlock()
+
+	if delay > 0 {
+		backgroundWriteThrottled.Add(delay.Seconds())
+		time.Sleep(delay)
+	}
+}
+
+// writer returns w with every write taken from the budget.
+func (t *WriteThrottle) writer(w io.Writer) io.Writer {
+	return &throttledWriter{w: w, throttle: t}
+}
+
+type throttledWriter struct {
+	w        io.Writer
+	throttle *WriteThrottle
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	w.throttle.wait(len(p))
+	n, err := w.w.Write(p)
+	backgroundWriteBytes.Add(float64(n))
+	return n, err
+}
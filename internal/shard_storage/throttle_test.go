@@ -0,0 +1,56 @@
+package shard_storage
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestWriteThrottle(t *testing.T) {
+	tests := map[string]struct {
+		throttle *WriteThrottle
+		// minDelay is the least time writing 150KB takes
+		minDelay time.Duration
+	}{
+		"nil":       {throttle: nil},
+		"unlimited": {throttle: NewWriteThrottle(0)},
+		// the bucket holds one second of budget, so only the rest of the write waits
+		"100KB per second": {
+			throttle: NewWriteThrottle(100_000),
+			minDelay: 400 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			var out bytes.Buffer
+			w := tc.throttle.writer(&out)
+
+			start := time.Now()
+			for range 3 {
+				n, err := w.Write(make([]byte, 50_000))
+				req.NoError(err)
+				req.Equal(50_000, n)
+			}
+			elapsed := time.Since(start)
+
+			req.Equal(150_000, out.Len())
+			req.GreaterOrEqual(elapsed, tc.minDelay)
+			if tc.minDelay == 0 {
+				req.Less(elapsed, 100*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestWriteThrottle_SetRate(t *testing.T) {
+	throttle := NewWriteThrottle(1000)
+	// lifting the budget lets a write far over the old one through at once
+	throttle.SetRate(0)
+
+	start := time.Now()
+	throttle.wait(1_000_000)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+}
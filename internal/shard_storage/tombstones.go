@@ -0,0 +1,82 @@
+package shard_storage
+
+import (
+	"cmp"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/shard_storage/reaper"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Tombstones returns the tombstones that have not expired on rows starting with prefix, sorted by
+// row key, family and qualifier, newest first. At most limit are returned, or all of them if limit
+// is not positive, with whether any were left out. Each tombstone is checked against the pending
+// entries of the reaper, so operators can see what will be collected.
+func (m *Manager) Tombstones(prefix string, limit int) ([]litetable.Tombstone, bool) {
+	pending, err := m.reaper.Pending()
+	if err != nil {
+		// the entries in memory are still listed; the error only means the GC log is behind
+		m.logger.Error().Err(err).Msg("failed to drain reaper queue")
+	}
+	// index the tombstone entries by row; entries for values written with a TTL collect nothing
+	scheduled := make(map[string][]reaper.ReapParams)
+	for _, p := range pending {
+		if !p.Expiry && strings.HasPrefix(p.RowKey, prefix) {
+			scheduled[p.RowKey] = append(scheduled[p.RowKey], p)
+		}
+	}
+
+	now := time.Now().UnixNano()
+	var tombstones []litetable.Tombstone
+	for _, s := range m.shardMap {
+		s.mutex.RLock()
+		for key, r := range s.data {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			for _, f := range r.families {
+				for qualifier, values := range f.qualifiers {
+					for _, v := range values {
+						if !v.IsTombstone || v.ExpiresAt <= now {
+							continue
+						}
+						t := litetable.Tombstone{
+							RowKey:    key,
+							Family:    f.name,
+							Qualifier: qualifier,
+							Timestamp: v.Timestamp,
+							ExpiresAt: v.ExpiresAt,
+						}
+						t.Scheduled = isScheduled(scheduled[key], t)
+						tombstones = append(tombstones, t)
+					}
+				}
+			}
+		}
+		s.mutex.RUnlock()
+	}
+
+	slices.SortFunc(tombstones, func(a, b litetable.Tombstone) int {
+		return cmp.Or(
+			strings.Compare(a.RowKey, b.RowKey),
+			strings.Compare(a.Family, b.Family),
+			strings.Compare(a.Qualifier, b.Qualifier),
+			cmp.Compare(b.Timestamp, a.Timestamp),
+		)
+	})
+	if limit > 0 && len(tombstones) > limit {
+		return tombstones[:limit], true
+	}
+	return tombstones, false
+}
+
+// isScheduled reports whether one of the reap entries of a row collects a tombstone. An entry
+// without a family deletes the whole row, and one without qualifiers the whole family.
+func isScheduled(entries []reaper.ReapParams, t litetable.Tombstone) bool {
+	return slices.ContainsFunc(entries, func(p reaper.ReapParams) bool {
+		return p.Timestamp == t.Timestamp &&
+			(p.Family == "" || p.Family == t.Family) &&
+			(len(p.Qualifiers) == 0 || slices.Contains(p.Qualifiers, t.Qualifier))
+	})
+}
@@ -0,0 +1,50 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManager_Tombstones(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	now := time.Now().UnixNano()
+	expiresAt := now + int64(time.Hour)
+	for _, key := range []string{"champ:1", "champ:2", "item:1"} {
+		req.NoError(m.Apply(key, "main", []string{"name", "title"},
+			[][]byte{[]byte("Ahri"), []byte("Fox")}, now, 0))
+	}
+
+	req.NoError(m.Delete("champ:1", "main", []string{"name"}, now+10, expiresAt))
+	// an expired tombstone is left to the reaper
+	req.NoError(m.Delete("champ:1", "main", []string{"title"}, now+10, now-1))
+	req.NoError(m.Delete("champ:2", "main", nil, now+20, expiresAt))
+	req.NoError(m.Delete("item:1", "main", nil, now+30, expiresAt))
+
+	tombstones, more := m.Tombstones("champ", 0)
+	req.False(more)
+	req.Equal([]litetable.Tombstone{
+		{RowKey: "champ:1", Family: "main", Qualifier: "name", Timestamp: now + 10,
+			ExpiresAt: expiresAt, Scheduled: true},
+		{RowKey: "champ:2", Family: "main", Qualifier: "name", Timestamp: now + 20,
+			ExpiresAt: expiresAt, Scheduled: true},
+		{RowKey: "champ:2", Family: "main", Qualifier: "title", Timestamp: now + 20,
+			ExpiresAt: expiresAt, Scheduled: true},
+	}, tombstones)
+
+	tombstones, more = m.Tombstones("", 2)
+	req.True(more)
+	req.Len(tombstones, 2)
+	req.Equal("champ:1", tombstones[0].RowKey)
+
+	// tombstones without a reap entry are listed as unscheduled
+	m.reaper = &fakeReaper{}
+	tombstones, more = m.Tombstones("item", 0)
+	req.False(more)
+	req.Len(tombstones, 2)
+	req.False(tombstones[0].Scheduled)
+	req.False(tombstones[1].Scheduled)
+}
@@ -0,0 +1,166 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"slices"
+)
+
+// Commit applies the mutations of a transaction at once, unless a row the transaction read or
+// changes was changed by a mutation numbered after readAt.
+//
+// Commit is a two-phase apply: it locks every shard the transaction touches, in index order so
+// two commits cannot deadlock, then checks the rows, families and quotas before changing
+// anything. Every mutation is numbered with the same sequence number, so a read sees all of the
+// transaction or none of it. It returns that number.
+//
+// accepted, when set, is called with the shards still locked once every check has passed and
+// before anything is applied, so the caller can log only the transactions that commit. Its
+// error fails the commit.
+func (m *Manager) Commit(readAt uint64, reads []string, mutations []litetable.Mutation,
+	accepted func() error) (uint64, error) {
+	stored := make([]storedWrite, len(mutations))
+	written := make(map[string]int64)
+	for i, mutation := range mutations {
+		if mutation.Operation == litetable.OperationWrite {
+			if !m.IsFamilyAllowed(mutation.Family) {
+				return 0, litetable.NewError(litetable.ErrorCodeFamilyMissing,
+					"column family not allowed: %s", mutation.Family)
+			}
+			mutations[i].ExpiresAt = m.ExpiresAt(mutation.Family, mutation.Timestamp,
+				mutation.ExpiresAt)
+			values, err := m.familyPolicies[mutation.Family].encode(mutation.Family,
+				mutation.Qualifiers, mutation.Values)
+			if err != nil {
+				return 0, err
+			}
+			mutations[i].Values = values
+			stored[i] = m.prepareWrite(mutation.Family, values)
+			written[mutation.Family] += stored[i].bytes
+		}
+	}
+
+	m.barrier.RLock()
+	defer m.barrier.RUnlock()
+
+	// the usage of the shards is summed before they are locked, like in Apply
+	used, familyUsed := m.transactionUsage(written)
+
+	rowKeys := slices.Clone(reads)
+	for _, mutation := range mutations {
+		rowKeys = append(rowKeys, mutation.RowKey)
+	}
+	var shards []int
+	for _, key := range rowKeys {
+		shards = append(shards, m.getShardIndex(key))
+	}
+	slices.Sort(shards)
+	shards = slices.Compact(shards)
+	for _, i := range shards {
+		m.shardMap[i].mutex.Lock()
+		defer m.shardMap[i].mutex.Unlock()
+	}
+
+	// phase one: nothing is changed unless every check passes
+	for _, key := range rowKeys {
+		if r, exists := m.shardMap[m.getShardIndex(key)].data[key]; exists &&
+			r.changedAfter(readAt) {
+			return 0, litetable.NewError(litetable.ErrorCodeConflict,
+				"row %s changed after sequence %d", key, readAt)
+		}
+	}
+	if err := m.checkTransaction(mutations, stored, used, familyUsed); err != nil {
+		return 0, err
+	}
+	if accepted != nil {
+		if err := accepted(); err != nil {
+			return 0, err
+		}
+	}
+
+	// phase two: apply every mutation
+	seq := m.nextSequence()
+	for i, mutation := range mutations {
+		s := m.shardMap[m.getShardIndex(mutation.RowKey)]
+		if mutation.Operation == litetable.OperationWrite {
+			m.write(s, mutation.RowKey, mutation.Family, mutation.Qualifiers, mutation.Values,
+				stored[i], mutation.Timestamp, mutation.ExpiresAt, seq)
+			continue
+		}
+		m.tombstone(s.data[mutation.RowKey], mutation.RowKey, mutation.Family,
+			mutation.Qualifiers, mutation.Timestamp, mutation.ExpiresAt, seq)
+	}
+	return seq, nil
+}
+
+// transactionUsage sums the usage of the table and of each family written to, when they have a
+// quota.
+func (m *Manager) transactionUsage(written map[string]int64) (usage, map[string]usage) {
+	var used usage
+	familyUsed := make(map[string]usage)
+	for family := range written {
+		if !m.quota.limited() && !m.familyPolicies[family].Quota.limited() {
+			continue
+		}
+		used, familyUsed[family] = m.usageOf(family)
+	}
+	return used, familyUsed
+}
+
+// checkTransaction checks the deletes of a transaction and that its writes, as stored, fit the
+// quotas. The caller holds the lock of every shard the transaction touches.
+func (m *Manager) checkTransaction(mutations []litetable.Mutation, stored []storedWrite,
+	used usage, familyUsed map[string]usage) error {
+	var added usage
+	familyAdded := make(map[string]usage)
+	// rows and families created by earlier mutations of the transaction
+	newRows := make(map[string]bool)
+	newFamilies := make(map[string]map[string]bool)
+
+	for i, mutation := range mutations {
+		r, exists := m.shardMap[m.getShardIndex(mutation.RowKey)].data[mutation.RowKey]
+		if mutation.Operation == litetable.OperationDelete {
+			if !exists {
+				if newRows[mutation.RowKey] {
+					// tombstones on a row the transaction writes first are not worth supporting
+					return litetable.NewError(litetable.ErrorCodeInvalidArgument,
+						"row %s is written and deleted in one transaction", mutation.RowKey)
+				}
+				return litetable.NewError(litetable.ErrorCodeNotFound, "row not found: %s",
+					mutation.RowKey)
+			}
+			if err := m.checkDelete(r, mutation.RowKey, mutation.Family); err != nil {
+				return err
+			}
+			continue
+		}
+
+		bytes := stored[i].bytes
+		newRow := !exists && !newRows[mutation.RowKey]
+		newRows[mutation.RowKey] = newRows[mutation.RowKey] || !exists
+
+		hasFamily := false
+		if exists {
+			_, hasFamily = r.family(mutation.Family)
+		}
+		if newFamilies[mutation.Family] == nil {
+			newFamilies[mutation.Family] = make(map[string]bool)
+		}
+		newFamily := !hasFamily && !newFamilies[mutation.Family][mutation.RowKey]
+		newFamilies[mutation.Family][mutation.RowKey] = true
+
+		added = added.add(writeUsage(bytes, newRow))
+		familyAdded[mutation.Family] = familyAdded[mutation.Family].add(
+			writeUsage(bytes, newFamily))
+	}
+
+	if err := m.quota.check("table", used, added); err != nil {
+		return err
+	}
+	for family, familyAdded := range familyAdded {
+		quota := m.familyPolicies[family].Quota
+		if err := quota.check("family "+family, familyUsed[family], familyAdded); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestManager_Commit(t *testing.T) {
+	now := time.Now().UnixNano()
+	write := func(key, value string) litetable.Mutation {
+		return litetable.Mutation{
+			Operation:  litetable.OperationWrite,
+			RowKey:     key,
+			Family:     "main",
+			Qualifiers: []string{"name"},
+			Values:     [][]byte{[]byte(value)},
+			Timestamp:  now,
+		}
+	}
+	remove := func(key string) litetable.Mutation {
+		return litetable.Mutation{
+			Operation: litetable.OperationDelete,
+			RowKey:    key,
+			Family:    "main",
+			Timestamp: now + 1,
+			ExpiresAt: now + int64(time.Hour),
+		}
+	}
+
+	tests := map[string]struct {
+		quota Quota
+		// readAt replaces the sequence number the transaction began at
+		readAt    uint64
+		reads     []string
+		mutations []litetable.Mutation
+		// changed is written after the transaction begins
+		changed   string
+		expectErr error
+	}{
+		"writes and deletes across shards": {
+			mutations: []litetable.Mutation{
+				write("champ:2", "Zed"),
+				write("champ:3", "Lux"),
+				write("champ:4", "Jinx"),
+				remove("champ:1"),
+			},
+		},
+		"row changed after the transaction began": {
+			mutations: []litetable.Mutation{write("champ:1", "Zed")},
+			changed:   "champ:1",
+			expectErr: litetable.ErrConflict,
+		},
+		"read row changed after the transaction began": {
+			reads:     []string{"champ:9"},
+			mutations: []litetable.Mutation{write("champ:2", "Zed")},
+			changed:   "champ:9",
+			expectErr: litetable.ErrConflict,
+		},
+		"changed rows do not conflict past the last sequence": {
+			readAt:    math.MaxUint64,
+			mutations: []litetable.Mutation{write("champ:1", "Zed")},
+			changed:   "champ:1",
+		},
+		"other rows may change": {
+			reads:     []string{"champ:1"},
+			mutations: []litetable.Mutation{write("champ:2", "Zed")},
+			changed:   "champ:3",
+		},
+		"delete of a missing row": {
+			mutations: []litetable.Mutation{write("champ:2", "Zed"), remove("champ:9")},
+			expectErr: litetable.ErrNotFound,
+		},
+		"missing family": {
+			mutations: []litetable.Mutation{{
+				Operation:  litetable.OperationWrite,
+				RowKey:     "champ:2",
+				Family:     "nope",
+				Qualifiers: []string{"name"},
+				Values:     [][]byte{[]byte("Zed")},
+			}},
+			expectErr: litetable.ErrFamilyMissing,
+		},
+		"rows over the quota": {
+			quota: Quota{MaxRows: 2},
+			mutations: []litetable.Mutation{
+				write("champ:2", "Zed"),
+				write("champ:2", "Zed"),
+				write("champ:3", "Lux"),
+			},
+			expectErr: litetable.ErrExhausted,
+		},
+		"rows within the quota": {
+			quota: Quota{MaxRows: 3},
+			mutations: []litetable.Mutation{
+				write("champ:1", "Ahri"),
+				write("champ:2", "Zed"),
+				write("champ:2", "Zed"),
+				write("champ:3", "Lux"),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			m := newTestManager(t)
+			m.quota = tc.quota
+			req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+				now, 0))
+
+			readAt := m.Sequence()
+			if tc.readAt != 0 {
+				readAt = tc.readAt
+			}
+			if tc.changed != "" {
+				req.NoError(m.Apply(tc.changed, "main", []string{"name"},
+					[][]byte{[]byte("Sona")}, now, 0))
+			}
+			before := m.Sequence()
+
+			accepted := 0
+			seq, err := m.Commit(readAt, tc.reads, tc.mutations, func() error {
+				accepted++
+				return nil
+			})
+			if tc.expectErr != nil {
+				req.ErrorIs(err, tc.expectErr)
+				req.Zero(accepted)
+				// nothing was applied
+				req.Equal(before, m.Sequence())
+				_, exists := m.GetRowByFamily("champ:2", "main")
+				req.False(exists)
+				return
+			}
+			req.NoError(err)
+			req.Equal(1, accepted)
+			req.Equal(before+1, seq)
+
+			// every mutation has the sequence number of the transaction
+			for _, mutation := range tc.mutations {
+				row, ok := m.GetRowByFamily(mutation.RowKey, "main")
+				req.True(ok)
+				values := (*row)[mutation.RowKey]["main"]["name"]
+				if mutation.Operation == litetable.OperationDelete {
+					// tombstones are sorted newest first
+					req.True(values[0].IsTombstone)
+					req.Equal(seq, values[0].Seq)
+					continue
+				}
+				req.Equal(seq, values[len(values)-1].Seq)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package shard_storage
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// ValueType is the type a family policy declares for the values of a qualifier. Typed values
+// are checked when they are written and stored in a canonical form: decimal text for numbers,
+// as the sum aggregation reads them, and true or false for booleans. Undeclared qualifiers hold
+// opaque bytes.
+type ValueType string
+
+const (
+	ValueInt64   ValueType = "int64"
+	ValueFloat64 ValueType = "float64"
+	ValueBool    ValueType = "bool"
+)
+
+// ValueTypes are the types a qualifier can be declared with.
+var ValueTypes = []ValueType{ValueInt64, ValueFloat64, ValueBool}
+
+func (t ValueType) validate(family, qualifier string) error {
+	if slices.Contains(ValueTypes, t) {
+		return nil
+	}
+	return fmt.Errorf("type of qualifier %s of family %s must be one of %v, got %q", qualifier,
+		family, ValueTypes, string(t))
+}
+
+// encode returns the canonical form of a value of the type, or false when the value is not one.
+// Floats must be finite, so that sums stay numbers.
+func (t ValueType) encode(value []byte) ([]byte, bool) {
+	switch t {
+	case ValueInt64:
+		n, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		return strconv.AppendInt(nil, n, 10), true
+	case ValueFloat64:
+		f, err := strconv.ParseFloat(string(value), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, false
+		}
+		return strconv.AppendFloat(nil, f, 'g', -1, 64), true
+	case ValueBool:
+		b, err := strconv.ParseBool(string(value))
+		if err != nil {
+			return nil, false
+		}
+		return strconv.AppendBool(nil, b), true
+	default:
+		return value, true
+	}
+}
+
+// encode returns the values of a write to the family with the values of typed qualifiers in
+// their canonical form, or an INVALID_ARGUMENT error naming the first value that is not of its
+// qualifier's type. The values belong to the caller, so they are copied before any is changed.
+func (p FamilyPolicy) encode(family string, qualifiers []string,
+	values [][]byte) ([][]byte, error) {
+	if len(p.Types) == 0 {
+		return values, nil
+	}
+
+	out := values
+	copied := false
+	for i, qualifier := range qualifiers {
+		t, typed := p.Types[qualifier]
+		if !typed {
+			continue
+		}
+		encoded, ok := t.encode(values[i])
+		if !ok {
+			return nil, litetable.NewError(litetable.ErrorCodeInvalidArgument,
+				"value of qualifier %s of family %s is not a valid %s", qualifier, family, t)
+		}
+		if !copied {
+			out = slices.Clone(values)
+			copied = true
+		}
+		out[i] = encoded
+	}
+	return out, nil
+}
@@ -0,0 +1,141 @@
+package shard_storage
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestValueType_encode(t *testing.T) {
+	tests := map[string]struct {
+		valueType ValueType
+		value     string
+		want      string
+		wantOK    bool
+	}{
+		"int64":              {valueType: ValueInt64, value: "42", want: "42", wantOK: true},
+		"int64 sign":         {valueType: ValueInt64, value: "+0042", want: "42", wantOK: true},
+		"negative int64":     {valueType: ValueInt64, value: "-7", want: "-7", wantOK: true},
+		"int64 out of range": {valueType: ValueInt64, value: "9223372036854775808"},
+		"int64 fraction":     {valueType: ValueInt64, value: "1.5"},
+		"int64 with spaces":  {valueType: ValueInt64, value: " 1"},
+		"float64":            {valueType: ValueFloat64, value: "1.50", want: "1.5", wantOK: true},
+		"float64 exponent":   {valueType: ValueFloat64, value: "1e3", want: "1000", wantOK: true},
+		"float64 not finite": {valueType: ValueFloat64, value: "NaN"},
+		"float64 infinite":   {valueType: ValueFloat64, value: "+Inf"},
+		"float64 text":       {valueType: ValueFloat64, value: "one"},
+		"bool":               {valueType: ValueBool, value: "TRUE", want: "true", wantOK: true},
+		"bool digit":         {valueType: ValueBool, value: "0", want: "false", wantOK: true},
+		"bool text":          {valueType: ValueBool, value: "yes"},
+		"empty":              {valueType: ValueInt64, value: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			got, ok := tc.valueType.encode([]byte(tc.value))
+			req.Equal(tc.wantOK, ok)
+			if ok {
+				req.Equal(tc.want, string(got))
+			}
+		})
+	}
+}
+
+func newTypedManager(t *testing.T) *Manager {
+	m, _, err := New(&Config{
+		RootDir:        t.TempDir(),
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+		FamilyPolicies: map[string]FamilyPolicy{"stats": {Types: map[string]ValueType{
+			"wins":   ValueInt64,
+			"ratio":  ValueFloat64,
+			"active": ValueBool,
+		}}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.UpdateFamilies([]string{"stats"}))
+	return m
+}
+
+func TestManager_Apply_typed(t *testing.T) {
+	req := require.New(t)
+	m := newTypedManager(t)
+	now := time.Now().UnixNano()
+
+	// typed values are stored in canonical form, and other qualifiers as they are
+	values := [][]byte{[]byte("+010"), []byte("0.50"), []byte("T"), []byte(" Ahri ")}
+	req.NoError(m.Apply("champ:1", "stats", []string{"wins", "ratio", "active", "name"},
+		values, now, 0))
+	data, found := m.GetRowByFamily("champ:1", "stats")
+	req.True(found)
+	row := (*data)["champ:1"]["stats"]
+	req.Equal([]byte("10"), row["wins"][0].Value)
+	req.Equal([]byte("0.5"), row["ratio"][0].Value)
+	req.Equal([]byte("true"), row["active"][0].Value)
+	req.Equal([]byte(" Ahri "), row["name"][0].Value)
+	// the caller's values are not changed
+	req.Equal([]byte("+010"), values[0])
+
+	// a value of the wrong type fails the whole write
+	err := m.Apply("champ:1", "stats", []string{"name", "wins"},
+		[][]byte{[]byte("Jinx"), []byte("ten")}, now+1, 0)
+	req.True(errors.Is(err, litetable.ErrInvalidArgument), err)
+	req.EqualError(err, "value of qualifier wins of family stats is not a valid int64")
+	data, _ = m.GetRowByFamily("champ:1", "stats")
+	req.Len((*data)["champ:1"]["stats"]["name"], 1)
+}
+
+func TestManager_ApplyBatch_typed(t *testing.T) {
+	req := require.New(t)
+	m := newTypedManager(t)
+	now := time.Now().UnixNano()
+
+	errs := m.ApplyBatch([]litetable.Mutation{
+		{RowKey: "champ:1", Family: "stats", Qualifiers: []string{"wins"},
+			Values: [][]byte{[]byte("ten")}, Timestamp: now},
+		{RowKey: "champ:2", Family: "stats", Qualifiers: []string{"wins"},
+			Values: [][]byte{[]byte("007")}, Timestamp: now},
+	})
+	req.True(errors.Is(errs[0], litetable.ErrInvalidArgument), errs[0])
+	req.NoError(errs[1])
+
+	_, found := m.GetRowByFamily("champ:1", "stats")
+	req.False(found)
+	data, found := m.GetRowByFamily("champ:2", "stats")
+	req.True(found)
+	req.Equal([]byte("7"), (*data)["champ:2"]["stats"]["wins"][0].Value)
+}
+
+func TestManager_Commit_typed(t *testing.T) {
+	req := require.New(t)
+	m := newTypedManager(t)
+	now := time.Now().UnixNano()
+
+	_, err := m.Commit(0, nil, []litetable.Mutation{
+		{Operation: litetable.OperationWrite, RowKey: "champ:1", Family: "stats",
+			Qualifiers: []string{"ratio"}, Values: [][]byte{[]byte("0.25")}, Timestamp: now},
+		{Operation: litetable.OperationWrite, RowKey: "champ:2", Family: "stats",
+			Qualifiers: []string{"active"}, Values: [][]byte{[]byte("maybe")}, Timestamp: now},
+	}, nil)
+	req.True(errors.Is(err, litetable.ErrInvalidArgument), err)
+	_, found := m.GetRowByFamily("champ:1", "stats")
+	req.False(found)
+}
+
+func TestNew_unknownValueType(t *testing.T) {
+	_, _, err := New(&Config{
+		RootDir:        t.TempDir(),
+		FlushThreshold: 60,
+		SnapshotTimer:  5,
+		CDCEmitter:     fakeCDC{},
+		FamilyPolicies: map[string]FamilyPolicy{"stats": {Types: map[string]ValueType{
+			"wins": "integer",
+		}}},
+	})
+	require.ErrorContains(t, err,
+		`type of qualifier wins of family stats must be one of [int64 float64 bool], got "integer"`)
+}
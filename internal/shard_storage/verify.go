@@ -0,0 +1,304 @@
+package shard_storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileReport is the result of verifying a single backup or snapshot file.
+type FileReport struct {
+	Path     string
+	Kind     string // "backup", "delta" or "snapshot"
+	Rows     int
+	Cells    int
+	Checksum string // "ok", "missing", "mismatch" or "unchecked"
+	Problems []string
+}
+
+// VerifyReport is the result of verifying every backup and snapshot in a data directory.
+type VerifyReport struct {
+	Files []*FileReport
+}
+
+// OK reports whether every file was readable and passed every check. A missing checksum is not a
+// problem, because files written before checksums were introduced do not have one.
+func (r *VerifyReport) OK() bool {
+	for _, f := range r.Files {
+		if len(f.Problems) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Write prints the report in a human-readable form.
+func (r *VerifyReport) Write(w io.Writer) {
+	for _, f := range r.Files {
+		status := "OK"
+		if len(f.Problems) > 0 {
+			status = "FAILED"
+		}
+		_, _ = fmt.Fprintf(w, "%-6s %-8s %s (rows: %d, cells: %d, checksum: %s)\n", status, f.Kind,
+			f.Path, f.Rows, f.Cells, f.Checksum)
+		for _, p := range f.Problems {
+			_, _ = fmt.Fprintf(w, "       - %s\n", p)
+		}
+	}
+
+	if len(r.Files) == 0 {
+		_, _ = fmt.Fprintln(w, "no backups or snapshots found")
+		return
+	}
+	if r.OK() {
+		_, _ = fmt.Fprintf(w, "%d files verified\n", len(r.Files))
+		return
+	}
+	_, _ = fmt.Fprintln(w, "verification failed")
+}
+
+// Verify checks the backups, the delta backups and the incremental snapshots under rootDir
+// without loading them into memory shards. Each file is checked for its structure, its checksum,
+// the timestamps of its values and the tombstone invariants; deltas must have their base backup
+// and snapshots must be in timestamp order. It only returns an error if the directory cannot be
+// read.
+func Verify(rootDir string) (*VerifyReport, error) {
+	if _, err := os.Stat(rootDir); err != nil {
+		return nil, err
+	}
+
+	backups, err := filepath.Glob(filepath.Join(rootDir, backupDirName, backupFileGlob))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	deltas, err := filepath.Glob(filepath.Join(rootDir, backupDirName, deltaFileGlob))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delta backups: %w", err)
+	}
+	snapshots, err := filepath.Glob(filepath.Join(rootDir, snapshotDir, snapshotFileGlob))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	sort.Strings(backups)
+	sort.Strings(deltas)
+	sort.Strings(snapshots)
+
+	report := &VerifyReport{}
+	for _, file := range backups {
+		report.Files = append(report.Files, verifyBackup(file))
+	}
+	for _, file := range deltas {
+		report.Files = append(report.Files, verifyDelta(file))
+	}
+
+	var previous *directSnapshotData
+	for _, file := range snapshots {
+		f, snapshot := verifySnapshot(file)
+		if snapshot != nil && previous != nil &&
+			snapshot.SnapshotTimestamp <= previous.SnapshotTimestamp {
+			f.Problems = append(f.Problems, fmt.Sprintf(
+				"snapshot timestamp %d is not after the previous snapshot (%d)",
+				snapshot.SnapshotTimestamp, previous.SnapshotTimestamp))
+		}
+		if snapshot != nil {
+			previous = snapshot
+		}
+		report.Files = append(report.Files, f)
+	}
+
+	return report, nil
+}
+
+func verifyBackup(file string) *FileReport {
+	f := &FileReport{Path: file, Kind: "backup", Checksum: "unchecked"}
+	data, ok := f.read()
+	if !ok {
+		return f
+	}
+
+	var backup litetable.Data
+	if err := json.Unmarshal(data, &backup); err != nil {
+		f.problem("invalid JSON: %v", err)
+		return f
+	}
+
+	for rowKey, families := range backup {
+		f.checkRow(rowKey, families, 0, false)
+	}
+
+	// backups written before manifests have nothing more to check
+	manifest, err := readManifest(file)
+	if err != nil {
+		f.problem("%v", err)
+		return f
+	}
+	if manifest.FormatVersion > 0 {
+		if manifest.Rows != len(backup) {
+			f.problem("manifest lists %d rows, backup has %d", manifest.Rows, len(backup))
+		}
+		if manifest.Bytes != int64(len(data)) {
+			f.problem("manifest lists %d bytes, backup has %d", manifest.Bytes, len(data))
+		}
+	}
+	return f
+}
+
+// verifyDelta checks a delta backup and that its base backup still exists.
+func verifyDelta(file string) *FileReport {
+	f := &FileReport{Path: file, Kind: "delta", Checksum: "unchecked"}
+	data, ok := f.read()
+	if !ok {
+		return f
+	}
+
+	var delta deltaBackupData
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&delta); err != nil {
+		f.problem("invalid JSON: %v", err)
+		return f
+	}
+	if delta.Version != DeltaFormatVersion {
+		f.problem("unsupported delta version %d", delta.Version)
+	}
+	if delta.Base == "" {
+		f.problem("missing base backup")
+	} else if !fileExists(filepath.Join(filepath.Dir(file), delta.Base)) {
+		f.problem("base backup %s does not exist", delta.Base)
+	}
+
+	for _, changes := range delta.Changes {
+		for rowKey, families := range changes {
+			if families == nil {
+				f.Rows++
+				continue
+			}
+			f.checkRow(rowKey, families, delta.LastSnapshot, true)
+		}
+	}
+	return f
+}
+
+// verifySnapshot checks a snapshot file and returns it if it could be parsed.
+func verifySnapshot(file string) (*FileReport, *directSnapshotData) {
+	f := &FileReport{Path: file, Kind: "snapshot", Checksum: "unchecked"}
+	data, ok := f.read()
+	if !ok {
+		return f, nil
+	}
+
+	var snapshot directSnapshotData
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&snapshot); err != nil {
+		f.problem("invalid JSON: %v", err)
+		return f, nil
+	}
+	if snapshot.Version != SnapshotFormatVersion {
+		f.problem("unsupported snapshot version %d", snapshot.Version)
+	}
+	if snapshot.SnapshotTimestamp <= 0 {
+		f.problem("missing snapshot timestamp")
+	}
+
+	for rowKey, families := range snapshot.SnapshotData {
+		// a nil row is a deletion marker
+		if families == nil {
+			f.Rows++
+			continue
+		}
+		f.checkRow(rowKey, families, snapshot.SnapshotTimestamp, true)
+	}
+	return f, &snapshot
+}
+
+// read returns the contents of the file and checks them against the checksum file.
+func (f *FileReport) read() ([]byte, bool) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		f.problem("unreadable: %v", err)
+		return nil, false
+	}
+
+	sum, err := os.ReadFile(f.Path + checksumSuffix)
+	switch {
+	case os.IsNotExist(err):
+		f.Checksum = "missing"
+	case err != nil:
+		f.problem("unreadable checksum: %v", err)
+	default:
+		actual := sha256.Sum256(data)
+		if strings.TrimSpace(string(sum)) == hex.EncodeToString(actual[:]) {
+			f.Checksum = "ok"
+		} else {
+			f.Checksum = "mismatch"
+			f.problem("checksum mismatch")
+		}
+	}
+	return data, true
+}
+
+// checkRow checks the structure, timestamps and tombstones of a row. Written values in a snapshot
+// cannot be newer than the snapshot itself; a zero snapshotTime skips that check. Nil families
+// are only allowed in snapshots, where they mark a deleted family.
+func (f *FileReport) checkRow(rowKey string, families map[string]litetable.VersionedQualifier,
+	snapshotTime int64, allowDeleted bool) {
+	f.Rows++
+	if rowKey == "" {
+		f.problem("empty row key")
+	}
+	if len(families) == 0 {
+		f.problem("row %s has no families", rowKey)
+	}
+
+	for family, qualifiers := range families {
+		if family == "" {
+			f.problem("row %s has an empty family name", rowKey)
+		}
+		if qualifiers == nil {
+			if !allowDeleted {
+				f.problem("row %s has a null family %s", rowKey, family)
+			}
+			continue
+		}
+
+		for qualifier, values := range qualifiers {
+			cell := fmt.Sprintf("%s/%s/%s", rowKey, family, qualifier)
+			if len(values) == 0 {
+				f.problem("%s has no values", cell)
+			}
+
+			for _, v := range values {
+				f.Cells++
+				if v.Timestamp <= 0 {
+					f.problem("%s has a value without a timestamp", cell)
+				}
+				// writes use the server clock, but deletes may place a tombstone at any time
+				if snapshotTime > 0 && v.Timestamp > snapshotTime && !v.IsTombstone {
+					f.problem("%s has a value at %d, after the snapshot was taken", cell,
+						v.Timestamp)
+				}
+				if v.IsTombstone && v.ExpiresAt < v.Timestamp {
+					f.problem("%s has a tombstone at %d that expires before it was written", cell,
+						v.Timestamp)
+				}
+				if !v.IsTombstone && v.ExpiresAt != 0 && v.ExpiresAt <= v.Timestamp {
+					f.problem("%s has a TTL at %d that expires before it was written", cell,
+						v.ExpiresAt)
+				}
+			}
+		}
+	}
+}
+
+func (f *FileReport) problem(format string, args ...any) {
+	f.Problems = append(f.Problems, fmt.Sprintf(format, args...))
+}
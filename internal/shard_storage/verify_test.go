@@ -0,0 +1,157 @@
+package shard_storage
+
+import (
+	"bytes"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Now().UnixNano()
+
+	tests := map[string]struct {
+		// setup writes files into the backup and snapshot directories of a fresh manager
+		setup    func(t *testing.T, m *Manager)
+		problems []string
+	}{
+		"empty directory": {
+			setup: func(t *testing.T, m *Manager) {},
+		},
+		"backup and snapshot written by the manager": {
+			setup: func(t *testing.T, m *Manager) {
+				require.NoError(t, m.Apply("champ:1", "main", []string{"name"},
+					[][]byte{[]byte("Ahri")}, now, 0))
+				require.NoError(t, m.Flush())
+				require.NoError(t, m.Delete("champ:1", "main", []string{"name"}, now+1,
+					now+int64(time.Hour)))
+				require.NoError(t, m.createDirectSnapshot())
+			},
+		},
+		"corrupted backup": {
+			setup: func(t *testing.T, m *Manager) {
+				require.NoError(t, m.Apply("champ:1", "main", []string{"name"},
+					[][]byte{[]byte("Ahri")}, now, 0))
+				require.NoError(t, m.Flush())
+
+				// same size and still valid, so only the checksum catches it
+				latest, err := m.getLatestBackup()
+				require.NoError(t, err)
+				data, err := os.ReadFile(latest)
+				require.NoError(t, err)
+				data = bytes.Replace(data, []byte(`"main"`), []byte(`"mair"`), 1)
+				require.NoError(t, os.WriteFile(latest, data, 0644))
+			},
+			problems: []string{"checksum mismatch"},
+		},
+		"manifest does not match backup": {
+			setup: func(t *testing.T, m *Manager) {
+				file := filepath.Join(m.dataDir, "backup-1.db")
+				require.NoError(t, writeDataFile(file, nil, []byte(`{"champ:1":{}}`)))
+				require.NoError(t, writeManifest(file, &litetable.BackupManifest{
+					File:          "backup-1.db",
+					FormatVersion: BackupFormatVersion,
+					Rows:          2,
+					Bytes:         10,
+				}))
+			},
+			problems: []string{
+				"row champ:1 has no families",
+				"manifest lists 2 rows, backup has 1",
+				"manifest lists 10 bytes, backup has 14",
+			},
+		},
+		"invalid json without checksum": {
+			setup: func(t *testing.T, m *Manager) {
+				require.NoError(t, os.WriteFile(filepath.Join(m.dataDir, "backup-1.db"),
+					[]byte("{"), 0644))
+			},
+			problems: []string{"invalid JSON: unexpected end of JSON input"},
+		},
+		"broken tombstone invariants": {
+			setup: func(t *testing.T, m *Manager) {
+				require.NoError(t, writeDataFile(filepath.Join(m.dataDir, "backup-1.db"), nil,
+					[]byte(`{"champ:1":{"main":{"name":[`+
+						`{"value":null,"timestamp":10,"tombstone":true,"expiresAt":5},`+
+						`{"value":"QWhyaQ==","timestamp":10,"expiresAt":20},`+
+						`{"value":"QWhyaQ==","timestamp":30,"expiresAt":30}]}}}`)))
+			},
+			problems: []string{
+				"champ:1/main/name has a tombstone at 10 that expires before it was written",
+				"champ:1/main/name has a TTL at 30 that expires before it was written",
+			},
+		},
+		"snapshot values after the snapshot": {
+			setup: func(t *testing.T, m *Manager) {
+				file := filepath.Join(m.snapshotDir, "ss-incr-100.db")
+				require.NoError(t, writeDataFile(file, nil,
+					[]byte(`{"version":1,"snapshotTimestamp":100,"epoch":1,"snapshotData":{`+
+						`"champ:1":{"main":{"name":[{"value":"QWhyaQ==","timestamp":200}]}},`+
+						`"champ:2":null}}`)))
+			},
+			problems: []string{"champ:1/main/name has a value at 200, after the snapshot was taken"},
+		},
+		"snapshots out of order": {
+			setup: func(t *testing.T, m *Manager) {
+				for _, name := range []string{"ss-incr-100.db", "ss-incr-200.db"} {
+					require.NoError(t, writeDataFile(filepath.Join(m.snapshotDir, name), nil,
+						[]byte(`{"version":1,"snapshotTimestamp":100,"snapshotData":{}}`)))
+				}
+			},
+			problems: []string{"snapshot timestamp 100 is not after the previous snapshot (100)"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			m := newTestManager(t)
+			tc.setup(t, m)
+
+			report, err := Verify(m.rootDir)
+			req.NoError(err)
+
+			var problems []string
+			for _, f := range report.Files {
+				problems = append(problems, f.Problems...)
+			}
+			req.ElementsMatch(tc.problems, problems)
+			req.Equal(len(tc.problems) == 0, report.OK())
+
+			var out bytes.Buffer
+			report.Write(&out)
+			req.NotEmpty(out.String())
+		})
+	}
+}
+
+func TestVerify_missingDirectory(t *testing.T) {
+	_, err := Verify(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestVerify_checksums(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+	req.NoError(m.Apply("champ:1", "main", []string{"name"}, [][]byte{[]byte("Ahri")},
+		time.Now().UnixNano(), 0))
+	req.NoError(m.Flush())
+
+	latest, err := m.getLatestBackup()
+	req.NoError(err)
+	req.FileExists(latest + checksumSuffix)
+
+	report, err := Verify(m.rootDir)
+	req.NoError(err)
+	req.Len(report.Files, 1)
+	req.Equal("ok", report.Files[0].Checksum)
+	req.Equal(1, report.Files[0].Rows)
+	req.Equal(1, report.Files[0].Cells)
+
+	// pruning a backup removes its checksum too
+	req.NoError(removeDataFile(latest))
+	req.NoFileExists(latest + checksumSuffix)
+}
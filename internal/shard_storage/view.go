@@ -0,0 +1,173 @@
+package shard_storage
+
+import (
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var readViewRefreshes = metrics.NewCounter("litetable_read_view_refreshes_total",
+	"Copies of a changed shard made for the read views.")
+
+// shardView is an immutable copy of the rows of a shard. Reads from it take no lock, and can
+// miss the changes made since it was copied.
+type shardView struct {
+	rows litetable.Data
+	// changes is the change count of the shard when it was copied
+	changes uint64
+}
+
+// refreshViews copies every shard that changed since its view was copied. A shard is locked for
+// reading while it is copied, like during a scan.
+func (m *Manager) refreshViews() {
+	for _, s := range m.shardMap {
+		// a change made while the shard is copied counts after the count is read, so the shard
+		// is copied again on the next refresh
+		changes := s.changeCount.Load()
+		if view := s.view.Load(); view != nil && view.changes == changes {
+			continue
+		}
+
+		s.RLock()
+		rows := make(litetable.Data, len(s.data))
+		for rowKey, r := range s.data {
+			families := make(map[string]litetable.VersionedQualifier, len(r.families))
+			for _, f := range r.families {
+				qualifiers := make(litetable.VersionedQualifier, len(f.qualifiers))
+				for qualifier, values := range f.qualifiers {
+					if hasCompressed(values) {
+						qualifiers[qualifier] = m.decompressed(values)
+					} else {
+						qualifiers[qualifier] = slices.Clone(values)
+					}
+				}
+				families[f.name] = qualifiers
+			}
+			rows[rowKey] = families
+		}
+		s.RUnlock()
+
+		s.view.Store(&shardView{rows: rows, changes: changes})
+		readViewRefreshes.Inc()
+	}
+}
+
+// ReadView returns a reader of the read views of the shards, which never waits on a shard lock
+// but is up to the read view interval behind the shards, and not at one point in time across
+// them. It returns false when the table keeps no read views.
+func (m *Manager) ReadView() (litetable.RowReader, bool) {
+	if m.readViewInterval == 0 {
+		return nil, false
+	}
+	return &readView{m: m}, true
+}
+
+// readView reads the rows of the shard views. The rows it returns are shared with the views and
+// must not be changed.
+type readView struct {
+	m *Manager
+}
+
+// view returns the view of a shard, which is empty until the first refresh.
+func (v *readView) view(s *shard) litetable.Data {
+	if view := s.view.Load(); view != nil {
+		return view.rows
+	}
+	return nil
+}
+
+func (v *readView) GetRowByFamily(key, family string) (*litetable.Data, bool) {
+	rows := v.view(v.m.shardMap[v.m.getShardIndex(key)])
+	qualifiers, exists := rows[key][family]
+	if !exists {
+		return nil, false
+	}
+	return &litetable.Data{key: {family: qualifiers}}, true
+}
+
+func (v *readView) FilterRowsByPrefix(prefix string, page litetable.ScanPage) (*litetable.Data,
+	litetable.ScanStats, bool) {
+	result := make(litetable.Data)
+	var stats litetable.ScanStats
+	for _, s := range v.m.shardMap {
+		rows := v.view(s)
+		stats.Examined += len(rows)
+		for rowKey, families := range rows {
+			if strings.HasPrefix(rowKey, prefix) && !page.Skips(rowKey) {
+				result[rowKey] = families
+			}
+		}
+	}
+	if page.MaxBytes > 0 {
+		result, stats.More = viewPage(result, page.MaxBytes)
+	}
+	return &result, stats, len(result) > 0
+}
+
+// FilterRowsByRegex scans the views of all shards concurrently, with the same pattern limits and
+// time budget as a scan of the shards.
+func (v *readView) FilterRowsByRegex(regex string, page litetable.ScanPage) (*litetable.Data,
+	litetable.ScanStats, bool, error) {
+	reg, err := compileRowKeyRegex(regex, v.m.maxRegexProgram)
+	if err != nil {
+		return nil, litetable.ScanStats{}, false, err
+	}
+
+	result := make(litetable.Data)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	exceeded := false
+	var stats litetable.ScanStats
+	for _, s := range v.m.shardMap {
+		wg.Add(1)
+		go func(rows litetable.Data) {
+			defer wg.Done()
+			matches := make(litetable.Data)
+			deadline := time.Now().Add(v.m.regexScanBudget)
+			checked := 0
+			for rowKey, families := range rows {
+				if checked++; checked%regexCheckInterval == 0 && time.Now().After(deadline) {
+					mutex.Lock()
+					exceeded = true
+					stats.Examined += checked
+					mutex.Unlock()
+					return
+				}
+				if !page.Skips(rowKey) && reg.MatchString(rowKey) {
+					matches[rowKey] = families
+				}
+			}
+
+			mutex.Lock()
+			for rowKey, families := range matches {
+				result[rowKey] = families
+			}
+			stats.Examined += checked
+			mutex.Unlock()
+		}(v.view(s))
+	}
+	wg.Wait()
+
+	if exceeded {
+		regexScansAbandoned.Inc()
+		return nil, stats, false, errRegexBudget(v.m.regexScanBudget)
+	}
+	if page.MaxBytes > 0 {
+		result, stats.More = viewPage(result, page.MaxBytes)
+	}
+	return &result, stats, len(result) > 0, nil
+}
+
+// viewPage keeps the first rows of a view scan by key until they pass maxBytes. The rows are
+// shared with the views, so the rest are dropped without having been copied.
+func viewPage(rows litetable.Data, maxBytes int) (litetable.Data, bool) {
+	return pageRows(slices.Collect(maps.Keys(rows)), maxBytes,
+		func(key string) (map[string]litetable.VersionedQualifier, bool) {
+			families, exists := rows[key]
+			return families, exists
+		})
+}
@@ -0,0 +1,63 @@
+package shard_storage
+
+import (
+	"errors"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestManager_ReadView(t *testing.T) {
+	req := require.New(t)
+	m := newTestManager(t)
+
+	_, ok := m.ReadView()
+	req.False(ok)
+
+	m.readViewInterval = time.Hour
+	view, ok := m.ReadView()
+	req.True(ok)
+
+	apply := func(rowKey, name string) {
+		req.NoError(m.Apply(rowKey, "main", []string{"name"}, [][]byte{[]byte(name)},
+			time.Now().UnixNano(), 0))
+	}
+	apply("champ:1", "Ahri")
+
+	// nothing is visible before the first refresh
+	_, found := view.GetRowByFamily("champ:1", "main")
+	req.False(found)
+
+	m.refreshViews()
+	data, found := view.GetRowByFamily("champ:1", "main")
+	req.True(found)
+	req.Equal([]byte("Ahri"), (*data)["champ:1"]["main"]["name"][0].Value)
+
+	// a write is only visible after the next refresh, which copies only the changed shard
+	apply("champ:2", "Jinx")
+	_, found = view.GetRowByFamily("champ:2", "main")
+	req.False(found)
+	refreshes := readViewRefreshes.Value()
+	m.refreshViews()
+	req.Equal(refreshes+1, readViewRefreshes.Value())
+
+	data, stats, found := view.FilterRowsByPrefix("champ:", litetable.ScanPage{})
+	req.True(found)
+	req.Len(*data, 2)
+	req.Equal(2, stats.Examined)
+
+	data, stats, found, err := view.FilterRowsByRegex("^champ:2$", litetable.ScanPage{})
+	req.NoError(err)
+	req.True(found)
+	req.Contains(*data, "champ:2")
+	req.Equal(2, stats.Examined)
+
+	_, _, _, err = view.FilterRowsByRegex("champ:(", litetable.ScanPage{})
+	req.True(errors.Is(err, litetable.ErrInvalidArgument))
+
+	// the view is a copy, so later versions do not show up in it
+	apply("champ:1", "Vi")
+	data, _ = view.GetRowByFamily("champ:1", "main")
+	req.Len((*data)["champ:1"]["main"]["name"], 1)
+}
@@ -1,13 +1,17 @@
 package wal
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,6 +20,9 @@ const (
 	defaultWALFile      = "wal.log"
 )
 
+var walBacklog = metrics.NewGauge("litetable_wal_backlog_entries",
+	"Entries waiting to be written to the WAL.")
+
 // Entry represents a Write-Ahead Log entry for a database operation
 type Entry struct {
 	Operation litetable.Operation `json:"operation"`
@@ -27,6 +34,8 @@ type Manager struct {
 	mu      sync.RWMutex
 	walFile *os.File
 	path    string
+	// backlog is the number of entries waiting to be written
+	backlog atomic.Int64
 }
 
 type Config struct {
@@ -65,6 +74,39 @@ func New(cfg *Config) (*Manager, error) {
 	}, nil
 }
 
+// CountEntries reads the WAL under dir, as New would open it, and returns the number of entries
+// in it. It fails on the first entry that cannot be parsed, such as one cut short by a crash. A
+// missing WAL has no entries.
+func CountEntries(dir string) (int, error) {
+	file, err := os.Open(filepath.Join(dir, defaultWalDirectory, defaultWALFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open WAL file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	entries := 0
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var e Entry
+			if jsonErr := json.Unmarshal(line, &e); jsonErr != nil {
+				return entries, fmt.Errorf("WAL entry %d is not valid: %w", entries+1, jsonErr)
+			}
+			entries++
+		}
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, fmt.Errorf("failed to read WAL file: %w", err)
+		}
+	}
+}
+
 // Apply takes in the query bytes and appends to the WAL file:
 //
 // ex: key=testKey:12345 family=main qualifier=status value=active qualifier=time value=now
@@ -79,6 +121,7 @@ func (m *Manager) Apply(e *Entry) error {
 		return fmt.Errorf("failed to marshal entry: %w", err)
 	}
 
+	defer m.wait(1)()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	// Write the JSON data to the WAL file, followed by a newline
@@ -88,3 +131,51 @@ func (m *Manager) Apply(e *Entry) error {
 
 	return nil
 }
+
+// Sync commits every entry applied so far to stable storage.
+func (m *Manager) Sync() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.walFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync WAL: %w", err)
+	}
+	return nil
+}
+
+// ApplyBatch appends entries to the WAL in a single write, in order, so a group of writes costs
+// one write to the WAL file.
+func (m *Manager) ApplyBatch(entries []*Entry) error {
+	var data []byte
+	for _, e := range entries {
+		jsonData, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal entry: %w", err)
+		}
+		data = append(append(data, jsonData...), '\n')
+	}
+
+	defer m.wait(len(entries))()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.walFile.Write(data); err != nil {
+		return fmt.Errorf("failed to write to WAL: %w", err)
+	}
+
+	return nil
+}
+
+// Backlog returns the number of entries waiting to be written to the WAL, which grows when
+// writers queue up behind a slow disk.
+func (m *Manager) Backlog() int {
+	return int(m.backlog.Load())
+}
+
+// wait counts n entries as waiting to be written, until the returned function is called.
+func (m *Manager) wait(n int) func() {
+	m.backlog.Add(int64(n))
+	walBacklog.Add(float64(n))
+	return func() {
+		m.backlog.Add(-int64(n))
+		walBacklog.Add(-float64(n))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/litetable/litetable-db/internal/litetable"
 	"github.com/stretchr/testify/require"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -79,3 +80,88 @@ func TestManager_Apply(t *testing.T) {
 		require.Equal(t, entry.Timestamp.Unix(), entryRead.Timestamp.Unix())
 	})
 }
+
+func TestManager_Sync(t *testing.T) {
+	t.Parallel()
+	m, err := New(&Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Apply(&Entry{
+		Operation: litetable.OperationWrite,
+		Query:     []byte("test query"),
+		Timestamp: time.Now(),
+	}))
+	require.NoError(t, m.Sync())
+
+	require.NoError(t, m.walFile.Close())
+	require.Error(t, m.Sync())
+}
+
+func TestManager_ApplyBatch(t *testing.T) {
+	t.Parallel()
+	m, err := New(&Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	queries := []string{"key=a family=main", "key=b family=main", "key=c family=main"}
+	entries := make([]*Entry, 0, len(queries))
+	for _, query := range queries {
+		entries = append(entries, &Entry{
+			Operation: litetable.OperationWrite,
+			Query:     []byte(query),
+			Timestamp: time.Now(),
+		})
+	}
+	require.NoError(t, m.ApplyBatch(entries))
+
+	// every entry is on its own line, in order
+	contents, err := os.ReadFile(m.path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+	require.Len(t, lines, len(queries))
+	for i, line := range lines {
+		var entry Entry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		require.Equal(t, queries[i], string(entry.Query))
+	}
+}
+
+func TestManager_Backlog(t *testing.T) {
+	t.Parallel()
+	m, err := New(&Config{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	// writers behind the lock are waiting to be written
+	m.mu.Lock()
+	done := make(chan error)
+	go func() {
+		done <- m.ApplyBatch([]*Entry{{Query: []byte("a")}, {Query: []byte("b")}})
+	}()
+	require.Eventually(t, func() bool { return m.Backlog() == 2 }, time.Second, time.Millisecond)
+	m.mu.Unlock()
+
+	require.NoError(t, <-done)
+	require.Zero(t, m.Backlog())
+}
+
+func TestCountEntries(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	entries, err := CountEntries(dir)
+	require.NoError(t, err)
+	require.Zero(t, entries)
+
+	m, err := New(&Config{Path: dir})
+	require.NoError(t, err)
+	require.NoError(t, m.ApplyBatch([]*Entry{{Query: []byte("a")}, {Query: []byte("b")}}))
+	entries, err = CountEntries(dir)
+	require.NoError(t, err)
+	require.Equal(t, 2, entries)
+
+	// an entry cut short by a crash is reported
+	_, err = m.walFile.WriteString(`{"operation":`)
+	require.NoError(t, err)
+	entries, err = CountEntries(dir)
+	require.ErrorContains(t, err, "WAL entry 3 is not valid")
+	require.Equal(t, 2, entries)
+}
@@ -0,0 +1,146 @@
+package workload
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/logging"
+	"github.com/litetable/litetable-db/internal/metrics"
+	"github.com/rs/zerolog"
+	rand2 "math/rand/v2"
+	"os"
+	"sync"
+	"time"
+)
+
+// flushInterval is how often recorded shapes are written out of the buffer.
+const flushInterval = time.Second
+
+var recordedShapes = metrics.NewCounter("litetable_recorded_requests_total",
+	"Requests whose shape was written to the query recording.")
+
+// Recorder writes the anonymized shape of a sample of the reads, writes and deletes a server
+// receives to a file, one JSON object per line. Names are hashed with a key that is never
+// written, so a recording cannot be mapped back to the keys and names it was taken from, and
+// values are reduced to their sizes.
+type Recorder struct {
+	file   *os.File
+	rate   float64
+	start  time.Time
+	anon   anonymizer
+	logger zerolog.Logger
+
+	mutex   sync.Mutex
+	writer  *bufio.Writer
+	stopped bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewRecorder creates a recorder writing to path, replacing any recording there, that records
+// each request with probability rate.
+func NewRecorder(path string, rate float64) (*Recorder, error) {
+	if rate <= 0 || rate > 1 {
+		return nil, fmt.Errorf("sample rate must be above 0 and at most 1, got %g", rate)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate recording key: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create query recording: %w", err)
+	}
+	return &Recorder{
+		file:   file,
+		rate:   rate,
+		anon:   anonymizer{key: key},
+		logger: logging.For("workload"),
+		writer: bufio.NewWriter(file),
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Start starts recording and flushing the recording every second.
+func (r *Recorder) Start() error {
+	r.mutex.Lock()
+	r.start = time.Now()
+	r.mutex.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+				r.mutex.Lock()
+				if err := r.writer.Flush(); err != nil {
+					r.logger.Warn().Err(err).Msg("failed to flush query recording")
+				}
+				r.mutex.Unlock()
+			}
+		}
+	}()
+	r.logger.Info().Str("path", r.file.Name()).Float64("rate", r.rate).
+		Msg("Recording query shapes")
+	return nil
+}
+
+// Stop stops recording and closes the recording.
+func (r *Recorder) Stop() error {
+	r.mutex.Lock()
+	if r.stopped {
+		r.mutex.Unlock()
+		return nil
+	}
+	r.stopped = true
+	close(r.done)
+	err := r.writer.Flush()
+	r.mutex.Unlock()
+
+	r.wg.Wait()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (r *Recorder) Name() string {
+	return "Query Recorder"
+}
+
+// Record writes the shape of req if it is a read, write or delete and it is sampled. It is
+// called for every request, so it drops the shape rather than fail the request.
+func (r *Recorder) Record(req any) {
+	if rand2.Float64() >= r.rate {
+		return
+	}
+	shape, ok := r.anon.shapeOf(req)
+	if !ok {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.stopped || r.start.IsZero() {
+		return
+	}
+	shape.Offset = time.Since(r.start)
+	line, err := json.Marshal(shape)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+	if _, err = r.writer.Write(line); err != nil {
+		r.logger.Warn().Err(err).Msg("failed to write query recording")
+		return
+	}
+	recordedShapes.Inc()
+}
@@ -0,0 +1,54 @@
+package workload
+
+import (
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	req := require.New(t)
+	path := filepath.Join(t.TempDir(), "queries.jsonl")
+
+	_, err := NewRecorder(path, 0)
+	req.Error(err)
+
+	r, err := NewRecorder(path, 1)
+	req.NoError(err)
+
+	// nothing is recorded before the recorder starts
+	r.Record(&proto.WriteRequest{RowKey: "champ:0", Family: "main"})
+	req.NoError(r.Start())
+
+	before := recordedShapes.Value()
+	r.Record(&proto.WriteRequest{RowKey: "champ:1", Family: "main",
+		Qualifiers: []*proto.ColumnQualifier{{Name: "name", Value: []byte("Ahri")}}})
+	r.Record(&proto.CreateTableRequest{Name: "wwe"})
+	r.Record(&proto.ReadRequest{RowKey: "champ:1", Family: "main"})
+	req.Equal(before+2, recordedShapes.Value())
+
+	req.NoError(r.Stop())
+	req.NoError(r.Stop())
+	// nothing is recorded after it stops
+	r.Record(&proto.ReadRequest{RowKey: "champ:1", Family: "main"})
+
+	f, err := os.Open(path)
+	req.NoError(err)
+	defer f.Close()
+	shapes, err := ReadShapes(f)
+	req.NoError(err)
+	req.Len(shapes, 2)
+	req.Equal(MethodWrite, shapes[0].Method)
+	req.Equal([]int{4}, shapes[0].Families[0].ValueSizes)
+	req.Equal(MethodRead, shapes[1].Method)
+	req.Equal(shapes[0].RowKey, shapes[1].RowKey)
+	req.NotEqual("champ:1", shapes[0].RowKey)
+	req.LessOrEqual(shapes[0].Offset, shapes[1].Offset)
+
+	contents, err := os.ReadFile(path)
+	req.NoError(err)
+	req.NotContains(string(contents), "champ")
+	req.NotContains(string(contents), "Ahri")
+}
@@ -0,0 +1,234 @@
+package workload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ReplayOptions set how fast a recording is replayed.
+type ReplayOptions struct {
+	// Speed scales the time between requests: 2 replays a recording in half the time it took to
+	// record. 0 sends every request as soon as a worker is free.
+	Speed float64
+	// Concurrency is the number of requests in flight at once. Below 1, it is 1.
+	Concurrency int
+}
+
+// ReplayReport is the outcome of a replay, by method.
+type ReplayReport struct {
+	Methods map[string]*MethodReport
+	// Skipped counts the regex reads, which are recorded without their pattern
+	Skipped  int
+	Duration time.Duration
+}
+
+// MethodReport is the outcome of the requests of one method.
+type MethodReport struct {
+	Requests int
+	// Errors counts the failed requests by gRPC code
+	Errors    map[string]int
+	latencies []time.Duration
+}
+
+// Latency returns the latency below which fraction q of the requests completed.
+func (r *MethodReport) Latency(q float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	return r.latencies[int(q*float64(len(r.latencies)-1))]
+}
+
+// Write prints the report in a human-readable form.
+func (r *ReplayReport) Write(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "replayed in %s, skipped %d regex reads\n", r.Duration, r.Skipped)
+	for _, method := range slices.Sorted(maps.Keys(r.Methods)) {
+		m := r.Methods[method]
+		_, _ = fmt.Fprintf(w, "%s: %d requests, p50 %s, p95 %s, p99 %s, max %s\n", method,
+			m.Requests, m.Latency(0.5), m.Latency(0.95), m.Latency(0.99), m.Latency(1))
+		for _, code := range slices.Sorted(maps.Keys(m.Errors)) {
+			_, _ = fmt.Fprintf(w, "  %s: %d\n", code, m.Errors[code])
+		}
+	}
+}
+
+// Replay creates the tables and families of the shapes that the server is missing, then sends
+// it the shapes in order, keeping their timing scaled by the speed. Values are filled to their
+// recorded size. Failed requests are counted in the report; only failing to create a table or
+// family stops the replay.
+func Replay(ctx context.Context, client proto.LitetableServiceClient, shapes []Shape,
+	opts ReplayOptions) (*ReplayReport, error) {
+	if err := prepare(ctx, client, shapes); err != nil {
+		return nil, err
+	}
+
+	report := &ReplayReport{Methods: make(map[string]*MethodReport)}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan Shape)
+	for range max(opts.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shape := range queue {
+				start := time.Now()
+				err := send(ctx, client, shape)
+				latency := time.Since(start)
+
+				mutex.Lock()
+				m, ok := report.Methods[shape.Method]
+				if !ok {
+					m = &MethodReport{Errors: make(map[string]int)}
+					report.Methods[shape.Method] = m
+				}
+				m.Requests++
+				m.latencies = append(m.latencies, latency)
+				if err != nil {
+					m.Errors[status.Code(err).String()]++
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	start := time.Now()
+	for _, shape := range shapes {
+		if shape.Method == MethodRead && shape.QueryType == QueryRegex {
+			report.Skipped++
+			continue
+		}
+		if opts.Speed > 0 {
+			wait := time.Duration(float64(shape.Offset)/opts.Speed) - time.Since(start)
+			if wait > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(wait):
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- shape
+	}
+	close(queue)
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	for _, m := range report.Methods {
+		slices.Sort(m.latencies)
+	}
+	return report, ctx.Err()
+}
+
+// prepare creates the tables and families the shapes use, leaving those that exist.
+func prepare(ctx context.Context, client proto.LitetableServiceClient, shapes []Shape) error {
+	families := make(map[string]map[string]struct{})
+	for _, shape := range shapes {
+		table := shape.Table
+		if table == "" {
+			table = litetable.DefaultTable
+		}
+		if families[table] == nil {
+			families[table] = make(map[string]struct{})
+		}
+		for _, family := range shape.Families {
+			if family.Family != "" {
+				families[table][family.Family] = struct{}{}
+			}
+		}
+	}
+
+	for _, table := range slices.Sorted(maps.Keys(families)) {
+		if table != litetable.DefaultTable {
+			_, err := client.CreateTable(ctx, &proto.CreateTableRequest{Name: table})
+			if err != nil && status.Code(err) != codes.AlreadyExists {
+				return fmt.Errorf("failed to create table %s: %w", table, err)
+			}
+		}
+		for _, family := range slices.Sorted(maps.Keys(families[table])) {
+			_, err := client.CreateFamily(ctx, &proto.CreateFamilyRequest{
+				Family: []string{family},
+				Table:  table,
+			})
+			if err != nil && status.Code(err) != codes.AlreadyExists {
+				return fmt.Errorf("failed to create family %s of table %s: %w", family, table,
+					err)
+			}
+		}
+	}
+	return nil
+}
+
+// send sends the request of a shape.
+func send(ctx context.Context, client proto.LitetableServiceClient, shape Shape) error {
+	var family FamilyShape
+	if len(shape.Families) > 0 {
+		family = shape.Families[0]
+	}
+
+	switch shape.Method {
+	case MethodRead:
+		msg := &proto.ReadRequest{
+			RowKey:     shape.RowKey,
+			Family:     family.Family,
+			Qualifiers: family.Qualifiers,
+			Latest:     shape.Latest,
+			Table:      shape.Table,
+			Ordered:    shape.Ordered,
+		}
+		if shape.QueryType == QueryPrefix {
+			msg.QueryType = proto.QueryType_PREFIX
+		}
+		if shape.Eventual {
+			msg.Consistency = proto.Consistency_EVENTUAL
+		}
+		_, err := client.Read(ctx, msg)
+		return err
+	case MethodWrite:
+		msg := &proto.WriteRequest{RowKey: shape.RowKey, Table: shape.Table}
+		if len(shape.Families) == 1 {
+			msg.Family = family.Family
+			msg.Qualifiers = columns(family)
+		} else {
+			for _, f := range shape.Families {
+				msg.Families = append(msg.Families,
+					&proto.FamilyCells{Family: f.Family, Qualifiers: columns(f)})
+			}
+		}
+		_, err := client.Write(ctx, msg)
+		return err
+	case MethodDelete:
+		_, err := client.Delete(ctx, &proto.DeleteRequest{
+			RowKey:     shape.RowKey,
+			Family:     family.Family,
+			Qualifiers: family.Qualifiers,
+			Table:      shape.Table,
+		})
+		return err
+	default:
+		return status.Errorf(codes.Unimplemented, "unknown method %q", shape.Method)
+	}
+}
+
+// columns fills the qualifiers of a family with values of their recorded size.
+func columns(family FamilyShape) []*proto.ColumnQualifier {
+	out := make([]*proto.ColumnQualifier, len(family.Qualifiers))
+	for i, qualifier := range family.Qualifiers {
+		var size int
+		if i < len(family.ValueSizes) {
+			size = family.ValueSizes[i]
+		}
+		out[i] = &proto.ColumnQualifier{Name: qualifier, Value: bytes.Repeat([]byte{'x'}, size)}
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package workload
+
+import (
+	"bytes"
+	"context"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClient is a server that keeps the requests sent to it.
+type testClient struct {
+	proto.LitetableServiceClient
+	mutex    sync.Mutex
+	tables   []string
+	families []string
+	reads    []*proto.ReadRequest
+	writes   []*proto.WriteRequest
+	deletes  []*proto.DeleteRequest
+}
+
+func (c *testClient) CreateTable(_ context.Context, msg *proto.CreateTableRequest,
+	_ ...grpc.CallOption) (*proto.Empty, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tables = append(c.tables, msg.GetName())
+	return &proto.Empty{}, nil
+}
+
+func (c *testClient) CreateFamily(_ context.Context, msg *proto.CreateFamilyRequest,
+	_ ...grpc.CallOption) (*proto.Empty, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if msg.GetFamily()[0] == "exists" {
+		return nil, status.Error(codes.AlreadyExists, "family exists already exists")
+	}
+	c.families = append(c.families, msg.GetTable()+"/"+msg.GetFamily()[0])
+	return &proto.Empty{}, nil
+}
+
+func (c *testClient) Read(_ context.Context, msg *proto.ReadRequest,
+	_ ...grpc.CallOption) (*proto.LitetableData, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reads = append(c.reads, msg)
+	return nil, status.Error(codes.NotFound, "row not found")
+}
+
+func (c *testClient) Write(_ context.Context, msg *proto.WriteRequest,
+	_ ...grpc.CallOption) (*proto.LitetableData, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.writes = append(c.writes, msg)
+	return &proto.LitetableData{}, nil
+}
+
+func (c *testClient) Delete(_ context.Context, msg *proto.DeleteRequest,
+	_ ...grpc.CallOption) (*proto.Empty, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.deletes = append(c.deletes, msg)
+	return &proto.Empty{}, nil
+}
+
+func TestReplay(t *testing.T) {
+	req := require.New(t)
+	shapes := []Shape{
+		{Method: MethodWrite, Table: "default", RowKey: "ab:1", Families: []FamilyShape{
+			{Family: "f1", Qualifiers: []string{"q1"}, ValueSizes: []int{3}},
+		}},
+		{Offset: 10 * time.Millisecond, Method: MethodWrite, Table: "t1", RowKey: "ab:2",
+			Families: []FamilyShape{
+				{Family: "f1", Qualifiers: []string{"q1"}, ValueSizes: []int{1}},
+				{Family: "exists", Qualifiers: []string{"q2"}, ValueSizes: []int{2}},
+			}},
+		{Offset: 20 * time.Millisecond, Method: MethodRead, RowKey: "ab:", QueryType: QueryPrefix,
+			Eventual: true, Families: []FamilyShape{{Family: "f1"}}},
+		{Offset: 30 * time.Millisecond, Method: MethodRead, QueryType: QueryRegex,
+			Families: []FamilyShape{{Family: "f1"}}},
+		{Offset: 40 * time.Millisecond, Method: MethodDelete, RowKey: "ab:1",
+			Families: []FamilyShape{{Family: "f1", Qualifiers: []string{"q1"}}}},
+	}
+
+	c := &testClient{}
+	start := time.Now()
+	report, err := Replay(context.Background(), c, shapes, ReplayOptions{Speed: 2})
+	req.NoError(err)
+	// the requests keep their timing, at twice the speed
+	req.GreaterOrEqual(time.Since(start), 20*time.Millisecond)
+
+	req.Equal([]string{"t1"}, c.tables)
+	req.Equal([]string{"default/f1", "t1/f1"}, c.families)
+
+	req.Len(c.writes, 2)
+	req.Equal("f1", c.writes[0].GetFamily())
+	req.Equal([]byte("xxx"), c.writes[0].GetQualifiers()[0].GetValue())
+	req.Len(c.writes[1].GetFamilies(), 2)
+	req.Equal(bytes.Repeat([]byte{'x'}, 2),
+		c.writes[1].GetFamilies()[1].GetQualifiers()[0].GetValue())
+
+	req.Len(c.reads, 1)
+	req.Equal(proto.QueryType_PREFIX, c.reads[0].GetQueryType())
+	req.Equal(proto.Consistency_EVENTUAL, c.reads[0].GetConsistency())
+	req.Len(c.deletes, 1)
+
+	req.Equal(1, report.Skipped)
+	req.Equal(2, report.Methods[MethodWrite].Requests)
+	req.Empty(report.Methods[MethodWrite].Errors)
+	req.Equal(map[string]int{"NotFound": 1}, report.Methods[MethodRead].Errors)
+	req.Positive(report.Methods[MethodDelete].Latency(1))
+
+	var out bytes.Buffer
+	report.Write(&out)
+	req.Contains(out.String(), "skipped 1 regex reads")
+	req.Contains(out.String(), "write: 2 requests")
+	req.Contains(out.String(), "  NotFound: 1")
+}
+
+func TestReplay_cancelled(t *testing.T) {
+	req := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &testClient{}
+	report, err := Replay(ctx, c, []Shape{{Method: MethodDelete, RowKey: "ab:1"}},
+		ReplayOptions{})
+	req.ErrorIs(err, context.Canceled)
+	req.Empty(report.Methods)
+}
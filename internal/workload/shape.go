@@ -0,0 +1,192 @@
+// Package workload records the shape of the reads, writes and deletes a server receives and
+// replays them against another server, so performance work can be measured against production
+// traffic without copying production data.
+package workload
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"io"
+	"time"
+)
+
+// The methods a Shape can be.
+const (
+	MethodRead   = "read"
+	MethodWrite  = "write"
+	MethodDelete = "delete"
+)
+
+// The query types of a read.
+const (
+	QueryExact  = "exact"
+	QueryPrefix = "prefix"
+	QueryRegex  = "regex"
+)
+
+// Shape is a request with its names anonymized and its values replaced by their sizes.
+type Shape struct {
+	// Offset is the time since recording started
+	Offset time.Duration `json:"offsetNanos"`
+	Method string        `json:"method"`
+	Table  string        `json:"table,omitempty"`
+	// RowKey is the row key or prefix. It is empty for regex reads, whose pattern cannot be
+	// anonymized.
+	RowKey    string `json:"rowKey,omitempty"`
+	QueryType string `json:"queryType,omitempty"`
+	Latest    int32  `json:"latest,omitempty"`
+	Ordered   bool   `json:"ordered,omitempty"`
+	Eventual  bool   `json:"eventual,omitempty"`
+	// Families are the families of the request with their qualifiers and, for writes, the size
+	// of each value
+	Families []FamilyShape `json:"families,omitempty"`
+}
+
+// FamilyShape is a column family of a Shape.
+type FamilyShape struct {
+	Family     string   `json:"family"`
+	Qualifiers []string `json:"qualifiers,omitempty"`
+	ValueSizes []int    `json:"valueSizes,omitempty"`
+}
+
+// anonymizer replaces names with keyed hashes of the same length. Each letter or digit is
+// replaced by a character of a keyed hash chained over the name up to and including it, and the
+// characters between them are kept, so the anonymized prefix of a name is the prefix of its
+// anonymized name: prefix reads such as "champ:1" still match the keys they matched before,
+// and the spread of keys over shards is preserved.
+type anonymizer struct {
+	key []byte
+}
+
+func (a anonymizer) name(s string) string {
+	out := []byte(s)
+	mac := hmac.New(sha256.New, a.key)
+	var sum []byte
+	for i := range out {
+		mac.Reset()
+		mac.Write(sum)
+		mac.Write(out[i : i+1])
+		sum = mac.Sum(sum[:0])
+		if isAlphanumeric(out[i]) {
+			out[i] = hexDigits[sum[0]&0x0f]
+		}
+	}
+	return string(out)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// table keeps the name of the default table, which every server has.
+func (a anonymizer) table(name string) string {
+	if name == litetable.DefaultTable {
+		return name
+	}
+	return a.name(name)
+}
+
+func (a anonymizer) names(names []string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = a.name(name)
+	}
+	return out
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// shapeOf returns the anonymized shape of a read, write or delete, or false for any other
+// request.
+func (a anonymizer) shapeOf(req any) (Shape, bool) {
+	switch msg := req.(type) {
+	case *proto.ReadRequest:
+		shape := Shape{
+			Method:    MethodRead,
+			Table:     a.table(msg.GetTable()),
+			QueryType: QueryExact,
+			Latest:    msg.GetLatest(),
+			Ordered:   msg.GetOrdered(),
+			Eventual:  msg.GetConsistency() == proto.Consistency_EVENTUAL,
+			Families: []FamilyShape{{
+				Family:     a.name(msg.GetFamily()),
+				Qualifiers: a.names(msg.GetQualifiers()),
+			}},
+		}
+		switch msg.GetQueryType() {
+		case proto.QueryType_PREFIX:
+			shape.QueryType = QueryPrefix
+		case proto.QueryType_REGEX:
+			shape.QueryType = QueryRegex
+		}
+		if shape.QueryType != QueryRegex {
+			shape.RowKey = a.name(msg.GetRowKey())
+		}
+		return shape, true
+	case *proto.WriteRequest:
+		shape := Shape{
+			Method: MethodWrite,
+			Table:  a.table(msg.GetTable()),
+			RowKey: a.name(msg.GetRowKey()),
+		}
+		if msg.GetFamily() != "" {
+			shape.Families = append(shape.Families,
+				a.cells(msg.GetFamily(), msg.GetQualifiers()))
+		}
+		for _, cells := range msg.GetFamilies() {
+			shape.Families = append(shape.Families,
+				a.cells(cells.GetFamily(), cells.GetQualifiers()))
+		}
+		return shape, true
+	case *proto.DeleteRequest:
+		return Shape{
+			Method: MethodDelete,
+			Table:  a.table(msg.GetTable()),
+			RowKey: a.name(msg.GetRowKey()),
+			Families: []FamilyShape{{
+				Family:     a.name(msg.GetFamily()),
+				Qualifiers: a.names(msg.GetQualifiers()),
+			}},
+		}, true
+	default:
+		return Shape{}, false
+	}
+}
+
+func (a anonymizer) cells(family string, qualifiers []*proto.ColumnQualifier) FamilyShape {
+	shape := FamilyShape{Family: a.name(family)}
+	for _, q := range qualifiers {
+		shape.Qualifiers = append(shape.Qualifiers, a.name(q.GetName()))
+		shape.ValueSizes = append(shape.ValueSizes, len(q.GetValue()))
+	}
+	return shape
+}
+
+// ReadShapes reads the shapes of a recording, one JSON object per line.
+func ReadShapes(r io.Reader) ([]Shape, error) {
+	var shapes []Shape
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var shape Shape
+		if err := json.Unmarshal(scanner.Bytes(), &shape); err != nil {
+			return nil, fmt.Errorf("line %d is not a valid shape: %w", line, err)
+		}
+		shapes = append(shapes, shape)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return shapes, nil
+}
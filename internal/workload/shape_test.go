@@ -0,0 +1,138 @@
+package workload
+
+import (
+	"github.com/litetable/litetable-db/pkg/proto"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestAnonymizer_name(t *testing.T) {
+	req := require.New(t)
+	a := anonymizer{key: []byte("key")}
+
+	key := a.name("champ:1")
+	req.Len(key, len("champ:1"))
+	req.Equal(byte(':'), key[5])
+	req.NotContains(key, "champ")
+	req.Equal(key, a.name("champ:1"))
+
+	// keys sharing a prefix still share it
+	req.True(strings.HasPrefix(a.name("champ:2"), a.name("champ:")))
+	req.NotEqual(a.name("champ:1"), a.name("champ:2"))
+
+	// any prefix of a name, not only one ending at a separator, is kept
+	req.True(strings.HasPrefix(a.name("champ:12"), a.name("champ:1")))
+	req.True(strings.HasPrefix(a.name("champion"), a.name("cha")))
+	req.NotEqual(a.name("abc")[2], a.name("abd")[2])
+
+	long := strings.Repeat("a", 100)
+	req.Len(a.name(long), 100)
+
+	// another key gives other names
+	req.NotEqual(key, anonymizer{key: []byte("other")}.name("champ:1"))
+}
+
+func TestAnonymizer_shapeOf(t *testing.T) {
+	a := anonymizer{key: []byte("key")}
+
+	tests := map[string]struct {
+		req      any
+		expected Shape
+		ok       bool
+	}{
+		"prefix read": {
+			req: &proto.ReadRequest{
+				RowKey:      "champ:",
+				QueryType:   proto.QueryType_PREFIX,
+				Family:      "main",
+				Qualifiers:  []string{"name"},
+				Latest:      2,
+				Table:       "wwe",
+				Consistency: proto.Consistency_EVENTUAL,
+			},
+			expected: Shape{
+				Method:    MethodRead,
+				Table:     a.name("wwe"),
+				RowKey:    a.name("champ:"),
+				QueryType: QueryPrefix,
+				Latest:    2,
+				Eventual:  true,
+				Families: []FamilyShape{
+					{Family: a.name("main"), Qualifiers: []string{a.name("name")}},
+				},
+			},
+			ok: true,
+		},
+		"regex read drops the pattern": {
+			req: &proto.ReadRequest{RowKey: "^champ", QueryType: proto.QueryType_REGEX,
+				Family: "main"},
+			expected: Shape{
+				Method:    MethodRead,
+				QueryType: QueryRegex,
+				Families:  []FamilyShape{{Family: a.name("main")}},
+			},
+			ok: true,
+		},
+		"write keeps value sizes": {
+			req: &proto.WriteRequest{
+				RowKey:     "champ:1",
+				Family:     "main",
+				Qualifiers: []*proto.ColumnQualifier{{Name: "name", Value: []byte("Ahri")}},
+				Table:      "default",
+				Families: []*proto.FamilyCells{{
+					Family:     "stats",
+					Qualifiers: []*proto.ColumnQualifier{{Name: "wins", Value: []byte("12")}},
+				}},
+			},
+			expected: Shape{
+				Method: MethodWrite,
+				Table:  "default",
+				RowKey: a.name("champ:1"),
+				Families: []FamilyShape{
+					{Family: a.name("main"), Qualifiers: []string{a.name("name")},
+						ValueSizes: []int{4}},
+					{Family: a.name("stats"), Qualifiers: []string{a.name("wins")},
+						ValueSizes: []int{2}},
+				},
+			},
+			ok: true,
+		},
+		"delete": {
+			req: &proto.DeleteRequest{RowKey: "champ:1", Family: "main"},
+			expected: Shape{
+				Method:   MethodDelete,
+				RowKey:   a.name("champ:1"),
+				Families: []FamilyShape{{Family: a.name("main")}},
+			},
+			ok: true,
+		},
+		"other requests are not recorded": {
+			req: &proto.CreateTableRequest{Name: "wwe"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			shape, ok := a.shapeOf(tc.req)
+			req.Equal(tc.ok, ok)
+			req.Equal(tc.expected, shape)
+		})
+	}
+}
+
+func TestReadShapes(t *testing.T) {
+	req := require.New(t)
+	shapes, err := ReadShapes(strings.NewReader(
+		`{"offsetNanos":5,"method":"read","rowKey":"ab:1","queryType":"exact"}` + "\n\n" +
+			`{"offsetNanos":9,"method":"delete","rowKey":"ab:2"}` + "\n"))
+	req.NoError(err)
+	req.Equal([]Shape{
+		{Offset: 5, Method: MethodRead, RowKey: "ab:1", QueryType: QueryExact},
+		{Offset: 9, Method: MethodDelete, RowKey: "ab:2"},
+	}, shapes)
+
+	_, err = ReadShapes(strings.NewReader("{\n"))
+	req.ErrorContains(err, "line 1 is not a valid shape")
+}
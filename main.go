@@ -2,34 +2,70 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/litetable/litetable-db/internal/app"
+	"github.com/litetable/litetable-db/internal/buildinfo"
 	v1 "github.com/litetable/litetable-db/internal/cdc_emitter/v1"
 	"github.com/litetable/litetable-db/internal/config"
+	"github.com/litetable/litetable-db/internal/engine"
+	"github.com/litetable/litetable-db/internal/litetable"
+	"github.com/litetable/litetable-db/internal/logging"
 	"github.com/litetable/litetable-db/internal/operations"
 	"github.com/litetable/litetable-db/internal/server"
 	"github.com/litetable/litetable-db/internal/server/grpc"
 	"github.com/litetable/litetable-db/internal/shard_storage"
+	"github.com/litetable/litetable-db/internal/shard_storage/reaper"
 
 	"github.com/litetable/litetable-db/internal/shard_storage/wal"
-	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
+	"github.com/litetable/litetable-db/internal/workload"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	defaultDir        = ".litetable"
-	defaultServerCert = "server.crt"
-	defaultServerKey  = "server.key"
+	defaultDir = ".litetable"
+	// shardCount is the number of shards of every table
+	shardCount = 8
+)
 
-	// googleSeverityKey is the key used for severity in Google Cloud Logging to conform to their
-	// Stackdriver logging format
-	googleSeverityKey = "severity"
+// Startup phases: storage loads its backup before the reaper replays its log against it, what
+// was loaded is reported, and the servers only accept traffic once both are running.
+const (
+	phaseStorage = iota
+	phaseMaintenance
+	phaseReport
+	phaseServing
 )
 
 func main() {
+	// litetable-db config validate [flags]
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "validate" {
+		os.Exit(validateConfig(os.Args[3:]))
+	}
+	// litetable-db verify [--dir <data directory>]
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		os.Exit(verify(os.Args[2:]))
+	}
+	// litetable-db shards [--dir <data directory>] [--counts 4,8,16]
+	if len(os.Args) > 1 && os.Args[1] == "shards" {
+		os.Exit(shards(os.Args[2:]))
+	}
+	// litetable-db --validate-restore [--dir <data directory>]
+	if len(os.Args) > 1 && os.Args[1] == "--validate-restore" {
+		os.Exit(validateRestore(os.Args[2:]))
+	}
+
 	application, err := initialize()
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -42,12 +78,15 @@ func main() {
 func initialize() (*app.App, error) {
 	var deps []app.Dependency
 
-	cfg, err := config.NewConfig()
+	cfg, err := config.NewConfig(os.Args[1:])
 	if err != nil {
 		return nil, err
 	}
 
-	initLogging(cfg)
+	logging.Init(&logging.Config{
+		Debug:            cfg.Debug,
+		CloudEnvironment: cfg.CloudEnvironment,
+	})
 
 	// load the defaults from the os.HomeDir
 	homeDir, err := os.UserHomeDir()
@@ -58,9 +97,21 @@ func initialize() (*app.App, error) {
 	// get the filepath
 	certDir := filepath.Join(homeDir, defaultDir)
 
-	// create a new CDC Stream Server
-	cdcStreamServer := v1.New()
-	deps = append(deps, cdcStreamServer)
+	// create a new CDC Stream Server, keeping the events no subscriber received for replay
+	cfg.CDC.SpillDir = filepath.Join(certDir, "cdc")
+	cdcStreamServer := v1.New(&cfg.CDC)
+	// a disabled stream drops every change and is never served
+	if !cfg.CDC.Disabled {
+		// the CDC stream is not needed to serve reads and writes, so it is restarted rather
+		// than taking the database down
+		deps = append(deps, app.WithRestart(app.RestartPolicy{
+			MaxRetries:        5,
+			Backoff:           time.Second,
+			MaxBackoff:        30 * time.Second,
+			CrashLoopFailures: 10,
+			CrashLoopWindow:   10 * time.Minute,
+		}, cdcStreamServer))
+	}
 
 	// create the WAL manager
 	walManager, err := wal.New(&wal.Config{
@@ -70,46 +121,145 @@ func initialize() (*app.App, error) {
 		return nil, err
 	}
 
-	// create a shard manager
-	shardManager, garbageCollector, err := shard_storage.New(&shard_storage.Config{
-		RootDir:          certDir,
-		FlushThreshold:   cfg.BackupTimer,
-		SnapshotTimer:    cfg.SnapshotTimer,
-		MaxSnapshotLimit: cfg.MaxSnapshotLimit,
-		ShardCount:       8,
-		CDCEmitter:       cdcStreamServer,
-	})
+	// every table writes its snapshots and backups within one budget, since they share the disk
+	writeThrottle := shard_storage.NewWriteThrottle(int64(cfg.BackgroundWriteRate))
+	// and every table stops saving snapshots and backups when the disk is low on space
+	disk := shard_storage.NewDiskMonitor(certDir, int64(cfg.MinFreeDiskBytes),
+		cfg.RejectWritesOnLowDisk)
+
+	// every table, including the default table in the root of the data directory, opens the
+	// same engine in its own directory
+	openEngine := func(table, dir string) (engine.StorageEngine, []app.Dependency, error) {
+		return engine.Open(&engine.Config{
+			Engine: cfg.StorageEngine,
+			Shadow: cfg.ShadowEngine,
+			Memory: &shard_storage.Config{
+				RootDir:            dir,
+				FlushThreshold:     cfg.BackupTimer,
+				SnapshotTimer:      cfg.SnapshotTimer,
+				MaxSnapshotLimit:   cfg.MaxSnapshotLimit,
+				ShardCount:         shardCount,
+				GCInterval:         cfg.GarbageCollectionTimer,
+				CDCEmitter:         cdcStreamServer,
+				Table:              table,
+				CDCOldValues:       cfg.CDCOldValues,
+				CDCDurable:         cfg.CDCDurable,
+				CDCOutboxSize:      cfg.CDCOutboxSize,
+				CDCOverflowPolicy:  cfg.CDC.OverflowPolicy,
+				FamilyPolicies:     cfg.FamilyPolicies,
+				BackupRetention:    cfg.BackupRetention,
+				FullBackupInterval: cfg.FullBackupInterval,
+				Quota:              cfg.TableQuotas[table],
+				StatsInterval:      cfg.StatsInterval,
+				MaxRegexProgram:    cfg.MaxRegexProgram,
+				RegexScanBudget:    cfg.RegexScanBudget,
+				ScanCacheTTL:       cfg.ScanCacheTTL,
+				ReadViewInterval:   cfg.ReadViewInterval,
+				MaintenanceWindows: cfg.MaintenanceWindows,
+				WriteThrottle:      writeThrottle,
+				DiskMonitor:        disk,
+			},
+		})
+	}
+
+	// open the storage engine of the default table
+	storage, maintenance, err := openEngine(litetable.DefaultTable, certDir)
 	if err != nil {
 		return nil, err
 	}
 
-	deps = append(deps, shardManager, garbageCollector)
+	deps = append(deps, app.InPhase(phaseStorage, storage))
+	for _, dep := range maintenance {
+		deps = append(deps, app.InPhase(phaseMaintenance, dep))
+	}
+
+	// the catalog opens the other tables and starts their maintenance itself
+	tables, err := engine.NewCatalog(&engine.CatalogConfig{
+		Dir:  certDir,
+		Open: openEngine,
+	})
+	if err != nil {
+		return nil, err
+	}
+	deps = append(deps, app.InPhase(phaseStorage, tables))
 
+	// writes are held to the row key and family name limits of the gRPC requests
+	names, err := litetable.NewNameRules(cfg.GRPCServer.Limits.MaxRowKeyLength,
+		cfg.GRPCServer.Limits.FamilyNamePattern)
+	if err != nil {
+		return nil, err
+	}
 	opsManager, err := operations.New(&operations.Config{
 		WAL:          walManager,
-		ShardStorage: shardManager,
+		ShardStorage: storage,
+		Tables:       tables,
+		Names:        names,
+		Disk:         disk,
+		// under sustained overload, mutations are refused rather than queued behind the WAL,
+		// the snapshots, the CDC stream or the reapers
+		Backlogs: []operations.Backlog{
+			{Name: "wal", Depth: walManager.Backlog, Limit: cfg.MaxWALBacklog},
+			{Name: "snapshot", Depth: shard_storage.SnapshotBacklog,
+				Limit: cfg.MaxSnapshotBacklog},
+			{Name: "cdc", Depth: cdcStreamServer.QueueDepth, Limit: cfg.MaxCDCQueue},
+			{Name: "reaper", Depth: reaper.QueueDepth, Limit: cfg.MaxReaperQueue},
+		},
+		Coalesce: &operations.CoalesceConfig{
+			MaxBatch: cfg.WriteBatchSize,
+			MaxDelay: cfg.WriteBatchDelay,
+		},
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// both servers report the build and storage setup, for checking compatibility in rollouts
+	info := buildinfo.New(cfg.StorageEngine, shardCount)
+
 	// create the gRPC server
 	cfg.GRPCServer.Operations = opsManager
+	if !cfg.CDC.Disabled {
+		cfg.GRPCServer.Watcher = cdcStreamServer
+		cfg.GRPCServer.Subscribers = cdcStreamServer
+	}
+	cfg.GRPCServer.Info = info
+	// a sample of the traffic can be recorded for replay against a test server
+	if cfg.RecordQueriesFile != "" {
+		recorder, err := workload.NewRecorder(cfg.RecordQueriesFile, cfg.RecordQueriesRate)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GRPCServer.Recorder = recorder
+		deps = append(deps, app.InPhase(phaseReport, recorder))
+	}
 	grpcServer, err := grpc.NewServer(&cfg.GRPCServer)
 	if err != nil {
 		return nil, err
 	}
-	deps = append(deps, grpcServer)
+	deps = append(deps, app.InPhase(phaseServing, grpcServer))
 
+	cfg.Server.Info = info
 	httpSrv, err := server.New(&cfg.Server)
 	if err != nil {
 		return nil, err
 	}
 
-	deps = append(deps, httpSrv)
+	deps = append(deps, app.InPhase(phaseServing, httpSrv))
+
+	addresses := map[string]string{"grpc": grpcServer.Addr(), "http": httpSrv.Addr()}
+	if !cfg.CDC.Disabled {
+		addresses["cdc"] = cdcStreamServer.Addr()
+	}
+	deps = append(deps, app.InPhase(phaseReport, &startupReport{
+		info:      info,
+		storage:   storage,
+		tables:    tables,
+		addresses: addresses,
+	}))
 	application, err := app.CreateApp(&app.Config{
 		ServiceName: "LiteTable DB",
-		StopTimeout: 30,
+		StopTimeout: 30 * time.Second,
+		Reload:      reload(grpcServer, opsManager, writeThrottle, storage, tables),
 	}, deps...)
 	if err != nil {
 		return nil, err
@@ -118,48 +268,232 @@ func initialize() (*app.App, error) {
 	return application, nil
 }
 
-func initLogging(cfg *config.Config) {
-	// if deployed to google, change the severity key
-	if cfg.CloudEnvironment == "google" {
-		zerolog.LevelFieldName = googleSeverityKey
+// validateConfig loads and checks the configuration without starting the server, returning the
+// process exit code.
+func validateConfig(args []string) int {
+	if _, err := config.NewConfig(args); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("configuration is valid")
+	return 0
+}
+
+// verify checks the backups and snapshots in the data directory and prints a report, returning
+// the process exit code.
+func verify(args []string) int {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find home directory: %v\n", err)
+		return 1
+	}
+
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	dir := flags.String("dir", filepath.Join(homeDir, defaultDir), "data directory to verify")
+	if err = flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	report, err := shard_storage.Verify(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verification failed: %v\n", err)
+		return 1
+	}
+
+	report.Write(os.Stdout)
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// shards reports how evenly the rows in a data directory spread over the shards, and how they
+// would spread over other shard counts, returning the process exit code.
+func shards(args []string) int {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find home directory: %v\n", err)
+		return 1
+	}
+
+	flags := flag.NewFlagSet("shards", flag.ContinueOnError)
+	dir := flags.String("dir", filepath.Join(homeDir, defaultDir),
+		"data directory of the table to analyze")
+	countList := flags.String("counts", "4,8,16,32", "comma separated shard counts to simulate")
+	if err = flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	var counts []int
+	for _, field := range strings.Split(*countList, ",") {
+		count, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid shard count %q\n", field)
+			return 2
+		}
+		counts = append(counts, count)
+	}
+
+	report, err := shard_storage.AnalyzeBalance(*dir, shardCount, counts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "analysis failed: %v\n", err)
+		return 1
+	}
+	report.Write(os.Stdout)
+	return 0
+}
+
+// validateRestore restores every table in the data directory into memory, as the server would on
+// start, and prints what each one loaded without serving traffic, returning the process exit
+// code.
+func validateRestore(args []string) int {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find home directory: %v\n", err)
+		return 1
 	}
 
-	// for sanity's sake - make the dev logs easier to read and parse
-	if cfg.Debug {
-		output := zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			TimeFormat: time.RFC3339,
-			NoColor:    false,
+	flags := flag.NewFlagSet("validate-restore", flag.ContinueOnError)
+	dir := flags.String("dir", filepath.Join(homeDir, defaultDir), "data directory to restore")
+	if err = flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
 		}
-		output.FormatLevel = func(i interface{}) string {
-			level, ok := i.(string)
+		return 2
+	}
+
+	tableDirs, err := engine.TableDirs(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "restore failed: %v\n", err)
+		return 1
+	}
+	tableDirs[litetable.DefaultTable] = *dir
+	tables := slices.Sorted(maps.Keys(tableDirs))
+
+	ok := true
+	for _, table := range tables {
+		report, err := shard_storage.ValidateRestore(tableDirs[table], table, shardCount)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "restore of table %s failed: %v\n", table, err)
+			return 1
+		}
+		fmt.Printf("table %s\n", table)
+		report.Write(os.Stdout)
+		ok = ok && report.OK()
+	}
+
+	entries, err := wal.CountEntries(*dir)
+	if err != nil {
+		fmt.Printf("wal: %v\n", err)
+		return 1
+	}
+	fmt.Printf("wal: %d entries\n", entries)
+
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// timerSetter is a storage engine or table catalog with snapshot, backup and garbage
+// collection timers.
+type timerSetter interface {
+	SetTimers(snapshot, backup, gc int) error
+}
+
+// reload re-reads the configuration and applies the settings that can change at runtime: the
+// snapshot, backup and garbage collection timers of every table, the background write rate, the
+// request limits, with the row key and family name rules they set, and the log level.
+func reload(grpcServer *grpc.Server, ops *operations.Manager,
+	writeThrottle *shard_storage.WriteThrottle, storage ...app.Dependency) func() error {
+	return func() error {
+		cfg, err := config.NewConfig(os.Args[1:])
+		if err != nil {
+			return err
+		}
+
+		// every setting is checked before any is applied, so an invalid configuration leaves
+		// all the running settings as they were
+		if err = grpc.ValidateLimits(cfg.GRPCServer.Limits); err != nil {
+			return err
+		}
+		names, err := litetable.NewNameRules(cfg.GRPCServer.Limits.MaxRowKeyLength,
+			cfg.GRPCServer.Limits.FamilyNamePattern)
+		if err != nil {
+			return err
+		}
+		if err = shard_storage.ValidateTimers(cfg.SnapshotTimer, cfg.BackupTimer,
+			cfg.GarbageCollectionTimer); err != nil {
+			return err
+		}
+
+		if err = grpcServer.SetLimits(cfg.GRPCServer.Limits); err != nil {
+			return err
+		}
+		ops.SetNameRules(names)
+		for _, s := range storage {
+			timers, ok := s.(timerSetter)
 			if !ok {
-				return "???"
+				continue
 			}
-
-			switch level {
-			case "debug":
-				return "\x1b[35m" + "DEBUG" + "\x1b[0m" // Purple for debug
-			case "info":
-				return "\x1b[32m" + "INFO " + "\x1b[0m" // Green for info
-			case "warn":
-				return "\x1b[33m" + "WARN " + "\x1b[0m" // Yellow for warn
-			case "error":
-				return "\x1b[31m" + "ERROR" + "\x1b[0m" // Red for error
-			case "fatal", "panic":
-				return "\x1b[41m" + level + "\x1b[0m" // White on red background
-			default:
-				return level
+			if err = timers.SetTimers(cfg.SnapshotTimer, cfg.BackupTimer,
+				cfg.GarbageCollectionTimer); err != nil {
+				return err
 			}
 		}
+		writeThrottle.SetRate(int64(cfg.BackgroundWriteRate))
+		logging.SetDebug(cfg.Debug)
+		return nil
+	}
+}
+
+// startupReport logs what every table loaded and where the servers listen, and has the servers
+// report it. It starts once every table is loaded, before the servers accept traffic.
+type startupReport struct {
+	info      *buildinfo.Info
+	storage   engine.StorageEngine
+	tables    *engine.Catalog
+	addresses map[string]string
+}
+
+func (r *startupReport) Start() error {
+	startup := &buildinfo.Startup{
+		Tables:    []litetable.LoadReport{r.storage.LoadReport()},
+		Addresses: r.addresses,
+	}
+	for _, name := range r.tables.ListTables() {
+		table, err := r.tables.Table(name)
+		if err != nil {
+			return err
+		}
+		startup.Tables = append(startup.Tables, table.LoadReport())
+	}
+	slices.SortFunc(startup.Tables, func(a, b litetable.LoadReport) int {
+		return strings.Compare(a.Table, b.Table)
+	})
+	r.info.Startup = startup
 
-		output.Out = os.Stderr
-		output.NoColor = false
-		zerolog.SetGlobalLevel(zerolog.DebugLevel) // always start with debug for base logging
-		// Set the global logger output
-		log.Logger = zerolog.New(output).With().Timestamp().Logger()
-	} else {
-		zerolog.SetGlobalLevel(zerolog.InfoLevel) // set to info for production
+	rows := 0
+	for _, t := range startup.Tables {
+		rows += t.Rows
 	}
+	logger := logging.For("startup")
+	logger.Info().Int("tables", len(startup.Tables)).Int("rows", rows).
+		Any("addresses", startup.Addresses).Msg("Startup complete")
+	return nil
+}
+
+func (r *startupReport) Stop() error {
+	return nil
+}
 
+func (r *startupReport) Name() string {
+	return "Startup Report"
 }
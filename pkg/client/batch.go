@@ -0,0 +1,258 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/pkg/proto"
+	protobuf "google.golang.org/protobuf/proto"
+	"sync"
+	"time"
+)
+
+// Defaults of the BatchConfig settings left unset.
+const (
+	defaultBatchMutations   = 100
+	defaultBatchBytes       = 1 << 20
+	defaultBatchInterval    = 100 * time.Millisecond
+	defaultBatchConcurrency = 16
+)
+
+// ErrBatchClosed is returned when adding a write to a closed BatchWriter.
+var ErrBatchClosed = errors.New("batch writer is closed")
+
+// BatchConfig sets when a BatchWriter sends its writes and how it reports them.
+type BatchConfig struct {
+	// MaxMutations sends the pending writes once there are this many. Defaults to 100.
+	MaxMutations int
+	// MaxBytes sends the pending writes once their encoded size reaches it. Defaults to 1 MiB.
+	MaxBytes int
+	// FlushInterval is the longest a write waits to be sent. Defaults to 100ms.
+	FlushInterval time.Duration
+	// Concurrency is the most rows written at once. Defaults to 16.
+	Concurrency int
+	// OnResult is optional. It is called with every write once it is applied, with a nil
+	// error, or has failed. It may be called from several goroutines at once.
+	OnResult func(req *proto.WriteRequest, err error)
+}
+
+func (c BatchConfig) withDefaults() BatchConfig {
+	if c.MaxMutations <= 0 {
+		c.MaxMutations = defaultBatchMutations
+	}
+	if c.MaxBytes <= 0 {
+		c.MaxBytes = defaultBatchBytes
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = defaultBatchInterval
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = defaultBatchConcurrency
+	}
+	return c
+}
+
+// BatchWriter accumulates writes and sends them in batches, once enough are pending or the
+// oldest has waited FlushInterval. Batches are sent one after the other, so the writes of one
+// row are applied in the order they were added, while the rows of a batch are written
+// concurrently. Each write succeeds or fails on its own: a failed write is reported to OnResult
+// and by the next Flush, and does not stop the others.
+type BatchWriter struct {
+	ctx    context.Context
+	client proto.LitetableServiceClient
+	cfg    BatchConfig
+
+	mutex   sync.Mutex
+	pending []*proto.WriteRequest
+	bytes   int
+	closed  bool
+	// done stops the sender once the writes left at Close are sent
+	done bool
+	// queue are the batches waiting to be sent, oldest first, and queued is signalled when one
+	// is added
+	queue  [][]*proto.WriteRequest
+	queued *sync.Cond
+	// inflight counts the batches queued or being sent, and idle is signalled when it drops to
+	// zero
+	inflight int
+	idle     *sync.Cond
+
+	errMutex sync.Mutex
+	// errs are the failed writes since the last Flush
+	errs []error
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewBatchWriter starts a batch writer sending its writes with client. Writes are sent with
+// ctx, so cancelling it fails the writes that are not sent yet.
+func NewBatchWriter(ctx context.Context, client proto.LitetableServiceClient,
+	cfg *BatchConfig) *BatchWriter {
+	var c BatchConfig
+	if cfg != nil {
+		c = *cfg
+	}
+	b := &BatchWriter{
+		ctx:     ctx,
+		client:  client,
+		cfg:     c.withDefaults(),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	b.queued = sync.NewCond(&b.mutex)
+	b.idle = sync.NewCond(&b.mutex)
+	go b.sendLoop()
+	go b.flushLoop()
+	return b
+}
+
+// Add queues a write. Its result is reported to OnResult and by Flush.
+func (b *BatchWriter) Add(req *proto.WriteRequest) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return ErrBatchClosed
+	}
+	b.pending = append(b.pending, req)
+	b.bytes += protobuf.Size(req)
+	if len(b.pending) >= b.cfg.MaxMutations || b.bytes >= b.cfg.MaxBytes {
+		b.enqueue()
+	}
+	return nil
+}
+
+// Flush sends the pending writes and waits until every write added so far is applied or has
+// failed. It returns the writes that failed since the last Flush, joined.
+func (b *BatchWriter) Flush() error {
+	b.mutex.Lock()
+	b.enqueue()
+	for b.inflight > 0 {
+		b.idle.Wait()
+	}
+	b.mutex.Unlock()
+
+	b.errMutex.Lock()
+	defer b.errMutex.Unlock()
+	err := errors.Join(b.errs...)
+	b.errs = nil
+	return err
+}
+
+// Close flushes the pending writes and stops the writer. Writes added after Close are refused.
+func (b *BatchWriter) Close() error {
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mutex.Unlock()
+
+	close(b.stop)
+	err := b.Flush()
+
+	b.mutex.Lock()
+	b.done = true
+	b.queued.Broadcast()
+	b.mutex.Unlock()
+	<-b.stopped
+	return err
+}
+
+// enqueue queues the pending writes as a batch. The caller holds the lock.
+func (b *BatchWriter) enqueue() {
+	if len(b.pending) == 0 {
+		return
+	}
+	b.queue = append(b.queue, b.pending)
+	b.pending, b.bytes = nil, 0
+	b.inflight++
+	b.queued.Signal()
+}
+
+// flushLoop queues the pending writes every FlushInterval.
+func (b *BatchWriter) flushLoop() {
+	ticker := time.NewTicker(b.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.mutex.Lock()
+			b.enqueue()
+			b.mutex.Unlock()
+		}
+	}
+}
+
+// sendLoop sends the queued batches in order until the writer is done.
+func (b *BatchWriter) sendLoop() {
+	defer close(b.stopped)
+	for {
+		b.mutex.Lock()
+		for len(b.queue) == 0 && !b.done {
+			b.queued.Wait()
+		}
+		if len(b.queue) == 0 {
+			b.mutex.Unlock()
+			return
+		}
+		batch := b.queue[0]
+		b.queue = b.queue[1:]
+		b.mutex.Unlock()
+
+		b.send(batch)
+
+		b.mutex.Lock()
+		if b.inflight--; b.inflight == 0 {
+			b.idle.Broadcast()
+		}
+		b.mutex.Unlock()
+	}
+}
+
+// send writes a batch, a row at a time in the order its writes were added, and up to
+// Concurrency rows at once.
+func (b *BatchWriter) send(batch []*proto.WriteRequest) {
+	var order []string
+	rows := make(map[string][]*proto.WriteRequest)
+	for _, req := range batch {
+		if _, ok := rows[req.GetRowKey()]; !ok {
+			order = append(order, req.GetRowKey())
+		}
+		rows[req.GetRowKey()] = append(rows[req.GetRowKey()], req)
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, b.cfg.Concurrency)
+	for _, rowKey := range order {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			for _, req := range rows[rowKey] {
+				b.write(req)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// write sends one write and reports its result.
+func (b *BatchWriter) write(req *proto.WriteRequest) {
+	_, err := b.client.Write(b.ctx, req)
+	if b.cfg.OnResult != nil {
+		b.cfg.OnResult(req, err)
+	}
+	if err != nil {
+		b.errMutex.Lock()
+		b.errs = append(b.errs, fmt.Errorf("row %s: %w", req.GetRowKey(), err))
+		b.errMutex.Unlock()
+	}
+}
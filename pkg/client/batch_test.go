@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingClient keeps the writes sent to it, failing those of the rows in fail.
+type recordingClient struct {
+	proto.LitetableServiceClient
+	fail map[string]bool
+
+	mutex  sync.Mutex
+	writes []*proto.WriteRequest
+}
+
+func (r *recordingClient) Write(_ context.Context, in *proto.WriteRequest,
+	_ ...grpc.CallOption) (*proto.LitetableData, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.fail[in.GetRowKey()] {
+		return nil, status.Error(codes.FailedPrecondition, "family not allowed")
+	}
+	r.writes = append(r.writes, in)
+	return &proto.LitetableData{}, nil
+}
+
+func (r *recordingClient) count() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.writes)
+}
+
+// write is a write of a row and value.
+func write(rowKey, value string) *proto.WriteRequest {
+	return &proto.WriteRequest{RowKey: rowKey, Family: "main", Qualifiers: []*proto.ColumnQualifier{
+		{Name: "name", Value: []byte(value)},
+	}}
+}
+
+func TestBatchWriter(t *testing.T) {
+	client := &recordingClient{fail: map[string]bool{"champ:bad": true}}
+	var mutex sync.Mutex
+	results := make(map[string]error)
+	b := NewBatchWriter(context.Background(), client, &BatchConfig{
+		MaxMutations:  4,
+		FlushInterval: time.Hour,
+		OnResult: func(req *proto.WriteRequest, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			results[req.GetRowKey()+"="+string(req.GetQualifiers()[0].GetValue())] = err
+		},
+	})
+
+	// a full batch is sent without waiting for the interval
+	for i := range 4 {
+		if err := b.Add(write(fmt.Sprintf("champ:%d", i), "Ahri")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for client.count() < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d writes, want 4", client.count())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// the writes of a row keep their order, and a failed write does not stop the others
+	for _, req := range []*proto.WriteRequest{
+		write("champ:1", "Jinx"), write("champ:bad", "Vi"), write("champ:1", "Zed"),
+	} {
+		if err := b.Add(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := b.Flush()
+	if status.Code(err) != codes.FailedPrecondition ||
+		!strings.HasPrefix(err.Error(), "row champ:bad: ") {
+		t.Fatalf("got %v, want the failed write of champ:bad", err)
+	}
+	var champ1 []string
+	for _, req := range client.writes {
+		if req.GetRowKey() == "champ:1" {
+			champ1 = append(champ1, string(req.GetQualifiers()[0].GetValue()))
+		}
+	}
+	if strings.Join(champ1, ",") != "Ahri,Jinx,Zed" {
+		t.Fatalf("got champ:1 writes %v, want Ahri,Jinx,Zed", champ1)
+	}
+	if len(results) != 7 || results["champ:bad=Vi"] == nil || results["champ:1=Zed"] != nil {
+		t.Fatalf("got results %v", results)
+	}
+
+	// the failures are reported once
+	if err = b.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = b.Add(write("champ:9", "Ahri")); !errors.Is(err, ErrBatchClosed) {
+		t.Fatalf("got %v, want %v", err, ErrBatchClosed)
+	}
+}
+
+func TestBatchWriter_interval(t *testing.T) {
+	client := &recordingClient{}
+	b := NewBatchWriter(context.Background(), client, &BatchConfig{
+		FlushInterval: 10 * time.Millisecond,
+	})
+	defer func() { _ = b.Close() }()
+
+	if err := b.Add(write("champ:1", "Ahri")); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for client.count() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the write was not sent after the flush interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBatchWriter_Close(t *testing.T) {
+	client := &recordingClient{}
+	b := NewBatchWriter(context.Background(), client, &BatchConfig{FlushInterval: time.Hour})
+	for i := range 10 {
+		if err := b.Add(write(fmt.Sprintf("champ:%d", i), "Ahri")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// closing sends what is pending
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if client.count() != 10 {
+		t.Fatalf("got %d writes, want 10", client.count())
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
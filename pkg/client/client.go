@@ -0,0 +1,175 @@
+// Package client is the Go client of LiteTable. It wraps the generated gRPC client, adding
+// retries with exponential backoff and hedging to the reads, which are safe to send more than
+// once. Writes and the other RPCs are sent once, as the generated client sends them.
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc"
+	"time"
+)
+
+// Client is a LiteTable client. Every RPC of the service is available on it.
+type Client struct {
+	proto.LitetableServiceClient
+	// replicas are the other servers a read is hedged against
+	replicas   []proto.LitetableServiceClient
+	retry      RetryPolicy
+	hedgeDelay time.Duration
+}
+
+type Config struct {
+	// Conn is the connection to the server.
+	Conn grpc.ClientConnInterface
+	// Replicas are connections to more servers holding the same data. Reads are hedged against
+	// them in order.
+	Replicas []grpc.ClientConnInterface
+	// Retry sets how reads that fail with a transient error are retried. Zero values use the
+	// defaults.
+	Retry RetryPolicy
+	// HedgeDelay is how long a read waits for an answer before it is also sent to the next
+	// replica. Zero turns hedging off.
+	HedgeDelay time.Duration
+}
+
+func (c *Config) validate() error {
+	var errGrp []error
+	if c.Conn == nil {
+		errGrp = append(errGrp, errors.New("conn required"))
+	}
+	if c.HedgeDelay < 0 {
+		errGrp = append(errGrp, errors.New("hedge delay cannot be negative"))
+	}
+	errGrp = append(errGrp, c.Retry.validate())
+	return errors.Join(errGrp...)
+}
+
+// New creates a client on the connections of cfg. The connections stay owned by the caller.
+func New(cfg *Config) (*Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		LitetableServiceClient: proto.NewLitetableServiceClient(cfg.Conn),
+		retry:                  cfg.Retry.withDefaults(),
+		hedgeDelay:             cfg.HedgeDelay,
+	}
+	for _, conn := range cfg.Replicas {
+		c.replicas = append(c.replicas, proto.NewLitetableServiceClient(conn))
+	}
+	return c, nil
+}
+
+// Read reads rows, retrying and hedging the read.
+func (c *Client) Read(ctx context.Context, in *proto.ReadRequest,
+	opts ...grpc.CallOption) (*proto.LitetableData, error) {
+	return read(ctx, c, func(ctx context.Context,
+		client proto.LitetableServiceClient) (*proto.LitetableData, error) {
+		return client.Read(ctx, in, opts...)
+	})
+}
+
+// Aggregate computes aggregations over rows, retrying and hedging the read.
+func (c *Client) Aggregate(ctx context.Context, in *proto.AggregateRequest,
+	opts ...grpc.CallOption) (*proto.AggregateResponse, error) {
+	return read(ctx, c, func(ctx context.Context,
+		client proto.LitetableServiceClient) (*proto.AggregateResponse, error) {
+		return client.Aggregate(ctx, in, opts...)
+	})
+}
+
+// ListQualifiers lists the qualifiers of a row's family, retrying and hedging the read.
+func (c *Client) ListQualifiers(ctx context.Context, in *proto.ListQualifiersRequest,
+	opts ...grpc.CallOption) (*proto.ListQualifiersResponse, error) {
+	return read(ctx, c, func(ctx context.Context,
+		client proto.LitetableServiceClient) (*proto.ListQualifiersResponse, error) {
+		return client.ListQualifiers(ctx, in, opts...)
+	})
+}
+
+// read sends a read, retrying it until it succeeds, fails with an error that is not transient or
+// runs out of attempts.
+func read[T any](ctx context.Context, c *Client,
+	call func(context.Context, proto.LitetableServiceClient) (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := hedge(ctx, c, call)
+		if err == nil || attempt == c.retry.MaxAttempts {
+			return resp, err
+		}
+		wait, retry := c.retry.backoff(attempt, err)
+		if !retry {
+			return resp, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		case <-timer.C:
+		}
+	}
+}
+
+// result is the answer of one server to a hedged read.
+type result[T any] struct {
+	resp T
+	err  error
+}
+
+// hedge sends a read to the server, and to the next replica each time HedgeDelay passes without
+// an answer or a server fails with a transient error. The first success or error that is not
+// transient is returned, and the reads still running are cancelled.
+func hedge[T any](ctx context.Context, c *Client,
+	call func(context.Context, proto.LitetableServiceClient) (T, error)) (T, error) {
+	if c.hedgeDelay == 0 || len(c.replicas) == 0 {
+		return call(ctx, c.LitetableServiceClient)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clients := append([]proto.LitetableServiceClient{c.LitetableServiceClient}, c.replicas...)
+	results := make(chan result[T], len(clients))
+	send := func(client proto.LitetableServiceClient) {
+		go func() {
+			resp, err := call(ctx, client)
+			results <- result[T]{resp: resp, err: err}
+		}()
+	}
+
+	send(clients[0])
+	sent, running := 1, 1
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+
+	var last result[T]
+	for {
+		select {
+		case <-timer.C:
+			if sent < len(clients) {
+				send(clients[sent])
+				sent++
+				running++
+				timer.Reset(c.hedgeDelay)
+			}
+		case r := <-results:
+			running--
+			if r.err == nil || !retryable(r.err) {
+				return r.resp, r.err
+			}
+			last = r
+			if sent < len(clients) {
+				send(clients[sent])
+				sent++
+				running++
+				timer.Reset(c.hedgeDelay)
+			} else if running == 0 {
+				return last.resp, last.err
+			}
+		}
+	}
+}
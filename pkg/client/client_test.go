@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testServer fails the reads it is sent with errs in turn, then answers them after delay.
+type testServer struct {
+	proto.UnimplementedLitetableServiceServer
+	errs  []error
+	delay time.Duration
+	reads atomic.Int32
+}
+
+func (s *testServer) Read(ctx context.Context, msg *proto.ReadRequest) (*proto.LitetableData,
+	error) {
+	n := int(s.reads.Add(1))
+	if n <= len(s.errs) {
+		return nil, s.errs[n-1]
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &proto.LitetableData{Rows: map[string]*proto.Row{
+		msg.GetRowKey(): {Key: msg.GetRowKey()},
+	}}, nil
+}
+
+// serve starts a server and returns a connection to it.
+func serve(t *testing.T, s *testServer) *grpc.ClientConn {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	srv.RegisterService(&proto.LitetableService_ServiceDesc, s)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// retryAfter is a RESOURCE_EXHAUSTED error asking to retry after delay.
+func retryAfter(t *testing.T, delay time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, "slow down").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st.Err()
+}
+
+func TestClient_Read_retries(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "restarting")
+	tests := map[string]struct {
+		errs      func(t *testing.T) []error
+		retry     RetryPolicy
+		wantCode  codes.Code
+		wantReads int32
+		// minWait is the least time the read takes, waiting between attempts
+		minWait time.Duration
+	}{
+		"retried until it succeeds": {
+			errs:      func(*testing.T) []error { return []error{unavailable, unavailable} },
+			wantCode:  codes.OK,
+			wantReads: 3,
+		},
+		"out of attempts": {
+			errs:      func(*testing.T) []error { return []error{unavailable, unavailable} },
+			retry:     RetryPolicy{MaxAttempts: 2},
+			wantCode:  codes.Unavailable,
+			wantReads: 2,
+		},
+		"not transient": {
+			errs: func(*testing.T) []error {
+				return []error{status.Error(codes.NotFound, "no table")}
+			},
+			wantCode:  codes.NotFound,
+			wantReads: 1,
+		},
+		"exhausted without a retry delay": {
+			errs: func(*testing.T) []error {
+				return []error{status.Error(codes.ResourceExhausted, "quota")}
+			},
+			wantCode:  codes.ResourceExhausted,
+			wantReads: 1,
+		},
+		"retry delay from the server": {
+			errs: func(t *testing.T) []error {
+				return []error{retryAfter(t, 100*time.Millisecond)}
+			},
+			retry:     RetryPolicy{InitialBackoff: time.Millisecond},
+			wantCode:  codes.OK,
+			wantReads: 2,
+			minWait:   100 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &testServer{errs: tc.errs(t)}
+			c, err := New(&Config{Conn: serve(t, s), Retry: tc.retry})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			_, err = c.Read(context.Background(), &proto.ReadRequest{RowKey: "champ:1"})
+			if code := status.Code(err); code != tc.wantCode {
+				t.Fatalf("got code %s, want %s: %v", code, tc.wantCode, err)
+			}
+			if reads := s.reads.Load(); reads != tc.wantReads {
+				t.Fatalf("got %d reads, want %d", reads, tc.wantReads)
+			}
+			if waited := time.Since(start); waited < tc.minWait {
+				t.Fatalf("retried after %s, want at least %s", waited, tc.minWait)
+			}
+		})
+	}
+}
+
+func TestClient_Read_hedged(t *testing.T) {
+	slow := &testServer{delay: time.Minute}
+	replica := &testServer{}
+	c, err := New(&Config{
+		Conn:       serve(t, slow),
+		Replicas:   []grpc.ClientConnInterface{serve(t, replica)},
+		HedgeDelay: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the replica answers while the server is still busy
+	data, err := c.Read(context.Background(), &proto.ReadRequest{RowKey: "champ:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := data.GetRows()["champ:1"]; !ok {
+		t.Fatalf("got rows %v, want champ:1", data.GetRows())
+	}
+	if slow.reads.Load() != 1 || replica.reads.Load() != 1 {
+		t.Fatalf("got %d and %d reads, want 1 each", slow.reads.Load(), replica.reads.Load())
+	}
+
+	// a transient failure is hedged at once, without waiting for the delay
+	failing := &testServer{errs: []error{status.Error(codes.Unavailable, "restarting")}}
+	replica = &testServer{}
+	c, err = New(&Config{
+		Conn:       serve(t, failing),
+		Replicas:   []grpc.ClientConnInterface{serve(t, replica)},
+		HedgeDelay: time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Read(context.Background(), &proto.ReadRequest{RowKey: "champ:1"}); err != nil {
+		t.Fatal(err)
+	}
+	if replica.reads.Load() != 1 {
+		t.Fatalf("got %d replica reads, want 1", replica.reads.Load())
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := RetryPolicy{}.withDefaults()
+	unavailable := status.Error(codes.Unavailable, "restarting")
+	for attempt, want := range map[int]time.Duration{
+		1:  50 * time.Millisecond,
+		3:  200 * time.Millisecond,
+		10: 2 * time.Second,
+	} {
+		wait, retry := p.backoff(attempt, unavailable)
+		if !retry || wait < want/2 || wait > want {
+			t.Fatalf("attempt %d waits %s, want between %s and %s", attempt, wait, want/2, want)
+		}
+	}
+	if _, retry := p.backoff(1, errors.New("not a status")); retry {
+		t.Fatal("retried an error that is not transient")
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	_, err := New(&Config{HedgeDelay: -time.Second, Retry: RetryPolicy{MaxAttempts: -1}})
+	want := "conn required\nhedge delay cannot be negative\nmax attempts cannot be negative"
+	if err == nil || err.Error() != want {
+		t.Fatalf("got %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math/rand/v2"
+	"time"
+)
+
+// Defaults of the RetryPolicy settings left unset.
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 50 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
+
+// RetryPolicy sets how reads are retried. A read is retried when the server is unavailable,
+// or when it is out of a resource and says when to retry with a RetryInfo detail.
+type RetryPolicy struct {
+	// MaxAttempts is the most times a read is sent, the first included. Defaults to 3; 1 turns
+	// retries off.
+	MaxAttempts int
+	// InitialBackoff is the longest wait before the first retry. Each retry doubles it, up to
+	// MaxBackoff, and the wait is picked at random between half of it and all of it. Defaults
+	// to 50ms.
+	InitialBackoff time.Duration
+	// MaxBackoff bounds the wait between two attempts. Defaults to 2s.
+	MaxBackoff time.Duration
+}
+
+func (p RetryPolicy) validate() error {
+	var errGrp []error
+	if p.MaxAttempts < 0 {
+		errGrp = append(errGrp, errors.New("max attempts cannot be negative"))
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		errGrp = append(errGrp, errors.New("backoff cannot be negative"))
+	}
+	return errors.Join(errGrp...)
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.InitialBackoff == 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	return p
+}
+
+// backoff returns how long to wait after a failed attempt, and whether to retry at all. A delay
+// the server asked for is used as is.
+func (p RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	if !retryable(err) {
+		return 0, false
+	}
+	if delay, ok := retryDelay(err); ok {
+		return delay, true
+	}
+
+	wait := p.InitialBackoff
+	for range attempt - 1 {
+		if wait >= p.MaxBackoff/2 {
+			wait = p.MaxBackoff
+			break
+		}
+		wait *= 2
+	}
+	wait = min(wait, p.MaxBackoff)
+	return wait/2 + rand.N(wait/2+1), true
+}
+
+// retryable reports whether an error is transient: the server was unavailable, or out of a
+// resource it expects to have again.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return true
+	case codes.ResourceExhausted:
+		_, ok := retryDelay(err)
+		return ok
+	default:
+		return false
+	}
+}
+
+// retryDelay returns the delay of the RetryInfo detail of an error, if it has one.
+func retryDelay(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// EncodeInt64 returns the canonical form of n a family stores in a qualifier typed int64:
+// its decimal text, as in 42 or -7.
+func EncodeInt64(n int64) []byte {
+	return strconv.AppendInt(nil, n, 10)
+}
+
+// EncodeFloat64 returns the canonical form of f a family stores in a qualifier typed float64:
+// the shortest decimal text that reads back as f, as in 0.5 or 1e+21. The server rejects NaN and
+// infinities.
+func EncodeFloat64(f float64) []byte {
+	return strconv.AppendFloat(nil, f, 'g', -1, 64)
+}
+
+// EncodeBool returns the canonical form of b a family stores in a qualifier typed bool: true
+// or false.
+func EncodeBool(b bool) []byte {
+	return strconv.AppendBool(nil, b)
+}
+
+// DecodeInt64 reads a value of a qualifier typed int64.
+func DecodeInt64(value []byte) (int64, error) {
+	n, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value %q is not an int64", value)
+	}
+	return n, nil
+}
+
+// DecodeFloat64 reads a value of a qualifier typed float64.
+func DecodeFloat64(value []byte) (float64, error) {
+	f, err := strconv.ParseFloat(string(value), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("value %q is not a finite float64", value)
+	}
+	return f, nil
+}
+
+// DecodeBool reads a value of a qualifier typed bool.
+func DecodeBool(value []byte) (bool, error) {
+	b, err := strconv.ParseBool(string(value))
+	if err != nil {
+		return false, fmt.Errorf("value %q is not a bool", value)
+	}
+	return b, nil
+}
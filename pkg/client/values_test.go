@@ -0,0 +1,59 @@
+package client
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValues_roundTrip(t *testing.T) {
+	for _, n := range []int64{0, 42, -7, math.MaxInt64, math.MinInt64} {
+		got, err := DecodeInt64(EncodeInt64(n))
+		if err != nil || got != n {
+			t.Fatalf("int64 %d read back as %d, %v", n, got, err)
+		}
+	}
+	for _, f := range []float64{0, 0.5, -1.25, 1e21, math.SmallestNonzeroFloat64} {
+		got, err := DecodeFloat64(EncodeFloat64(f))
+		if err != nil || got != f {
+			t.Fatalf("float64 %v read back as %v, %v", f, got, err)
+		}
+	}
+	for _, b := range []bool{true, false} {
+		got, err := DecodeBool(EncodeBool(b))
+		if err != nil || got != b {
+			t.Fatalf("bool %v read back as %v, %v", b, got, err)
+		}
+	}
+}
+
+func TestValues_canonical(t *testing.T) {
+	tests := map[string]struct {
+		got  []byte
+		want string
+	}{
+		"int64":    {got: EncodeInt64(-42), want: "-42"},
+		"float64":  {got: EncodeFloat64(1000), want: "1000"},
+		"fraction": {got: EncodeFloat64(0.5), want: "0.5"},
+		"bool":     {got: EncodeBool(true), want: "true"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if string(tc.got) != tc.want {
+				t.Fatalf("got %s, want %s", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValues_decodeErrors(t *testing.T) {
+	if _, err := DecodeInt64([]byte("1.5")); err == nil {
+		t.Fatal("decoded 1.5 as an int64")
+	}
+	if _, err := DecodeFloat64([]byte("NaN")); err == nil {
+		t.Fatal("decoded NaN as a float64")
+	}
+	if _, err := DecodeBool([]byte("yes")); err == nil {
+		t.Fatal("decoded yes as a bool")
+	}
+}
@@ -19,10 +19,32 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	LitetableService_CreateFamily_FullMethodName = "/litetable.server.v1.LitetableService/CreateFamily"
-	LitetableService_Read_FullMethodName         = "/litetable.server.v1.LitetableService/Read"
-	LitetableService_Write_FullMethodName        = "/litetable.server.v1.LitetableService/Write"
-	LitetableService_Delete_FullMethodName       = "/litetable.server.v1.LitetableService/Delete"
+	LitetableService_CreateFamily_FullMethodName       = "/litetable.server.v1.LitetableService/CreateFamily"
+	LitetableService_CloneFamily_FullMethodName        = "/litetable.server.v1.LitetableService/CloneFamily"
+	LitetableService_Read_FullMethodName               = "/litetable.server.v1.LitetableService/Read"
+	LitetableService_Aggregate_FullMethodName          = "/litetable.server.v1.LitetableService/Aggregate"
+	LitetableService_ListQualifiers_FullMethodName     = "/litetable.server.v1.LitetableService/ListQualifiers"
+	LitetableService_Write_FullMethodName              = "/litetable.server.v1.LitetableService/Write"
+	LitetableService_Delete_FullMethodName             = "/litetable.server.v1.LitetableService/Delete"
+	LitetableService_Flush_FullMethodName              = "/litetable.server.v1.LitetableService/Flush"
+	LitetableService_ListBackups_FullMethodName        = "/litetable.server.v1.LitetableService/ListBackups"
+	LitetableService_CreateTable_FullMethodName        = "/litetable.server.v1.LitetableService/CreateTable"
+	LitetableService_DropTable_FullMethodName          = "/litetable.server.v1.LitetableService/DropTable"
+	LitetableService_ListTables_FullMethodName         = "/litetable.server.v1.LitetableService/ListTables"
+	LitetableService_ExportSchema_FullMethodName       = "/litetable.server.v1.LitetableService/ExportSchema"
+	LitetableService_ImportSchema_FullMethodName       = "/litetable.server.v1.LitetableService/ImportSchema"
+	LitetableService_TableStats_FullMethodName         = "/litetable.server.v1.LitetableService/TableStats"
+	LitetableService_ListTombstones_FullMethodName     = "/litetable.server.v1.LitetableService/ListTombstones"
+	LitetableService_Sequence_FullMethodName           = "/litetable.server.v1.LitetableService/Sequence"
+	LitetableService_LockRow_FullMethodName            = "/litetable.server.v1.LitetableService/LockRow"
+	LitetableService_UnlockRow_FullMethodName          = "/litetable.server.v1.LitetableService/UnlockRow"
+	LitetableService_Watch_FullMethodName              = "/litetable.server.v1.LitetableService/Watch"
+	LitetableService_BeginTransaction_FullMethodName   = "/litetable.server.v1.LitetableService/BeginTransaction"
+	LitetableService_Commit_FullMethodName             = "/litetable.server.v1.LitetableService/Commit"
+	LitetableService_Info_FullMethodName               = "/litetable.server.v1.LitetableService/Info"
+	LitetableService_Capabilities_FullMethodName       = "/litetable.server.v1.LitetableService/Capabilities"
+	LitetableService_ListCDCSubscribers_FullMethodName = "/litetable.server.v1.LitetableService/ListCDCSubscribers"
+	LitetableService_KickCDCSubscriber_FullMethodName  = "/litetable.server.v1.LitetableService/KickCDCSubscriber"
 )
 
 // LitetableServiceClient is the client API for LitetableService service.
@@ -30,9 +52,64 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type LitetableServiceClient interface {
 	CreateFamily(ctx context.Context, in *CreateFamilyRequest, opts ...grpc.CallOption) (*Empty, error)
+	// CloneFamily creates a column family holding a copy of the current rows of another.
+	CloneFamily(ctx context.Context, in *CloneFamilyRequest, opts ...grpc.CallOption) (*CloneFamilyResponse, error)
 	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*LitetableData, error)
+	// Aggregate computes counts, timestamp bounds and sums over the cells a read would return.
+	Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error)
+	// ListQualifiers returns the qualifiers of a row's family and their version counts.
+	ListQualifiers(ctx context.Context, in *ListQualifiersRequest, opts ...grpc.CallOption) (*ListQualifiersResponse, error)
 	Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*LitetableData, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Flush blocks until every acknowledged write is in an fsynced backup.
+	Flush(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	// ListBackups returns the backup catalog.
+	ListBackups(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListBackupsResponse, error)
+	// CreateTable creates a table with its own keyspace and column families.
+	CreateTable(ctx context.Context, in *CreateTableRequest, opts ...grpc.CallOption) (*Empty, error)
+	// DropTable deletes a table and all of its data.
+	DropTable(ctx context.Context, in *DropTableRequest, opts ...grpc.CallOption) (*Empty, error)
+	// ListTables returns the name of every table.
+	ListTables(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListTablesResponse, error)
+	// ExportSchema returns every table with its column families.
+	ExportSchema(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Schema, error)
+	// ImportSchema creates the tables and column families of an exported schema that do not
+	// exist yet, leaving the existing ones as they are.
+	ImportSchema(ctx context.Context, in *ImportSchemaRequest, opts ...grpc.CallOption) (*ImportSchemaResponse, error)
+	// TableStats returns the space a table and its column families take, with their quotas, and
+	// the stats of its families from the periodic stats job.
+	TableStats(ctx context.Context, in *TableStatsRequest, opts ...grpc.CallOption) (*TableStatsResponse, error)
+	// ListTombstones returns the tombstones of a table that have not expired, so pending deletes
+	// can be audited before the reaper collects them.
+	ListTombstones(ctx context.Context, in *ListTombstonesRequest, opts ...grpc.CallOption) (*ListTombstonesResponse, error)
+	// Sequence returns the sequence number of the latest mutation to a table. Reads with it as
+	// read_at see every shard at the same point.
+	Sequence(ctx context.Context, in *SequenceRequest, opts ...grpc.CallOption) (*SequenceResponse, error)
+	// LockRow takes or renews an advisory lease on a row. The lease does not block other clients;
+	// pass its fencing token with writes and deletes to have them rejected once it is lost.
+	LockRow(ctx context.Context, in *LockRowRequest, opts ...grpc.CallOption) (*LockRowResponse, error)
+	// UnlockRow releases a row lease before it expires.
+	UnlockRow(ctx context.Context, in *UnlockRowRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Watch streams the writes and deletes of a row or key prefix. A client that falls behind has
+	// the stream ended with RESOURCE_EXHAUSTED and must re-read the rows it watches.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (LitetableService_WatchClient, error)
+	// BeginTransaction returns the sequence number to read a transaction at.
+	BeginTransaction(ctx context.Context, in *BeginTransactionRequest, opts ...grpc.CallOption) (*BeginTransactionResponse, error)
+	// Commit applies the mutations of a transaction at once. It fails with ABORTED if a row the
+	// transaction read or changes was changed after read_at.
+	Commit(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error)
+	// Info returns the version and build of the server, the versions of the API and storage
+	// formats it speaks, and how its storage is set up.
+	Info(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InfoResponse, error)
+	// Capabilities lists the features the server supports, so clients can degrade gracefully
+	// against older servers.
+	Capabilities(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+	// ListCDCSubscribers lists the clients of the change stream with how far each has been sent,
+	// so operators can find slow or stuck ones.
+	ListCDCSubscribers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListCDCSubscribersResponse, error)
+	// KickCDCSubscriber disconnects a change stream client. Its stream ends with ABORTED and it
+	// may reconnect.
+	KickCDCSubscriber(ctx context.Context, in *KickCDCSubscriberRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type litetableServiceClient struct {
@@ -52,6 +129,15 @@ func (c *litetableServiceClient) CreateFamily(ctx context.Context, in *CreateFam
 	return out, nil
 }
 
+func (c *litetableServiceClient) CloneFamily(ctx context.Context, in *CloneFamilyRequest, opts ...grpc.CallOption) (*CloneFamilyResponse, error) {
+	out := new(CloneFamilyResponse)
+	err := c.cc.Invoke(ctx, LitetableService_CloneFamily_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *litetableServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*LitetableData, error) {
 	out := new(LitetableData)
 	err := c.cc.Invoke(ctx, LitetableService_Read_FullMethodName, in, out, opts...)
@@ -61,6 +147,24 @@ func (c *litetableServiceClient) Read(ctx context.Context, in *ReadRequest, opts
 	return out, nil
 }
 
+func (c *litetableServiceClient) Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error) {
+	out := new(AggregateResponse)
+	err := c.cc.Invoke(ctx, LitetableService_Aggregate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) ListQualifiers(ctx context.Context, in *ListQualifiersRequest, opts ...grpc.CallOption) (*ListQualifiersResponse, error) {
+	out := new(ListQualifiersResponse)
+	err := c.cc.Invoke(ctx, LitetableService_ListQualifiers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *litetableServiceClient) Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*LitetableData, error) {
 	out := new(LitetableData)
 	err := c.cc.Invoke(ctx, LitetableService_Write_FullMethodName, in, out, opts...)
@@ -79,14 +183,263 @@ func (c *litetableServiceClient) Delete(ctx context.Context, in *DeleteRequest,
 	return out, nil
 }
 
+func (c *litetableServiceClient) Flush(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, LitetableService_Flush_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) ListBackups(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListBackupsResponse, error) {
+	out := new(ListBackupsResponse)
+	err := c.cc.Invoke(ctx, LitetableService_ListBackups_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) CreateTable(ctx context.Context, in *CreateTableRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, LitetableService_CreateTable_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) DropTable(ctx context.Context, in *DropTableRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, LitetableService_DropTable_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) ListTables(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListTablesResponse, error) {
+	out := new(ListTablesResponse)
+	err := c.cc.Invoke(ctx, LitetableService_ListTables_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) ExportSchema(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Schema, error) {
+	out := new(Schema)
+	err := c.cc.Invoke(ctx, LitetableService_ExportSchema_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) ImportSchema(ctx context.Context, in *ImportSchemaRequest, opts ...grpc.CallOption) (*ImportSchemaResponse, error) {
+	out := new(ImportSchemaResponse)
+	err := c.cc.Invoke(ctx, LitetableService_ImportSchema_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) TableStats(ctx context.Context, in *TableStatsRequest, opts ...grpc.CallOption) (*TableStatsResponse, error) {
+	out := new(TableStatsResponse)
+	err := c.cc.Invoke(ctx, LitetableService_TableStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) ListTombstones(ctx context.Context, in *ListTombstonesRequest, opts ...grpc.CallOption) (*ListTombstonesResponse, error) {
+	out := new(ListTombstonesResponse)
+	err := c.cc.Invoke(ctx, LitetableService_ListTombstones_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) Sequence(ctx context.Context, in *SequenceRequest, opts ...grpc.CallOption) (*SequenceResponse, error) {
+	out := new(SequenceResponse)
+	err := c.cc.Invoke(ctx, LitetableService_Sequence_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) LockRow(ctx context.Context, in *LockRowRequest, opts ...grpc.CallOption) (*LockRowResponse, error) {
+	out := new(LockRowResponse)
+	err := c.cc.Invoke(ctx, LitetableService_LockRow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) UnlockRow(ctx context.Context, in *UnlockRowRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, LitetableService_UnlockRow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (LitetableService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LitetableService_ServiceDesc.Streams[0], LitetableService_Watch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &litetableServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LitetableService_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type litetableServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *litetableServiceWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *litetableServiceClient) BeginTransaction(ctx context.Context, in *BeginTransactionRequest, opts ...grpc.CallOption) (*BeginTransactionResponse, error) {
+	out := new(BeginTransactionResponse)
+	err := c.cc.Invoke(ctx, LitetableService_BeginTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) Commit(ctx context.Context, in *CommitRequest, opts ...grpc.CallOption) (*CommitResponse, error) {
+	out := new(CommitResponse)
+	err := c.cc.Invoke(ctx, LitetableService_Commit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) Info(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InfoResponse, error) {
+	out := new(InfoResponse)
+	err := c.cc.Invoke(ctx, LitetableService_Info_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) Capabilities(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
+	out := new(CapabilitiesResponse)
+	err := c.cc.Invoke(ctx, LitetableService_Capabilities_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) ListCDCSubscribers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListCDCSubscribersResponse, error) {
+	out := new(ListCDCSubscribersResponse)
+	err := c.cc.Invoke(ctx, LitetableService_ListCDCSubscribers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *litetableServiceClient) KickCDCSubscriber(ctx context.Context, in *KickCDCSubscriberRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, LitetableService_KickCDCSubscriber_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LitetableServiceServer is the server API for LitetableService service.
 // All implementations must embed UnimplementedLitetableServiceServer
 // for forward compatibility
 type LitetableServiceServer interface {
 	CreateFamily(context.Context, *CreateFamilyRequest) (*Empty, error)
+	// CloneFamily creates a column family holding a copy of the current rows of another.
+	CloneFamily(context.Context, *CloneFamilyRequest) (*CloneFamilyResponse, error)
 	Read(context.Context, *ReadRequest) (*LitetableData, error)
+	// Aggregate computes counts, timestamp bounds and sums over the cells a read would return.
+	Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error)
+	// ListQualifiers returns the qualifiers of a row's family and their version counts.
+	ListQualifiers(context.Context, *ListQualifiersRequest) (*ListQualifiersResponse, error)
 	Write(context.Context, *WriteRequest) (*LitetableData, error)
 	Delete(context.Context, *DeleteRequest) (*Empty, error)
+	// Flush blocks until every acknowledged write is in an fsynced backup.
+	Flush(context.Context, *Empty) (*Empty, error)
+	// ListBackups returns the backup catalog.
+	ListBackups(context.Context, *Empty) (*ListBackupsResponse, error)
+	// CreateTable creates a table with its own keyspace and column families.
+	CreateTable(context.Context, *CreateTableRequest) (*Empty, error)
+	// DropTable deletes a table and all of its data.
+	DropTable(context.Context, *DropTableRequest) (*Empty, error)
+	// ListTables returns the name of every table.
+	ListTables(context.Context, *Empty) (*ListTablesResponse, error)
+	// ExportSchema returns every table with its column families.
+	ExportSchema(context.Context, *Empty) (*Schema, error)
+	// ImportSchema creates the tables and column families of an exported schema that do not
+	// exist yet, leaving the existing ones as they are.
+	ImportSchema(context.Context, *ImportSchemaRequest) (*ImportSchemaResponse, error)
+	// TableStats returns the space a table and its column families take, with their quotas, and
+	// the stats of its families from the periodic stats job.
+	TableStats(context.Context, *TableStatsRequest) (*TableStatsResponse, error)
+	// ListTombstones returns the tombstones of a table that have not expired, so pending deletes
+	// can be audited before the reaper collects them.
+	ListTombstones(context.Context, *ListTombstonesRequest) (*ListTombstonesResponse, error)
+	// Sequence returns the sequence number of the latest mutation to a table. Reads with it as
+	// read_at see every shard at the same point.
+	Sequence(context.Context, *SequenceRequest) (*SequenceResponse, error)
+	// LockRow takes or renews an advisory lease on a row. The lease does not block other clients;
+	// pass its fencing token with writes and deletes to have them rejected once it is lost.
+	LockRow(context.Context, *LockRowRequest) (*LockRowResponse, error)
+	// UnlockRow releases a row lease before it expires.
+	UnlockRow(context.Context, *UnlockRowRequest) (*Empty, error)
+	// Watch streams the writes and deletes of a row or key prefix. A client that falls behind has
+	// the stream ended with RESOURCE_EXHAUSTED and must re-read the rows it watches.
+	Watch(*WatchRequest, LitetableService_WatchServer) error
+	// BeginTransaction returns the sequence number to read a transaction at.
+	BeginTransaction(context.Context, *BeginTransactionRequest) (*BeginTransactionResponse, error)
+	// Commit applies the mutations of a transaction at once. It fails with ABORTED if a row the
+	// transaction read or changes was changed after read_at.
+	Commit(context.Context, *CommitRequest) (*CommitResponse, error)
+	// Info returns the version and build of the server, the versions of the API and storage
+	// formats it speaks, and how its storage is set up.
+	Info(context.Context, *Empty) (*InfoResponse, error)
+	// Capabilities lists the features the server supports, so clients can degrade gracefully
+	// against older servers.
+	Capabilities(context.Context, *Empty) (*CapabilitiesResponse, error)
+	// ListCDCSubscribers lists the clients of the change stream with how far each has been sent,
+	// so operators can find slow or stuck ones.
+	ListCDCSubscribers(context.Context, *Empty) (*ListCDCSubscribersResponse, error)
+	// KickCDCSubscriber disconnects a change stream client. Its stream ends with ABORTED and it
+	// may reconnect.
+	KickCDCSubscriber(context.Context, *KickCDCSubscriberRequest) (*Empty, error)
 	mustEmbedUnimplementedLitetableServiceServer()
 }
 
@@ -97,15 +450,81 @@ type UnimplementedLitetableServiceServer struct {
 func (UnimplementedLitetableServiceServer) CreateFamily(context.Context, *CreateFamilyRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateFamily not implemented")
 }
+func (UnimplementedLitetableServiceServer) CloneFamily(context.Context, *CloneFamilyRequest) (*CloneFamilyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneFamily not implemented")
+}
 func (UnimplementedLitetableServiceServer) Read(context.Context, *ReadRequest) (*LitetableData, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
 }
+func (UnimplementedLitetableServiceServer) Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Aggregate not implemented")
+}
+func (UnimplementedLitetableServiceServer) ListQualifiers(context.Context, *ListQualifiersRequest) (*ListQualifiersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQualifiers not implemented")
+}
 func (UnimplementedLitetableServiceServer) Write(context.Context, *WriteRequest) (*LitetableData, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Write not implemented")
 }
 func (UnimplementedLitetableServiceServer) Delete(context.Context, *DeleteRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedLitetableServiceServer) Flush(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Flush not implemented")
+}
+func (UnimplementedLitetableServiceServer) ListBackups(context.Context, *Empty) (*ListBackupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBackups not implemented")
+}
+func (UnimplementedLitetableServiceServer) CreateTable(context.Context, *CreateTableRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTable not implemented")
+}
+func (UnimplementedLitetableServiceServer) DropTable(context.Context, *DropTableRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropTable not implemented")
+}
+func (UnimplementedLitetableServiceServer) ListTables(context.Context, *Empty) (*ListTablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTables not implemented")
+}
+func (UnimplementedLitetableServiceServer) ExportSchema(context.Context, *Empty) (*Schema, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSchema not implemented")
+}
+func (UnimplementedLitetableServiceServer) ImportSchema(context.Context, *ImportSchemaRequest) (*ImportSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportSchema not implemented")
+}
+func (UnimplementedLitetableServiceServer) TableStats(context.Context, *TableStatsRequest) (*TableStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TableStats not implemented")
+}
+func (UnimplementedLitetableServiceServer) ListTombstones(context.Context, *ListTombstonesRequest) (*ListTombstonesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTombstones not implemented")
+}
+func (UnimplementedLitetableServiceServer) Sequence(context.Context, *SequenceRequest) (*SequenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sequence not implemented")
+}
+func (UnimplementedLitetableServiceServer) LockRow(context.Context, *LockRowRequest) (*LockRowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LockRow not implemented")
+}
+func (UnimplementedLitetableServiceServer) UnlockRow(context.Context, *UnlockRowRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockRow not implemented")
+}
+func (UnimplementedLitetableServiceServer) Watch(*WatchRequest, LitetableService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedLitetableServiceServer) BeginTransaction(context.Context, *BeginTransactionRequest) (*BeginTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BeginTransaction not implemented")
+}
+func (UnimplementedLitetableServiceServer) Commit(context.Context, *CommitRequest) (*CommitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Commit not implemented")
+}
+func (UnimplementedLitetableServiceServer) Info(context.Context, *Empty) (*InfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
+func (UnimplementedLitetableServiceServer) Capabilities(context.Context, *Empty) (*CapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Capabilities not implemented")
+}
+func (UnimplementedLitetableServiceServer) ListCDCSubscribers(context.Context, *Empty) (*ListCDCSubscribersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCDCSubscribers not implemented")
+}
+func (UnimplementedLitetableServiceServer) KickCDCSubscriber(context.Context, *KickCDCSubscriberRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickCDCSubscriber not implemented")
+}
 func (UnimplementedLitetableServiceServer) mustEmbedUnimplementedLitetableServiceServer() {}
 
 // UnsafeLitetableServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -137,6 +556,24 @@ func _LitetableService_CreateFamily_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LitetableService_CloneFamily_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneFamilyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).CloneFamily(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_CloneFamily_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).CloneFamily(ctx, req.(*CloneFamilyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LitetableService_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReadRequest)
 	if err := dec(in); err != nil {
@@ -155,6 +592,42 @@ func _LitetableService_Read_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LitetableService_Aggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).Aggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_Aggregate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).Aggregate(ctx, req.(*AggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_ListQualifiers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQualifiersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).ListQualifiers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_ListQualifiers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).ListQualifiers(ctx, req.(*ListQualifiersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LitetableService_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(WriteRequest)
 	if err := dec(in); err != nil {
@@ -191,6 +664,351 @@ func _LitetableService_Delete_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LitetableService_Flush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).Flush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_Flush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).Flush(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_ListBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).ListBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_ListBackups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).ListBackups(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_CreateTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).CreateTable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_CreateTable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).CreateTable(ctx, req.(*CreateTableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_DropTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropTableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).DropTable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_DropTable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).DropTable(ctx, req.(*DropTableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_ListTables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).ListTables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_ListTables_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).ListTables(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_ExportSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).ExportSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_ExportSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).ExportSchema(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_ImportSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).ImportSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_ImportSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).ImportSchema(ctx, req.(*ImportSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_TableStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TableStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).TableStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_TableStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).TableStats(ctx, req.(*TableStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_ListTombstones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTombstonesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).ListTombstones(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_ListTombstones_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).ListTombstones(ctx, req.(*ListTombstonesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_Sequence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SequenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).Sequence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_Sequence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).Sequence(ctx, req.(*SequenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_LockRow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).LockRow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_LockRow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).LockRow(ctx, req.(*LockRowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_UnlockRow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockRowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).UnlockRow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_UnlockRow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).UnlockRow(ctx, req.(*UnlockRowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LitetableServiceServer).Watch(m, &litetableServiceWatchServer{stream})
+}
+
+type LitetableService_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type litetableServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *litetableServiceWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LitetableService_BeginTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).BeginTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_BeginTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).BeginTransaction(ctx, req.(*BeginTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_Commit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).Commit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_Commit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).Commit(ctx, req.(*CommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_Info_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).Info(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_Capabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).Capabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_Capabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).Capabilities(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_ListCDCSubscribers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).ListCDCSubscribers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_ListCDCSubscribers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).ListCDCSubscribers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LitetableService_KickCDCSubscriber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickCDCSubscriberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LitetableServiceServer).KickCDCSubscriber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LitetableService_KickCDCSubscriber_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LitetableServiceServer).KickCDCSubscriber(ctx, req.(*KickCDCSubscriberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LitetableService_ServiceDesc is the grpc.ServiceDesc for LitetableService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -202,10 +1020,22 @@ var LitetableService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateFamily",
 			Handler:    _LitetableService_CreateFamily_Handler,
 		},
+		{
+			MethodName: "CloneFamily",
+			Handler:    _LitetableService_CloneFamily_Handler,
+		},
 		{
 			MethodName: "Read",
 			Handler:    _LitetableService_Read_Handler,
 		},
+		{
+			MethodName: "Aggregate",
+			Handler:    _LitetableService_Aggregate_Handler,
+		},
+		{
+			MethodName: "ListQualifiers",
+			Handler:    _LitetableService_ListQualifiers_Handler,
+		},
 		{
 			MethodName: "Write",
 			Handler:    _LitetableService_Write_Handler,
@@ -214,7 +1044,85 @@ var LitetableService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _LitetableService_Delete_Handler,
 		},
+		{
+			MethodName: "Flush",
+			Handler:    _LitetableService_Flush_Handler,
+		},
+		{
+			MethodName: "ListBackups",
+			Handler:    _LitetableService_ListBackups_Handler,
+		},
+		{
+			MethodName: "CreateTable",
+			Handler:    _LitetableService_CreateTable_Handler,
+		},
+		{
+			MethodName: "DropTable",
+			Handler:    _LitetableService_DropTable_Handler,
+		},
+		{
+			MethodName: "ListTables",
+			Handler:    _LitetableService_ListTables_Handler,
+		},
+		{
+			MethodName: "ExportSchema",
+			Handler:    _LitetableService_ExportSchema_Handler,
+		},
+		{
+			MethodName: "ImportSchema",
+			Handler:    _LitetableService_ImportSchema_Handler,
+		},
+		{
+			MethodName: "TableStats",
+			Handler:    _LitetableService_TableStats_Handler,
+		},
+		{
+			MethodName: "ListTombstones",
+			Handler:    _LitetableService_ListTombstones_Handler,
+		},
+		{
+			MethodName: "Sequence",
+			Handler:    _LitetableService_Sequence_Handler,
+		},
+		{
+			MethodName: "LockRow",
+			Handler:    _LitetableService_LockRow_Handler,
+		},
+		{
+			MethodName: "UnlockRow",
+			Handler:    _LitetableService_UnlockRow_Handler,
+		},
+		{
+			MethodName: "BeginTransaction",
+			Handler:    _LitetableService_BeginTransaction_Handler,
+		},
+		{
+			MethodName: "Commit",
+			Handler:    _LitetableService_Commit_Handler,
+		},
+		{
+			MethodName: "Info",
+			Handler:    _LitetableService_Info_Handler,
+		},
+		{
+			MethodName: "Capabilities",
+			Handler:    _LitetableService_Capabilities_Handler,
+		},
+		{
+			MethodName: "ListCDCSubscribers",
+			Handler:    _LitetableService_ListCDCSubscribers_Handler,
+		},
+		{
+			MethodName: "KickCDCSubscriber",
+			Handler:    _LitetableService_KickCDCSubscriber_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _LitetableService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/litetable_operation.proto",
 }